@@ -32,6 +32,22 @@ echo.
 echo 🚀 Starting Meraki Info build process...
 echo.
 
+REM Build information injected into internal/version, as in the Makefile; VERSION, COMMIT and
+REM BUILD_DATE may be set in the environment to override them
+if not defined VERSION (
+    for /f "delims=" %%v in ('git describe --tags --always --dirty 2^>nul') do set "VERSION=%%v"
+)
+if not defined VERSION set "VERSION=1.0.0"
+if not defined COMMIT (
+    for /f "delims=" %%c in ('git rev-parse --short HEAD 2^>nul') do set "COMMIT=%%c"
+)
+if not defined COMMIT set "COMMIT=unknown"
+if not defined BUILD_DATE (
+    for /f "delims=" %%d in ('powershell -NoProfile -Command "(Get-Date).ToUniversalTime().ToString('yyyy-MM-ddTHH:mm:ssZ')" 2^>nul') do set "BUILD_DATE=%%d"
+)
+if not defined BUILD_DATE set "BUILD_DATE=unknown"
+set "LDFLAGS=-X meraki-info/internal/version.Version=%VERSION% -X meraki-info/internal/version.Commit=%COMMIT% -X meraki-info/internal/version.BuildDate=%BUILD_DATE%"
+
 REM Clean if requested
 if "%CLEAN%"=="true" call :clean_artifacts
 
@@ -144,7 +160,7 @@ goto :eof
 echo 🔨 Building for Windows...
 set GOOS=windows
 set GOARCH=amd64
-go build -o meraki-info.exe .
+go build -ldflags "%LDFLAGS%" -o meraki-info.exe .
 if errorlevel 1 (
     echo ❌ Windows build failed!
     exit /b 1
@@ -156,7 +172,7 @@ goto :eof
 echo 🔨 Building for Linux...
 set GOOS=linux
 set GOARCH=amd64
-go build -o meraki-info-linux .
+go build -ldflags "%LDFLAGS%" -o meraki-info-linux .
 if errorlevel 1 (
     echo ❌ Linux build failed!
     exit /b 1
@@ -168,7 +184,7 @@ goto :eof
 echo 🔨 Building for macOS...
 set GOOS=darwin
 set GOARCH=amd64
-go build -o meraki-info-mac .
+go build -ldflags "%LDFLAGS%" -o meraki-info-mac .
 if errorlevel 1 (
     echo ❌ macOS build failed!
     exit /b 1
@@ -52,12 +52,24 @@ if ($Test) {
     Write-Host ""
 }
 
+# Build information injected into internal/version, as in the Makefile; VERSION, COMMIT and
+# BUILD_DATE may be set in the environment to override them
+$Version = $env:VERSION
+if (-not $Version) { $Version = git describe --tags --always --dirty 2>$null }
+if (-not $Version) { $Version = "1.0.0" }
+$Commit = $env:COMMIT
+if (-not $Commit) { $Commit = git rev-parse --short HEAD 2>$null }
+if (-not $Commit) { $Commit = "unknown" }
+$BuildDate = $env:BUILD_DATE
+if (-not $BuildDate) { $BuildDate = (Get-Date).ToUniversalTime().ToString("yyyy-MM-ddTHH:mm:ssZ") }
+$LdFlags = "-X meraki-info/internal/version.Version=$Version -X meraki-info/internal/version.Commit=$Commit -X meraki-info/internal/version.BuildDate=$BuildDate"
+
 # Build function
 function BuildApp($platform, $goos, $goarch, $output) {
     Write-Host "🔨 Building $platform..." -ForegroundColor Yellow
     $env:GOOS = $goos
     $env:GOARCH = $goarch
-    go build -o $output .
+    go build -ldflags $LdFlags -o $output .
     if ($LASTEXITCODE -eq 0) {
         $size = (Get-Item $output).Length / 1MB
         $sizeMB = [math]::Round($size, 2)
@@ -0,0 +1,235 @@
+package commands
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"meraki-info/internal/config"
+	"meraki-info/internal/meraki"
+	"meraki-info/internal/output"
+)
+
+// AccessInformation displays available organizations and networks for the API key. It returns an
+// error, after reporting it, only when the organizations cannot be listed.
+func AccessInformation(client Client, cfg *config.Config) error {
+	if _, isText := output.NewWriter(cfg.OutputType).(*output.TextWriter); !isText {
+		return accessInformationRecords(client, cfg)
+	}
+
+	orgFilters := organizationFilters(cfg)
+	orgFilter := strings.Join(orgFilters, ", ")
+	fmt.Fprintln(stdout, "╔════════════════════════════════════════════════════════════════════════════════╗")
+	fmt.Fprintln(stdout, "║                          Meraki API Access Information                        ║")
+	fmt.Fprintln(stdout, "╚════════════════════════════════════════════════════════════════════════════════╝")
+	fmt.Fprintln(stdout)
+
+	// Get organizations
+	orgs, err := client.GetOrganizations()
+	if err != nil {
+		fmt.Fprintf(stderr, "❌ Error fetching organizations: %v\n", err)
+		return err
+	}
+
+	if len(orgs) == 0 {
+		fmt.Fprintln(stderr, "⚠️  No organizations found. Please check your API key permissions.")
+		return nil
+	}
+
+	// Filter organizations if orgFilter is provided
+	if orgFilter != "" {
+		filteredOrgs := filterOrganizations(orgs, orgFilters)
+		if len(filteredOrgs) == 0 {
+			fmt.Fprintf(stderr, "⚠️  No organization found matching '%s'\n", orgFilter)
+			fmt.Fprintln(stdout, "\n📋 Available organizations:")
+			for i, org := range orgs {
+				fmt.Fprintf(stdout, "   %d. %s (ID: %s)\n", i+1, org.Name, org.ID)
+			}
+			return nil
+		}
+		orgs = filteredOrgs
+		fmt.Fprintf(stdout, "🔍 Filtering by organization: %s\n\n", orgFilter)
+	}
+
+	fmt.Fprintf(stdout, "✅ Found %d organization(s) accessible with your API key:\n\n", len(orgs))
+
+	for i, org := range orgs {
+		fmt.Fprintf(stdout, "┌─ Organization %d ─────────────────────────────────────────────────────────\n", i+1)
+		fmt.Fprintf(stdout, "│ ID:         %s\n", org.ID)
+		fmt.Fprintf(stdout, "│ Name:       %s\n", org.Name)
+		fmt.Fprintf(stdout, "│ API:        %s\n", formatBool(org.API.Enabled, "Enabled", "Disabled"))
+		fmt.Fprintf(stdout, "│ Licensing:  %s\n", org.Licensing.Model)
+		fmt.Fprintf(stdout, "│ Region:     %s (%s)\n", org.Cloud.Region.Name, org.Cloud.Region.Host.Name)
+		if org.URL != "" {
+			fmt.Fprintf(stdout, "│ Dashboard:  %s\n", org.URL)
+		}
+		if total, err := client.GetOrganizationDeviceStatusTotal(org.ID); err != nil {
+			slog.Warn("Failed to get device statuses for organization", "orgID", org.ID, "orgName", org.Name, "error", err)
+		} else {
+			fmt.Fprintf(stdout, "│ Devices:    %s\n", formatDeviceCounts(total))
+		}
+		fmt.Fprintf(stdout, "└───────────────────────────────────────────────────────────────────────────\n")
+
+		// Get networks for this organization
+		networks, err := client.GetOrganizationNetworks(org.ID)
+		if err != nil {
+			fmt.Fprintf(stderr, "  ⚠️  Error fetching networks for %s: %v\n", org.Name, err)
+			continue
+		}
+
+		if len(networks) == 0 {
+			fmt.Fprintln(stdout, "  📭 No networks found in this organization")
+		} else {
+			fmt.Fprintf(stdout, "  📶 Networks (%d):\n", len(networks))
+			for j, network := range networks {
+				fmt.Fprintf(stdout, "    %d. %s (ID: %s)", j+1, network.Name, network.ID)
+				if len(network.ProductTypes) > 0 {
+					fmt.Fprintf(stdout, " - Products: %v", network.ProductTypes)
+				}
+				if network.TimeZone != "" {
+					fmt.Fprintf(stdout, " - TZ: %s", network.TimeZone)
+				}
+				fmt.Fprintln(stdout)
+			}
+		}
+		fmt.Fprintln(stdout)
+	}
+
+	fmt.Fprintln(stdout, "💡 Usage Examples:")
+	if len(orgs) > 0 {
+		if orgFilter != "" {
+			// When filtering by org, show specific examples for that org
+			fmt.Fprintf(stdout, "   # Get route info from organization '%s':\n", orgs[0].Name)
+			fmt.Fprintf(stdout, "   ./meraki-routes-backup --apikey \"your-key\" --org \"%s\"\n", orgs[0].ID)
+
+			networks, err := client.GetOrganizationNetworks(orgs[0].ID)
+			if err == nil && len(networks) > 0 {
+				fmt.Fprintf(stdout, "   # Get route info from specific network in '%s':\n", orgs[0].Name)
+				fmt.Fprintf(stdout, "   ./meraki-routes-backup --apikey \"your-key\" --org \"%s\" --network \"%s\"\n", orgs[0].ID, networks[0].ID)
+				fmt.Fprintf(stdout, "   # Get info for all networks in '%s' to separate files:\n", orgs[0].Name)
+				fmt.Fprintf(stdout, "   ./meraki-routes-backup --apikey \"your-key\" --org \"%s\" --all\n", orgs[0].ID)
+				fmt.Fprintf(stdout, "   # View access info for this organization only:\n")
+				fmt.Fprintf(stdout, "   ./meraki-routes-backup --access --apikey \"your-key\" --org \"%s\"\n", orgs[0].ID)
+			}
+		} else {
+			// General examples when showing all orgs
+			fmt.Fprintf(stdout, "   # Get route info from a specific organization:\n")
+			fmt.Fprintf(stdout, "   ./meraki-routes-backup --apikey \"your-key\" --org \"%s\"\n", orgs[0].ID)
+
+			networks, err := client.GetOrganizationNetworks(orgs[0].ID)
+			if err == nil && len(networks) > 0 {
+				fmt.Fprintf(stdout, "   # Get route info from a specific network:\n")
+				fmt.Fprintf(stdout, "   ./meraki-routes-backup --apikey \"your-key\" --org \"%s\" --network \"%s\"\n", orgs[0].ID, networks[0].ID)
+			}
+			fmt.Fprintf(stdout, "   # Get info for all networks to separate files:\n")
+			fmt.Fprintf(stdout, "   ./meraki-routes-backup --apikey \"your-key\" --org \"%s\" --all\n", orgs[0].ID)
+			fmt.Fprintf(stdout, "   # View access info for specific organization:\n")
+			fmt.Fprintf(stdout, "   ./meraki-routes-backup --access --apikey \"your-key\" --org \"%s\"\n", orgs[0].ID)
+		}
+	}
+	fmt.Fprintln(stdout)
+	return nil
+}
+
+// accessInformationRecords writes the organizations and networks for the API key as an AccessInfo
+// record through the configured output writer, for the machine-readable formats
+func accessInformationRecords(client Client, cfg *config.Config) error {
+	orgs, err := client.GetOrganizations()
+	if err != nil {
+		slog.Error("Failed to fetch organizations", "error", err)
+		return err
+	}
+	if orgFilters := organizationFilters(cfg); len(orgFilters) > 0 {
+		orgs = filterOrganizations(orgs, orgFilters)
+		if len(orgs) == 0 {
+			slog.Warn("No organization found matching filter", "org", strings.Join(orgFilters, ", "))
+		}
+	}
+
+	access := meraki.AccessInfo{Organizations: make([]meraki.OrganizationAccess, 0, len(orgs))}
+	for _, org := range orgs {
+		orgAccess := meraki.OrganizationAccess{
+			ID:             org.ID,
+			Name:           org.Name,
+			URL:            org.URL,
+			APIEnabled:     org.API.Enabled,
+			LicensingModel: org.Licensing.Model,
+			Region:         org.Cloud.Region.Name,
+			RegionHost:     org.Cloud.Region.Host.Name,
+			Networks:       []meraki.Network{},
+		}
+		if total, err := client.GetOrganizationDeviceStatusTotal(org.ID); err != nil {
+			slog.Warn("Failed to get device statuses for organization", "orgID", org.ID, "orgName", org.Name, "error", err)
+		} else {
+			orgAccess.Devices = &total
+		}
+		if networks, err := client.GetOrganizationNetworks(org.ID); err != nil {
+			slog.Warn("Failed to get networks for organization", "orgID", org.ID, "orgName", org.Name, "error", err)
+			orgAccess.Error = err.Error()
+		} else if networks != nil {
+			orgAccess.Networks = networks
+		}
+		access.Organizations = append(access.Organizations, orgAccess)
+	}
+
+	writer := newOutputWriter(cfg)
+	if cfg.OutputFile == "" || cfg.OutputFile == "-" {
+		if err := writer.WriteTo(access, stdout); err != nil {
+			slog.Error("Failed to write access info to stdout", "error", err)
+			return err
+		}
+		slog.Info("Access info sent to stdout", "organizations", len(access.Organizations))
+	} else {
+		if err := writer.WriteToFile(access, cfg.OutputFile); err != nil {
+			slog.Error("Failed to write access info to file", "file", cfg.OutputFile, "error", err)
+			return err
+		}
+		slog.Info("Access info written to file", "organizations", len(access.Organizations), "file", cfg.OutputFile)
+	}
+	return nil
+}
+
+// organizationFilters returns the -org values access is restricted to, as resolved by main
+func organizationFilters(cfg *config.Config) []string {
+	if len(cfg.SelectedOrganizations) > 0 {
+		ids := make([]string, len(cfg.SelectedOrganizations))
+		for i, org := range cfg.SelectedOrganizations {
+			ids[i] = org.ID
+		}
+		return ids
+	}
+	if cfg.Organization != "" {
+		return []string{cfg.Organization}
+	}
+	return nil
+}
+
+// filterOrganizations returns the organizations whose ID or name equals any of orgFilters
+func filterOrganizations(orgs []meraki.Organization, orgFilters []string) []meraki.Organization {
+	var filteredOrgs []meraki.Organization
+	for _, org := range orgs {
+		if slices.Contains(orgFilters, org.ID) || slices.Contains(orgFilters, org.Name) {
+			filteredOrgs = append(filteredOrgs, org)
+		}
+	}
+	return filteredOrgs
+}
+
+// formatDeviceCounts formats organization device status counts as "N online, N alerting, N offline",
+// adding dormant devices when there are any
+func formatDeviceCounts(total meraki.DeviceStatusSummary) string {
+	counts := fmt.Sprintf("%d online, %d alerting, %d offline", total.Online, total.Alerting, total.Offline)
+	if total.Dormant > 0 {
+		counts += fmt.Sprintf(", %d dormant", total.Dormant)
+	}
+	return counts
+}
+
+// formatBool formats a boolean value with custom true/false strings
+func formatBool(value bool, trueStr, falseStr string) string {
+	if value {
+		return trueStr
+	}
+	return falseStr
+}
@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"meraki-info/internal/config"
+	"meraki-info/internal/meraki"
+)
+
+func TestFormatDeviceCounts(t *testing.T) {
+	total := meraki.DeviceStatusSummary{Online: 120, Alerting: 3, Offline: 5}
+	if got := formatDeviceCounts(total); got != "120 online, 3 alerting, 5 offline" {
+		t.Errorf("Unexpected device counts: %q", got)
+	}
+
+	total.Dormant = 2
+	if got := formatDeviceCounts(total); got != "120 online, 3 alerting, 5 offline, 2 dormant" {
+		t.Errorf("Unexpected device counts with dormant devices: %q", got)
+	}
+}
+
+func TestAccessInformation_Records(t *testing.T) {
+	out, _ := captureOutput(t)
+	client := newTestClient()
+	client.networkErrs = map[string]error{"org2": errors.New("forbidden")}
+
+	cfg := &config.Config{Command: "access", OutputType: "json"}
+	if err := AccessInformation(client, cfg); err != nil {
+		t.Fatalf("AccessInformation failed: %v", err)
+	}
+
+	var access meraki.AccessInfo
+	if err := json.Unmarshal(out.Bytes(), &access); err != nil {
+		t.Fatalf("Expected JSON instead of the text report: %v\n%s", err, out.String())
+	}
+	if len(access.Organizations) != 2 {
+		t.Fatalf("Expected 2 organizations, got %d", len(access.Organizations))
+	}
+	if len(access.Organizations[0].Networks) != 2 || access.Organizations[0].Devices == nil {
+		t.Errorf("Expected networks and device counts for org1, got %+v", access.Organizations[0])
+	}
+	if access.Organizations[1].Error != "forbidden" {
+		t.Errorf("Expected the network error for org2, got %+v", access.Organizations[1])
+	}
+
+	out.Reset()
+	cfg.Organization = "Org Two"
+	if err := AccessInformation(client, cfg); err != nil {
+		t.Fatalf("AccessInformation failed: %v", err)
+	}
+	access = meraki.AccessInfo{}
+	if err := json.Unmarshal(out.Bytes(), &access); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if len(access.Organizations) != 1 || access.Organizations[0].ID != "org2" {
+		t.Errorf("Expected only org2 with an organization filter, got %+v", access.Organizations)
+	}
+}
+
+func TestAccessInformation_Text(t *testing.T) {
+	out, _ := captureOutput(t)
+	cfg := &config.Config{Command: "access", OutputType: "text"}
+	if err := AccessInformation(newTestClient(), cfg); err != nil {
+		t.Fatalf("AccessInformation failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "Meraki API Access Information") || !strings.Contains(out.String(), "Branch 4 (ID: N_4)") {
+		t.Errorf("Expected the text report, got:\n%s", out.String())
+	}
+}
+
+func TestAccessInformation_SelectedOrganizations(t *testing.T) {
+	out, _ := captureOutput(t)
+	client := newTestClient()
+	client.organizations = append(client.organizations, meraki.Organization{ID: "org3", Name: "Org Three"})
+
+	cfg := &config.Config{Command: "access", OutputType: "json", SelectedOrganizations: []meraki.Organization{{ID: "org1"}, {ID: "org3"}}}
+	if err := AccessInformation(client, cfg); err != nil {
+		t.Fatalf("AccessInformation failed: %v", err)
+	}
+
+	var access meraki.AccessInfo
+	if err := json.Unmarshal(out.Bytes(), &access); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if len(access.Organizations) != 2 || access.Organizations[0].ID != "org1" || access.Organizations[1].ID != "org3" {
+		t.Errorf("Expected org1 and org3, got %+v", access.Organizations)
+	}
+}
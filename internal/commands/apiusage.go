@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"fmt"
+	"log/slog"
+
+	"meraki-info/internal/config"
+	"meraki-info/internal/meraki"
+)
+
+// APIUsage reports the API requests made to one organization, or all organizations with -all,
+// over -timespan. With -detailed every request is listed along with the top admins and user agents.
+func APIUsage(client Client, cfg *config.Config) error {
+	orgs, err := selectedOrganizations(client, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to get organizations: %w", err)
+	}
+
+	usages := make([]meraki.APIUsage, 0)
+	for _, org := range orgs {
+		if cfg.Organization != "" && org.ID != cfg.Organization {
+			continue
+		}
+
+		usage, err := client.GetAPIUsage(org.ID, cfg.Timespan, cfg.Detailed)
+		if err != nil {
+			if cfg.Organization != "" {
+				return fmt.Errorf("failed to get API usage: %w", err)
+			}
+			slog.Error("Failed to get API usage for organization", "orgID", org.ID, "orgName", org.Name, "error", err)
+			continue
+		}
+		usage.Organization = org.Name
+		usages = append(usages, usage)
+
+		if usage.RateLimited > 0 {
+			slog.Warn("Organization API requests were rate limited", "orgID", org.ID, "orgName", org.Name, "rate_limited", usage.RateLimited)
+		}
+	}
+
+	// Output to stdout or file
+	writer := newOutputWriter(cfg)
+	if cfg.OutputFile == "" || cfg.OutputFile == "-" {
+		if err := writer.WriteTo(usages, stdout); err != nil {
+			return fmt.Errorf("failed to write output to stdout: %w", err)
+		}
+		slog.Info("API usage sent to stdout", "organizations", len(usages))
+	} else {
+		if err := writer.WriteToFile(usages, cfg.OutputFile); err != nil {
+			return fmt.Errorf("failed to write output to file: %w", err)
+		}
+		slog.Info("API usage written to file", "organizations", len(usages), "file", cfg.OutputFile)
+	}
+
+	return nil
+}
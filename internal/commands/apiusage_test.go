@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"meraki-info/internal/config"
+	"meraki-info/internal/meraki"
+)
+
+func TestAPIUsage(t *testing.T) {
+	out, _ := captureOutput(t)
+	client := newTestClient()
+	client.usageErrs = map[string]error{"org1": errors.New("forbidden")}
+
+	cfg := &config.Config{Command: "api-usage", InfoAll: true, OutputType: "json", Timespan: time.Hour, Detailed: true}
+	if err := APIUsage(client, cfg); err != nil {
+		t.Fatalf("APIUsage failed: %v", err)
+	}
+
+	var usages []meraki.APIUsage
+	if err := json.Unmarshal(out.Bytes(), &usages); err != nil {
+		t.Fatalf("Failed to parse JSON: %v\n%s", err, out.String())
+	}
+	if len(usages) != 1 || usages[0].Organization != "Org Two" || usages[0].TimespanSeconds != 3600 || len(usages[0].TopAdmins) != 1 {
+		t.Errorf("Expected the usage of the organization that could be read, got %+v", usages)
+	}
+	if client.called("GetAPIUsage org2 1h0m0s true") != 1 {
+		t.Errorf("Expected the timespan and -detailed to be passed to the client, got calls %v", client.calls)
+	}
+
+	// A failure for a single organization is an error
+	cfg = &config.Config{Command: "api-usage", Organization: "org1", OutputType: "json", Timespan: time.Hour}
+	if err := APIUsage(client, cfg); err == nil {
+		t.Error("Expected an error for the selected organization")
+	}
+}
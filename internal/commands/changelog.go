@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"fmt"
+	"log/slog"
+
+	"meraki-info/internal/config"
+	"meraki-info/internal/meraki"
+	"meraki-info/internal/output"
+)
+
+// ChangeLog collects the configuration change log of one organization, all organizations with
+// -all, or a single network with -network, within -since/-until and limited to -admin
+func ChangeLog(client Client, cfg *config.Config) error {
+	orgs, err := selectedOrganizations(client, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to get organizations: %w", err)
+	}
+
+	query := meraki.ConfigurationChangeQuery{
+		Network: cfg.Network,
+		Admin:   cfg.Admin,
+		Since:   cfg.Since,
+		Until:   cfg.Until,
+	}
+
+	var run output.RunSummary
+	allChanges := make([]meraki.ConfigurationChangeWithOrganization, 0)
+	for _, org := range orgs {
+		if cfg.Organization != "" && org.ID != cfg.Organization {
+			continue
+		}
+
+		changes, err := client.GetConfigurationChanges(org.ID, query)
+		if err != nil {
+			if cfg.Organization != "" || cfg.Strict {
+				return fmt.Errorf("failed to get configuration changes: %w", err)
+			}
+			run.AddOrganization(organizationFailed(org, err))
+			continue
+		}
+
+		// Add organization information to each change record
+		for _, change := range changes {
+			allChanges = append(allChanges, meraki.ConfigurationChangeWithOrganization{
+				ConfigurationChange: change,
+				Organization:        org.Name,
+				OrganizationID:      org.ID,
+			})
+		}
+		run.AddOrganization(output.OrganizationRunStats{Organization: org.Name, OrganizationID: org.ID, Items: len(changes)})
+	}
+
+	// Output to stdout or file
+	writer := newOutputWriter(cfg)
+	if cfg.OutputFile == "" || cfg.OutputFile == "-" {
+		if err := writer.WriteTo(allChanges, stdout); err != nil {
+			return fmt.Errorf("failed to write output to stdout: %w", err)
+		}
+		slog.Info("Configuration changes sent to stdout", "change_count", len(allChanges))
+	} else {
+		if err := writer.WriteToFile(allChanges, cfg.OutputFile); err != nil {
+			return fmt.Errorf("failed to write output to file: %w", err)
+		}
+		slog.Info("Configuration changes written to file", "change_count", len(allChanges), "file", cfg.OutputFile)
+	}
+
+	return incompleteRunError(run, stderr)
+}
@@ -0,0 +1,78 @@
+// Package commands implements the meraki-info commands: it fetches data through a Client,
+// applies the configured filters and writes the result to stdout, a file or a URL
+package commands
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"meraki-info/internal/config"
+	"meraki-info/internal/meraki"
+)
+
+// Client is the subset of the Meraki API client used by the commands, so tests can substitute a fake
+type Client interface {
+	GetOrganizations() ([]meraki.Organization, error)
+	GetOrganizationNetworks(organizationID string) ([]meraki.Network, error)
+	GetOrganizationNetworksByTags(organizationID string, tags []string, matchAll bool) ([]meraki.Network, error)
+	MatchNetworks(organizationID, pattern string) ([]meraki.Network, error)
+	ResolveNetworkID(organizationID, networkIdentifier string) (string, error)
+	GetRoutes(organizationID, networkIdentifier string) ([]meraki.Route, error)
+	GetAllNetworkRoutes(organizationID string) ([]meraki.NetworkRoutes, error)
+	GetLicenses(organizationID string) ([]meraki.License, error)
+	ResolveLicenseNetworks(organizationID string, licenses []meraki.License) ([]meraki.License, error)
+	GetDownDevices(organizationID, networkIdentifier string, downStatuses []string) ([]meraki.Device, error)
+	GetAlertingDevices(organizationID, networkIdentifier string) ([]meraki.Device, error)
+	GetNetworkHealthAlerts(networkID string) ([]meraki.HealthAlert, error)
+	GetDeviceStatusSummary(organizationID, networkIdentifier string) ([]meraki.DeviceStatusSummary, error)
+	GetOrganizationDeviceStatusTotal(organizationID string) (meraki.DeviceStatusSummary, error)
+	GetSwitchStacks(organizationID, networkIdentifier string) ([]meraki.SwitchStackWithNetwork, error)
+	GetDeviceDetails(serial string) (meraki.DeviceDetails, error)
+	GetDeviceLocations(organizationID, networkIdentifier string) ([]meraki.DeviceLocation, error)
+	GetAppliancePerformance(organizationID, networkIdentifier string, timespan time.Duration) ([]meraki.AppliancePerformance, error)
+	GetSwitchPortStatuses(organizationID, networkIdentifier string) ([]meraki.SwitchPortStatusWithNetwork, error)
+	GetNetworkSwitchPortStatuses(networkID, serial string) ([]meraki.SwitchPortStatus, error)
+	GetDHCPSubnets(organizationID, networkIdentifier string) ([]meraki.DHCPSubnetWithNetwork, error)
+	GetFirewallRules(organizationID, networkIdentifier string, includeL7 bool) ([]meraki.FirewallRuleWithNetwork, error)
+	GetNetworkEvents(organizationID, networkIdentifier string, query meraki.EventQuery) ([]meraki.EventWithNetwork, error)
+	GetAPIUsage(organizationID string, timespan time.Duration, detailed bool) (meraki.APIUsage, error)
+	GetConfigurationChanges(organizationID string, query meraki.ConfigurationChangeQuery) ([]meraki.ConfigurationChange, error)
+	RequestCount() int
+}
+
+// stdout and stderr receive command output and notes; overridden in tests
+var (
+	stdout io.Writer = os.Stdout
+	stderr io.Writer = os.Stderr
+)
+
+// TagFilter builds the -tag/-tag-match filter
+func TagFilter(cfg *config.Config) meraki.TagFilter {
+	return meraki.TagFilter{Tags: cfg.Tags, MatchAny: cfg.TagMatch == "any"}
+}
+
+// organizationNetworks lists the networks an -all run covers, restricted by the API to the
+// -network-tags (any or all of them, per -network-tag-match) when set and without the -exclude-network networks
+func organizationNetworks(client Client, cfg *config.Config, organizationID string) ([]meraki.Network, error) {
+	var networks []meraki.Network
+	var err error
+	if len(cfg.NetworkTags) > 0 {
+		networks, err = client.GetOrganizationNetworksByTags(organizationID, cfg.NetworkTags, cfg.NetworkTagMatch == "all")
+	} else {
+		networks, err = client.GetOrganizationNetworks(organizationID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return meraki.ExcludeNetworks(organizationID, networks, cfg.ExcludeNetworks), nil
+}
+
+// selectedOrganizations returns the organizations an -all run covers: those selected with several
+// -org values, otherwise every organization the API key can see
+func selectedOrganizations(client Client, cfg *config.Config) ([]meraki.Organization, error) {
+	if len(cfg.SelectedOrganizations) > 0 {
+		return cfg.SelectedOrganizations, nil
+	}
+	return client.GetOrganizations()
+}
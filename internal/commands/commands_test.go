@@ -0,0 +1,1123 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"meraki-info/internal/config"
+	"meraki-info/internal/meraki"
+	"meraki-info/internal/output"
+)
+
+// fakeClient is an in-memory Client; methods not needed by a test return empty results
+type fakeClient struct {
+	organizations []meraki.Organization
+	networks      map[string][]meraki.Network // keyed by organization ID
+	networkErrs   map[string]error            // keyed by organization ID
+	routes        map[string][]meraki.Route   // keyed by network ID
+	routeErrs     map[string]error            // keyed by network ID
+	devices       map[string][]meraki.Device  // keyed by network ID
+	deviceErrs    map[string]error            // keyed by network ID
+	usageErrs     map[string]error            // keyed by organization ID
+	calls         []string
+
+	// ports are the switch port statuses keyed by organization ID
+	ports map[string][]meraki.SwitchPortStatusWithNetwork
+
+	// changes are the configuration changes keyed by organization ID
+	changes map[string][]meraki.ConfigurationChange
+
+	// appliances are the appliance performance records keyed by organization ID
+	appliances map[string][]meraki.AppliancePerformance
+
+	// licenses are the licenses keyed by organization ID
+	licenses map[string][]meraki.License
+
+	// alerting are the alerting devices and healthAlerts the health alerts, keyed by network ID
+	alerting     map[string][]meraki.Device
+	healthAlerts map[string][]meraki.HealthAlert
+}
+
+func (f *fakeClient) record(call string) {
+	f.calls = append(f.calls, call)
+}
+
+func (f *fakeClient) GetOrganizations() ([]meraki.Organization, error) {
+	f.record("GetOrganizations")
+	return f.organizations, nil
+}
+
+func (f *fakeClient) GetOrganizationNetworks(organizationID string) ([]meraki.Network, error) {
+	f.record("GetOrganizationNetworks " + organizationID)
+	if err := f.networkErrs[organizationID]; err != nil {
+		return nil, err
+	}
+	return f.networks[organizationID], nil
+}
+
+func (f *fakeClient) GetOrganizationNetworksByTags(organizationID string, tags []string, matchAll bool) ([]meraki.Network, error) {
+	f.record("GetOrganizationNetworksByTags " + organizationID)
+	if err := f.networkErrs[organizationID]; err != nil {
+		return nil, err
+	}
+	return meraki.FilterNetworksByTag(f.networks[organizationID], meraki.TagFilter{Tags: tags, MatchAny: !matchAll}), nil
+}
+
+func (f *fakeClient) MatchNetworks(organizationID, pattern string) ([]meraki.Network, error) {
+	var matched []meraki.Network
+	for _, network := range f.networks[organizationID] {
+		if ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(network.Name)); ok {
+			matched = append(matched, network)
+		}
+	}
+	return matched, nil
+}
+
+func (f *fakeClient) ResolveNetworkID(organizationID, networkIdentifier string) (string, error) {
+	for _, network := range f.networks[organizationID] {
+		if network.ID == networkIdentifier || strings.EqualFold(network.Name, networkIdentifier) {
+			return network.ID, nil
+		}
+	}
+	return "", fmt.Errorf("network '%s' not found in organization %s", networkIdentifier, organizationID)
+}
+
+func (f *fakeClient) GetRoutes(organizationID, networkIdentifier string) ([]meraki.Route, error) {
+	f.record("GetRoutes " + networkIdentifier)
+	if err := f.routeErrs[networkIdentifier]; err != nil {
+		return nil, err
+	}
+	return f.routes[networkIdentifier], nil
+}
+
+func (f *fakeClient) GetAllNetworkRoutes(organizationID string) ([]meraki.NetworkRoutes, error) {
+	f.record("GetAllNetworkRoutes " + organizationID)
+	if err := f.networkErrs[organizationID]; err != nil {
+		return nil, err
+	}
+	var networkRoutes []meraki.NetworkRoutes
+	for _, network := range f.networks[organizationID] {
+		nr := meraki.NetworkRoutes{Network: network, Routes: f.routes[network.ID]}
+		if err := f.routeErrs[network.ID]; err != nil {
+			nr.Error = err.Error()
+		}
+		networkRoutes = append(networkRoutes, nr)
+	}
+	return networkRoutes, nil
+}
+
+func (f *fakeClient) GetLicenses(organizationID string) ([]meraki.License, error) {
+	return f.licenses[organizationID], nil
+}
+
+func (f *fakeClient) ResolveLicenseNetworks(organizationID string, licenses []meraki.License) ([]meraki.License, error) {
+	return licenses, nil
+}
+
+func (f *fakeClient) GetDownDevices(organizationID, networkIdentifier string, downStatuses []string) ([]meraki.Device, error) {
+	f.record("GetDownDevices " + networkIdentifier)
+	if err := f.deviceErrs[networkIdentifier]; err != nil {
+		return nil, err
+	}
+	return f.devices[networkIdentifier], nil
+}
+
+func (f *fakeClient) GetAlertingDevices(organizationID, networkIdentifier string) ([]meraki.Device, error) {
+	return f.alerting[networkIdentifier], nil
+}
+
+func (f *fakeClient) GetNetworkHealthAlerts(networkID string) ([]meraki.HealthAlert, error) {
+	f.record("GetNetworkHealthAlerts " + networkID)
+	return f.healthAlerts[networkID], nil
+}
+
+func (f *fakeClient) GetDeviceStatusSummary(organizationID, networkIdentifier string) ([]meraki.DeviceStatusSummary, error) {
+	return nil, nil
+}
+
+func (f *fakeClient) GetOrganizationDeviceStatusTotal(organizationID string) (meraki.DeviceStatusSummary, error) {
+	return meraki.DeviceStatusSummary{}, nil
+}
+
+func (f *fakeClient) GetSwitchStacks(organizationID, networkIdentifier string) ([]meraki.SwitchStackWithNetwork, error) {
+	return nil, nil
+}
+
+func (f *fakeClient) GetDeviceDetails(serial string) (meraki.DeviceDetails, error) {
+	f.record("GetDeviceDetails " + serial)
+	for networkID, devices := range f.devices {
+		for _, device := range devices {
+			if device.Serial == serial {
+				return meraki.DeviceDetails{Serial: device.Serial, Name: device.Name, Model: device.Model, NetworkID: networkID}, nil
+			}
+		}
+	}
+	return meraki.DeviceDetails{}, fmt.Errorf("failed to get device %s: %w", serial, meraki.ErrNotFound)
+}
+
+func (f *fakeClient) GetDeviceLocations(organizationID, networkIdentifier string) ([]meraki.DeviceLocation, error) {
+	f.record("GetDeviceLocations " + organizationID)
+	var locations []meraki.DeviceLocation
+	for _, network := range f.networks[organizationID] {
+		for _, device := range f.devices[network.ID] {
+			if device.HasLocation() {
+				locations = append(locations, meraki.DeviceLocation{Serial: device.Serial, Lat: device.Lat, Lng: device.Lng, NetworkID: network.ID, NetworkName: network.Name})
+			}
+		}
+	}
+	return locations, nil
+}
+
+func (f *fakeClient) GetAppliancePerformance(organizationID, networkIdentifier string, timespan time.Duration) ([]meraki.AppliancePerformance, error) {
+	f.record(fmt.Sprintf("GetAppliancePerformance %s %s", organizationID, timespan))
+	return f.appliances[organizationID], nil
+}
+
+func (f *fakeClient) GetNetworkSwitchPortStatuses(networkID, serial string) ([]meraki.SwitchPortStatus, error) {
+	f.record("GetNetworkSwitchPortStatuses " + networkID + " " + serial)
+	var statuses []meraki.SwitchPortStatus
+	for _, ports := range f.ports {
+		for _, port := range ports {
+			if port.NetworkID == networkID && port.Serial == serial {
+				statuses = append(statuses, port.SwitchPortStatus)
+			}
+		}
+	}
+	return statuses, nil
+}
+
+func (f *fakeClient) GetSwitchPortStatuses(organizationID, networkIdentifier string) ([]meraki.SwitchPortStatusWithNetwork, error) {
+	return f.ports[organizationID], nil
+}
+
+func (f *fakeClient) GetDHCPSubnets(organizationID, networkIdentifier string) ([]meraki.DHCPSubnetWithNetwork, error) {
+	return nil, nil
+}
+
+func (f *fakeClient) GetFirewallRules(organizationID, networkIdentifier string, includeL7 bool) ([]meraki.FirewallRuleWithNetwork, error) {
+	return nil, nil
+}
+
+func (f *fakeClient) GetNetworkEvents(organizationID, networkIdentifier string, query meraki.EventQuery) ([]meraki.EventWithNetwork, error) {
+	return nil, nil
+}
+
+func (f *fakeClient) GetConfigurationChanges(organizationID string, query meraki.ConfigurationChangeQuery) ([]meraki.ConfigurationChange, error) {
+	f.record("GetConfigurationChanges " + organizationID + " " + query.Network)
+	if err := f.networkErrs[organizationID]; err != nil {
+		return nil, err
+	}
+	return f.changes[organizationID], nil
+}
+
+func (f *fakeClient) GetAPIUsage(organizationID string, timespan time.Duration, detailed bool) (meraki.APIUsage, error) {
+	f.record(fmt.Sprintf("GetAPIUsage %s %s %t", organizationID, timespan, detailed))
+	if err := f.usageErrs[organizationID]; err != nil {
+		return meraki.APIUsage{}, err
+	}
+	usage := meraki.APIUsage{
+		OrganizationID:  organizationID,
+		TimespanSeconds: int(timespan.Seconds()),
+		TotalRequests:   12,
+		RateLimited:     2,
+		ResponseCodes:   []meraki.UsageCount{{Key: "200", Count: 10}, {Key: "429", Count: 2}},
+	}
+	if detailed {
+		usage.TopAdmins = []meraki.UsageCount{{Key: "admin-" + organizationID, Count: 12}}
+	}
+	return usage, nil
+}
+
+func (f *fakeClient) RequestCount() int {
+	return len(f.calls)
+}
+
+// captureOutput redirects command output to buffers for the duration of the test
+func captureOutput(t *testing.T) (*bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	oldStdout, oldStderr := stdout, stderr
+	stdout, stderr = &out, &errOut
+	t.Cleanup(func() { stdout, stderr = oldStdout, oldStderr })
+	return &out, &errOut
+}
+
+// newTestClient returns a fake with two organizations of two networks each
+func newTestClient() *fakeClient {
+	return &fakeClient{
+		organizations: []meraki.Organization{{ID: "org1", Name: "Org One"}, {ID: "org2", Name: "Org Two"}},
+		networks: map[string][]meraki.Network{
+			"org1": {{ID: "N_1", Name: "Branch 1"}, {ID: "N_2", Name: "Branch 2"}},
+			"org2": {{ID: "N_3", Name: "Branch 3"}, {ID: "N_4", Name: "Branch 4"}},
+		},
+		routes: map[string][]meraki.Route{
+			"N_1": {{Subnet: "10.1.0.0/24", GatewayIP: "10.1.0.1", Enabled: true}},
+			"N_2": {{Subnet: "10.2.0.0/24", GatewayIP: "10.2.0.1", Enabled: true}},
+			"N_3": {{Subnet: "10.3.0.0/24", GatewayIP: "10.3.0.1", Enabled: true}},
+			"N_4": {{Subnet: "10.4.0.0/24", GatewayIP: "10.4.0.1", Enabled: true}},
+		},
+		devices: map[string][]meraki.Device{
+			"N_1": {{Serial: "Q2AA-0001", Status: "offline"}},
+			"N_2": {{Serial: "Q2AA-0002", Status: "offline"}},
+			"N_3": {{Serial: "Q2AA-0003", Status: "offline"}},
+			"N_4": {{Serial: "Q2AA-0004", Status: "offline"}},
+		},
+	}
+}
+
+func (f *fakeClient) called(prefix string) int {
+	count := 0
+	for _, call := range f.calls {
+		if strings.HasPrefix(call, prefix) {
+			count++
+		}
+	}
+	return count
+}
+
+func TestAllNetworkRoutes_Consolidated(t *testing.T) {
+	out, _ := captureOutput(t)
+	client := newTestClient()
+	cfg := &config.Config{Command: "route-tables", InfoAll: true, OutputType: "json"}
+
+	if err := AllNetworkRoutes(client, cfg); err != nil {
+		t.Fatalf("AllNetworkRoutes failed: %v", err)
+	}
+
+	if client.called("GetAllNetworkRoutes") != 2 {
+		t.Errorf("Expected GetAllNetworkRoutes per organization, got calls %v", client.calls)
+	}
+	if client.called("GetRoutes") != 0 {
+		t.Errorf("Consolidated output should not fetch routes per network, got calls %v", client.calls)
+	}
+
+	var routes []meraki.RouteWithNetwork
+	if err := json.Unmarshal(out.Bytes(), &routes); err != nil {
+		t.Fatalf("Failed to parse stdout as JSON: %v\n%s", err, out.String())
+	}
+	if len(routes) != 4 {
+		t.Fatalf("Expected 4 consolidated routes, got %d", len(routes))
+	}
+	if routes[0].Organization != "Org One" || routes[0].NetworkName != "Branch 1" {
+		t.Errorf("Expected network and organization on consolidated routes, got %+v", routes[0])
+	}
+}
+
+func TestAllNetworkRoutes_SeparateFiles(t *testing.T) {
+	out, _ := captureOutput(t)
+	client := newTestClient()
+	outputFile := filepath.Join(t.TempDir(), "routes.json")
+	cfg := &config.Config{Command: "route-tables", InfoAll: true, OutputType: "json", OutputFile: outputFile, Organization: "org1"}
+
+	if err := AllNetworkRoutes(client, cfg); err != nil {
+		t.Fatalf("AllNetworkRoutes failed: %v", err)
+	}
+
+	if client.called("GetAllNetworkRoutes") != 0 {
+		t.Errorf("Separate-file output should fetch routes per network, got calls %v", client.calls)
+	}
+	if client.called("GetRoutes") != 2 {
+		t.Errorf("Expected GetRoutes for each network of org1, got calls %v", client.calls)
+	}
+	if out.Len() != 0 {
+		t.Errorf("Expected nothing on stdout, got %q", out.String())
+	}
+	if _, err := os.Stat(outputFile); err != nil {
+		t.Errorf("Expected output file to be written: %v", err)
+	}
+}
+
+func TestAllNetworkRoutes_Manifest(t *testing.T) {
+	captureOutput(t)
+	client := newTestClient()
+	client.routes["N_2"] = append(client.routes["N_2"], meraki.Route{Subnet: "10.2.1.0/24", GatewayIP: "10.2.0.1", Enabled: true})
+	dir := t.TempDir()
+	manifestFile := filepath.Join(dir, "manifest.json")
+	cfg := &config.Config{Command: "route-tables", InfoAll: true, OutputType: "json", Organization: "org1",
+		OutputFile: filepath.Join(dir, "routes-{network}.json"), Manifest: manifestFile}
+
+	if err := AllNetworkRoutes(client, cfg); err != nil {
+		t.Fatalf("AllNetworkRoutes failed: %v", err)
+	}
+
+	content, err := os.ReadFile(manifestFile)
+	if err != nil {
+		t.Fatalf("Expected the manifest to be written: %v", err)
+	}
+	var manifest struct {
+		Files []output.ManifestEntry `json:"files"`
+	}
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		t.Fatalf("Failed to parse manifest: %v", err)
+	}
+	if len(manifest.Files) != 2 {
+		t.Fatalf("Expected a manifest entry per network file, got %+v", manifest.Files)
+	}
+	for i, expected := range []struct {
+		network string
+		records int
+	}{{"N_1", 1}, {"N_2", 2}} {
+		entry := manifest.Files[i]
+		if entry.File != filepath.Join(dir, "routes-"+expected.network+".json") || entry.OrganizationID != "org1" ||
+			entry.NetworkID != expected.network || entry.Records != expected.records {
+			t.Errorf("Expected %d record(s) of %s, got %+v", expected.records, expected.network, entry)
+		}
+		info, err := os.Stat(entry.File)
+		if err != nil || info.Size() != entry.Size {
+			t.Errorf("Expected the size of %s, got %+v (%v)", entry.File, entry, err)
+		}
+	}
+}
+
+func TestAllNetworkRoutes_SeparateFilesContinuesOnError(t *testing.T) {
+	_, errOut := captureOutput(t)
+	client := newTestClient()
+	client.networkErrs = map[string]error{"org1": errors.New("forbidden")}
+	client.routeErrs = map[string]error{"N_3": errors.New("not found")}
+	outputFile := filepath.Join(t.TempDir(), "routes.json")
+	cfg := &config.Config{Command: "route-tables", InfoAll: true, OutputType: "json", OutputFile: outputFile}
+
+	if err := AllNetworkRoutes(client, cfg); !errors.Is(err, ErrIncompleteRun) {
+		t.Fatalf("Expected failures to be skipped and the run to be incomplete, got %v", err)
+	}
+	if !strings.Contains(errOut.String(), "  - Org One (org1): error getting organization networks: forbidden\n") {
+		t.Errorf("Expected incomplete note for org1 on stderr, got %q", errOut.String())
+	}
+	if !strings.Contains(errOut.String(), "  - Org Two / Branch 3 (N_3): ") {
+		t.Errorf("Expected failed network note for N_3 on stderr, got %q", errOut.String())
+	}
+
+	// org1 fails to list networks, N_3 fails to fetch routes, N_4 is still written
+	if got := strings.Join(client.calls, ","); got != "GetOrganizations,GetOrganizationNetworks org1,GetOrganizationNetworks org2,GetRoutes N_3,GetRoutes N_4" {
+		t.Errorf("Unexpected calls: %s", got)
+	}
+	data, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Expected output file to be written: %v", err)
+	}
+	if !strings.Contains(string(data), "10.4.0.0/24") {
+		t.Errorf("Expected routes of N_4 in output file, got %s", data)
+	}
+}
+
+func TestAllNetworkRoutes_RunSummaryAPICalls(t *testing.T) {
+	_, errOut := captureOutput(t)
+	client := newTestClient()
+	// Requests made before the command, such as resolving -org, are not the organization's
+	client.record("GetOrganizations")
+
+	cfg := &config.Config{Command: "route-tables", InfoAll: true, OutputType: "json", RunSummary: true,
+		Organization: "org1", OrganizationName: "Org One"}
+	if err := AllNetworkRoutes(client, cfg); err != nil {
+		t.Fatalf("AllNetworkRoutes failed: %v", err)
+	}
+
+	var run output.RunSummary
+	if err := json.Unmarshal(errOut.Bytes(), &run); err != nil {
+		t.Fatalf("Failed to parse the run summary on stderr as JSON: %v\n%s", err, errOut.String())
+	}
+	if len(run.Organizations) != 1 || run.Organizations[0].APICalls != 1 || run.Organizations[0].NetworksScanned != 2 {
+		t.Errorf("Expected 2 networks scanned with 1 API call for org1, got %+v", run.Organizations)
+	}
+}
+
+func TestAllNetworkRoutes_ConsolidatedContinuesOnError(t *testing.T) {
+	out, errOut := captureOutput(t)
+	client := newTestClient()
+	client.networkErrs = map[string]error{"org1": errors.New("forbidden")}
+
+	cfg := &config.Config{Command: "route-tables", InfoAll: true, OutputType: "json"}
+	if err := AllNetworkRoutes(client, cfg); !errors.Is(err, ErrIncompleteRun) {
+		t.Fatalf("Expected the failed organization to be skipped and the run to be incomplete, got %v", err)
+	}
+
+	var routes []meraki.RouteWithNetwork
+	if err := json.Unmarshal(out.Bytes(), &routes); err != nil {
+		t.Fatalf("Failed to parse stdout as JSON: %v", err)
+	}
+	if len(routes) != 2 || routes[0].OrganizationID != "org2" {
+		t.Errorf("Expected the routes of org2 only, got %+v", routes)
+	}
+	if !strings.Contains(errOut.String(), "Org One (org1): forbidden") {
+		t.Errorf("Expected incomplete note for org1 on stderr, got %q", errOut.String())
+	}
+}
+
+func TestAllNetworkRoutes_AccessDenied(t *testing.T) {
+	_, errOut := captureOutput(t)
+	client := newTestClient()
+	client.networkErrs = map[string]error{"org1": fmt.Errorf("failed to get networks: %w", meraki.ErrForbidden)}
+
+	cfg := &config.Config{Command: "route-tables", InfoAll: true, OutputType: "json"}
+	if err := AllNetworkRoutes(client, cfg); !errors.Is(err, ErrIncompleteRun) {
+		t.Errorf("Expected a denied organization to make the run incomplete, got %v", err)
+	}
+	if !strings.Contains(errOut.String(), "could access 1 of 2 organization(s):\n  Accessible: Org Two\n  Denied: Org One (org1)\n") {
+		t.Errorf("Expected an access note on stderr, got %q", errOut.String())
+	}
+	if strings.Contains(errOut.String(), "are incomplete") {
+		t.Errorf("Expected the denied organization only in the access note, got %q", errOut.String())
+	}
+}
+
+func TestAllNetworkDownDevices_ContinuesOnError(t *testing.T) {
+	out, errOut := captureOutput(t)
+	client := newTestClient()
+	client.networkErrs = map[string]error{"org2": errors.New("forbidden")}
+	client.deviceErrs = map[string]error{"N_1": errors.New("timeout")}
+
+	cfg := &config.Config{Command: "down", InfoAll: true, OutputType: "json"}
+	count, err := AllNetworkDownDevices(client, cfg)
+	if !errors.Is(err, ErrIncompleteRun) {
+		t.Fatalf("Expected failures to be skipped and the run to be incomplete, got %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 down device, got %d", count)
+	}
+
+	var devices []meraki.DeviceWithNetwork
+	if err := json.Unmarshal(out.Bytes(), &devices); err != nil {
+		t.Fatalf("Failed to parse stdout as JSON: %v", err)
+	}
+	if len(devices) != 1 || devices[0].Serial != "Q2AA-0002" {
+		t.Errorf("Expected only the device of N_2, got %+v", devices)
+	}
+	if !strings.Contains(errOut.String(), "Org Two (org2): forbidden") {
+		t.Errorf("Expected incomplete note for org2 on stderr, got %q", errOut.String())
+	}
+	if !strings.Contains(errOut.String(), "1 network(s) failed and are missing from the output:\n  - Org One / Branch 1 (N_1): timeout\n") {
+		t.Errorf("Expected failed network note for N_1 on stderr, got %q", errOut.String())
+	}
+}
+
+func TestAllNetworkDownDevices_FailedNetwork(t *testing.T) {
+	out, errOut := captureOutput(t)
+	client := newTestClient()
+	client.deviceErrs = map[string]error{"N_3": errors.New("timeout")}
+
+	cfg := &config.Config{Command: "down", InfoAll: true, OutputType: "json"}
+	count, err := AllNetworkDownDevices(client, cfg)
+	if !errors.Is(err, ErrIncompleteRun) || !strings.Contains(err.Error(), "0 organization(s) and 1 network(s)") {
+		t.Fatalf("Expected ErrIncompleteRun for the failed network, got %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected the down devices of the other 3 networks, got %d", count)
+	}
+	var devices []meraki.DeviceWithNetwork
+	if err := json.Unmarshal(out.Bytes(), &devices); err != nil {
+		t.Fatalf("Failed to parse stdout as JSON: %v", err)
+	}
+	if len(devices) != 3 {
+		t.Errorf("Expected the successful networks to be written, got %+v", devices)
+	}
+	if !strings.Contains(errOut.String(), "Org Two / Branch 3 (N_3): timeout") {
+		t.Errorf("Expected failed network note on stderr, got %q", errOut.String())
+	}
+
+	// -strict stops at the failed network without writing anything
+	out.Reset()
+	client.calls = nil
+	cfg.Strict = true
+	if _, err := AllNetworkDownDevices(client, cfg); err == nil || errors.Is(err, ErrIncompleteRun) || !strings.Contains(err.Error(), "Branch 3") {
+		t.Errorf("Expected -strict to abort on Branch 3, got %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("Expected no output with -strict, got %q", out.String())
+	}
+	if client.called("GetDownDevices N_4") != 0 {
+		t.Errorf("Expected -strict to skip the networks after the failure, got calls %v", client.calls)
+	}
+}
+
+func TestAllNetworkDownDevices_SeparateFiles(t *testing.T) {
+	out, errOut := captureOutput(t)
+	client := newTestClient()
+	client.deviceErrs = map[string]error{"N_2": errors.New("timeout")}
+	outputFile := filepath.Join(t.TempDir(), "down.json")
+
+	cfg := &config.Config{Command: "down", InfoAll: true, OutputType: "json", OutputFile: outputFile, Organization: "org1", OrganizationName: "Org One"}
+	count, err := AllNetworkDownDevices(client, cfg)
+	if !errors.Is(err, ErrIncompleteRun) || !strings.Contains(err.Error(), "1 network(s)") {
+		t.Fatalf("Expected ErrIncompleteRun for the failed network, got %v", err)
+	}
+	if !strings.Contains(errOut.String(), "  - Org One / Branch 2 (N_2): ") {
+		t.Errorf("Expected failed network note for N_2 on stderr, got %q", errOut.String())
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 down device from the networks that succeeded, got %d", count)
+	}
+	if client.called("GetOrganizations") != 0 {
+		t.Errorf("Expected only the configured organization to be scanned, got calls %v", client.calls)
+	}
+	if out.Len() != 0 {
+		t.Errorf("Expected nothing on stdout, got %q", out.String())
+	}
+}
+
+func TestAllNetworkDownDevices_NetworkTags(t *testing.T) {
+	out, _ := captureOutput(t)
+	client := newTestClient()
+	client.networks["org1"][1].Tags = []string{"production"}
+	client.networks["org2"][0].Tags = []string{"branch"}
+
+	cfg := &config.Config{Command: "down", InfoAll: true, OutputType: "json", NetworkTags: []string{"production", "branch"}}
+	count, err := AllNetworkDownDevices(client, cfg)
+	if err != nil {
+		t.Fatalf("AllNetworkDownDevices failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected the down devices of the 2 tagged networks, got %d", count)
+	}
+	if client.called("GetOrganizationNetworks ") != 0 || client.called("GetOrganizationNetworksByTags") != 2 {
+		t.Errorf("Expected networks to be listed by tag, got calls %v", client.calls)
+	}
+	if !strings.Contains(out.String(), "Q2AA-0002") || !strings.Contains(out.String(), "Q2AA-0003") {
+		t.Errorf("Expected devices of N_2 and N_3, got %s", out.String())
+	}
+
+	// With -network-tag-match all no network carries both tags
+	cfg.NetworkTagMatch = "all"
+	count, err = AllNetworkDownDevices(client, cfg)
+	if err != nil {
+		t.Fatalf("AllNetworkDownDevices failed: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected no networks to carry every tag, got %d down devices", count)
+	}
+}
+
+func TestAllNetworkRoutes_SelectedOrganizations(t *testing.T) {
+	out, _ := captureOutput(t)
+	client := newTestClient()
+	client.organizations = append(client.organizations, meraki.Organization{ID: "org3", Name: "Org Three"})
+
+	cfg := &config.Config{Command: "route-tables", InfoAll: true, OutputType: "json", SelectedOrganizations: []meraki.Organization{{ID: "org2", Name: "Org Two"}}}
+	if err := AllNetworkRoutes(client, cfg); err != nil {
+		t.Fatalf("AllNetworkRoutes failed: %v", err)
+	}
+
+	if got := strings.Join(client.calls, ","); got != "GetAllNetworkRoutes org2" {
+		t.Errorf("Expected only the selected organization to be scanned, got calls %s", got)
+	}
+	var routes []meraki.RouteWithNetwork
+	if err := json.Unmarshal(out.Bytes(), &routes); err != nil {
+		t.Fatalf("Failed to parse stdout as JSON: %v", err)
+	}
+	if len(routes) != 2 || routes[0].Organization != "Org Two" {
+		t.Errorf("Expected the routes of org2 labelled with its name, got %+v", routes)
+	}
+}
+
+func TestAllNetworkDownDevices_SerialShortCircuits(t *testing.T) {
+	out, _ := captureOutput(t)
+	client := newTestClient()
+
+	cfg := &config.Config{Command: "down", InfoAll: true, OutputType: "json", SerialFilter: "q2aa-0002"}
+	count, err := AllNetworkDownDevices(client, cfg)
+	if err != nil {
+		t.Fatalf("AllNetworkDownDevices failed: %v", err)
+	}
+	if count != 1 || !strings.Contains(out.String(), "Q2AA-0002") || strings.Contains(out.String(), "Q2AA-0001") {
+		t.Errorf("Expected only the device with the serial, got %d:\n%s", count, out.String())
+	}
+	if got := strings.Join(client.calls, ","); got != "GetOrganizations,GetOrganizationNetworks org1,GetDownDevices N_1,GetDownDevices N_2" {
+		t.Errorf("Expected no networks to be fetched after the device was found, got calls %s", got)
+	}
+
+	out.Reset()
+	client.calls = nil
+	cfg.SerialFilter = "Q2ZZ-9999"
+	if count, err = AllNetworkDownDevices(client, cfg); err != nil || count != 0 {
+		t.Errorf("Expected no devices for an unknown serial, got %d, %v", count, err)
+	}
+	if client.called("GetDownDevices") != 4 {
+		t.Errorf("Expected every network to be searched for an unknown serial, got calls %v", client.calls)
+	}
+}
+
+func TestAllNetworkRoutes_FilterRegexMatchesNetworkName(t *testing.T) {
+	out, _ := captureOutput(t)
+	client := newTestClient()
+	cfg := &config.Config{Command: "route-tables", InfoAll: true, OutputType: "json", FilterRegex: regexp.MustCompile(`^Branch [13]$`)}
+
+	if err := AllNetworkRoutes(client, cfg); err != nil {
+		t.Fatalf("AllNetworkRoutes failed: %v", err)
+	}
+
+	for _, subnet := range []string{"10.1.0.0/24", "10.3.0.0/24"} {
+		if !strings.Contains(out.String(), subnet) {
+			t.Errorf("Expected routes of the matching network with subnet %s, got:\n%s", subnet, out.String())
+		}
+	}
+	for _, subnet := range []string{"10.2.0.0/24", "10.4.0.0/24"} {
+		if strings.Contains(out.String(), subnet) {
+			t.Errorf("Expected no routes of other networks, got %s:\n%s", subnet, out.String())
+		}
+	}
+}
+
+func TestAllNetworkRoutes_Gateway(t *testing.T) {
+	out, _ := captureOutput(t)
+	client := newTestClient()
+	cfg := &config.Config{Command: "route-tables", InfoAll: true, OutputType: "json", Gateway: "10.2.0.0/16"}
+
+	if err := AllNetworkRoutes(client, cfg); err != nil {
+		t.Fatalf("AllNetworkRoutes failed: %v", err)
+	}
+
+	var routes []meraki.RouteWithNetwork
+	if err := json.Unmarshal(out.Bytes(), &routes); err != nil {
+		t.Fatalf("Failed to parse stdout as JSON: %v\n%s", err, out.String())
+	}
+	if len(routes) != 1 || routes[0].GatewayIP != "10.2.0.1" {
+		t.Errorf("Expected only the route via 10.2.0.1, got %+v", routes)
+	}
+}
+
+func TestAllNetworkRoutes_DetectOverlaps(t *testing.T) {
+	captureOutput(t)
+	client := newTestClient()
+	client.routes["N_3"] = []meraki.Route{{Subnet: "10.0.0.0/8", GatewayIP: "10.0.0.1", Enabled: true}}
+	outputFile := filepath.Join(t.TempDir(), "overlaps.json")
+	cfg := &config.Config{Command: "route-tables", InfoAll: true, OutputType: "json", OutputFile: outputFile, DetectOverlaps: true}
+
+	if err := AllNetworkRoutes(client, cfg); err != nil {
+		t.Fatalf("AllNetworkRoutes failed: %v", err)
+	}
+
+	// Overlaps span networks, so they are written to the one file rather than a file per network
+	data, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Expected a single consolidated overlap report: %v", err)
+	}
+	var overlaps []meraki.RouteOverlap
+	if err := json.Unmarshal(data, &overlaps); err != nil {
+		t.Fatalf("Failed to parse output as JSON: %v\n%s", err, data)
+	}
+	if len(overlaps) != 3 {
+		t.Fatalf("Expected 10.0.0.0/8 to overlap the 3 other networks' routes, got %+v", overlaps)
+	}
+	for _, overlap := range overlaps {
+		if overlap.Route.NetworkName != "Branch 3" || overlap.Relation != meraki.OverlapContains || overlap.OverlapsWith.NetworkName == "Branch 3" {
+			t.Errorf("Expected Branch 3's supernet to contain another network's route, got %+v", overlap)
+		}
+	}
+}
+
+func TestNetworks(t *testing.T) {
+	out, _ := captureOutput(t)
+	client := newTestClient()
+	client.networks["org2"] = []meraki.Network{
+		{ID: "N_3", Name: "Branch 3", ProductTypes: []string{"appliance", "switch"}, Tags: []string{"retail"}, Notes: "Kiosk"},
+		{ID: "N_4", Name: "Branch 4", ProductTypes: []string{"wireless"}},
+	}
+	cfg := &config.Config{Command: "networks", InfoAll: true, OutputType: "csv", ProductTypeFilter: []string{"appliance"}}
+
+	if err := Networks(client, cfg); err != nil {
+		t.Fatalf("Networks failed: %v", err)
+	}
+
+	expected := "Organization,Organization ID,Network ID,Network Name,Product Types,Time Zone,Tags,Enrollment String,Notes,URL\n" +
+		"Org Two,org2,N_3,Branch 3,appliance switch,,retail,,Kiosk,\n"
+	if out.String() != expected {
+		t.Errorf("Expected only the appliance network:\n%s\ngot:\n%s", expected, out.String())
+	}
+
+	out.Reset()
+	cfg = &config.Config{Command: "networks", InfoAll: true, Organization: "org1", OrganizationName: "Org One", OutputType: "json"}
+	if err := Networks(client, cfg); err != nil {
+		t.Fatalf("Networks failed: %v", err)
+	}
+	var networks []meraki.NetworkWithOrganization
+	if err := json.Unmarshal(out.Bytes(), &networks); err != nil {
+		t.Fatalf("Failed to parse stdout as JSON: %v", err)
+	}
+	if len(networks) != 2 || networks[0].ID != "N_1" || networks[1].OrganizationID != "org1" {
+		t.Errorf("Expected the networks of org1 only, got %+v", networks)
+	}
+}
+
+func TestSwitchPorts(t *testing.T) {
+	out, _ := captureOutput(t)
+	client := newTestClient()
+	client.ports = map[string][]meraki.SwitchPortStatusWithNetwork{
+		"org1": {
+			{SwitchPortStatus: meraki.SwitchPortStatus{Serial: "Q2SW-0001", PortID: "1", Status: "Connected"}, NetworkID: "N_1"},
+			{SwitchPortStatus: meraki.SwitchPortStatus{Serial: "Q2SW-0001", PortID: "2", Status: "Connected", CRCAlignErrors: 7}, NetworkID: "N_1"},
+		},
+		"org2": {
+			{SwitchPortStatus: meraki.SwitchPortStatus{Serial: "Q2SW-0002", PortID: "5", Status: "Disconnected"}, NetworkID: "N_3"},
+		},
+	}
+
+	cfg := &config.Config{Command: "switchports", InfoAll: true, OutputType: "json", ErroredOnly: true}
+	if err := SwitchPorts(client, cfg); err != nil {
+		t.Fatalf("SwitchPorts failed: %v", err)
+	}
+	var ports []meraki.SwitchPortStatusWithNetwork
+	if err := json.Unmarshal(out.Bytes(), &ports); err != nil {
+		t.Fatalf("Failed to parse stdout as JSON: %v", err)
+	}
+	if len(ports) != 2 || ports[0].PortID != "2" || ports[0].Organization != "Org One" || ports[1].PortID != "5" {
+		t.Errorf("Expected the errored ports of both organizations, got %+v", ports)
+	}
+}
+
+func TestSwitchPorts_Serial(t *testing.T) {
+	out, _ := captureOutput(t)
+	client := newTestClient()
+	client.devices["N_3"] = append(client.devices["N_3"], meraki.Device{Serial: "Q2SW-0002", Model: "MS120-8"})
+	client.ports = map[string][]meraki.SwitchPortStatusWithNetwork{
+		"org2": {
+			{SwitchPortStatus: meraki.SwitchPortStatus{Serial: "Q2SW-0002", PortID: "5", Status: "Connected", VLAN: 20}, NetworkID: "N_3"},
+			{SwitchPortStatus: meraki.SwitchPortStatus{Serial: "Q2SW-0003", PortID: "1", Status: "Connected"}, NetworkID: "N_3"},
+		},
+	}
+
+	cfg := &config.Config{Command: "switchports", SerialFilter: "Q2SW-0002", OutputType: "json"}
+	if err := SwitchPorts(client, cfg); err != nil {
+		t.Fatalf("SwitchPorts failed: %v", err)
+	}
+	var ports []meraki.SwitchPortStatusWithNetwork
+	if err := json.Unmarshal(out.Bytes(), &ports); err != nil {
+		t.Fatalf("Failed to parse stdout as JSON: %v", err)
+	}
+	if len(ports) != 1 || ports[0].PortID != "5" || ports[0].VLAN != 20 || ports[0].NetworkID != "N_3" {
+		t.Errorf("Expected the ports of the selected switch only, got %+v", ports)
+	}
+	if client.called("GetOrganizations") != 0 || client.called("GetSwitchPortStatuses") != 0 {
+		t.Errorf("Expected the switch to be looked up without a network scan, got calls %v", client.calls)
+	}
+
+	cfg.SerialFilter = "Q2ZZ-9999"
+	if err := SwitchPorts(client, cfg); !errors.Is(err, ErrDeviceNotFound) {
+		t.Errorf("Expected ErrDeviceNotFound for an unknown serial, got: %v", err)
+	}
+}
+
+func TestChangeLog(t *testing.T) {
+	out, errOut := captureOutput(t)
+	client := newTestClient()
+	client.changes = map[string][]meraki.ConfigurationChange{
+		"org1": {{TS: "2026-10-15T08:00:00Z", AdminName: "Ann", Label: "VLAN", OldValue: "10", NewValue: "20", NetworkID: "N_1", NetworkName: "Network 1"}},
+		"org2": {{TS: "2026-10-15T09:00:00Z", AdminName: "Bob", Label: "SSID name"}},
+	}
+	client.networkErrs = map[string]error{"org2": errors.New("boom")}
+
+	cfg := &config.Config{Command: "change-log", InfoAll: true, OutputType: "json"}
+	if err := ChangeLog(client, cfg); !errors.Is(err, ErrIncompleteRun) {
+		t.Fatalf("Expected ErrIncompleteRun for the failed organization, got: %v", err)
+	}
+	var changes []meraki.ConfigurationChangeWithOrganization
+	if err := json.Unmarshal(out.Bytes(), &changes); err != nil {
+		t.Fatalf("Failed to parse stdout as JSON: %v", err)
+	}
+	if len(changes) != 1 || changes[0].AdminName != "Ann" || changes[0].OrganizationID != "org1" {
+		t.Errorf("Expected the changes of the organization that succeeded, got %+v", changes)
+	}
+	if !strings.Contains(errOut.String(), "Org Two") {
+		t.Errorf("Expected the failed organization to be reported, got %q", errOut.String())
+	}
+}
+
+func TestAllNetworkLicenses_MergeLicenses(t *testing.T) {
+	client := newTestClient()
+	client.licenses = map[string][]meraki.License{
+		"org1": {
+			{ID: "L1", State: "active", ExpirationDate: "2027-03-01T00:00:00Z", DurationInDays: 365},
+			{ID: "L2", State: "active", ExpirationDate: "2027-01-15T00:00:00Z", DurationInDays: 365},
+			{ID: "L3", State: "expired", ExpirationDate: "2026-01-01T00:00:00Z", DurationInDays: 30},
+		},
+		"org2": {{ID: "L4", State: "recentlyQueued", DurationInDays: 1095}},
+	}
+
+	out, _ := captureOutput(t)
+	cfg := &config.Config{Command: "licenses", InfoAll: true, OutputType: "json", DaysUntilExpiry: -1}
+	if err := AllNetworkLicenses(client, cfg); err != nil {
+		t.Fatalf("AllNetworkLicenses failed: %v", err)
+	}
+	var licenses []meraki.LicenseWithNetwork
+	if err := json.Unmarshal(out.Bytes(), &licenses); err != nil {
+		t.Fatalf("Failed to parse stdout as JSON: %v", err)
+	}
+	if len(licenses) != 4 {
+		t.Errorf("Expected every license without -merge-org-licenses, got %d", len(licenses))
+	}
+
+	out.Reset()
+	cfg.MergeLicenses = true
+	if err := AllNetworkLicenses(client, cfg); err != nil {
+		t.Fatalf("AllNetworkLicenses failed: %v", err)
+	}
+	var summaries []meraki.OrganizationLicenseSummary
+	if err := json.Unmarshal(out.Bytes(), &summaries); err != nil {
+		t.Fatalf("Failed to parse stdout as JSON: %v", err)
+	}
+	if len(summaries) != 2 {
+		t.Fatalf("Expected one entry per organization, got %+v", summaries)
+	}
+	org1 := summaries[0]
+	if org1.Organization != "Org One" || org1.Total != 3 || org1.TotalDurationDays != 760 {
+		t.Errorf("Unexpected summary for org1: %+v", org1)
+	}
+	if org1.SoonestExpiration != "2026-01-01" || org1.LatestExpiration != "2027-03-01" {
+		t.Errorf("Expected the expiration range of org1, got %s to %s", org1.SoonestExpiration, org1.LatestExpiration)
+	}
+	states := []meraki.LicenseStateCount{{State: "active", Count: 2}, {State: "expired", Count: 1}}
+	if !reflect.DeepEqual(org1.States, states) {
+		t.Errorf("Expected state counts %+v, got %+v", states, org1.States)
+	}
+	if summaries[1].OrganizationID != "org2" || summaries[1].Total != 1 || summaries[1].SoonestExpiration != "" {
+		t.Errorf("Unexpected summary for org2: %+v", summaries[1])
+	}
+}
+
+func TestAlertingDevices_HealthAlerts(t *testing.T) {
+	client := newTestClient()
+	client.alerting = map[string][]meraki.Device{
+		"N_1": {{Serial: "Q2AA-0001", Status: "alerting", NetworkID: "N_1"}},
+	}
+	client.healthAlerts = map[string][]meraki.HealthAlert{
+		"N_1": {
+			{ID: "a1", Scope: "device", Severity: "warning", Type: "Unreachable DNS", Source: meraki.HealthAlertSource{Serial: "Q2AA-0001"}},
+			{ID: "a2", Scope: "device", Severity: "informational", Type: "Port flapping", Source: meraki.HealthAlertSource{Serial: "Q2AA-0009", Name: "Closet"}},
+		},
+		"N_3": {{ID: "a3", Scope: "network", Severity: "error", Type: "VPN down"}},
+	}
+
+	out, _ := captureOutput(t)
+	cfg := &config.Config{Command: "alerting", InfoAll: true, OutputType: "json"}
+	count, err := AllNetworkAlertingDevices(client, cfg)
+	if err != nil {
+		t.Fatalf("AllNetworkAlertingDevices failed: %v", err)
+	}
+	if count != 1 || client.called("GetNetworkHealthAlerts") != 0 {
+		t.Errorf("Expected only status-based devices without -health-alerts, got %d and calls %v", count, client.calls)
+	}
+
+	out.Reset()
+	cfg.HealthAlerts = true
+	if count, err = AllNetworkAlertingDevices(client, cfg); err != nil {
+		t.Fatalf("AllNetworkAlertingDevices failed: %v", err)
+	}
+	var devices []meraki.DeviceWithNetwork
+	if err := json.Unmarshal(out.Bytes(), &devices); err != nil {
+		t.Fatalf("Failed to parse stdout as JSON: %v", err)
+	}
+	if count != 2 || len(devices) != 2 {
+		t.Fatalf("Expected the alerting device and the one with a device health alert, got %+v", devices)
+	}
+	if devices[0].HealthAlert != "warning: Unreachable DNS" {
+		t.Errorf("Expected the alerting device annotated with its alert, got %+v", devices[0])
+	}
+	if devices[1].Serial != "Q2AA-0009" || devices[1].Name != "Closet" || devices[1].NetworkName != "Branch 1" {
+		t.Errorf("Expected the health alert device in its network, got %+v", devices[1])
+	}
+	if calls := client.called("GetNetworkHealthAlerts"); calls != 4 {
+		t.Errorf("Expected health alerts to be fetched for every network, got %d calls", calls)
+	}
+}
+
+func TestLocations(t *testing.T) {
+	out, _ := captureOutput(t)
+	client := newTestClient()
+	client.devices["N_1"][0].Lat, client.devices["N_1"][0].Lng = 52.37, 4.89
+	client.devices["N_4"][0].Lat, client.devices["N_4"][0].Lng = 40.71, -74.01
+
+	cfg := &config.Config{Command: "locations", InfoAll: true, OutputType: "geojson"}
+	if err := Locations(client, cfg); err != nil {
+		t.Fatalf("Locations failed: %v", err)
+	}
+	var collection struct {
+		Features []struct {
+			Geometry struct {
+				Coordinates []float64 `json:"coordinates"`
+			} `json:"geometry"`
+			Properties meraki.DeviceLocation `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &collection); err != nil {
+		t.Fatalf("Failed to parse stdout as GeoJSON: %v", err)
+	}
+	if len(collection.Features) != 2 {
+		t.Fatalf("Expected the two placed devices, got %s", out.String())
+	}
+	feature := collection.Features[1]
+	if feature.Properties.Serial != "Q2AA-0004" || feature.Properties.Organization != "Org Two" || feature.Geometry.Coordinates[0] != -74.01 {
+		t.Errorf("Expected the org2 device with longitude first, got %+v", feature)
+	}
+}
+
+func TestAppliancePerformance(t *testing.T) {
+	out, _ := captureOutput(t)
+	client := newTestClient()
+	client.appliances = map[string][]meraki.AppliancePerformance{
+		"org1": {
+			{Serial: "Q2MX-0001", PerfScore: 85, LossPercent: 0.1, NetworkID: "N_1"},
+			{Serial: "Q2MX-0002", PerfScore: 12, LossPercent: 0, NetworkID: "N_2"},
+		},
+		"org2": {
+			{Serial: "Q2MX-0003", PerfScore: 5, LossPercent: 30.5, LatencyMs: 120.25, NetworkID: "N_3"},
+		},
+	}
+
+	cfg := &config.Config{Command: "perf", InfoAll: true, OutputType: "json", Timespan: time.Hour, Threshold: 25}
+	if err := AppliancePerformance(client, cfg); err != nil {
+		t.Fatalf("AppliancePerformance failed: %v", err)
+	}
+	var appliances []map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &appliances); err != nil {
+		t.Fatalf("Failed to parse stdout as JSON: %v", err)
+	}
+	if len(appliances) != 2 || appliances[0]["serial"] != "Q2MX-0001" || appliances[1]["serial"] != "Q2MX-0003" {
+		t.Fatalf("Expected the busy and the lossy appliance, got %s", out.String())
+	}
+	if appliances[1]["lossPercent"] != 30.5 || appliances[1]["latencyMs"] != 120.25 || appliances[1]["organization"] != "Org Two" {
+		t.Errorf("Expected numeric loss and latency with the organization, got %+v", appliances[1])
+	}
+	if client.called("GetAppliancePerformance org1 1h0m0s") != 1 {
+		t.Errorf("Expected the -timespan to be passed on, got calls %v", client.calls)
+	}
+}
+
+func TestDevice(t *testing.T) {
+	out, _ := captureOutput(t)
+	client := newTestClient()
+
+	cfg := &config.Config{Command: "device", SerialFilter: "Q2AA-0003", OutputType: "json"}
+	if err := Device(client, cfg); err != nil {
+		t.Fatalf("Device failed: %v", err)
+	}
+	var devices []meraki.DeviceDetails
+	if err := json.Unmarshal(out.Bytes(), &devices); err != nil {
+		t.Fatalf("Failed to parse stdout as JSON: %v", err)
+	}
+	if len(devices) != 1 || devices[0].Serial != "Q2AA-0003" || devices[0].NetworkID != "N_3" {
+		t.Errorf("Expected a single record for the device, got %+v", devices)
+	}
+	if client.called("GetOrganizations") != 0 || client.called("GetOrganizationNetworks") != 0 {
+		t.Errorf("Expected the device to be looked up without a network scan, got calls %v", client.calls)
+	}
+
+	cfg.SerialFilter = "Q2ZZ-9999"
+	if err := Device(client, cfg); !errors.Is(err, ErrDeviceNotFound) {
+		t.Errorf("Expected ErrDeviceNotFound for an unknown serial, got: %v", err)
+	}
+}
+
+func TestListedNetworks(t *testing.T) {
+	out, errOut := captureOutput(t)
+	client := newTestClient()
+	cfg := &config.Config{
+		Command:          "route-tables",
+		Organization:     "org1",
+		OrganizationName: "Org One",
+		OutputType:       "json",
+		NetworksFile:     "critical.txt",
+		NetworkList:      []string{"branch 2", "Closed Store", "N_2", "N_1"},
+	}
+
+	count, err := ListedNetworks(client, cfg)
+	if !errors.Is(err, ErrIncompleteRun) {
+		t.Fatalf("Expected the unresolved entry to make the run incomplete, got %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected routes of the 2 listed networks, got %d", count)
+	}
+	if client.called("GetRoutes") != 2 {
+		t.Errorf("Expected each listed network to be fetched once, got calls %v", client.calls)
+	}
+
+	var routes []meraki.RouteWithNetwork
+	if err := json.Unmarshal(out.Bytes(), &routes); err != nil {
+		t.Fatalf("Failed to parse stdout as JSON: %v\n%s", err, out.String())
+	}
+	if len(routes) != 2 || routes[0].NetworkName != "Branch 2" || routes[1].NetworkName != "Branch 1" {
+		t.Errorf("Expected the listed networks' routes in file order, got %+v", routes)
+	}
+	if !strings.Contains(errOut.String(), "1 network(s) listed in critical.txt could not be resolved") || !strings.Contains(errOut.String(), "  - Closed Store\n") {
+		t.Errorf("Expected the unresolved entry to be reported, got %q", errOut.String())
+	}
+}
+
+func TestMatchedNetworks_Licenses(t *testing.T) {
+	out, _ := captureOutput(t)
+	client := newTestClient()
+	client.licenses = map[string][]meraki.License{
+		"org1": {
+			{ID: "L1", State: "active", NetworkID: "N_1"},
+			{ID: "L2", State: "active", NetworkID: "N_2"},
+			{ID: "L3", State: "unusedActive"},
+		},
+	}
+
+	cfg := &config.Config{Command: "licenses", Organization: "org1", Network: "*2", OutputType: "json", DaysUntilExpiry: -1}
+	count, err := MatchedNetworks(client, cfg)
+	if err != nil {
+		t.Fatalf("MatchedNetworks failed: %v", err)
+	}
+
+	var licenses []meraki.License
+	if err := json.Unmarshal(out.Bytes(), &licenses); err != nil {
+		t.Fatalf("Failed to parse stdout as JSON: %v\n%s", err, out.String())
+	}
+	if count != 1 || len(licenses) != 1 || licenses[0].ID != "L2" {
+		t.Errorf("Expected only the license of the matching network, got %d: %+v", count, licenses)
+	}
+}
+
+func TestAllNetworkDownDevices_ExcludeNetworks(t *testing.T) {
+	out, _ := captureOutput(t)
+	client := newTestClient()
+
+	cfg := &config.Config{Command: "down", InfoAll: true, OutputType: "json", ExcludeNetworks: []string{"branch 2", "N_3", "Lab"}}
+	count, err := AllNetworkDownDevices(client, cfg)
+	if err != nil {
+		t.Fatalf("AllNetworkDownDevices failed: %v", err)
+	}
+
+	var devices []meraki.DeviceWithNetwork
+	if err := json.Unmarshal(out.Bytes(), &devices); err != nil {
+		t.Fatalf("Failed to parse JSON: %v\n%s", err, out.String())
+	}
+	if count != 2 || len(devices) != 2 || devices[0].NetworkID != "N_1" || devices[1].NetworkID != "N_4" {
+		t.Errorf("Expected devices of the networks that are not excluded, got %+v", devices)
+	}
+	if client.called("GetDownDevices N_2") != 0 || client.called("GetDownDevices N_3") != 0 {
+		t.Errorf("Expected no API calls for excluded networks, got calls %v", client.calls)
+	}
+}
+
+func TestAllNetworkDownDevices_LongestDownFirst(t *testing.T) {
+	out, _ := captureOutput(t)
+	client := newTestClient()
+	client.devices["N_1"][0].LastReportedAt = "2025-07-17T11:50:00Z"
+	client.devices["N_3"][0].LastReportedAt = "2025-07-16T09:55:00Z"
+
+	cfg := &config.Config{Command: "down", InfoAll: true, OutputType: "json"}
+	if _, err := AllNetworkDownDevices(client, cfg); err != nil {
+		t.Fatalf("AllNetworkDownDevices failed: %v", err)
+	}
+
+	var devices []meraki.DeviceWithNetwork
+	if err := json.Unmarshal(out.Bytes(), &devices); err != nil {
+		t.Fatalf("Failed to parse JSON: %v\n%s", err, out.String())
+	}
+	var order []string
+	for _, device := range devices {
+		order = append(order, device.NetworkID)
+	}
+	if got := strings.Join(order, ","); got != "N_3,N_1,N_2,N_4" {
+		t.Errorf("Expected the longest outage first and unknown durations last, got %s", got)
+	}
+}
@@ -0,0 +1,690 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"meraki-info/internal/config"
+	"meraki-info/internal/meraki"
+	"meraki-info/internal/output"
+)
+
+// SingleNetworkDownDevices collects info for down devices for a single network
+func SingleNetworkDownDevices(client Client, cfg *config.Config) (int, error) {
+	// Fetch down devices for single network
+	downDevices, err := client.GetDownDevices(cfg.Organization, cfg.Network, cfg.DownStatuses)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch down devices: %w", err)
+	}
+	downDevices = meraki.FilterDevicesBySerial(downDevices, cfg.SerialFilter)
+	downDevices = meraki.FilterDevicesByRegex(downDevices, cfg.FilterRegex, "")
+
+	slog.Info("Retrieved down devices", "count", len(downDevices))
+
+	// Determine output filename
+	outputFile := cfg.OutputFile
+	if outputFile == "" || outputFile == "-" {
+		// Send to stdout when not provided or explicitly set to "-"
+		outputWriter := newOutputWriter(cfg)
+		if err := outputWriter.WriteTo(downDevices, stdout); err != nil {
+			return 0, fmt.Errorf("failed to write output to stdout: %w", err)
+		}
+		slog.Info("Down devices sent to stdout", "device_count", len(downDevices))
+		return len(downDevices), nil
+	}
+
+	// Output to file
+	outputWriter := newOutputWriter(cfg)
+	if err := outputWriter.WriteToFile(downDevices, outputFile); err != nil {
+		return 0, fmt.Errorf("failed to write output: %w", err)
+	}
+	slog.Info("Down devices info collection completed successfully", "output_file", outputFile)
+
+	return len(downDevices), nil
+}
+
+// AllNetworkDownDevices collects info for down devices for all networks in the organization(s)
+func AllNetworkDownDevices(client Client, cfg *config.Config) (int, error) {
+	// Check if output should go to stdout or a URL (consolidated format)
+	if cfg.OutputFile == "" || cfg.OutputFile == "-" || output.IsURL(cfg.OutputFile) {
+		return infoAllNetworkDownDevicesConsolidated(client, cfg)
+	}
+
+	// Otherwise use separate files for each network
+	if cfg.Organization != "" {
+		// Get info for all networks in a specific organization
+		stats := output.OrganizationRunStats{Organization: cfg.OrganizationName, OrganizationID: cfg.Organization}
+		count, err := infoOrganizationNetworkDownDevices(cfg, client, cfg.Organization, &stats)
+		if err != nil {
+			return count, err
+		}
+		var run output.RunSummary
+		run.AddOrganization(stats)
+		return count, incompleteRunError(run, stderr)
+	} else {
+		// Get info for all networks in all organizations
+		return infoAllOrganizationDownDevices(cfg, client)
+	}
+}
+
+// infoAllNetworkDownDevicesConsolidated collects down device info for all networks and outputs in a consolidated format to stdout
+func infoAllNetworkDownDevicesConsolidated(client Client, cfg *config.Config) (int, error) {
+	// Get all organizations
+	orgs, err := selectedOrganizations(client, cfg)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get organizations: %w", err)
+	}
+
+	var allDownDevices []meraki.DeviceWithNetwork
+
+	var run output.RunSummary
+	for _, org := range orgs {
+		stats := output.OrganizationRunStats{Organization: org.Name, OrganizationID: org.ID}
+		callsBefore, itemsBefore := client.RequestCount(), len(allDownDevices)
+
+		// Get all networks in the organization
+		networks, err := organizationNetworks(client, cfg, org.ID)
+		if err != nil {
+			if cfg.Strict {
+				return 0, fmt.Errorf("failed to get networks for organization %s: %w", org.Name, err)
+			}
+			stats = organizationFailed(org, err)
+			stats.APICalls = client.RequestCount() - callsBefore
+			run.AddOrganization(stats)
+			continue
+		}
+
+		for _, network := range networks {
+			stats.NetworksScanned++
+
+			// Get down devices for this network
+			downDevices, err := client.GetDownDevices(org.ID, network.ID, cfg.DownStatuses)
+			if err != nil {
+				if cfg.Strict {
+					return 0, fmt.Errorf("failed to get down devices for network %s: %w", network.Name, err)
+				}
+				slog.Error("Failed to get down devices for network", "networkID", network.ID, "networkName", network.Name, "error", err)
+				stats.AddFailedNetwork(network.Name, network.ID, err.Error())
+				continue
+			}
+			downDevices = meraki.FilterDevicesBySerial(downDevices, cfg.SerialFilter)
+			downDevices = meraki.FilterDevicesByRegex(downDevices, cfg.FilterRegex, network.Name)
+
+			// Add network and organization information to each device
+			for _, device := range downDevices {
+				deviceWithNetwork := meraki.DeviceWithNetwork{
+					Device:         device,
+					NetworkName:    network.Name,
+					NetworkID:      network.ID,
+					Organization:   org.Name,
+					OrganizationID: org.ID,
+				}
+				allDownDevices = append(allDownDevices, deviceWithNetwork)
+			}
+			if serialFound(cfg, len(allDownDevices)) {
+				break
+			}
+		}
+
+		stats.Items = len(allDownDevices) - itemsBefore
+		stats.APICalls = client.RequestCount() - callsBefore
+		run.AddOrganization(stats)
+		if serialFound(cfg, len(allDownDevices)) {
+			slog.Info("Found device serial, skipping remaining networks", "serial", cfg.SerialFilter)
+			break
+		}
+	}
+
+	slog.Info("Collected all down devices", "totalDevices", len(allDownDevices))
+
+	// Report the longest outages first across all networks; -sort reorders this when given
+	sort.SliceStable(allDownDevices, func(i, j int) bool {
+		return meraki.DownLonger(allDownDevices[i].Device, allDownDevices[j].Device)
+	})
+
+	// Output to stdout or file
+	writer := newOutputWriter(cfg)
+	if cfg.OutputFile == "" || cfg.OutputFile == "-" {
+		if err := writer.WriteTo(allDownDevices, stdout); err != nil {
+			return 0, fmt.Errorf("failed to write output to stdout: %w", err)
+		}
+		slog.Info("Down devices info sent to stdout", "total_devices", len(allDownDevices))
+	} else {
+		if err := writer.WriteToFile(allDownDevices, cfg.OutputFile); err != nil {
+			return 0, fmt.Errorf("failed to write output to file: %w", err)
+		}
+		slog.Info("Down devices info written to file", "total_devices", len(allDownDevices), "file", cfg.OutputFile)
+	}
+
+	return len(allDownDevices), finishRun(cfg, run)
+}
+
+// infoOrganizationNetworkDownDevices collects info for down devices for all networks in an
+// organization, recording the networks scanned and those that failed in stats
+func infoOrganizationNetworkDownDevices(cfg *config.Config, client Client, organizationID string, stats *output.OrganizationRunStats) (int, error) {
+	networks, err := organizationNetworks(client, cfg, organizationID)
+	if err != nil {
+		return 0, fmt.Errorf("error getting organization networks: %w", err)
+	}
+
+	total := 0
+	for _, network := range networks {
+		stats.NetworksScanned++
+
+		// Create a copy of config for this network
+		networkCfg := *cfg
+		networkCfg.Organization = organizationID
+		networkCfg.Network = network.ID
+
+		// Only proceed if a specific output file is provided
+		if networkCfg.OutputFile == "" {
+			return total, fmt.Errorf("no output file specified for separate file generation")
+		}
+
+		count, err := SingleNetworkDownDevices(client, &networkCfg)
+		if err != nil {
+			if cfg.Strict {
+				return total, fmt.Errorf("failed to collect down device info for network %s: %w", network.Name, err)
+			}
+			slog.Error("Failed to collect down device info for network", "network", network.Name, "error", err)
+			stats.AddFailedNetwork(network.Name, network.ID, err.Error())
+			continue
+		}
+		total += count
+		if serialFound(cfg, total) {
+			break
+		}
+	}
+
+	return total, nil
+}
+
+// infoAllOrganizationDownDevices collects info for down devices for all organizations
+func infoAllOrganizationDownDevices(cfg *config.Config, client Client) (int, error) {
+	organizations, err := selectedOrganizations(client, cfg)
+	if err != nil {
+		return 0, fmt.Errorf("error getting organizations: %w", err)
+	}
+
+	total := 0
+	var run output.RunSummary
+	for _, org := range organizations {
+		slog.Info("Processing organization for down devices", "org", org.Name, "id", org.ID)
+		stats := output.OrganizationRunStats{Organization: org.Name, OrganizationID: org.ID}
+		callsBefore := client.RequestCount()
+		count, err := infoOrganizationNetworkDownDevices(cfg, client, org.ID, &stats)
+		if err != nil {
+			if cfg.Strict {
+				return total, err
+			}
+			stats = organizationFailed(org, err)
+			stats.APICalls = client.RequestCount() - callsBefore
+			run.AddOrganization(stats)
+			continue
+		}
+		total += count
+		stats.Items = count
+		stats.APICalls = client.RequestCount() - callsBefore
+		run.AddOrganization(stats)
+		if serialFound(cfg, total) {
+			slog.Info("Found device serial, skipping remaining organizations", "serial", cfg.SerialFilter)
+			break
+		}
+	}
+
+	return total, incompleteRunError(run, stderr)
+}
+
+// SingleNetworkAlertingDevices retrieves and outputs alerting device information for a single network
+func SingleNetworkAlertingDevices(client Client, cfg *config.Config) (int, error) {
+	// Fetch alerting devices for single network
+	alertingDevices, err := client.GetAlertingDevices(cfg.Organization, cfg.Network)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch alerting devices: %w", err)
+	}
+	if cfg.HealthAlerts {
+		networkID, err := client.ResolveNetworkID(cfg.Organization, cfg.Network)
+		if err != nil {
+			return 0, err
+		}
+		if alertingDevices, err = withHealthAlerts(client, networkID, alertingDevices); err != nil {
+			return 0, err
+		}
+	}
+	alertingDevices = meraki.FilterDevicesBySerial(alertingDevices, cfg.SerialFilter)
+	alertingDevices = meraki.FilterDevicesByRegex(alertingDevices, cfg.FilterRegex, "")
+
+	slog.Info("Retrieved alerting devices", "count", len(alertingDevices))
+
+	// Determine output filename
+	outputFile := cfg.OutputFile
+	if outputFile == "" || outputFile == "-" {
+		// Send to stdout when not provided or explicitly set to "-"
+		outputWriter := newOutputWriter(cfg)
+		if err := outputWriter.WriteTo(alertingDevices, stdout); err != nil {
+			return 0, fmt.Errorf("failed to write output to stdout: %w", err)
+		}
+		slog.Info("Alerting devices sent to stdout", "device_count", len(alertingDevices))
+		return len(alertingDevices), nil
+	}
+
+	// Output to file
+	outputWriter := newOutputWriter(cfg)
+	if err := outputWriter.WriteToFile(alertingDevices, outputFile); err != nil {
+		return 0, fmt.Errorf("failed to write output: %w", err)
+	}
+	slog.Info("Alerting devices info collection completed successfully", "output_file", outputFile)
+
+	return len(alertingDevices), nil
+}
+
+// withHealthAlerts merges the health alerts of a network into its alerting devices, for -health-alerts
+func withHealthAlerts(client Client, networkID string, devices []meraki.Device) ([]meraki.Device, error) {
+	alerts, err := client.GetNetworkHealthAlerts(networkID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch health alerts: %w", err)
+	}
+	return meraki.MergeHealthAlerts(devices, alerts, networkID), nil
+}
+
+// AllNetworkAlertingDevices collects info for alerting devices for all networks in the organization(s) to separate files
+func AllNetworkAlertingDevices(client Client, cfg *config.Config) (int, error) {
+	// Check if output should go to stdout or a URL (consolidated format)
+	if cfg.OutputFile == "" || cfg.OutputFile == "-" || output.IsURL(cfg.OutputFile) {
+		return infoAllNetworkAlertingDevicesConsolidated(client, cfg)
+	}
+
+	// Otherwise use separate files for each network
+	if cfg.Organization != "" {
+		// Get info for all networks in a specific organization
+		stats := output.OrganizationRunStats{Organization: cfg.OrganizationName, OrganizationID: cfg.Organization}
+		count, err := infoOrganizationNetworkAlertingDevices(cfg, client, cfg.Organization, &stats)
+		if err != nil {
+			return count, err
+		}
+		var run output.RunSummary
+		run.AddOrganization(stats)
+		return count, incompleteRunError(run, stderr)
+	} else {
+		// Get info for all networks in all organizations
+		return infoAllOrganizationAlertingDevices(cfg, client)
+	}
+}
+
+// infoAllNetworkAlertingDevicesConsolidated collects alerting device info for all networks and outputs in a consolidated format to stdout
+func infoAllNetworkAlertingDevicesConsolidated(client Client, cfg *config.Config) (int, error) {
+	// Get all organizations
+	orgs, err := selectedOrganizations(client, cfg)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get organizations: %w", err)
+	}
+
+	var allAlertingDevices []meraki.DeviceWithNetwork
+
+	var run output.RunSummary
+	for _, org := range orgs {
+		stats := output.OrganizationRunStats{Organization: org.Name, OrganizationID: org.ID}
+		callsBefore, itemsBefore := client.RequestCount(), len(allAlertingDevices)
+
+		// Get all networks in the organization
+		networks, err := organizationNetworks(client, cfg, org.ID)
+		if err != nil {
+			if cfg.Strict {
+				return 0, fmt.Errorf("failed to get networks for organization %s: %w", org.Name, err)
+			}
+			stats = organizationFailed(org, err)
+			stats.APICalls = client.RequestCount() - callsBefore
+			run.AddOrganization(stats)
+			continue
+		}
+
+		for _, network := range networks {
+			stats.NetworksScanned++
+
+			// Get alerting devices for this network
+			alertingDevices, err := client.GetAlertingDevices(org.ID, network.ID)
+			if err != nil {
+				if cfg.Strict {
+					return 0, fmt.Errorf("failed to get alerting devices for network %s: %w", network.Name, err)
+				}
+				slog.Error("Failed to get alerting devices for network", "networkID", network.ID, "networkName", network.Name, "error", err)
+				stats.AddFailedNetwork(network.Name, network.ID, err.Error())
+				continue
+			}
+			if cfg.HealthAlerts {
+				if alertingDevices, err = withHealthAlerts(client, network.ID, alertingDevices); err != nil {
+					if cfg.Strict {
+						return 0, fmt.Errorf("failed to get health alerts for network %s: %w", network.Name, err)
+					}
+					slog.Error("Failed to get health alerts for network", "networkID", network.ID, "networkName", network.Name, "error", err)
+					stats.AddFailedNetwork(network.Name, network.ID, err.Error())
+					continue
+				}
+			}
+			alertingDevices = meraki.FilterDevicesBySerial(alertingDevices, cfg.SerialFilter)
+			alertingDevices = meraki.FilterDevicesByRegex(alertingDevices, cfg.FilterRegex, network.Name)
+
+			// Add network and organization information to each device
+			for _, device := range alertingDevices {
+				deviceWithNetwork := meraki.DeviceWithNetwork{
+					Device:         device,
+					NetworkName:    network.Name,
+					NetworkID:      network.ID,
+					Organization:   org.Name,
+					OrganizationID: org.ID,
+				}
+				allAlertingDevices = append(allAlertingDevices, deviceWithNetwork)
+			}
+			if serialFound(cfg, len(allAlertingDevices)) {
+				break
+			}
+		}
+
+		stats.Items = len(allAlertingDevices) - itemsBefore
+		stats.APICalls = client.RequestCount() - callsBefore
+		run.AddOrganization(stats)
+		if serialFound(cfg, len(allAlertingDevices)) {
+			slog.Info("Found device serial, skipping remaining networks", "serial", cfg.SerialFilter)
+			break
+		}
+	}
+
+	slog.Info("Collected all alerting devices", "totalDevices", len(allAlertingDevices))
+
+	// Output to stdout or file
+	writer := newOutputWriter(cfg)
+	if cfg.OutputFile == "" || cfg.OutputFile == "-" {
+		if err := writer.WriteTo(allAlertingDevices, stdout); err != nil {
+			return 0, fmt.Errorf("failed to write output to stdout: %w", err)
+		}
+		slog.Info("Alerting devices info sent to stdout", "total_devices", len(allAlertingDevices))
+	} else {
+		if err := writer.WriteToFile(allAlertingDevices, cfg.OutputFile); err != nil {
+			return 0, fmt.Errorf("failed to write output to file: %w", err)
+		}
+		slog.Info("Alerting devices info written to file", "total_devices", len(allAlertingDevices), "file", cfg.OutputFile)
+	}
+
+	return len(allAlertingDevices), finishRun(cfg, run)
+}
+
+// infoOrganizationNetworkAlertingDevices collects info for alerting devices for all networks in an
+// organization, recording the networks scanned and those that failed in stats
+func infoOrganizationNetworkAlertingDevices(cfg *config.Config, client Client, organizationID string, stats *output.OrganizationRunStats) (int, error) {
+	networks, err := organizationNetworks(client, cfg, organizationID)
+	if err != nil {
+		return 0, fmt.Errorf("error getting organization networks: %w", err)
+	}
+
+	total := 0
+	for _, network := range networks {
+		stats.NetworksScanned++
+
+		// Create a copy of config for this network
+		networkCfg := *cfg
+		networkCfg.Organization = organizationID
+		networkCfg.Network = network.ID
+
+		// Only proceed if a specific output file is provided
+		if networkCfg.OutputFile == "" {
+			return total, fmt.Errorf("no output file specified for separate file generation")
+		}
+
+		count, err := SingleNetworkAlertingDevices(client, &networkCfg)
+		if err != nil {
+			if cfg.Strict {
+				return total, fmt.Errorf("failed to collect alerting device info for network %s: %w", network.Name, err)
+			}
+			slog.Error("Failed to collect alerting device info for network", "network", network.Name, "error", err)
+			stats.AddFailedNetwork(network.Name, network.ID, err.Error())
+			continue
+		}
+		total += count
+		if serialFound(cfg, total) {
+			break
+		}
+	}
+
+	return total, nil
+}
+
+// infoAllOrganizationAlertingDevices collects info for alerting devices for all organizations
+func infoAllOrganizationAlertingDevices(cfg *config.Config, client Client) (int, error) {
+	organizations, err := selectedOrganizations(client, cfg)
+	if err != nil {
+		return 0, fmt.Errorf("error getting organizations: %w", err)
+	}
+
+	total := 0
+	var run output.RunSummary
+	for _, org := range organizations {
+		slog.Info("Processing organization for alerting devices", "org", org.Name, "id", org.ID)
+		stats := output.OrganizationRunStats{Organization: org.Name, OrganizationID: org.ID}
+		callsBefore := client.RequestCount()
+		count, err := infoOrganizationNetworkAlertingDevices(cfg, client, org.ID, &stats)
+		if err != nil {
+			if cfg.Strict {
+				return total, err
+			}
+			stats = organizationFailed(org, err)
+			stats.APICalls = client.RequestCount() - callsBefore
+			run.AddOrganization(stats)
+			continue
+		}
+		total += count
+		stats.Items = count
+		stats.APICalls = client.RequestCount() - callsBefore
+		run.AddOrganization(stats)
+		if serialFound(cfg, total) {
+			slog.Info("Found device serial, skipping remaining organizations", "serial", cfg.SerialFilter)
+			break
+		}
+	}
+
+	return total, incompleteRunError(run, stderr)
+}
+
+// DeviceStatusSummary collects device status counts for one organization, or all organizations with -all
+func DeviceStatusSummary(client Client, cfg *config.Config) error {
+	orgs, err := selectedOrganizations(client, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to get organizations: %w", err)
+	}
+
+	var run output.RunSummary
+	allSummaries := make([]meraki.DeviceStatusSummary, 0)
+	for _, org := range orgs {
+		if cfg.Organization != "" && org.ID != cfg.Organization {
+			continue
+		}
+
+		summaries, err := client.GetDeviceStatusSummary(org.ID, cfg.Network)
+		if err != nil {
+			if cfg.Organization != "" || cfg.Strict {
+				return fmt.Errorf("failed to get device status summary: %w", err)
+			}
+			run.AddOrganization(organizationFailed(org, err))
+			continue
+		}
+
+		// Add organization information to each summary record
+		for _, summary := range summaries {
+			summary.Organization = org.Name
+			summary.OrganizationID = org.ID
+			allSummaries = append(allSummaries, summary)
+		}
+		run.AddOrganization(output.OrganizationRunStats{Organization: org.Name, OrganizationID: org.ID, Items: len(summaries)})
+	}
+
+	allSummaries = append(allSummaries, meraki.TotalDeviceStatusSummary(allSummaries))
+
+	// Output to stdout or file
+	writer := newOutputWriter(cfg)
+	if cfg.OutputFile == "" || cfg.OutputFile == "-" {
+		if err := writer.WriteTo(allSummaries, stdout); err != nil {
+			return fmt.Errorf("failed to write output to stdout: %w", err)
+		}
+		slog.Info("Device status summary sent to stdout", "records", len(allSummaries))
+	} else {
+		if err := writer.WriteToFile(allSummaries, cfg.OutputFile); err != nil {
+			return fmt.Errorf("failed to write output to file: %w", err)
+		}
+		slog.Info("Device status summary written to file", "records", len(allSummaries), "file", cfg.OutputFile)
+	}
+
+	return incompleteRunError(run, stderr)
+}
+
+// serialFound reports whether -serial is set and its device has been found. Serials are globally
+// unique, so -all runs stop fetching further networks once found is non-zero.
+func serialFound(cfg *config.Config, found int) bool {
+	return cfg.SerialFilter != "" && found > 0
+}
+
+// ErrDeviceNotFound is returned by the device command when no device with the -serial serial is
+// visible to the API key
+var ErrDeviceNotFound = errors.New("device not found")
+
+// Device outputs the details of the single device selected with -serial. The record is written as
+// a one-element list so every output format works as it does for the other commands.
+func Device(client Client, cfg *config.Config) error {
+	details, err := client.GetDeviceDetails(cfg.SerialFilter)
+	if err != nil {
+		return deviceLookupError(cfg.SerialFilter, err)
+	}
+	devices := []meraki.DeviceDetails{details}
+
+	// Output to stdout or file
+	writer := newOutputWriter(cfg)
+	if cfg.OutputFile == "" || cfg.OutputFile == "-" {
+		if err := writer.WriteTo(devices, stdout); err != nil {
+			return fmt.Errorf("failed to write output to stdout: %w", err)
+		}
+		slog.Info("Device details sent to stdout", "serial", details.Serial)
+	} else {
+		if err := writer.WriteToFile(devices, cfg.OutputFile); err != nil {
+			return fmt.Errorf("failed to write output to file: %w", err)
+		}
+		slog.Info("Device details written to file", "serial", details.Serial, "file", cfg.OutputFile)
+	}
+
+	return nil
+}
+
+// deviceLookupError describes a failure to look up the device with serial, telling an unknown
+// serial (ErrDeviceNotFound) apart from an organization the API key cannot read
+func deviceLookupError(serial string, err error) error {
+	switch {
+	case errors.Is(err, meraki.ErrNotFound):
+		return fmt.Errorf("%w: no device with serial %s in any organization the API key can see", ErrDeviceNotFound, serial)
+	case errors.Is(err, meraki.ErrForbidden):
+		return fmt.Errorf("access denied to device %s, the API key cannot read its organization: %w", serial, err)
+	}
+	return fmt.Errorf("failed to get device details: %w", err)
+}
+
+// Locations lists the devices placed on the map, grouped by network, for one organization, all
+// organizations with -all, or a single network with -network
+func Locations(client Client, cfg *config.Config) error {
+	orgs, err := selectedOrganizations(client, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to get organizations: %w", err)
+	}
+
+	var run output.RunSummary
+	allLocations := make([]meraki.DeviceLocation, 0)
+	for _, org := range orgs {
+		if cfg.Organization != "" && org.ID != cfg.Organization {
+			continue
+		}
+
+		locations, err := client.GetDeviceLocations(org.ID, cfg.Network)
+		if err != nil {
+			if cfg.Organization != "" || cfg.Strict {
+				return fmt.Errorf("failed to get device locations: %w", err)
+			}
+			run.AddOrganization(organizationFailed(org, err))
+			continue
+		}
+
+		// Add organization information to each location record
+		for _, location := range locations {
+			location.Organization = org.Name
+			location.OrganizationID = org.ID
+			allLocations = append(allLocations, location)
+		}
+		run.AddOrganization(output.OrganizationRunStats{Organization: org.Name, OrganizationID: org.ID, Items: len(locations)})
+	}
+
+	// Output to stdout or file
+	writer := newOutputWriter(cfg)
+	if cfg.OutputFile == "" || cfg.OutputFile == "-" {
+		if err := writer.WriteTo(allLocations, stdout); err != nil {
+			return fmt.Errorf("failed to write output to stdout: %w", err)
+		}
+		slog.Info("Device locations sent to stdout", "device_count", len(allLocations))
+	} else {
+		if err := writer.WriteToFile(allLocations, cfg.OutputFile); err != nil {
+			return fmt.Errorf("failed to write output to file: %w", err)
+		}
+		slog.Info("Device locations written to file", "device_count", len(allLocations), "file", cfg.OutputFile)
+	}
+
+	return incompleteRunError(run, stderr)
+}
+
+// AppliancePerformance reports the performance score and uplink loss and latency of the MX
+// appliances of one organization, all organizations with -all, or a single network with -network.
+// With -threshold only appliances running hot or lossy are output.
+func AppliancePerformance(client Client, cfg *config.Config) error {
+	orgs, err := selectedOrganizations(client, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to get organizations: %w", err)
+	}
+
+	var run output.RunSummary
+	allAppliances := make([]meraki.AppliancePerformance, 0)
+	for _, org := range orgs {
+		if cfg.Organization != "" && org.ID != cfg.Organization {
+			continue
+		}
+
+		appliances, err := client.GetAppliancePerformance(org.ID, cfg.Network, cfg.Timespan)
+		if err != nil {
+			if cfg.Organization != "" || cfg.Strict {
+				return fmt.Errorf("failed to get appliance performance: %w", err)
+			}
+			run.AddOrganization(organizationFailed(org, err))
+			continue
+		}
+		if cfg.Threshold >= 0 {
+			appliances = meraki.FilterAppliancePerformance(appliances, cfg.Threshold)
+		}
+
+		// Add organization information to each appliance record
+		for _, appliance := range appliances {
+			appliance.Organization = org.Name
+			appliance.OrganizationID = org.ID
+			allAppliances = append(allAppliances, appliance)
+		}
+		run.AddOrganization(output.OrganizationRunStats{Organization: org.Name, OrganizationID: org.ID, Items: len(appliances)})
+	}
+
+	// Output to stdout or file
+	writer := newOutputWriter(cfg)
+	if cfg.OutputFile == "" || cfg.OutputFile == "-" {
+		if err := writer.WriteTo(allAppliances, stdout); err != nil {
+			return fmt.Errorf("failed to write output to stdout: %w", err)
+		}
+		slog.Info("Appliance performance sent to stdout", "appliance_count", len(allAppliances))
+	} else {
+		if err := writer.WriteToFile(allAppliances, cfg.OutputFile); err != nil {
+			return fmt.Errorf("failed to write output to file: %w", err)
+		}
+		slog.Info("Appliance performance written to file", "appliance_count", len(allAppliances), "file", cfg.OutputFile)
+	}
+
+	return incompleteRunError(run, stderr)
+}
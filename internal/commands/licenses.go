@@ -0,0 +1,238 @@
+package commands
+
+import (
+	"fmt"
+	"log/slog"
+
+	"meraki-info/internal/config"
+	"meraki-info/internal/meraki"
+	"meraki-info/internal/output"
+)
+
+// SingleNetworkLicenses collects info for licenses for a single network/organization
+func SingleNetworkLicenses(client Client, cfg *config.Config) error {
+	// Fetch licenses for the organization
+	licenses, err := client.GetLicenses(cfg.Organization)
+	if err != nil {
+		return fmt.Errorf("failed to fetch licenses: %w", err)
+	}
+
+	licenses = meraki.FilterLicensesByState(licenses, cfg.LicenseStates)
+	if licenses, err = filterLicensesByExpiry(licenses, cfg); err != nil {
+		return err
+	}
+	licenses = resolveLicenseNetworks(client, cfg.Organization, licenses)
+
+	slog.Info("Retrieved licenses", "count", len(licenses))
+
+	var data interface{} = licenses
+	if cfg.MergeLicenses {
+		organizationLicenses := make([]meraki.LicenseWithNetwork, len(licenses))
+		for i, license := range licenses {
+			organizationLicenses[i] = meraki.LicenseWithNetwork{License: license, OrganizationID: cfg.Organization}
+		}
+		if data, err = meraki.MergeOrganizationLicenses(organizationLicenses); err != nil {
+			return err
+		}
+	}
+
+	// Determine output filename
+	outputFile := cfg.OutputFile
+	if outputFile == "" || outputFile == "-" {
+		// Send to stdout when not provided or explicitly set to "-"
+		outputWriter := newOutputWriter(cfg)
+		if err := outputWriter.WriteTo(data, stdout); err != nil {
+			return fmt.Errorf("failed to write output to stdout: %w", err)
+		}
+		slog.Info("Licenses sent to stdout", "license_count", len(licenses))
+		return nil
+	}
+
+	// Output to file
+	outputWriter := newOutputWriter(cfg)
+	if err := outputWriter.WriteToFile(data, outputFile); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	slog.Info("Licenses info collection completed successfully", "output_file", outputFile)
+
+	return nil
+}
+
+// AllNetworkLicenses collects info for licenses for all networks in the organization(s)
+func AllNetworkLicenses(client Client, cfg *config.Config) error {
+	// Check if output should go to stdout or a URL (consolidated format). Merged licenses summarize
+	// whole organizations, so they are never split into a file per network.
+	if cfg.OutputFile == "" || cfg.OutputFile == "-" || output.IsURL(cfg.OutputFile) || cfg.MergeLicenses {
+		return infoAllNetworkLicensesConsolidated(client, cfg)
+	}
+
+	// Otherwise use separate files for each network
+	if cfg.Organization != "" {
+		// Get info for all networks in a specific organization
+		stats := output.OrganizationRunStats{Organization: cfg.OrganizationName, OrganizationID: cfg.Organization}
+		if err := infoOrganizationNetworkLicenses(cfg, client, cfg.Organization, &stats); err != nil {
+			return err
+		}
+		var run output.RunSummary
+		run.AddOrganization(stats)
+		return incompleteRunError(run, stderr)
+	} else {
+		// Get info for all networks in all organizations
+		return infoAllOrganizationLicenses(cfg, client)
+	}
+}
+
+// infoAllNetworkLicensesConsolidated collects license info for all networks and outputs in a consolidated format to stdout
+func infoAllNetworkLicensesConsolidated(client Client, cfg *config.Config) error {
+	// Get all organizations
+	orgs, err := selectedOrganizations(client, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to get organizations: %w", err)
+	}
+
+	var allLicenses []meraki.LicenseWithNetwork
+	var run output.RunSummary
+
+	for _, org := range orgs {
+		// Get licenses for this organization
+		licenses, err := client.GetLicenses(org.ID)
+		if err != nil {
+			if cfg.Strict {
+				return fmt.Errorf("failed to get licenses for organization %s: %w", org.Name, err)
+			}
+			run.AddOrganization(organizationFailed(org, err))
+			continue
+		}
+		licenses = meraki.FilterLicensesByState(licenses, cfg.LicenseStates)
+		if licenses, err = filterLicensesByExpiry(licenses, cfg); err != nil {
+			return err
+		}
+		licenses = resolveLicenseNetworks(client, org.ID, licenses)
+
+		// Add organization information to each license
+		for _, license := range licenses {
+			licenseWithNetwork := meraki.LicenseWithNetwork{
+				License:        license,
+				Organization:   org.Name,
+				OrganizationID: org.ID,
+			}
+			allLicenses = append(allLicenses, licenseWithNetwork)
+		}
+		run.AddOrganization(output.OrganizationRunStats{Organization: org.Name, OrganizationID: org.ID, Items: len(licenses)})
+	}
+
+	slog.Info("Collected all licenses", "totalLicenses", len(allLicenses))
+
+	var data interface{} = allLicenses
+	if cfg.MergeLicenses {
+		if data, err = meraki.MergeOrganizationLicenses(allLicenses); err != nil {
+			return err
+		}
+	}
+
+	// Output to stdout or file
+	writer := newOutputWriter(cfg)
+	if cfg.OutputFile == "" || cfg.OutputFile == "-" {
+		if err := writer.WriteTo(data, stdout); err != nil {
+			return fmt.Errorf("failed to write output to stdout: %w", err)
+		}
+		slog.Info("License info sent to stdout", "total_licenses", len(allLicenses))
+	} else {
+		if err := writer.WriteToFile(data, cfg.OutputFile); err != nil {
+			return fmt.Errorf("failed to write output to file: %w", err)
+		}
+		slog.Info("License info written to file", "total_licenses", len(allLicenses), "file", cfg.OutputFile)
+	}
+
+	return incompleteRunError(run, stderr)
+}
+
+// infoOrganizationNetworkLicenses collects info for licenses for all networks in an
+// organization, recording the networks scanned and those that failed in stats
+func infoOrganizationNetworkLicenses(cfg *config.Config, client Client, organizationID string, stats *output.OrganizationRunStats) error {
+	networks, err := organizationNetworks(client, cfg, organizationID)
+	if err != nil {
+		return fmt.Errorf("error getting organization networks: %w", err)
+	}
+
+	for _, network := range networks {
+		stats.NetworksScanned++
+
+		// Create a copy of config for this network
+		networkCfg := *cfg
+		networkCfg.Organization = organizationID
+		networkCfg.Network = network.ID
+
+		// Only proceed if a specific output file is provided
+		if networkCfg.OutputFile == "" {
+			return fmt.Errorf("no output file specified for separate file generation")
+		}
+
+		err := SingleNetworkLicenses(client, &networkCfg)
+		if err != nil {
+			if cfg.Strict {
+				return fmt.Errorf("failed to collect license info for network %s: %w", network.Name, err)
+			}
+			slog.Error("Failed to collect license info for network", "network", network.Name, "error", err)
+			stats.AddFailedNetwork(network.Name, network.ID, err.Error())
+			continue
+		}
+	}
+
+	return nil
+}
+
+// infoAllOrganizationLicenses collects info for licenses for all organizations
+func infoAllOrganizationLicenses(cfg *config.Config, client Client) error {
+	organizations, err := selectedOrganizations(client, cfg)
+	if err != nil {
+		return fmt.Errorf("error getting organizations: %w", err)
+	}
+
+	var run output.RunSummary
+	for _, org := range organizations {
+		slog.Info("Processing organization for licenses", "org", org.Name, "id", org.ID)
+		stats := output.OrganizationRunStats{Organization: org.Name, OrganizationID: org.ID}
+		callsBefore := client.RequestCount()
+		err := infoOrganizationNetworkLicenses(cfg, client, org.ID, &stats)
+		if err != nil {
+			if cfg.Strict {
+				return err
+			}
+			stats = organizationFailed(org, err)
+			stats.APICalls = client.RequestCount() - callsBefore
+			run.AddOrganization(stats)
+			continue
+		}
+		stats.APICalls = client.RequestCount() - callsBefore
+		run.AddOrganization(stats)
+	}
+
+	return incompleteRunError(run, stderr)
+}
+
+// resolveLicenseNetworks fills in the effective network of each license, keeping the licenses as
+// returned by the API when the extra lookups fail
+func resolveLicenseNetworks(client Client, organizationID string, licenses []meraki.License) []meraki.License {
+	resolved, err := client.ResolveLicenseNetworks(organizationID, licenses)
+	if err != nil {
+		slog.Warn("Failed to resolve license networks, using network IDs as returned", "orgID", organizationID, "error", err)
+		return licenses
+	}
+	return resolved
+}
+
+// filterLicensesByExpiry applies -days-until-expiry and -expires-after/-expires-before to the
+// licenses of an organization
+func filterLicensesByExpiry(licenses []meraki.License, cfg *config.Config) ([]meraki.License, error) {
+	var err error
+	if cfg.DaysUntilExpiry >= 0 {
+		if licenses, err = meraki.FilterLicensesByExpiry(licenses, cfg.DaysUntilExpiry); err != nil {
+			return nil, err
+		}
+	}
+	if !cfg.ExpiresAfter.IsZero() || !cfg.ExpiresBefore.IsZero() {
+		return meraki.FilterLicensesByExpirationRange(licenses, cfg.ExpiresAfter, cfg.ExpiresBefore)
+	}
+	return licenses, nil
+}
@@ -0,0 +1,600 @@
+package commands
+
+import (
+	"fmt"
+	"log/slog"
+
+	"meraki-info/internal/config"
+	"meraki-info/internal/meraki"
+	"meraki-info/internal/output"
+)
+
+// MatchedNetworks collects the command's records for every network matching
+// the -network glob pattern and outputs them in the consolidated format
+func MatchedNetworks(client Client, cfg *config.Config) (int, error) {
+	networks, err := client.MatchNetworks(cfg.Organization, cfg.Network)
+	if err != nil {
+		return 0, err
+	}
+	return networksReport(client, cfg, networks)
+}
+
+// ListedNetworks collects the command's records for the networks listed in -networks-file and
+// outputs them in the consolidated format. Entries that do not resolve to a network of the
+// organization are skipped and reported once the other networks have been processed, and make
+// the run incomplete.
+func ListedNetworks(client Client, cfg *config.Config) (int, error) {
+	organizationNetworks, err := client.GetOrganizationNetworks(cfg.Organization)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get networks for organization %s: %w", cfg.Organization, err)
+	}
+	byID := make(map[string]meraki.Network, len(organizationNetworks))
+	for _, network := range organizationNetworks {
+		byID[network.ID] = network
+	}
+
+	var networks []meraki.Network
+	var unresolved []string
+	listed := make(map[string]bool)
+	for _, entry := range cfg.NetworkList {
+		networkID, err := client.ResolveNetworkID(cfg.Organization, entry)
+		if err != nil {
+			slog.Warn("Skipping network from -networks-file", "network", entry, "error", err)
+			unresolved = append(unresolved, entry)
+			continue
+		}
+		if !listed[networkID] {
+			listed[networkID] = true
+			networks = append(networks, byID[networkID])
+		}
+	}
+
+	count, err := networksReport(client, cfg, networks)
+	if err != nil || len(unresolved) == 0 {
+		return count, err
+	}
+
+	fmt.Fprintf(stderr, "Note: %d network(s) listed in %s could not be resolved and were skipped:\n", len(unresolved), cfg.NetworksFile)
+	for _, entry := range unresolved {
+		fmt.Fprintf(stderr, "  - %s\n", entry)
+	}
+	return count, fmt.Errorf("%w: %d network(s) could not be resolved", ErrIncompleteRun, len(unresolved))
+}
+
+// networksReport collects the command's records for each of networks and outputs them in the
+// consolidated format, returning the number of records
+func networksReport(client Client, cfg *config.Config, networks []meraki.Network) (int, error) {
+	var data interface{}
+	var count int
+	var err error
+	switch cfg.Command {
+	case "route-tables":
+		allRoutes := make([]meraki.RouteWithNetwork, 0)
+		for _, network := range meraki.FilterNetworksByTag(networks, TagFilter(cfg)) {
+			routes, err := client.GetRoutes(cfg.Organization, network.ID)
+			if err != nil {
+				slog.Error("Failed to get routes for network", "networkID", network.ID, "networkName", network.Name, "error", err)
+				continue
+			}
+			if routes, err = filterRoutesBySubnet(routes, cfg); err != nil {
+				return 0, err
+			}
+			routes = meraki.FilterRoutesByRegex(routes, cfg.FilterRegex, network.Name)
+			routes = meraki.FilterRoutesByIPVersion(routes, cfg.IPVersion)
+			routes = meraki.FilterDefaultRoutes(routes, cfg.DefaultRoutesOnly)
+			networkRoutes := make([]meraki.RouteWithNetwork, 0, len(routes))
+			for _, route := range routes {
+				networkRoutes = append(networkRoutes, meraki.RouteWithNetwork{
+					Route:          route,
+					NetworkID:      network.ID,
+					NetworkName:    network.Name,
+					Organization:   cfg.OrganizationName,
+					OrganizationID: cfg.Organization,
+				})
+			}
+			if networkRoutes, err = meraki.FilterRoutesByGateway(networkRoutes, cfg.Gateway); err != nil {
+				return 0, err
+			}
+			allRoutes = append(allRoutes, meraki.FilterRoutesByEnabled(networkRoutes, cfg.EnabledOnly, cfg.DisabledOnly)...)
+		}
+		data, count = routeReport(allRoutes, cfg), len(allRoutes)
+
+	case "down", "alerting":
+		allDevices := make([]meraki.DeviceWithNetwork, 0)
+		for _, network := range networks {
+			var devices []meraki.Device
+			if cfg.Command == "down" {
+				devices, err = client.GetDownDevices(cfg.Organization, network.ID, cfg.DownStatuses)
+			} else {
+				devices, err = client.GetAlertingDevices(cfg.Organization, network.ID)
+			}
+			if err != nil {
+				slog.Error("Failed to get devices for network", "networkID", network.ID, "networkName", network.Name, "error", err)
+				continue
+			}
+			devices = meraki.FilterDevicesByRegex(devices, cfg.FilterRegex, network.Name)
+			for _, device := range meraki.FilterDevicesBySerial(devices, cfg.SerialFilter) {
+				allDevices = append(allDevices, meraki.DeviceWithNetwork{
+					Device:         device,
+					NetworkName:    network.Name,
+					NetworkID:      network.ID,
+					Organization:   cfg.Organization,
+					OrganizationID: cfg.Organization,
+				})
+			}
+			if serialFound(cfg, len(allDevices)) {
+				break
+			}
+		}
+		data, count = allDevices, len(allDevices)
+
+	case "licenses":
+		licenses, err := client.GetLicenses(cfg.Organization)
+		if err != nil {
+			return 0, fmt.Errorf("failed to fetch licenses: %w", err)
+		}
+		licenses = meraki.FilterLicensesByState(licenses, cfg.LicenseStates)
+		if licenses, err = filterLicensesByExpiry(licenses, cfg); err != nil {
+			return 0, err
+		}
+		inNetworks := make(map[string]bool, len(networks))
+		for _, network := range networks {
+			inNetworks[network.ID] = true
+		}
+		networkLicenses := make([]meraki.License, 0)
+		for _, license := range resolveLicenseNetworks(client, cfg.Organization, licenses) {
+			if inNetworks[license.NetworkID] {
+				networkLicenses = append(networkLicenses, license)
+			}
+		}
+		data, count = networkLicenses, len(networkLicenses)
+
+	case "stacks":
+		allStacks := make([]meraki.SwitchStackWithNetwork, 0)
+		for _, network := range networks {
+			stacks, err := client.GetSwitchStacks(cfg.Organization, network.ID)
+			if err != nil {
+				slog.Error("Failed to get switch stacks for network", "networkID", network.ID, "networkName", network.Name, "error", err)
+				continue
+			}
+			for _, stack := range stacks {
+				stack.Organization = cfg.OrganizationName
+				stack.OrganizationID = cfg.Organization
+				allStacks = append(allStacks, stack)
+			}
+		}
+		data, count = allStacks, len(allStacks)
+
+	case "dhcp":
+		allSubnets := make([]meraki.DHCPSubnetWithNetwork, 0)
+		for _, network := range networks {
+			subnets, err := client.GetDHCPSubnets(cfg.Organization, network.ID)
+			if err != nil {
+				slog.Error("Failed to get DHCP subnets for network", "networkID", network.ID, "networkName", network.Name, "error", err)
+				continue
+			}
+			for _, subnet := range subnets {
+				subnet.Organization = cfg.OrganizationName
+				subnet.OrganizationID = cfg.Organization
+				allSubnets = append(allSubnets, subnet)
+			}
+		}
+		data, count = allSubnets, len(allSubnets)
+
+	case "firewall":
+		allRules := make([]meraki.FirewallRuleWithNetwork, 0)
+		for _, network := range networks {
+			rules, err := client.GetFirewallRules(cfg.Organization, network.ID, cfg.IncludeL7)
+			if err != nil {
+				slog.Error("Failed to get firewall rules for network", "networkID", network.ID, "networkName", network.Name, "error", err)
+				continue
+			}
+			for _, rule := range rules {
+				rule.Organization = cfg.OrganizationName
+				rule.OrganizationID = cfg.Organization
+				allRules = append(allRules, rule)
+			}
+		}
+		data, count = allRules, len(allRules)
+
+	case "status-summary":
+		allSummaries := make([]meraki.DeviceStatusSummary, 0)
+		for _, network := range networks {
+			summaries, err := client.GetDeviceStatusSummary(cfg.Organization, network.ID)
+			if err != nil {
+				slog.Error("Failed to get device status summary for network", "networkID", network.ID, "networkName", network.Name, "error", err)
+				continue
+			}
+			for _, summary := range summaries {
+				summary.Organization = cfg.OrganizationName
+				summary.OrganizationID = cfg.Organization
+				allSummaries = append(allSummaries, summary)
+			}
+		}
+		count = len(allSummaries)
+		data = append(allSummaries, meraki.TotalDeviceStatusSummary(allSummaries))
+
+	case "events":
+		allEvents := make([]meraki.EventWithNetwork, 0)
+		for _, network := range networks {
+			events, err := client.GetNetworkEvents(cfg.Organization, network.ID, eventQuery(cfg))
+			if err != nil {
+				slog.Error("Failed to get events for network", "networkID", network.ID, "networkName", network.Name, "error", err)
+				continue
+			}
+			for _, event := range events {
+				event.Organization = cfg.OrganizationName
+				event.OrganizationID = cfg.Organization
+				allEvents = append(allEvents, event)
+			}
+		}
+		data, count = allEvents, len(allEvents)
+
+	default:
+		return 0, fmt.Errorf("network patterns are not supported for the %s command", cfg.Command)
+	}
+
+	// Output to stdout or file
+	writer := newOutputWriter(cfg)
+	if cfg.OutputFile == "" || cfg.OutputFile == "-" {
+		if err := writer.WriteTo(data, stdout); err != nil {
+			return 0, fmt.Errorf("failed to write output to stdout: %w", err)
+		}
+		slog.Info("Matched network info sent to stdout", "networks", len(networks), "records", count)
+	} else {
+		if err := writer.WriteToFile(data, cfg.OutputFile); err != nil {
+			return 0, fmt.Errorf("failed to write output to file: %w", err)
+		}
+		slog.Info("Matched network info written to file", "networks", len(networks), "records", count, "file", cfg.OutputFile)
+	}
+
+	return count, nil
+}
+
+// SwitchStacks collects switch stacks for one organization, or all organizations with -all
+func SwitchStacks(client Client, cfg *config.Config) error {
+	orgs, err := selectedOrganizations(client, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to get organizations: %w", err)
+	}
+
+	var run output.RunSummary
+	allStacks := make([]meraki.SwitchStackWithNetwork, 0)
+	for _, org := range orgs {
+		if cfg.Organization != "" && org.ID != cfg.Organization {
+			continue
+		}
+
+		stacks, err := client.GetSwitchStacks(org.ID, cfg.Network)
+		if err != nil {
+			if cfg.Organization != "" || cfg.Strict {
+				return fmt.Errorf("failed to get switch stacks: %w", err)
+			}
+			run.AddOrganization(organizationFailed(org, err))
+			continue
+		}
+
+		// Add organization information to each stack record
+		for _, stack := range stacks {
+			stack.Organization = org.Name
+			stack.OrganizationID = org.ID
+			allStacks = append(allStacks, stack)
+		}
+		run.AddOrganization(output.OrganizationRunStats{Organization: org.Name, OrganizationID: org.ID, Items: len(stacks)})
+	}
+
+	// Output to stdout or file
+	writer := newOutputWriter(cfg)
+	if cfg.OutputFile == "" || cfg.OutputFile == "-" {
+		if err := writer.WriteTo(allStacks, stdout); err != nil {
+			return fmt.Errorf("failed to write output to stdout: %w", err)
+		}
+		slog.Info("Switch stacks sent to stdout", "stack_count", len(allStacks))
+	} else {
+		if err := writer.WriteToFile(allStacks, cfg.OutputFile); err != nil {
+			return fmt.Errorf("failed to write output to file: %w", err)
+		}
+		slog.Info("Switch stacks written to file", "stack_count", len(allStacks), "file", cfg.OutputFile)
+	}
+
+	return incompleteRunError(run, stderr)
+}
+
+// SwitchPorts collects switch port statuses for one organization, all organizations with -all, or
+// the single switch selected with -serial. With -errored-only only ports that are not connected or
+// show errors are output.
+func SwitchPorts(client Client, cfg *config.Config) error {
+	if cfg.SerialFilter != "" {
+		ports, err := switchPortsOfSerial(client, cfg)
+		if err != nil {
+			return err
+		}
+		return writeSwitchPorts(cfg, ports)
+	}
+
+	orgs, err := selectedOrganizations(client, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to get organizations: %w", err)
+	}
+
+	var run output.RunSummary
+	allPorts := make([]meraki.SwitchPortStatusWithNetwork, 0)
+	for _, org := range orgs {
+		if cfg.Organization != "" && org.ID != cfg.Organization {
+			continue
+		}
+
+		ports, err := client.GetSwitchPortStatuses(org.ID, cfg.Network)
+		if err != nil {
+			if cfg.Organization != "" || cfg.Strict {
+				return fmt.Errorf("failed to get switch port statuses: %w", err)
+			}
+			run.AddOrganization(organizationFailed(org, err))
+			continue
+		}
+		if cfg.ErroredOnly {
+			ports = meraki.FilterErroredPorts(ports)
+		}
+
+		// Add organization information to each port record
+		for _, port := range ports {
+			port.Organization = org.Name
+			port.OrganizationID = org.ID
+			allPorts = append(allPorts, port)
+		}
+		run.AddOrganization(output.OrganizationRunStats{Organization: org.Name, OrganizationID: org.ID, Items: len(ports)})
+	}
+
+	if err := writeSwitchPorts(cfg, allPorts); err != nil {
+		return err
+	}
+	return incompleteRunError(run, stderr)
+}
+
+// switchPortsOfSerial gets the port statuses of the switch selected with -serial. The serial
+// identifies the switch's network and organization, so no networks are scanned.
+func switchPortsOfSerial(client Client, cfg *config.Config) ([]meraki.SwitchPortStatusWithNetwork, error) {
+	device, err := client.GetDeviceDetails(cfg.SerialFilter)
+	if err != nil {
+		return nil, deviceLookupError(cfg.SerialFilter, err)
+	}
+	if device.NetworkID == "" {
+		return nil, fmt.Errorf("switch %s is not in a network and reports no port statuses", device.Serial)
+	}
+
+	statuses, err := client.GetNetworkSwitchPortStatuses(device.NetworkID, device.Serial)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get switch port statuses: %w", err)
+	}
+
+	ports := make([]meraki.SwitchPortStatusWithNetwork, 0, len(statuses))
+	for _, status := range statuses {
+		ports = append(ports, meraki.SwitchPortStatusWithNetwork{
+			SwitchPortStatus: status,
+			NetworkID:        device.NetworkID,
+			NetworkName:      device.NetworkName,
+			OrganizationID:   device.OrganizationID,
+		})
+	}
+	if cfg.ErroredOnly {
+		ports = meraki.FilterErroredPorts(ports)
+	}
+	return ports, nil
+}
+
+// writeSwitchPorts writes switch port statuses to stdout or the -output file
+func writeSwitchPorts(cfg *config.Config, ports []meraki.SwitchPortStatusWithNetwork) error {
+	writer := newOutputWriter(cfg)
+	if cfg.OutputFile == "" || cfg.OutputFile == "-" {
+		if err := writer.WriteTo(ports, stdout); err != nil {
+			return fmt.Errorf("failed to write output to stdout: %w", err)
+		}
+		slog.Info("Switch ports sent to stdout", "port_count", len(ports))
+	} else {
+		if err := writer.WriteToFile(ports, cfg.OutputFile); err != nil {
+			return fmt.Errorf("failed to write output to file: %w", err)
+		}
+		slog.Info("Switch ports written to file", "port_count", len(ports), "file", cfg.OutputFile)
+	}
+	return nil
+}
+
+// DHCPSubnets collects appliance VLAN and switch stack DHCP settings for one organization, or all
+// organizations with -all
+func DHCPSubnets(client Client, cfg *config.Config) error {
+	orgs, err := selectedOrganizations(client, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to get organizations: %w", err)
+	}
+
+	var run output.RunSummary
+	allSubnets := make([]meraki.DHCPSubnetWithNetwork, 0)
+	for _, org := range orgs {
+		if cfg.Organization != "" && org.ID != cfg.Organization {
+			continue
+		}
+
+		subnets, err := client.GetDHCPSubnets(org.ID, cfg.Network)
+		if err != nil {
+			if cfg.Organization != "" || cfg.Strict {
+				return fmt.Errorf("failed to get DHCP subnets: %w", err)
+			}
+			run.AddOrganization(organizationFailed(org, err))
+			continue
+		}
+
+		// Add organization information to each subnet record
+		for _, subnet := range subnets {
+			subnet.Organization = org.Name
+			subnet.OrganizationID = org.ID
+			allSubnets = append(allSubnets, subnet)
+		}
+		run.AddOrganization(output.OrganizationRunStats{Organization: org.Name, OrganizationID: org.ID, Items: len(subnets)})
+	}
+
+	// Output to stdout or file
+	writer := newOutputWriter(cfg)
+	if cfg.OutputFile == "" || cfg.OutputFile == "-" {
+		if err := writer.WriteTo(allSubnets, stdout); err != nil {
+			return fmt.Errorf("failed to write output to stdout: %w", err)
+		}
+		slog.Info("DHCP subnets sent to stdout", "subnet_count", len(allSubnets))
+	} else {
+		if err := writer.WriteToFile(allSubnets, cfg.OutputFile); err != nil {
+			return fmt.Errorf("failed to write output to file: %w", err)
+		}
+		slog.Info("DHCP subnets written to file", "subnet_count", len(allSubnets), "file", cfg.OutputFile)
+	}
+
+	return incompleteRunError(run, stderr)
+}
+
+// FirewallRules collects appliance firewall rules for one organization, or all organizations with -all
+func FirewallRules(client Client, cfg *config.Config) error {
+	orgs, err := selectedOrganizations(client, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to get organizations: %w", err)
+	}
+
+	var run output.RunSummary
+	allRules := make([]meraki.FirewallRuleWithNetwork, 0)
+	for _, org := range orgs {
+		if cfg.Organization != "" && org.ID != cfg.Organization {
+			continue
+		}
+
+		rules, err := client.GetFirewallRules(org.ID, cfg.Network, cfg.IncludeL7)
+		if err != nil {
+			if cfg.Organization != "" || cfg.Strict {
+				return fmt.Errorf("failed to get firewall rules: %w", err)
+			}
+			run.AddOrganization(organizationFailed(org, err))
+			continue
+		}
+
+		// Add organization information to each rule record
+		for _, rule := range rules {
+			rule.Organization = org.Name
+			rule.OrganizationID = org.ID
+			allRules = append(allRules, rule)
+		}
+		run.AddOrganization(output.OrganizationRunStats{Organization: org.Name, OrganizationID: org.ID, Items: len(rules)})
+	}
+
+	// Output to stdout or file
+	writer := newOutputWriter(cfg)
+	if cfg.OutputFile == "" || cfg.OutputFile == "-" {
+		if err := writer.WriteTo(allRules, stdout); err != nil {
+			return fmt.Errorf("failed to write output to stdout: %w", err)
+		}
+		slog.Info("Firewall rules sent to stdout", "rule_count", len(allRules))
+	} else {
+		if err := writer.WriteToFile(allRules, cfg.OutputFile); err != nil {
+			return fmt.Errorf("failed to write output to file: %w", err)
+		}
+		slog.Info("Firewall rules written to file", "rule_count", len(allRules), "file", cfg.OutputFile)
+	}
+
+	return incompleteRunError(run, stderr)
+}
+
+// Networks lists the networks of one organization, or all organizations with -all, narrowed by
+// -network-tags, -tag, -exclude-network and -product-type
+func Networks(client Client, cfg *config.Config) error {
+	orgs, err := selectedOrganizations(client, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to get organizations: %w", err)
+	}
+
+	var run output.RunSummary
+	allNetworks := make([]meraki.NetworkWithOrganization, 0)
+	for _, org := range orgs {
+		if cfg.Organization != "" && org.ID != cfg.Organization {
+			continue
+		}
+
+		networks, err := organizationNetworks(client, cfg, org.ID)
+		if err != nil {
+			if cfg.Organization != "" || cfg.Strict {
+				return fmt.Errorf("failed to get networks: %w", err)
+			}
+			run.AddOrganization(organizationFailed(org, err))
+			continue
+		}
+		networks = meraki.FilterNetworksByTag(networks, TagFilter(cfg))
+		networks = meraki.FilterNetworksByProductType(networks, cfg.ProductTypeFilter)
+
+		// Add organization information to each network record
+		for _, network := range networks {
+			allNetworks = append(allNetworks, meraki.NetworkWithOrganization{
+				Network:        network,
+				Organization:   org.Name,
+				OrganizationID: org.ID,
+			})
+		}
+		run.AddOrganization(output.OrganizationRunStats{Organization: org.Name, OrganizationID: org.ID, Items: len(networks)})
+	}
+
+	// Output to stdout or file
+	writer := newOutputWriter(cfg)
+	if cfg.OutputFile == "" || cfg.OutputFile == "-" {
+		if err := writer.WriteTo(allNetworks, stdout); err != nil {
+			return fmt.Errorf("failed to write output to stdout: %w", err)
+		}
+		slog.Info("Networks sent to stdout", "network_count", len(allNetworks))
+	} else {
+		if err := writer.WriteToFile(allNetworks, cfg.OutputFile); err != nil {
+			return fmt.Errorf("failed to write output to file: %w", err)
+		}
+		slog.Info("Networks written to file", "network_count", len(allNetworks), "file", cfg.OutputFile)
+	}
+
+	return incompleteRunError(run, stderr)
+}
+
+// NetworkEvents collects the event log of a single network
+func NetworkEvents(client Client, cfg *config.Config) error {
+	events, err := client.GetNetworkEvents(cfg.Organization, cfg.Network, eventQuery(cfg))
+	if err != nil {
+		return fmt.Errorf("failed to get network events: %w", err)
+	}
+
+	// Add organization information to each event record
+	for i := range events {
+		events[i].Organization = cfg.OrganizationName
+		events[i].OrganizationID = cfg.Organization
+	}
+
+	// Output to stdout or file
+	writer := newOutputWriter(cfg)
+	if cfg.OutputFile == "" || cfg.OutputFile == "-" {
+		if err := writer.WriteTo(events, stdout); err != nil {
+			return fmt.Errorf("failed to write output to stdout: %w", err)
+		}
+		slog.Info("Network events sent to stdout", "event_count", len(events))
+	} else {
+		if err := writer.WriteToFile(events, cfg.OutputFile); err != nil {
+			return fmt.Errorf("failed to write output to file: %w", err)
+		}
+		slog.Info("Network events written to file", "event_count", len(events), "file", cfg.OutputFile)
+	}
+
+	return nil
+}
+
+// eventQuery builds the events query for -event-type, -since, -until, -product-type and -limit/-offset
+func eventQuery(cfg *config.Config) meraki.EventQuery {
+	query := meraki.EventQuery{
+		EventTypes: cfg.EventTypes,
+		Since:      cfg.Since,
+		Until:      cfg.Until,
+	}
+	if len(cfg.ProductTypeFilter) > 0 {
+		query.ProductType = cfg.ProductTypeFilter[0]
+	}
+	// Stop paging once enough events for the requested page of output have been fetched
+	if cfg.Limit > 0 {
+		query.Limit = cfg.Limit + cfg.Offset
+	}
+	return query
+}
@@ -0,0 +1,347 @@
+package commands
+
+import (
+	"fmt"
+	"log/slog"
+
+	"meraki-info/internal/config"
+	"meraki-info/internal/meraki"
+	"meraki-info/internal/output"
+)
+
+// SingleNetworkRoutes collects routes for a single network
+func SingleNetworkRoutes(client Client, cfg *config.Config) error {
+	// Fetch routes for single network
+	routes, err := client.GetRoutes(cfg.Organization, cfg.Network)
+	if err != nil {
+		return fmt.Errorf("failed to fetch routes: %w", err)
+	}
+	if routes, err = filterRoutesBySubnet(routes, cfg); err != nil {
+		return err
+	}
+	routes = filterRoutesByEnabled(routes, cfg)
+	if routes, err = filterRoutesByGateway(routes, cfg); err != nil {
+		return err
+	}
+	routes = meraki.FilterRoutesByRegex(routes, cfg.FilterRegex, "")
+	routes = meraki.FilterRoutesByIPVersion(routes, cfg.IPVersion)
+	routes = meraki.FilterDefaultRoutes(routes, cfg.DefaultRoutesOnly)
+
+	slog.Info("Retrieved routes", "count", len(routes))
+
+	var data interface{} = routes
+	if cfg.DetectOverlaps {
+		networkRoutes := make([]meraki.RouteWithNetwork, len(routes))
+		for i, route := range routes {
+			networkRoutes[i] = meraki.RouteWithNetwork{Route: route, NetworkID: cfg.Network}
+		}
+		data = routeReport(networkRoutes, cfg)
+	}
+
+	// Determine output filename
+	outputFile := cfg.OutputFile
+	if outputFile == "" || outputFile == "-" {
+		// Send to stdout when not provided or explicitly set to "-"
+		outputWriter := newOutputWriter(cfg)
+		if err := outputWriter.WriteTo(data, stdout); err != nil {
+			return fmt.Errorf("failed to write output to stdout: %w", err)
+		}
+		slog.Info("Route tables sent to stdout", "route_count", len(routes))
+		return nil
+	}
+
+	// Output to file
+	outputWriter := newOutputWriter(cfg)
+	if err := outputWriter.WriteToFile(data, outputFile); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	slog.Info("Route tables info collection completed successfully", "output_file", outputFile)
+
+	return nil
+}
+
+// AllNetworkRoutes collects info for routes for all networks in the organization(s)
+func AllNetworkRoutes(client Client, cfg *config.Config) error {
+	// Check if output should go to stdout or a URL (consolidated format). Overlaps are detected
+	// across all networks, so they are always reported together.
+	if cfg.OutputFile == "" || cfg.OutputFile == "-" || output.IsURL(cfg.OutputFile) || cfg.DetectOverlaps {
+		return infoAllNetworkRoutesConsolidated(client, cfg)
+	}
+
+	// Otherwise use separate files for each network
+	if cfg.Organization != "" {
+		// Get info for all networks in a specific organization
+		stats := output.OrganizationRunStats{Organization: cfg.OrganizationName, OrganizationID: cfg.Organization}
+		if err := infoOrganizationNetworkRoutes(cfg, client, cfg.Organization, &stats); err != nil {
+			return err
+		}
+		var run output.RunSummary
+		run.AddOrganization(stats)
+		return incompleteRunError(run, stderr)
+	} else {
+		// Get info for all networks in all organizations
+		return infoAllOrganizationRoutes(cfg, client)
+	}
+}
+
+// infoAllNetworkRoutesConsolidated collects info for routes for all networks and outputs to stdout in consolidated format
+func infoAllNetworkRoutesConsolidated(client Client, cfg *config.Config) error {
+	if cfg.Organization != "" {
+		// Get routes for all networks in a specific organization
+		callsBefore := client.RequestCount()
+		networkRoutes, err := client.GetAllNetworkRoutes(cfg.Organization)
+		if err != nil {
+			return fmt.Errorf("failed to fetch network routes: %w", err)
+		}
+
+		// Create consolidated output with network information
+		allRoutes := make([]meraki.RouteWithNetwork, 0)
+		stats := output.OrganizationRunStats{Organization: cfg.OrganizationName, OrganizationID: cfg.Organization, APICalls: client.RequestCount() - callsBefore}
+		for _, nr := range networkRoutes {
+			stats.NetworksScanned++
+			if nr.Error != "" {
+				if cfg.Strict {
+					return fmt.Errorf("failed to get routes for network %s: %s", nr.Network.Name, nr.Error)
+				}
+				stats.AddFailedNetwork(nr.Network.Name, nr.Network.ID, nr.Error)
+			}
+			routes, err := filterRoutesBySubnet(nr.Routes, cfg)
+			if err != nil {
+				return err
+			}
+			routes = meraki.FilterRoutesByRegex(routes, cfg.FilterRegex, nr.Network.Name)
+			routes = meraki.FilterRoutesByIPVersion(routes, cfg.IPVersion)
+			routes = meraki.FilterDefaultRoutes(routes, cfg.DefaultRoutesOnly)
+			networkRoutes := make([]meraki.RouteWithNetwork, 0, len(routes))
+			for _, route := range routes {
+				networkRoutes = append(networkRoutes, meraki.RouteWithNetwork{
+					Route:          route,
+					NetworkID:      nr.Network.ID,
+					NetworkName:    nr.Network.Name,
+					Organization:   cfg.OrganizationName,
+					OrganizationID: cfg.Organization,
+				})
+			}
+			if networkRoutes, err = meraki.FilterRoutesByGateway(networkRoutes, cfg.Gateway); err != nil {
+				return err
+			}
+			allRoutes = append(allRoutes, meraki.FilterRoutesByEnabled(networkRoutes, cfg.EnabledOnly, cfg.DisabledOnly)...)
+		}
+
+		// Output to stdout or file
+		outputWriter := newOutputWriter(cfg)
+		if cfg.OutputFile == "" || cfg.OutputFile == "-" {
+			if err := outputWriter.WriteTo(routeReport(allRoutes, cfg), stdout); err != nil {
+				return fmt.Errorf("failed to write output to stdout: %w", err)
+			}
+			slog.Info("Route tables info sent to stdout", "total_routes", len(allRoutes))
+		} else {
+			if err := outputWriter.WriteToFile(routeReport(allRoutes, cfg), cfg.OutputFile); err != nil {
+				return fmt.Errorf("failed to write output to file: %w", err)
+			}
+			slog.Info("Route tables info written to file", "total_routes", len(allRoutes), "file", cfg.OutputFile)
+		}
+
+		var run output.RunSummary
+		stats.Items = len(allRoutes)
+		run.AddOrganization(stats)
+		return finishRun(cfg, run)
+	} else {
+		// Get routes for all networks in all organizations
+		organizations, err := selectedOrganizations(client, cfg)
+		if err != nil {
+			return fmt.Errorf("error getting organizations: %w", err)
+		}
+
+		allRoutes := make([]meraki.RouteWithNetwork, 0)
+		var run output.RunSummary
+		for _, org := range organizations {
+			stats := output.OrganizationRunStats{Organization: org.Name, OrganizationID: org.ID}
+			callsBefore, itemsBefore := client.RequestCount(), len(allRoutes)
+
+			networkRoutes, err := client.GetAllNetworkRoutes(org.ID)
+			if err != nil {
+				if cfg.Strict {
+					return fmt.Errorf("failed to fetch routes for organization %s: %w", org.Name, err)
+				}
+				stats = organizationFailed(org, err)
+				stats.APICalls = client.RequestCount() - callsBefore
+				run.AddOrganization(stats)
+				continue
+			}
+
+			for _, nr := range networkRoutes {
+				stats.NetworksScanned++
+				if nr.Error != "" {
+					if cfg.Strict {
+						return fmt.Errorf("failed to get routes for network %s: %s", nr.Network.Name, nr.Error)
+					}
+					stats.AddFailedNetwork(nr.Network.Name, nr.Network.ID, nr.Error)
+				}
+				routes, err := filterRoutesBySubnet(nr.Routes, cfg)
+				if err != nil {
+					return err
+				}
+				routes = meraki.FilterRoutesByRegex(routes, cfg.FilterRegex, nr.Network.Name)
+				routes = meraki.FilterRoutesByIPVersion(routes, cfg.IPVersion)
+				routes = meraki.FilterDefaultRoutes(routes, cfg.DefaultRoutesOnly)
+				networkRoutes := make([]meraki.RouteWithNetwork, 0, len(routes))
+				for _, route := range routes {
+					networkRoutes = append(networkRoutes, meraki.RouteWithNetwork{
+						Route:          route,
+						NetworkID:      nr.Network.ID,
+						NetworkName:    nr.Network.Name,
+						Organization:   org.Name,
+						OrganizationID: org.ID,
+					})
+				}
+				if networkRoutes, err = meraki.FilterRoutesByGateway(networkRoutes, cfg.Gateway); err != nil {
+					return err
+				}
+				allRoutes = append(allRoutes, meraki.FilterRoutesByEnabled(networkRoutes, cfg.EnabledOnly, cfg.DisabledOnly)...)
+			}
+
+			stats.Items = len(allRoutes) - itemsBefore
+			stats.APICalls = client.RequestCount() - callsBefore
+			run.AddOrganization(stats)
+		}
+
+		// Output to stdout or file
+		outputWriter := newOutputWriter(cfg)
+		if cfg.OutputFile == "" || cfg.OutputFile == "-" {
+			if err := outputWriter.WriteTo(routeReport(allRoutes, cfg), stdout); err != nil {
+				return fmt.Errorf("failed to write output to stdout: %w", err)
+			}
+			slog.Info("Route tables info sent to stdout", "total_routes", len(allRoutes))
+		} else {
+			if err := outputWriter.WriteToFile(routeReport(allRoutes, cfg), cfg.OutputFile); err != nil {
+				return fmt.Errorf("failed to write output to file: %w", err)
+			}
+			slog.Info("Route tables info written to file", "total_routes", len(allRoutes), "file", cfg.OutputFile)
+		}
+		return finishRun(cfg, run)
+	}
+}
+
+// infoOrganizationNetworkRoutes collects info for routes for all networks in an
+// organization, recording the networks scanned and those that failed in stats
+func infoOrganizationNetworkRoutes(cfg *config.Config, client Client, organizationID string, stats *output.OrganizationRunStats) error {
+	networks, err := organizationNetworks(client, cfg, organizationID)
+	if err != nil {
+		return fmt.Errorf("error getting organization networks: %w", err)
+	}
+	networks = meraki.FilterNetworksByTag(networks, TagFilter(cfg))
+
+	for _, network := range networks {
+		stats.NetworksScanned++
+
+		// Create a copy of config for this network
+		networkCfg := *cfg
+		networkCfg.Organization = organizationID
+		networkCfg.Network = network.ID
+
+		// Only proceed if a specific output file is provided
+		if networkCfg.OutputFile == "" {
+			return fmt.Errorf("no output file specified for separate file generation")
+		}
+
+		err := SingleNetworkRoutes(client, &networkCfg)
+		if err != nil {
+			if cfg.Strict {
+				return fmt.Errorf("failed to collect route info for network %s: %w", network.Name, err)
+			}
+			slog.Error("Failed to collect route info for network", "network", network.Name, "error", err)
+			stats.AddFailedNetwork(network.Name, network.ID, err.Error())
+			continue
+		}
+	}
+
+	return nil
+}
+
+// infoAllOrganizationRoutes collects info for routes for all organizations
+func infoAllOrganizationRoutes(cfg *config.Config, client Client) error {
+	organizations, err := selectedOrganizations(client, cfg)
+	if err != nil {
+		return fmt.Errorf("error getting organizations: %w", err)
+	}
+
+	var run output.RunSummary
+	for _, org := range organizations {
+		slog.Info("Processing organization for route tables", "org", org.Name, "id", org.ID)
+		stats := output.OrganizationRunStats{Organization: org.Name, OrganizationID: org.ID}
+		callsBefore := client.RequestCount()
+		err := infoOrganizationNetworkRoutes(cfg, client, org.ID, &stats)
+		if err != nil {
+			if cfg.Strict {
+				return err
+			}
+			stats = organizationFailed(org, err)
+			stats.APICalls = client.RequestCount() - callsBefore
+			run.AddOrganization(stats)
+			continue
+		}
+		stats.APICalls = client.RequestCount() - callsBefore
+		run.AddOrganization(stats)
+	}
+
+	return incompleteRunError(run, stderr)
+}
+
+// routeReport returns the routes to write, or the pairs of them whose subnets overlap with -detect-overlaps
+func routeReport(routes []meraki.RouteWithNetwork, cfg *config.Config) interface{} {
+	if !cfg.DetectOverlaps {
+		return routes
+	}
+	overlaps := meraki.FindRouteOverlaps(routes)
+	slog.Info("Detected overlapping subnets", "route_count", len(routes), "overlaps", len(overlaps))
+	return overlaps
+}
+
+// filterRoutesBySubnet keeps the routes within -subnet, or all routes when it is not set
+func filterRoutesBySubnet(routes []meraki.Route, cfg *config.Config) ([]meraki.Route, error) {
+	if cfg.Subnet == "" {
+		return routes, nil
+	}
+	return meraki.FilterRoutesBySupernet(routes, cfg.Subnet)
+}
+
+// filterRoutesByEnabled applies -enabled-only/-disabled-only to the routes of a single network
+func filterRoutesByEnabled(routes []meraki.Route, cfg *config.Config) []meraki.Route {
+	if !cfg.EnabledOnly && !cfg.DisabledOnly {
+		return routes
+	}
+
+	wrapped := make([]meraki.RouteWithNetwork, len(routes))
+	for i, route := range routes {
+		wrapped[i] = meraki.RouteWithNetwork{Route: route}
+	}
+
+	filtered := make([]meraki.Route, 0, len(routes))
+	for _, route := range meraki.FilterRoutesByEnabled(wrapped, cfg.EnabledOnly, cfg.DisabledOnly) {
+		filtered = append(filtered, route.Route)
+	}
+	return filtered
+}
+
+// filterRoutesByGateway applies -gateway to the routes of a single network
+func filterRoutesByGateway(routes []meraki.Route, cfg *config.Config) ([]meraki.Route, error) {
+	if cfg.Gateway == "" {
+		return routes, nil
+	}
+
+	wrapped := make([]meraki.RouteWithNetwork, len(routes))
+	for i, route := range routes {
+		wrapped[i] = meraki.RouteWithNetwork{Route: route}
+	}
+
+	matched, err := meraki.FilterRoutesByGateway(wrapped, cfg.Gateway)
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]meraki.Route, 0, len(matched))
+	for _, route := range matched {
+		filtered = append(filtered, route.Route)
+	}
+	return filtered, nil
+}
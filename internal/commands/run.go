@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"meraki-info/internal/config"
+	"meraki-info/internal/meraki"
+	"meraki-info/internal/output"
+)
+
+// ErrIncompleteRun marks a run where some organizations or networks could not be scanned, so the
+// output written holds only the data of the others
+var ErrIncompleteRun = errors.New("run is incomplete")
+
+// finishRun writes the run summary and notes organizations and networks that could not be scanned
+func finishRun(cfg *config.Config, run output.RunSummary) error {
+	if err := writeRunSummary(cfg, run); err != nil {
+		return err
+	}
+	return incompleteRunError(run, stderr)
+}
+
+// incompleteRunError writes notes about denied and incomplete organizations and failed networks to
+// writer and, when there are any, returns an error wrapping ErrIncompleteRun so the run exits with
+// exitPartialResults
+func incompleteRunError(run output.RunSummary, writer io.Writer) error {
+	if err := output.WriteAccessNote(writer, run); err != nil {
+		return err
+	}
+	if err := output.WriteIncompleteNote(writer, run); err != nil {
+		return err
+	}
+	if err := output.WriteFailedNetworksNote(writer, run); err != nil {
+		return err
+	}
+	incomplete, failedNetworks := len(run.Incomplete()), run.Total.NetworksFailed
+	if incomplete+failedNetworks == 0 {
+		return nil
+	}
+	if failedNetworks == 0 {
+		return fmt.Errorf("%w: %d organization(s) could not be fully scanned", ErrIncompleteRun, incomplete)
+	}
+	return fmt.Errorf("%w: %d organization(s) and %d network(s) could not be fully scanned", ErrIncompleteRun, incomplete, failedNetworks)
+}
+
+// organizationFailed returns the run statistics of an organization whose data could not be fetched,
+// logging the failure. Organizations that deny the API key access are marked as such.
+func organizationFailed(org meraki.Organization, err error) output.OrganizationRunStats {
+	stats := output.OrganizationRunStats{Organization: org.Name, OrganizationID: org.ID, Error: err.Error()}
+	if errors.Is(err, meraki.ErrForbidden) {
+		slog.Warn("Access denied to organization, skipping it", "orgID", org.ID, "orgName", org.Name)
+		stats.AccessDenied = true
+	} else {
+		slog.Error("Failed to get data for organization", "orgID", org.ID, "orgName", org.Name, "error", err)
+	}
+	return stats
+}
+
+// writeRunSummary reports per-organization run statistics when -run-summary is set.
+// Text output gets the summary appended to the same file or stdout; other formats, and text files
+// gzipped with -compress, write it to OUTPUT.summary, or to stderr when the data went to stdout or
+// a URL, so the data stays parseable. Filename tokens and -compress apply as they do to the output.
+func writeRunSummary(cfg *config.Config, run output.RunSummary) error {
+	if !cfg.RunSummary {
+		return nil
+	}
+	run.Command = cfg.Command
+
+	writer := output.NewWriter(cfg.OutputType,
+		output.WithCSVDelimiter(cfg.CSVDelimiter),
+		output.WithFileMode(cfg.OutputMode),
+		output.WithCompress(cfg.Compress))
+	toStdout := cfg.OutputFile == "" || cfg.OutputFile == "-" || output.IsURL(cfg.OutputFile)
+	outputFile := output.ExpandFilename(cfg.OutputFile, filenameTokens(cfg))
+
+	if _, isText := writer.(*output.TextWriter); isText {
+		if toStdout {
+			fmt.Fprintln(stdout)
+			return writer.WriteTo(run, stdout)
+		}
+		if !cfg.Compress {
+			file, err := os.OpenFile(outputFile, os.O_APPEND|os.O_WRONLY, 0644)
+			if err != nil {
+				return fmt.Errorf("failed to open output file for run summary: %w", err)
+			}
+			defer file.Close()
+			fmt.Fprintln(file)
+			return writer.WriteTo(run, file)
+		}
+	}
+
+	if toStdout {
+		return writer.WriteTo(run, stderr)
+	}
+	summaryFile := outputFile + ".summary"
+	if err := writer.WriteToFile(run, summaryFile); err != nil {
+		return fmt.Errorf("failed to write run summary: %w", err)
+	}
+	if cfg.Compress {
+		summaryFile = output.CompressedFilename(summaryFile)
+	}
+	slog.Info("Run summary written to file", "file", summaryFile)
+	return nil
+}
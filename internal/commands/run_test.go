@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"meraki-info/internal/config"
+	"meraki-info/internal/output"
+)
+
+func TestIncompleteRunError(t *testing.T) {
+	var run output.RunSummary
+	run.AddOrganization(output.OrganizationRunStats{Organization: "Visible", OrganizationID: "1", NetworksScanned: 2, Items: 4})
+	run.AddOrganization(output.OrganizationRunStats{Organization: "Hidden", OrganizationID: "2",
+		Error: "failed to get networks: API request failed with status 404: Not Found"})
+
+	t.Run("incomplete organization", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := incompleteRunError(run, &buf)
+		if !errors.Is(err, ErrIncompleteRun) {
+			t.Fatalf("Expected ErrIncompleteRun, got: %v", err)
+		}
+		note := buf.String()
+		if !strings.Contains(note, "1 organization(s) are incomplete") || !strings.Contains(note, "Hidden (2)") {
+			t.Errorf("Expected note naming the incomplete organization, got:\n%s", note)
+		}
+		if strings.Contains(note, "Visible") {
+			t.Errorf("Expected complete organizations to be left out of the note, got:\n%s", note)
+		}
+	})
+
+	t.Run("complete run", func(t *testing.T) {
+		var complete output.RunSummary
+		complete.AddOrganization(output.OrganizationRunStats{Organization: "Visible", OrganizationID: "1"})
+		var buf bytes.Buffer
+		if err := incompleteRunError(complete, &buf); err != nil {
+			t.Errorf("Expected no error for a complete run, got: %v", err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("Expected no note for a complete run, got: %q", buf.String())
+		}
+	})
+
+	t.Run("failed network", func(t *testing.T) {
+		stats := output.OrganizationRunStats{Organization: "Visible", OrganizationID: "1", NetworksScanned: 2}
+		stats.AddFailedNetwork("Branch", "N_1", "timeout")
+		var partial output.RunSummary
+		partial.AddOrganization(stats)
+
+		var buf bytes.Buffer
+		err := incompleteRunError(partial, &buf)
+		if !errors.Is(err, ErrIncompleteRun) {
+			t.Fatalf("Expected ErrIncompleteRun for a failed network, got: %v", err)
+		}
+		if !strings.Contains(buf.String(), "  - Visible / Branch (N_1): timeout") {
+			t.Errorf("Expected note naming the failed network, got:\n%s", buf.String())
+		}
+	})
+}
+
+func TestWriteRunSummary_OutputFile(t *testing.T) {
+	out, _ := captureOutput(t)
+	var run output.RunSummary
+	run.AddOrganization(output.OrganizationRunStats{Organization: "Org One", OrganizationID: "org1", NetworksScanned: 2, Items: 1})
+	date := runStarted.Format("2006-01-02")
+
+	t.Run("filename tokens", func(t *testing.T) {
+		dir := t.TempDir()
+		cfg := &config.Config{Command: "down", InfoAll: true, OutputType: "text", RunSummary: true,
+			OutputFile: filepath.Join(dir, "down-{date}.txt")}
+		outputFile := filepath.Join(dir, "down-"+date+".txt")
+		if err := os.WriteFile(outputFile, []byte("devices\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := writeRunSummary(cfg, run); err != nil {
+			t.Fatalf("writeRunSummary failed: %v", err)
+		}
+		data, err := os.ReadFile(outputFile)
+		if err != nil {
+			t.Fatalf("Failed to read output file: %v", err)
+		}
+		if !strings.HasPrefix(string(data), "devices\n\nRun Summary\n") {
+			t.Errorf("Expected the run summary appended to the expanded output file, got:\n%s", data)
+		}
+	})
+
+	t.Run("compressed text output", func(t *testing.T) {
+		dir := t.TempDir()
+		cfg := &config.Config{Command: "down", InfoAll: true, OutputType: "text", RunSummary: true, Compress: true,
+			OutputFile: filepath.Join(dir, "down-{date}.txt")}
+		outputFile := filepath.Join(dir, "down-"+date+".txt.gz")
+		if err := os.WriteFile(outputFile, []byte("gzipped devices"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := writeRunSummary(cfg, run); err != nil {
+			t.Fatalf("writeRunSummary failed: %v", err)
+		}
+		if data, _ := os.ReadFile(outputFile); string(data) != "gzipped devices" {
+			t.Errorf("Expected the compressed output to be left alone, got %q", data)
+		}
+		file, err := os.Open(filepath.Join(dir, "down-"+date+".txt.summary.gz"))
+		if err != nil {
+			t.Fatalf("Expected a compressed summary file: %v", err)
+		}
+		defer file.Close()
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			t.Fatalf("Expected a gzip summary file: %v", err)
+		}
+		summary, err := io.ReadAll(gz)
+		if err != nil || !strings.Contains(string(summary), "Run Summary") || !strings.Contains(string(summary), "Org One") {
+			t.Errorf("Expected the run summary in the summary file, got %q (%v)", summary, err)
+		}
+	})
+
+	if out.Len() != 0 {
+		t.Errorf("Expected nothing on stdout, got %q", out.String())
+	}
+}
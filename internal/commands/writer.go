@@ -0,0 +1,324 @@
+package commands
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"meraki-info/internal/config"
+	"meraki-info/internal/meraki"
+	"meraki-info/internal/output"
+)
+
+// newOutputWriter creates the output writer for the configured format, posting to -output when it
+// is a URL, fanning out to any -secondary-output destinations, and applying -sort, -offset/-limit,
+// -summary and -diff-against in that order. Filename tokens such as {date} are expanded, files
+// are gzipped with -compress, JSON is wrapped in an envelope with -json-envelope and written files
+// are listed in the -manifest.
+func newOutputWriter(cfg *config.Config) output.Writer {
+	tokens := filenameTokens(cfg)
+
+	options := []output.Option{
+		output.WithFileMode(cfg.OutputMode),
+		output.WithFields(cfg.Fields),
+		output.WithCompress(cfg.Compress),
+		output.WithCSVDelimiter(cfg.CSVDelimiter),
+		output.WithCSVBOM(cfg.CSVBOM),
+		output.WithTemplate(cfg.TemplateFile, output.TemplateMeta{
+			Command:      cfg.Command,
+			Organization: labelOrID(cfg.OrganizationName, cfg.Organization),
+		}),
+		output.WithJSONEnvelope(cfg.JSONEnvelope),
+	}
+
+	writer := output.NewWriter(cfg.OutputType, options...)
+	if textWriter, ok := writer.(*output.TextWriter); ok {
+		textWriter.Subtotals = cfg.Subtotals
+		textWriter.GroupByNetwork = cfg.GroupByNetwork
+		textWriter.NoLicenseFooter = cfg.NoLicenseFooter
+	}
+	if jsonWriter, ok := writer.(*output.JSONWriter); ok {
+		jsonWriter.Native = cfg.NativeJSON
+	}
+	if prometheusWriter, ok := writer.(*output.PrometheusWriter); ok && cfg.Command == "alerting" {
+		prometheusWriter.DeviceMetric = "meraki_device_alerting"
+	}
+	if output.IsURL(cfg.OutputFile) {
+		headers := make(http.Header)
+		for _, spec := range cfg.OutputHeaders {
+			// Headers are validated during config parsing
+			name, value, _ := output.ParseHeader(spec)
+			headers.Add(name, value)
+		}
+		writer = output.NewHTTPWriter(writer, cfg.OutputType, headers)
+	}
+	if len(cfg.SecondaryOutputs) > 0 {
+		writers := []output.Writer{writer}
+		for _, spec := range cfg.SecondaryOutputs {
+			// Specs are validated during config parsing
+			outputType, path, _ := config.ParseSecondaryOutput(spec)
+			writers = append(writers, output.NewDestinationWriter(outputType, output.ExpandFilename(path, tokens), options...))
+		}
+		writer = output.NewMultiWriter(writers...)
+	}
+	if cfg.DiffAgainst != "" {
+		writer = output.NewDiffWriter(writer, cfg.DiffAgainst)
+	}
+	if cfg.Summary {
+		writer = &summaryWriter{writer: writer, cfg: cfg}
+	}
+	if cfg.Manifest != "" {
+		writer = &manifestWriter{writer: writer, manifest: runManifest(cfg), tokens: tokens, compress: cfg.Compress}
+	}
+	if cfg.Limit > 0 || cfg.Offset > 0 {
+		writer = &pageWriter{writer: writer, limit: cfg.Limit, offset: cfg.Offset}
+	}
+	if cfg.Sort != "" {
+		// The sort spec is validated during config parsing
+		field, ascending, _ := output.ParseSort(cfg.Sort)
+		writer = &sortWriter{writer: writer, field: field, ascending: ascending}
+	}
+	return &filenameWriter{writer: writer, tokens: tokens}
+}
+
+// runStarted is the time of the run used for the {date} and {time} filename tokens, so the files of
+// a run, such as the output and its run summary, carry the same timestamp
+var runStarted = time.Now()
+
+// filenameTokens returns the values of the filename tokens for cfg
+func filenameTokens(cfg *config.Config) output.FilenameTokens {
+	return output.FilenameTokens{
+		Time:         runStarted,
+		Organization: cfg.Organization,
+		Network:      cfg.Network,
+		Command:      cfg.Command,
+	}
+}
+
+// filenameWriter expands the tokens of output filenames before handing them to the wrapped writer
+type filenameWriter struct {
+	writer output.Writer
+	tokens output.FilenameTokens
+}
+
+// WriteToFile writes data to the file named by filename with its tokens expanded
+func (w *filenameWriter) WriteToFile(data interface{}, filename string) error {
+	return w.writer.WriteToFile(data, output.ExpandFilename(filename, w.tokens))
+}
+
+// WriteTo writes data to an io.Writer
+func (w *filenameWriter) WriteTo(data interface{}, writer io.Writer) error {
+	return w.writer.WriteTo(data, writer)
+}
+
+// manifests holds the -manifest of the run by path, so the writers created for each network of a
+// separate-file run add to the same manifest
+var manifests = struct {
+	sync.Mutex
+	byPath map[string]*output.Manifest
+}{byPath: make(map[string]*output.Manifest)}
+
+// runManifest returns the manifest that -manifest names
+func runManifest(cfg *config.Config) *output.Manifest {
+	manifests.Lock()
+	defer manifests.Unlock()
+	manifest, ok := manifests.byPath[cfg.Manifest]
+	if !ok {
+		manifest = output.NewManifest(cfg.Manifest, cfg.OutputMode)
+		manifests.byPath[cfg.Manifest] = manifest
+	}
+	return manifest
+}
+
+// manifestWriter adds each file written by the wrapped writer to the -manifest, with the
+// organization and network it covers and its number of records
+type manifestWriter struct {
+	writer   output.Writer
+	manifest *output.Manifest
+	tokens   output.FilenameTokens
+	compress bool
+}
+
+// WriteToFile writes data to a file and adds the file to the manifest
+func (w *manifestWriter) WriteToFile(data interface{}, filename string) error {
+	if err := w.writer.WriteToFile(data, filename); err != nil {
+		return err
+	}
+	if output.IsURL(filename) {
+		return nil
+	}
+	if w.compress {
+		filename = output.CompressedFilename(filename)
+	}
+
+	records := 1
+	if value := reflect.ValueOf(data); value.Kind() == reflect.Slice {
+		records = value.Len()
+	}
+	return w.manifest.Add(output.ManifestEntry{
+		File:           filename,
+		OrganizationID: w.tokens.Organization,
+		NetworkID:      w.tokens.Network,
+		Records:        records,
+	})
+}
+
+// WriteTo writes data to an io.Writer; streams are not files, so they are not added to the manifest
+func (w *manifestWriter) WriteTo(data interface{}, writer io.Writer) error {
+	return w.writer.WriteTo(data, writer)
+}
+
+// pageWriter passes a -offset/-limit window of records to the wrapped writer
+type pageWriter struct {
+	writer output.Writer
+	limit  int
+	offset int
+}
+
+// WriteToFile writes the selected page of data to a file
+func (w *pageWriter) WriteToFile(data interface{}, filename string) error {
+	return w.writer.WriteToFile(applyLimitOffset(data, w.limit, w.offset), filename)
+}
+
+// WriteTo writes the selected page of data to an io.Writer
+func (w *pageWriter) WriteTo(data interface{}, writer io.Writer) error {
+	return w.writer.WriteTo(applyLimitOffset(data, w.limit, w.offset), writer)
+}
+
+// applyLimitOffset returns the records of a slice starting at offset, at most limit of them.
+// A limit of 0 means no limit; an offset past the end yields an empty slice. Non-slice data is returned unchanged.
+func applyLimitOffset(slice interface{}, limit, offset int) interface{} {
+	value := reflect.ValueOf(slice)
+	if value.Kind() != reflect.Slice {
+		return slice
+	}
+
+	length := value.Len()
+	start := offset
+	if start > length {
+		start = length
+	}
+	end := length
+	if limit > 0 && start+limit < end {
+		end = start + limit
+	}
+
+	return value.Slice(start, end).Interface()
+}
+
+// sortWriter orders records by a field before handing them to the wrapped writer
+type sortWriter struct {
+	writer    output.Writer
+	field     string
+	ascending bool
+}
+
+// WriteToFile sorts data and writes it to a file
+func (w *sortWriter) WriteToFile(data interface{}, filename string) error {
+	if err := output.SortSlice(data, w.field, w.ascending); err != nil {
+		return err
+	}
+	return w.writer.WriteToFile(data, filename)
+}
+
+// WriteTo sorts data and writes it to an io.Writer
+func (w *sortWriter) WriteTo(data interface{}, writer io.Writer) error {
+	if err := output.SortSlice(data, w.field, w.ascending); err != nil {
+		return err
+	}
+	return w.writer.WriteTo(data, writer)
+}
+
+// summaryWriter replaces fetched item lists with aggregate counts before writing them
+type summaryWriter struct {
+	writer output.Writer
+	cfg    *config.Config
+}
+
+// WriteToFile writes a summary of data to a file
+func (w *summaryWriter) WriteToFile(data interface{}, filename string) error {
+	return w.writer.WriteToFile(summarize(data, w.cfg), filename)
+}
+
+// WriteTo writes a summary of data to an io.Writer
+func (w *summaryWriter) WriteTo(data interface{}, writer io.Writer) error {
+	return w.writer.WriteTo(summarize(data, w.cfg), writer)
+}
+
+// summarize computes aggregate counts from a fetched slice: routes per network,
+// licenses by state, and devices per network (single network) or per organization (consolidated).
+// Data that is already aggregated is returned unchanged.
+func summarize(data interface{}, cfg *config.Config) interface{} {
+	counts := make(map[string]int)
+	var title, groupBy string
+	var total int
+
+	switch v := data.(type) {
+	case []meraki.Route:
+		title, groupBy, total = "Route Tables", "Network", len(v)
+		if len(v) > 0 {
+			counts[cfg.Network] = len(v)
+		}
+	case []meraki.RouteWithNetwork:
+		title, groupBy, total = "Route Tables", "Network", len(v)
+		for _, route := range v {
+			counts[labelOrID(route.NetworkName, route.NetworkID)]++
+		}
+	case []meraki.License:
+		title, groupBy, total = "Licenses", "State", len(v)
+		for _, license := range v {
+			counts[license.State]++
+		}
+	case []meraki.LicenseWithNetwork:
+		title, groupBy, total = "Licenses", "State", len(v)
+		for _, license := range v {
+			counts[license.State]++
+		}
+	case []meraki.Device:
+		title, groupBy, total = deviceSummaryTitle(cfg.Command), "Network", len(v)
+		for _, device := range v {
+			counts[device.NetworkID]++
+		}
+	case []meraki.DeviceWithNetwork:
+		title, groupBy, total = deviceSummaryTitle(cfg.Command), "Organization", len(v)
+		for _, device := range v {
+			counts[labelOrID(device.Organization, device.OrganizationID)]++
+		}
+	default:
+		return data
+	}
+
+	summary := output.Summary{
+		Title:   title,
+		GroupBy: groupBy,
+		Total:   total,
+		Counts:  make([]output.SummaryCount, 0, len(counts)),
+	}
+	for key, count := range counts {
+		summary.Counts = append(summary.Counts, output.SummaryCount{Key: key, Count: count})
+	}
+	sort.Slice(summary.Counts, func(i, j int) bool {
+		return summary.Counts[i].Key < summary.Counts[j].Key
+	})
+
+	return summary
+}
+
+// deviceSummaryTitle returns the summary title for a device command
+func deviceSummaryTitle(command string) string {
+	if command == "alerting" {
+		return "Alerting Devices"
+	}
+	return "Down Devices"
+}
+
+// labelOrID returns label if set, otherwise id
+func labelOrID(label, id string) string {
+	if label != "" {
+		return label
+	}
+	return id
+}
@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"meraki-info/internal/config"
+	"meraki-info/internal/meraki"
+)
+
+func TestApplyLimitOffset(t *testing.T) {
+	routes := make([]meraki.RouteWithNetwork, 200)
+	for i := range routes {
+		routes[i].GatewayVlan = i
+	}
+
+	tests := []struct {
+		name          string
+		limit         int
+		offset        int
+		expectedLen   int
+		expectedFirst int
+	}{
+		{name: "no limit or offset", limit: 0, offset: 0, expectedLen: 200, expectedFirst: 0},
+		{name: "offset and limit", limit: 50, offset: 100, expectedLen: 50, expectedFirst: 100},
+		{name: "offset without limit", limit: 0, offset: 150, expectedLen: 50, expectedFirst: 150},
+		{name: "limit without offset", limit: 10, offset: 0, expectedLen: 10, expectedFirst: 0},
+		{name: "limit past end", limit: 50, offset: 180, expectedLen: 20, expectedFirst: 180},
+		{name: "offset at end", limit: 10, offset: 200, expectedLen: 0},
+		{name: "offset beyond end", limit: 10, offset: 500, expectedLen: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, ok := applyLimitOffset(routes, tt.limit, tt.offset).([]meraki.RouteWithNetwork)
+			if !ok {
+				t.Fatal("Expected []meraki.RouteWithNetwork")
+			}
+			if len(page) != tt.expectedLen {
+				t.Fatalf("Expected %d records, got %d", tt.expectedLen, len(page))
+			}
+			if tt.expectedLen > 0 && page[0].GatewayVlan != tt.expectedFirst {
+				t.Errorf("Expected first record %d, got %d", tt.expectedFirst, page[0].GatewayVlan)
+			}
+		})
+	}
+
+	// Non-slice data passes through unchanged
+	if data := applyLimitOffset("not a slice", 1, 1); data != "not a slice" {
+		t.Errorf("Expected non-slice data unchanged, got %v", data)
+	}
+}
+
+func TestNewOutputWriter_FilenameTokensAndCompress(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{Command: "route-tables", OutputType: "json", Organization: "org1", Network: "N_1", Compress: true}
+	routes := []meraki.Route{{Subnet: "10.0.0.0/24", GatewayIP: "10.0.0.1"}}
+
+	pattern := filepath.Join(dir, "{command}-{org}-{network}-{date}.json")
+	if err := newOutputWriter(cfg).WriteToFile(routes, pattern); err != nil {
+		t.Fatalf("WriteToFile failed: %v", err)
+	}
+
+	expected := filepath.Join(dir, "route-tables-org1-N_1-"+time.Now().Format("2006-01-02")+".json.gz")
+	if _, err := os.Stat(expected); err != nil {
+		t.Errorf("Expected compressed file %s: %v", expected, err)
+	}
+}
+
+func TestNewOutputWriter_CSVDelimiter(t *testing.T) {
+	dir := t.TempDir()
+	primary := filepath.Join(dir, "routes.csv")
+	secondary := filepath.Join(dir, "routes-copy.csv")
+	cfg := &config.Config{Command: "route-tables", OutputType: "csv", CSVDelimiter: ';', SecondaryOutputs: []string{"csv:" + secondary}}
+	routes := []meraki.Route{{Name: "Office, 2nd floor", Subnet: "10.0.0.0/24", GatewayIP: "10.0.0.1"}}
+
+	if err := newOutputWriter(cfg).WriteToFile(routes, primary); err != nil {
+		t.Fatalf("WriteToFile failed: %v", err)
+	}
+
+	for _, path := range []string{primary, secondary} {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", path, err)
+		}
+		if !strings.HasPrefix(string(content), "ID;Name;Subnet;") || !strings.Contains(string(content), ";Office, 2nd floor;10.0.0.0/24;") {
+			t.Errorf("Expected semicolon-delimited CSV in %s, got:\n%s", path, content)
+		}
+	}
+}
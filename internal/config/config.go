@@ -2,27 +2,593 @@
 package config
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"net"
 	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
+
+	"meraki-info/internal/meraki"
+	"meraki-info/internal/output"
 )
 
+// DefaultAPIUsageTimespan is the window the api-usage command reports when -timespan is not set
+const DefaultAPIUsageTimespan = 24 * time.Hour
+
+// DefaultPerfTimespan is the window of the appliance performance scores the perf command reports when -timespan is not set
+const DefaultPerfTimespan = 30 * time.Minute
+
 // Config holds all configuration options for the application
 type Config struct {
-	Organization string
-	Network      string
-	APIKey       string
-	OutputFile   string
-	OutputType   string
-	LogLevel     string
-	Command      string // The command argument (access, route-tables, licenses, down)
-	InfoAll      bool
+	Organization    string
+	Network         string
+	APIKey          string
+	APIKeyFile      string // File holding the API key, so it stays out of shell history and ps
+	APIKeyKeychain  bool   // Read the API key from the OS keychain
+	BaseURL         string
+	OutputFile      string
+	OutputType      string
+	ConfigFile      string // YAML or TOML file supplying defaults for any flag
+	LogLevel        string
+	Command         string // The command argument (access, api-usage, change-log, route-tables, licenses, down, alerting, device, dhcp, events, firewall, locations, networks, perf, stacks, status-summary, switchports)
+	InfoAll         bool
+	VPNMode         string // Only include VPN routes from networks in this mode (hub, spoke, none)
+	Subnet          string // Only include routes equal to or within this CIDR
+	Gateway         string // Only include routes whose gateway IP equals this IP or is within this CIDR
+	IPVersion       int    // Only include routes of this IP version, 4 or 6 (0 means both)
+	EnabledOnly     bool   // Only include enabled routes
+	DisabledOnly    bool   // Only include disabled routes
+	Summary         bool   // Output aggregate counts instead of every item
+	ShowVersion     bool   // Print version information and exit
+	ListFormats     bool   // Print the supported output formats and exit
+	FailOnResults   bool   // Exit with status 2 when down/alerting finds any devices
+	Strict          bool   // Abort -all runs on the first organization or network that fails instead of skipping it
+	Subtotals       bool   // Insert per-organization subtotal lines in consolidated text output
+	GroupByNetwork  bool   // Print consolidated text routes under a header per network
+	NoLicenseFooter bool   // Omit the statistics footer from text license output
+	MergeLicenses   bool   // Collapse each organization's licenses into a single summary entry
+	NativeJSON      bool   // Write JSON with Meraki field names verbatim and context nested under meta
+	JSONEnvelope    bool   // Wrap JSON output in an object with generatedAt, count and items
+	IgnoreWarmSpare bool   // Suppress down warm spare appliances whose primary is online
+	HealthAlerts    bool   // Add devices with dashboard health alerts to the alerting report
+	NoDedup         bool   // Keep routes reported by several sources once per source instead of merging them
+	DetectOverlaps  bool   // Report pairs of routes with overlapping subnets instead of the routes
+	Compress        bool   // Gzip output files, appending .gz to their names
+	RunSummary      bool   // Report per-organization networks scanned/failed, items and API calls for -all runs
+	DiffAgainst     string // Previous JSON output to compare against, reporting only changed records and fields
+	Manifest        string // JSON file listing each output file written with its organization, network, records and size
+	Sort            string // Sort records by FIELD[:asc|desc] before writing
+	Proxy           string // Explicit proxy URL, overriding HTTP_PROXY/HTTPS_PROXY
+	ETagCache       string // File caching GET responses by ETag across runs
+	NoProxy         bool   // Connect directly, ignoring proxy environment variables
+	ConnectRetries  int    // Retries for establishing the first API connection (separate from status code retries)
+	MaxRetries      int    // Retries for 429, 5xx and network errors on API requests
+	Limit           int    // Maximum number of records to output (0 means no limit)
+	Offset          int    // Number of records to skip before output
+
+	// NoSyntheticNames leaves routes without an API name unnamed instead of generating placeholders
+	NoSyntheticNames bool
+
+	// NormalizeSubnets rewrites route subnets to their network address before deduplication
+	NormalizeSubnets bool
+
+	// DefaultRoutesOnly keeps only default routes (0.0.0.0/0 and ::/0) in route-tables output
+	DefaultRoutesOnly bool
+
+	// Fields limits text, JSON and CSV records to these fields; all fields when empty
+	Fields []string
+
+	// DaysUntilExpiry limits licenses to those expiring within this many days (-1 means no limit)
+	DaysUntilExpiry int
+
+	// ExpiresAfter and ExpiresBefore limit licenses to those expiring on or after, and before, these
+	// dates (UTC midnight). Zero when unset.
+	ExpiresAfter  time.Time
+	ExpiresBefore time.Time
+
+	// LicenseStates limits licenses to these states (active, inactive, expired, recentlyQueued, permanentlyQueued)
+	LicenseStates []string
+
+	// RetryMaxInterval caps the backoff between API request retries
+	RetryMaxInterval time.Duration
+
+	// RateLimit caps API requests per second across the whole run (0 means unlimited)
+	RateLimit float64
+
+	// DownLongerThan only reports down devices whose last report is older than this
+	DownLongerThan time.Duration
+
+	// DownStatuses are the device statuses the down command treats as down
+	DownStatuses []string
+
+	// Device filters for the down and alerting commands (AND semantics, case-insensitive)
+	ModelFilter       []string // Model prefixes or globs; a device matching any of them is kept
+	ProductTypeFilter []string // Product types; a device of any of them is kept
+	DeviceTag         string
+	SerialFilter      string // Device serial; serials are globally unique, so -all stops once it is found
+
+	// SecondaryOutputs holds additional TYPE:PATH destinations written alongside the primary output
+	SecondaryOutputs []string
+
+	// Tags selects networks (and, for down/alerting, devices or their networks) by Meraki tag.
+	// TagMatch is "all" (every tag required) or "any".
+	Tags     []string
+	TagMatch string
+
+	// NetworkTags restricts -all runs to networks carrying these tags, filtered by the API.
+	// NetworkTagMatch is "any" (one tag suffices) or "all".
+	NetworkTags     []string
+	NetworkTagMatch string
+
+	// ExcludeNetworks are the IDs or names (case-insensitive) of networks -all runs skip
+	ExcludeNetworks []string
+
+	// NetworksFile lists the network IDs or names to process, one per line, instead of -network or
+	// -all. NetworkList holds its entries.
+	NetworksFile string
+	NetworkList  []string
+
+	// OutputHeaders holds "Name: value" headers sent when -output is an HTTP(S) URL
+	OutputHeaders []string
+
+	// OutputMode is the permission of created output files, e.g. 0600 (0 keeps the default 0644)
+	OutputMode os.FileMode
+
+	// CSVDelimiter separates the fields of CSV output: ',' (the default), ';' or '\t'
+	CSVDelimiter rune
+	// CSVBOM starts CSV output with a UTF-8 byte order mark so that Excel detects the encoding
+	CSVBOM bool
+
+	// TemplateFile is the Go text/template that lays out -format template output
+	TemplateFile string
+
+	// OrganizationName is filled in once Organization has been resolved to an ID
+	OrganizationName string
+
+	// Organizations holds every -org value. A single value is also set as Organization; several are
+	// resolved by main into SelectedOrganizations, which -all runs and access cover instead of every organization.
+	Organizations         []string
+	SelectedOrganizations []meraki.Organization
+
+	// FilterRegex keeps only routes and devices whose name, or the name of their network when it is
+	// included in the output, matches this expression. Nil when -regex is not set.
+	FilterRegex *regexp.Regexp
+
+	// API usage options for the api-usage command. Timespan defaults to DefaultAPIUsageTimespan, or
+	// DefaultPerfTimespan for the perf command.
+	Timespan time.Duration // Window of API requests or performance scores to report, ending now
+	Detailed bool          // Page through the request log to list every request and the top admins and user agents
+
+	// IncludeL7 adds the layer 7 rules to the layer 3 rules of the firewall command
+	IncludeL7 bool
+
+	// ErroredOnly limits the switchports command to ports that are not connected or show errors
+	ErroredOnly bool
+
+	// Event filters for the events command. Since and Until are zero when unset.
+	EventTypes []string
+	Since      time.Time
+	Until      time.Time
+
+	// Admin limits the change-log command to changes by the administrator with this name, email or ID
+	Admin string
+
+	// Threshold limits the perf command to appliances whose performance score or uplink loss
+	// percentage is above it (-1 means no limit)
+	Threshold float64
+}
+
+// stringSliceFlag is a flag.Value that collects every occurrence of a repeatable flag
+type stringSliceFlag []string
+
+func (f *stringSliceFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringSliceFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// productTypeNames maps lowercased Meraki product types to their API spelling
+var productTypeNames = map[string]string{
+	"appliance":       "appliance",
+	"switch":          "switch",
+	"wireless":        "wireless",
+	"camera":          "camera",
+	"sensor":          "sensor",
+	"cellulargateway": "cellularGateway",
+}
+
+// licenseStateNames maps lowercased license states to their API spelling
+var licenseStateNames = map[string]string{
+	"active":            "active",
+	"inactive":          "inactive",
+	"expired":           "expired",
+	"recentlyqueued":    "recentlyQueued",
+	"permanentlyqueued": "permanentlyQueued",
+}
+
+// flagEnvVars maps flags to the environment variables that supply their defaults
+var flagEnvVars = map[string]string{
+	"org":         "MERAKI_ORG",
+	"network":     "MERAKI_NET",
+	"apikey":      "MERAKI_APIKEY",
+	"apikey-file": "MERAKI_APIKEY_FILE",
+	"base-url":    "MERAKI_BASE_URL",
+}
+
+// applyConfigFile sets flags from a YAML or TOML config file. Keys are flag names (underscores
+// may be used for hyphens). Flags given on the command line, and flags whose environment
+// variable is set, keep their values, giving the precedence flags > env > file > defaults.
+func applyConfigFile(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read -config file: %w", err)
+	}
+
+	var values map[string][]string
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		values, err = parseYAMLConfig(string(data))
+	case ".toml":
+		values, err = parseTOMLConfig(string(data))
+	default:
+		return fmt.Errorf("unsupported -config file '%s'. Use a .yaml, .yml or .toml file", filename)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid -config file %s: %w", filename, err)
+	}
+
+	setOnCommandLine := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		setOnCommandLine[f.Name] = true
+	})
+
+	for name, items := range values {
+		if name == "config" || flag.Lookup(name) == nil {
+			return fmt.Errorf("invalid -config file %s: unknown setting '%s'", filename, name)
+		}
+		if setOnCommandLine[name] || os.Getenv(flagEnvVars[name]) != "" {
+			continue
+		}
+		for _, item := range items {
+			if err := flag.Set(name, item); err != nil {
+				return fmt.Errorf("invalid -config file %s: setting '%s': %w", filename, name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseYAMLConfig parses the flat subset of YAML used by -config files: "key: value" lines,
+// with lists written inline as [a, b] or as "- item" lines under the key
+func parseYAMLConfig(content string) (map[string][]string, error) {
+	values := make(map[string][]string)
+	listKey := ""
+	for i, line := range strings.Split(content, "\n") {
+		line = stripConfigComment(line)
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed == "---" {
+			continue
+		}
+
+		if item, ok := strings.CutPrefix(trimmed, "- "); ok && listKey != "" {
+			values[listKey] = append(values[listKey], unquoteConfigValue(item))
+			continue
+		}
+		if line != strings.TrimLeft(line, " \t") {
+			return nil, fmt.Errorf("line %d: nested settings are not supported", i+1)
+		}
+
+		key, value, found := strings.Cut(trimmed, ":")
+		if !found {
+			return nil, fmt.Errorf("line %d: expected 'key: value'", i+1)
+		}
+		key = configKey(key)
+		value = strings.TrimSpace(value)
+		listKey = ""
+		if value == "" {
+			// A list of "- item" lines may follow
+			listKey = key
+			continue
+		}
+		values[key] = splitConfigValue(value)
+	}
+	return values, nil
+}
+
+// parseTOMLConfig parses the flat subset of TOML used by -config files: "key = value" lines,
+// with lists written as ["a", "b"]
+func parseTOMLConfig(content string) (map[string][]string, error) {
+	values := make(map[string][]string)
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(stripConfigComment(line))
+		if trimmed == "" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "[") {
+			return nil, fmt.Errorf("line %d: tables are not supported", i+1)
+		}
+
+		key, value, found := strings.Cut(trimmed, "=")
+		if !found {
+			return nil, fmt.Errorf("line %d: expected 'key = value'", i+1)
+		}
+		values[configKey(key)] = splitConfigValue(strings.TrimSpace(value))
+	}
+	return values, nil
+}
+
+// configKey normalizes a config file key to a flag name
+func configKey(key string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(key)), "_", "-")
+}
+
+// splitConfigValue unquotes a scalar value, or each item of a [a, b] list
+func splitConfigValue(value string) []string {
+	if inner, ok := strings.CutPrefix(value, "["); ok {
+		inner = strings.TrimSuffix(inner, "]")
+		var items []string
+		for _, item := range strings.Split(inner, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, unquoteConfigValue(item))
+			}
+		}
+		return items
+	}
+	return []string{unquoteConfigValue(value)}
+}
+
+// unquoteConfigValue strips matching single or double quotes from a value
+func unquoteConfigValue(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// stripConfigComment removes a # comment that is not inside a quoted value
+func stripConfigComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// errFlagParse wraps errors from parsing command line flags
+var errFlagParse = errors.New("invalid command line flags")
+
+// splitList splits a comma-separated flag value, dropping blank entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// resolveAPIKey replaces the API key with the one from -apikey-file (or MERAKI_APIKEY_FILE) or
+// the OS keychain, which take precedence over MERAKI_APIKEY. apikeySet reports whether -apikey was
+// given explicitly, on the command line or in the -config file. Errors never include the key.
+func resolveAPIKey(cfg *Config, apikeySet bool) error {
+	if cfg.APIKeyFile != "" && cfg.APIKeyKeychain {
+		return fmt.Errorf("cannot use -apikey-file and -apikey-keychain together")
+	}
+
+	if cfg.APIKeyFile != "" {
+		key, err := readAPIKeyFile(cfg.APIKeyFile)
+		if err != nil {
+			return err
+		}
+		if apikeySet && cfg.APIKey != key {
+			return fmt.Errorf("-apikey and -apikey-file supply different API keys. Use only one of them")
+		}
+		cfg.APIKey = key
+	}
+
+	if cfg.APIKeyKeychain {
+		if apikeySet {
+			return fmt.Errorf("cannot use -apikey and -apikey-keychain together")
+		}
+		key, err := keychain.Lookup(keychainService, keychainAccount)
+		if err != nil {
+			return fmt.Errorf("failed to read the API key from the OS keychain: %w", err)
+		}
+		if key == "" {
+			return fmt.Errorf("the OS keychain has no API key for service '%s', account '%s'", keychainService, keychainAccount)
+		}
+		cfg.APIKey = key
+	}
+
+	return nil
+}
+
+// readAPIKeyFile reads an API key from a file, trimming surrounding whitespace such as a
+// trailing newline
+func readAPIKeyFile(filename string) (string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return "", fmt.Errorf("failed to read -apikey-file: %w", err)
+	}
+
+	key := strings.TrimSpace(string(data))
+	if key == "" {
+		return "", fmt.Errorf("-apikey-file '%s' is empty", filename)
+	}
+	return key, nil
+}
+
+// sortRecords returns an empty slice of the records cfg's command writes, or nil when it does not
+// write a list of records. A single network, and each file of an -all run with -output FILE, holds
+// the plain records; the other -all runs, network patterns and -networks-file combine networks and
+// write them with their network and organization.
+func sortRecords(cfg *Config) interface{} {
+	oneFile := cfg.OutputFile == "" || cfg.OutputFile == "-" || output.IsURL(cfg.OutputFile)
+	consolidated := cfg.InfoAll && oneFile || meraki.IsNetworkPattern(cfg.Network) || len(cfg.NetworkList) > 0
+
+	switch cfg.Command {
+	case "route-tables":
+		if consolidated {
+			return []meraki.RouteWithNetwork{}
+		}
+		return []meraki.Route{}
+	case "down", "alerting":
+		if consolidated {
+			return []meraki.DeviceWithNetwork{}
+		}
+		return []meraki.Device{}
+	case "licenses":
+		// Licenses of matched or listed networks keep the plain records
+		if cfg.InfoAll && oneFile {
+			return []meraki.LicenseWithNetwork{}
+		}
+		return []meraki.License{}
+	case "status-summary":
+		return []meraki.DeviceStatusSummary{}
+	case "stacks":
+		return []meraki.SwitchStackWithNetwork{}
+	case "switchports":
+		return []meraki.SwitchPortStatusWithNetwork{}
+	case "dhcp":
+		return []meraki.DHCPSubnetWithNetwork{}
+	case "firewall":
+		return []meraki.FirewallRuleWithNetwork{}
+	case "networks":
+		return []meraki.NetworkWithOrganization{}
+	case "events":
+		return []meraki.EventWithNetwork{}
+	case "locations":
+		return []meraki.DeviceLocation{}
+	case "perf":
+		return []meraki.AppliancePerformance{}
+	case "api-usage":
+		return []meraki.APIUsage{}
+	case "change-log":
+		return []meraki.ConfigurationChangeWithOrganization{}
+	case "device":
+		return []meraki.DeviceDetails{}
+	default:
+		return nil
+	}
+}
+
+// readNetworksFile reads the network IDs or names listed in a -networks-file, one per line.
+// Blank lines and lines starting with # are skipped.
+func readNetworksFile(filename string) ([]string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read -networks-file: %w", err)
+	}
+
+	var networks []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		networks = append(networks, line)
+	}
+	if len(networks) == 0 {
+		return nil, fmt.Errorf("-networks-file '%s' does not list any networks", filename)
+	}
+	return networks, nil
+}
+
+// parseTimeFlag parses a -since/-until value as an RFC3339 timestamp or as a duration before
+// reference, such as 24h, 90m or 7d
+func parseTimeFlag(name, value string, reference time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	ago, err := parseDuration(value)
+	if err != nil || ago < 0 {
+		return time.Time{}, fmt.Errorf("invalid -%s '%s'. Use an RFC3339 time (e.g. 2025-07-17T22:00:00Z) or a duration ago (e.g. 24h, 7d)", name, value)
+	}
+
+	return reference.Add(-ago), nil
+}
+
+// parseDuration parses a Go duration such as 90m or 24h, or a whole number of days such as 7d
+func parseDuration(value string) (time.Duration, error) {
+	if days, found := strings.CutSuffix(value, "d"); found {
+		n, err := strconv.Atoi(days)
+		return time.Duration(n) * 24 * time.Hour, err
+	}
+	return time.ParseDuration(value)
+}
+
+// parseDateFlag parses a -expires-after/-expires-before value as a YYYY-MM-DD date at UTC midnight
+func parseDateFlag(name, value string) (time.Time, error) {
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid -%s '%s'. Use a date as YYYY-MM-DD (e.g. 2025-12-31)", name, value)
+	}
+	return t, nil
+}
+
+// ValidateSeveralOrganizations rejects the options that need a single organization when several are
+// selected, by repeating -org or, once resolved, by a comma-separated -org or MERAKI_ORG value
+func ValidateSeveralOrganizations(cfg *Config) error {
+	if cfg.NetworksFile != "" {
+		return fmt.Errorf("-networks-file lists the networks of a single organization and cannot be used with several -org values")
+	}
+	// A network belongs to a single organization, so several organizations need -all
+	if cfg.Network != "" {
+		return fmt.Errorf("cannot specify -network with several -org values. Use -all to process every network of the selected organizations")
+	}
+	return nil
+}
+
+// ParseSecondaryOutput splits a TYPE:PATH secondary output specification
+func ParseSecondaryOutput(spec string) (outputType, path string, err error) {
+	outputType, path, found := strings.Cut(spec, ":")
+	if !found || path == "" {
+		return "", "", fmt.Errorf("invalid -secondary-output '%s'. Expected TYPE:PATH, e.g. json:routes.json", spec)
+	}
+
+	outputType = strings.ToLower(outputType)
+	if _, ok := output.LookupFormat(outputType); !ok {
+		return "", "", fmt.Errorf("invalid -secondary-output type '%s'. Must be one of: %s", outputType, strings.Join(output.FormatNames(), ", "))
+	}
+
+	return outputType, path, nil
 }
 
 // ParseConfig parses command line arguments and environment variables
 func ParseConfig() *Config {
+	// Report flag errors ourselves so they exit 1, keeping exit status 2 for -fail-on-results
+	flag.CommandLine.Init(os.Args[0], flag.ContinueOnError)
+
 	cfg, err := parseConfigWithValidation()
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if errors.Is(err, errFlagParse) {
+		// The flag package has already printed the error and usage
+		os.Exit(1)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %s\n\n", err.Error())
 		printUsage()
@@ -37,6 +603,7 @@ func printUsage() {
 	fmt.Fprintf(os.Stderr, "OPTIONS:\n")
 
 	// Manually print each flag, with special handling for apikey
+	fmt.Fprintf(os.Stderr, "  -admin string\n    \tOnly include configuration changes by the administrator with this name, email or ID (change-log command)\n")
 	fmt.Fprintf(os.Stderr, "  -all\n    \tGet info for all networks. If -org specified, get info for all networks in that organization. If -org not specified, get info for all networks in all organizations.\n")
 
 	// Special handling for apikey
@@ -45,19 +612,117 @@ func printUsage() {
 		apikeyDescription += " (env MERAKI_APIKEY is set)"
 	}
 	fmt.Fprintf(os.Stderr, "  -apikey string\n    \t%s\n", apikeyDescription)
+	apikeyFileDescription := "File containing the Meraki API key, keeping it out of shell history and ps. Takes precedence over MERAKI_APIKEY"
+	if os.Getenv("MERAKI_APIKEY_FILE") != "" {
+		apikeyFileDescription += " (env MERAKI_APIKEY_FILE is set)"
+	}
+	fmt.Fprintf(os.Stderr, "  -apikey-file FILE\n    \t%s\n", apikeyFileDescription)
+	fmt.Fprintf(os.Stderr, "  -apikey-keychain\n    \tRead the Meraki API key from the OS keychain (service \"%s\", account \"%s\"), using security on macOS or secret-tool on Linux\n", keychainService, keychainAccount)
 
-	fmt.Fprintf(os.Stderr, "  -format string\n    \tOutput format: text, xml, json, csv (default \"text\")\n")
+	fmt.Fprintf(os.Stderr, "  -base-url string\n    \tMeraki API base URL for regional/government clouds (default \"https://api.meraki.com/api/v1\")\n")
+	fmt.Fprintf(os.Stderr, "  -compress\n    \tGzip output files as they are written, appending .gz to the filename\n")
+	fmt.Fprintf(os.Stderr, "  -config FILE\n    \tYAML (.yaml/.yml) or TOML (.toml) file with default flag values, e.g. 'org: 123456' or 'org = \"123456\"'. Precedence: flags, then environment, then file\n")
+	fmt.Fprintf(os.Stderr, "  -connect-retries int\n    \tRetry establishing the first API connection this many times, for cold starts\n")
+	fmt.Fprintf(os.Stderr, "  -csv-bom\n    \tStart CSV output with a UTF-8 byte order mark, so that Excel shows non-ASCII names correctly\n")
+	fmt.Fprintf(os.Stderr, "  -csv-delimiter string\n    \tField delimiter of CSV output, including csv -secondary-output files: comma, semicolon, tab (default \"comma\")\n")
+	fmt.Fprintf(os.Stderr, "  -days-until-expiry int\n    \tOnly include licenses expiring within this many days, including expired ones (licenses command)\n")
+	fmt.Fprintf(os.Stderr, "  -default-routes-only\n    \tOnly include default routes, 0.0.0.0/0 and ::/0, such as the internet route of each appliance (route-tables)\n")
+	fmt.Fprintf(os.Stderr, "  -detailed\n    \tAlso page through the API request log, listing every request and the top admins and user agents (api-usage command)\n")
+	fmt.Fprintf(os.Stderr, "  -detect-overlaps\n    \tReport the pairs of routes whose subnets overlap, across all networks, instead of the routes (route-tables)\n")
+	fmt.Fprintf(os.Stderr, "  -device-serial string\n    \tAlias for -serial\n")
+	fmt.Fprintf(os.Stderr, "  -device-tag string\n    \tOnly include down/alerting devices carrying this tag\n")
+	fmt.Fprintf(os.Stderr, "  -diff-against FILE\n    \tOutput only records and fields that changed since FILE, a previous JSON output of the same command\n")
+	fmt.Fprintf(os.Stderr, "  -disabled-only\n    \tOnly include disabled routes\n")
+	fmt.Fprintf(os.Stderr, "  -down-longer-than duration\n    \tOnly report down devices unreachable for longer than this, e.g. 1h or 30m\n")
+	fmt.Fprintf(os.Stderr, "  -down-statuses string\n    \tComma-separated device statuses the down command treats as down (default \"%s\")\n", strings.Join(meraki.DefaultDownStatuses, ","))
+	fmt.Fprintf(os.Stderr, "  -enabled-only\n    \tOnly include enabled routes\n")
+	fmt.Fprintf(os.Stderr, "  -errored-only\n    \tOnly include switch ports that are not connected, or that have dashboard errors, CRC align errors or collisions (switchports command)\n")
+	fmt.Fprintf(os.Stderr, "  -etag-cache FILE\n    \tCache API responses that carry an ETag in FILE and revalidate them with If-None-Match on later runs, reusing the cached body when unchanged\n")
+	fmt.Fprintf(os.Stderr, "  -event-type string\n    \tOnly include events of these comma-separated types (events command)\n")
+	fmt.Fprintf(os.Stderr, "  -exclude-network string\n    \tWith -all, skip the network with this ID or name (case-insensitive), e.g. a test environment. Repeatable\n")
+	fmt.Fprintf(os.Stderr, "  -expires-after YYYY-MM-DD\n    \tOnly include licenses expiring on or after this date (licenses command)\n")
+	fmt.Fprintf(os.Stderr, "  -expires-before YYYY-MM-DD\n    \tOnly include licenses expiring before this date; combine with -expires-after for a range (licenses command)\n")
+	fmt.Fprintf(os.Stderr, "  -fail-on-partial\n    \tDeprecated and ignored: a run that skipped organizations or networks that could not be scanned always exits with status 3\n")
+	fmt.Fprintf(os.Stderr, "  -fail-on-results\n    \tExit with status 2 when the down or alerting command finds any devices (0 when none, 1 on errors)\n")
+	fmt.Fprintf(os.Stderr, "  -fields FIELD1,FIELD2\n    \tOnly output these comma-separated fields of each record, e.g. Subnet,GatewayIP (text, json and csv formats)\n")
+	fmt.Fprintf(os.Stderr, "  -gateway IP|CIDR\n    \tOnly include routes whose next-hop gateway is this IP, or falls within this CIDR (route-tables command)\n")
+	fmt.Fprintf(os.Stderr, "  -group-by-network\n    \tPrint consolidated text routes under a header per network instead of one numbered list (route-tables)\n")
+	fmt.Fprintf(os.Stderr, "  -format string\n    \tOutput format: %s (default \"text\")\n", strings.Join(output.FormatNames(), ", "))
+	fmt.Fprintf(os.Stderr, "  -health-alerts\n    \tAlso fetch each network's health alerts and report the devices they name, once per device with its most severe alert (alerting command)\n")
+	fmt.Fprintf(os.Stderr, "  -ignore-warm-spare\n    \tOmit down warm spare appliances whose primary is online from the down report\n")
+	fmt.Fprintf(os.Stderr, "  -ip-version string\n    \tOnly include routes whose subnet is IPv4 or IPv6: 4, 6, both (default \"both\") (route-tables command)\n")
+	fmt.Fprintf(os.Stderr, "  -json-envelope\n    \tWrap JSON output in an object {\"generatedAt\": ..., \"count\": N, \"items\": [...]} instead of a bare array, including json -secondary-output files\n")
+	fmt.Fprintf(os.Stderr, "  -l7\n    \tAlso output layer 7 firewall rules (firewall command)\n")
+	fmt.Fprintf(os.Stderr, "  -layer string\n    \tFirewall rule layers to output: 3, or 7 to add the layer 7 rules like -l7 (default \"3\") (firewall command)\n")
+	fmt.Fprintf(os.Stderr, "  -license-state string\n    \tOnly include licenses in these comma-separated states: active, inactive, expired, recentlyQueued, permanentlyQueued (licenses command)\n")
+	fmt.Fprintf(os.Stderr, "  -limit int\n    \tMaximum number of records to output, 0 for no limit\n")
+	fmt.Fprintf(os.Stderr, "  -list-formats\n    \tPrint the supported output formats and exit\n")
 	fmt.Fprintf(os.Stderr, "  -loglevel string\n    \tLog level: debug, info, error (default \"error\")\n")
-	fmt.Fprintf(os.Stderr, "  -network string\n    \tMeraki network ID or name\n")
-	fmt.Fprintf(os.Stderr, "  -org string\n    \tMeraki organization ID or name\n")
-	fmt.Fprintf(os.Stderr, "  -output string\n    \tOutput file path. Use '-' or omit for stdout\n")
+	fmt.Fprintf(os.Stderr, "  -manifest FILE\n    \tWrite a JSON manifest listing each output file with its organization, network, record count and size, e.g. for -all runs writing a file per network; requires -output to be a file\n")
+	fmt.Fprintf(os.Stderr, "  -max-retries int\n    \tRetry API requests failing with 429, 5xx or network errors this many times (default %d)\n", meraki.DefaultRetryConfig().MaxRetries)
+	fmt.Fprintf(os.Stderr, "  -merge-org-licenses\n    \tCollapse each organization's licenses into a single entry with counts by state, soonest/latest expiration and total duration; with -all a single report is written even when -output is a file (licenses)\n")
+	fmt.Fprintf(os.Stderr, "  -model string\n    \tOnly include down/alerting devices whose model starts with, or matches a glob in, this comma-separated list (e.g. MX64,MR*)\n")
+	fmt.Fprintf(os.Stderr, "  -model-prefix string\n    \tAlias for -model, e.g. MX,MR\n")
+	fmt.Fprintf(os.Stderr, "  -no-dedup\n    \tKeep routes reported by several sources, e.g. a VLAN subnet that is also a static route, instead of merging those with the same subnet and gateway (route-tables)\n")
+	fmt.Fprintf(os.Stderr, "  -no-license-footer\n    \tOmit the footer of license counts by state, soonest/latest expiration and total duration from text output (licenses)\n")
+	fmt.Fprintf(os.Stderr, "  -no-synthetic-names\n    \tLeave routes without a name in the API unnamed instead of generating names such as \"Static Route 1\" (route-tables)\n")
+	fmt.Fprintf(os.Stderr, "  -no-proxy\n    \tConnect directly, ignoring HTTP_PROXY/HTTPS_PROXY\n")
+	fmt.Fprintf(os.Stderr, "  -native-json\n    \tWrite JSON with Meraki field names verbatim and organization/network under meta (implies -format json)\n")
+	fmt.Fprintf(os.Stderr, "  -network string\n    \tMeraki network ID, name, or glob pattern matching several names (e.g. 'Store-*')\n")
+	fmt.Fprintf(os.Stderr, "  -network-tag string\n    \tAlias for -network-tags\n")
+	fmt.Fprintf(os.Stderr, "  -network-tag-match string\n    \tWhether -network-tags requires any of the tags or all of them: any, all (default \"any\")\n")
+	fmt.Fprintf(os.Stderr, "  -network-tags string\n    \tWith -all, only process networks carrying any of these comma-separated tags, filtered by the API (e.g. production,branch)\n")
+	fmt.Fprintf(os.Stderr, "  -networks-file FILE\n    \tOnly process the networks of -org listed in FILE, one ID or name per line, instead of -network or -all. Entries that cannot be resolved are reported at the end\n")
+	fmt.Fprintf(os.Stderr, "  -normalize-subnets\n    \tRewrite route subnets such as 192.168.1.5/24 to their network address (192.168.1.0/24) before merging and output, leaving invalid ones untouched with a warning (route-tables)\n")
+	fmt.Fprintf(os.Stderr, "  -offset int\n    \tNumber of records to skip before output\n")
+	fmt.Fprintf(os.Stderr, "  -org string\n    \tMeraki organization ID or name. Repeatable or comma-separated to select several organizations\n")
+	fmt.Fprintf(os.Stderr, "  -output string\n    \tOutput file path, or an http(s):// URL to POST the output to. Use '-' or omit for stdout. The path may contain {date}, {time}, {org}, {network} and {command} tokens\n")
+	fmt.Fprintf(os.Stderr, "  -output-header 'Name: value'\n    \tHTTP header to send when -output is a URL. Repeatable\n")
+	fmt.Fprintf(os.Stderr, "  -output-mode MODE\n    \tOctal permission of created output files, e.g. 0600 for dumps containing license keys (default 0644)\n")
+	fmt.Fprintf(os.Stderr, "  -product-type string\n    \tOnly include down/alerting devices of these comma-separated product types: appliance, switch, wireless, camera, sensor, cellularGateway. For events, the single product type whose events to fetch; for networks, networks containing any of them\n")
+	fmt.Fprintf(os.Stderr, "  -proxy string\n    \tProxy URL for API requests, overriding HTTP_PROXY/HTTPS_PROXY\n")
+	fmt.Fprintf(os.Stderr, "  -rate-limit float\n    \tMaximum API requests per second, shared by all requests of the run including retries (0 for no limit; Meraki allows 10 per organization)\n")
+	fmt.Fprintf(os.Stderr, "  -regex PATTERN\n    \tOnly include routes and down/alerting devices whose name, or network name in -all output, matches this Go regular expression\n")
+	fmt.Fprintf(os.Stderr, "  -retry-max-interval duration\n    \tMaximum backoff between API request retries; each wait is a random duration up to the exponential interval (default %s)\n", meraki.DefaultRetryConfig().MaxInterval)
+	fmt.Fprintf(os.Stderr, "  -run-summary\n    \tWith -all, report networks scanned/failed, items found and API calls per organization\n")
+	fmt.Fprintf(os.Stderr, "  -secondary-output TYPE:PATH\n    \tAlso write output in another format to PATH ('-' for stdout). Repeatable\n")
+	fmt.Fprintf(os.Stderr, "  -serial string\n    \tOnly include the down/alerting device with this serial (case-insensitive), the device the device command outputs, or the switch whose ports switchports outputs; exits with status 4 when it is not found\n")
+	fmt.Fprintf(os.Stderr, "  -since string\n    \tOnly include events or configuration changes at or after this RFC3339 time or duration ago, e.g. 24h or 7d (events and change-log commands)\n")
+	fmt.Fprintf(os.Stderr, "  -sort FIELD[:asc|desc]\n    \tSort records before writing. Routes: Subnet, NetworkName, Organization, GatewayIP; devices: Serial, Name, Model, Status, LastReportedAt; licenses: ExpirationDate, State. NetworkName and Organization need consolidated output\n")
+	fmt.Fprintf(os.Stderr, "  -strict\n    \tAbort an -all run on the first organization or network that fails instead of skipping it and writing the rest; exits with status 1\n")
+	fmt.Fprintf(os.Stderr, "  -subnet CIDR\n    \tOnly include routes whose subnet equals or falls within this CIDR, e.g. 10.0.0.0/8\n")
+	fmt.Fprintf(os.Stderr, "  -subtotals\n    \tInsert per-organization subtotal lines in consolidated text output\n")
+	fmt.Fprintf(os.Stderr, "  -summary\n    \tOutput aggregate counts (routes per network, licenses by state, devices per network/org) instead of every item\n")
+	fmt.Fprintf(os.Stderr, "  -tag string\n    \tOnly include networks, and down/alerting devices or their networks, carrying this tag. Repeatable\n")
+	fmt.Fprintf(os.Stderr, "  -tag-match string\n    \tWhether -tag requires all tags or any of them: all, any (default \"all\")\n")
+	fmt.Fprintf(os.Stderr, "  -template FILE\n    \tGo text/template laying out -format template output, executed with .Items (the records) and .Meta (.Command, .Organization, .GeneratedAt); functions: join, formatTime, pad, padLeft\n")
+	fmt.Fprintf(os.Stderr, "  -threshold float\n    \tOnly include appliances whose performance score or uplink loss percentage is above this value (perf command)\n")
+	fmt.Fprintf(os.Stderr, "  -timespan duration\n    \tWindow of API requests or appliance performance scores to report, ending now, e.g. 1h or 7d; at most 31d for api-usage (default %s) and 14d for perf (default %s)\n", DefaultAPIUsageTimespan, DefaultPerfTimespan)
+	fmt.Fprintf(os.Stderr, "  -until string\n    \tOnly include events or configuration changes before this RFC3339 time or duration ago (events and change-log commands)\n")
+	fmt.Fprintf(os.Stderr, "  -version\n    \tPrint version information and exit\n")
+	fmt.Fprintf(os.Stderr, "  -vpn-mode string\n    \tOnly include VPN routes from networks in this site-to-site VPN mode: hub, spoke, none\n")
 
 	fmt.Fprintf(os.Stderr, "\nCOMMANDS:\n")
 	fmt.Fprintf(os.Stderr, "  access        Show available organizations and networks for the API key\n")
 	fmt.Fprintf(os.Stderr, "  alerting      Output all devices that are alerting\n")
+	fmt.Fprintf(os.Stderr, "  api-usage     Output API request counts by response code, including 429 rate limiting, per organization\n")
+	fmt.Fprintf(os.Stderr, "  change-log    Output the configuration change log of organizations or a single network\n")
+	fmt.Fprintf(os.Stderr, "  device        Output the details of the device selected with -serial\n")
+	fmt.Fprintf(os.Stderr, "  dhcp          Output DHCP server/relay settings of appliance VLANs and switch stack interfaces\n")
 	fmt.Fprintf(os.Stderr, "  down          Output all devices that are down/offline\n")
+	fmt.Fprintf(os.Stderr, "  events        Output the event log of a single network\n")
+	fmt.Fprintf(os.Stderr, "  firewall      Output appliance L3 firewall rules, and L7 rules with -l7\n")
+	fmt.Fprintf(os.Stderr, "  firewall-rules  Alias for firewall\n")
 	fmt.Fprintf(os.Stderr, "  licenses      Output license information\n")
+	fmt.Fprintf(os.Stderr, "  locations     Output devices placed on the map, grouped by network; -format geojson for mapping tools\n")
+	fmt.Fprintf(os.Stderr, "  networks      Output a flat list of networks with their product types, time zone, tags and notes\n")
+	fmt.Fprintf(os.Stderr, "  perf          Output MX appliance performance scores with uplink loss and latency\n")
+	fmt.Fprintf(os.Stderr, "  port-statuses  Alias for switchports\n")
 	fmt.Fprintf(os.Stderr, "  route-tables  Output route tables\n")
+	fmt.Fprintf(os.Stderr, "  stacks        Output switch stacks and their member serials\n")
+	fmt.Fprintf(os.Stderr, "  status-summary  Output device status counts per network and product type\n")
+	fmt.Fprintf(os.Stderr, "  switchports   Output switch port statuses with their error counters\n")
+	fmt.Fprintf(os.Stderr, "  version       Print version information\n")
 }
 
 // parseConfigWithValidation parses config and returns validation errors (for testing)
@@ -65,27 +730,155 @@ func parseConfigWithValidation() (*Config, error) {
 	cfg := &Config{}
 
 	// Define command line flags (options only, not commands)
-	flag.StringVar(&cfg.Organization, "org", os.Getenv("MERAKI_ORG"), "Meraki organization ID or name")
-	flag.StringVar(&cfg.Network, "network", os.Getenv("MERAKI_NET"), "Meraki network ID or name")
+	flag.StringVar(&cfg.ConfigFile, "config", "", "YAML or TOML file with default flag values; flags and environment variables override it")
+	var organizations stringSliceFlag
+	flag.Var(&organizations, "org", "Meraki organization ID or name. Repeatable or comma-separated")
+	flag.StringVar(&cfg.Network, "network", os.Getenv("MERAKI_NET"), "Meraki network ID, name, or glob pattern")
 
 	// Special handling for apikey to not show default in usage
 	apikeyDefault := os.Getenv("MERAKI_APIKEY")
 	flag.StringVar(&cfg.APIKey, "apikey", apikeyDefault, "Meraki API key")
+	flag.StringVar(&cfg.APIKeyFile, "apikey-file", os.Getenv("MERAKI_APIKEY_FILE"), "File containing the Meraki API key")
+	flag.BoolVar(&cfg.APIKeyKeychain, "apikey-keychain", false, "Read the Meraki API key from the OS keychain")
 
-	flag.StringVar(&cfg.OutputFile, "output", "", "Output file path. Use '-' or omit for stdout")
-	flag.StringVar(&cfg.OutputType, "format", "text", "Output format: text, xml, json, csv")
+	flag.StringVar(&cfg.BaseURL, "base-url", os.Getenv("MERAKI_BASE_URL"), "Meraki API base URL for regional/government clouds")
+	flag.StringVar(&cfg.OutputFile, "output", "", "Output file path, or an http(s):// URL to POST the output to. Use '-' or omit for stdout")
+	flag.Var((*stringSliceFlag)(&cfg.OutputHeaders), "output-header", "HTTP header to send when -output is a URL, as 'Name: value'. Repeatable")
+	flag.StringVar(&cfg.OutputType, "format", "text", "Output format: "+strings.Join(output.FormatNames(), ", "))
 	flag.StringVar(&cfg.LogLevel, "loglevel", "error", "Log level: debug, info, error")
+	flag.StringVar(&cfg.VPNMode, "vpn-mode", "", "Only include VPN routes from networks in this site-to-site VPN mode: hub, spoke, none")
+	flag.BoolVar(&cfg.EnabledOnly, "enabled-only", false, "Only include enabled routes")
+	flag.BoolVar(&cfg.DisabledOnly, "disabled-only", false, "Only include disabled routes")
+	flag.StringVar(&cfg.Subnet, "subnet", "", "Only include routes whose subnet equals or falls within this CIDR, e.g. 10.0.0.0/8")
+	flag.StringVar(&cfg.Gateway, "gateway", "", "Only include routes whose next-hop gateway is this IP, or falls within this CIDR (route-tables command)")
+	ipVersion := flag.String("ip-version", "both", "Only include routes whose subnet is IPv4 or IPv6: 4, 6, both (route-tables command)")
+	outputMode := flag.String("output-mode", "", "Octal permission of created output files, e.g. 0600")
+	csvDelimiter := flag.String("csv-delimiter", "comma", "Field delimiter of CSV output: comma, semicolon, tab")
+	flag.BoolVar(&cfg.CSVBOM, "csv-bom", false, "Start CSV output with a UTF-8 byte order mark, for Excel")
+	flag.StringVar(&cfg.TemplateFile, "template", "", "Go text/template laying out -format template output")
+	flag.Var((*stringSliceFlag)(&cfg.SecondaryOutputs), "secondary-output", "Also write output in another format to PATH, as TYPE:PATH. Repeatable")
+	var models, modelPrefixes string
+	flag.StringVar(&models, "model", "", "Only include down/alerting devices whose model starts with, or matches a glob in, this comma-separated list")
+	flag.StringVar(&modelPrefixes, "model-prefix", "", "Alias for -model, e.g. MX,MR")
+	var licenseStates string
+	flag.StringVar(&licenseStates, "license-state", "", "Only include licenses in these comma-separated states (licenses command)")
+	var networkTags, networkTag string
+	flag.StringVar(&networkTags, "network-tags", "", "With -all, only process networks carrying any of these comma-separated tags")
+	flag.StringVar(&networkTag, "network-tag", "", "Alias for -network-tags")
+	flag.StringVar(&cfg.NetworkTagMatch, "network-tag-match", "any", "Whether -network-tags requires any of the tags or all of them: any, all")
+	flag.StringVar(&cfg.NetworksFile, "networks-file", "", "Only process the networks of -org listed in this file, one ID or name per line")
+	flag.Var((*stringSliceFlag)(&cfg.ExcludeNetworks), "exclude-network", "With -all, skip the network with this ID or name. Repeatable")
+	var productTypes string
+	flag.StringVar(&productTypes, "product-type", "", "Only include down/alerting devices of these comma-separated product types")
+	flag.StringVar(&cfg.DeviceTag, "device-tag", "", "Only include down/alerting devices carrying this tag")
+	filterRegex := flag.String("regex", "", "Only include routes and down/alerting devices whose name matches this Go regular expression")
+	flag.StringVar(&cfg.SerialFilter, "serial", "", "Only include the down/alerting device with this serial, the device to output (device command), or the switch whose ports to output (switchports)")
+	flag.StringVar(&cfg.SerialFilter, "device-serial", "", "Alias for -serial")
+	flag.Float64Var(&cfg.RateLimit, "rate-limit", 0, "Maximum API requests per second, shared by all requests of the run (0 for no limit)")
+	flag.IntVar(&cfg.MaxRetries, "max-retries", meraki.DefaultRetryConfig().MaxRetries, "Retry API requests failing with 429, 5xx or network errors this many times")
+	flag.DurationVar(&cfg.RetryMaxInterval, "retry-max-interval", meraki.DefaultRetryConfig().MaxInterval, "Maximum backoff between API request retries")
+	flag.DurationVar(&cfg.DownLongerThan, "down-longer-than", 0, "Only report down devices unreachable for longer than this, e.g. 1h")
+	downStatuses := flag.String("down-statuses", strings.Join(meraki.DefaultDownStatuses, ","), "Comma-separated device statuses the down command treats as down")
+	flag.StringVar(&cfg.Proxy, "proxy", "", "Proxy URL for API requests, overriding HTTP_PROXY/HTTPS_PROXY")
+	flag.StringVar(&cfg.ETagCache, "etag-cache", "", "Cache API responses by ETag in this file and revalidate them on later runs")
+	flag.BoolVar(&cfg.NoProxy, "no-proxy", false, "Connect directly, ignoring HTTP_PROXY/HTTPS_PROXY")
+	flag.IntVar(&cfg.ConnectRetries, "connect-retries", 0, "Retry establishing the first API connection this many times, for cold starts")
+	flag.IntVar(&cfg.DaysUntilExpiry, "days-until-expiry", -1, "Only include licenses expiring within this many days, including expired ones (licenses command)")
+	var expiresAfter, expiresBefore string
+	flag.StringVar(&expiresAfter, "expires-after", "", "Only include licenses expiring on or after this date, as YYYY-MM-DD (licenses command)")
+	flag.StringVar(&expiresBefore, "expires-before", "", "Only include licenses expiring before this date, as YYYY-MM-DD (licenses command)")
+	flag.IntVar(&cfg.Limit, "limit", 0, "Maximum number of records to output, 0 for no limit")
+	flag.IntVar(&cfg.Offset, "offset", 0, "Number of records to skip before output")
+	flag.StringVar(&cfg.Sort, "sort", "", "Sort records before writing, as FIELD[:asc|desc]")
+	var fields string
+	flag.StringVar(&fields, "fields", "", "Only output these comma-separated fields of each record (text, json and csv formats)")
+	flag.Var((*stringSliceFlag)(&cfg.Tags), "tag", "Only include networks, and down/alerting devices or their networks, carrying this tag. Repeatable")
+	flag.StringVar(&cfg.TagMatch, "tag-match", "all", "Whether -tag requires all tags or any of them: all, any")
+	var eventTypes, since, until string
+	flag.StringVar(&eventTypes, "event-type", "", "Only include events of these comma-separated types")
+	flag.StringVar(&since, "since", "", "Only include events or configuration changes at or after this RFC3339 time or duration ago, e.g. 24h")
+	flag.StringVar(&until, "until", "", "Only include events or configuration changes before this RFC3339 time or duration ago, e.g. 1h")
+	flag.StringVar(&cfg.Admin, "admin", "", "Only include configuration changes by the administrator with this name, email or ID (change-log command)")
+	timespan := flag.String("timespan", "", "Window of API requests or appliance performance scores to report, ending now, e.g. 1h or 7d (api-usage and perf commands)")
+	flag.Float64Var(&cfg.Threshold, "threshold", -1, "Only include appliances whose performance score or uplink loss percentage is above this value (perf command)")
+	flag.BoolVar(&cfg.IncludeL7, "l7", false, "Also output layer 7 firewall rules (firewall command)")
+	flag.BoolVar(&cfg.ErroredOnly, "errored-only", false, "Only include switch ports that are not connected or have errors, CRC align errors or collisions (switchports command)")
+	layer := flag.String("layer", "", "Firewall rule layers to output: 3, or 7 to add the layer 7 rules (firewall command)")
+	flag.BoolVar(&cfg.Detailed, "detailed", false, "Also page through the API request log, listing every request and the top admins and user agents (api-usage command)")
+	flag.StringVar(&cfg.Manifest, "manifest", "", "Write a JSON manifest of the output files written, with their organization, network, record count and size")
+	flag.StringVar(&cfg.DiffAgainst, "diff-against", "", "Output only records and fields that changed since FILE, a previous JSON output of the same command")
+	flag.BoolVar(&cfg.RunSummary, "run-summary", false, "With -all, report networks scanned/failed, items found and API calls per organization")
+	flag.BoolVar(&cfg.Compress, "compress", false, "Gzip output files as they are written, appending .gz to the filename")
+	flag.BoolVar(&cfg.NoSyntheticNames, "no-synthetic-names", false, "Leave routes without a name in the API unnamed instead of generating placeholder names (route-tables)")
+	flag.BoolVar(&cfg.DetectOverlaps, "detect-overlaps", false, "Report the pairs of routes whose subnets overlap instead of the routes (route-tables)")
+	flag.BoolVar(&cfg.DefaultRoutesOnly, "default-routes-only", false, "Only include default routes, 0.0.0.0/0 and ::/0 (route-tables)")
+	flag.BoolVar(&cfg.NormalizeSubnets, "normalize-subnets", false, "Rewrite route subnets with host bits set to their network address before merging and output (route-tables)")
+	flag.BoolVar(&cfg.NoDedup, "no-dedup", false, "Keep routes reported by several sources instead of merging those with the same subnet and gateway (route-tables)")
+	flag.BoolVar(&cfg.NoLicenseFooter, "no-license-footer", false, "Omit the license statistics footer from text output (licenses)")
+	flag.BoolVar(&cfg.MergeLicenses, "merge-org-licenses", false, "Collapse each organization's licenses into a single summary entry (licenses)")
+	flag.BoolVar(&cfg.HealthAlerts, "health-alerts", false, "Also report devices with dashboard health alerts, annotated with their most severe alert (alerting)")
+	flag.BoolVar(&cfg.IgnoreWarmSpare, "ignore-warm-spare", false, "Omit down warm spare appliances whose primary is online from the down report")
+	flag.BoolVar(&cfg.JSONEnvelope, "json-envelope", false, "Wrap JSON output in an object with generatedAt, count and items instead of a bare array")
+	flag.BoolVar(&cfg.NativeJSON, "native-json", false, "Write JSON with Meraki field names verbatim and organization/network under meta (implies -format json)")
+	// Partial runs exit with status 3 by default; the flag is still accepted so existing scripts keep working
+	flag.Bool("fail-on-partial", false, "Deprecated and ignored: partial runs always exit with status 3")
+	flag.BoolVar(&cfg.Strict, "strict", false, "Abort an -all run on the first organization or network that fails instead of skipping it")
+	flag.BoolVar(&cfg.FailOnResults, "fail-on-results", false, "Exit with status 2 when the down or alerting command finds any devices")
+	flag.BoolVar(&cfg.Subtotals, "subtotals", false, "Insert per-organization subtotal lines in consolidated text output")
+	flag.BoolVar(&cfg.GroupByNetwork, "group-by-network", false, "Print consolidated text routes under a header per network (route-tables)")
+	flag.BoolVar(&cfg.Summary, "summary", false, "Output aggregate counts instead of every item")
+	flag.BoolVar(&cfg.ShowVersion, "version", false, "Print version information and exit")
+	flag.BoolVar(&cfg.ListFormats, "list-formats", false, "Print the supported output formats and exit")
 	flag.BoolVar(&cfg.InfoAll, "all", false, "Get info for all networks. If -org specified, get info for all networks in that organization. If -org not specified, get info for all networks in all organizations.")
 
 	// Custom usage function
 	flag.Usage = printUsage
 
-	flag.Parse()
+	if err := flag.CommandLine.Parse(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %w", errFlagParse, err)
+	}
+
+	if cfg.ConfigFile != "" {
+		if err := applyConfigFile(cfg.ConfigFile); err != nil {
+			return nil, err
+		}
+	}
+
+	// -org is repeatable, so MERAKI_ORG applies only when no -org was given
+	if len(organizations) == 0 {
+		organizations = stringSliceFlag{os.Getenv("MERAKI_ORG")}
+	}
+	// Values are kept whole, as organization names may contain commas; a single value listing several
+	// organizations is split when it is resolved
+	for _, organization := range organizations {
+		if organization = strings.TrimSpace(organization); organization != "" {
+			cfg.Organizations = append(cfg.Organizations, organization)
+		}
+	}
+	if len(cfg.Organizations) == 1 {
+		cfg.Organization = cfg.Organizations[0]
+	}
 
 	// Get the command from positional arguments (after options)
 	args := flag.Args()
+
+	// Version needs neither an API key nor a command
+	if cfg.ShowVersion || (len(args) == 1 && strings.ToLower(args[0]) == "version") {
+		cfg.Command = "version"
+		return cfg, nil
+	}
+
+	// Listing formats, like version, needs neither an API key nor a command
+	if cfg.ListFormats {
+		cfg.Command = "list-formats"
+		return cfg, nil
+	}
+
 	if len(args) == 0 {
-		return nil, fmt.Errorf("command is required. Must be one of: access, alerting, down, licenses, route-tables")
+		return nil, fmt.Errorf("command is required. Must be one of: access, alerting, api-usage, change-log, device, dhcp, down, events, firewall, firewall-rules, licenses, locations, networks, perf, port-statuses, route-tables, stacks, status-summary, switchports")
 	}
 	if len(args) > 1 {
 		return nil, fmt.Errorf("only one command is allowed, got: %s", strings.Join(args, ", "))
@@ -93,26 +886,495 @@ func parseConfigWithValidation() (*Config, error) {
 
 	command := strings.ToLower(args[0])
 	switch command {
-	case "access", "route-tables", "licenses", "down", "alerting":
+	case "access", "api-usage", "change-log", "route-tables", "licenses", "down", "alerting", "device", "dhcp", "events", "firewall", "locations", "networks", "perf", "stacks", "status-summary", "switchports":
 		cfg.Command = command
+	case "firewall-rules":
+		cfg.Command = "firewall"
+	case "port-statuses":
+		cfg.Command = "switchports"
 	default:
-		return nil, fmt.Errorf("invalid command '%s'. Must be one of: access, alerting, down, licenses, route-tables", args[0])
+		return nil, fmt.Errorf("invalid command '%s'. Must be one of: access, alerting, api-usage, change-log, device, dhcp, down, events, firewall, firewall-rules, licenses, locations, networks, perf, port-statuses, route-tables, stacks, status-summary, switchports", args[0])
+	}
+
+	cfg.VPNMode = strings.ToLower(cfg.VPNMode)
+	switch cfg.VPNMode {
+	case "", "hub", "spoke", "none":
+	default:
+		return nil, fmt.Errorf("invalid -vpn-mode '%s'. Must be one of: hub, spoke, none", cfg.VPNMode)
+	}
+
+	cfg.ModelFilter = append(splitList(models), splitList(modelPrefixes)...)
+
+	for _, productType := range splitList(productTypes) {
+		canonical, ok := productTypeNames[strings.ToLower(productType)]
+		if !ok {
+			return nil, fmt.Errorf("invalid -product-type '%s'. Must be one of: appliance, switch, wireless, camera, sensor, cellularGateway", productType)
+		}
+		cfg.ProductTypeFilter = append(cfg.ProductTypeFilter, canonical)
+	}
+
+	for _, state := range splitList(licenseStates) {
+		canonical, ok := licenseStateNames[strings.ToLower(state)]
+		if !ok {
+			return nil, fmt.Errorf("invalid -license-state '%s'. Must be one of: active, inactive, expired, recentlyQueued, permanentlyQueued", state)
+		}
+		cfg.LicenseStates = append(cfg.LicenseStates, canonical)
+	}
+	if len(cfg.LicenseStates) > 0 && cfg.Command != "licenses" {
+		return nil, fmt.Errorf("-license-state can only be used with the licenses command")
+	}
+	if cfg.NoLicenseFooter && cfg.Command != "licenses" {
+		return nil, fmt.Errorf("-no-license-footer can only be used with the licenses command")
+	}
+	if cfg.HealthAlerts && cfg.Command != "alerting" {
+		return nil, fmt.Errorf("-health-alerts can only be used with the alerting command")
+	}
+	if cfg.MergeLicenses && cfg.Command != "licenses" {
+		return nil, fmt.Errorf("-merge-org-licenses can only be used with the licenses command")
+	}
+
+	cfg.NetworkTags = append(splitList(networkTags), splitList(networkTag)...)
+	cfg.EventTypes = splitList(eventTypes)
+	reference := time.Now()
+	if since != "" {
+		t, err := parseTimeFlag("since", since, reference)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Since = t
+	}
+	if until != "" {
+		t, err := parseTimeFlag("until", until, reference)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Until = t
+	}
+	if !cfg.Since.IsZero() && !cfg.Until.IsZero() && !cfg.Since.Before(cfg.Until) {
+		return nil, fmt.Errorf("-since must be before -until")
+	}
+
+	if cfg.Admin != "" && cfg.Command != "change-log" {
+		return nil, fmt.Errorf("-admin can only be used with the change-log command")
+	}
+	if *timespan != "" && cfg.Command != "api-usage" && cfg.Command != "perf" {
+		return nil, fmt.Errorf("-timespan can only be used with the perf and api-usage commands")
+	}
+	if cfg.Detailed && cfg.Command != "api-usage" {
+		return nil, fmt.Errorf("-detailed can only be used with the api-usage command")
+	}
+	if cfg.Command == "perf" {
+		cfg.Timespan = DefaultPerfTimespan
+		if *timespan != "" {
+			d, err := parseDuration(*timespan)
+			if err != nil || d < time.Second || d > meraki.MaxAppliancePerformanceTimespan {
+				return nil, fmt.Errorf("invalid -timespan '%s'. Use a duration between 1s and 14d, e.g. 30m or 1d", *timespan)
+			}
+			cfg.Timespan = d
+		}
+	}
+	if cfg.Threshold != -1 {
+		if cfg.Command != "perf" {
+			return nil, fmt.Errorf("-threshold can only be used with the perf command")
+		}
+		if cfg.Threshold < 0 {
+			return nil, fmt.Errorf("-threshold must not be negative")
+		}
+	}
+	if cfg.Command == "api-usage" {
+		cfg.Timespan = DefaultAPIUsageTimespan
+		if *timespan != "" {
+			d, err := parseDuration(*timespan)
+			if err != nil || d < time.Second || d > meraki.MaxAPIUsageTimespan {
+				return nil, fmt.Errorf("invalid -timespan '%s'. Use a duration between 1s and 31d, e.g. 1h or 7d", *timespan)
+			}
+			cfg.Timespan = d
+		}
+	}
+
+	if cfg.ErroredOnly && cfg.Command != "switchports" {
+		return nil, fmt.Errorf("-errored-only can only be used with the switchports command")
+	}
+
+	if cfg.IncludeL7 && cfg.Command != "firewall" {
+		return nil, fmt.Errorf("-l7 can only be used with the firewall command")
+	}
+
+	switch *layer {
+	case "":
+	case "3", "7":
+		if cfg.Command != "firewall" {
+			return nil, fmt.Errorf("-layer can only be used with the firewall command")
+		}
+		cfg.IncludeL7 = cfg.IncludeL7 || *layer == "7"
+	default:
+		return nil, fmt.Errorf("invalid -layer '%s'. Must be one of: 3, 7", *layer)
+	}
+
+	// Firewall rules are evaluated top to bottom, so their order is kept
+	if cfg.Sort != "" && cfg.Command == "firewall" {
+		return nil, fmt.Errorf("-sort cannot be used with the firewall command, whose rules are output in evaluation order")
+	}
+
+	if cfg.NativeJSON {
+		// -native-json implies JSON output; only the default text format may be overridden
+		switch strings.ToLower(cfg.OutputType) {
+		case "text", "json":
+			cfg.OutputType = "json"
+		default:
+			return nil, fmt.Errorf("-native-json cannot be used with -format %s", cfg.OutputType)
+		}
+	}
+
+	if *filterRegex != "" {
+		re, err := regexp.Compile(*filterRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -regex '%s': %w", *filterRegex, err)
+		}
+		switch cfg.Command {
+		case "route-tables", "down", "alerting":
+			cfg.FilterRegex = re
+		default:
+			return nil, fmt.Errorf("-regex can only be used with the route-tables, down and alerting commands")
+		}
+	}
+
+	switch cfg.Command {
+	case "down", "alerting", "device", "switchports":
+	default:
+		if cfg.SerialFilter != "" {
+			return nil, fmt.Errorf("-serial can only be used with the down, alerting, device and switchports commands")
+		}
+	}
+
+	if cfg.NoDedup && cfg.Command != "route-tables" {
+		return nil, fmt.Errorf("-no-dedup can only be used with the route-tables command")
+	}
+
+	if cfg.DefaultRoutesOnly && cfg.Command != "route-tables" {
+		return nil, fmt.Errorf("-default-routes-only can only be used with the route-tables command")
+	}
+
+	if cfg.NormalizeSubnets && cfg.Command != "route-tables" {
+		return nil, fmt.Errorf("-normalize-subnets can only be used with the route-tables command")
+	}
+
+	if cfg.NoSyntheticNames && cfg.Command != "route-tables" {
+		return nil, fmt.Errorf("-no-synthetic-names can only be used with the route-tables command")
+	}
+
+	if cfg.DetectOverlaps && cfg.Command != "route-tables" {
+		return nil, fmt.Errorf("-detect-overlaps can only be used with the route-tables command")
+	}
+
+	if cfg.GroupByNetwork && cfg.Command != "route-tables" {
+		return nil, fmt.Errorf("-group-by-network can only be used with the route-tables command")
+	}
+
+	// Prometheus metrics are only defined for devices and licenses
+	if strings.EqualFold(cfg.OutputType, "prometheus") {
+		switch cfg.Command {
+		case "down", "alerting", "licenses":
+		default:
+			return nil, fmt.Errorf("-format prometheus can only be used with the down, alerting and licenses commands")
+		}
+		if cfg.Summary || cfg.RunSummary || cfg.DiffAgainst != "" {
+			return nil, fmt.Errorf("-format prometheus cannot be used with -summary, -run-summary or -diff-against")
+		}
+	}
+
+	// GeoJSON features are points, so only devices can be written as GeoJSON
+	if strings.EqualFold(cfg.OutputType, "geojson") {
+		switch cfg.Command {
+		case "locations", "down", "alerting":
+		default:
+			return nil, fmt.Errorf("-format geojson can only be used with the locations, down and alerting commands")
+		}
+		if cfg.Summary || cfg.RunSummary || cfg.DiffAgainst != "" {
+			return nil, fmt.Errorf("-format geojson cannot be used with -summary, -run-summary or -diff-against")
+		}
+	}
+
+	cfg.Fields = splitList(fields)
+	if len(cfg.Fields) > 0 {
+		switch strings.ToLower(cfg.OutputType) {
+		case "text", "json", "csv":
+		default:
+			return nil, fmt.Errorf("-fields can only be used with -format text, json or csv")
+		}
+	}
+
+	for _, status := range splitList(*downStatuses) {
+		cfg.DownStatuses = append(cfg.DownStatuses, strings.ToLower(status))
+	}
+	if len(cfg.DownStatuses) == 0 {
+		return nil, fmt.Errorf("-down-statuses must list at least one status")
+	}
+
+	cfg.TagMatch = strings.ToLower(cfg.TagMatch)
+	switch cfg.TagMatch {
+	case "all", "any":
+	default:
+		return nil, fmt.Errorf("invalid -tag-match '%s'. Must be one of: all, any", cfg.TagMatch)
+	}
+	cfg.NetworkTagMatch = strings.ToLower(cfg.NetworkTagMatch)
+	switch cfg.NetworkTagMatch {
+	case "all", "any":
+	default:
+		return nil, fmt.Errorf("invalid -network-tag-match '%s'. Must be one of: any, all", cfg.NetworkTagMatch)
+	}
+
+	if cfg.DiffAgainst != "" && cfg.Summary {
+		return nil, fmt.Errorf("cannot use -diff-against and -summary together")
+	}
+
+	// Overlapping pairs have no single subnet or network to sort by
+	if cfg.DetectOverlaps && cfg.Sort != "" {
+		return nil, fmt.Errorf("cannot use -detect-overlaps and -sort together")
+	}
+
+	// Merged license summaries already aggregate, and have no license fields to sort by
+	if cfg.MergeLicenses && (cfg.Summary || cfg.Sort != "" || strings.EqualFold(cfg.OutputType, "prometheus")) {
+		return nil, fmt.Errorf("cannot use -merge-org-licenses with -summary, -sort or -format prometheus")
+	}
+
+	if cfg.ConnectRetries < 0 {
+		return nil, fmt.Errorf("-connect-retries must not be negative")
+	}
+
+	if cfg.MaxRetries < 0 {
+		return nil, fmt.Errorf("-max-retries must not be negative")
+	}
+
+	if cfg.RateLimit < 0 {
+		return nil, fmt.Errorf("-rate-limit must not be negative")
+	}
+
+	if cfg.RetryMaxInterval <= 0 {
+		return nil, fmt.Errorf("-retry-max-interval must be positive")
+	}
+
+	if cfg.Limit < 0 || cfg.Offset < 0 {
+		return nil, fmt.Errorf("-limit and -offset must not be negative")
+	}
+
+	if cfg.DaysUntilExpiry < -1 {
+		return nil, fmt.Errorf("-days-until-expiry must not be negative")
+	}
+	if cfg.DaysUntilExpiry >= 0 && cfg.Command != "licenses" {
+		return nil, fmt.Errorf("-days-until-expiry can only be used with the licenses command")
+	}
+
+	if expiresAfter != "" {
+		t, err := parseDateFlag("expires-after", expiresAfter)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ExpiresAfter = t
+	}
+	if expiresBefore != "" {
+		t, err := parseDateFlag("expires-before", expiresBefore)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ExpiresBefore = t
+	}
+	if (!cfg.ExpiresAfter.IsZero() || !cfg.ExpiresBefore.IsZero()) && cfg.Command != "licenses" {
+		return nil, fmt.Errorf("-expires-after and -expires-before can only be used with the licenses command")
+	}
+	if !cfg.ExpiresAfter.IsZero() && !cfg.ExpiresBefore.IsZero() && !cfg.ExpiresAfter.Before(cfg.ExpiresBefore) {
+		return nil, fmt.Errorf("-expires-after must be before -expires-before")
+	}
+
+	if cfg.Proxy != "" && cfg.NoProxy {
+		return nil, fmt.Errorf("cannot use -proxy and -no-proxy together")
+	}
+
+	if cfg.Proxy != "" {
+		if _, err := meraki.ValidateProxyURL(cfg.Proxy); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.EnabledOnly && cfg.DisabledOnly {
+		return nil, fmt.Errorf("cannot use -enabled-only and -disabled-only together")
+	}
+
+	switch *ipVersion {
+	case "both":
+	case "4", "6":
+		if cfg.Command != "route-tables" {
+			return nil, fmt.Errorf("-ip-version can only be used with the route-tables command")
+		}
+		cfg.IPVersion, _ = strconv.Atoi(*ipVersion)
+	default:
+		return nil, fmt.Errorf("invalid -ip-version '%s'. Must be one of: 4, 6, both", *ipVersion)
+	}
+
+	if cfg.Subnet != "" {
+		ip, _, err := net.ParseCIDR(cfg.Subnet)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -subnet '%s'. Must be a CIDR such as 10.0.0.0/8 or 2001:db8::/32", cfg.Subnet)
+		}
+		// A supernet of the other IP version could never match a route
+		subnetVersion := 6
+		if ip.To4() != nil {
+			subnetVersion = 4
+		}
+		if cfg.IPVersion != 0 && cfg.IPVersion != subnetVersion {
+			return nil, fmt.Errorf("-subnet %s is an IPv%d CIDR and cannot match -ip-version %d", cfg.Subnet, subnetVersion, cfg.IPVersion)
+		}
+	}
+
+	if cfg.Gateway != "" {
+		if cfg.Command != "route-tables" {
+			return nil, fmt.Errorf("-gateway can only be used with the route-tables command")
+		}
+		valid := net.ParseIP(cfg.Gateway) != nil
+		if strings.Contains(cfg.Gateway, "/") {
+			_, _, err := net.ParseCIDR(cfg.Gateway)
+			valid = err == nil
+		}
+		if !valid {
+			return nil, fmt.Errorf("invalid -gateway '%s'. Must be an IP such as 10.0.0.1 or a CIDR such as 10.0.0.0/24", cfg.Gateway)
+		}
+	}
+
+	if cfg.DownLongerThan < 0 {
+		return nil, fmt.Errorf("-down-longer-than must not be negative")
+	}
+
+	jsonOutput := strings.EqualFold(cfg.OutputType, "json")
+	csvOutput := strings.EqualFold(cfg.OutputType, "csv")
+	templateOutput := strings.EqualFold(cfg.OutputType, "template")
+	for _, spec := range cfg.SecondaryOutputs {
+		outputType, _, err := ParseSecondaryOutput(spec)
+		if err != nil {
+			return nil, err
+		}
+		jsonOutput = jsonOutput || strings.EqualFold(outputType, "json")
+		csvOutput = csvOutput || strings.EqualFold(outputType, "csv")
+		templateOutput = templateOutput || strings.EqualFold(outputType, "template")
+	}
+	if cfg.JSONEnvelope && !jsonOutput {
+		return nil, fmt.Errorf("-json-envelope requires -format json or a json -secondary-output")
+	}
+
+	switch strings.ToLower(*csvDelimiter) {
+	case "comma":
+		cfg.CSVDelimiter = ','
+	case "semicolon":
+		cfg.CSVDelimiter = ';'
+	case "tab":
+		cfg.CSVDelimiter = '\t'
+	default:
+		return nil, fmt.Errorf("invalid -csv-delimiter '%s'. Must be one of: comma, semicolon, tab", *csvDelimiter)
+	}
+	if cfg.CSVDelimiter != ',' && !csvOutput {
+		return nil, fmt.Errorf("-csv-delimiter requires -format csv or a csv -secondary-output")
+	}
+	if cfg.CSVBOM && !csvOutput {
+		return nil, fmt.Errorf("-csv-bom requires -format csv or a csv -secondary-output")
+	}
+
+	// Parse the template now so that syntax errors are reported before any API calls
+	if templateOutput && cfg.TemplateFile == "" {
+		return nil, fmt.Errorf("-format template requires -template FILE")
+	}
+	if cfg.TemplateFile != "" {
+		if !templateOutput {
+			return nil, fmt.Errorf("-template requires -format template or a template -secondary-output")
+		}
+		if _, err := output.ParseTemplate(cfg.TemplateFile); err != nil {
+			return nil, fmt.Errorf("invalid -template: %w", err)
+		}
+	}
+	// Templates range over the records, which -summary and -run-summary replace with aggregate reports
+	if templateOutput && cfg.Summary {
+		return nil, fmt.Errorf("-summary cannot be used with -format template or a template -secondary-output")
+	}
+	if strings.EqualFold(cfg.OutputType, "template") && cfg.RunSummary {
+		return nil, fmt.Errorf("-format template cannot be used with -run-summary")
+	}
+
+	if *outputMode != "" {
+		mode, err := strconv.ParseUint(*outputMode, 8, 32)
+		if err != nil || mode == 0 || mode > 0777 {
+			return nil, fmt.Errorf("invalid -output-mode '%s'. Use an octal permission such as 0600 or 0640", *outputMode)
+		}
+		cfg.OutputMode = os.FileMode(mode)
+	}
+
+	for _, spec := range cfg.OutputHeaders {
+		if _, _, err := output.ParseHeader(spec); err != nil {
+			return nil, err
+		}
+	}
+	if len(cfg.OutputHeaders) > 0 && !output.IsURL(cfg.OutputFile) {
+		return nil, fmt.Errorf("-output-header requires -output to be an http:// or https:// URL")
+	}
+	if cfg.Compress && (cfg.OutputFile == "" || cfg.OutputFile == "-" || output.IsURL(cfg.OutputFile)) {
+		return nil, fmt.Errorf("-compress requires -output to be a file")
+	}
+	if cfg.Manifest != "" && (cfg.OutputFile == "" || cfg.OutputFile == "-" || output.IsURL(cfg.OutputFile)) {
+		return nil, fmt.Errorf("-manifest requires -output to be a file")
+	}
+
+	// The device command, and switchports with -serial, look up a single serial, which identifies
+	// its network and organization
+	serialLookup := cfg.Command == "device" || (cfg.Command == "switchports" && cfg.SerialFilter != "")
+	if cfg.Command == "device" && cfg.SerialFilter == "" {
+		return nil, fmt.Errorf("device command requires -serial")
+	}
+	if serialLookup && (cfg.InfoAll || cfg.Network != "" || cfg.NetworksFile != "") {
+		return nil, fmt.Errorf("%s command with -serial outputs a single device and cannot be used with -all, -network or -networks-file", cfg.Command)
 	}
 
 	// Set InfoAll to true if no network is specified (as per requirements)
-	// Exception: access command doesn't use InfoAll
-	if cfg.Network == "" && cfg.Command != "access" {
+	// Exception: access command and serial lookups don't use InfoAll
+	if cfg.Network == "" && cfg.Command != "access" && !serialLookup {
 		cfg.InfoAll = true
 	}
 
+	// -networks-file names the networks to process, so it takes the place of -network and -all
+	if cfg.NetworksFile != "" {
+		if cfg.Network != "" {
+			return nil, fmt.Errorf("cannot use -network and -networks-file together")
+		}
+		if cfg.Command == "access" || cfg.Command == "api-usage" || cfg.Command == "change-log" || cfg.Command == "locations" || cfg.Command == "networks" || cfg.Command == "perf" {
+			return nil, fmt.Errorf("-networks-file cannot be used with the %s command", cfg.Command)
+		}
+		networks, err := readNetworksFile(cfg.NetworksFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.NetworkList = networks
+		cfg.InfoAll = false
+	}
+
+	// -apikey-file and -apikey-keychain take precedence over MERAKI_APIKEY
+	apikeySet := false
+	flag.Visit(func(f *flag.Flag) {
+		apikeySet = apikeySet || f.Name == "apikey"
+	})
+	if err := resolveAPIKey(cfg, apikeySet); err != nil {
+		return nil, err
+	}
+
 	// Validate required fields
 	if cfg.APIKey == "" {
-		return nil, fmt.Errorf("API key is required. Use -apikey flag or MERAKI_APIKEY environment variable")
+		return nil, fmt.Errorf("API key is required. Use -apikey, -apikey-file, -apikey-keychain, or the MERAKI_APIKEY or MERAKI_APIKEY_FILE environment variable")
+	}
+
+	if len(cfg.Organizations) > 1 {
+		if err := ValidateSeveralOrganizations(cfg); err != nil {
+			return nil, err
+		}
 	}
 
-	// If showing access or using --all, organization is not required
+	// If showing access, looking up a serial or using --all, organization is not required
 	// For other commands without --all, organization is required
-	if cfg.Command != "access" && !cfg.InfoAll && cfg.Organization == "" {
+	if cfg.Command != "access" && !serialLookup && !cfg.InfoAll && cfg.Organization == "" {
 		return nil, fmt.Errorf("organization is required when not using --all or access command. Use --org flag or MERAKI_ORG environment variable")
 	}
 
@@ -124,10 +1386,69 @@ func parseConfigWithValidation() (*Config, error) {
 	// Note: -all with stdout is now supported for consolidated output with network information
 	// The validation requiring -output default for -all has been removed to support this use case
 
+	// Network tags select the networks of organization-wide runs, so a single network cannot be combined with them
+	if len(cfg.NetworkTags) > 0 && !cfg.InfoAll {
+		return nil, fmt.Errorf("-network-tags can only be used with -all, not with -network or the access command")
+	}
+	if len(cfg.ExcludeNetworks) > 0 && !cfg.InfoAll {
+		return nil, fmt.Errorf("-exclude-network can only be used with -all, not with -network or the access command")
+	}
+
 	// Access mode doesn't support -all
 	if cfg.Command == "access" && cfg.InfoAll {
 		return nil, fmt.Errorf("cannot use -all with access command. Use access command alone to show organizations/networks")
 	}
 
+	// API request logs are kept per organization, so there is no network to select
+	if cfg.Command == "api-usage" && cfg.Network != "" {
+		return nil, fmt.Errorf("api-usage command reports whole organizations and cannot be used with -network")
+	}
+
+	// The networks command lists networks, so it has no single network to select
+	if cfg.Command == "networks" && cfg.Network != "" {
+		return nil, fmt.Errorf("networks command lists whole organizations and cannot be used with -network")
+	}
+
+	// The events API is per-network and paged, so events are only fetched for one network at a time
+	if cfg.Command == "events" {
+		if cfg.InfoAll || meraki.IsNetworkPattern(cfg.Network) {
+			return nil, fmt.Errorf("events command requires a single -network; -all and network patterns are not supported because the events API is per-network")
+		}
+		if len(cfg.ProductTypeFilter) > 1 {
+			return nil, fmt.Errorf("events command accepts only one -product-type")
+		}
+	}
+
+	// The change log is filtered to one network by the API, so network patterns are not supported
+	if cfg.Command == "change-log" && meraki.IsNetworkPattern(cfg.Network) {
+		return nil, fmt.Errorf("change-log command accepts a single -network; network patterns are not supported")
+	}
+
+	// A network pattern is expanded into a consolidated report over the matching networks, which
+	// only the commands that report per network records support
+	if meraki.IsNetworkPattern(cfg.Network) {
+		switch cfg.Command {
+		case "route-tables", "down", "alerting", "licenses", "stacks", "dhcp", "firewall", "status-summary":
+		default:
+			return nil, fmt.Errorf("network patterns are not supported for the %s command; use a single -network", cfg.Command)
+		}
+	}
+
+	// -sort must name a field of the records the command writes, which only gain the network and
+	// organization in consolidated output
+	if cfg.Sort != "" {
+		field, _, err := output.ParseSort(cfg.Sort)
+		if err != nil {
+			return nil, err
+		}
+		fields := output.SortFields(sortRecords(cfg))
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("-sort cannot be used with the %s command", cfg.Command)
+		}
+		if !slices.Contains(fields, field) {
+			return nil, fmt.Errorf("invalid -sort field '%s' for the %s command. Must be one of: %s", field, cfg.Command, strings.Join(fields, ", "))
+		}
+	}
+
 	return cfg, nil
 }
@@ -1,10 +1,14 @@
 package config
 
 import (
+	"errors"
 	"flag"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestParseConfig(t *testing.T) {
@@ -39,6 +43,93 @@ func TestParseConfig(t *testing.T) {
 		}
 	})
 
+	t.Run("apikey-file flag", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "env-key")
+		keyFile := filepath.Join(t.TempDir(), "apikey")
+		if err := os.WriteFile(keyFile, []byte("  file-key\r\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-apikey-file", keyFile, "licenses"}
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.APIKey != "file-key" {
+			t.Errorf("Expected the trimmed key from the file to override MERAKI_APIKEY, got %q", cfg.APIKey)
+		}
+
+		t.Setenv("MERAKI_APIKEY_FILE", keyFile)
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-apikey", "file-key", "licenses"}
+		cfg, err = parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error for matching -apikey and MERAKI_APIKEY_FILE: %v", err)
+		}
+		if cfg.APIKey != "file-key" {
+			t.Errorf("Expected the key from MERAKI_APIKEY_FILE, got %q", cfg.APIKey)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-apikey", "flag-key", "licenses"}
+		_, err = parseConfigWithValidation()
+		if err == nil || !strings.Contains(err.Error(), "-apikey and -apikey-file supply different API keys") {
+			t.Errorf("Expected a conflicting key error, got: %v", err)
+		}
+		if err != nil && (strings.Contains(err.Error(), "flag-key") || strings.Contains(err.Error(), "file-key")) {
+			t.Errorf("Expected the error not to reveal the keys, got: %v", err)
+		}
+		os.Unsetenv("MERAKI_APIKEY_FILE")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-apikey-file", filepath.Join(t.TempDir(), "missing"), "licenses"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "failed to read -apikey-file") {
+			t.Errorf("Expected a missing file error, got: %v", err)
+		}
+
+		emptyFile := filepath.Join(t.TempDir(), "empty")
+		if err := os.WriteFile(emptyFile, []byte("\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-apikey-file", emptyFile, "licenses"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "is empty") {
+			t.Errorf("Expected an empty file error, got: %v", err)
+		}
+	})
+
+	t.Run("apikey-keychain flag", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "env-key")
+		previous := keychain
+		defer func() { keychain = previous }()
+		fake := fakeKeychain{"meraki-info/apikey": "keychain-key"}
+		keychain = fake
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-apikey-keychain", "licenses"}
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.APIKey != "keychain-key" {
+			t.Errorf("Expected the key from the keychain, got %q", cfg.APIKey)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-apikey", "flag-key", "-apikey-keychain", "licenses"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "cannot use -apikey and -apikey-keychain together") {
+			t.Errorf("Expected a conflict error, got: %v", err)
+		}
+
+		delete(fake, "meraki-info/apikey")
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-apikey-keychain", "licenses"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "failed to read the API key from the OS keychain") {
+			t.Errorf("Expected a keychain error, got: %v", err)
+		}
+	})
+
 	t.Run("missing command should return error", func(t *testing.T) {
 		// Set API key but no command
 		os.Setenv("MERAKI_APIKEY", "test-key")
@@ -227,29 +318,1702 @@ func TestParseConfig(t *testing.T) {
 		}
 	})
 
-	t.Run("options followed by command format", func(t *testing.T) {
+	t.Run("invalid vpn mode should return error", func(t *testing.T) {
 		os.Setenv("MERAKI_APIKEY", "test-key")
 
-		// Reset flags and set test args with options in various orders, then command
+		// Reset flags and set test args with an unknown VPN mode
 		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
-		os.Args = []string{"meraki-info", "-all", "-output", "test.txt", "-org", "test-org", "-format", "json", "licenses"}
+		os.Args = []string{"meraki-info", "-org", "test-org", "-vpn-mode", "mesh", "route-tables"}
+
+		_, err := parseConfigWithValidation()
+		if err == nil {
+			t.Error("Expected error for invalid -vpn-mode")
+		}
+		if err != nil && !strings.Contains(err.Error(), "invalid -vpn-mode") {
+			t.Errorf("Expected invalid -vpn-mode error, got: %v", err)
+		}
+	})
+
+	t.Run("repeatable secondary outputs", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		// Reset flags and set test args with two secondary outputs
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-secondary-output", "json:routes.json", "-secondary-output", "csv:-", "route-tables"}
 
 		cfg, err := parseConfigWithValidation()
 		if err != nil {
-			t.Errorf("Expected no error when using [OPTIONS] COMMAND format, got: %v", err)
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if len(cfg.SecondaryOutputs) != 2 {
+			t.Fatalf("Expected 2 secondary outputs, got %d", len(cfg.SecondaryOutputs))
 		}
 
-		if cfg.Command != "licenses" {
-			t.Errorf("Expected Command 'licenses', got '%s'", cfg.Command)
+		outputType, path, err := ParseSecondaryOutput(cfg.SecondaryOutputs[0])
+		if err != nil || outputType != "json" || path != "routes.json" {
+			t.Errorf("Expected json:routes.json, got %s:%s (err: %v)", outputType, path, err)
 		}
-		if cfg.OutputFile != "test.txt" {
-			t.Errorf("Expected OutputFile 'test.txt', got '%s'", cfg.OutputFile)
+	})
+
+	t.Run("invalid secondary output should return error", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		// Reset flags and set test args with an unsupported secondary output type
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-secondary-output", "yaml:routes.yaml", "route-tables"}
+
+		_, err := parseConfigWithValidation()
+		if err == nil || !strings.Contains(err.Error(), "invalid -secondary-output") {
+			t.Errorf("Expected invalid -secondary-output error, got: %v", err)
 		}
-		if cfg.OutputType != "json" {
-			t.Errorf("Expected OutputType 'json', got '%s'", cfg.OutputType)
+	})
+
+	t.Run("version flag does not require API key or command", func(t *testing.T) {
+		os.Unsetenv("MERAKI_APIKEY")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-version"}
+
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
 		}
-		if !cfg.InfoAll {
-			t.Error("Expected InfoAll to be true")
+		if cfg.Command != "version" {
+			t.Errorf("Expected Command 'version', got '%s'", cfg.Command)
+		}
+	})
+
+	t.Run("list-formats flag does not require API key or command", func(t *testing.T) {
+		os.Unsetenv("MERAKI_APIKEY")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-list-formats"}
+
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if cfg.Command != "list-formats" {
+			t.Errorf("Expected Command 'list-formats', got '%s'", cfg.Command)
+		}
+	})
+
+	t.Run("version pseudo-command", func(t *testing.T) {
+		os.Unsetenv("MERAKI_APIKEY")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "version"}
+
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if cfg.Command != "version" {
+			t.Errorf("Expected Command 'version', got '%s'", cfg.Command)
+		}
+	})
+
+	t.Run("invalid sort field should return error", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		// Reset flags and set test args with an unknown sort field
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-sort", "Color:asc", "route-tables"}
+
+		_, err := parseConfigWithValidation()
+		if err == nil || !strings.Contains(err.Error(), "invalid sort field") {
+			t.Errorf("Expected invalid sort field error, got: %v", err)
+		}
+	})
+
+	t.Run("sort field must fit the command's records", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		tests := []struct {
+			args     []string
+			expected string
+		}{
+			{args: []string{"-org", "test-org", "-sort", "Serial", "route-tables"}, expected: "invalid -sort field 'Serial' for the route-tables command"},
+			{args: []string{"-org", "test-org", "-network", "HQ", "-sort", "NetworkName", "route-tables"}, expected: "Must be one of: GatewayIP, Name, Subnet"},
+			{args: []string{"-org", "test-org", "-output", "routes.txt", "-sort", "NetworkName", "route-tables"}, expected: "invalid -sort field 'NetworkName'"},
+			{args: []string{"-org", "test-org", "-sort", "Subnet", "down"}, expected: "invalid -sort field 'Subnet' for the down command"},
+			{args: []string{"-org", "test-org", "-network", "HQ", "-sort", "Name", "access"}, expected: "-sort cannot be used with the access command"},
+			{args: []string{"-org", "test-org", "-sort", "NetworkName", "route-tables"}},
+			{args: []string{"-org", "test-org", "-network", "Store-*", "-sort", "Organization:desc", "down"}},
+			{args: []string{"-org", "test-org", "-network", "HQ", "-sort", "LastReportedAt", "alerting"}},
+			{args: []string{"-org", "test-org", "-sort", "Organization", "licenses"}},
+		}
+		for _, tt := range tests {
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+			os.Args = append([]string{"meraki-info"}, tt.args...)
+			_, err := parseConfigWithValidation()
+			if tt.expected == "" {
+				if err != nil {
+					t.Errorf("%v: unexpected error: %v", tt.args, err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("%v: expected error containing %q, got: %v", tt.args, tt.expected, err)
+			}
+		}
+	})
+
+	t.Run("negative limit should return error", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		// Reset flags and set test args with a negative limit
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-limit", "-1", "down"}
+
+		_, err := parseConfigWithValidation()
+		if err == nil || !strings.Contains(err.Error(), "must not be negative") {
+			t.Errorf("Expected negative limit error, got: %v", err)
+		}
+	})
+
+	t.Run("invalid proxy URL should return error", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		// Reset flags and set test args with an unsupported proxy scheme
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-proxy", "ftp://proxy:21", "down"}
+
+		_, err := parseConfigWithValidation()
+		if err == nil || !strings.Contains(err.Error(), "invalid proxy URL") {
+			t.Errorf("Expected invalid proxy URL error, got: %v", err)
+		}
+	})
+
+	t.Run("comma-separated model filter", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		// Reset flags and set test args with a model list
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-model", "MX64, MX84,,", "down"}
+
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(cfg.ModelFilter) != 2 || cfg.ModelFilter[0] != "MX64" || cfg.ModelFilter[1] != "MX84" {
+			t.Errorf("Expected ModelFilter [MX64 MX84], got %v", cfg.ModelFilter)
+		}
+
+		// -model-prefix values are combined with -model
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-model", "MX", "-model-prefix", "mr,MS", "down"}
+		cfg, err = parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.Join(cfg.ModelFilter, ",") != "MX,mr,MS" {
+			t.Errorf("Expected ModelFilter [MX mr MS], got %v", cfg.ModelFilter)
+		}
+	})
+
+	t.Run("comma-separated product type filter", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		// Reset flags and set test args with a product type list
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-product-type", "Wireless,cellulargateway", "down"}
+
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(cfg.ProductTypeFilter) != 2 || cfg.ProductTypeFilter[0] != "wireless" || cfg.ProductTypeFilter[1] != "cellularGateway" {
+			t.Errorf("Expected ProductTypeFilter [wireless cellularGateway], got %v", cfg.ProductTypeFilter)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-product-type", "wireless,router", "down"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "invalid -product-type 'router'") {
+			t.Errorf("Expected invalid product type error, got: %v", err)
+		}
+	})
+
+	t.Run("output headers require a URL output", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		// Reset flags and set test args with a header but a file output
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-output", "down.json", "-output-header", "Authorization: Bearer x", "down"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "requires -output to be") {
+			t.Errorf("Expected URL requirement error, got: %v", err)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-output", "https://hooks.example.com/meraki", "-output-header", "Authorization: Bearer x", "-output-header", "X-Source: meraki-info", "down"}
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(cfg.OutputHeaders) != 2 {
+			t.Errorf("Expected 2 output headers, got %v", cfg.OutputHeaders)
+		}
+	})
+
+	t.Run("invalid tag match should return error", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		// Reset flags and set test args with repeated tags and an unknown match mode
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-tag", "retail", "-tag", "east", "-tag-match", "some", "route-tables"}
+
+		_, err := parseConfigWithValidation()
+		if err == nil || !strings.Contains(err.Error(), "invalid -tag-match") {
+			t.Errorf("Expected invalid tag match error, got: %v", err)
+		}
+	})
+
+	t.Run("down statuses default and override", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		// Without the flag the built-in down status list applies
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "down"}
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.Join(cfg.DownStatuses, ",") != "offline,alerting,dormant,down,unreachable,disconnected" {
+			t.Errorf("Expected default down statuses, got %v", cfg.DownStatuses)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-down-statuses", "Offline, down,maintenance", "down"}
+		cfg, err = parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.Join(cfg.DownStatuses, ",") != "offline,down,maintenance" {
+			t.Errorf("Expected custom down statuses, got %v", cfg.DownStatuses)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-down-statuses", "", "down"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "-down-statuses") {
+			t.Errorf("Expected empty down statuses error, got: %v", err)
+		}
+	})
+
+	t.Run("events command rejects -all", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		// Reset flags and set test args without a network, which implies -all
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "events"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "single -network") {
+			t.Errorf("Expected single network error, got: %v", err)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-network", "HQ", "-product-type", "wireless", "-event-type", "association, disassociation", "-since", "2025-07-17T00:00:00Z", "-until", "2025-07-17T06:00:00Z", "events"}
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(cfg.EventTypes) != 2 || cfg.EventTypes[1] != "disassociation" {
+			t.Errorf("Expected two event types, got %v", cfg.EventTypes)
+		}
+		if cfg.Until.Sub(cfg.Since) != 6*time.Hour {
+			t.Errorf("Expected a 6h window, got %s to %s", cfg.Since, cfg.Until)
+		}
+	})
+
+	t.Run("since must be before until", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-network", "HQ", "-since", "1h", "-until", "24h", "events"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "-since must be before -until") {
+			t.Errorf("Expected since/until order error, got: %v", err)
+		}
+	})
+
+	t.Run("invalid subnet should return error", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		// Reset flags and set test args with a subnet that is not a CIDR
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-subnet", "10.0.0.0", "route-tables"}
+
+		_, err := parseConfigWithValidation()
+		if err == nil || !strings.Contains(err.Error(), "invalid -subnet") {
+			t.Errorf("Expected invalid subnet error, got: %v", err)
+		}
+	})
+
+	t.Run("gateway flag accepts an IP or a CIDR", func(t *testing.T) {
+		for _, gateway := range []string{"10.0.0.1", "10.0.0.0/24"} {
+			os.Setenv("MERAKI_APIKEY", "test-key")
+
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+			os.Args = []string{"meraki-info", "-org", "test-org", "-gateway", gateway, "route-tables"}
+
+			cfg, err := parseConfigWithValidation()
+			if err != nil {
+				t.Fatalf("Unexpected error for -gateway %s: %v", gateway, err)
+			}
+			if cfg.Gateway != gateway {
+				t.Errorf("Expected Gateway %q, got %q", gateway, cfg.Gateway)
+			}
+		}
+	})
+
+	t.Run("invalid gateway returns an error", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-gateway", "10.0.0.0/33", "route-tables"}
+
+		_, err := parseConfigWithValidation()
+		if err == nil || !strings.Contains(err.Error(), "invalid -gateway") {
+			t.Errorf("Expected invalid gateway error, got: %v", err)
+		}
+	})
+
+	t.Run("gateway requires route-tables", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-gateway", "10.0.0.1", "down"}
+
+		_, err := parseConfigWithValidation()
+		if err == nil || !strings.Contains(err.Error(), "-gateway can only be used with the route-tables command") {
+			t.Errorf("Expected route-tables only error, got: %v", err)
+		}
+	})
+
+	t.Run("fail-on-results flag", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-fail-on-results", "down"}
+
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !cfg.FailOnResults {
+			t.Error("Expected FailOnResults to be set")
 		}
 	})
+
+	t.Run("deprecated fail-on-partial flag is accepted", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-all", "-fail-on-partial", "route-tables"}
+
+		if _, err := parseConfigWithValidation(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("strict flag", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-all", "-strict", "licenses"}
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !cfg.Strict {
+			t.Error("Expected Strict to be set")
+		}
+	})
+
+	t.Run("days-until-expiry flag", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-days-until-expiry", "90", "licenses"}
+
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.DaysUntilExpiry != 90 {
+			t.Errorf("Expected DaysUntilExpiry 90, got %d", cfg.DaysUntilExpiry)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "down"}
+
+		cfg, err = parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.DaysUntilExpiry != -1 {
+			t.Errorf("Expected DaysUntilExpiry to default to -1, got %d", cfg.DaysUntilExpiry)
+		}
+	})
+
+	t.Run("days-until-expiry requires licenses command", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-days-until-expiry", "30", "down"}
+
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "licenses command") {
+			t.Errorf("Expected licenses command error, got: %v", err)
+		}
+	})
+
+	t.Run("expires-after and expires-before flags", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-expires-after", "2026-01-01", "-expires-before", "2026-04-01", "licenses"}
+
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !cfg.ExpiresAfter.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) || !cfg.ExpiresBefore.Equal(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("Expected the expiration range 2026-01-01 to 2026-04-01, got %v to %v", cfg.ExpiresAfter, cfg.ExpiresBefore)
+		}
+
+		tests := []struct {
+			args     []string
+			expected string
+		}{
+			{args: []string{"-expires-after", "01/01/2026", "licenses"}, expected: "invalid -expires-after"},
+			{args: []string{"-expires-before", "2026-13-01", "licenses"}, expected: "invalid -expires-before"},
+			{args: []string{"-expires-after", "2026-04-01", "-expires-before", "2026-01-01", "licenses"}, expected: "must be before"},
+			{args: []string{"-expires-before", "2026-01-01", "down"}, expected: "licenses command"},
+		}
+		for _, tt := range tests {
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+			os.Args = append([]string{"meraki-info", "-org", "test-org"}, tt.args...)
+			if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q for %v, got: %v", tt.expected, tt.args, err)
+			}
+		}
+	})
+
+	t.Run("retry flags", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "down"}
+
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.MaxRetries != 3 || cfg.RetryMaxInterval != 30*time.Second {
+			t.Errorf("Expected default retries 3 and 30s, got %d and %v", cfg.MaxRetries, cfg.RetryMaxInterval)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-max-retries", "6", "-retry-max-interval", "2m", "down"}
+
+		cfg, err = parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.MaxRetries != 6 || cfg.RetryMaxInterval != 2*time.Minute {
+			t.Errorf("Expected retries 6 and 2m, got %d and %v", cfg.MaxRetries, cfg.RetryMaxInterval)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-max-retries", "-1", "down"}
+
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "-max-retries") {
+			t.Errorf("Expected -max-retries error, got: %v", err)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-rate-limit", "5", "down"}
+
+		cfg, err = parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.RateLimit != 5 {
+			t.Errorf("Expected RateLimit 5, got %v", cfg.RateLimit)
+		}
+	})
+
+	t.Run("license-state flag", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-license-state", "Expired, recentlyqueued", "licenses"}
+
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.Join(cfg.LicenseStates, ",") != "expired,recentlyQueued" {
+			t.Errorf("Expected canonical license states, got %v", cfg.LicenseStates)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-license-state", "active,bogus", "licenses"}
+
+		_, err = parseConfigWithValidation()
+		if err == nil || !strings.Contains(err.Error(), "'bogus'") || !strings.Contains(err.Error(), "permanentlyQueued") {
+			t.Errorf("Expected invalid state error listing valid values, got: %v", err)
+		}
+	})
+
+	t.Run("network-tags flag", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-all", "-network-tags", "production, branch", "down"}
+
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.Join(cfg.NetworkTags, ",") != "production,branch" || cfg.NetworkTagMatch != "any" {
+			t.Errorf("Expected network tags production,branch matching any, got %v %s", cfg.NetworkTags, cfg.NetworkTagMatch)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-all", "-network-tag", "production", "-network-tag-match", "ALL", "down"}
+		cfg, err = parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.Join(cfg.NetworkTags, ",") != "production" || cfg.NetworkTagMatch != "all" {
+			t.Errorf("Expected -network-tag to select production matching all, got %v %s", cfg.NetworkTags, cfg.NetworkTagMatch)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-all", "-network-tags", "production", "-network-tag-match", "some", "down"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "-network-tag-match") {
+			t.Errorf("Expected an invalid -network-tag-match to be rejected, got: %v", err)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-network", "net1", "-network-tags", "production", "down"}
+
+		_, err = parseConfigWithValidation()
+		if err == nil || !strings.Contains(err.Error(), "-network-tags") {
+			t.Errorf("Expected -network-tags to be rejected with -network, got: %v", err)
+		}
+	})
+
+	t.Run("prometheus format", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-all", "-format", "prometheus", "down"}
+
+		if _, err := parseConfigWithValidation(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-all", "-format", "prometheus", "route-tables"}
+
+		_, err := parseConfigWithValidation()
+		if err == nil || !strings.Contains(err.Error(), "prometheus") {
+			t.Errorf("Expected -format prometheus to be rejected for route-tables, got: %v", err)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-all", "-format", "prometheus", "-run-summary", "licenses"}
+
+		_, err = parseConfigWithValidation()
+		if err == nil || !strings.Contains(err.Error(), "-run-summary") {
+			t.Errorf("Expected -format prometheus to be rejected with -run-summary, got: %v", err)
+		}
+	})
+
+	t.Run("group-by-network flag", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-all", "-group-by-network", "route-tables"}
+
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !cfg.GroupByNetwork {
+			t.Error("Expected GroupByNetwork to be set")
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-all", "-group-by-network", "down"}
+
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "-group-by-network") {
+			t.Errorf("Expected -group-by-network to be rejected for down, got: %v", err)
+		}
+	})
+
+	t.Run("repeatable org flag", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+		os.Setenv("MERAKI_ORG", "env-org")
+		defer os.Unsetenv("MERAKI_ORG")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "Acme, Inc.", "-org", "123456", "down"}
+
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.Join(cfg.Organizations, "|") != "Acme, Inc.|123456" {
+			t.Errorf("Expected every -org value kept whole, replacing MERAKI_ORG, got %v", cfg.Organizations)
+		}
+		if cfg.Organization != "" || !cfg.InfoAll {
+			t.Errorf("Expected no single organization and -all, got Organization=%q InfoAll=%t", cfg.Organization, cfg.InfoAll)
+		}
+
+		// A single comma-separated value is split only when it is resolved, so an organization
+		// named with a comma is still selected by its name
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "Acme, Inc.", "down"}
+
+		cfg, err = parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.Organization != "Acme, Inc." || len(cfg.Organizations) != 1 {
+			t.Errorf("Expected the whole value as the organization, got %q %v", cfg.Organization, cfg.Organizations)
+		}
+		if err := ValidateSeveralOrganizations(&Config{Network: "net1"}); err == nil || !strings.Contains(err.Error(), "several -org values") {
+			t.Errorf("Expected -network to be rejected once a value resolves to several organizations, got: %v", err)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "down"}
+
+		cfg, err = parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.Organization != "env-org" || len(cfg.Organizations) != 1 {
+			t.Errorf("Expected MERAKI_ORG as the single organization, got %q %v", cfg.Organization, cfg.Organizations)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "Org A", "-org", "Org B", "-network", "net1", "down"}
+
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "several -org values") {
+			t.Errorf("Expected -network to be rejected with several organizations, got: %v", err)
+		}
+	})
+
+	t.Run("regex flag", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-regex", "^BRANCH-[^-]+-MX$", "down"}
+
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.FilterRegex == nil || !cfg.FilterRegex.MatchString("BRANCH-Berlin-MX") || cfg.FilterRegex.MatchString("BRANCH-Berlin-MX-2") {
+			t.Errorf("Expected the compiled anchored -regex, got %v", cfg.FilterRegex)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-regex", "BRANCH-(", "route-tables"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "invalid -regex") {
+			t.Errorf("Expected an invalid -regex error, got: %v", err)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-regex", "BRANCH", "licenses"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "route-tables, down and alerting") {
+			t.Errorf("Expected a command error, got: %v", err)
+		}
+	})
+
+	t.Run("output-mode flag", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-output-mode", "0600", "licenses"}
+
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.OutputMode != 0600 {
+			t.Errorf("Expected OutputMode 0600, got %o", cfg.OutputMode)
+		}
+
+		for _, value := range []string{"0900", "rw-------", "01777", "0"} {
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+			os.Args = []string{"meraki-info", "-org", "test-org", "-output-mode", value, "licenses"}
+			if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "invalid -output-mode") {
+				t.Errorf("Expected an invalid -output-mode error for %q, got: %v", value, err)
+			}
+		}
+	})
+
+	t.Run("networks command", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-product-type", "Wireless", "networks"}
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.Command != "networks" || !cfg.InfoAll || strings.Join(cfg.ProductTypeFilter, ",") != "wireless" {
+			t.Errorf("Expected the networks of the organization filtered to wireless, got %+v", cfg)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-network", "HQ", "networks"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "cannot be used with -network") {
+			t.Errorf("Expected -network to be rejected, got: %v", err)
+		}
+	})
+
+	t.Run("api-usage command", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "api-usage"}
+
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.Timespan != DefaultAPIUsageTimespan || cfg.Detailed {
+			t.Errorf("Expected the default timespan without -detailed, got %s, %t", cfg.Timespan, cfg.Detailed)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-timespan", "7d", "-detailed", "api-usage"}
+		if cfg, err = parseConfigWithValidation(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.Timespan != 7*24*time.Hour || !cfg.Detailed {
+			t.Errorf("Expected a 7 day detailed report, got %s, %t", cfg.Timespan, cfg.Detailed)
+		}
+
+		tests := []struct {
+			args     []string
+			expected string
+		}{
+			{args: []string{"-timespan", "32d", "api-usage"}, expected: "invalid -timespan"},
+			{args: []string{"-timespan", "soon", "api-usage"}, expected: "invalid -timespan"},
+			{args: []string{"-timespan", "1h", "licenses"}, expected: "api-usage command"},
+			{args: []string{"-detailed", "down"}, expected: "api-usage command"},
+			{args: []string{"-network", "N_1", "api-usage"}, expected: "cannot be used with -network"},
+		}
+		for _, tt := range tests {
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+			os.Args = append([]string{"meraki-info", "-org", "test-org"}, tt.args...)
+			if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q for %v, got: %v", tt.expected, tt.args, err)
+			}
+		}
+	})
+
+	t.Run("ip-version flag", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-ip-version", "6", "-subnet", "2001:db8::/32", "route-tables"}
+
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.IPVersion != 6 {
+			t.Errorf("Expected IPVersion 6, got %d", cfg.IPVersion)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "route-tables"}
+		if cfg, err = parseConfigWithValidation(); err != nil || cfg.IPVersion != 0 {
+			t.Errorf("Expected both IP versions by default, got %v, %v", cfg, err)
+		}
+
+		tests := []struct {
+			args     []string
+			expected string
+		}{
+			{args: []string{"-ip-version", "5", "route-tables"}, expected: "invalid -ip-version"},
+			{args: []string{"-ip-version", "4", "down"}, expected: "route-tables command"},
+			{args: []string{"-ip-version", "4", "-subnet", "2001:db8::/32", "route-tables"}, expected: "cannot match -ip-version 4"},
+		}
+		for _, tt := range tests {
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+			os.Args = append([]string{"meraki-info", "-org", "test-org"}, tt.args...)
+			if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q for %v, got: %v", tt.expected, tt.args, err)
+			}
+		}
+	})
+
+	t.Run("exclude-network flag", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-exclude-network", "Test Lab", "-exclude-network", "N_2", "down"}
+
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.Join(cfg.ExcludeNetworks, ",") != "Test Lab,N_2" {
+			t.Errorf("Expected both exclusions, got %v", cfg.ExcludeNetworks)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-network", "N_1", "-exclude-network", "N_2", "down"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "-exclude-network can only be used with -all") {
+			t.Errorf("Expected an -all error, got: %v", err)
+		}
+	})
+
+	t.Run("fields flag", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-network", "N_1", "-fields", "Subnet, GatewayIP", "-format", "csv", "route-tables"}
+
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.Join(cfg.Fields, ",") != "Subnet,GatewayIP" {
+			t.Errorf("Expected Subnet and GatewayIP fields, got %v", cfg.Fields)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-network", "N_1", "-fields", "Subnet", "-format", "xml", "route-tables"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "-fields can only be used with -format text, json or csv") {
+			t.Errorf("Expected a format error, got: %v", err)
+		}
+	})
+
+	t.Run("l7 flag", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-l7", "firewall"}
+
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.Command != "firewall" || !cfg.IncludeL7 || !cfg.InfoAll {
+			t.Errorf("Expected firewall command with L7 rules for all networks, got %+v", cfg)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-l7", "route-tables"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "-l7 can only be used with the firewall command") {
+			t.Errorf("Expected a firewall command error, got: %v", err)
+		}
+	})
+
+	t.Run("firewall-rules command and layer flag", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-layer", "7", "firewall-rules"}
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.Command != "firewall" || !cfg.IncludeL7 {
+			t.Errorf("Expected firewall-rules to run the firewall command with L7 rules, got %+v", cfg)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-layer", "3", "firewall-rules"}
+		if cfg, err = parseConfigWithValidation(); err != nil || cfg.IncludeL7 {
+			t.Errorf("Expected only L3 rules with -layer 3, got %+v, %v", cfg, err)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-layer", "4", "firewall-rules"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "invalid -layer '4'") {
+			t.Errorf("Expected an invalid layer error, got: %v", err)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-layer", "7", "licenses"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "-layer can only be used with the firewall command") {
+			t.Errorf("Expected a firewall command error, got: %v", err)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-sort", "NetworkName", "firewall-rules"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "-sort cannot be used with the firewall command") {
+			t.Errorf("Expected firewall rules not to be sortable, got: %v", err)
+		}
+	})
+
+	t.Run("networks-file flag", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+		networksFile := filepath.Join(t.TempDir(), "networks.txt")
+		if err := os.WriteFile(networksFile, []byte("# critical sites\nHQ\n\n  L_123  \n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-all", "-networks-file", networksFile, "down"}
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.Join(cfg.NetworkList, ",") != "HQ,L_123" || cfg.InfoAll {
+			t.Errorf("Expected the listed networks instead of -all, got %v and InfoAll %t", cfg.NetworkList, cfg.InfoAll)
+		}
+
+		for _, tt := range []struct {
+			args     []string
+			expected string
+		}{
+			{[]string{"-org", "test-org", "-network", "HQ", "-networks-file", networksFile, "down"}, "cannot use -network and -networks-file together"},
+			{[]string{"-networks-file", networksFile, "route-tables"}, "organization is required"},
+			{[]string{"-org", "test-org", "-networks-file", networksFile, "api-usage"}, "-networks-file cannot be used with the api-usage command"},
+			{[]string{"-org", "test-org", "-networks-file", filepath.Join(t.TempDir(), "missing.txt"), "down"}, "failed to read -networks-file"},
+		} {
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+			os.Args = append([]string{"meraki-info"}, tt.args...)
+			if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected %q for %v, got: %v", tt.expected, tt.args, err)
+			}
+		}
+	})
+
+	t.Run("no-license-footer flag", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-no-license-footer", "licenses"}
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !cfg.NoLicenseFooter {
+			t.Error("Expected NoLicenseFooter to be set")
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-no-license-footer", "down"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "-no-license-footer can only be used with the licenses command") {
+			t.Errorf("Expected a licenses command error, got: %v", err)
+		}
+	})
+
+	t.Run("errored-only flag", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-all", "-errored-only", "switchports"}
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.Command != "switchports" || !cfg.ErroredOnly {
+			t.Errorf("Expected the switchports command with ErroredOnly, got %+v", cfg)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-all", "-errored-only", "stacks"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "-errored-only can only be used with the switchports command") {
+			t.Errorf("Expected a switchports command error, got: %v", err)
+		}
+	})
+
+	t.Run("normalize-subnets flag", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-normalize-subnets", "route-tables"}
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !cfg.NormalizeSubnets {
+			t.Error("Expected NormalizeSubnets to be set")
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-normalize-subnets", "licenses"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "-normalize-subnets can only be used with the route-tables command") {
+			t.Errorf("Expected a route-tables command error, got: %v", err)
+		}
+	})
+
+	t.Run("device command", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+		os.Unsetenv("MERAKI_ORG")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-serial", "Q2AA-0001", "device"}
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.Command != "device" || cfg.SerialFilter != "Q2AA-0001" || cfg.InfoAll {
+			t.Errorf("Expected a device lookup without -org or -all, got %+v", cfg)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "device"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "device command requires -serial") {
+			t.Errorf("Expected a missing -serial error, got: %v", err)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-network", "N_1", "-serial", "Q2AA-0001", "device"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "device command with -serial outputs a single device and cannot be used with -all, -network or -networks-file") {
+			t.Errorf("Expected a -network error, got: %v", err)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-serial", "Q2AA-0001", "licenses"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "-serial can only be used with the down, alerting, device and switchports commands") {
+			t.Errorf("Expected a -serial command error, got: %v", err)
+		}
+	})
+
+	t.Run("port-statuses command", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+		os.Unsetenv("MERAKI_ORG")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-device-serial", "Q2SW-0001", "port-statuses"}
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.Command != "switchports" || cfg.SerialFilter != "Q2SW-0001" || cfg.InfoAll {
+			t.Errorf("Expected a single switch lookup without -org or -all, got %+v", cfg)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "port-statuses"}
+		cfg, err = parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.Command != "switchports" || !cfg.InfoAll {
+			t.Errorf("Expected every switch of the organization without -serial, got %+v", cfg)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-all", "-serial", "Q2SW-0001", "switchports"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "switchports command with -serial outputs a single device") {
+			t.Errorf("Expected an -all error, got: %v", err)
+		}
+	})
+
+	t.Run("manifest flag", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-output", "routes-{network}.json", "-manifest", "manifest.json", "route-tables"}
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.Manifest != "manifest.json" {
+			t.Errorf("Expected Manifest to be set, got %q", cfg.Manifest)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-manifest", "manifest.json", "route-tables"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "-manifest requires -output to be a file") {
+			t.Errorf("Expected an -output error, got: %v", err)
+		}
+	})
+
+	t.Run("json-envelope flag", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-format", "json", "-json-envelope", "route-tables"}
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !cfg.JSONEnvelope {
+			t.Error("Expected JSONEnvelope to be set")
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-secondary-output", "json:routes.json", "-json-envelope", "route-tables"}
+		if _, err := parseConfigWithValidation(); err != nil {
+			t.Errorf("Expected a json secondary output to allow -json-envelope, got: %v", err)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-format", "csv", "-json-envelope", "route-tables"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "-json-envelope requires -format json") {
+			t.Errorf("Expected a JSON format error, got: %v", err)
+		}
+	})
+
+	t.Run("change-log command", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-network", "HQ", "-admin", "ann@example.com", "-since", "7d", "change-log"}
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.Command != "change-log" || cfg.Admin != "ann@example.com" || cfg.Since.IsZero() || cfg.InfoAll {
+			t.Errorf("Expected a single-network change-log run for the admin, got %+v", cfg)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-network", "HQ-*", "change-log"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "network patterns are not supported") {
+			t.Errorf("Expected a network pattern error, got: %v", err)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-admin", "ann", "events"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "-admin can only be used with the change-log command") {
+			t.Errorf("Expected a change-log command error, got: %v", err)
+		}
+	})
+
+	t.Run("geojson format", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-format", "geojson", "locations"}
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.Command != "locations" || !cfg.InfoAll {
+			t.Errorf("Expected an organization-wide locations run, got %+v", cfg)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-format", "geojson", "licenses"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "-format geojson can only be used with the locations, down and alerting commands") {
+			t.Errorf("Expected a device command error, got: %v", err)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-format", "geojson", "-summary", "locations"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "-format geojson cannot be used with -summary") {
+			t.Errorf("Expected a -summary error, got: %v", err)
+		}
+	})
+
+	t.Run("perf command", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "perf"}
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.Timespan != DefaultPerfTimespan || cfg.Threshold != -1 {
+			t.Errorf("Expected the default timespan without a threshold, got %s, %g", cfg.Timespan, cfg.Threshold)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-timespan", "1d", "-threshold", "80", "perf"}
+		if cfg, err = parseConfigWithValidation(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.Timespan != 24*time.Hour || cfg.Threshold != 80 {
+			t.Errorf("Expected a 1 day window above 80, got %s, %g", cfg.Timespan, cfg.Threshold)
+		}
+
+		tests := []struct {
+			args     []string
+			expected string
+		}{
+			{args: []string{"-timespan", "15d", "perf"}, expected: "invalid -timespan"},
+			{args: []string{"-threshold", "-5", "perf"}, expected: "-threshold must not be negative"},
+			{args: []string{"-threshold", "5", "down"}, expected: "-threshold can only be used with the perf command"},
+			{args: []string{"-detailed", "perf"}, expected: "-detailed can only be used with the api-usage command"},
+		}
+		for _, tt := range tests {
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+			os.Args = append([]string{"meraki-info", "-org", "test-org"}, tt.args...)
+			if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q for %v, got: %v", tt.expected, tt.args, err)
+			}
+		}
+	})
+
+	t.Run("merge-org-licenses flag", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-all", "-merge-org-licenses", "licenses"}
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !cfg.MergeLicenses {
+			t.Error("Expected MergeLicenses to be set")
+		}
+
+		tests := []struct {
+			args     []string
+			expected string
+		}{
+			{args: []string{"-merge-org-licenses", "down"}, expected: "-merge-org-licenses can only be used with the licenses command"},
+			{args: []string{"-merge-org-licenses", "-summary", "licenses"}, expected: "cannot use -merge-org-licenses with -summary"},
+			{args: []string{"-merge-org-licenses", "-sort", "State", "licenses"}, expected: "cannot use -merge-org-licenses with -summary, -sort"},
+			{args: []string{"-merge-org-licenses", "-format", "prometheus", "licenses"}, expected: "-format prometheus"},
+		}
+		for _, tt := range tests {
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+			os.Args = append([]string{"meraki-info", "-org", "test-org"}, tt.args...)
+			if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q for %v, got: %v", tt.expected, tt.args, err)
+			}
+		}
+	})
+
+	t.Run("csv-delimiter and csv-bom flags", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "licenses"}
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.CSVDelimiter != ',' {
+			t.Errorf("Expected comma by default, got %q", cfg.CSVDelimiter)
+		}
+
+		delimiters := map[string]rune{"semicolon": ';', "TAB": '\t', "comma": ','}
+		for name, expected := range delimiters {
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+			os.Args = []string{"meraki-info", "-org", "test-org", "-format", "csv", "-csv-delimiter", name, "licenses"}
+			cfg, err := parseConfigWithValidation()
+			if err != nil {
+				t.Fatalf("Unexpected error for %s: %v", name, err)
+			}
+			if cfg.CSVDelimiter != expected {
+				t.Errorf("Expected %q for %s, got %q", expected, name, cfg.CSVDelimiter)
+			}
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-csv-delimiter", "tab", "-secondary-output", "csv:out.csv", "licenses"}
+		if _, err := parseConfigWithValidation(); err != nil {
+			t.Errorf("Expected a csv secondary output to accept -csv-delimiter, got: %v", err)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-format", "csv", "-csv-bom", "licenses"}
+		if cfg, err = parseConfigWithValidation(); err != nil || !cfg.CSVBOM {
+			t.Errorf("Expected -csv-bom to be accepted with -format csv, got %v", err)
+		}
+
+		tests := []struct {
+			args     []string
+			expected string
+		}{
+			{args: []string{"-format", "csv", "-csv-delimiter", "pipe", "licenses"}, expected: "invalid -csv-delimiter 'pipe'"},
+			{args: []string{"-format", "json", "-csv-delimiter", "semicolon", "licenses"}, expected: "-csv-delimiter requires -format csv"},
+			{args: []string{"-csv-bom", "licenses"}, expected: "-csv-bom requires -format csv"},
+		}
+		for _, tt := range tests {
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+			os.Args = append([]string{"meraki-info", "-org", "test-org"}, tt.args...)
+			if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q for %v, got: %v", tt.expected, tt.args, err)
+			}
+		}
+	})
+
+	t.Run("template format", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+		dir := t.TempDir()
+		valid := filepath.Join(dir, "down.tmpl")
+		broken := filepath.Join(dir, "broken.tmpl")
+		os.WriteFile(valid, []byte("{{range .Items}}{{.Serial | pad 12}}\n{{end}}"), 0644)
+		os.WriteFile(broken, []byte("ok\n{{range .Items}}\n{{end"), 0644)
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-format", "template", "-template", valid, "down"}
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.TemplateFile != valid {
+			t.Errorf("Expected template file %s, got %s", valid, cfg.TemplateFile)
+		}
+
+		tests := []struct {
+			args     []string
+			expected string
+		}{
+			{args: []string{"-format", "template", "down"}, expected: "-format template requires -template FILE"},
+			{args: []string{"-template", valid, "down"}, expected: "-template requires -format template"},
+			{args: []string{"-format", "template", "-template", broken, "down"}, expected: "broken.tmpl:3"},
+			{args: []string{"-format", "template", "-template", filepath.Join(dir, "missing.tmpl"), "down"}, expected: "invalid -template"},
+			{args: []string{"-format", "template", "-template", valid, "-summary", "down"}, expected: "-summary cannot be used with -format template"},
+			{args: []string{"-secondary-output", "template:" + filepath.Join(dir, "down.txt"), "-template", valid, "-summary", "down"}, expected: "-summary cannot be used with -format template"},
+			{args: []string{"-format", "template", "-template", valid, "-all", "-run-summary", "down"}, expected: "-format template cannot be used with -run-summary"},
+		}
+		for _, tt := range tests {
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+			os.Args = append([]string{"meraki-info", "-org", "test-org"}, tt.args...)
+			if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q for %v, got: %v", tt.expected, tt.args, err)
+			}
+		}
+	})
+
+	t.Run("health-alerts flag", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-health-alerts", "alerting"}
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !cfg.HealthAlerts {
+			t.Error("Expected HealthAlerts to be set")
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-health-alerts", "down"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "-health-alerts can only be used with the alerting command") {
+			t.Errorf("Expected -health-alerts to be rejected for down, got: %v", err)
+		}
+	})
+
+	t.Run("network patterns", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		for _, command := range []string{"route-tables", "licenses", "stacks", "status-summary", "dhcp", "firewall"} {
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+			os.Args = []string{"meraki-info", "-org", "test-org", "-network", "Store-*", command}
+			if _, err := parseConfigWithValidation(); err != nil {
+				t.Errorf("Expected a network pattern to be accepted for %s, got: %v", command, err)
+			}
+		}
+
+		for _, command := range []string{"switchports", "perf", "locations"} {
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+			os.Args = []string{"meraki-info", "-org", "test-org", "-network", "Store-*", command}
+			if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "network patterns are not supported for the "+command+" command") {
+				t.Errorf("Expected a network pattern error for %s, got: %v", command, err)
+			}
+		}
+	})
+
+	t.Run("default-routes-only flag", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-default-routes-only", "route-tables"}
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !cfg.DefaultRoutesOnly {
+			t.Error("Expected DefaultRoutesOnly to be set")
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-default-routes-only", "dhcp"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "-default-routes-only can only be used with the route-tables command") {
+			t.Errorf("Expected a route-tables command error, got: %v", err)
+		}
+	})
+
+	t.Run("no-dedup flag", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-no-dedup", "route-tables"}
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !cfg.NoDedup {
+			t.Error("Expected NoDedup to be set")
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-no-dedup", "down"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "-no-dedup can only be used with the route-tables command") {
+			t.Errorf("Expected a route-tables command error, got: %v", err)
+		}
+	})
+
+	t.Run("detect-overlaps flag", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-detect-overlaps", "route-tables"}
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !cfg.DetectOverlaps {
+			t.Error("Expected DetectOverlaps to be set")
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-detect-overlaps", "down"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "-detect-overlaps can only be used with the route-tables command") {
+			t.Errorf("Expected a route-tables command error, got: %v", err)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-detect-overlaps", "-sort", "Subnet", "route-tables"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "cannot use -detect-overlaps and -sort together") {
+			t.Errorf("Expected a -sort conflict error, got: %v", err)
+		}
+	})
+
+	t.Run("no-synthetic-names flag", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-no-synthetic-names", "route-tables"}
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !cfg.NoSyntheticNames {
+			t.Error("Expected NoSyntheticNames to be set")
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-no-synthetic-names", "licenses"}
+		if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "-no-synthetic-names can only be used with the route-tables command") {
+			t.Errorf("Expected route-tables only error, got: %v", err)
+		}
+	})
+
+	t.Run("compress flag", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-compress", "-output", "routes-{date}.json", "route-tables"}
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !cfg.Compress || cfg.OutputFile != "routes-{date}.json" {
+			t.Errorf("Expected compression with the unexpanded output pattern, got %v, %q", cfg.Compress, cfg.OutputFile)
+		}
+
+		for _, output := range []string{"", "-", "https://example.com/hook"} {
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+			os.Args = []string{"meraki-info", "-org", "test-org", "-compress", "-output", output, "route-tables"}
+			if _, err := parseConfigWithValidation(); err == nil || !strings.Contains(err.Error(), "-compress requires -output to be a file") {
+				t.Errorf("Expected a file output error for -output %q, got: %v", output, err)
+			}
+		}
+	})
+
+	t.Run("unknown flag returns a flag parse error", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		flag.CommandLine.SetOutput(io.Discard)
+		os.Args = []string{"meraki-info", "-bogus", "down"}
+
+		if _, err := parseConfigWithValidation(); !errors.Is(err, errFlagParse) {
+			t.Errorf("Expected flag parse error, got: %v", err)
+		}
+	})
+
+	t.Run("enabled-only and disabled-only are mutually exclusive", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-org", "test-org", "-enabled-only", "-disabled-only", "route-tables"}
+
+		_, err := parseConfigWithValidation()
+		if err == nil || !strings.Contains(err.Error(), "-enabled-only and -disabled-only") {
+			t.Errorf("Expected mutually exclusive error, got: %v", err)
+		}
+	})
+
+	t.Run("options followed by command format", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "test-key")
+
+		// Reset flags and set test args with options in various orders, then command
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-all", "-output", "test.txt", "-org", "test-org", "-format", "json", "licenses"}
+
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Errorf("Expected no error when using [OPTIONS] COMMAND format, got: %v", err)
+		}
+
+		if cfg.Command != "licenses" {
+			t.Errorf("Expected Command 'licenses', got '%s'", cfg.Command)
+		}
+		if cfg.OutputFile != "test.txt" {
+			t.Errorf("Expected OutputFile 'test.txt', got '%s'", cfg.OutputFile)
+		}
+		if cfg.OutputType != "json" {
+			t.Errorf("Expected OutputType 'json', got '%s'", cfg.OutputType)
+		}
+		if !cfg.InfoAll {
+			t.Error("Expected InfoAll to be true")
+		}
+	})
+}
+
+func TestParseTimeFlag(t *testing.T) {
+	reference := time.Date(2025, 7, 17, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value    string
+		expected time.Time
+		wantErr  bool
+	}{
+		{"2025-07-16T22:00:00Z", time.Date(2025, 7, 16, 22, 0, 0, 0, time.UTC), false},
+		{"2025-07-16T22:00:00+02:00", time.Date(2025, 7, 16, 20, 0, 0, 0, time.UTC), false},
+		{"24h", reference.Add(-24 * time.Hour), false},
+		{"90m", reference.Add(-90 * time.Minute), false},
+		{"7d", reference.Add(-7 * 24 * time.Hour), false},
+		{"-1h", time.Time{}, true},
+		{"xd", time.Time{}, true},
+		{"yesterday", time.Time{}, true},
+		{"2025-07-16", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseTimeFlag("since", tt.value, reference)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "invalid -since") {
+					t.Errorf("Expected invalid -since error, got %v (%s)", err, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !got.Equal(tt.expected) {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseConfig_ConfigFile(t *testing.T) {
+	originalKey, originalOrg := os.Getenv("MERAKI_APIKEY"), os.Getenv("MERAKI_ORG")
+	defer func() {
+		os.Setenv("MERAKI_APIKEY", originalKey)
+		os.Setenv("MERAKI_ORG", originalOrg)
+	}()
+
+	dir := t.TempDir()
+	yamlFile := filepath.Join(dir, "meraki-info.yaml")
+	yamlContent := `# Sample settings
+org: "123456"
+apikey: file-key
+format: json
+loglevel: debug
+connect_retries: 3
+tag:
+  - retail
+  - east
+output: https://hooks.example.com/meraki
+output-header: "Authorization: Bearer abc # not a comment"
+`
+	if err := os.WriteFile(yamlFile, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	tomlFile := filepath.Join(dir, "meraki-info.toml")
+	tomlContent := `org = "654321"  # trailing comment
+apikey = "file-key"
+format = "csv"
+tag = ["retail", "west"]
+`
+	if err := os.WriteFile(tomlFile, []byte(tomlContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	t.Run("yaml file supplies values", func(t *testing.T) {
+		os.Unsetenv("MERAKI_APIKEY")
+		os.Unsetenv("MERAKI_ORG")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-config", yamlFile, "down"}
+
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.Organization != "123456" || cfg.APIKey != "file-key" || cfg.OutputType != "json" || cfg.LogLevel != "debug" || cfg.ConnectRetries != 3 {
+			t.Errorf("Expected settings from file, got org=%s apikey=%s format=%s loglevel=%s connect-retries=%d", cfg.Organization, cfg.APIKey, cfg.OutputType, cfg.LogLevel, cfg.ConnectRetries)
+		}
+		if strings.Join(cfg.Tags, ",") != "retail,east" {
+			t.Errorf("Expected tags from file, got %v", cfg.Tags)
+		}
+		if len(cfg.OutputHeaders) != 1 || cfg.OutputHeaders[0] != "Authorization: Bearer abc # not a comment" {
+			t.Errorf("Expected quoted header from file, got %v", cfg.OutputHeaders)
+		}
+	})
+
+	t.Run("flags override env which overrides the file", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "env-key")
+		os.Setenv("MERAKI_ORG", "env-org")
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-config", tomlFile, "-org", "flag-org", "route-tables"}
+
+		cfg, err := parseConfigWithValidation()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.Organization != "flag-org" {
+			t.Errorf("Expected flag to override file and env, got org %s", cfg.Organization)
+		}
+		if cfg.APIKey != "env-key" {
+			t.Errorf("Expected env to override file, got apikey %s", cfg.APIKey)
+		}
+		if cfg.OutputType != "csv" || strings.Join(cfg.Tags, ",") != "retail,west" {
+			t.Errorf("Expected file values where nothing overrides them, got format=%s tags=%v", cfg.OutputType, cfg.Tags)
+		}
+	})
+
+	t.Run("unknown setting should return error", func(t *testing.T) {
+		os.Setenv("MERAKI_APIKEY", "env-key")
+		badFile := filepath.Join(dir, "bad.yaml")
+		if err := os.WriteFile(badFile, []byte("colour: blue\n"), 0644); err != nil {
+			t.Fatalf("Failed to write config file: %v", err)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		os.Args = []string{"meraki-info", "-config", badFile, "-org", "test-org", "down"}
+
+		_, err := parseConfigWithValidation()
+		if err == nil || !strings.Contains(err.Error(), "unknown setting 'colour'") {
+			t.Errorf("Expected unknown setting error, got: %v", err)
+		}
+	})
+}
+
+// fakeKeychain serves secrets keyed by "service/account"
+type fakeKeychain map[string]string
+
+func (k fakeKeychain) Lookup(service, account string) (string, error) {
+	secret, ok := k[service+"/"+account]
+	if !ok {
+		return "", errors.New("secret not found")
+	}
+	return secret, nil
 }
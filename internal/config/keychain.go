@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Keychain service and account under which -apikey-keychain looks up the API key
+const (
+	keychainService = "meraki-info"
+	keychainAccount = "apikey"
+)
+
+// keychainReader looks up a secret stored in the OS keychain
+type keychainReader interface {
+	Lookup(service, account string) (string, error)
+}
+
+// keychain is the keychain used by -apikey-keychain; tests replace it
+var keychain keychainReader = systemKeychain{}
+
+// systemKeychain reads secrets with the platform's keychain tool: security on macOS and
+// secret-tool (libsecret) elsewhere
+type systemKeychain struct{}
+
+// Lookup returns the secret stored for service and account
+func (systemKeychain) Lookup(service, account string) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", service, "-a", account, "-w")
+	case "windows":
+		return "", fmt.Errorf("the OS keychain is not supported on windows")
+	default:
+		cmd = exec.Command("secret-tool", "lookup", "service", service, "account", account)
+	}
+
+	// Only the exit status is reported; the tool's output may contain the secret
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%s failed: %w", cmd.Args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -2,16 +2,34 @@
 package meraki
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"math"
+	"math/rand/v2"
+	"net"
 	"net/http"
+	"net/netip"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"meraki-info/internal/version"
+
 	"golang.org/x/oauth2"
 )
 
@@ -24,12 +42,14 @@ type Route struct {
 	GatewayVlan int         `json:"gatewayVlanId,omitempty"`
 	Enabled     bool        `json:"enabled"`
 	FixedIP     interface{} `json:"fixedIpAssignments,omitempty"`
+	VPNMode     string      `json:"vpnMode,omitempty"`
 }
 
 // NetworkRoutes represents routes for a specific network
 type NetworkRoutes struct {
 	Network Network `json:"network"`
 	Routes  []Route `json:"routes"`
+	Error   string  `json:"error,omitempty"` // Set when the network's routes could not be fetched
 }
 
 // License represents a Meraki license
@@ -47,6 +67,7 @@ type License struct {
 	OrderNumber       string `json:"orderNumber,omitempty"`
 	PermanentlyQueued bool   `json:"permanentlyQueued,omitempty"`
 	DurationInDays    int    `json:"durationInDays,omitempty"`
+	NetworkName       string `json:"networkName,omitempty"` // Set by ResolveLicenseNetworks, not returned by the API
 }
 
 // NetworkLicenses represents licenses for a specific network
@@ -70,6 +91,9 @@ type Device struct {
 	Lat            float64  `json:"lat,omitempty"`
 	Lng            float64  `json:"lng,omitempty"`
 	Notes          string   `json:"notes,omitempty"`
+	DownDuration   string   `json:"downDuration,omitempty"`  // Computed from LastReportedAt for down devices
+	WarmSpareRole  string   `json:"warmSpareRole,omitempty"` // primary or spare for appliances in a warm spare pair
+	HealthAlert    string   `json:"healthAlert,omitempty"`   // Most severe health alert as "severity: type", set by MergeHealthAlerts
 	BeaconIdParams struct {
 		UUID  string `json:"uuid,omitempty"`
 		Major int    `json:"major,omitempty"`
@@ -80,9 +104,24 @@ type Device struct {
 // RouteWithNetwork extends the Route struct to include network and organization information
 type RouteWithNetwork struct {
 	Route
-	NetworkID    string `json:"network_id" xml:"NetworkID" csv:"network_id"`
-	NetworkName  string `json:"network_name" xml:"NetworkName" csv:"network_name"`
-	Organization string `json:"organization" xml:"Organization" csv:"organization"`
+	NetworkID      string `json:"network_id" xml:"NetworkID" csv:"network_id"`
+	NetworkName    string `json:"network_name" xml:"NetworkName" csv:"network_name"`
+	Organization   string `json:"organization" xml:"Organization" csv:"organization"`
+	OrganizationID string `json:"organization_id" xml:"OrganizationID" csv:"organization_id"`
+}
+
+// Relations between the subnets of a RouteOverlap
+const (
+	OverlapEqual    = "equal"    // Both routes have the same prefix
+	OverlapContains = "contains" // The route's prefix contains the other, longer prefix
+)
+
+// RouteOverlap is a pair of routes whose subnets overlap. Route holds the shorter prefix, so it
+// either equals or contains the subnet of OverlapsWith.
+type RouteOverlap struct {
+	Route        RouteWithNetwork `json:"route"`
+	OverlapsWith RouteWithNetwork `json:"overlaps_with"`
+	Relation     string           `json:"relation"`
 }
 
 // DeviceWithNetwork extends the Device struct to include network and organization information
@@ -103,9 +142,27 @@ type LicenseWithNetwork struct {
 
 // DeviceStatus represents the status information for a device from the organization statuses endpoint
 type DeviceStatus struct {
-	Serial string `json:"serial"`
-	Name   string `json:"name,omitempty"`
-	Status string `json:"status"`
+	Serial      string `json:"serial"`
+	Name        string `json:"name,omitempty"`
+	Status      string `json:"status"`
+	Model       string `json:"model,omitempty"`
+	NetworkID   string `json:"networkId,omitempty"`
+	ProductType string `json:"productType,omitempty"`
+}
+
+// DeviceStatusSummary holds device status counts for a network, a product type, or a grand total
+type DeviceStatusSummary struct {
+	Scope          string `json:"scope"` // network, productType, or total
+	Organization   string `json:"organization,omitempty"`
+	OrganizationID string `json:"organization_id,omitempty"`
+	NetworkID      string `json:"network_id,omitempty"`
+	NetworkName    string `json:"network_name,omitempty"`
+	ProductType    string `json:"product_type,omitempty"`
+	Online         int    `json:"online"`
+	Offline        int    `json:"offline"`
+	Alerting       int    `json:"alerting"`
+	Dormant        int    `json:"dormant"`
+	Total          int    `json:"total"`
 }
 
 // NetworkDevices represents devices for a specific network
@@ -132,25 +189,220 @@ func DefaultRetryConfig() RetryConfig {
 	}
 }
 
+// DefaultBaseURL is the Meraki Dashboard API endpoint for the global cloud
+const DefaultBaseURL = "https://api.meraki.com/api/v1"
+
 // Client represents a Meraki API client
 type Client struct {
 	httpClient  *http.Client
 	baseURL     string
 	apiKey      string
 	retryConfig RetryConfig
+	vpnMode     string // Only include VPN routes from networks in this site-to-site mode (hub, spoke, none)
+
+	keepDuplicateRoutes bool // Return routes reported by several sources once per source instead of merging them
+	keepEmptyRouteNames bool // Leave routes without an API name unnamed instead of synthesizing one
+	normalizeSubnets    bool // Rewrite route subnets with host bits set to their network address before deduplication
+
+	downLongerThan  time.Duration // Only report down devices unreachable for longer than this
+	deviceFilter    DeviceFilter  // Model/product type/tag filters applied to down and alerting devices
+	ignoreWarmSpare bool          // Drop down warm spares whose primary is online
+	tagFilter       TagFilter     // Network and device tags selected with -tag
+	networkTags     []string      // Networks listed for -all runs are restricted by the API to these tags
+	networkTagsAll  bool          // Whether networks must carry all of the networkTags rather than any
+	excludeNetworks []string      // Networks skipped by -all runs, by ID or name
+
+	requestCount atomic.Int64 // HTTP requests sent, including retries
+	limiter      *rateLimiter // Shared by every request so concurrent callers stay under one rate; nil means unlimited
+
+	connectRetries int         // Extra attempts when the very first connection cannot be established
+	connected      atomic.Bool // Set once any request has reached the API
+
+	etags *etagCache // Bodies of GET responses by URL, revalidated with If-None-Match; nil means no cache
+
+	// Organizations and networks are fetched once per run and reused by every later lookup
+	cacheMu       sync.Mutex
+	organizations []Organization       // nil until the organizations have been fetched
+	networks      map[string][]Network // Networks by organization ID, plus "?query" for tag-filtered lookups
+}
+
+// rateLimiter is a token bucket limiting the aggregate request rate of a Client. Callers reserve a
+// token under the lock and sleep outside it, so any number of goroutines can share one limiter.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration // Time to earn one token
+	burst    float64       // Maximum tokens that can accumulate
+	tokens   float64
+	last     time.Time
+}
+
+// newRateLimiter creates a limiter allowing requestsPerSecond on average and bursts of up to burst requests
+func newRateLimiter(requestsPerSecond float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		interval: time.Duration(float64(time.Second) / requestsPerSecond),
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		last:     time.Now(),
+	}
+}
+
+// wait blocks until the caller may send a request
+func (l *rateLimiter) wait() {
+	l.mu.Lock()
+	current := time.Now()
+	l.tokens = math.Min(l.burst, l.tokens+float64(current.Sub(l.last))/float64(l.interval))
+	l.last = current
+	l.tokens--
+	var delay time.Duration
+	if l.tokens < 0 {
+		// The token is borrowed from the future; later callers queue up behind it
+		delay = time.Duration(-l.tokens * float64(l.interval))
+	}
+	l.mu.Unlock()
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+}
+
+// etagCache persists the ETag and body of GET responses by URL, so repeated runs can send
+// If-None-Match and reuse the body when the API answers 304 Not Modified. Entries are kept in
+// memory and written to the file once, by save, at the end of the run.
+type etagCache struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string]etagEntry
+	dirty   bool // entries changed since the file was read or last saved
 }
 
+// etagEntry is a cached response: its ETag, body and the Link header needed to continue paging
+type etagEntry struct {
+	ETag string `json:"etag"`
+	Link string `json:"link,omitempty"`
+	Body []byte `json:"body"`
+}
+
+// loadETagCache reads the cache file at path. A missing file starts an empty cache; an unreadable
+// one is reported and replaced when the cache is saved.
+func loadETagCache(path string) (*etagCache, error) {
+	cache := &etagCache{path: path, entries: make(map[string]etagEntry)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ETag cache: %w", err)
+	}
+	if err := json.Unmarshal(data, &cache.entries); err != nil {
+		slog.Warn("Ignoring unreadable ETag cache", "file", path, "error", err)
+		cache.entries = make(map[string]etagEntry)
+	}
+	return cache, nil
+}
+
+// get returns the cached response for url
+func (e *etagCache) get(url string) (etagEntry, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	entry, ok := e.entries[url]
+	return entry, ok
+}
+
+// put stores the response for url, or drops a stale entry when the response has no ETag
+func (e *etagCache) put(url string, entry etagEntry) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if entry.ETag == "" {
+		if _, ok := e.entries[url]; !ok {
+			return
+		}
+		delete(e.entries, url)
+	} else {
+		e.entries[url] = entry
+	}
+	e.dirty = true
+}
+
+// save writes the cache file when entries changed. It writes a temporary file in the same
+// directory and renames it over the cache, so an interrupted write never leaves a torn cache and
+// concurrent runs sharing the file do not write to the same temporary file.
+func (e *etagCache) save() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.dirty {
+		return nil
+	}
+	data, err := json.Marshal(e.entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode ETag cache: %w", err)
+	}
+	temp, err := os.CreateTemp(filepath.Dir(e.path), filepath.Base(e.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write ETag cache: %w", err)
+	}
+	defer os.Remove(temp.Name())
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return fmt.Errorf("failed to write ETag cache: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("failed to write ETag cache: %w", err)
+	}
+	if err := os.Rename(temp.Name(), e.path); err != nil {
+		return fmt.Errorf("failed to write ETag cache: %w", err)
+	}
+	e.dirty = false
+	return nil
+}
+
+// now returns the current time; overridden in tests
+var now = time.Now
+
+// ErrServiceUnavailable is returned when the API still answers 503 Service Unavailable after every
+// retry, as it does during Meraki maintenance
+var ErrServiceUnavailable = errors.New("Meraki API appears to be unavailable (503); try again later")
+
+// ErrBadRequest is returned when the API rejects a request with 400, as it does for settings of a
+// feature the network does not have, such as the SSIDs of a network without wireless
+var ErrBadRequest = errors.New("API request failed with status 400")
+
+// ErrForbidden is returned when the API refuses a request with 401 or 403, typically because the
+// API key has no access to the organization
+var ErrForbidden = errors.New("API access denied")
+
+// ErrNotFound is returned when the API answers 404, as it does for a device serial that does not
+// exist or belongs to an organization the API key cannot see
+var ErrNotFound = errors.New("API resource not found")
+
+// connectRetryDelay is the pause between initial connection attempts; overridden in tests
+var connectRetryDelay = 500 * time.Millisecond
+
 // NewClient creates a new Meraki API client
 func NewClient(apiKey string) (*Client, error) {
+	return NewClientWithBaseURL(apiKey, DefaultBaseURL)
+}
+
+// NewClientWithBaseURL creates a new Meraki API client that talks to the given base URL.
+// This is needed for the regional clouds (Canada, China) and the FedRAMP government cloud.
+func NewClientWithBaseURL(apiKey, baseURL string) (*Client, error) {
 	if apiKey == "" {
 		return nil, fmt.Errorf("API key cannot be empty")
 	}
 
+	normalizedURL, err := normalizeBaseURL(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
 	// For production, you might want to implement proper OAuth2 flow
 	// This is a simplified version using API key authentication
 	client := &http.Client{
 		Timeout: time.Second * 30,
 		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
 			TLSClientConfig: &tls.Config{
 				MinVersion: tls.VersionTLS12,
 				// Could add certificate pinning for extra security
@@ -163,12 +415,30 @@ func NewClient(apiKey string) (*Client, error) {
 
 	return &Client{
 		httpClient:  client,
-		baseURL:     "https://api.meraki.com/api/v1",
+		baseURL:     normalizedURL,
 		apiKey:      apiKey,
 		retryConfig: DefaultRetryConfig(),
 	}, nil
 }
 
+// normalizeBaseURL validates that a base URL is an absolute https URL and strips trailing slashes
+func normalizeBaseURL(baseURL string) (string, error) {
+	if baseURL == "" {
+		return DefaultBaseURL, nil
+	}
+
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid base URL '%s': %w", baseURL, err)
+	}
+
+	if parsed.Scheme != "https" || parsed.Host == "" {
+		return "", fmt.Errorf("invalid base URL '%s': must be an absolute https URL", baseURL)
+	}
+
+	return strings.TrimRight(baseURL, "/"), nil
+}
+
 // NewClientWithOAuth2 creates a new Meraki API client with OAuth2 authentication
 func NewClientWithOAuth2(clientID, clientSecret, token string) (*Client, error) {
 	config := &oauth2.Config{
@@ -189,7 +459,7 @@ func NewClientWithOAuth2(clientID, clientSecret, token string) (*Client, error)
 
 	return &Client{
 		httpClient:  client,
-		baseURL:     "https://api.meraki.com/api/v1",
+		baseURL:     DefaultBaseURL,
 		retryConfig: DefaultRetryConfig(),
 	}, nil
 }
@@ -212,23 +482,52 @@ func isRetryableError(err error, statusCode int) bool {
 	}
 }
 
-// calculateBackoff calculates the backoff duration for a retry attempt
-func (c *Client) calculateBackoff(attempt int) time.Duration {
-	if attempt <= 0 {
-		return c.retryConfig.InitialInterval
+// Interval returns the exponential backoff interval after the zero-based attempt that failed,
+// capped at the maximum
+func (r RetryConfig) Interval(attempt int) time.Duration {
+	backoff := r.InitialInterval
+	if attempt > 0 {
+		backoff = time.Duration(float64(r.InitialInterval) * math.Pow(r.Multiplier, float64(attempt-1)))
 	}
 
-	// Exponential backoff with jitter
-	backoff := time.Duration(float64(c.retryConfig.InitialInterval) * math.Pow(c.retryConfig.Multiplier, float64(attempt-1)))
-
 	// Cap at maximum interval
-	if backoff > c.retryConfig.MaxInterval {
-		backoff = c.retryConfig.MaxInterval
+	if backoff > r.MaxInterval {
+		backoff = r.MaxInterval
 	}
 
 	return backoff
 }
 
+// Backoff returns the delay after a failed attempt using full jitter: a random duration between 0
+// and the capped exponential interval, so clients retrying at the same time spread out
+func (r RetryConfig) Backoff(attempt int) time.Duration {
+	interval := r.Interval(attempt)
+	if interval <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int64N(int64(interval) + 1))
+}
+
+// RetryDelay returns the delay before retrying a request whose attempt failed with resp, which is
+// nil for network errors. A Retry-After header on 429 and 503 responses, in seconds or as an HTTP
+// date, is honored up to the maximum interval; otherwise the jittered backoff is used.
+func (r RetryConfig) RetryDelay(attempt int, resp *http.Response) time.Duration {
+	if resp == nil || (resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable) {
+		return r.Backoff(attempt)
+	}
+
+	retryAfter := resp.Header.Get("Retry-After")
+	var delay time.Duration
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+		delay = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(retryAfter); err == nil {
+		delay = max(0, time.Until(date))
+	} else {
+		return r.Backoff(attempt)
+	}
+	return min(delay, r.MaxInterval)
+}
+
 // makeRequest makes an authenticated HTTP request to the Meraki API with retry logic
 func (c *Client) makeRequest(method, endpoint string) (*http.Response, error) {
 	url := fmt.Sprintf("%s%s", c.baseURL, endpoint)
@@ -248,17 +547,30 @@ func (c *Client) makeRequest(method, endpoint string) (*http.Response, error) {
 		}
 
 		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("User-Agent", "meraki-info/1.0.0")
+		req.Header.Set("User-Agent", version.UserAgent())
+
+		var cached etagEntry
+		var hasCached bool
+		if c.etags != nil && method == "GET" {
+			if cached, hasCached = c.etags.get(url); hasCached {
+				req.Header.Set("If-None-Match", cached.ETag)
+			}
+		}
+
+		if c.limiter != nil {
+			c.limiter.wait()
+		}
 
 		slog.Debug("Making API request", "method", method, "url", url, "attempt", attempt+1)
+		c.requestCount.Add(1)
 
-		resp, err := c.httpClient.Do(req)
+		resp, err := c.do(req)
 		if err != nil {
 			lastErr = err
 			lastStatusCode = 0
 
 			if attempt < c.retryConfig.MaxRetries && isRetryableError(err, 0) {
-				backoff := c.calculateBackoff(attempt)
+				backoff := c.retryConfig.RetryDelay(attempt, nil)
 				slog.Info("Request failed, retrying", "error", err, "attempt", attempt+1, "backoff", backoff)
 				time.Sleep(backoff)
 				continue
@@ -267,6 +579,12 @@ func (c *Client) makeRequest(method, endpoint string) (*http.Response, error) {
 			return nil, fmt.Errorf("failed to make request after %d attempts: %w", attempt+1, err)
 		}
 
+		if resp.StatusCode == http.StatusNotModified && hasCached {
+			resp.Body.Close()
+			slog.Debug("API response not modified, using cached body", "method", method, "url", url)
+			return cachedResponse(resp, cached), nil
+		}
+
 		// Check for HTTP errors
 		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 			lastStatusCode = resp.StatusCode
@@ -274,17 +592,35 @@ func (c *Client) makeRequest(method, endpoint string) (*http.Response, error) {
 			resp.Body.Close()
 
 			if attempt < c.retryConfig.MaxRetries && isRetryableError(nil, resp.StatusCode) {
-				backoff := c.calculateBackoff(attempt)
+				backoff := c.retryConfig.RetryDelay(attempt, resp)
 				slog.Info("Request failed with retryable status, retrying", "status", resp.StatusCode, "attempt", attempt+1, "backoff", backoff)
 				time.Sleep(backoff)
 				continue
 			}
 
+			if resp.StatusCode == http.StatusServiceUnavailable {
+				if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+					return nil, fmt.Errorf("%w (Retry-After: %s)", ErrServiceUnavailable, retryAfter)
+				}
+				return nil, ErrServiceUnavailable
+			}
+			if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+				return nil, fmt.Errorf("%w: API request failed with status %d after %d attempts", ErrForbidden, resp.StatusCode, attempt+1)
+			}
+			if resp.StatusCode == http.StatusBadRequest {
+				return nil, fmt.Errorf("%w after %d attempts", ErrBadRequest, attempt+1)
+			}
+			if resp.StatusCode == http.StatusNotFound {
+				return nil, fmt.Errorf("%w: API request failed with status 404 after %d attempts", ErrNotFound, attempt+1)
+			}
 			return nil, fmt.Errorf("API request failed with status %d after %d attempts", resp.StatusCode, attempt+1)
 		}
 
 		// Success - return the response
 		slog.Debug("API request successful", "method", method, "url", url, "attempt", attempt+1)
+		if c.etags != nil && method == "GET" {
+			return c.cacheResponse(url, resp)
+		}
 		return resp, nil
 	}
 
@@ -295,6 +631,114 @@ func (c *Client) makeRequest(method, endpoint string) (*http.Response, error) {
 	return nil, fmt.Errorf("request failed after %d attempts with status: %d", c.retryConfig.MaxRetries+1, lastStatusCode)
 }
 
+// do sends req, retrying connection establishment failures up to connectRetries times
+// until the first request has reached the API. These retries are separate from, and
+// happen before, the status code retries in makeRequest.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	resp, err := c.httpClient.Do(req)
+	for attempt := 1; err != nil && !c.connected.Load() && attempt <= c.connectRetries && isConnectError(err); attempt++ {
+		slog.Info("Initial connection failed, retrying", "error", err, "attempt", attempt, "delay", connectRetryDelay)
+		time.Sleep(connectRetryDelay)
+		c.requestCount.Add(1)
+		resp, err = c.httpClient.Do(req)
+	}
+	if err == nil {
+		c.connected.Store(true)
+	}
+	return resp, err
+}
+
+// isConnectError reports whether err happened while establishing a connection (DNS lookup or dial)
+func isConnectError(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// cacheResponse stores a successful GET response in the ETag cache and returns it with its body
+// buffered, so the caller can still read it
+func (c *Client) cacheResponse(url string, resp *http.Response) (*http.Response, error) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	c.etags.put(url, etagEntry{ETag: resp.Header.Get("ETag"), Link: resp.Header.Get("Link"), Body: body})
+	return resp, nil
+}
+
+// cachedResponse turns a 304 Not Modified response into a 200 OK carrying the cached body
+func cachedResponse(resp *http.Response, cached etagEntry) *http.Response {
+	header := resp.Header.Clone()
+	header.Del("Link")
+	if cached.Link != "" {
+		header.Set("Link", cached.Link)
+	}
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Proto:      resp.Proto,
+		ProtoMajor: resp.ProtoMajor,
+		ProtoMinor: resp.ProtoMinor,
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewReader(cached.Body)),
+		Request:    resp.Request,
+	}
+}
+
+// SetConnectRetries sets how many times the first connection to the API is retried
+// when it cannot be established, e.g. while networking warms up in serverless environments
+func (c *Client) SetConnectRetries(retries int) {
+	c.connectRetries = retries
+}
+
+// RequestCount returns the number of HTTP requests sent to the API so far, including retries
+func (c *Client) RequestCount() int {
+	return int(c.requestCount.Load())
+}
+
+// SetRateLimit caps the aggregate rate of API requests, including retries, at requestsPerSecond
+// across every goroutine using the client, allowing bursts of up to burst requests. A rate of 0
+// removes the limit.
+func (c *Client) SetRateLimit(requestsPerSecond float64, burst int) {
+	if requestsPerSecond <= 0 {
+		c.limiter = nil
+		return
+	}
+	c.limiter = newRateLimiter(requestsPerSecond, burst)
+}
+
+// SetETagCache caches GET responses that carry an ETag in the file at path. Later requests for the
+// same URL, in this run or the next, send If-None-Match and reuse the cached body when the API
+// answers 304 Not Modified. Responses without an ETag are not cached. An empty path disables the cache.
+// The file is only written by SaveETagCache.
+func (c *Client) SetETagCache(path string) error {
+	if path == "" {
+		c.etags = nil
+		return nil
+	}
+	cache, err := loadETagCache(path)
+	if err != nil {
+		return err
+	}
+	c.etags = cache
+	return nil
+}
+
+// SaveETagCache writes the responses cached during the run to the file given to SetETagCache.
+// It does nothing when the cache is disabled or unchanged.
+func (c *Client) SaveETagCache() error {
+	if c.etags == nil {
+		return nil
+	}
+	return c.etags.save()
+}
+
 // SetRetryConfig allows customization of retry behavior
 func (c *Client) SetRetryConfig(config RetryConfig) {
 	c.retryConfig = config
@@ -305,6 +749,142 @@ func (c *Client) GetRetryConfig() RetryConfig {
 	return c.retryConfig
 }
 
+// SetDownLongerThan restricts down devices to those whose last report is older than threshold.
+// A zero threshold disables the filter.
+func (c *Client) SetDownLongerThan(threshold time.Duration) {
+	c.downLongerThan = threshold
+}
+
+// ValidateProxyURL parses an explicit proxy URL, accepting http, https and socks5 proxies
+func ValidateProxyURL(proxyURL string) (*url.URL, error) {
+	parsed, err := url.Parse(proxyURL)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL '%s': must be an absolute URL such as http://proxy:8080", proxyURL)
+	}
+
+	switch parsed.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("invalid proxy URL '%s': scheme must be http, https or socks5", proxyURL)
+	}
+
+	return parsed, nil
+}
+
+// SetProxy routes all requests through the given proxy URL, overriding HTTP_PROXY/HTTPS_PROXY
+func (c *Client) SetProxy(proxyURL string) error {
+	parsed, err := ValidateProxyURL(proxyURL)
+	if err != nil {
+		return err
+	}
+
+	transport, err := c.transport()
+	if err != nil {
+		return err
+	}
+	transport.Proxy = http.ProxyURL(parsed)
+	return nil
+}
+
+// DisableProxy connects directly to the API, ignoring any proxy environment variables
+func (c *Client) DisableProxy() error {
+	transport, err := c.transport()
+	if err != nil {
+		return err
+	}
+	transport.Proxy = nil
+	return nil
+}
+
+// ProxyURL returns the proxy that requests to the API base URL will use, or nil for a direct connection
+func (c *Client) ProxyURL() (*url.URL, error) {
+	transport, err := c.transport()
+	if err != nil {
+		return nil, err
+	}
+	if transport.Proxy == nil {
+		return nil, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, c.baseURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	return transport.Proxy(req)
+}
+
+// transport returns the client's underlying *http.Transport for configuration
+func (c *Client) transport() (*http.Transport, error) {
+	transport, ok := c.httpClient.Transport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("proxy settings are not supported for this client's transport")
+	}
+	return transport, nil
+}
+
+// SetIgnoreWarmSpare suppresses down warm spare appliances whose primary is online
+func (c *Client) SetIgnoreWarmSpare(ignore bool) {
+	c.ignoreWarmSpare = ignore
+}
+
+// SetTagFilter restricts network listings to tagged networks, and down/alerting devices to
+// those tagged themselves or sitting in a tagged network
+func (c *Client) SetTagFilter(filter TagFilter) {
+	c.tagFilter = filter
+}
+
+// SetNetworkTags restricts the networks listed for organization-wide runs to those carrying any
+// of the tags, or all of them when matchAll is set, filtered by the API rather than after
+// fetching every network
+func (c *Client) SetNetworkTags(tags []string, matchAll bool) {
+	c.networkTags = tags
+	c.networkTagsAll = matchAll
+}
+
+// SetExcludedNetworks skips the networks whose ID or name (case-insensitive) is listed in
+// organization-wide runs, before any of their data is fetched
+func (c *Client) SetExcludedNetworks(exclusions []string) {
+	c.excludeNetworks = exclusions
+}
+
+// SetDeviceFilter sets the filters applied to down and alerting device results
+func (c *Client) SetDeviceFilter(filter DeviceFilter) {
+	c.deviceFilter = filter
+}
+
+// SetKeepDuplicateRoutes returns a route once for every source reporting it, such as a VLAN
+// subnet that is also a static route, instead of merging routes with the same subnet and gateway
+func (c *Client) SetKeepDuplicateRoutes(keep bool) {
+	c.keepDuplicateRoutes = keep
+}
+
+// SetKeepEmptyRouteNames leaves routes the API returns without a name unnamed, instead of
+// synthesizing placeholders such as "Static Route 1" or "VPN Route 1"
+func (c *Client) SetKeepEmptyRouteNames(keep bool) {
+	c.keepEmptyRouteNames = keep
+}
+
+// SetNormalizeSubnets rewrites route subnets such as 192.168.1.5/24 to their network address
+// (192.168.1.0/24) before routes are deduplicated, so routes from different sources compare equal
+func (c *Client) SetNormalizeSubnets(normalize bool) {
+	c.normalizeSubnets = normalize
+}
+
+// routeName returns synthetic as the name of a route whose API name is apiName, or the empty
+// API name itself when synthetic names are disabled
+func (c *Client) routeName(apiName, synthetic string) string {
+	if apiName == "" && c.keepEmptyRouteNames {
+		return ""
+	}
+	return synthetic
+}
+
+// SetVPNModeFilter restricts VPN routes to networks whose site-to-site VPN mode matches.
+// An empty mode disables the filter.
+func (c *Client) SetVPNModeFilter(mode string) {
+	c.vpnMode = strings.ToLower(mode)
+}
+
 // GetRoutes fetches all routes for the specified organization and network
 func (c *Client) GetRoutes(organizationID, networkIdentifier string) ([]Route, error) {
 	routes := make([]Route, 0) // Initialize as empty slice instead of nil slice
@@ -325,11 +905,12 @@ func (c *Client) GetRoutes(organizationID, networkIdentifier string) ([]Route, e
 		slog.Info("Retrieved routes from specific network", "network_id", networkID, "route_count", len(networkRoutes))
 	} else {
 		// Get all networks in the organization and fetch routes for each
-		networks, err := c.getOrganizationNetworks(organizationID)
+		networks, err := c.getSelectedNetworks(organizationID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get networks for organization %s: %w", organizationID, err)
 		}
 
+		networks = FilterNetworksByTag(networks, c.tagFilter)
 		slog.Info("Found networks", "count", len(networks))
 
 		for _, network := range networks {
@@ -348,11 +929,21 @@ func (c *Client) GetRoutes(organizationID, networkIdentifier string) ([]Route, e
 
 // Network represents a Meraki network
 type Network struct {
-	ID           string   `json:"id"`
-	Name         string   `json:"name"`
-	ProductTypes []string `json:"productTypes,omitempty"`
-	TimeZone     string   `json:"timeZone,omitempty"`
-	Tags         []string `json:"tags,omitempty"`
+	ID               string   `json:"id"`
+	Name             string   `json:"name"`
+	ProductTypes     []string `json:"productTypes,omitempty"`
+	TimeZone         string   `json:"timeZone,omitempty"`
+	Tags             []string `json:"tags,omitempty"`
+	EnrollmentString string   `json:"enrollmentString,omitempty"`
+	Notes            string   `json:"notes,omitempty"`
+	URL              string   `json:"url,omitempty"`
+}
+
+// NetworkWithOrganization extends the Network struct to include organization information
+type NetworkWithOrganization struct {
+	Network
+	Organization   string `json:"organization" xml:"Organization" csv:"organization"`
+	OrganizationID string `json:"organization_id" xml:"OrganizationID" csv:"organization_id"`
 }
 
 // Organization represents a Meraki organization
@@ -376,9 +967,40 @@ type Organization struct {
 	} `json:"cloud"`
 }
 
+// AccessInfo describes the organizations and networks accessible with an API key
+type AccessInfo struct {
+	Organizations []OrganizationAccess `json:"organizations"`
+}
+
+// OrganizationAccess describes an accessible organization with its networks. Devices is nil when
+// the device status counts could not be fetched; Error is set when the networks could not be listed.
+type OrganizationAccess struct {
+	ID             string               `json:"id"`
+	Name           string               `json:"name"`
+	URL            string               `json:"url,omitempty"`
+	APIEnabled     bool                 `json:"api_enabled"`
+	LicensingModel string               `json:"licensing_model"`
+	Region         string               `json:"region"`
+	RegionHost     string               `json:"region_host"`
+	Devices        *DeviceStatusSummary `json:"devices,omitempty"`
+	Networks       []Network            `json:"networks"`
+	Error          string               `json:"error,omitempty"`
+}
+
 // getOrganizationNetworks fetches all networks in an organization
 func (c *Client) getOrganizationNetworks(organizationID string) ([]Network, error) {
-	endpoint := fmt.Sprintf("/organizations/%s/networks", organizationID)
+	return c.getCachedNetworks(organizationID, fmt.Sprintf("/organizations/%s/networks", organizationID))
+}
+
+// getCachedNetworks fetches a networks endpoint once per process; later lookups with the same key
+// are answered from the cache until InvalidateCache is called
+func (c *Client) getCachedNetworks(key, endpoint string) ([]Network, error) {
+	c.cacheMu.Lock()
+	cached, ok := c.networks[key]
+	c.cacheMu.Unlock()
+	if ok {
+		return slices.Clone(cached), nil
+	}
 
 	resp, err := c.makeRequest("GET", endpoint)
 	if err != nil {
@@ -391,9 +1013,82 @@ func (c *Client) getOrganizationNetworks(organizationID string) ([]Network, erro
 		return nil, fmt.Errorf("failed to decode networks response: %w", err)
 	}
 
+	c.cacheMu.Lock()
+	if c.networks == nil {
+		c.networks = make(map[string][]Network)
+	}
+	c.networks[key] = slices.Clone(networks)
+	c.cacheMu.Unlock()
+
 	return networks, nil
 }
 
+// GetOrganizationNetworksByTags fetches the networks in an organization carrying any of the tags,
+// or all of them when matchAll is set, using the API's tag filter so untagged networks are never transferred
+func (c *Client) GetOrganizationNetworksByTags(organizationID string, tags []string, matchAll bool) ([]Network, error) {
+	params := url.Values{}
+	if matchAll {
+		params.Set("tagsFilterType", "withAllTags")
+	} else {
+		params.Set("tagsFilterType", "withAnyTags")
+	}
+	for _, tag := range tags {
+		params.Add("tags[]", tag)
+	}
+	query := params.Encode()
+	endpoint := fmt.Sprintf("/organizations/%s/networks?%s", organizationID, query)
+	return c.getCachedNetworks(organizationID+"?"+query, endpoint)
+}
+
+// getSelectedNetworks fetches the networks an organization-wide run covers: those carrying the
+// -network-tags when set, otherwise every network in the organization, less any
+// -exclude-network entries
+func (c *Client) getSelectedNetworks(organizationID string) ([]Network, error) {
+	var networks []Network
+	var err error
+	if len(c.networkTags) > 0 {
+		networks, err = c.GetOrganizationNetworksByTags(organizationID, c.networkTags, c.networkTagsAll)
+	} else {
+		networks, err = c.getOrganizationNetworks(organizationID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ExcludeNetworks(organizationID, networks, c.excludeNetworks), nil
+}
+
+// ExcludeNetworks returns the networks whose ID or name (case-insensitive) is not among the
+// exclusions, warning about exclusions that match no network of the organization
+func ExcludeNetworks(organizationID string, networks []Network, exclusions []string) []Network {
+	if len(exclusions) == 0 {
+		return networks
+	}
+
+	matched := make(map[string]bool, len(exclusions))
+	kept := make([]Network, 0, len(networks))
+	for _, network := range networks {
+		excluded := false
+		for _, exclusion := range exclusions {
+			if network.ID == exclusion || strings.EqualFold(network.Name, exclusion) {
+				matched[exclusion] = true
+				excluded = true
+			}
+		}
+		if excluded {
+			slog.Debug("Skipping excluded network", "network_id", network.ID, "network_name", network.Name)
+			continue
+		}
+		kept = append(kept, network)
+	}
+
+	for _, exclusion := range exclusions {
+		if !matched[exclusion] {
+			slog.Warn("Network exclusion matched no network", "exclusion", exclusion, "org_id", organizationID)
+		}
+	}
+	return kept
+}
+
 // getNetworkRoutes fetches all routes for a specific network from multiple sources
 func (c *Client) getNetworkRoutes(networkID string) ([]Route, error) {
 	var allRoutes []Route
@@ -448,7 +1143,77 @@ func (c *Client) getNetworkRoutes(networkID string) ([]Route, error) {
 		slog.Debug("Fetched switch stack routes", "network_id", networkID, "count", len(switchStackRoutes))
 	}
 
-	return allRoutes, nil
+	if c.normalizeSubnets {
+		allRoutes = NormalizeRouteSubnets(allRoutes)
+	}
+	if c.keepDuplicateRoutes {
+		return allRoutes, nil
+	}
+	routes := DeduplicateRoutes(allRoutes)
+	if len(routes) < len(allRoutes) {
+		slog.Debug("Merged duplicate routes", "network_id", networkID, "routes", len(allRoutes), "merged", len(allRoutes)-len(routes))
+	}
+	return routes, nil
+}
+
+// NormalizeRouteSubnets rewrites each route's subnet to the canonical form of its network
+// address, e.g. 192.168.1.5/24 to 192.168.1.0/24. Subnets that are not valid CIDRs are left
+// untouched with a warning.
+func NormalizeRouteSubnets(routes []Route) []Route {
+	normalized := make([]Route, len(routes))
+	for i, route := range routes {
+		normalized[i] = route
+		_, network, err := net.ParseCIDR(route.Subnet)
+		if err != nil {
+			slog.Warn("Leaving subnet that is not a valid CIDR unnormalized", "route_id", route.ID, "subnet", route.Subnet)
+			continue
+		}
+		normalized[i].Subnet = network.String()
+	}
+	return normalized
+}
+
+// DeduplicateRoutes merges routes with the same subnet and gateway IP, which happens when several
+// sources report one route, e.g. a VLAN subnet that is also a static route. The merged route takes
+// the place of the first duplicate and the fields of the first one with a name, filling fields that
+// one leaves empty from the others.
+func DeduplicateRoutes(routes []Route) []Route {
+	type routeKey struct{ subnet, gatewayIP string }
+	index := make(map[routeKey]int)
+	deduped := make([]Route, 0, len(routes))
+	for _, route := range routes {
+		key := routeKey{route.Subnet, route.GatewayIP}
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(deduped)
+			deduped = append(deduped, route)
+			continue
+		}
+		if deduped[i].Name == "" && route.Name != "" {
+			deduped[i] = mergeRoute(route, deduped[i])
+		} else {
+			deduped[i] = mergeRoute(deduped[i], route)
+		}
+	}
+	return deduped
+}
+
+// mergeRoute fills the empty fields of preferred from other. The merged route is enabled if either is.
+func mergeRoute(preferred, other Route) Route {
+	if preferred.ID == "" {
+		preferred.ID = other.ID
+	}
+	if preferred.GatewayVlan == 0 {
+		preferred.GatewayVlan = other.GatewayVlan
+	}
+	if preferred.FixedIP == nil {
+		preferred.FixedIP = other.FixedIP
+	}
+	if preferred.VPNMode == "" {
+		preferred.VPNMode = other.VPNMode
+	}
+	preferred.Enabled = preferred.Enabled || other.Enabled
+	return preferred
 }
 
 // getNetworkStaticRoutes fetches static routes for a specific network
@@ -469,7 +1234,7 @@ func (c *Client) getNetworkStaticRoutes(networkID string) ([]Route, error) {
 	// Mark these as static routes
 	for i := range routes {
 		if routes[i].Name == "" {
-			routes[i].Name = fmt.Sprintf("Static Route %d", i+1)
+			routes[i].Name = c.routeName(routes[i].Name, fmt.Sprintf("Static Route %d", i+1))
 		}
 	}
 
@@ -500,15 +1265,21 @@ func (c *Client) getNetworkVPNRoutes(networkID string) ([]Route, error) {
 		return []Route{}, nil // Not an error, just no VPN routes
 	}
 
+	mode := strings.ToLower(vpnConfig.Mode)
+	if c.vpnMode != "" && mode != c.vpnMode {
+		slog.Debug("Skipping VPN routes due to VPN mode filter", "network_id", networkID, "mode", mode, "filter", c.vpnMode)
+		return []Route{}, nil
+	}
+
 	var routes []Route
-	// Parse VPN subnets regardless of mode
 	for i, subnet := range vpnConfig.Subnets {
 		if subnet.UseVpn {
 			routes = append(routes, Route{
 				ID:      fmt.Sprintf("vpn-%d", i),
-				Name:    fmt.Sprintf("VPN Route %d", i+1),
+				Name:    c.routeName("", fmt.Sprintf("VPN Route %d", i+1)),
 				Subnet:  subnet.LocalSubnet,
 				Enabled: true, // VPN routes are enabled if useVpn is true
+				VPNMode: mode,
 			})
 		}
 	}
@@ -545,7 +1316,7 @@ func (c *Client) getNetworkVLANRoutes(networkID string) ([]Route, error) {
 		if vlan.Subnet != "" {
 			routes = append(routes, Route{
 				ID:        fmt.Sprintf("vlan-%d", vlan.ID),
-				Name:      fmt.Sprintf("VLAN %d - %s", vlan.ID, vlan.Name),
+				Name:      c.routeName(vlan.Name, fmt.Sprintf("VLAN %d - %s", vlan.ID, vlan.Name)),
 				Subnet:    vlan.Subnet,
 				GatewayIP: vlan.ApplianceIP,
 				Enabled:   true, // VLAN interfaces are enabled by default
@@ -606,7 +1377,7 @@ func (c *Client) getNetworkSwitchInterfaces(networkID string) ([]Route, error) {
 		if iface.Subnet != "" {
 			routes = append(routes, Route{
 				ID:        fmt.Sprintf("switch-iface-%s", iface.InterfaceID),
-				Name:      fmt.Sprintf("Switch Interface - %s", iface.Name),
+				Name:      c.routeName(iface.Name, fmt.Sprintf("Switch Interface - %s", iface.Name)),
 				Subnet:    iface.Subnet,
 				GatewayIP: iface.InterfaceIP,
 				Enabled:   true,
@@ -635,7 +1406,7 @@ func (c *Client) getNetworkSwitchStaticRoutes(networkID string) ([]Route, error)
 	// Mark these as switch static routes
 	for i := range routes {
 		if routes[i].Name == "" {
-			routes[i].Name = fmt.Sprintf("Switch Static Route %d", i+1)
+			routes[i].Name = c.routeName(routes[i].Name, fmt.Sprintf("Switch Static Route %d", i+1))
 		}
 	}
 
@@ -670,23 +1441,72 @@ func (c *Client) getNetworkSwitchStackRoutes(networkID string) ([]Route, error)
 		} else {
 			allRoutes = append(allRoutes, stackStaticRoutes...)
 		}
-
-		// Get DHCP information for this stack
-		stackDHCPRoutes, err := c.getSwitchStackDHCPRoutes(networkID, stack.ID)
-		if err != nil {
-			slog.Debug("Failed to get DHCP routes for stack", "network_id", networkID, "stack_id", stack.ID, "error", err)
-		} else {
-			allRoutes = append(allRoutes, stackDHCPRoutes...)
-		}
-	}
+	}
 
 	return allRoutes, nil
 }
 
 // SwitchStack represents a switch stack
 type SwitchStack struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Serials []string `json:"serials,omitempty"` // Serials of the member switches
+}
+
+// SwitchStackWithNetwork extends the SwitchStack struct to include network and organization information
+type SwitchStackWithNetwork struct {
+	SwitchStack
+	NetworkID      string `json:"network_id" xml:"NetworkID" csv:"network_id"`
+	NetworkName    string `json:"network_name" xml:"NetworkName" csv:"network_name"`
+	Organization   string `json:"organization" xml:"Organization" csv:"organization"`
+	OrganizationID string `json:"organization_id" xml:"OrganizationID" csv:"organization_id"`
+}
+
+// GetSwitchStacks lists the switch stacks of one network, or of every switch network in the organization
+func (c *Client) GetSwitchStacks(organizationID, networkIdentifier string) ([]SwitchStackWithNetwork, error) {
+	networks, err := c.getSelectedNetworks(organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get networks for organization %s: %w", organizationID, err)
+	}
+
+	networkID := ""
+	if networkIdentifier != "" {
+		networkID, err = c.ResolveNetworkID(organizationID, networkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if networkID == "" {
+		networks = FilterNetworksByTag(networks, c.tagFilter)
+	}
+
+	allStacks := make([]SwitchStackWithNetwork, 0)
+	for _, network := range networks {
+		if networkID != "" && network.ID != networkID {
+			continue
+		}
+		// Networks without switches have no stacks, so skip the request for them
+		if networkID == "" && len(network.ProductTypes) > 0 && !hasProductType(network, "switch") {
+			continue
+		}
+
+		stacks, err := c.getNetworkSwitchStacks(network.ID)
+		if err != nil {
+			slog.Warn("Failed to get switch stacks for network", "network_id", network.ID, "network_name", network.Name, "error", err)
+			continue
+		}
+		for _, stack := range stacks {
+			allStacks = append(allStacks, SwitchStackWithNetwork{
+				SwitchStack: stack,
+				NetworkID:   network.ID,
+				NetworkName: network.Name,
+			})
+		}
+	}
+
+	slog.Info("Retrieved switch stacks", "organization_id", organizationID, "stack_count", len(allStacks))
+	return allStacks, nil
 }
 
 // getNetworkSwitchStacks gets all switch stacks in a network
@@ -707,25 +1527,437 @@ func (c *Client) getNetworkSwitchStacks(networkID string) ([]SwitchStack, error)
 	return stacks, nil
 }
 
-// getSwitchStackRoutingInterfaces gets routing interfaces for a specific switch stack
-func (c *Client) getSwitchStackRoutingInterfaces(networkID, stackID string) ([]Route, error) {
-	endpoint := fmt.Sprintf("/networks/%s/switch/stacks/%s/routing/interfaces", networkID, stackID)
+// SwitchPortStatus represents the status of one port of a switch, with its error counters over
+// the last day
+type SwitchPortStatus struct {
+	Serial         string   `json:"serial"` // Serial of the switch the port belongs to
+	SwitchName     string   `json:"switchName,omitempty"`
+	PortID         string   `json:"portId"`
+	Enabled        bool     `json:"enabled"`
+	Status         string   `json:"status"` // Connected, Disconnected or Disabled
+	IsUplink       bool     `json:"isUplink"`
+	Speed          string   `json:"speed,omitempty"`
+	Duplex         string   `json:"duplex,omitempty"`
+	Errors         []string `json:"errors,omitempty"`   // Port errors reported by the dashboard, e.g. "CRC errors"
+	Warnings       []string `json:"warnings,omitempty"` // Port warnings reported by the dashboard
+	CRCAlignErrors int      `json:"crcAlignErrors"`
+	Collisions     int      `json:"collisions"`
+	ClientCount    int      `json:"clientCount"`
+	PowerUsageInWh float64  `json:"powerUsageInWh"`      // PoE power drawn over the last day
+	VLAN           int      `json:"vlan,omitempty"`      // From the port configuration, not the status
+	VoiceVLAN      int      `json:"voiceVlan,omitempty"` // From the port configuration, not the status
+	UsageInKb      struct {
+		Total int64 `json:"total"`
+		Sent  int64 `json:"sent"`
+		Recv  int64 `json:"recv"`
+	} `json:"usageInKb"`
+	// RecentErrorsPerSecond is the recent rate of CRC align errors
+	RecentErrorsPerSecond float64 `json:"recentErrorsPerSecond"`
+}
+
+// Errored reports whether the port is not connected, has dashboard errors or has counted CRC
+// align errors or collisions
+func (p SwitchPortStatus) Errored() bool {
+	return !strings.EqualFold(p.Status, "connected") || len(p.Errors) > 0 ||
+		p.CRCAlignErrors > 0 || p.Collisions > 0 || p.RecentErrorsPerSecond > 0
+}
+
+// SwitchPortStatusWithNetwork extends the SwitchPortStatus struct to include network and organization information
+type SwitchPortStatusWithNetwork struct {
+	SwitchPortStatus
+	NetworkID      string `json:"network_id" xml:"NetworkID" csv:"network_id"`
+	NetworkName    string `json:"network_name" xml:"NetworkName" csv:"network_name"`
+	Organization   string `json:"organization" xml:"Organization" csv:"organization"`
+	OrganizationID string `json:"organization_id" xml:"OrganizationID" csv:"organization_id"`
+}
+
+// FilterErroredPorts returns the ports that are not connected or show errors
+func FilterErroredPorts(ports []SwitchPortStatusWithNetwork) []SwitchPortStatusWithNetwork {
+	errored := make([]SwitchPortStatusWithNetwork, 0, len(ports))
+	for _, port := range ports {
+		if port.Errored() {
+			errored = append(errored, port)
+		}
+	}
+	return errored
+}
+
+// GetSwitchPortStatuses lists the port statuses of every switch in one network, or in every switch
+// network in the organization
+func (c *Client) GetSwitchPortStatuses(organizationID, networkIdentifier string) ([]SwitchPortStatusWithNetwork, error) {
+	networks, err := c.getSelectedNetworks(organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get networks for organization %s: %w", organizationID, err)
+	}
+
+	networkID := ""
+	if networkIdentifier != "" {
+		networkID, err = c.ResolveNetworkID(organizationID, networkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if networkID == "" {
+		networks = FilterNetworksByTag(networks, c.tagFilter)
+	}
+
+	allPorts := make([]SwitchPortStatusWithNetwork, 0)
+	for _, network := range networks {
+		if networkID != "" && network.ID != networkID {
+			continue
+		}
+		// Networks without switches have no ports, so skip the requests for them
+		if networkID == "" && len(network.ProductTypes) > 0 && !hasProductType(network, "switch") {
+			continue
+		}
+
+		devices, err := c.getNetworkDevices(network.ID)
+		if err != nil {
+			slog.Warn("Failed to get devices for network", "network_id", network.ID, "network_name", network.Name, "error", err)
+			continue
+		}
+		for _, device := range devices {
+			if device.ProductType != "switch" && !strings.HasPrefix(strings.ToUpper(device.Model), "MS") {
+				continue
+			}
+			ports, err := c.getSwitchPortStatuses(device)
+			if err != nil {
+				slog.Warn("Failed to get port statuses for switch", "serial", device.Serial, "network_id", network.ID, "error", err)
+				continue
+			}
+			for _, port := range ports {
+				allPorts = append(allPorts, SwitchPortStatusWithNetwork{
+					SwitchPortStatus: port,
+					NetworkID:        network.ID,
+					NetworkName:      network.Name,
+				})
+			}
+		}
+	}
+
+	slog.Info("Retrieved switch port statuses", "organization_id", organizationID, "port_count", len(allPorts))
+	return allPorts, nil
+}
+
+// GetNetworkSwitchPortStatuses gets the port statuses of the switch with serial in a network. A serial
+// that is not a switch of the network returns ErrNotFound.
+func (c *Client) GetNetworkSwitchPortStatuses(networkID, serial string) ([]SwitchPortStatus, error) {
+	devices, err := c.getNetworkDevices(networkID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get devices for network %s: %w", networkID, err)
+	}
+	for _, device := range devices {
+		if !strings.EqualFold(device.Serial, serial) {
+			continue
+		}
+		if device.ProductType != "switch" && !strings.HasPrefix(strings.ToUpper(device.Model), "MS") {
+			return nil, fmt.Errorf("device %s is a %s, not a switch", device.Serial, device.Model)
+		}
+		ports, err := c.getSwitchPortStatuses(device)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get port statuses for switch %s: %w", device.Serial, err)
+		}
+		return ports, nil
+	}
+	return nil, fmt.Errorf("%w: no switch %s in network %s", ErrNotFound, serial, networkID)
+}
+
+// getSwitchPortStatuses gets the port statuses of a switch, filling in their CRC align error and
+// collision counters from the packet counters endpoint and their VLANs from the port configuration
+func (c *Client) getSwitchPortStatuses(device Device) ([]SwitchPortStatus, error) {
+	resp, err := c.makeRequest("GET", fmt.Sprintf("/devices/%s/switch/ports/statuses", device.Serial))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var ports []SwitchPortStatus
+	if err := json.NewDecoder(resp.Body).Decode(&ports); err != nil {
+		return nil, fmt.Errorf("failed to decode switch port statuses: %w", err)
+	}
+
+	counters, err := c.getSwitchPortPacketCounters(device.Serial)
+	if err != nil {
+		slog.Warn("Failed to get port packet counters for switch, reporting statuses without them", "serial", device.Serial, "error", err)
+	}
+	configs, err := c.getSwitchPortConfigs(device.Serial)
+	if err != nil {
+		slog.Warn("Failed to get port configuration for switch, reporting statuses without VLANs", "serial", device.Serial, "error", err)
+	}
+	for i := range ports {
+		ports[i].Serial = device.Serial
+		ports[i].SwitchName = device.Name
+		ports[i].VLAN = configs[ports[i].PortID].VLAN
+		ports[i].VoiceVLAN = configs[ports[i].PortID].VoiceVLAN
+		for _, counter := range counters[ports[i].PortID] {
+			switch counter.Desc {
+			case "CRC align errors":
+				ports[i].CRCAlignErrors = counter.Total
+				ports[i].RecentErrorsPerSecond = counter.RatePerSec.Total
+			case "Collisions":
+				ports[i].Collisions = counter.Total
+			}
+		}
+	}
+	return ports, nil
+}
+
+// switchPortPacketCounter is one packet counter of a switch port, such as "CRC align errors"
+type switchPortPacketCounter struct {
+	Desc       string `json:"desc"`
+	Total      int    `json:"total"`
+	RatePerSec struct {
+		Total float64 `json:"total"`
+	} `json:"ratePerSec"`
+}
+
+// getSwitchPortPacketCounters gets the packet counters of each port of a switch, keyed by port ID
+func (c *Client) getSwitchPortPacketCounters(serial string) (map[string][]switchPortPacketCounter, error) {
+	resp, err := c.makeRequest("GET", fmt.Sprintf("/devices/%s/switch/ports/statuses/packets", serial))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var response []struct {
+		PortID  string                    `json:"portId"`
+		Packets []switchPortPacketCounter `json:"packets"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode switch port packet counters: %w", err)
+	}
+
+	counters := make(map[string][]switchPortPacketCounter, len(response))
+	for _, port := range response {
+		counters[port.PortID] = port.Packets
+	}
+	return counters, nil
+}
+
+// DeviceDetails is everything the device command reports about a single device: its settings,
+// management interface, network and latest status
+type DeviceDetails struct {
+	Serial         string   `json:"serial"`
+	Name           string   `json:"name,omitempty"`
+	Model          string   `json:"model"`
+	MAC            string   `json:"mac,omitempty"`
+	ProductType    string   `json:"productType,omitempty"`
+	Firmware       string   `json:"firmware,omitempty"`
+	LanIP          string   `json:"lanIp,omitempty"`
+	WAN1IP         string   `json:"wan1Ip,omitempty"`
+	WAN2IP         string   `json:"wan2Ip,omitempty"`
+	PublicIP       string   `json:"publicIp,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+	Address        string   `json:"address,omitempty"`
+	Lat            float64  `json:"lat,omitempty"`
+	Lng            float64  `json:"lng,omitempty"`
+	Notes          string   `json:"notes,omitempty"`
+	Status         string   `json:"status,omitempty"`
+	LastReportedAt string   `json:"lastReportedAt,omitempty"`
+	NetworkID      string   `json:"networkId,omitempty"`
+	NetworkName    string   `json:"networkName,omitempty"`
+	OrganizationID string   `json:"organizationId,omitempty"`
+}
+
+// deviceManagementInterface is the WAN configuration of a device's management interface
+type deviceManagementInterface struct {
+	WAN1 managementInterfaceWAN `json:"wan1"`
+	WAN2 managementInterfaceWAN `json:"wan2"`
+}
+
+// managementInterfaceWAN is one WAN of a device's management interface
+type managementInterfaceWAN struct {
+	UsingStaticIP bool   `json:"usingStaticIp"`
+	StaticIP      string `json:"staticIp,omitempty"`
+}
+
+// GetDeviceDetails gets a single device by serial with its management interface, network name and
+// latest status. The serial identifies the network and organization, so neither has to be given.
+// A serial the API key cannot see returns ErrNotFound; no access to its organization ErrForbidden.
+func (c *Client) GetDeviceDetails(serial string) (DeviceDetails, error) {
+	resp, err := c.makeRequest("GET", fmt.Sprintf("/devices/%s", serial))
+	if err != nil {
+		return DeviceDetails{}, fmt.Errorf("failed to get device %s: %w", serial, err)
+	}
+	defer resp.Body.Close()
+
+	var details DeviceDetails
+	if err := json.NewDecoder(resp.Body).Decode(&details); err != nil {
+		return DeviceDetails{}, fmt.Errorf("failed to decode device %s: %w", serial, err)
+	}
+
+	// Static WAN addresses come from the management interface; DHCP ones from the status below
+	management, err := c.getDeviceManagementInterface(details.Serial)
+	if err != nil {
+		slog.Warn("Failed to get management interface of device, reporting it without", "serial", details.Serial, "error", err)
+	} else {
+		if management.WAN1.UsingStaticIP {
+			details.WAN1IP = management.WAN1.StaticIP
+		}
+		if management.WAN2.UsingStaticIP {
+			details.WAN2IP = management.WAN2.StaticIP
+		}
+	}
+
+	// Devices that are only in the inventory have no network, and so no status
+	if details.NetworkID == "" {
+		return details, nil
+	}
+
+	network, err := c.getNetwork(details.NetworkID)
+	if err != nil {
+		slog.Warn("Failed to get network of device, reporting its ID only", "serial", details.Serial, "networkID", details.NetworkID, "error", err)
+		return details, nil
+	}
+	details.NetworkName = network.Name
+	details.OrganizationID = network.OrganizationID
 
+	status, err := c.getDeviceStatus(network.OrganizationID, details.Serial)
+	if err != nil {
+		slog.Warn("Failed to get status of device, reporting it without", "serial", details.Serial, "error", err)
+		return details, nil
+	}
+	details.Status = status.Status
+	details.LastReportedAt = status.LastReportedAt
+	details.PublicIP = status.PublicIP
+	if details.LanIP == "" {
+		details.LanIP = status.LanIP
+	}
+	if details.WAN1IP == "" {
+		details.WAN1IP = status.WAN1IP
+	}
+	if details.WAN2IP == "" {
+		details.WAN2IP = status.WAN2IP
+	}
+	return details, nil
+}
+
+// getDeviceManagementInterface gets the management interface settings of a device
+func (c *Client) getDeviceManagementInterface(serial string) (deviceManagementInterface, error) {
+	resp, err := c.makeRequest("GET", fmt.Sprintf("/devices/%s/managementInterface", serial))
+	if err != nil {
+		return deviceManagementInterface{}, err
+	}
+	defer resp.Body.Close()
+
+	var management deviceManagementInterface
+	if err := json.NewDecoder(resp.Body).Decode(&management); err != nil {
+		return deviceManagementInterface{}, fmt.Errorf("failed to decode management interface: %w", err)
+	}
+	return management, nil
+}
+
+// networkWithOrganizationID is a network as returned by the single network endpoint, which
+// includes the ID of its organization
+type networkWithOrganizationID struct {
+	Network
+	OrganizationID string `json:"organizationId"`
+}
+
+// getNetwork gets a single network by ID
+func (c *Client) getNetwork(networkID string) (networkWithOrganizationID, error) {
+	resp, err := c.makeRequest("GET", fmt.Sprintf("/networks/%s", networkID))
+	if err != nil {
+		return networkWithOrganizationID{}, err
+	}
+	defer resp.Body.Close()
+
+	var network networkWithOrganizationID
+	if err := json.NewDecoder(resp.Body).Decode(&network); err != nil {
+		return networkWithOrganizationID{}, fmt.Errorf("failed to decode network: %w", err)
+	}
+	return network, nil
+}
+
+// deviceStatusDetails is the status of a single device with its reported addresses
+type deviceStatusDetails struct {
+	Status         string `json:"status"`
+	LastReportedAt string `json:"lastReportedAt,omitempty"`
+	LanIP          string `json:"lanIp,omitempty"`
+	PublicIP       string `json:"publicIp,omitempty"`
+	WAN1IP         string `json:"wan1Ip,omitempty"`
+	WAN2IP         string `json:"wan2Ip,omitempty"`
+}
+
+// getDeviceStatus gets the status of a single device from the organization statuses endpoint
+func (c *Client) getDeviceStatus(organizationID, serial string) (deviceStatusDetails, error) {
+	query := url.Values{"serials[]": {serial}}
+	endpoint := fmt.Sprintf("/organizations/%s/devices/statuses?%s", organizationID, query.Encode())
 	resp, err := c.makeRequest("GET", endpoint)
 	if err != nil {
-		return []Route{}, nil
+		return deviceStatusDetails{}, err
 	}
 	defer resp.Body.Close()
 
-	var interfaces []struct {
-		InterfaceID string `json:"interfaceId"`
-		Name        string `json:"name"`
-		Subnet      string `json:"subnet"`
-		InterfaceIP string `json:"interfaceIp"`
-		VLAN        int    `json:"vlan"`
+	var statuses []deviceStatusDetails
+	if err := json.NewDecoder(resp.Body).Decode(&statuses); err != nil {
+		return deviceStatusDetails{}, fmt.Errorf("failed to decode device statuses: %w", err)
 	}
+	if len(statuses) == 0 {
+		return deviceStatusDetails{}, fmt.Errorf("no status reported for device %s", serial)
+	}
+	return statuses[0], nil
+}
+
+// switchPortConfig is the VLAN configuration of a switch port
+type switchPortConfig struct {
+	PortID    string `json:"portId"`
+	VLAN      int    `json:"vlan"`
+	VoiceVLAN int    `json:"voiceVlan"`
+}
+
+// getSwitchPortConfigs gets the configuration of each port of a switch, keyed by port ID
+func (c *Client) getSwitchPortConfigs(serial string) (map[string]switchPortConfig, error) {
+	resp, err := c.makeRequest("GET", fmt.Sprintf("/devices/%s/switch/ports", serial))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var ports []switchPortConfig
+	if err := json.NewDecoder(resp.Body).Decode(&ports); err != nil {
+		return nil, fmt.Errorf("failed to decode switch ports: %w", err)
+	}
+
+	configs := make(map[string]switchPortConfig, len(ports))
+	for _, port := range ports {
+		configs[port.PortID] = port
+	}
+	return configs, nil
+}
+
+// switchRoutingInterface is a Layer 3 interface of a switch or switch stack
+type switchRoutingInterface struct {
+	InterfaceID string `json:"interfaceId"`
+	Name        string `json:"name"`
+	Subnet      string `json:"subnet"`
+	InterfaceIP string `json:"interfaceIp"`
+	VLAN        int    `json:"vlan"`
+}
 
+// getSwitchStackInterfaces gets the Layer 3 interfaces of a specific switch stack
+func (c *Client) getSwitchStackInterfaces(networkID, stackID string) ([]switchRoutingInterface, error) {
+	endpoint := fmt.Sprintf("/networks/%s/switch/stacks/%s/routing/interfaces", networkID, stackID)
+
+	resp, err := c.makeRequest("GET", endpoint)
+	if err != nil {
+		return []switchRoutingInterface{}, nil
+	}
+	defer resp.Body.Close()
+
+	var interfaces []switchRoutingInterface
 	if err := json.NewDecoder(resp.Body).Decode(&interfaces); err != nil {
+		return []switchRoutingInterface{}, nil
+	}
+
+	return interfaces, nil
+}
+
+// getSwitchStackRoutingInterfaces gets routing interfaces for a specific switch stack
+func (c *Client) getSwitchStackRoutingInterfaces(networkID, stackID string) ([]Route, error) {
+	interfaces, err := c.getSwitchStackInterfaces(networkID, stackID)
+	if err != nil {
 		return []Route{}, nil
 	}
 
@@ -734,7 +1966,7 @@ func (c *Client) getSwitchStackRoutingInterfaces(networkID, stackID string) ([]R
 		if iface.Subnet != "" {
 			routes = append(routes, Route{
 				ID:        fmt.Sprintf("stack-%s-iface-%s", stackID, iface.InterfaceID),
-				Name:      fmt.Sprintf("Stack Interface - %s", iface.Name),
+				Name:      c.routeName(iface.Name, fmt.Sprintf("Stack Interface - %s", iface.Name)),
 				Subnet:    iface.Subnet,
 				GatewayIP: iface.InterfaceIP,
 				Enabled:   true,
@@ -779,7 +2011,7 @@ func (c *Client) getSwitchStackStaticRoutes(networkID, stackID string) ([]Route,
 		}
 
 		if route.Name == "" {
-			route.Name = fmt.Sprintf("Stack %s Static Route", stackID)
+			route.Name = c.routeName(route.Name, fmt.Sprintf("Stack %s Static Route", stackID))
 		}
 
 		routes = append(routes, route)
@@ -788,100 +2020,1629 @@ func (c *Client) getSwitchStackStaticRoutes(networkID, stackID string) ([]Route,
 	return routes, nil
 }
 
-// getSwitchStackDHCPRoutes gets DHCP subnet information for a specific switch stack
-func (c *Client) getSwitchStackDHCPRoutes(networkID, stackID string) ([]Route, error) {
-	endpoint := fmt.Sprintf("/networks/%s/switch/stacks/%s/routing/dhcp", networkID, stackID)
-
-	resp, err := c.makeRequest("GET", endpoint)
-	if err != nil {
-		return []Route{}, nil
-	}
-	defer resp.Body.Close()
+// DHCP modes reported for DHCPSubnet.Mode
+const (
+	DHCPModeServer   = "server"
+	DHCPModeRelay    = "relay"
+	DHCPModeDisabled = "disabled"
+)
 
-	var dhcpConfig struct {
-		DHCPRelayServers []string `json:"dhcpRelayServerIps"`
-		DHCPOptions      []struct {
-			Code  string `json:"code"`
-			Type  string `json:"type"`
-			Value string `json:"value"`
-		} `json:"dhcpOptions"`
-		ReservedIPRanges []struct {
-			Start   string `json:"start"`
-			End     string `json:"end"`
-			Comment string `json:"comment"`
-		} `json:"reservedIpRanges"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&dhcpConfig); err != nil {
-		return []Route{}, nil
-	}
+// DHCPReservedRange is an address range the DHCP server does not hand out
+type DHCPReservedRange struct {
+	Start   string `json:"start" xml:"start"`
+	End     string `json:"end" xml:"end"`
+	Comment string `json:"comment,omitempty" xml:"comment,omitempty"`
+}
 
-	var routes []Route
+// DHCPOption is a custom DHCP option served to clients
+type DHCPOption struct {
+	Code  string `json:"code" xml:"code"`
+	Type  string `json:"type" xml:"type"`
+	Value string `json:"value" xml:"value"`
+}
 
-	// Add DHCP relay servers as routes if they exist
-	for i, relayServer := range dhcpConfig.DHCPRelayServers {
-		if relayServer != "" {
-			routes = append(routes, Route{
-				ID:        fmt.Sprintf("stack-%s-dhcp-relay-%d", stackID, i),
-				Name:      fmt.Sprintf("Stack %s DHCP Relay %d", stackID, i+1),
-				Subnet:    "0.0.0.0/0", // DHCP relay typically forwards all requests
-				GatewayIP: relayServer,
-				Enabled:   true,
-			})
-		}
-	}
+// DHCPSubnet describes DHCP handling for one appliance VLAN or switch stack routing interface
+type DHCPSubnet struct {
+	Source         string              `json:"source"`               // "appliance" or "switch-stack"
+	StackID        string              `json:"stack_id,omitempty"`   // Set for switch stack interfaces
+	StackName      string              `json:"stack_name,omitempty"` // Set for switch stack interfaces
+	InterfaceID    string              `json:"interface_id"`         // VLAN ID for appliance VLANs
+	Name           string              `json:"name"`
+	VLAN           int                 `json:"vlan,omitempty"`
+	Subnet         string              `json:"subnet"`
+	GatewayIP      string              `json:"gateway_ip"`
+	Mode           string              `json:"mode"` // server, relay or disabled
+	RelayServerIPs []string            `json:"relay_server_ips,omitempty"`
+	LeaseTime      string              `json:"lease_time,omitempty"`
+	DNSNameservers []string            `json:"dns_nameservers,omitempty"`
+	ReservedRanges []DHCPReservedRange `json:"reserved_ranges,omitempty"`
+	Options        []DHCPOption        `json:"options,omitempty"`
+}
 
-	return routes, nil
+// DHCPSubnetWithNetwork extends the DHCPSubnet struct to include network and organization information
+type DHCPSubnetWithNetwork struct {
+	DHCPSubnet
+	NetworkID      string `json:"network_id" xml:"NetworkID" csv:"network_id"`
+	NetworkName    string `json:"network_name" xml:"NetworkName" csv:"network_name"`
+	Organization   string `json:"organization" xml:"Organization" csv:"organization"`
+	OrganizationID string `json:"organization_id" xml:"OrganizationID" csv:"organization_id"`
 }
 
-// GetOrganizations fetches all organizations accessible with the API key
-func (c *Client) GetOrganizations() ([]Organization, error) {
-	resp, err := c.makeRequest("GET", "/organizations")
+// GetDHCPSubnets lists DHCP server/relay settings for the appliance VLANs and switch stack routing
+// interfaces of one network, or of every network in the organization
+func (c *Client) GetDHCPSubnets(organizationID, networkIdentifier string) ([]DHCPSubnetWithNetwork, error) {
+	networks, err := c.getSelectedNetworks(organizationID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get organizations: %w", err)
-	}
-	defer resp.Body.Close()
-
-	var organizations []Organization
-	if err := json.NewDecoder(resp.Body).Decode(&organizations); err != nil {
-		return nil, fmt.Errorf("failed to decode organizations response: %w", err)
+		return nil, fmt.Errorf("failed to get networks for organization %s: %w", organizationID, err)
 	}
 
+	networkID := ""
+	if networkIdentifier != "" {
+		networkID, err = c.ResolveNetworkID(organizationID, networkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if networkID == "" {
+		networks = FilterNetworksByTag(networks, c.tagFilter)
+	}
+
+	allSubnets := make([]DHCPSubnetWithNetwork, 0)
+	for _, network := range networks {
+		if networkID != "" && network.ID != networkID {
+			continue
+		}
+
+		var subnets []DHCPSubnet
+		// Networks without appliances or switches have no DHCP settings, so skip the requests for them
+		if networkID != "" || len(network.ProductTypes) == 0 || hasProductType(network, "appliance") {
+			vlanSubnets, err := c.getApplianceVLANDHCP(network.ID)
+			if err != nil {
+				slog.Warn("Failed to get appliance VLAN DHCP settings for network", "network_id", network.ID, "network_name", network.Name, "error", err)
+			}
+			subnets = append(subnets, vlanSubnets...)
+		}
+		if networkID != "" || len(network.ProductTypes) == 0 || hasProductType(network, "switch") {
+			stackSubnets, err := c.getSwitchStackDHCP(network.ID)
+			if err != nil {
+				slog.Warn("Failed to get switch stack DHCP settings for network", "network_id", network.ID, "network_name", network.Name, "error", err)
+			}
+			subnets = append(subnets, stackSubnets...)
+		}
+
+		for _, subnet := range subnets {
+			allSubnets = append(allSubnets, DHCPSubnetWithNetwork{
+				DHCPSubnet:  subnet,
+				NetworkID:   network.ID,
+				NetworkName: network.Name,
+			})
+		}
+	}
+
+	slog.Info("Retrieved DHCP subnets", "organization_id", organizationID, "subnet_count", len(allSubnets))
+	return allSubnets, nil
+}
+
+// getApplianceVLANDHCP gets the DHCP settings of every VLAN on a network's appliance
+func (c *Client) getApplianceVLANDHCP(networkID string) ([]DHCPSubnet, error) {
+	endpoint := fmt.Sprintf("/networks/%s/appliance/vlans", networkID)
+
+	resp, err := c.makeRequest("GET", endpoint)
+	if err != nil {
+		// VLANs might not be enabled on the appliance
+		return []DHCPSubnet{}, nil
+	}
+	defer resp.Body.Close()
+
+	var vlans []struct {
+		ID                 json.Number         `json:"id"`
+		Name               string              `json:"name"`
+		Subnet             string              `json:"subnet"`
+		ApplianceIP        string              `json:"applianceIp"`
+		DHCPHandling       string              `json:"dhcpHandling"`
+		DHCPRelayServerIPs []string            `json:"dhcpRelayServerIps"`
+		DHCPLeaseTime      string              `json:"dhcpLeaseTime"`
+		DNSNameservers     string              `json:"dnsNameservers"`
+		ReservedIPRanges   []DHCPReservedRange `json:"reservedIpRanges"`
+		DHCPOptions        []DHCPOption        `json:"dhcpOptions"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&vlans); err != nil {
+		return nil, fmt.Errorf("failed to decode appliance VLANs: %w", err)
+	}
+
+	subnets := make([]DHCPSubnet, 0, len(vlans))
+	for _, vlan := range vlans {
+		vlanID, _ := vlan.ID.Int64()
+		subnet := DHCPSubnet{
+			Source:         "appliance",
+			InterfaceID:    vlan.ID.String(),
+			Name:           vlan.Name,
+			VLAN:           int(vlanID),
+			Subnet:         vlan.Subnet,
+			GatewayIP:      vlan.ApplianceIP,
+			Mode:           normalizeDHCPMode(vlan.DHCPHandling),
+			RelayServerIPs: vlan.DHCPRelayServerIPs,
+			ReservedRanges: vlan.ReservedIPRanges,
+			Options:        vlan.DHCPOptions,
+		}
+		if subnet.Mode == DHCPModeServer {
+			subnet.LeaseTime = vlan.DHCPLeaseTime
+			subnet.DNSNameservers = strings.Fields(vlan.DNSNameservers)
+		}
+		subnets = append(subnets, subnet)
+	}
+
+	return subnets, nil
+}
+
+// getSwitchStackDHCP gets the DHCP settings of every routing interface of every switch stack in a network
+func (c *Client) getSwitchStackDHCP(networkID string) ([]DHCPSubnet, error) {
+	stacks, err := c.getNetworkSwitchStacks(networkID)
+	if err != nil {
+		return nil, err
+	}
+
+	var subnets []DHCPSubnet
+	for _, stack := range stacks {
+		interfaces, err := c.getSwitchStackInterfaces(networkID, stack.ID)
+		if err != nil {
+			slog.Debug("Failed to get routing interfaces for stack", "network_id", networkID, "stack_id", stack.ID, "error", err)
+			continue
+		}
+
+		for _, iface := range interfaces {
+			subnet, err := c.getSwitchStackInterfaceDHCP(networkID, stack.ID, iface.InterfaceID)
+			if err != nil {
+				slog.Debug("Failed to get DHCP settings for stack interface", "network_id", networkID, "stack_id", stack.ID, "interface_id", iface.InterfaceID, "error", err)
+				continue
+			}
+			subnet.Source = "switch-stack"
+			subnet.StackID = stack.ID
+			subnet.StackName = stack.Name
+			subnet.InterfaceID = iface.InterfaceID
+			subnet.Name = iface.Name
+			subnet.VLAN = iface.VLAN
+			subnet.Subnet = iface.Subnet
+			subnet.GatewayIP = iface.InterfaceIP
+			subnets = append(subnets, subnet)
+		}
+	}
+
+	return subnets, nil
+}
+
+// getSwitchStackInterfaceDHCP gets the DHCP settings of a single switch stack routing interface
+func (c *Client) getSwitchStackInterfaceDHCP(networkID, stackID, interfaceID string) (DHCPSubnet, error) {
+	endpoint := fmt.Sprintf("/networks/%s/switch/stacks/%s/routing/interfaces/%s/dhcp", networkID, stackID, interfaceID)
+
+	resp, err := c.makeRequest("GET", endpoint)
+	if err != nil {
+		return DHCPSubnet{}, err
+	}
+	defer resp.Body.Close()
+
+	var dhcp struct {
+		DHCPMode             string              `json:"dhcpMode"`
+		DHCPRelayServerIPs   []string            `json:"dhcpRelayServerIps"`
+		DHCPLeaseTime        string              `json:"dhcpLeaseTime"`
+		DNSNameserversOption string              `json:"dnsNameserversOption"`
+		DNSCustomNameservers []string            `json:"dnsCustomNameservers"`
+		ReservedIPRanges     []DHCPReservedRange `json:"reservedIpRanges"`
+		DHCPOptions          []DHCPOption        `json:"dhcpOptions"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&dhcp); err != nil {
+		return DHCPSubnet{}, fmt.Errorf("failed to decode DHCP settings: %w", err)
+	}
+
+	subnet := DHCPSubnet{
+		Mode:           normalizeDHCPMode(dhcp.DHCPMode),
+		RelayServerIPs: dhcp.DHCPRelayServerIPs,
+		ReservedRanges: dhcp.ReservedIPRanges,
+		Options:        dhcp.DHCPOptions,
+	}
+	if subnet.Mode == DHCPModeServer {
+		subnet.LeaseTime = dhcp.DHCPLeaseTime
+		if dhcp.DNSNameserversOption == "custom" {
+			subnet.DNSNameservers = dhcp.DNSCustomNameservers
+		} else if dhcp.DNSNameserversOption != "" {
+			subnet.DNSNameservers = []string{dhcp.DNSNameserversOption}
+		}
+	}
+
+	return subnet, nil
+}
+
+// normalizeDHCPMode maps the appliance dhcpHandling and switch dhcpMode values to server, relay or disabled
+func normalizeDHCPMode(mode string) string {
+	switch strings.ToLower(mode) {
+	case "run a dhcp server", "dhcpserver":
+		return DHCPModeServer
+	case "relay dhcp to another server", "dhcprelay":
+		return DHCPModeRelay
+	case "do not respond to dhcp requests", "dhcpdisabled", "":
+		return DHCPModeDisabled
+	}
+	return mode
+}
+
+// FirewallRule represents a single appliance firewall rule. Layer 3 rules fill the protocol,
+// source and destination fields; layer 7 rules match an application, category, host, port or
+// IP range given by Type and Value.
+type FirewallRule struct {
+	Layer         string `json:"layer"` // "l3" or "l7"
+	Number        int    `json:"number"`
+	Policy        string `json:"policy"`
+	Protocol      string `json:"protocol,omitempty"`
+	SrcCIDR       string `json:"src_cidr,omitempty"`
+	SrcPort       string `json:"src_port,omitempty"`
+	DestCIDR      string `json:"dest_cidr,omitempty"`
+	DestPort      string `json:"dest_port,omitempty"`
+	Type          string `json:"type,omitempty"`
+	Value         string `json:"value,omitempty"`
+	Comment       string `json:"comment,omitempty"`
+	SyslogEnabled bool   `json:"syslog_enabled,omitempty"`
+}
+
+// FirewallRuleWithNetwork extends the FirewallRule struct to include network and organization information
+type FirewallRuleWithNetwork struct {
+	FirewallRule
+	NetworkID      string `json:"network_id" xml:"NetworkID" csv:"network_id"`
+	NetworkName    string `json:"network_name" xml:"NetworkName" csv:"network_name"`
+	Organization   string `json:"organization" xml:"Organization" csv:"organization"`
+	OrganizationID string `json:"organization_id" xml:"OrganizationID" csv:"organization_id"`
+}
+
+// GetFirewallRules lists the layer 3 firewall rules, and with includeL7 the layer 7 rules, of the
+// appliance of one network, or of every appliance network in the organization
+func (c *Client) GetFirewallRules(organizationID, networkIdentifier string, includeL7 bool) ([]FirewallRuleWithNetwork, error) {
+	networks, err := c.getSelectedNetworks(organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get networks for organization %s: %w", organizationID, err)
+	}
+
+	networkID := ""
+	if networkIdentifier != "" {
+		networkID, err = c.ResolveNetworkID(organizationID, networkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if networkID == "" {
+		networks = FilterNetworksByTag(networks, c.tagFilter)
+	}
+
+	allRules := make([]FirewallRuleWithNetwork, 0)
+	for _, network := range networks {
+		if networkID != "" && network.ID != networkID {
+			continue
+		}
+		// Networks without appliances have no firewall rules, so skip the requests for them
+		if networkID == "" && len(network.ProductTypes) > 0 && !hasProductType(network, "appliance") {
+			continue
+		}
+
+		rules, err := c.GetNetworkApplianceFirewallL3Rules(network.ID)
+		if err != nil {
+			slog.Warn("Failed to get L3 firewall rules for network", "network_id", network.ID, "network_name", network.Name, "error", err)
+		}
+		if includeL7 {
+			l7Rules, err := c.getL7FirewallRules(network.ID)
+			if err != nil {
+				slog.Warn("Failed to get L7 firewall rules for network", "network_id", network.ID, "network_name", network.Name, "error", err)
+			}
+			rules = append(rules, l7Rules...)
+		}
+
+		for _, rule := range rules {
+			allRules = append(allRules, FirewallRuleWithNetwork{
+				FirewallRule: rule,
+				NetworkID:    network.ID,
+				NetworkName:  network.Name,
+			})
+		}
+	}
+
+	slog.Info("Retrieved firewall rules", "organization_id", organizationID, "rule_count", len(allRules))
+	return allRules, nil
+}
+
+// GetNetworkApplianceFirewallL3Rules gets the layer 3 firewall rules of a network's appliance,
+// including the trailing default rule, unwrapped from the API's {"rules": [...]} envelope
+func (c *Client) GetNetworkApplianceFirewallL3Rules(networkID string) ([]FirewallRule, error) {
+	endpoint := fmt.Sprintf("/networks/%s/appliance/firewall/l3FirewallRules", networkID)
+
+	resp, err := c.makeRequest("GET", endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		Rules []struct {
+			Comment       string `json:"comment"`
+			Policy        string `json:"policy"`
+			Protocol      string `json:"protocol"`
+			SrcPort       string `json:"srcPort"`
+			SrcCIDR       string `json:"srcCidr"`
+			DestPort      string `json:"destPort"`
+			DestCIDR      string `json:"destCidr"`
+			SyslogEnabled bool   `json:"syslogEnabled"`
+		} `json:"rules"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode L3 firewall rules: %w", err)
+	}
+
+	rules := make([]FirewallRule, 0, len(response.Rules))
+	for i, rule := range response.Rules {
+		rules = append(rules, FirewallRule{
+			Layer:         "l3",
+			Number:        i + 1,
+			Policy:        rule.Policy,
+			Protocol:      rule.Protocol,
+			SrcCIDR:       rule.SrcCIDR,
+			SrcPort:       rule.SrcPort,
+			DestCIDR:      rule.DestCIDR,
+			DestPort:      rule.DestPort,
+			Comment:       rule.Comment,
+			SyslogEnabled: rule.SyslogEnabled,
+		})
+	}
+
+	return rules, nil
+}
+
+// getL7FirewallRules gets the layer 7 firewall rules of a network's appliance
+func (c *Client) getL7FirewallRules(networkID string) ([]FirewallRule, error) {
+	endpoint := fmt.Sprintf("/networks/%s/appliance/firewall/l7FirewallRules", networkID)
+
+	resp, err := c.makeRequest("GET", endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		Rules []struct {
+			Policy string          `json:"policy"`
+			Type   string          `json:"type"`
+			Value  json.RawMessage `json:"value"`
+		} `json:"rules"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode L7 firewall rules: %w", err)
+	}
+
+	rules := make([]FirewallRule, 0, len(response.Rules))
+	for i, rule := range response.Rules {
+		rules = append(rules, FirewallRule{
+			Layer:  "l7",
+			Number: i + 1,
+			Policy: rule.Policy,
+			Type:   rule.Type,
+			Value:  l7RuleValue(rule.Value),
+		})
+	}
+
+	return rules, nil
+}
+
+// l7RuleValue renders the value of a layer 7 rule, which is a string for hosts, ports and IP
+// ranges and an {id, name} object for applications and application categories
+func l7RuleValue(raw json.RawMessage) string {
+	var value string
+	if err := json.Unmarshal(raw, &value); err == nil {
+		return value
+	}
+	var named struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(raw, &named); err == nil {
+		if named.Name != "" {
+			return named.Name
+		}
+		return named.ID
+	}
+	return string(raw)
+}
+
+// NetworkClient represents a client device (endpoint) seen on a network
+type NetworkClient struct {
+	ID                   string `json:"id"`
+	MAC                  string `json:"mac"`
+	Description          string `json:"description,omitempty"`
+	IP                   string `json:"ip,omitempty"`
+	IP6                  string `json:"ip6,omitempty"`
+	VLAN                 string `json:"vlan,omitempty"`
+	Status               string `json:"status,omitempty"`
+	LastSeen             string `json:"lastSeen,omitempty"`
+	Manufacturer         string `json:"manufacturer,omitempty"`
+	OS                   string `json:"os,omitempty"`
+	User                 string `json:"user,omitempty"`
+	SSID                 string `json:"ssid,omitempty"`
+	WirelessCapabilities string `json:"wirelessCapabilities,omitempty"`
+}
+
+// NetworkClientWithNetwork extends the NetworkClient struct to include network and organization information
+type NetworkClientWithNetwork struct {
+	NetworkClient
+	NetworkName    string `json:"network_name" xml:"NetworkName" csv:"network_name"`
+	NetworkID      string `json:"network_id" xml:"NetworkID" csv:"network_id"`
+	Organization   string `json:"organization" xml:"Organization" csv:"organization"`
+	OrganizationID string `json:"organization_id" xml:"OrganizationID" csv:"organization_id"`
+}
+
+// DefaultClientsTimespan is the lookback in seconds used by GetNetworkClients when none is given
+const DefaultClientsTimespan = 86400
+
+// clientsPerPage is the page size requested from the network clients endpoint
+var clientsPerPage = 1000
+
+// GetNetworkClients fetches the clients seen on a network within the last timespan seconds,
+// DefaultClientsTimespan when timespan is 0, following the rel=next Link header across pages
+func (c *Client) GetNetworkClients(networkID string, timespan int) ([]NetworkClient, error) {
+	if timespan == 0 {
+		timespan = DefaultClientsTimespan
+	}
+
+	clients := make([]NetworkClient, 0)
+	startingAfter := ""
+	for page := 1; ; page++ {
+		params := url.Values{}
+		params.Set("timespan", fmt.Sprintf("%d", timespan))
+		params.Set("perPage", fmt.Sprintf("%d", clientsPerPage))
+		if startingAfter != "" {
+			params.Set("startingAfter", startingAfter)
+		}
+
+		resp, err := c.makeRequest("GET", fmt.Sprintf("/networks/%s/clients?%s", networkID, params.Encode()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get network clients: %w", err)
+		}
+
+		// VLAN is a number on some networks and a string on others, and lastSeen an epoch or a timestamp
+		var apiClients []struct {
+			NetworkClient
+			VLAN     json.RawMessage `json:"vlan"`
+			LastSeen json.RawMessage `json:"lastSeen"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&apiClients)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode network clients response: %w", err)
+		}
+		for _, apiClient := range apiClients {
+			client := apiClient.NetworkClient
+			client.VLAN = jsonScalarString(apiClient.VLAN)
+			client.LastSeen = jsonScalarString(apiClient.LastSeen)
+			clients = append(clients, client)
+		}
+		slog.Debug("Retrieved network clients page", "network_id", networkID, "page", page, "count", len(apiClients))
+
+		next := nextPageStartingAfter(resp.Header.Get("Link"))
+		if len(apiClients) == 0 || next == "" || next == startingAfter {
+			break
+		}
+		startingAfter = next
+	}
+
+	return clients, nil
+}
+
+// jsonScalarString renders a JSON string or number as a string; null and missing values are empty
+func jsonScalarString(raw json.RawMessage) string {
+	var value string
+	if err := json.Unmarshal(raw, &value); err == nil {
+		return value
+	}
+	var number json.Number
+	if err := json.Unmarshal(raw, &number); err == nil {
+		return number.String()
+	}
+	return ""
+}
+
+// SSID represents the configuration of one of a network's wireless SSIDs
+type SSID struct {
+	Number            int    `json:"number"`
+	Name              string `json:"name"`
+	Enabled           bool   `json:"enabled"`
+	AuthMode          string `json:"authMode,omitempty"`
+	EncryptionMode    string `json:"encryptionMode,omitempty"`
+	WpaEncryptionMode string `json:"wpaEncryptionMode,omitempty"`
+	IPAssignmentMode  string `json:"ipAssignmentMode,omitempty"`
+	DefaultVlanID     int    `json:"defaultVlanId,omitempty"`
+	Visible           bool   `json:"visible"`
+	SplashPage        string `json:"splashPage,omitempty"`
+	BandSelection     string `json:"bandSelection,omitempty"`
+}
+
+// SSIDWithNetwork extends the SSID struct to include network and organization information
+type SSIDWithNetwork struct {
+	SSID
+	NetworkName    string `json:"network_name" xml:"NetworkName" csv:"network_name"`
+	NetworkID      string `json:"network_id" xml:"NetworkID" csv:"network_id"`
+	Organization   string `json:"organization" xml:"Organization" csv:"organization"`
+	OrganizationID string `json:"organization_id" xml:"OrganizationID" csv:"organization_id"`
+}
+
+// GetNetworkWirelessSSIDs fetches the SSID configurations of a network. Networks without
+// wireless, for which the API answers 400, have no SSIDs.
+func (c *Client) GetNetworkWirelessSSIDs(networkID string) ([]SSID, error) {
+	resp, err := c.makeRequest("GET", fmt.Sprintf("/networks/%s/wireless/ssids", networkID))
+	if errors.Is(err, ErrBadRequest) {
+		slog.Debug("Network has no wireless SSIDs", "network_id", networkID)
+		return []SSID{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wireless SSIDs: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var ssids []SSID
+	if err := json.NewDecoder(resp.Body).Decode(&ssids); err != nil {
+		return nil, fmt.Errorf("failed to decode wireless SSIDs response: %w", err)
+	}
+
+	return ssids, nil
+}
+
+// Event represents a network event log entry
+type Event struct {
+	OccurredAt        string `json:"occurredAt"`
+	Type              string `json:"type"`
+	Description       string `json:"description"`
+	Category          string `json:"category,omitempty"`
+	DeviceSerial      string `json:"deviceSerial,omitempty"`
+	DeviceName        string `json:"deviceName,omitempty"`
+	ClientID          string `json:"clientId,omitempty"`
+	ClientDescription string `json:"clientDescription,omitempty"`
+	ClientMac         string `json:"clientMac,omitempty"`
+}
+
+// EventWithNetwork extends the Event struct to include network and organization information
+type EventWithNetwork struct {
+	Event
+	NetworkID      string `json:"network_id" xml:"NetworkID" csv:"network_id"`
+	NetworkName    string `json:"network_name" xml:"NetworkName" csv:"network_name"`
+	Organization   string `json:"organization" xml:"Organization" csv:"organization"`
+	OrganizationID string `json:"organization_id" xml:"OrganizationID" csv:"organization_id"`
+}
+
+// EventQuery narrows the events fetched for a network. Zero values mean no restriction.
+type EventQuery struct {
+	ProductType string    // Product type whose events to fetch; required by the API for networks with several
+	EventTypes  []string  // Only include these event types
+	Since       time.Time // Only include events that occurred at or after this time
+	Until       time.Time // Only include events that occurred before this time
+	Limit       int       // Stop paging once this many events have been collected
+}
+
+// eventsPage is one page of the network events endpoint
+type eventsPage struct {
+	PageStartAt string  `json:"pageStartAt"`
+	PageEndAt   string  `json:"pageEndAt"`
+	Events      []Event `json:"events"`
+}
+
+// eventsPerPage is the page size requested from the network events endpoint
+var eventsPerPage = 1000
+
+// OccurredTime parses OccurredAt, reporting false when it is missing or malformed
+func (e Event) OccurredTime() (time.Time, bool) {
+	if e.OccurredAt == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, e.OccurredAt)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// GetNetworkEvents fetches the events of a single network, oldest first. The events API is
+// per-network and paged, so there is deliberately no all-networks variant.
+func (c *Client) GetNetworkEvents(organizationID, networkIdentifier string, query EventQuery) ([]EventWithNetwork, error) {
+	if networkIdentifier == "" {
+		return nil, fmt.Errorf("a network is required to fetch events")
+	}
+
+	networkID, err := c.ResolveNetworkID(organizationID, networkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	networkName := ""
+	networks, err := c.getOrganizationNetworks(organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get networks for organization %s: %w", organizationID, err)
+	}
+	for _, network := range networks {
+		if network.ID == networkID {
+			networkName = network.Name
+			break
+		}
+	}
+
+	events, err := c.getNetworkEvents(networkID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	networkEvents := make([]EventWithNetwork, 0, len(events))
+	for _, event := range events {
+		networkEvents = append(networkEvents, EventWithNetwork{
+			Event:       event,
+			NetworkID:   networkID,
+			NetworkName: networkName,
+		})
+	}
+
+	slog.Info("Retrieved network events", "network_id", networkID, "event_count", len(networkEvents))
+	return networkEvents, nil
+}
+
+// getNetworkEvents pages backwards through a network's events from query.Until until the
+// window back to query.Since is covered, the API runs out of events, or query.Limit is hit.
+// With a limit, the most recent events are kept. The result is in chronological order.
+func (c *Client) getNetworkEvents(networkID string, query EventQuery) ([]Event, error) {
+	// Collected newest first while paging backwards, then reversed
+	events := make([]Event, 0)
+	endingBefore := query.Until
+
+	for page := 1; ; page++ {
+		params := url.Values{}
+		params.Set("perPage", fmt.Sprintf("%d", eventsPerPage))
+		if query.ProductType != "" {
+			params.Set("productType", query.ProductType)
+		}
+		for _, eventType := range query.EventTypes {
+			params.Add("includedEventTypes[]", eventType)
+		}
+		if !endingBefore.IsZero() {
+			params.Set("endingBefore", endingBefore.UTC().Format(time.RFC3339))
+		}
+
+		resp, err := c.makeRequest("GET", fmt.Sprintf("/networks/%s/events?%s", networkID, params.Encode()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get events for network %s: %w", networkID, err)
+		}
+
+		var result eventsPage
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode events response: %w", err)
+		}
+		slog.Debug("Retrieved events page", "network_id", networkID, "page", page, "count", len(result.Events), "page_start_at", result.PageStartAt)
+
+		done := len(result.Events) == 0
+		for i := len(result.Events) - 1; i >= 0; i-- {
+			event := result.Events[i]
+			if occurred, ok := event.OccurredTime(); ok {
+				if !query.Since.IsZero() && occurred.Before(query.Since) {
+					// Older than the window, so there is nothing further back worth fetching
+					done = true
+					continue
+				}
+				if !query.Until.IsZero() && !occurred.Before(query.Until) {
+					continue
+				}
+			}
+			events = append(events, event)
+			if query.Limit > 0 && len(events) >= query.Limit {
+				done = true
+				break
+			}
+		}
+		if done {
+			break
+		}
+
+		// Continue from the start of this page; stop if the API gives no earlier position
+		pageStart, err := time.Parse(time.RFC3339, result.PageStartAt)
+		if err != nil || (!endingBefore.IsZero() && !pageStart.Before(endingBefore)) {
+			break
+		}
+		if !query.Since.IsZero() && !pageStart.After(query.Since) {
+			break
+		}
+		endingBefore = pageStart
+	}
+
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+	return events, nil
+}
+
+// EventLogParams selects the entries fetched from an organization's event log. Zero values mean no
+// restriction; PerPage falls back to the API's default page size.
+type EventLogParams struct {
+	ProductType        string    // Product type whose events to fetch, such as "appliance" or "wireless"
+	IncludedEventTypes []string  // Only include these event types
+	ExcludedEventTypes []string  // Leave out these event types
+	StartingAfter      time.Time // Only include events that occurred after this time
+	EndingBefore       time.Time // Only include events that occurred before this time
+	PerPage            int       // Entries requested per page
+	MaxResults         int       // Stop paging once this many entries have been collected
+}
+
+// EventLogEntry is one entry of an organization's event log
+type EventLogEntry struct {
+	OccurredAt   string `json:"occurredAt"`
+	NetworkID    string `json:"networkId"`
+	Type         string `json:"type"`
+	Description  string `json:"description"`
+	Category     string `json:"category"`
+	ClientID     string `json:"clientId,omitempty"`
+	ClientMAC    string `json:"clientMac,omitempty"`
+	DeviceSerial string `json:"deviceSerial,omitempty"`
+	DeviceName   string `json:"deviceName,omitempty"`
+	SSIDNumber   int    `json:"ssidNumber,omitempty"`
+}
+
+// GetOrganizationEventLog fetches an organization's event log, following the Link header of each
+// response until params.MaxResults entries have been collected or there is no next page
+func (c *Client) GetOrganizationEventLog(organizationID string, params EventLogParams) ([]EventLogEntry, error) {
+	query := url.Values{}
+	if params.PerPage > 0 {
+		query.Set("perPage", fmt.Sprintf("%d", params.PerPage))
+	}
+	if params.ProductType != "" {
+		query.Set("productType", params.ProductType)
+	}
+	for _, eventType := range params.IncludedEventTypes {
+		query.Add("includedEventTypes[]", eventType)
+	}
+	for _, eventType := range params.ExcludedEventTypes {
+		query.Add("excludedEventTypes[]", eventType)
+	}
+	if !params.StartingAfter.IsZero() {
+		query.Set("startingAfter", params.StartingAfter.UTC().Format(time.RFC3339))
+	}
+	if !params.EndingBefore.IsZero() {
+		query.Set("endingBefore", params.EndingBefore.UTC().Format(time.RFC3339))
+	}
+
+	endpoint := fmt.Sprintf("/organizations/%s/events", organizationID)
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	items, err := c.paginateGETLimit(endpoint, params.MaxResults)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event log for organization %s: %w", organizationID, err)
+	}
+
+	entries := make([]EventLogEntry, 0, len(items))
+	for _, item := range items {
+		var entry EventLogEntry
+		if err := json.Unmarshal(item, &entry); err != nil {
+			return nil, fmt.Errorf("failed to decode event log entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	slog.Debug("Retrieved organization event log", "org_id", organizationID, "count", len(entries))
+	return entries, nil
+}
+
+// ConfigurationChange is one entry of an organization's configuration change log: a setting an
+// administrator changed in the dashboard or through the API
+type ConfigurationChange struct {
+	TS          string `json:"ts"`
+	AdminName   string `json:"adminName"`
+	AdminEmail  string `json:"adminEmail"`
+	AdminID     string `json:"adminId,omitempty"`
+	NetworkID   string `json:"networkId,omitempty"`
+	NetworkName string `json:"networkName,omitempty"` // Resolved from NetworkID when the API omits it
+	SSIDName    string `json:"ssidName,omitempty"`
+	Page        string `json:"page"`
+	Label       string `json:"label"`
+	OldValue    string `json:"oldValue"`
+	NewValue    string `json:"newValue"`
+}
+
+// ConfigurationChangeWithOrganization extends the ConfigurationChange struct to include organization information
+type ConfigurationChangeWithOrganization struct {
+	ConfigurationChange
+	Organization   string `json:"organization" xml:"Organization" csv:"organization"`
+	OrganizationID string `json:"organization_id" xml:"OrganizationID" csv:"organization_id"`
+}
+
+// ConfigurationChangeQuery selects configuration changes
+type ConfigurationChangeQuery struct {
+	Network string    // Network ID or name whose changes to fetch; the whole organization when empty
+	Admin   string    // Only include changes by the administrator with this name, email or ID (case-insensitive)
+	Since   time.Time // Only include changes made at or after this time
+	Until   time.Time // Only include changes made before this time
+}
+
+// configurationChangesPerPage is the page size requested from the configuration changes endpoint
+var configurationChangesPerPage = 5000
+
+// GetConfigurationChanges pages through the configuration change log of an organization, or of one
+// of its networks, within the query's window. Network names missing from the log are resolved from
+// the organization's cached network list.
+func (c *Client) GetConfigurationChanges(organizationID string, query ConfigurationChangeQuery) ([]ConfigurationChange, error) {
+	params := url.Values{}
+	params.Set("perPage", fmt.Sprintf("%d", configurationChangesPerPage))
+	if query.Network != "" {
+		networkID, err := c.ResolveNetworkID(organizationID, query.Network)
+		if err != nil {
+			return nil, err
+		}
+		params.Set("networkId", networkID)
+	}
+	if !query.Since.IsZero() {
+		params.Set("t0", query.Since.UTC().Format(time.RFC3339))
+	}
+	if !query.Until.IsZero() {
+		params.Set("t1", query.Until.UTC().Format(time.RFC3339))
+	}
+
+	items, err := c.paginateGET(fmt.Sprintf("/organizations/%s/configurationChanges?%s", organizationID, params.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get configuration changes for organization %s: %w", organizationID, err)
+	}
+
+	changes := make([]ConfigurationChange, 0, len(items))
+	for _, item := range items {
+		var change ConfigurationChange
+		if err := json.Unmarshal(item, &change); err != nil {
+			return nil, fmt.Errorf("failed to decode configuration change: %w", err)
+		}
+		if query.Admin != "" && !matchesAdmin(change, query.Admin) {
+			continue
+		}
+		changes = append(changes, change)
+	}
+
+	c.resolveChangeNetworkNames(organizationID, changes)
+	slog.Info("Retrieved configuration changes", "organization_id", organizationID, "change_count", len(changes))
+	return changes, nil
+}
+
+// matchesAdmin reports whether change was made by the administrator with this name, email or ID
+func matchesAdmin(change ConfigurationChange, admin string) bool {
+	return strings.EqualFold(change.AdminName, admin) || strings.EqualFold(change.AdminEmail, admin) || change.AdminID == admin
+}
+
+// resolveChangeNetworkNames fills in the names of networks the change log reports by ID only.
+// Changes keep their ID alone when the networks cannot be listed or the network was deleted.
+func (c *Client) resolveChangeNetworkNames(organizationID string, changes []ConfigurationChange) {
+	var names map[string]string
+	for i := range changes {
+		if changes[i].NetworkID == "" || changes[i].NetworkName != "" {
+			continue
+		}
+		if names == nil {
+			names = make(map[string]string)
+			networks, err := c.getOrganizationNetworks(organizationID)
+			if err != nil {
+				slog.Warn("Failed to get networks to resolve configuration change network names", "organization_id", organizationID, "error", err)
+			}
+			for _, network := range networks {
+				names[network.ID] = network.Name
+			}
+		}
+		changes[i].NetworkName = names[changes[i].NetworkID]
+	}
+}
+
+// APIRequest is one entry of an organization's API request log
+type APIRequest struct {
+	AdminID      string `json:"adminId"`
+	Method       string `json:"method"`
+	Host         string `json:"host,omitempty"`
+	Path         string `json:"path"`
+	QueryString  string `json:"queryString,omitempty"`
+	UserAgent    string `json:"userAgent,omitempty"`
+	Ts           string `json:"ts"`
+	ResponseCode int    `json:"responseCode"`
+	SourceIP     string `json:"sourceIp,omitempty"`
+	Version      int    `json:"version,omitempty"`
+	OperationID  string `json:"operationId,omitempty"`
+}
+
+// UsageCount is the number of API requests with one response code, admin or user agent
+type UsageCount struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
+// APIUsage summarizes the API requests made to an organization over a timespan. TopAdmins,
+// TopUserAgents and Requests come from the paged request log and are only set for detailed reports.
+type APIUsage struct {
+	Organization    string       `json:"organization"`
+	OrganizationID  string       `json:"organization_id"`
+	TimespanSeconds int          `json:"timespan_seconds"`
+	Since           string       `json:"since"`
+	Until           string       `json:"until"`
+	TotalRequests   int          `json:"total_requests"`
+	RateLimited     int          `json:"rate_limited"` // Requests answered with 429 Too Many Requests
+	ResponseCodes   []UsageCount `json:"response_codes"`
+	TopAdmins       []UsageCount `json:"top_admins,omitempty"`
+	TopUserAgents   []UsageCount `json:"top_user_agents,omitempty"`
+	Requests        []APIRequest `json:"requests,omitempty"`
+}
+
+// MaxAPIUsageTimespan is the longest timespan the API request endpoints accept
+const MaxAPIUsageTimespan = 31 * 24 * time.Hour
+
+// apiUsageTopCount is the number of admins and user agents listed in a detailed report
+const apiUsageTopCount = 10
+
+// apiRequestsPerPage is the page size requested from the API request log endpoint
+var apiRequestsPerPage = 1000
+
+// GetAPIUsage reports the API requests made to an organization over the timespan ending now,
+// counted by response code. With detailed, the request log is paged through as well, adding
+// every request and the admins and user agents making the most of them.
+func (c *Client) GetAPIUsage(organizationID string, timespan time.Duration, detailed bool) (APIUsage, error) {
+	until := time.Now().UTC().Truncate(time.Second)
+	usage := APIUsage{
+		OrganizationID:  organizationID,
+		TimespanSeconds: int(timespan.Seconds()),
+		Since:           until.Add(-timespan).Format(time.RFC3339),
+		Until:           until.Format(time.RFC3339),
+		ResponseCodes:   make([]UsageCount, 0),
+	}
+
+	endpoint := fmt.Sprintf("/organizations/%s/apiRequests/overview?timespan=%d", organizationID, usage.TimespanSeconds)
+	resp, err := c.makeRequest("GET", endpoint)
+	if err != nil {
+		return APIUsage{}, fmt.Errorf("failed to get API request overview: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var overview struct {
+		ResponseCodeCounts map[string]int `json:"responseCodeCounts"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&overview); err != nil {
+		return APIUsage{}, fmt.Errorf("failed to decode API request overview response: %w", err)
+	}
+
+	for code, count := range overview.ResponseCodeCounts {
+		if count == 0 {
+			continue
+		}
+		usage.ResponseCodes = append(usage.ResponseCodes, UsageCount{Key: code, Count: count})
+		usage.TotalRequests += count
+		if code == "429" {
+			usage.RateLimited = count
+		}
+	}
+	sort.Slice(usage.ResponseCodes, func(i, j int) bool {
+		return usage.ResponseCodes[i].Key < usage.ResponseCodes[j].Key
+	})
+
+	if !detailed {
+		return usage, nil
+	}
+
+	requests, err := c.getAPIRequests(organizationID, usage.TimespanSeconds)
+	if err != nil {
+		return APIUsage{}, err
+	}
+	usage.Requests = requests
+	usage.TopAdmins = topUsageCounts(requests, func(r APIRequest) string { return r.AdminID })
+	usage.TopUserAgents = topUsageCounts(requests, func(r APIRequest) string { return r.UserAgent })
+
+	return usage, nil
+}
+
+// getAPIRequests pages through an organization's API request log for the last timespanSeconds
+func (c *Client) getAPIRequests(organizationID string, timespanSeconds int) ([]APIRequest, error) {
+	params := url.Values{}
+	params.Set("perPage", fmt.Sprintf("%d", apiRequestsPerPage))
+	params.Set("timespan", fmt.Sprintf("%d", timespanSeconds))
+
+	items, err := c.paginateGET(fmt.Sprintf("/organizations/%s/apiRequests?%s", organizationID, params.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API requests: %w", err)
+	}
+
+	requests := make([]APIRequest, 0, len(items))
+	for _, item := range items {
+		var request APIRequest
+		if err := json.Unmarshal(item, &request); err != nil {
+			return nil, fmt.Errorf("failed to decode API requests response: %w", err)
+		}
+		requests = append(requests, request)
+	}
+	return requests, nil
+}
+
+// InventoryDevice represents a device claimed into an organization's inventory, whether or not
+// it has been added to a network
+type InventoryDevice struct {
+	Serial                string   `json:"serial"`
+	MAC                   string   `json:"mac,omitempty"`
+	Model                 string   `json:"model"`
+	NetworkID             string   `json:"networkId"` // Empty for devices not yet added to a network
+	OrderNumber           string   `json:"orderNumber,omitempty"`
+	ClaimedAt             string   `json:"claimedAt,omitempty"`
+	LicenseExpirationDate string   `json:"licenseExpirationDate,omitempty"`
+	Tags                  []string `json:"tags,omitempty"`
+	ProductType           string   `json:"productType,omitempty"`
+}
+
+// Unassigned reports whether the device is claimed but not yet added to a network
+func (d InventoryDevice) Unassigned() bool {
+	return d.NetworkID == ""
+}
+
+// inventoryPerPage is the page size requested from the organization inventory endpoint
+var inventoryPerPage = 1000
+
+// GetOrganizationInventoryDevices fetches every device claimed into an organization, including
+// hardware that is not yet assigned to a network
+func (c *Client) GetOrganizationInventoryDevices(organizationID string) ([]InventoryDevice, error) {
+	items, err := c.paginateGET(fmt.Sprintf("/organizations/%s/inventory/devices?perPage=%d", organizationID, inventoryPerPage))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inventory devices: %w", err)
+	}
+
+	devices := make([]InventoryDevice, 0, len(items))
+	for _, item := range items {
+		var device InventoryDevice
+		if err := json.Unmarshal(item, &device); err != nil {
+			return nil, fmt.Errorf("failed to decode inventory device: %w", err)
+		}
+		devices = append(devices, device)
+	}
+
+	slog.Debug("Retrieved inventory devices", "org_id", organizationID, "count", len(devices))
+	return devices, nil
+}
+
+// paginateGET fetches every page of a list endpoint, following the startingAfter position of each
+// response's rel=next Link header, and returns the records of all pages in order. Query parameters
+// of endpoint, such as perPage, are sent with every page.
+func (c *Client) paginateGET(endpoint string) ([]json.RawMessage, error) {
+	return c.paginateGETLimit(endpoint, 0)
+}
+
+// paginateGETLimit is paginateGET that stops requesting pages once limit records have been
+// collected and truncates the result to limit. A limit of zero or less fetches every page.
+func (c *Client) paginateGETLimit(endpoint string, limit int) ([]json.RawMessage, error) {
+	path, query, _ := strings.Cut(endpoint, "?")
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query in endpoint %s: %w", endpoint, err)
+	}
+
+	items := make([]json.RawMessage, 0)
+	startingAfter := ""
+	for page := 1; ; page++ {
+		if startingAfter != "" {
+			params.Set("startingAfter", startingAfter)
+		}
+		pageEndpoint := path
+		if len(params) > 0 {
+			pageEndpoint += "?" + params.Encode()
+		}
+
+		resp, err := c.makeRequest("GET", pageEndpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		var result []json.RawMessage
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode page %d of %s: %w", page, path, err)
+		}
+		items = append(items, result...)
+		slog.Debug("Retrieved page", "endpoint", path, "page", page, "count", len(result))
+		if limit > 0 && len(items) >= limit {
+			items = items[:limit]
+			break
+		}
+
+		next := nextPageStartingAfter(resp.Header.Get("Link"))
+		if len(result) == 0 || next == "" || next == startingAfter {
+			break
+		}
+		startingAfter = next
+	}
+
+	return items, nil
+}
+
+// nextPageStartingAfter returns the startingAfter parameter of the rel=next URL in a Link header,
+// or "" when there is no next page
+func nextPageStartingAfter(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		target, params, found := strings.Cut(strings.TrimSpace(part), ";")
+		if !found || !strings.Contains(strings.ReplaceAll(params, `"`, ""), "rel=next") {
+			continue
+		}
+		next, err := url.Parse(strings.Trim(strings.TrimSpace(target), "<>"))
+		if err != nil {
+			return ""
+		}
+		return next.Query().Get("startingAfter")
+	}
+	return ""
+}
+
+// topUsageCounts counts the requests by key and returns the apiUsageTopCount largest counts,
+// ties broken by key. Requests with an empty key are not counted.
+func topUsageCounts(requests []APIRequest, key func(APIRequest) string) []UsageCount {
+	counts := make(map[string]int)
+	for _, request := range requests {
+		if k := key(request); k != "" {
+			counts[k]++
+		}
+	}
+
+	top := make([]UsageCount, 0, len(counts))
+	for k, count := range counts {
+		top = append(top, UsageCount{Key: k, Count: count})
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Count != top[j].Count {
+			return top[i].Count > top[j].Count
+		}
+		return top[i].Key < top[j].Key
+	})
+	if len(top) > apiUsageTopCount {
+		top = top[:apiUsageTopCount]
+	}
+	return top
+}
+
+// GetOrganizations fetches all organizations accessible with the API key
+func (c *Client) GetOrganizations() ([]Organization, error) {
+	c.cacheMu.Lock()
+	cached := c.organizations
+	c.cacheMu.Unlock()
+	if cached != nil {
+		return slices.Clone(cached), nil
+	}
+
+	resp, err := c.makeRequest("GET", "/organizations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organizations: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var organizations []Organization
+	if err := json.NewDecoder(resp.Body).Decode(&organizations); err != nil {
+		return nil, fmt.Errorf("failed to decode organizations response: %w", err)
+	}
+	if organizations == nil {
+		organizations = []Organization{}
+	}
+
+	c.cacheMu.Lock()
+	c.organizations = slices.Clone(organizations)
+	c.cacheMu.Unlock()
+
 	return organizations, nil
 }
 
+// InvalidateCache drops the cached organizations and networks so the next lookups fetch them again
+func (c *Client) InvalidateCache() {
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+	c.organizations = nil
+	c.networks = nil
+}
+
 // GetOrganizationNetworks fetches all networks in an organization (public method)
 func (c *Client) GetOrganizationNetworks(organizationID string) ([]Network, error) {
 	return c.getOrganizationNetworks(organizationID)
 }
 
-// getOrganizationDeviceStatuses fetches device statuses for all devices in an organization
-func (c *Client) getOrganizationDeviceStatuses(organizationID string) ([]DeviceStatus, error) {
-	endpoint := fmt.Sprintf("/organizations/%s/devices/statuses", organizationID)
+// getOrganizationDeviceStatuses fetches device statuses for all devices in an organization
+func (c *Client) getOrganizationDeviceStatuses(organizationID string) ([]DeviceStatus, error) {
+	endpoint := fmt.Sprintf("/organizations/%s/devices/statuses", organizationID)
+
+	resp, err := c.makeRequest("GET", endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var deviceStatuses []DeviceStatus
+	if err := json.NewDecoder(resp.Body).Decode(&deviceStatuses); err != nil {
+		return nil, fmt.Errorf("failed to decode device statuses: %w", err)
+	}
+
+	slog.Debug("Retrieved device statuses from organization", "organization_id", organizationID, "status_count", len(deviceStatuses))
+	return deviceStatuses, nil
+}
+
+// ApplianceUplinkStatus is the WAN uplink state of an MX/Z appliance, or of each appliance of a warm spare pair
+type ApplianceUplinkStatus struct {
+	NetworkID        string `json:"networkId"`
+	Serial           string `json:"serial"`
+	Model            string `json:"model"`
+	LastReportedAt   string `json:"lastReportedAt,omitempty"`
+	HighAvailability struct {
+		Enabled bool   `json:"enabled"`
+		Role    string `json:"role,omitempty"` // primary or spare
+	} `json:"highAvailability"`
+	Uplinks []UplinkDetail `json:"uplinks"`
+}
+
+// UplinkDetail is the state of one WAN or cellular uplink of an appliance
+type UplinkDetail struct {
+	Interface  string `json:"interface"` // wan1, wan2 or cellular
+	Status     string `json:"status"`    // active, ready, connecting, not connected or failed
+	IP         string `json:"ip,omitempty"`
+	Gateway    string `json:"gateway,omitempty"`
+	PublicIP   string `json:"publicIp,omitempty"`
+	SignalStat struct {
+		Rsrp string `json:"rsrp,omitempty"`
+		Rsrq string `json:"rsrq,omitempty"`
+	} `json:"signalStat"` // Cellular uplinks only
+}
+
+// ApplianceUplinkStatusWithOrganization extends the ApplianceUplinkStatus struct to include organization information
+type ApplianceUplinkStatusWithOrganization struct {
+	ApplianceUplinkStatus
+	Organization   string `json:"organization" xml:"Organization" csv:"organization"`
+	OrganizationID string `json:"organization_id" xml:"OrganizationID" csv:"organization_id"`
+}
+
+// uplinkStatusesPerPage is the page size requested from the appliance uplink statuses endpoint
+var uplinkStatusesPerPage = 1000
+
+// GetOrganizationApplianceUplinkStatuses pages through the uplink statuses of every appliance in an organization
+func (c *Client) GetOrganizationApplianceUplinkStatuses(organizationID string) ([]ApplianceUplinkStatus, error) {
+	items, err := c.paginateGET(fmt.Sprintf("/organizations/%s/appliance/uplink/statuses?perPage=%d", organizationID, uplinkStatusesPerPage))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get appliance uplink statuses for organization %s: %w", organizationID, err)
+	}
+
+	statuses := make([]ApplianceUplinkStatus, 0, len(items))
+	for _, item := range items {
+		var status ApplianceUplinkStatus
+		if err := json.Unmarshal(item, &status); err != nil {
+			return nil, fmt.Errorf("failed to decode appliance uplink status: %w", err)
+		}
+		statuses = append(statuses, status)
+	}
+
+	slog.Debug("Retrieved appliance uplink statuses from organization", "organization_id", organizationID, "appliance_count", len(statuses))
+	return statuses, nil
+}
+
+// AppliancePerformance is the performance score of an MX appliance joined with the loss and
+// latency of its uplinks. LossPercent and LatencyMs are those of its worst uplink.
+type AppliancePerformance struct {
+	Serial         string              `json:"serial"`
+	Name           string              `json:"name,omitempty"`
+	Model          string              `json:"model"`
+	PerfScore      float64             `json:"perfScore"` // Utilization score from 0 to 100; higher is busier
+	LossPercent    float64             `json:"lossPercent"`
+	LatencyMs      float64             `json:"latencyMs"`
+	Uplinks        []UplinkPerformance `json:"uplinks"`
+	NetworkID      string              `json:"network_id" xml:"NetworkID" csv:"network_id"`
+	NetworkName    string              `json:"network_name" xml:"NetworkName" csv:"network_name"`
+	Organization   string              `json:"organization" xml:"Organization" csv:"organization"`
+	OrganizationID string              `json:"organization_id" xml:"OrganizationID" csv:"organization_id"`
+}
+
+// UplinkPerformance is the average loss and latency of one appliance uplink to its probe IP
+type UplinkPerformance struct {
+	Interface   string  `json:"interface" xml:"interface"`
+	IP          string  `json:"ip,omitempty" xml:"ip,omitempty"` // Probe destination
+	LossPercent float64 `json:"lossPercent" xml:"lossPercent"`
+	LatencyMs   float64 `json:"latencyMs" xml:"latencyMs"`
+}
+
+// MaxAppliancePerformanceTimespan is the longest timespan the appliance performance endpoint accepts
+const MaxAppliancePerformanceTimespan = 14 * 24 * time.Hour
+
+// GetAppliancePerformance reports the performance score over timespan of each appliance in one
+// network, or in every network of an organization when networkIdentifier is empty. The loss and
+// latency of the uplinks cover the last five minutes, the window the API measures them over.
+// Appliances whose score cannot be fetched, such as offline ones, are kept with a score of 0.
+func (c *Client) GetAppliancePerformance(organizationID, networkIdentifier string, timespan time.Duration) ([]AppliancePerformance, error) {
+	networks, err := c.getOrganizationNetworks(organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get networks for organization %s: %w", organizationID, err)
+	}
+	networkNames := make(map[string]string)
+	for _, network := range networks {
+		networkNames[network.ID] = network.Name
+	}
+	networkID := ""
+	if networkIdentifier != "" {
+		if networkID, err = c.ResolveNetworkID(organizationID, networkIdentifier); err != nil {
+			return nil, err
+		}
+	}
+
+	statuses, err := c.getOrganizationDeviceStatuses(organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get device statuses for organization %s: %w", organizationID, err)
+	}
+	uplinks, err := c.getUplinksLossAndLatency(organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get uplink loss and latency for organization %s: %w", organizationID, err)
+	}
+
+	appliances := make([]AppliancePerformance, 0)
+	for _, status := range statuses {
+		if status.ProductType != "appliance" || (networkID != "" && status.NetworkID != networkID) {
+			continue
+		}
+		appliance := AppliancePerformance{
+			Serial:         status.Serial,
+			Name:           status.Name,
+			Model:          status.Model,
+			Uplinks:        uplinks[status.Serial],
+			NetworkID:      status.NetworkID,
+			NetworkName:    networkNames[status.NetworkID],
+			OrganizationID: organizationID,
+		}
+		if appliance.Uplinks == nil {
+			appliance.Uplinks = []UplinkPerformance{}
+		}
+		for _, uplink := range appliance.Uplinks {
+			appliance.LossPercent = math.Max(appliance.LossPercent, uplink.LossPercent)
+			appliance.LatencyMs = math.Max(appliance.LatencyMs, uplink.LatencyMs)
+		}
+		score, err := c.getAppliancePerfScore(status.Serial, timespan)
+		if err != nil {
+			slog.Warn("Failed to get appliance performance score", "serial", status.Serial, "status", status.Status, "error", err)
+		}
+		appliance.PerfScore = score
+		appliances = append(appliances, appliance)
+	}
+
+	sort.SliceStable(appliances, func(i, j int) bool {
+		if appliances[i].NetworkName != appliances[j].NetworkName {
+			return appliances[i].NetworkName < appliances[j].NetworkName
+		}
+		return appliances[i].Serial < appliances[j].Serial
+	})
+
+	slog.Info("Retrieved appliance performance", "organization_id", organizationID, "appliance_count", len(appliances))
+	return appliances, nil
+}
+
+// getAppliancePerfScore gets the performance score of an appliance over timespan
+func (c *Client) getAppliancePerfScore(serial string, timespan time.Duration) (float64, error) {
+	endpoint := fmt.Sprintf("/devices/%s/appliance/performance?timespan=%d", serial, int(timespan.Seconds()))
+	resp, err := c.makeRequest("GET", endpoint)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var performance struct {
+		PerfScore float64 `json:"perfScore"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&performance); err != nil {
+		return 0, fmt.Errorf("failed to decode appliance performance: %w", err)
+	}
+	return performance.PerfScore, nil
+}
+
+// getUplinksLossAndLatency gets the average loss and latency of every appliance uplink in an
+// organization over the last five minutes, keyed by serial. Samples the API could not measure are
+// reported as null and left out of the averages.
+func (c *Client) getUplinksLossAndLatency(organizationID string) (map[string][]UplinkPerformance, error) {
+	resp, err := c.makeRequest("GET", fmt.Sprintf("/organizations/%s/devices/uplinksLossAndLatency", organizationID))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var series []struct {
+		Serial     string `json:"serial"`
+		Uplink     string `json:"uplink"`
+		IP         string `json:"ip"`
+		TimeSeries []struct {
+			LossPercent *float64 `json:"lossPercent"`
+			LatencyMs   *float64 `json:"latencyMs"`
+		} `json:"timeSeries"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&series); err != nil {
+		return nil, fmt.Errorf("failed to decode uplink loss and latency: %w", err)
+	}
+
+	uplinks := make(map[string][]UplinkPerformance)
+	for _, uplink := range series {
+		var loss, latency []float64
+		for _, sample := range uplink.TimeSeries {
+			if sample.LossPercent != nil {
+				loss = append(loss, *sample.LossPercent)
+			}
+			if sample.LatencyMs != nil {
+				latency = append(latency, *sample.LatencyMs)
+			}
+		}
+		uplinks[uplink.Serial] = append(uplinks[uplink.Serial], UplinkPerformance{
+			Interface:   uplink.Uplink,
+			IP:          uplink.IP,
+			LossPercent: average(loss),
+			LatencyMs:   average(latency),
+		})
+	}
+	return uplinks, nil
+}
+
+// average returns the mean of values rounded to two decimals, or 0 when there are none
+func average(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, value := range values {
+		total += value
+	}
+	return math.Round(total/float64(len(values))*100) / 100
+}
+
+// FilterAppliancePerformance returns the appliances whose performance score or worst uplink loss
+// is above threshold
+func FilterAppliancePerformance(appliances []AppliancePerformance, threshold float64) []AppliancePerformance {
+	filtered := make([]AppliancePerformance, 0, len(appliances))
+	for _, appliance := range appliances {
+		if appliance.PerfScore > threshold || appliance.LossPercent > threshold {
+			filtered = append(filtered, appliance)
+		}
+	}
+	return filtered
+}
+
+// GetDeviceStatusSummary returns device status counts per network and per product type for an organization.
+// Counts come from a single call to the organization statuses endpoint; the network list is only used for names.
+func (c *Client) GetDeviceStatusSummary(organizationID, networkIdentifier string) ([]DeviceStatusSummary, error) {
+	networks, err := c.getOrganizationNetworks(organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get networks for organization %s: %w", organizationID, err)
+	}
+
+	networkID := ""
+	if networkIdentifier != "" {
+		networkID, err = c.ResolveNetworkID(organizationID, networkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	statuses, err := c.getOrganizationDeviceStatuses(organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get device statuses for organization %s: %w", organizationID, err)
+	}
+
+	if networkID != "" {
+		filtered := make([]DeviceStatus, 0)
+		for _, status := range statuses {
+			if status.NetworkID == networkID {
+				filtered = append(filtered, status)
+			}
+		}
+		statuses = filtered
+	}
+
+	summaries := SummarizeDeviceStatuses(statuses, networks)
+	slog.Info("Summarized device statuses", "organization_id", organizationID, "device_count", len(statuses))
+	return summaries, nil
+}
+
+// GetOrganizationDeviceStatusTotal returns the device status counts of a whole organization
+func (c *Client) GetOrganizationDeviceStatusTotal(organizationID string) (DeviceStatusSummary, error) {
+	statuses, err := c.getOrganizationDeviceStatuses(organizationID)
+	if err != nil {
+		return DeviceStatusSummary{}, fmt.Errorf("failed to get device statuses for organization %s: %w", organizationID, err)
+	}
+
+	total := TotalDeviceStatusSummary(SummarizeDeviceStatuses(statuses, nil))
+	total.OrganizationID = organizationID
+	return total, nil
+}
+
+// SummarizeDeviceStatuses aggregates device statuses into per-network and per-product-type counts.
+// Network rows are returned first, sorted by network name, followed by product type rows sorted by type.
+func SummarizeDeviceStatuses(statuses []DeviceStatus, networks []Network) []DeviceStatusSummary {
+	networkNames := make(map[string]string)
+	for _, network := range networks {
+		networkNames[network.ID] = network.Name
+	}
 
-	resp, err := c.makeRequest("GET", endpoint)
-	if err != nil {
-		return nil, err
+	byNetwork := make(map[string]*DeviceStatusSummary)
+	byProductType := make(map[string]*DeviceStatusSummary)
+
+	for _, status := range statuses {
+		networkSummary, ok := byNetwork[status.NetworkID]
+		if !ok {
+			networkSummary = &DeviceStatusSummary{
+				Scope:       "network",
+				NetworkID:   status.NetworkID,
+				NetworkName: networkNames[status.NetworkID],
+			}
+			byNetwork[status.NetworkID] = networkSummary
+		}
+		networkSummary.add(status.Status)
+
+		productSummary, ok := byProductType[status.ProductType]
+		if !ok {
+			productSummary = &DeviceStatusSummary{
+				Scope:       "productType",
+				ProductType: status.ProductType,
+			}
+			byProductType[status.ProductType] = productSummary
+		}
+		productSummary.add(status.Status)
 	}
-	defer resp.Body.Close()
 
-	var deviceStatuses []DeviceStatus
-	if err := json.NewDecoder(resp.Body).Decode(&deviceStatuses); err != nil {
-		return nil, fmt.Errorf("failed to decode device statuses: %w", err)
+	networkRows := make([]DeviceStatusSummary, 0, len(byNetwork))
+	for _, summary := range byNetwork {
+		networkRows = append(networkRows, *summary)
 	}
+	sort.Slice(networkRows, func(i, j int) bool {
+		if networkRows[i].NetworkName != networkRows[j].NetworkName {
+			return networkRows[i].NetworkName < networkRows[j].NetworkName
+		}
+		return networkRows[i].NetworkID < networkRows[j].NetworkID
+	})
 
-	slog.Debug("Retrieved device statuses from organization", "organization_id", organizationID, "status_count", len(deviceStatuses))
-	return deviceStatuses, nil
+	productRows := make([]DeviceStatusSummary, 0, len(byProductType))
+	for _, summary := range byProductType {
+		productRows = append(productRows, *summary)
+	}
+	sort.Slice(productRows, func(i, j int) bool {
+		return productRows[i].ProductType < productRows[j].ProductType
+	})
+
+	return append(networkRows, productRows...)
+}
+
+// TotalDeviceStatusSummary sums the network rows of the given summaries into a single total record
+func TotalDeviceStatusSummary(summaries []DeviceStatusSummary) DeviceStatusSummary {
+	total := DeviceStatusSummary{Scope: "total"}
+	for _, summary := range summaries {
+		if summary.Scope != "network" {
+			continue
+		}
+		total.Online += summary.Online
+		total.Offline += summary.Offline
+		total.Alerting += summary.Alerting
+		total.Dormant += summary.Dormant
+		total.Total += summary.Total
+	}
+	return total
+}
+
+// add counts a single device status in the summary
+func (s *DeviceStatusSummary) add(status string) {
+	switch strings.ToLower(status) {
+	case "online":
+		s.Online++
+	case "offline":
+		s.Offline++
+	case "alerting":
+		s.Alerting++
+	case "dormant":
+		s.Dormant++
+	}
+	s.Total++
 }
 
 // GetAllNetworkRoutes fetches routes for all networks in an organization
 func (c *Client) GetAllNetworkRoutes(organizationID string) ([]NetworkRoutes, error) {
 	// Get all networks in the organization
-	networks, err := c.getOrganizationNetworks(organizationID)
+	networks, err := c.getSelectedNetworks(organizationID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get networks for organization %s: %w", organizationID, err)
 	}
 
+	networks = FilterNetworksByTag(networks, c.tagFilter)
 	slog.Info("Found networks for backup", "count", len(networks))
 
 	var allNetworkRoutes []NetworkRoutes
@@ -894,6 +3655,7 @@ func (c *Client) GetAllNetworkRoutes(organizationID string) ([]NetworkRoutes, er
 			allNetworkRoutes = append(allNetworkRoutes, NetworkRoutes{
 				Network: network,
 				Routes:  []Route{},
+				Error:   err.Error(),
 			})
 			continue
 		}
@@ -958,26 +3720,637 @@ func (c *Client) ResolveNetworkID(organizationID, networkIdentifier string) (str
 		return "", fmt.Errorf("multiple networks found with name '%s' in organization %s. Please use network ID instead. Network IDs: %v", networkIdentifier, organizationID, matchedIDs)
 	}
 
-	slog.Info("Resolved network name to ID", "name", networkIdentifier, "id", matchedNetworks[0].ID)
-	return matchedNetworks[0].ID, nil
+	slog.Info("Resolved network name to ID", "name", networkIdentifier, "id", matchedNetworks[0].ID)
+	return matchedNetworks[0].ID, nil
+}
+
+// IsNetworkPattern reports whether a network identifier contains glob wildcards (*, ?, [)
+func IsNetworkPattern(networkIdentifier string) bool {
+	return strings.ContainsAny(networkIdentifier, "*?[")
+}
+
+// MatchNetworks returns all networks in an organization whose names match a glob pattern
+func (c *Client) MatchNetworks(organizationID, pattern string) ([]Network, error) {
+	networks, err := c.getOrganizationNetworks(organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get networks for organization %s: %w", organizationID, err)
+	}
+
+	matched, err := FilterNetworksByPattern(networks, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(matched) == 0 {
+		return nil, fmt.Errorf("no networks matching '%s' found in organization %s", pattern, organizationID)
+	}
+
+	slog.Info("Matched networks by pattern", "pattern", pattern, "count", len(matched))
+	return matched, nil
+}
+
+// FilterNetworksByPattern returns the networks whose names match a glob pattern (case-insensitive)
+func FilterNetworksByPattern(networks []Network, pattern string) ([]Network, error) {
+	lowerPattern := strings.ToLower(pattern)
+	if _, err := path.Match(lowerPattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid network pattern '%s': %w", pattern, err)
+	}
+
+	matched := make([]Network, 0)
+	for _, network := range networks {
+		if ok, _ := path.Match(lowerPattern, strings.ToLower(network.Name)); ok {
+			matched = append(matched, network)
+		}
+	}
+
+	return matched, nil
+}
+
+// FilterRoutesBySupernet returns the routes whose subnet equals or falls within the supernet CIDR.
+// Routes whose subnet is not a valid CIDR or address are dropped.
+func FilterRoutesBySupernet(routes []Route, supernet string) ([]Route, error) {
+	_, supernetIP, err := net.ParseCIDR(supernet)
+	if err != nil {
+		return nil, fmt.Errorf("invalid supernet '%s': %w", supernet, err)
+	}
+	supernetBits, supernetSize := supernetIP.Mask.Size()
+
+	matched := make([]Route, 0)
+	for _, route := range routes {
+		subnet, ok := parseRouteSubnet(route.Subnet)
+		if !ok {
+			continue
+		}
+		// A subnet is within the supernet when its network address is and its prefix is at least as long
+		bits, size := subnet.Mask.Size()
+		if size == supernetSize && bits >= supernetBits && supernetIP.Contains(subnet.IP) {
+			matched = append(matched, route)
+		}
+	}
+
+	return matched, nil
+}
+
+// FilterLicensesByExpiry returns the licenses that expire within daysUntilExpiry days of today,
+// counting whole days remaining, so licenses expiring today (0 days) and already expired licenses
+// (negative days) are included. Licenses without an expiration date and permanently queued
+// licenses are excluded.
+func FilterLicensesByExpiry(licenses []License, daysUntilExpiry int) ([]License, error) {
+	if daysUntilExpiry < 0 {
+		return nil, fmt.Errorf("days until expiry must not be negative, got %d", daysUntilExpiry)
+	}
+
+	filtered := make([]License, 0)
+	for _, license := range licenses {
+		daysRemaining, ok, err := license.DaysRemaining()
+		if err != nil {
+			return nil, err
+		}
+		if ok && daysRemaining <= daysUntilExpiry {
+			filtered = append(filtered, license)
+		}
+	}
+
+	return filtered, nil
+}
+
+// FilterLicensesByExpirationRange returns the licenses that expire at or after after and before
+// before. A zero bound leaves that side of the range open. Licenses without an expiration date and
+// permanently queued licenses are excluded.
+func FilterLicensesByExpirationRange(licenses []License, after, before time.Time) ([]License, error) {
+	filtered := make([]License, 0)
+	for _, license := range licenses {
+		expiration, ok, err := license.Expiration()
+		if err != nil {
+			return nil, err
+		}
+		if !ok || (!after.IsZero() && expiration.Before(after)) || (!before.IsZero() && !expiration.Before(before)) {
+			continue
+		}
+		filtered = append(filtered, license)
+	}
+
+	return filtered, nil
+}
+
+// DaysRemaining returns the whole days until the license expires, negative once it has expired.
+// ok is false for licenses without an expiration date and permanently queued licenses.
+func (l License) DaysRemaining() (days int, ok bool, err error) {
+	expiration, ok, err := l.Expiration()
+	if !ok || err != nil {
+		return 0, ok, err
+	}
+	return int(time.Until(expiration).Hours() / 24), true, nil
+}
+
+// Expiration returns the parsed expiration date of the license.
+// ok is false for licenses without an expiration date and permanently queued licenses.
+func (l License) Expiration() (expiration time.Time, ok bool, err error) {
+	if l.PermanentlyQueued || l.ExpirationDate == "" {
+		return time.Time{}, false, nil
+	}
+	expiration, err = time.Parse(time.RFC3339, l.ExpirationDate)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid expiration date '%s' for license %s: %w", l.ExpirationDate, l.ID, err)
+	}
+	return expiration, true, nil
+}
+
+// LicenseStatistics aggregates a set of licenses
+type LicenseStatistics struct {
+	Total             int
+	ByState           map[string]int
+	SoonestExpiration time.Time // Zero when no license has an expiration date
+	LatestExpiration  time.Time // Zero when no license has an expiration date
+	TotalDurationDays int
+}
+
+// SummarizeLicenses counts licenses by state, finds the soonest and latest expiration dates and
+// adds up their durations. Licenses without an expiration date and permanently queued licenses
+// are counted but do not affect the expiration dates.
+func SummarizeLicenses(licenses []License) (LicenseStatistics, error) {
+	stats := LicenseStatistics{Total: len(licenses), ByState: make(map[string]int)}
+	for _, license := range licenses {
+		stats.ByState[license.State]++
+		stats.TotalDurationDays += license.DurationInDays
+
+		expiration, ok, err := license.Expiration()
+		if err != nil {
+			return LicenseStatistics{}, err
+		}
+		if !ok {
+			continue
+		}
+		if stats.SoonestExpiration.IsZero() || expiration.Before(stats.SoonestExpiration) {
+			stats.SoonestExpiration = expiration
+		}
+		if expiration.After(stats.LatestExpiration) {
+			stats.LatestExpiration = expiration
+		}
+	}
+
+	return stats, nil
+}
+
+// OrganizationLicenseSummary collapses the licenses of one organization into a single entry
+type OrganizationLicenseSummary struct {
+	Organization      string              `json:"organization" xml:"organization"`
+	OrganizationID    string              `json:"organization_id" xml:"organizationId"`
+	Total             int                 `json:"total" xml:"total"`
+	States            []LicenseStateCount `json:"states" xml:"states>state"`
+	SoonestExpiration string              `json:"soonest_expiration,omitempty" xml:"soonestExpiration,omitempty"` // Empty when no license has an expiration date
+	LatestExpiration  string              `json:"latest_expiration,omitempty" xml:"latestExpiration,omitempty"`   // Empty when no license has an expiration date
+	TotalDurationDays int                 `json:"total_duration_days" xml:"totalDurationDays"`
+}
+
+// LicenseStateCount is the number of licenses in a single state
+type LicenseStateCount struct {
+	State string `json:"state" xml:"name,attr"`
+	Count int    `json:"count" xml:",chardata"`
+}
+
+// MergeOrganizationLicenses summarizes licenses per organization, in the order the organizations
+// first appear. States are sorted by name and expiration dates are formatted as YYYY-MM-DD.
+func MergeOrganizationLicenses(licenses []LicenseWithNetwork) ([]OrganizationLicenseSummary, error) {
+	grouped := make(map[string][]License)
+	organizations := make([]LicenseWithNetwork, 0)
+	for _, license := range licenses {
+		if _, seen := grouped[license.OrganizationID]; !seen {
+			organizations = append(organizations, license)
+		}
+		grouped[license.OrganizationID] = append(grouped[license.OrganizationID], license.License)
+	}
+
+	summaries := make([]OrganizationLicenseSummary, 0, len(organizations))
+	for _, organization := range organizations {
+		stats, err := SummarizeLicenses(grouped[organization.OrganizationID])
+		if err != nil {
+			return nil, err
+		}
+
+		summary := OrganizationLicenseSummary{
+			Organization:      organization.Organization,
+			OrganizationID:    organization.OrganizationID,
+			Total:             stats.Total,
+			States:            make([]LicenseStateCount, 0, len(stats.ByState)),
+			TotalDurationDays: stats.TotalDurationDays,
+		}
+		for state, count := range stats.ByState {
+			summary.States = append(summary.States, LicenseStateCount{State: state, Count: count})
+		}
+		sort.Slice(summary.States, func(i, j int) bool {
+			return summary.States[i].State < summary.States[j].State
+		})
+		if !stats.SoonestExpiration.IsZero() {
+			summary.SoonestExpiration = stats.SoonestExpiration.Format("2006-01-02")
+			summary.LatestExpiration = stats.LatestExpiration.Format("2006-01-02")
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, nil
+}
+
+// FilterDevicesBySerial returns the devices whose serial equals serial (case-insensitive).
+// With no serial the devices are returned unchanged.
+func FilterDevicesBySerial(devices []Device, serial string) []Device {
+	if serial == "" {
+		return devices
+	}
+
+	filtered := make([]Device, 0, 1)
+	for _, device := range devices {
+		if strings.EqualFold(device.Serial, serial) {
+			filtered = append(filtered, device)
+		}
+	}
+	return filtered
+}
+
+// FilterRoutesByRegex returns the routes whose name, or networkName, matches re. networkName may
+// be empty when the routes are written without their network. With no re the routes are returned
+// unchanged.
+func FilterRoutesByRegex(routes []Route, re *regexp.Regexp, networkName string) []Route {
+	if re == nil {
+		return routes
+	}
+
+	filtered := make([]Route, 0, len(routes))
+	for _, route := range routes {
+		if matchesAnyName(re, route.Name, networkName) {
+			filtered = append(filtered, route)
+		}
+	}
+	return filtered
+}
+
+// FilterDevicesByRegex returns the devices whose name, or networkName, matches re. networkName may
+// be empty when the devices are written without their network. With no re the devices are returned
+// unchanged.
+func FilterDevicesByRegex(devices []Device, re *regexp.Regexp, networkName string) []Device {
+	if re == nil {
+		return devices
+	}
+
+	filtered := make([]Device, 0, len(devices))
+	for _, device := range devices {
+		if matchesAnyName(re, device.Name, networkName) {
+			filtered = append(filtered, device)
+		}
+	}
+	return filtered
+}
+
+// matchesAnyName reports whether re matches any of the non-empty names
+func matchesAnyName(re *regexp.Regexp, names ...string) bool {
+	for _, name := range names {
+		if name != "" && re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterLicensesByState returns the licenses whose state is one of states (case-insensitive).
+// With no states the licenses are returned unchanged.
+func FilterLicensesByState(licenses []License, states []string) []License {
+	if len(states) == 0 {
+		return licenses
+	}
+
+	filtered := make([]License, 0, len(licenses))
+	for _, license := range licenses {
+		for _, state := range states {
+			if strings.EqualFold(license.State, state) {
+				filtered = append(filtered, license)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// FilterRoutesByEnabled keeps only enabled routes when enabledOnly is set, or only disabled
+// routes when disabledOnly is set. With neither set the routes are returned unchanged.
+func FilterRoutesByEnabled(routes []RouteWithNetwork, enabledOnly, disabledOnly bool) []RouteWithNetwork {
+	if !enabledOnly && !disabledOnly {
+		return routes
+	}
+
+	filtered := make([]RouteWithNetwork, 0, len(routes))
+	for _, route := range routes {
+		if (enabledOnly && route.Enabled) || (disabledOnly && !route.Enabled) {
+			filtered = append(filtered, route)
+		}
+	}
+	return filtered
+}
+
+// FilterRoutesByGateway returns the routes whose next-hop gateway matches gatewayFilter. A bare
+// address must equal the gateway IP exactly, while a CIDR matches every gateway IP within it. With
+// no gatewayFilter the routes are returned unchanged.
+func FilterRoutesByGateway(routes []RouteWithNetwork, gatewayFilter string) ([]RouteWithNetwork, error) {
+	if gatewayFilter == "" {
+		return routes, nil
+	}
+
+	var gatewayNet *net.IPNet
+	if strings.Contains(gatewayFilter, "/") {
+		_, ipNet, err := net.ParseCIDR(gatewayFilter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gateway filter '%s': %w", gatewayFilter, err)
+		}
+		gatewayNet = ipNet
+	} else if net.ParseIP(gatewayFilter) == nil {
+		return nil, fmt.Errorf("invalid gateway filter '%s': not an IP address or CIDR", gatewayFilter)
+	}
+
+	filtered := make([]RouteWithNetwork, 0, len(routes))
+	for _, route := range routes {
+		if gatewayNet == nil {
+			if route.GatewayIP == gatewayFilter {
+				filtered = append(filtered, route)
+			}
+			continue
+		}
+		if ip := net.ParseIP(route.GatewayIP); ip != nil && gatewayNet.Contains(ip) {
+			filtered = append(filtered, route)
+		}
+	}
+	return filtered, nil
+}
+
+// parseRouteSubnet parses a route subnet as a CIDR, treating a bare address as a host route
+func parseRouteSubnet(subnet string) (*net.IPNet, bool) {
+	if _, ipNet, err := net.ParseCIDR(strings.TrimSpace(subnet)); err == nil {
+		return ipNet, true
+	}
+	ip := net.ParseIP(strings.TrimSpace(subnet))
+	if ip == nil {
+		return nil, false
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, true
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, true
+}
+
+// routePrefix parses a route subnet as a prefix, treating a bare address as a host route
+func routePrefix(subnet string) (netip.Prefix, bool) {
+	subnet = strings.TrimSpace(subnet)
+	if prefix, err := netip.ParsePrefix(subnet); err == nil {
+		return prefix, true
+	}
+	addr, err := netip.ParseAddr(subnet)
+	if err != nil {
+		return netip.Prefix{}, false
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), true
+}
+
+// IsIPv6 reports whether the route's subnet is an IPv6 prefix or address. Subnets that cannot
+// be parsed are not IPv6.
+func (r Route) IsIPv6() bool {
+	prefix, ok := routePrefix(r.Subnet)
+	return ok && prefix.Addr().Is6()
+}
+
+// FilterRoutesByIPVersion returns the routes whose subnet is of ipVersion, 4 or 6. With ipVersion 0
+// the routes are returned unchanged; otherwise routes whose subnet cannot be parsed are dropped.
+func FilterRoutesByIPVersion(routes []Route, ipVersion int) []Route {
+	if ipVersion == 0 {
+		return routes
+	}
+
+	filtered := make([]Route, 0, len(routes))
+	for _, route := range routes {
+		prefix, ok := routePrefix(route.Subnet)
+		if !ok {
+			slog.Debug("Skipping route with unparseable subnet", "route_id", route.ID, "subnet", route.Subnet)
+			continue
+		}
+		if (ipVersion == 6) == prefix.Addr().Is6() {
+			filtered = append(filtered, route)
+		}
+	}
+	return filtered
+}
+
+// IsDefault reports whether the route is a default route, 0.0.0.0/0 or ::/0
+func (r Route) IsDefault() bool {
+	prefix, ok := routePrefix(r.Subnet)
+	return ok && prefix.Bits() == 0
+}
+
+// FilterDefaultRoutes returns only the default routes when only is set, and the routes unchanged
+// otherwise
+func FilterDefaultRoutes(routes []Route, only bool) []Route {
+	if !only {
+		return routes
+	}
+
+	filtered := make([]Route, 0)
+	for _, route := range routes {
+		if route.IsDefault() {
+			filtered = append(filtered, route)
+		}
+	}
+	return filtered
+}
+
+// FindRouteOverlaps returns every pair of routes whose subnets overlap, across all the networks
+// the routes belong to. Two prefixes overlap when one contains the other; the pair is ordered so
+// that Route has the shorter prefix. Routes whose subnet cannot be parsed are skipped.
+func FindRouteOverlaps(routes []RouteWithNetwork) []RouteOverlap {
+	type parsedRoute struct {
+		route  RouteWithNetwork
+		prefix netip.Prefix
+	}
+	parsed := make([]parsedRoute, 0, len(routes))
+	for _, route := range routes {
+		prefix, ok := routePrefix(route.Subnet)
+		if !ok {
+			slog.Debug("Skipping route with unparseable subnet", "route_id", route.ID, "subnet", route.Subnet)
+			continue
+		}
+		parsed = append(parsed, parsedRoute{route, prefix.Masked()})
+	}
+
+	overlaps := make([]RouteOverlap, 0)
+	for i := range parsed {
+		for j := i + 1; j < len(parsed); j++ {
+			outer, inner := parsed[i], parsed[j]
+			if inner.prefix.Bits() < outer.prefix.Bits() {
+				outer, inner = inner, outer
+			}
+			if !outer.prefix.Contains(inner.prefix.Addr()) {
+				continue
+			}
+			relation := OverlapContains
+			if outer.prefix == inner.prefix {
+				relation = OverlapEqual
+			}
+			overlaps = append(overlaps, RouteOverlap{Route: outer.route, OverlapsWith: inner.route, Relation: relation})
+		}
+	}
+	return overlaps
+}
+
+// TagFilter selects networks or devices by their Meraki tags (case-insensitive)
+type TagFilter struct {
+	Tags     []string
+	MatchAny bool // Match any of the tags instead of requiring all of them
+}
+
+// IsEmpty reports whether no tags are set
+func (f TagFilter) IsEmpty() bool {
+	return len(f.Tags) == 0
+}
+
+// Matches reports whether tags satisfy the filter; an empty filter matches everything
+func (f TagFilter) Matches(tags []string) bool {
+	for _, want := range f.Tags {
+		found := matchesAny(want, tags)
+		if f.MatchAny && found {
+			return true
+		}
+		if !f.MatchAny && !found {
+			return false
+		}
+	}
+	return !f.MatchAny || f.IsEmpty()
+}
+
+// FilterNetworksByTag returns the networks whose tags satisfy filter
+func FilterNetworksByTag(networks []Network, filter TagFilter) []Network {
+	if filter.IsEmpty() {
+		return networks
+	}
+
+	matched := make([]Network, 0)
+	for _, network := range networks {
+		if filter.Matches(network.Tags) {
+			matched = append(matched, network)
+		}
+	}
+
+	slog.Info("Filtered networks by tag", "tags", filter.Tags, "match_any", filter.MatchAny, "total_networks", len(networks), "matched_networks", len(matched))
+	return matched
+}
+
+// FilterNetworksByProductType returns the networks containing any of productTypes. With no
+// product types the networks are returned unchanged.
+func FilterNetworksByProductType(networks []Network, productTypes []string) []Network {
+	if len(productTypes) == 0 {
+		return networks
+	}
+
+	matched := make([]Network, 0)
+	for _, network := range networks {
+		for _, productType := range network.ProductTypes {
+			if slices.ContainsFunc(productTypes, func(wanted string) bool { return strings.EqualFold(wanted, productType) }) {
+				matched = append(matched, network)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// applyTagFilter keeps devices whose own tags, or whose network's tags, satisfy the tag filter.
+// The organization's networks are only fetched when a tag filter is set.
+func (c *Client) applyTagFilter(organizationID string, devices []Device) ([]Device, error) {
+	if c.tagFilter.IsEmpty() {
+		return devices, nil
+	}
+
+	networks, err := c.getOrganizationNetworks(organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get networks for organization %s: %w", organizationID, err)
+	}
+	networkTags := make(map[string][]string)
+	for _, network := range networks {
+		networkTags[network.ID] = network.Tags
+	}
+
+	filtered := make([]Device, 0)
+	for _, device := range devices {
+		if c.tagFilter.Matches(device.Tags) || c.tagFilter.Matches(networkTags[device.NetworkID]) {
+			filtered = append(filtered, device)
+		}
+	}
+	return filtered, nil
 }
 
 // ResolveOrganizationID resolves an organization name or ID to an organization ID
 func (c *Client) ResolveOrganizationID(organizationIdentifier string) (string, error) {
+	org, err := c.ResolveOrganization(organizationIdentifier)
+	if err != nil {
+		return "", err
+	}
+	return org.ID, nil
+}
+
+// ResolveOrganization resolves an organization name or ID to the organization, keeping both its ID and name
+func (c *Client) ResolveOrganization(organizationIdentifier string) (Organization, error) {
 	if organizationIdentifier == "" {
-		return "", nil
+		return Organization{}, nil
 	}
 
 	// Get all organizations
 	organizations, err := c.GetOrganizations()
 	if err != nil {
-		return "", fmt.Errorf("failed to get organizations: %w", err)
+		return Organization{}, fmt.Errorf("failed to get organizations: %w", err)
+	}
+
+	return findOrganization(organizations, organizationIdentifier)
+}
+
+// ResolveOrganizations resolves several organization names or IDs, listing the organizations once.
+// Identifiers resolving to the same organization yield it once, in the order first given. Any
+// identifier that cannot be resolved is an error. A single identifier that does not name an
+// organization as a whole is split on commas, so "Org A,Org B" selects both organizations while
+// an organization named "Acme, Inc." is still found by its name.
+func (c *Client) ResolveOrganizations(organizationIdentifiers []string) ([]Organization, error) {
+	organizations, err := c.GetOrganizations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organizations: %w", err)
 	}
 
+	identifiers := organizationIdentifiers
+	if len(identifiers) == 1 && strings.Contains(identifiers[0], ",") {
+		if _, err := findOrganization(organizations, identifiers[0]); err != nil {
+			identifiers = nil
+			for _, identifier := range strings.Split(organizationIdentifiers[0], ",") {
+				if identifier = strings.TrimSpace(identifier); identifier != "" {
+					identifiers = append(identifiers, identifier)
+				}
+			}
+		}
+	}
+
+	resolved := make([]Organization, 0, len(identifiers))
+	seen := make(map[string]bool)
+	for _, identifier := range identifiers {
+		org, err := findOrganization(organizations, identifier)
+		if err != nil {
+			return nil, err
+		}
+		if seen[org.ID] {
+			continue
+		}
+		seen[org.ID] = true
+		resolved = append(resolved, org)
+	}
+	return resolved, nil
+}
+
+// findOrganization finds an organization by ID, or else by name (case-insensitive)
+func findOrganization(organizations []Organization, organizationIdentifier string) (Organization, error) {
 	// First check if it's already a valid organization ID
 	for _, org := range organizations {
 		if org.ID == organizationIdentifier {
-			return organizationIdentifier, nil
+			return org, nil
 		}
 	}
 
@@ -997,9 +4370,9 @@ func (c *Client) ResolveOrganizationID(organizationIdentifier string) (string, e
 			availableOrganizations = append(availableOrganizations, fmt.Sprintf("%s (ID: %s)", org.Name, org.ID))
 		}
 		if len(availableOrganizations) > 0 {
-			return "", fmt.Errorf("organization '%s' not found. Available organizations: %v", organizationIdentifier, availableOrganizations)
+			return Organization{}, fmt.Errorf("organization '%s' not found. Available organizations: %v", organizationIdentifier, availableOrganizations)
 		}
-		return "", fmt.Errorf("organization '%s' not found (no organizations found)", organizationIdentifier)
+		return Organization{}, fmt.Errorf("organization '%s' not found (no organizations found)", organizationIdentifier)
 	}
 
 	if len(matchedOrganizations) > 1 {
@@ -1008,12 +4381,12 @@ func (c *Client) ResolveOrganizationID(organizationIdentifier string) (string, e
 		for _, org := range matchedOrganizations {
 			duplicateOrgs = append(duplicateOrgs, fmt.Sprintf("%s (ID: %s)", org.Name, org.ID))
 		}
-		return "", fmt.Errorf("multiple organizations found with name '%s': %v. Please use organization ID for disambiguation", organizationIdentifier, duplicateOrgs)
+		return Organization{}, fmt.Errorf("multiple organizations found with name '%s': %v. Please use organization ID for disambiguation", organizationIdentifier, duplicateOrgs)
 	}
 
 	// Single match found
 	slog.Info("Resolved organization name to ID", "name", organizationIdentifier, "id", matchedOrganizations[0].ID)
-	return matchedOrganizations[0].ID, nil
+	return matchedOrganizations[0], nil
 }
 
 // GetLicenses fetches license information for the specified organization
@@ -1034,6 +4407,55 @@ func (c *Client) GetLicenses(organizationID string) ([]License, error) {
 	return licenses, nil
 }
 
+// ResolveLicenseNetworks sets the effective network of each license. Per-device licenses usually
+// have no networkId of their own, so their network is taken from the device they are bound to.
+// NetworkName is filled in for every license with a known network.
+func (c *Client) ResolveLicenseNetworks(organizationID string, licenses []License) ([]License, error) {
+	networks, err := c.getOrganizationNetworks(organizationID)
+	if err != nil {
+		return licenses, fmt.Errorf("failed to get networks for organization %s: %w", organizationID, err)
+	}
+
+	// Only look up devices when some license has to be resolved through its device
+	var statuses []DeviceStatus
+	for _, license := range licenses {
+		if license.NetworkID == "" && license.DeviceSerial != "" {
+			statuses, err = c.getOrganizationDeviceStatuses(organizationID)
+			if err != nil {
+				return licenses, fmt.Errorf("failed to get devices for organization %s: %w", organizationID, err)
+			}
+			break
+		}
+	}
+
+	return JoinLicenseNetworks(licenses, networks, statuses), nil
+}
+
+// JoinLicenseNetworks joins licenses to networks, through the bound device's network when the
+// license has no networkId. The licenses are copied, not modified in place.
+func JoinLicenseNetworks(licenses []License, networks []Network, devices []DeviceStatus) []License {
+	networkNames := make(map[string]string, len(networks))
+	for _, network := range networks {
+		networkNames[network.ID] = network.Name
+	}
+	deviceNetworks := make(map[string]string, len(devices))
+	for _, device := range devices {
+		deviceNetworks[device.Serial] = device.NetworkID
+	}
+
+	resolved := make([]License, len(licenses))
+	for i, license := range licenses {
+		if license.NetworkID == "" && license.DeviceSerial != "" {
+			license.NetworkID = deviceNetworks[license.DeviceSerial]
+		}
+		if license.NetworkID != "" {
+			license.NetworkName = networkNames[license.NetworkID]
+		}
+		resolved[i] = license
+	}
+	return resolved
+}
+
 // GetAllNetworkLicenses fetches licenses for all networks in an organization
 func (c *Client) GetAllNetworkLicenses(organizationID string) ([]NetworkLicenses, error) {
 	// Get all networks in the organization
@@ -1053,6 +4475,13 @@ func (c *Client) GetAllNetworkLicenses(organizationID string) ([]NetworkLicenses
 		orgLicenses = []License{} // Continue with empty licenses if org licenses fail
 	}
 
+	// Attribute per-device licenses to their device's network rather than to every network
+	if resolved, err := c.ResolveLicenseNetworks(organizationID, orgLicenses); err != nil {
+		slog.Warn("Failed to resolve license networks", "org_id", organizationID, "error", err)
+	} else {
+		orgLicenses = resolved
+	}
+
 	// For each network, collect relevant licenses
 	for _, network := range networks {
 		// Filter licenses relevant to this network (if they have networkId specified)
@@ -1116,26 +4545,62 @@ func (c *Client) GetDevices(organizationID, networkIdentifier string) ([]Device,
 	return devices, nil
 }
 
-// GetDownDevices fetches devices that are currently down/offline
-func (c *Client) GetDownDevices(organizationID, networkIdentifier string) ([]Device, error) {
+// GetDownDevices fetches devices that are currently down/offline, i.e. whose status is one of
+// downStatuses. A nil or empty downStatuses uses DefaultDownStatuses.
+func (c *Client) GetDownDevices(organizationID, networkIdentifier string, downStatuses []string) ([]Device, error) {
+	if len(downStatuses) == 0 {
+		downStatuses = DefaultDownStatuses
+	}
+
 	// Get all devices first
 	allDevices, err := c.GetDevices(organizationID, networkIdentifier)
 	if err != nil {
 		return nil, err
 	}
 
+	// Label appliances in warm spare pairs so passive spares can be told apart from real outages
+	c.annotateWarmSpare(organizationID, allDevices)
+
 	// Filter for devices that are down/offline
 	downDevices := make([]Device, 0) // Initialize as empty slice instead of nil slice
 	for _, device := range allDevices {
 		// Check if device is offline/down
 		// Meraki API typically uses "offline", "alerting", or similar statuses for down devices
-		if isDeviceDown(device.Status) {
+		if !isDeviceDown(device.Status, downStatuses) {
+			continue
+		}
+		if c.ignoreWarmSpare && isIdleWarmSpare(device, allDevices, downStatuses) {
+			slog.Debug("Ignoring down warm spare with online primary", "serial", device.Serial)
+			continue
+		}
+
+		lastReported, ok := device.LastReportedTime()
+		if !ok {
+			// Without a last report time we can't tell how long it has been down, so keep it
+			slog.Debug("Down device has no usable last reported time", "serial", device.Serial, "last_reported_at", device.LastReportedAt)
+			device.DownDuration = UnknownDownDuration
 			downDevices = append(downDevices, device)
+			continue
 		}
+
+		downFor := now().Sub(lastReported)
+		if downFor < c.downLongerThan {
+			continue
+		}
+
+		device.DownDuration = formatDownDuration(downFor)
+		downDevices = append(downDevices, device)
 	}
+	sort.SliceStable(downDevices, func(i, j int) bool {
+		return DownLonger(downDevices[i], downDevices[j])
+	})
 
-	slog.Info("Filtered down devices", "total_devices", len(allDevices), "down_devices", len(downDevices))
-	return downDevices, nil
+	filteredDevices, err := c.applyTagFilter(organizationID, c.deviceFilter.Apply(downDevices))
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("Filtered down devices", "total_devices", len(allDevices), "down_devices", len(downDevices), "after_device_filters", len(filteredDevices), "filtered_out", len(downDevices)-len(filteredDevices))
+	return filteredDevices, nil
 }
 
 // GetAlertingDevices fetches devices that are currently alerting
@@ -1158,8 +4623,12 @@ func (c *Client) GetAlertingDevices(organizationID, networkIdentifier string) ([
 				alertingDevices = append(alertingDevices, device)
 			}
 		}
-		slog.Info("Filtered alerting devices (fallback)", "total_devices", len(allDevices), "alerting_devices", len(alertingDevices))
-		return alertingDevices, nil
+		filteredDevices, err := c.applyTagFilter(organizationID, c.deviceFilter.Apply(alertingDevices))
+		if err != nil {
+			return nil, err
+		}
+		slog.Info("Filtered alerting devices (fallback)", "total_devices", len(allDevices), "alerting_devices", len(alertingDevices), "after_device_filters", len(filteredDevices), "filtered_out", len(alertingDevices)-len(filteredDevices))
+		return filteredDevices, nil
 	}
 
 	// Create a map of device serial to status for quick lookup
@@ -1183,8 +4652,260 @@ func (c *Client) GetAlertingDevices(organizationID, networkIdentifier string) ([
 		}
 	}
 
-	slog.Info("Filtered alerting devices", "total_devices", len(allDevices), "alerting_devices", len(alertingDevices))
-	return alertingDevices, nil
+	filteredDevices, err := c.applyTagFilter(organizationID, c.deviceFilter.Apply(alertingDevices))
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("Filtered alerting devices", "total_devices", len(allDevices), "alerting_devices", len(alertingDevices), "after_device_filters", len(filteredDevices), "filtered_out", len(alertingDevices)-len(filteredDevices))
+	return filteredDevices, nil
+}
+
+// Severities of health alerts
+const (
+	HealthAlertSeverityError         = "error"
+	HealthAlertSeverityWarning       = "warning"
+	HealthAlertSeverityInformational = "informational"
+)
+
+// HealthAlert is an explicit health alert raised by the dashboard for a network or one of its devices
+type HealthAlert struct {
+	ID       string            `json:"id"`
+	Category string            `json:"category"` // security, configuration, performance or topology
+	Scope    string            `json:"scope"`    // network or device
+	Severity string            `json:"severity"` // error, warning or informational
+	Type     string            `json:"type"`
+	Source   HealthAlertSource `json:"source"`
+}
+
+// HealthAlertSource is the device a device-scoped health alert was raised for
+type HealthAlertSource struct {
+	Serial string `json:"serial,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Model  string `json:"model,omitempty"`
+}
+
+// UnmarshalJSON decodes a health alert. The API reports the scope either as "network" or
+// "device" with the device under source, or as an object listing the affected devices, in which
+// case the first device becomes the source.
+func (a *HealthAlert) UnmarshalJSON(data []byte) error {
+	type plain HealthAlert
+	var alert struct {
+		plain
+		Scope json.RawMessage `json:"scope"`
+	}
+	if err := json.Unmarshal(data, &alert); err != nil {
+		return err
+	}
+	*a = HealthAlert(alert.plain)
+
+	if len(alert.Scope) == 0 || string(alert.Scope) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(alert.Scope, &a.Scope); err == nil {
+		return nil
+	}
+	var scope struct {
+		Devices []HealthAlertSource `json:"devices"`
+	}
+	if err := json.Unmarshal(alert.Scope, &scope); err != nil {
+		return fmt.Errorf("invalid health alert scope: %w", err)
+	}
+	a.Scope = "network"
+	if len(scope.Devices) > 0 {
+		a.Scope = "device"
+		a.Source = scope.Devices[0]
+	}
+	return nil
+}
+
+// GetNetworkHealthAlerts fetches the health alerts currently raised for a network
+func (c *Client) GetNetworkHealthAlerts(networkID string) ([]HealthAlert, error) {
+	resp, err := c.makeRequest("GET", fmt.Sprintf("/networks/%s/health/alerts", networkID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get health alerts for network %s: %w", networkID, err)
+	}
+	defer resp.Body.Close()
+
+	var alerts []HealthAlert
+	if err := json.NewDecoder(resp.Body).Decode(&alerts); err != nil {
+		return nil, fmt.Errorf("failed to decode health alerts response: %w", err)
+	}
+
+	slog.Debug("Retrieved health alerts", "network_id", networkID, "count", len(alerts))
+	return alerts, nil
+}
+
+// healthAlertSeverityRank orders severities, most severe first; unknown severities rank last
+func healthAlertSeverityRank(severity string) int {
+	switch strings.ToLower(severity) {
+	case HealthAlertSeverityError:
+		return 0
+	case HealthAlertSeverityWarning:
+		return 1
+	case HealthAlertSeverityInformational:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// MergeHealthAlerts merges the health alerts of a network into its alerting devices. Each device
+// appears once: alerts are deduplicated by source serial, keeping the most severe, which is set as
+// the device's HealthAlert. Devices with an alert that are not alerting by status are appended with
+// status "alerting", named after the first of their alerts that gives a name and model.
+// Network-scoped alerts have no device and are skipped.
+func MergeHealthAlerts(devices []Device, alerts []HealthAlert, networkID string) []Device {
+	worst := make(map[string]HealthAlert)
+	sources := make(map[string]HealthAlertSource)
+	order := make([]string, 0)
+	for _, alert := range alerts {
+		serial := alert.Source.Serial
+		if serial == "" {
+			slog.Debug("Skipping health alert without a device", "network_id", networkID, "type", alert.Type, "scope", alert.Scope)
+			continue
+		}
+		current, seen := worst[serial]
+		if !seen {
+			order = append(order, serial)
+		}
+		if !seen || healthAlertSeverityRank(alert.Severity) < healthAlertSeverityRank(current.Severity) {
+			worst[serial] = alert
+		}
+
+		source := sources[serial]
+		if source.Name == "" {
+			source.Name = alert.Source.Name
+		}
+		if source.Model == "" {
+			source.Model = alert.Source.Model
+		}
+		sources[serial] = source
+	}
+
+	merged := make([]Device, 0, len(devices)+len(order))
+	listed := make(map[string]bool, len(devices))
+	for _, device := range devices {
+		if alert, ok := worst[device.Serial]; ok {
+			device.HealthAlert = alert.Severity + ": " + alert.Type
+		}
+		listed[device.Serial] = true
+		merged = append(merged, device)
+	}
+	for _, serial := range order {
+		if listed[serial] {
+			continue
+		}
+		alert := worst[serial]
+		merged = append(merged, Device{
+			Serial:      serial,
+			Name:        sources[serial].Name,
+			Model:       sources[serial].Model,
+			NetworkID:   networkID,
+			Status:      "alerting",
+			HealthAlert: alert.Severity + ": " + alert.Type,
+		})
+	}
+	return merged
+}
+
+// WarmSpare is an appliance network's warm spare (HA) configuration
+type WarmSpare struct {
+	Enabled       bool   `json:"enabled"`
+	PrimarySerial string `json:"primarySerial,omitempty"`
+	SpareSerial   string `json:"spareSerial,omitempty"`
+	UplinkMode    string `json:"uplinkMode,omitempty"`
+}
+
+// getNetworkWarmSpare fetches the warm spare configuration of an appliance network
+func (c *Client) getNetworkWarmSpare(networkID string) (*WarmSpare, error) {
+	endpoint := fmt.Sprintf("/networks/%s/appliance/warmSpare", networkID)
+
+	resp, err := c.makeRequest("GET", endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var warmSpare WarmSpare
+	if err := json.NewDecoder(resp.Body).Decode(&warmSpare); err != nil {
+		return nil, fmt.Errorf("failed to decode warm spare response: %w", err)
+	}
+
+	return &warmSpare, nil
+}
+
+// annotateWarmSpare sets WarmSpareRole on appliances that belong to an enabled warm spare pair.
+// Only networks whose ProductTypes include appliance are queried, and nothing is fetched when
+// no appliances are present. Failures are logged and leave devices unannotated.
+func (c *Client) annotateWarmSpare(organizationID string, devices []Device) {
+	applianceNetworks := make(map[string]bool)
+	for _, device := range devices {
+		if device.ProductType == "appliance" {
+			applianceNetworks[device.NetworkID] = true
+		}
+	}
+	if len(applianceNetworks) == 0 {
+		return
+	}
+
+	networks, err := c.getOrganizationNetworks(organizationID)
+	if err != nil {
+		slog.Warn("Failed to get networks for warm spare lookup", "organization_id", organizationID, "error", err)
+		return
+	}
+
+	roles := make(map[string]string)
+	for _, network := range networks {
+		if !applianceNetworks[network.ID] || !hasProductType(network, "appliance") {
+			continue
+		}
+
+		warmSpare, err := c.getNetworkWarmSpare(network.ID)
+		if err != nil {
+			slog.Warn("Failed to get warm spare configuration", "network_id", network.ID, "network_name", network.Name, "error", err)
+			continue
+		}
+		if !warmSpare.Enabled {
+			continue
+		}
+
+		slog.Debug("Found warm spare pair", "network_id", network.ID, "primary", warmSpare.PrimarySerial, "spare", warmSpare.SpareSerial)
+		if warmSpare.PrimarySerial != "" {
+			roles[warmSpare.PrimarySerial] = "primary"
+		}
+		if warmSpare.SpareSerial != "" {
+			roles[warmSpare.SpareSerial] = "spare"
+		}
+	}
+
+	for i := range devices {
+		if role, ok := roles[devices[i].Serial]; ok {
+			devices[i].WarmSpareRole = role
+		}
+	}
+}
+
+// hasProductType reports whether a network contains the given product type
+func hasProductType(network Network, productType string) bool {
+	for _, pt := range network.ProductTypes {
+		if strings.EqualFold(pt, productType) {
+			return true
+		}
+	}
+	return false
+}
+
+// isIdleWarmSpare reports whether device is a warm spare whose primary in the same network is online
+func isIdleWarmSpare(device Device, devices []Device, downStatuses []string) bool {
+	if device.WarmSpareRole != "spare" {
+		return false
+	}
+	for _, other := range devices {
+		if other.NetworkID == device.NetworkID && other.WarmSpareRole == "primary" {
+			return !isDeviceDown(other.Status, downStatuses)
+		}
+	}
+	return false
 }
 
 // getNetworkDevices fetches all devices in a specific network
@@ -1240,20 +4961,217 @@ func (c *Client) GetAllNetworkDevices(organizationID string) ([]NetworkDevices,
 	return allNetworkDevices, nil
 }
 
-// isDeviceDown determines if a device is considered down based on its status
-func isDeviceDown(status string) bool {
-	downStatuses := []string{
-		"offline",
-		"alerting",
-		"dormant",
-		"down",
-		"unreachable",
-		"disconnected",
+// DeviceLocation is a device placed on the map, with the network and organization it belongs to
+type DeviceLocation struct {
+	Serial         string  `json:"serial"`
+	Name           string  `json:"name,omitempty"`
+	Model          string  `json:"model"`
+	ProductType    string  `json:"productType,omitempty"`
+	Address        string  `json:"address,omitempty"`
+	Lat            float64 `json:"lat"`
+	Lng            float64 `json:"lng"`
+	NetworkID      string  `json:"network_id" xml:"NetworkID" csv:"network_id"`
+	NetworkName    string  `json:"network_name" xml:"NetworkName" csv:"network_name"`
+	Organization   string  `json:"organization" xml:"Organization" csv:"organization"`
+	OrganizationID string  `json:"organization_id" xml:"OrganizationID" csv:"organization_id"`
+}
+
+// HasLocation reports whether a device has been placed on the map. Unplaced devices report 0, 0.
+func (d Device) HasLocation() bool {
+	return d.Lat != 0 || d.Lng != 0
+}
+
+// GetDeviceLocations lists the devices with coordinates in one network, or in every network of an
+// organization when networkIdentifier is empty, grouped by network name. Devices that have not
+// been placed on the map are skipped.
+func (c *Client) GetDeviceLocations(organizationID, networkIdentifier string) ([]DeviceLocation, error) {
+	networks, err := c.getOrganizationNetworks(organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get networks for organization %s: %w", organizationID, err)
+	}
+	if networkIdentifier != "" {
+		networkID, err := c.ResolveNetworkID(organizationID, networkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		networks = slices.DeleteFunc(slices.Clone(networks), func(network Network) bool { return network.ID != networkID })
 	}
+	sort.SliceStable(networks, func(i, j int) bool { return networks[i].Name < networks[j].Name })
 
-	statusLower := strings.ToLower(status)
+	locations := make([]DeviceLocation, 0)
+	for _, network := range networks {
+		devices, err := c.getNetworkDevices(network.ID)
+		if err != nil {
+			if networkIdentifier != "" {
+				return nil, fmt.Errorf("failed to get devices for network %s: %w", network.ID, err)
+			}
+			slog.Warn("Failed to get devices for network", "network_id", network.ID, "network_name", network.Name, "error", err)
+			continue
+		}
+		for _, device := range devices {
+			if !device.HasLocation() {
+				continue
+			}
+			locations = append(locations, DeviceLocation{
+				Serial:         device.Serial,
+				Name:           device.Name,
+				Model:          device.Model,
+				ProductType:    device.ProductType,
+				Address:        device.Address,
+				Lat:            device.Lat,
+				Lng:            device.Lng,
+				NetworkID:      network.ID,
+				NetworkName:    network.Name,
+				OrganizationID: organizationID,
+			})
+		}
+	}
+
+	slog.Info("Retrieved device locations", "organization_id", organizationID, "device_count", len(locations))
+	return locations, nil
+}
+
+// IsDeviceDown reports whether a device status counts as down under DefaultDownStatuses, for use in output predicates
+func IsDeviceDown(status string) bool {
+	return isDeviceDown(status, DefaultDownStatuses)
+}
+
+// IsDeviceAlerting reports whether a device status counts as alerting, for use in output predicates
+func IsDeviceAlerting(status string) bool {
+	return isDeviceAlerting(status)
+}
+
+// DeviceFilter selects devices by model, product type, and tag. Empty fields match everything,
+// set fields are combined with AND semantics, and all comparisons are case-insensitive.
+type DeviceFilter struct {
+	Models       []string // Model prefixes, or glob patterns if they contain wildcards (e.g. MR*); any may match
+	ProductTypes []string // appliance, switch, wireless, camera, sensor, cellularGateway; any may match
+	Tag          string   // Device must carry this tag
+}
+
+// IsEmpty reports whether no filters are set
+func (f DeviceFilter) IsEmpty() bool {
+	return len(f.Models) == 0 && len(f.ProductTypes) == 0 && f.Tag == ""
+}
+
+// Matches reports whether a device satisfies every set filter
+func (f DeviceFilter) Matches(device Device) bool {
+	if len(f.Models) > 0 && !matchesModel(device.Model, f.Models) {
+		return false
+	}
+
+	if len(f.ProductTypes) > 0 && !matchesAny(device.ProductType, f.ProductTypes) {
+		return false
+	}
+
+	if f.Tag != "" {
+		tagged := false
+		for _, tag := range device.Tags {
+			if strings.EqualFold(tag, f.Tag) {
+				tagged = true
+				break
+			}
+		}
+		if !tagged {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchesAny reports whether value equals any of the candidates, ignoring case
+func matchesAny(value string, candidates []string) bool {
+	for _, candidate := range candidates {
+		if strings.EqualFold(value, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesModel reports whether model starts with, or matches the glob of, any of the patterns
+func matchesModel(model string, patterns []string) bool {
+	model = strings.ToLower(model)
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		if strings.ContainsAny(pattern, "*?[") {
+			if ok, _ := path.Match(pattern, model); ok {
+				return true
+			}
+		} else if strings.HasPrefix(model, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply returns the devices that satisfy the filter
+func (f DeviceFilter) Apply(devices []Device) []Device {
+	if f.IsEmpty() {
+		return devices
+	}
+
+	filtered := make([]Device, 0, len(devices))
+	for _, device := range devices {
+		if f.Matches(device) {
+			filtered = append(filtered, device)
+		}
+	}
+	return filtered
+}
+
+// LastReportedTime parses LastReportedAt, returning false if it is empty or malformed
+func (d Device) LastReportedTime() (time.Time, bool) {
+	if d.LastReportedAt == "" {
+		return time.Time{}, false
+	}
+
+	lastReported, err := time.Parse(time.RFC3339, d.LastReportedAt)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return lastReported, true
+}
+
+// UnknownDownDuration is the down duration reported for devices without a usable last reported time
+const UnknownDownDuration = "unknown"
+
+// DownLonger reports whether device a has been down longer than device b, judged by how long ago
+// each last reported. Devices with an unknown down duration order after those with a known one.
+func DownLonger(a, b Device) bool {
+	aReported, aOK := a.LastReportedTime()
+	bReported, bOK := b.LastReportedTime()
+	if !aOK || !bOK {
+		return aOK && !bOK
+	}
+	return aReported.Before(bReported)
+}
+
+// formatDownDuration renders a down duration to minute precision, e.g. "26h5m"
+func formatDownDuration(d time.Duration) string {
+	if d < time.Minute {
+		return "<1m"
+	}
+	d = d.Truncate(time.Minute)
+	return strings.TrimSuffix(d.String(), "0s")
+}
+
+// DefaultDownStatuses are the device statuses treated as down unless overridden with -down-statuses
+var DefaultDownStatuses = []string{
+	"offline",
+	"alerting",
+	"dormant",
+	"down",
+	"unreachable",
+	"disconnected",
+}
+
+// isDeviceDown determines if a device is considered down based on its status and the given down statuses
+func isDeviceDown(status string, downStatuses []string) bool {
 	for _, downStatus := range downStatuses {
-		if statusLower == downStatus {
+		if strings.EqualFold(status, downStatus) {
 			return true
 		}
 	}
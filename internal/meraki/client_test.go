@@ -1,10 +1,20 @@
 package meraki
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"regexp"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"meraki-info/internal/version"
 )
 
 func TestNewClient(t *testing.T) {
@@ -42,6 +52,90 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestNewClientWithBaseURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		baseURL     string
+		expectedURL string
+		shouldErr   bool
+	}{
+		{
+			name:        "empty base URL uses default",
+			baseURL:     "",
+			expectedURL: DefaultBaseURL,
+		},
+		{
+			name:        "canada cloud",
+			baseURL:     "https://api.meraki.ca/api/v1",
+			expectedURL: "https://api.meraki.ca/api/v1",
+		},
+		{
+			name:        "trailing slashes are trimmed",
+			baseURL:     "https://api.gov-meraki.com/api/v1//",
+			expectedURL: "https://api.gov-meraki.com/api/v1",
+		},
+		{
+			name:      "http scheme is rejected",
+			baseURL:   "http://api.meraki.cn/api/v1",
+			shouldErr: true,
+		},
+		{
+			name:      "relative URL is rejected",
+			baseURL:   "/api/v1",
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClientWithBaseURL("test-api-key", tt.baseURL)
+
+			if tt.shouldErr {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			if client.baseURL != tt.expectedURL {
+				t.Errorf("Expected base URL '%s', got '%s'", tt.expectedURL, client.baseURL)
+			}
+		})
+	}
+}
+
+func TestClient_BaseURLOverride(t *testing.T) {
+	var requestedPath string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPath = r.URL.Path
+		if r.Header.Get("X-Cisco-Meraki-API-Key") != "test-api-key" {
+			t.Error("Expected API key header not found")
+		}
+		if r.Header.Get("User-Agent") != version.UserAgent() {
+			t.Errorf("Unexpected User-Agent: %s", r.Header.Get("User-Agent"))
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithBaseURL("test-api-key", server.URL+"/api/v1/")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client.httpClient = server.Client()
+
+	if _, err := client.GetOrganizations(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if requestedPath != "/api/v1/organizations" {
+		t.Errorf("Expected request to /api/v1/organizations, got %s", requestedPath)
+	}
+}
+
 func TestClient_makeRequest(t *testing.T) {
 	// Create a test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -126,58 +220,23 @@ func TestClient_getOrganizationNetworks(t *testing.T) {
 	}
 }
 
-func TestClient_getNetworkRoutes(t *testing.T) {
-	// Create a test server
+func TestClient_GetOrganizationNetworksByTags(t *testing.T) {
+	wantFilterType := "withAnyTags"
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		switch r.URL.Path {
-		case "/networks/net123/appliance/staticRoutes":
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`[
-				{
-					"id": "route1",
-					"name": "Test Route",
-					"subnet": "192.168.1.0/24",
-					"gatewayIp": "192.168.1.1",
-					"gatewayVlanId": 100,
-					"enabled": true
-				}
-			]`))
-		case "/networks/net123/appliance/vpn/siteToSiteVpn":
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{
-				"subnets": [
-					{
-						"localSubnet": "10.0.0.0/8",
-						"useVpn": true
-					}
-				]
-			}`))
-		case "/networks/net123/appliance/vlans":
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`[
-				{
-					"id": 1,
-					"name": "Default",
-					"applianceIp": "172.16.1.1",
-					"subnet": "172.16.1.0/24"
-				}
-			]`))
-		case "/networks/net123/switch/routing/interfaces":
-			// Return empty array for switch routing interfaces (no switch Layer 3 interfaces)
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`[]`))
-		case "/networks/net123/switch/routing/staticRoutes":
-			// Return empty array for switch static routes
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`[]`))
-		case "/networks/net123/switch/stacks":
-			// Return empty array for switch stacks
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`[]`))
-		default:
-			t.Errorf("Unexpected path: %s", r.URL.Path)
-			w.WriteHeader(http.StatusNotFound)
+		if r.URL.Path != "/organizations/org123/networks" {
+			t.Errorf("Expected path /organizations/org123/networks, got %s", r.URL.Path)
+		}
+		query := r.URL.Query()
+		if query.Get("tagsFilterType") != wantFilterType {
+			t.Errorf("Expected tagsFilterType %s, got %q", wantFilterType, query.Get("tagsFilterType"))
 		}
+		if tags := strings.Join(query["tags[]"], ","); tags != "production,branch" {
+			t.Errorf("Expected tags[] production,branch, got %q", tags)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[{"id": "net1", "name": "Network 1", "tags": ["production"], "enrollmentString": "branch-1",
+			"notes": "Main branch", "url": "https://n1.meraki.com/Network-1/n/abc/manage/usage/list"}]`))
 	}))
 	defer server.Close()
 
@@ -187,58 +246,107 @@ func TestClient_getNetworkRoutes(t *testing.T) {
 		apiKey:     "test-api-key",
 	}
 
-	routes, err := client.getNetworkRoutes("net123")
+	networks, err := client.GetOrganizationNetworksByTags("org123", []string{"production", "branch"}, false)
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
+	if len(networks) != 1 || networks[0].ID != "net1" {
+		t.Errorf("Expected the tagged network, got %+v", networks)
+	}
+	if networks[0].EnrollmentString != "branch-1" || networks[0].Notes != "Main branch" || !strings.HasPrefix(networks[0].URL, "https://n1.meraki.com/") {
+		t.Errorf("Expected enrollment string, notes and URL to be decoded, got %+v", networks[0])
+	}
 
-	if len(routes) != 3 {
-		t.Errorf("Expected 3 routes, got %d", len(routes))
+	// Organization-wide listings use the tag filter once network tags are set
+	client.SetNetworkTags([]string{"production", "branch"}, false)
+	if _, err := client.getSelectedNetworks("org123"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
 	}
 
-	// Check static route
-	if routes[0].ID != "route1" {
-		t.Errorf("Expected route ID 'route1', got '%s'", routes[0].ID)
+	// -network-tag-match all asks the API for networks carrying every tag
+	wantFilterType = "withAllTags"
+	client.SetNetworkTags([]string{"production", "branch"}, true)
+	if _, err := client.getSelectedNetworks("org123"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
 	}
+}
 
-	if routes[0].Subnet != "192.168.1.0/24" {
-		t.Errorf("Expected subnet '192.168.1.0/24', got '%s'", routes[0].Subnet)
+func TestClient_CachesOrganizationsAndNetworks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		switch r.URL.Path {
+		case "/organizations":
+			w.Write([]byte(`[{"id": "org1", "name": "Org 1"}]`))
+		case "/organizations/org1/networks", "/organizations/org2/networks":
+			w.Write([]byte(`[{"id": "net1", "name": "Network 1"}]`))
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-api-key",
 	}
 
-	// Check VPN route
-	if routes[1].Subnet != "10.0.0.0/8" {
-		t.Errorf("Expected VPN subnet '10.0.0.0/8', got '%s'", routes[1].Subnet)
+	for i := 0; i < 3; i++ {
+		if orgs, err := client.GetOrganizations(); err != nil || len(orgs) != 1 {
+			t.Fatalf("Expected one organization, got %+v, %v", orgs, err)
+		}
+		if networks, err := client.GetOrganizationNetworks("org1"); err != nil || len(networks) != 1 {
+			t.Fatalf("Expected one network, got %+v, %v", networks, err)
+		}
+	}
+	if client.RequestCount() != 2 {
+		t.Errorf("Expected organizations and networks to be fetched once, got %d requests", client.RequestCount())
 	}
 
-	// Check VLAN route
-	if routes[2].Subnet != "172.16.1.0/24" {
-		t.Errorf("Expected VLAN subnet '172.16.1.0/24', got '%s'", routes[2].Subnet)
+	// Each organization's networks are cached separately
+	if _, err := client.GetOrganizationNetworks("org2"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if client.RequestCount() != 3 {
+		t.Errorf("Expected another organization's networks to be fetched, got %d requests", client.RequestCount())
+	}
+
+	// Callers may modify the returned slice without affecting the cache
+	networks, _ := client.GetOrganizationNetworks("org1")
+	networks[0].Name = "Changed"
+	if networks, _ := client.GetOrganizationNetworks("org1"); networks[0].Name != "Network 1" {
+		t.Errorf("Expected the cached network to be unchanged, got %q", networks[0].Name)
+	}
+
+	client.InvalidateCache()
+	if _, err := client.GetOrganizations(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := client.GetOrganizationNetworks("org1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if client.RequestCount() != 5 {
+		t.Errorf("Expected InvalidateCache to fetch organizations and networks again, got %d requests", client.RequestCount())
 	}
 }
 
-func TestClient_GetOrganizations(t *testing.T) {
-	// Create a test server
+func TestClient_NetworksFetchedOncePerOrganization(t *testing.T) {
+	var mu sync.Mutex
+	hits := make(map[string]int)
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path != "/organizations" {
-			t.Errorf("Expected path /organizations, got %s", r.URL.Path)
+		mu.Lock()
+		hits[r.URL.Path]++
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/organizations":
+			w.Write([]byte(`[{"id": "org1", "name": "Org 1"}, {"id": "org2", "name": "Org 2"}]`))
+		case "/organizations/org1/networks":
+			w.Write([]byte(`[{"id": "N_1", "name": "Branch 1"}, {"id": "N_2", "name": "Branch 2"}]`))
+		case "/organizations/org2/networks":
+			w.Write([]byte(`[{"id": "N_3", "name": "Branch 3"}]`))
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
 		}
-
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`[
-			{
-				"id": "123456",
-				"name": "Test Organization",
-				"url": "https://dashboard.meraki.com/test",
-				"api": {"enabled": true},
-				"licensing": {"model": "co-term"},
-				"cloud": {
-					"region": {
-						"name": "North America",
-						"host": {"name": "United States"}
-					}
-				}
-			}
-		]`))
 	}))
 	defer server.Close()
 
@@ -248,53 +356,81 @@ func TestClient_GetOrganizations(t *testing.T) {
 		apiKey:     "test-api-key",
 	}
 
-	orgs, err := client.GetOrganizations()
-	if err != nil {
-		t.Fatalf("Unexpected error: %v", err)
+	// An -all run resolves networks by name, lists every organization's networks and selects
+	// them again per command; each lookup after the first is answered from the cache
+	lookups := func() {
+		orgs, err := client.GetOrganizations()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := client.ResolveNetworkID("org1", "branch 1"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, org := range orgs {
+			if _, err := client.GetOrganizationNetworks(org.ID); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if _, err := client.getSelectedNetworks(org.ID); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
 	}
+	lookups()
+	lookups()
 
-	if len(orgs) != 1 {
-		t.Errorf("Expected 1 organization, got %d", len(orgs))
+	for _, path := range []string{"/organizations", "/organizations/org1/networks", "/organizations/org2/networks"} {
+		if hits[path] != 1 {
+			t.Errorf("Expected %s to be fetched once, got %d", path, hits[path])
+		}
 	}
 
-	if orgs[0].ID != "123456" {
-		t.Errorf("Expected organization ID '123456', got '%s'", orgs[0].ID)
+	// Tag-filtered lookups are cached separately from the unfiltered list
+	client.SetNetworkTags([]string{"branch"}, false)
+	for i := 0; i < 2; i++ {
+		if _, err := client.getSelectedNetworks("org1"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
 	}
-
-	if orgs[0].Name != "Test Organization" {
-		t.Errorf("Expected organization name 'Test Organization', got '%s'", orgs[0].Name)
+	if hits["/organizations/org1/networks"] != 2 {
+		t.Errorf("Expected the tag-filtered networks to be fetched once, got %d network requests", hits["/organizations/org1/networks"]-1)
 	}
 
-	if !orgs[0].API.Enabled {
-		t.Error("Expected API to be enabled")
+	client.InvalidateCache()
+	client.SetNetworkTags(nil, false)
+	lookups()
+	for _, path := range []string{"/organizations", "/organizations/org2/networks"} {
+		if hits[path] != 2 {
+			t.Errorf("Expected %s to be fetched again after InvalidateCache, got %d requests", path, hits[path])
+		}
 	}
 }
 
-func TestClient_ResolveNetworkID(t *testing.T) {
-	// Create a test server
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path != "/organizations/org123/networks" {
-			t.Errorf("Expected path /organizations/org123/networks, got %s", r.URL.Path)
-		}
+func TestClient_GetAPIUsage(t *testing.T) {
+	originalPerPage := apiRequestsPerPage
+	apiRequestsPerPage = 2
+	defer func() { apiRequestsPerPage = originalPerPage }()
 
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`[
-			{
-				"id": "net1",
-				"name": "Main Office",
-				"productTypes": ["appliance", "wireless"]
-			},
-			{
-				"id": "net2", 
-				"name": "Branch Office",
-				"productTypes": ["wireless"]
-			},
-			{
-				"id": "net3",
-				"name": "Main Office",
-				"productTypes": ["camera"]
+	pages := map[string]string{
+		"":  `[{"adminId": "a1", "method": "GET", "path": "/api/v1/organizations", "userAgent": "script/1.0", "responseCode": 429}, {"adminId": "a1", "method": "GET", "path": "/api/v1/organizations", "userAgent": "script/1.0", "responseCode": 200}]`,
+		"2": `[{"adminId": "a2", "method": "PUT", "path": "/api/v1/networks/N_1", "userAgent": "meraki-info", "responseCode": 200}]`,
+	}
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("timespan") != "3600" {
+			t.Errorf("Expected timespan 3600, got %q", r.URL.Query().Get("timespan"))
+		}
+		switch r.URL.Path {
+		case "/organizations/org1/apiRequests/overview":
+			w.Write([]byte(`{"responseCodeCounts": {"200": 2, "429": 1, "500": 0}}`))
+		case "/organizations/org1/apiRequests":
+			startingAfter := r.URL.Query().Get("startingAfter")
+			if startingAfter == "" {
+				w.Header().Set("Link", fmt.Sprintf(`<%s/organizations/org1/apiRequests?perPage=2&startingAfter=1>; rel=first, <%s/organizations/org1/apiRequests?perPage=2&startingAfter=2>; rel=next`, server.URL, server.URL))
 			}
-		]`))
+			w.Write([]byte(pages[startingAfter]))
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
 	}))
 	defer server.Close()
 
@@ -304,180 +440,2683 @@ func TestClient_ResolveNetworkID(t *testing.T) {
 		apiKey:     "test-api-key",
 	}
 
-	tests := []struct {
-		name              string
-		networkIdentifier string
-		expectedID        string
-		shouldError       bool
-		errorContains     string
-	}{
-		{
-			name:              "resolve by existing ID",
-			networkIdentifier: "net1",
-			expectedID:        "net1",
-			shouldError:       false,
-		},
-		{
-			name:              "resolve by unique name",
-			networkIdentifier: "Branch Office",
-			expectedID:        "net2",
-			shouldError:       false,
-		},
-		{
-			name:              "resolve by unique name case insensitive",
-			networkIdentifier: "BRANCH OFFICE",
-			expectedID:        "net2",
-			shouldError:       false,
-		},
-		{
-			name:              "resolve by duplicate name should error",
-			networkIdentifier: "Main Office",
-			shouldError:       true,
-			errorContains:     "multiple networks found",
-		},
-		{
-			name:              "resolve by duplicate name case insensitive should error",
-			networkIdentifier: "main office",
-			shouldError:       true,
-			errorContains:     "multiple networks found",
-		},
-		{
+	t.Run("overview only", func(t *testing.T) {
+		usage, err := client.GetAPIUsage("org1", time.Hour, false)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if usage.TotalRequests != 3 || usage.RateLimited != 1 || usage.TimespanSeconds != 3600 {
+			t.Errorf("Unexpected usage totals: %+v", usage)
+		}
+		if len(usage.ResponseCodes) != 2 || usage.ResponseCodes[0].Key != "200" || usage.ResponseCodes[1].Key != "429" {
+			t.Errorf("Expected non-zero response codes in order, got %+v", usage.ResponseCodes)
+		}
+		since, _ := time.Parse(time.RFC3339, usage.Since)
+		until, _ := time.Parse(time.RFC3339, usage.Until)
+		if until.Sub(since) != time.Hour {
+			t.Errorf("Expected since and until an hour apart, got %s and %s", usage.Since, usage.Until)
+		}
+		if usage.Requests != nil || usage.TopAdmins != nil {
+			t.Errorf("Expected no request log without detailed, got %+v", usage)
+		}
+	})
+
+	t.Run("detailed", func(t *testing.T) {
+		before := client.RequestCount()
+		usage, err := client.GetAPIUsage("org1", time.Hour, true)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(usage.Requests) != 3 {
+			t.Fatalf("Expected requests from both pages, got %d", len(usage.Requests))
+		}
+		if client.RequestCount()-before != 3 {
+			t.Errorf("Expected the overview and two request pages, got %d requests", client.RequestCount()-before)
+		}
+		if len(usage.TopAdmins) != 2 || usage.TopAdmins[0] != (UsageCount{Key: "a1", Count: 2}) {
+			t.Errorf("Unexpected top admins: %+v", usage.TopAdmins)
+		}
+		if len(usage.TopUserAgents) != 2 || usage.TopUserAgents[0].Key != "script/1.0" {
+			t.Errorf("Unexpected top user agents: %+v", usage.TopUserAgents)
+		}
+	})
+}
+
+func TestClient_GetNetworkClients(t *testing.T) {
+	originalPerPage := clientsPerPage
+	clientsPerPage = 2
+	defer func() { clientsPerPage = originalPerPage }()
+
+	pages := map[string]string{
+		"": `[
+			{"id": "k74272e", "mac": "22:33:44:55:66:77", "description": "Miles's phone", "ip": "10.0.0.20", "ip6": "2001:db8::20",
+			 "vlan": "100", "status": "Online", "lastSeen": "2024-05-01T10:00:00Z", "manufacturer": "Apple", "os": "iOS",
+			 "user": "miles", "ssid": "Corp", "wirelessCapabilities": "802.11ac - 2.4 and 5 GHz"},
+			{"id": "k11111a", "mac": "00:11:22:33:44:55", "ip": "10.0.1.5", "vlan": 200, "status": "Offline", "lastSeen": 1714557600}
+		]`,
+		"k11111a": `[{"id": "k22222b", "mac": "66:77:88:99:aa:bb", "vlan": null}]`,
+	}
+	var timespans []string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/networks/N_1/clients" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		timespans = append(timespans, r.URL.Query().Get("timespan"))
+		startingAfter := r.URL.Query().Get("startingAfter")
+		if startingAfter == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/networks/N_1/clients?perPage=2&startingAfter=k11111a>; rel=next`, server.URL))
+		}
+		w.Write([]byte(pages[startingAfter]))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-api-key",
+	}
+
+	clients, err := client.GetNetworkClients("N_1", 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Join(timespans, ",") != "86400,86400" {
+		t.Errorf("Expected the default timespan on both pages, got %v", timespans)
+	}
+	if len(clients) != 3 {
+		t.Fatalf("Expected 3 clients across both pages, got %+v", clients)
+	}
+	expected := NetworkClient{
+		ID: "k74272e", MAC: "22:33:44:55:66:77", Description: "Miles's phone", IP: "10.0.0.20", IP6: "2001:db8::20",
+		VLAN: "100", Status: "Online", LastSeen: "2024-05-01T10:00:00Z", Manufacturer: "Apple", OS: "iOS",
+		User: "miles", SSID: "Corp", WirelessCapabilities: "802.11ac - 2.4 and 5 GHz",
+	}
+	if clients[0] != expected {
+		t.Errorf("Expected %+v, got %+v", expected, clients[0])
+	}
+	if clients[1].VLAN != "200" || clients[1].LastSeen != "1714557600" {
+		t.Errorf("Expected numeric VLAN and lastSeen as strings, got %+v", clients[1])
+	}
+	if clients[2].ID != "k22222b" || clients[2].VLAN != "" {
+		t.Errorf("Expected the second page's client without a VLAN, got %+v", clients[2])
+	}
+
+	timespans = nil
+	if _, err := client.GetNetworkClients("N_1", 3600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(timespans) == 0 || timespans[0] != "3600" {
+		t.Errorf("Expected timespan 3600, got %v", timespans)
+	}
+}
+
+func TestClient_GetOrganizationInventoryDevices(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/organizations/org1/inventory/devices" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("perPage") != "1000" {
+			t.Errorf("Expected perPage on every page, got %q", r.URL.RawQuery)
+		}
+		switch r.URL.Query().Get("startingAfter") {
+		case "":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/organizations/org1/inventory/devices?perPage=1000&startingAfter=Q2AA-0001>; rel=next`, server.URL))
+			w.Write([]byte(`[{"serial": "Q2AA-0001", "mac": "00:18:0a:00:00:01", "model": "MX68", "networkId": "N_1",
+				"orderNumber": "4C1234567", "claimedAt": "2024-03-01T12:00:00Z", "licenseExpirationDate": "2027-03-01T00:00:00Z",
+				"tags": ["hq"], "productType": "appliance"}]`))
+		case "Q2AA-0001":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/organizations/org1/inventory/devices?perPage=1000>; rel=first`, server.URL))
+			w.Write([]byte(`[{"serial": "Q2AA-0002", "model": "MR46", "networkId": null, "productType": "wireless"}]`))
+		default:
+			t.Errorf("Unexpected page %s", r.URL.RawQuery)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-api-key",
+	}
+
+	devices, err := client.GetOrganizationInventoryDevices("org1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(devices) != 2 {
+		t.Fatalf("Expected the devices of both pages, got %+v", devices)
+	}
+	expected := InventoryDevice{Serial: "Q2AA-0001", MAC: "00:18:0a:00:00:01", Model: "MX68", NetworkID: "N_1",
+		OrderNumber: "4C1234567", ClaimedAt: "2024-03-01T12:00:00Z", LicenseExpirationDate: "2027-03-01T00:00:00Z",
+		Tags: []string{"hq"}, ProductType: "appliance"}
+	if !reflect.DeepEqual(devices[0], expected) {
+		t.Errorf("Expected %+v, got %+v", expected, devices[0])
+	}
+	if devices[0].Unassigned() || devices[1].Serial != "Q2AA-0002" || !devices[1].Unassigned() {
+		t.Errorf("Expected only the second device to be unassigned, got %+v", devices)
+	}
+}
+
+func TestClient_GetDeviceDetails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/devices/Q2AA-0001":
+			w.Write([]byte(`{"serial": "Q2AA-0001", "name": "Branch MX", "model": "MX68", "mac": "00:18:0a:00:00:01",
+				"lanIp": "10.0.0.1", "firmware": "wired-18-211", "tags": ["branch"], "address": "1 Main St",
+				"lat": 37.4, "lng": -122.1, "networkId": "N_1", "productType": "appliance"}`))
+		case "/devices/Q2AA-0001/managementInterface":
+			w.Write([]byte(`{"wan1": {"usingStaticIp": true, "staticIp": "203.0.113.10"}, "wan2": {"usingStaticIp": false}}`))
+		case "/networks/N_1":
+			w.Write([]byte(`{"id": "N_1", "name": "Branch 1", "organizationId": "org1"}`))
+		case "/organizations/org1/devices/statuses":
+			if r.URL.Query().Get("serials[]") != "Q2AA-0001" {
+				t.Errorf("Expected the statuses of the device only, got %q", r.URL.RawQuery)
+			}
+			w.Write([]byte(`[{"serial": "Q2AA-0001", "status": "online", "lastReportedAt": "2026-10-16T08:00:00Z",
+				"publicIp": "203.0.113.10", "wan1Ip": "192.0.2.2", "wan2Ip": "198.51.100.7"}]`))
+		case "/devices/Q2AA-9999":
+			w.WriteHeader(http.StatusNotFound)
+		case "/devices/Q2AA-0403":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-api-key",
+	}
+
+	details, err := client.GetDeviceDetails("Q2AA-0001")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// The static WAN 1 address takes precedence; WAN 2 uses DHCP, so its address comes from the status
+	expected := DeviceDetails{Serial: "Q2AA-0001", Name: "Branch MX", Model: "MX68", MAC: "00:18:0a:00:00:01",
+		ProductType: "appliance", Firmware: "wired-18-211", LanIP: "10.0.0.1", WAN1IP: "203.0.113.10", WAN2IP: "198.51.100.7",
+		PublicIP: "203.0.113.10", Tags: []string{"branch"}, Address: "1 Main St", Lat: 37.4, Lng: -122.1, Status: "online",
+		LastReportedAt: "2026-10-16T08:00:00Z", NetworkID: "N_1", NetworkName: "Branch 1", OrganizationID: "org1"}
+	if !reflect.DeepEqual(details, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, details)
+	}
+
+	if _, err := client.GetDeviceDetails("Q2AA-9999"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for an unknown serial, got: %v", err)
+	}
+	if _, err := client.GetDeviceDetails("Q2AA-0403"); !errors.Is(err, ErrForbidden) || errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrForbidden for a device the API key cannot read, got: %v", err)
+	}
+}
+
+func TestClient_GetSwitchPortStatuses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/organizations/org1/networks":
+			w.Write([]byte(`[{"id": "N_1", "name": "Campus", "productTypes": ["switch", "wireless"]}, {"id": "N_2", "name": "Cameras", "productTypes": ["camera"]}]`))
+		case "/networks/N_1/devices":
+			w.Write([]byte(`[{"serial": "Q2SW-0001", "name": "Core", "model": "MS250-48"}, {"serial": "Q2AP-0001", "model": "MR46"}]`))
+		case "/devices/Q2SW-0001/switch/ports/statuses":
+			w.Write([]byte(`[
+				{"portId": "1", "enabled": true, "status": "Connected", "isUplink": true, "speed": "10 Gbps", "duplex": "full", "errors": [], "warnings": [],
+					"clientCount": 12, "powerUsageInWh": 0, "usageInKb": {"total": 3000, "sent": 1000, "recv": 2000}},
+				{"portId": "2", "enabled": true, "status": "Connected", "speed": "1 Gbps", "duplex": "full", "errors": ["CRC errors"], "warnings": [],
+					"clientCount": 1, "powerUsageInWh": 55.9},
+				{"portId": "3", "enabled": true, "status": "Disconnected", "errors": ["Port disconnected"]}]`))
+		case "/devices/Q2SW-0001/switch/ports":
+			w.Write([]byte(`[{"portId": "1", "vlan": 1}, {"portId": "2", "vlan": 20, "voiceVlan": 30}, {"portId": "3", "vlan": 20, "voiceVlan": null}]`))
+		case "/devices/Q2SW-0001/switch/ports/statuses/packets":
+			w.Write([]byte(`[
+				{"portId": "1", "packets": [{"desc": "Total", "total": 1000, "ratePerSec": {"total": 10}}, {"desc": "CRC align errors", "total": 0, "ratePerSec": {"total": 0}}]},
+				{"portId": "2", "packets": [{"desc": "CRC align errors", "total": 42, "ratePerSec": {"total": 0.5}}, {"desc": "Collisions", "total": 3}]}]`))
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-api-key",
+	}
+
+	ports, err := client.GetSwitchPortStatuses("org1", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(ports) != 3 {
+		t.Fatalf("Expected the 3 ports of the switch, got %+v", ports)
+	}
+	if ports[0].Serial != "Q2SW-0001" || ports[0].SwitchName != "Core" || ports[0].NetworkName != "Campus" || !ports[0].IsUplink || ports[0].Errored() {
+		t.Errorf("Expected a healthy uplink on the Core switch, got %+v", ports[0])
+	}
+	if ports[1].CRCAlignErrors != 42 || ports[1].Collisions != 3 || ports[1].RecentErrorsPerSecond != 0.5 {
+		t.Errorf("Expected the error counters of port 2, got %+v", ports[1])
+	}
+	if ports[0].ClientCount != 12 || ports[0].UsageInKb.Total != 3000 || ports[0].UsageInKb.Recv != 2000 || ports[0].VLAN != 1 {
+		t.Errorf("Expected the clients, usage and VLAN of port 1, got %+v", ports[0])
+	}
+	if ports[1].PowerUsageInWh != 55.9 || ports[1].VLAN != 20 || ports[1].VoiceVLAN != 30 || ports[2].VoiceVLAN != 0 {
+		t.Errorf("Expected the PoE usage and VLANs of ports 2 and 3, got %+v, %+v", ports[1], ports[2])
+	}
+
+	errored := FilterErroredPorts(ports)
+	if len(errored) != 2 || errored[0].PortID != "2" || errored[1].PortID != "3" {
+		t.Errorf("Expected the port with CRC errors and the disconnected port, got %+v", errored)
+	}
+}
+
+func TestClient_GetNetworkSwitchPortStatuses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/networks/N_1/devices":
+			w.Write([]byte(`[{"serial": "Q2SW-0001", "name": "Core", "model": "MS250-48"}, {"serial": "Q2AP-0001", "model": "MR46"}]`))
+		case "/devices/Q2SW-0001/switch/ports/statuses":
+			w.Write([]byte(`[{"portId": "1", "enabled": true, "status": "Connected", "clientCount": 4}, {"portId": "2", "enabled": false, "status": "Disabled"}]`))
+		case "/devices/Q2SW-0001/switch/ports":
+			w.Write([]byte(`[{"portId": "1", "vlan": 10}]`))
+		case "/devices/Q2SW-0001/switch/ports/statuses/packets":
+			w.Write([]byte(`[]`))
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-api-key",
+	}
+
+	ports, err := client.GetNetworkSwitchPortStatuses("N_1", "q2sw-0001")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(ports) != 2 || ports[0].Serial != "Q2SW-0001" || ports[0].SwitchName != "Core" || ports[0].ClientCount != 4 || ports[0].VLAN != 10 {
+		t.Errorf("Expected the ports of the Core switch, got %+v", ports)
+	}
+
+	if _, err := client.GetNetworkSwitchPortStatuses("N_1", "Q2AP-0001"); err == nil || !strings.Contains(err.Error(), "not a switch") {
+		t.Errorf("Expected an error for an access point, got: %v", err)
+	}
+	if _, err := client.GetNetworkSwitchPortStatuses("N_1", "Q2SW-9999"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a switch outside the network, got: %v", err)
+	}
+}
+
+func TestClient_GetNetworkWirelessSSIDs(t *testing.T) {
+	// The API always returns 15 SSIDs, the unconfigured ones disabled with placeholder names
+	ssidsJSON := []string{`{"number": 0, "name": "Corp", "enabled": true, "authMode": "psk", "encryptionMode": "wpa",
+		"wpaEncryptionMode": "WPA2 only", "ipAssignmentMode": "Bridge mode", "defaultVlanId": 10, "visible": true,
+		"splashPage": "None", "bandSelection": "Dual band operation with Band Steering"}`}
+	for i := 1; i < 15; i++ {
+		ssidsJSON = append(ssidsJSON, fmt.Sprintf(`{"number": %d, "name": "Unconfigured SSID %d", "enabled": false, "authMode": "open", "visible": true}`, i, i+1))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/networks/N_wireless/wireless/ssids":
+			w.Write([]byte("[" + strings.Join(ssidsJSON, ",") + "]"))
+		case "/networks/N_wired/wireless/ssids":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errors": ["This endpoint only supports wireless networks"]}`))
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-api-key",
+	}
+
+	ssids, err := client.GetNetworkWirelessSSIDs("N_wireless")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(ssids) != 15 {
+		t.Fatalf("Expected 15 SSIDs, got %d", len(ssids))
+	}
+	expected := SSID{
+		Number: 0, Name: "Corp", Enabled: true, AuthMode: "psk", EncryptionMode: "wpa", WpaEncryptionMode: "WPA2 only",
+		IPAssignmentMode: "Bridge mode", DefaultVlanID: 10, Visible: true, SplashPage: "None",
+		BandSelection: "Dual band operation with Band Steering",
+	}
+	if ssids[0] != expected {
+		t.Errorf("Expected %+v, got %+v", expected, ssids[0])
+	}
+	if ssids[14].Number != 14 || ssids[14].Name != "Unconfigured SSID 15" || ssids[14].Enabled {
+		t.Errorf("Expected the last SSID to be unconfigured and disabled, got %+v", ssids[14])
+	}
+
+	ssids, err = client.GetNetworkWirelessSSIDs("N_wired")
+	if err != nil {
+		t.Fatalf("Expected no error for a network without wireless, got %v", err)
+	}
+	if len(ssids) != 0 {
+		t.Errorf("Expected no SSIDs for a network without wireless, got %+v", ssids)
+	}
+}
+
+func TestClient_getNetworkRoutes_Dedup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/networks/net123/appliance/staticRoutes":
+			// The static route has no name of its own, so it is labelled "Static Route 1"
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{"id": "route1", "subnet": "172.16.1.0/24", "gatewayIp": "172.16.1.1", "gatewayVlanId": 1, "enabled": false},
+				{"id": "route2", "name": "Lab", "subnet": "10.9.0.0/16", "gatewayIp": "172.16.1.254", "enabled": true}
+			]`))
+		case "/networks/net123/appliance/vlans":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": 1, "name": "Default", "applianceIp": "172.16.1.1", "subnet": "172.16.1.0/24"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-api-key",
+	}
+
+	routes, err := client.getNetworkRoutes("net123")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(routes) != 2 {
+		t.Fatalf("Expected the VLAN subnet and static route to be merged into one of 2 routes, got %+v", routes)
+	}
+	merged := routes[0]
+	if merged.ID != "route1" || merged.Name != "Static Route 1" || merged.GatewayVlan != 1 || !merged.Enabled {
+		t.Errorf("Expected the first source's route, enabled by the VLAN, got %+v", merged)
+	}
+	if routes[1].ID != "route2" {
+		t.Errorf("Expected the distinct static route to be kept, got %+v", routes[1])
+	}
+
+	client.SetKeepDuplicateRoutes(true)
+	if routes, err = client.getNetworkRoutes("net123"); err != nil || len(routes) != 3 {
+		t.Errorf("Expected all 3 raw routes with -no-dedup, got %d, %v", len(routes), err)
+	}
+}
+
+func TestClient_getNetworkRoutes_KeepEmptyRouteNames(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/networks/net123/appliance/staticRoutes":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{"id": "route1", "subnet": "172.16.1.0/24", "gatewayIp": "172.16.1.1", "enabled": true},
+				{"id": "route2", "name": "Lab", "subnet": "10.9.0.0/16", "gatewayIp": "172.16.1.254", "enabled": true}
+			]`))
+		case "/networks/net123/appliance/vpn/siteToSiteVpn":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"mode": "spoke", "subnets": [{"localSubnet": "192.168.10.0/24", "useVpn": true}]}`))
+		case "/networks/net123/appliance/vlans":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": 20, "name": "", "applianceIp": "10.20.0.1", "subnet": "10.20.0.0/24"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-api-key",
+	}
+
+	names := func() map[string]string {
+		routes, err := client.getNetworkRoutes("net123")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		byID := make(map[string]string)
+		for _, route := range routes {
+			byID[route.ID] = route.Name
+		}
+		return byID
+	}
+
+	synthetic := names()
+	if synthetic["route1"] != "Static Route 1" || synthetic["vpn-0"] != "VPN Route 1" || synthetic["vlan-20"] != "VLAN 20 - " {
+		t.Errorf("Expected synthetic names by default, got %v", synthetic)
+	}
+
+	client.SetKeepEmptyRouteNames(true)
+	raw := names()
+	if len(raw) != 4 {
+		t.Fatalf("Expected 4 routes, got %v", raw)
+	}
+	for _, id := range []string{"route1", "vpn-0", "vlan-20"} {
+		if name, ok := raw[id]; !ok || name != "" {
+			t.Errorf("Expected route %s to keep its empty name, got %q", id, name)
+		}
+	}
+	if raw["route2"] != "Lab" {
+		t.Errorf("Expected API names to be kept, got %q", raw["route2"])
+	}
+}
+
+func TestNormalizeRouteSubnets(t *testing.T) {
+	routes := []Route{
+		{ID: "static-1", Subnet: "192.168.1.5/24", GatewayIP: "192.168.1.1"},
+		{ID: "vlan-1", Subnet: "192.168.1.0/24", GatewayIP: "192.168.1.1"},
+		{ID: "v6", Subnet: "2001:db8::1/64"},
+		{ID: "bad", Subnet: "not-a-subnet"},
+	}
+
+	normalized := NormalizeRouteSubnets(routes)
+	expected := []string{"192.168.1.0/24", "192.168.1.0/24", "2001:db8::/64", "not-a-subnet"}
+	for i, route := range normalized {
+		if route.Subnet != expected[i] {
+			t.Errorf("Route %s: expected subnet %s, got %s", route.ID, expected[i], route.Subnet)
+		}
+	}
+	if routes[0].Subnet != "192.168.1.5/24" {
+		t.Errorf("Expected the input routes to be left unchanged, got %s", routes[0].Subnet)
+	}
+
+	// Once normalized, the host-bit-set static route merges with the VLAN subnet
+	if deduped := DeduplicateRoutes(normalized); len(deduped) != 3 {
+		t.Errorf("Expected the normalized duplicates to merge into 3 routes, got %+v", deduped)
+	}
+}
+
+func TestDeduplicateRoutes(t *testing.T) {
+	routes := []Route{
+		{ID: "vpn-0", Subnet: "10.0.0.0/24", GatewayIP: "10.0.0.1", VPNMode: "hub"},
+		{ID: "vlan-10", Name: "VLAN 10 - Users", Subnet: "10.0.0.0/24", GatewayIP: "10.0.0.1", Enabled: true},
+		{ID: "vlan-20", Name: "VLAN 20", Subnet: "10.0.0.0/24", GatewayIP: "10.0.0.2"},
+	}
+
+	deduped := DeduplicateRoutes(routes)
+	if len(deduped) != 2 {
+		t.Fatalf("Expected 2 routes, got %+v", deduped)
+	}
+	if deduped[0].ID != "vlan-10" || deduped[0].Name != "VLAN 10 - Users" || deduped[0].VPNMode != "hub" || !deduped[0].Enabled {
+		t.Errorf("Expected the named route with the VPN mode filled in, got %+v", deduped[0])
+	}
+	if deduped[1].ID != "vlan-20" {
+		t.Errorf("Expected the route with another gateway to be kept, got %+v", deduped[1])
+	}
+}
+
+func TestClient_getNetworkRoutes(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/networks/net123/appliance/staticRoutes":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{
+					"id": "route1",
+					"name": "Test Route",
+					"subnet": "192.168.1.0/24",
+					"gatewayIp": "192.168.1.1",
+					"gatewayVlanId": 100,
+					"enabled": true
+				}
+			]`))
+		case "/networks/net123/appliance/vpn/siteToSiteVpn":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{
+				"subnets": [
+					{
+						"localSubnet": "10.0.0.0/8",
+						"useVpn": true
+					}
+				]
+			}`))
+		case "/networks/net123/appliance/vlans":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{
+					"id": 1,
+					"name": "Default",
+					"applianceIp": "172.16.1.1",
+					"subnet": "172.16.1.0/24"
+				}
+			]`))
+		case "/networks/net123/switch/routing/interfaces":
+			// Return empty array for switch routing interfaces (no switch Layer 3 interfaces)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[]`))
+		case "/networks/net123/switch/routing/staticRoutes":
+			// Return empty array for switch static routes
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[]`))
+		case "/networks/net123/switch/stacks":
+			// Return empty array for switch stacks
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[]`))
+		default:
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-api-key",
+	}
+
+	routes, err := client.getNetworkRoutes("net123")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(routes) != 3 {
+		t.Errorf("Expected 3 routes, got %d", len(routes))
+	}
+
+	// Check static route
+	if routes[0].ID != "route1" {
+		t.Errorf("Expected route ID 'route1', got '%s'", routes[0].ID)
+	}
+
+	if routes[0].Subnet != "192.168.1.0/24" {
+		t.Errorf("Expected subnet '192.168.1.0/24', got '%s'", routes[0].Subnet)
+	}
+
+	// Check VPN route
+	if routes[1].Subnet != "10.0.0.0/8" {
+		t.Errorf("Expected VPN subnet '10.0.0.0/8', got '%s'", routes[1].Subnet)
+	}
+
+	// Check VLAN route
+	if routes[2].Subnet != "172.16.1.0/24" {
+		t.Errorf("Expected VLAN subnet '172.16.1.0/24', got '%s'", routes[2].Subnet)
+	}
+}
+
+func TestClient_getNetworkVPNRoutes_Mode(t *testing.T) {
+	// Create a test server returning a different VPN mode per network
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var mode string
+		switch r.URL.Path {
+		case "/networks/hubnet/appliance/vpn/siteToSiteVpn":
+			mode = "hub"
+		case "/networks/spokenet/appliance/vpn/siteToSiteVpn":
+			mode = "spoke"
+		case "/networks/nonenet/appliance/vpn/siteToSiteVpn":
+			mode = "none"
+		default:
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{
+			"mode": "` + mode + `",
+			"subnets": [
+				{"localSubnet": "10.1.0.0/16", "useVpn": true},
+				{"localSubnet": "10.2.0.0/16", "useVpn": false}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		filter        string
+		networkID     string
+		expectedCount int
+		expectedMode  string
+	}{
+		{name: "hub without filter", networkID: "hubnet", expectedCount: 1, expectedMode: "hub"},
+		{name: "spoke without filter", networkID: "spokenet", expectedCount: 1, expectedMode: "spoke"},
+		{name: "none without filter", networkID: "nonenet", expectedCount: 1, expectedMode: "none"},
+		{name: "hub filter keeps hub", filter: "hub", networkID: "hubnet", expectedCount: 1, expectedMode: "hub"},
+		{name: "hub filter skips spoke", filter: "hub", networkID: "spokenet", expectedCount: 0},
+		{name: "spoke filter is case insensitive", filter: "SPOKE", networkID: "spokenet", expectedCount: 1, expectedMode: "spoke"},
+		{name: "none filter skips hub", filter: "none", networkID: "hubnet", expectedCount: 0},
+		{name: "none filter keeps none", filter: "none", networkID: "nonenet", expectedCount: 1, expectedMode: "none"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &Client{
+				httpClient: &http.Client{},
+				baseURL:    server.URL,
+				apiKey:     "test-api-key",
+			}
+			client.SetVPNModeFilter(tt.filter)
+
+			routes, err := client.getNetworkVPNRoutes(tt.networkID)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(routes) != tt.expectedCount {
+				t.Fatalf("Expected %d routes, got %d", tt.expectedCount, len(routes))
+			}
+
+			for _, route := range routes {
+				if route.VPNMode != tt.expectedMode {
+					t.Errorf("Expected VPN mode '%s', got '%s'", tt.expectedMode, route.VPNMode)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_GetOrganizations(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/organizations" {
+			t.Errorf("Expected path /organizations, got %s", r.URL.Path)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[
+			{
+				"id": "123456",
+				"name": "Test Organization",
+				"url": "https://dashboard.meraki.com/test",
+				"api": {"enabled": true},
+				"licensing": {"model": "co-term"},
+				"cloud": {
+					"region": {
+						"name": "North America",
+						"host": {"name": "United States"}
+					}
+				}
+			}
+		]`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-api-key",
+	}
+
+	orgs, err := client.GetOrganizations()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(orgs) != 1 {
+		t.Errorf("Expected 1 organization, got %d", len(orgs))
+	}
+
+	if orgs[0].ID != "123456" {
+		t.Errorf("Expected organization ID '123456', got '%s'", orgs[0].ID)
+	}
+
+	if orgs[0].Name != "Test Organization" {
+		t.Errorf("Expected organization name 'Test Organization', got '%s'", orgs[0].Name)
+	}
+
+	if !orgs[0].API.Enabled {
+		t.Error("Expected API to be enabled")
+	}
+}
+
+func TestClient_ResolveNetworkID(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/organizations/org123/networks" {
+			t.Errorf("Expected path /organizations/org123/networks, got %s", r.URL.Path)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[
+			{
+				"id": "net1",
+				"name": "Main Office",
+				"productTypes": ["appliance", "wireless"]
+			},
+			{
+				"id": "net2", 
+				"name": "Branch Office",
+				"productTypes": ["wireless"]
+			},
+			{
+				"id": "net3",
+				"name": "Main Office",
+				"productTypes": ["camera"]
+			}
+		]`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-api-key",
+	}
+
+	tests := []struct {
+		name              string
+		networkIdentifier string
+		expectedID        string
+		shouldError       bool
+		errorContains     string
+	}{
+		{
+			name:              "resolve by existing ID",
+			networkIdentifier: "net1",
+			expectedID:        "net1",
+			shouldError:       false,
+		},
+		{
+			name:              "resolve by unique name",
+			networkIdentifier: "Branch Office",
+			expectedID:        "net2",
+			shouldError:       false,
+		},
+		{
+			name:              "resolve by unique name case insensitive",
+			networkIdentifier: "BRANCH OFFICE",
+			expectedID:        "net2",
+			shouldError:       false,
+		},
+		{
+			name:              "resolve by duplicate name should error",
+			networkIdentifier: "Main Office",
+			shouldError:       true,
+			errorContains:     "multiple networks found",
+		},
+		{
+			name:              "resolve by duplicate name case insensitive should error",
+			networkIdentifier: "main office",
+			shouldError:       true,
+			errorContains:     "multiple networks found",
+		},
+		{
 			name:              "resolve non-existent network should error",
 			networkIdentifier: "Non-existent Network",
 			shouldError:       true,
 			errorContains:     "not found",
 		},
 		{
-			name:              "empty identifier returns empty",
-			networkIdentifier: "",
-			expectedID:        "",
-			shouldError:       false,
+			name:              "empty identifier returns empty",
+			networkIdentifier: "",
+			expectedID:        "",
+			shouldError:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolvedID, err := client.ResolveNetworkID("org123", tt.networkIdentifier)
+
+			if tt.shouldError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				if tt.errorContains != "" && !strings.Contains(err.Error(), tt.errorContains) {
+					t.Errorf("Expected error to contain '%s', got '%s'", tt.errorContains, err.Error())
+				}
+			} else {
+				if err != nil {
+					t.Errorf("Expected no error but got: %v", err)
+				}
+				if resolvedID != tt.expectedID {
+					t.Errorf("Expected resolved ID '%s', got '%s'", tt.expectedID, resolvedID)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_GetAllNetworkRoutes(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/organizations/org123/networks":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{
+					"id": "net1",
+					"name": "Network 1",
+					"productTypes": ["appliance"]
+				},
+				{
+					"id": "net2", 
+					"name": "Network 2",
+					"productTypes": ["appliance"]
+				}
+			]`))
+		case "/networks/net1/appliance/staticRoutes":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{
+					"id": "route1",
+					"name": "Test Route 1",
+					"subnet": "192.168.1.0/24",
+					"gatewayIp": "192.168.1.1",
+					"enabled": true
+				}
+			]`))
+		case "/networks/net1/appliance/vpn/siteToSiteVpn":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{
+				"subnets": [
+					{
+						"localSubnet": "10.1.0.0/16",
+						"useVpn": true
+					}
+				]
+			}`))
+		case "/networks/net2/appliance/staticRoutes":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{
+					"id": "route2",
+					"name": "Test Route 2",
+					"subnet": "192.168.2.0/24",
+					"gatewayIp": "192.168.2.1",
+					"enabled": true
+				}
+			]`))
+		case "/networks/net2/appliance/vpn/siteToSiteVpn":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{
+				"subnets": [
+					{
+						"localSubnet": "10.2.0.0/16",
+						"useVpn": true
+					}
+				]
+			}`))
+		case "/networks/net1/appliance/vlans":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{
+					"id": 1,
+					"name": "Default-1",
+					"applianceIp": "172.16.1.1",
+					"subnet": "172.16.1.0/24"
+				}
+			]`))
+		case "/networks/net2/appliance/vlans":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{
+					"id": 1,
+					"name": "Default-2",
+					"applianceIp": "172.16.2.1",
+					"subnet": "172.16.2.0/24"
+				}
+			]`))
+		// Switch routing endpoints for net1
+		case "/networks/net1/switch/routing/interfaces":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[]`))
+		case "/networks/net1/switch/routing/staticRoutes":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[]`))
+		case "/networks/net1/switch/stacks":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[]`))
+		// Switch routing endpoints for net2
+		case "/networks/net2/switch/routing/interfaces":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[]`))
+		case "/networks/net2/switch/routing/staticRoutes":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[]`))
+		case "/networks/net2/switch/stacks":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[]`))
+		default:
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-api-key",
+	}
+
+	allNetworkRoutes, err := client.GetAllNetworkRoutes("org123")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(allNetworkRoutes) != 2 {
+		t.Errorf("Expected 2 network route sets, got %d", len(allNetworkRoutes))
+	}
+
+	// Check first network
+	if allNetworkRoutes[0].Network.ID != "net1" {
+		t.Errorf("Expected network ID 'net1', got '%s'", allNetworkRoutes[0].Network.ID)
+	}
+	if len(allNetworkRoutes[0].Routes) != 3 {
+		t.Errorf("Expected 3 routes for net1, got %d", len(allNetworkRoutes[0].Routes))
+	}
+	if allNetworkRoutes[0].Routes[0].ID != "route1" {
+		t.Errorf("Expected route ID 'route1', got '%s'", allNetworkRoutes[0].Routes[0].ID)
+	}
+
+	// Check second network
+	if allNetworkRoutes[1].Network.ID != "net2" {
+		t.Errorf("Expected network ID 'net2', got '%s'", allNetworkRoutes[1].Network.ID)
+	}
+	if len(allNetworkRoutes[1].Routes) != 3 {
+		t.Errorf("Expected 3 routes for net2, got %d", len(allNetworkRoutes[1].Routes))
+	}
+	if allNetworkRoutes[1].Routes[0].ID != "route2" {
+		t.Errorf("Expected route ID 'route2', got '%s'", allNetworkRoutes[1].Routes[0].ID)
+	}
+	if allNetworkRoutes[0].Error != "" || allNetworkRoutes[1].Error != "" {
+		t.Errorf("Expected no network errors, got '%s' and '%s'", allNetworkRoutes[0].Error, allNetworkRoutes[1].Error)
+	}
+
+	// One request for the network list plus six route endpoints per network
+	if client.RequestCount() != 13 {
+		t.Errorf("Expected 13 API requests, got %d", client.RequestCount())
+	}
+}
+
+func TestClient_ResolveOrganizations(t *testing.T) {
+	listCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		listCalls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id": "org1", "name": "Test Org 1"}, {"id": "org2", "name": "Test Org 2"}, {"id": "org3", "name": "Test Org 3"}]`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-key",
+	}
+
+	orgs, err := client.ResolveOrganizations([]string{"org3", "test org 1", "Test Org 3"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(orgs) != 2 || orgs[0].ID != "org3" || orgs[1].ID != "org1" {
+		t.Errorf("Expected org3 and org1 once each in the given order, got %+v", orgs)
+	}
+	if listCalls != 1 {
+		t.Errorf("Expected organizations to be listed once, got %d requests", listCalls)
+	}
+
+	if _, err := client.ResolveOrganizations([]string{"org1", "Missing Org"}); err == nil || !strings.Contains(err.Error(), "'Missing Org' not found") {
+		t.Errorf("Expected an error for an unresolvable organization, got %v", err)
+	}
+}
+
+func TestClient_ResolveOrganizations_CommaInName(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id": "org1", "name": "Acme, Inc."}, {"id": "org2", "name": "Test Org 2"}, {"id": "org3", "name": "Inc."}]`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-key",
+	}
+
+	tests := []struct {
+		name        string
+		identifiers []string
+		expectedIDs []string
+	}{
+		{name: "whole name with a comma", identifiers: []string{"acme, inc."}, expectedIDs: []string{"org1"}},
+		{name: "comma-separated list", identifiers: []string{"test org 2, org3"}, expectedIDs: []string{"org2", "org3"}},
+		{name: "repeated values are not split", identifiers: []string{"Acme, Inc.", "org2"}, expectedIDs: []string{"org1", "org2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orgs, err := client.ResolveOrganizations(tt.identifiers)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			var ids []string
+			for _, org := range orgs {
+				ids = append(ids, org.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.expectedIDs) {
+				t.Errorf("Expected %v, got %v", tt.expectedIDs, ids)
+			}
+		})
+	}
+
+	if _, err := client.ResolveOrganizations([]string{"Acme, Missing"}); err == nil || !strings.Contains(err.Error(), "'Acme' not found") {
+		t.Errorf("Expected the split entries to be reported, got %v", err)
+	}
+}
+
+func TestResolveOrganizationID(t *testing.T) {
+	// Mock server setup
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/organizations" {
+			// Mock organizations response
+			response := `[
+				{"id": "org1", "name": "Test Org 1"},
+				{"id": "org2", "name": "Test Org 2"},
+				{"id": "org3", "name": "Duplicate Name"},
+				{"id": "org4", "name": "Duplicate Name"}
+			]`
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(response))
+		}
+	}))
+	defer server.Close()
+
+	// Create client with mock server
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-key",
+	}
+
+	tests := []struct {
+		name          string
+		orgIdentifier string
+		expectedID    string
+		expectedName  string
+		shouldErr     bool
+		errorContains string
+	}{
+		{
+			name:          "resolve by ID",
+			orgIdentifier: "org1",
+			expectedID:    "org1",
+			expectedName:  "Test Org 1",
+			shouldErr:     false,
+		},
+		{
+			name:          "resolve by name",
+			orgIdentifier: "Test Org 2",
+			expectedID:    "org2",
+			expectedName:  "Test Org 2",
+			shouldErr:     false,
+		},
+		{
+			name:          "resolve by name case insensitive",
+			orgIdentifier: "TEST ORG 2",
+			expectedID:    "org2",
+			expectedName:  "Test Org 2",
+			shouldErr:     false,
+		},
+		{
+			name:          "empty identifier",
+			orgIdentifier: "",
+			expectedID:    "",
+			shouldErr:     false,
+		},
+		{
+			name:          "non-existent organization",
+			orgIdentifier: "Non-existent Org",
+			shouldErr:     true,
+			errorContains: "not found",
+		},
+		{
+			name:          "duplicate organization names",
+			orgIdentifier: "Duplicate Name",
+			shouldErr:     true,
+			errorContains: "multiple organizations found",
+		},
+		{
+			name:          "duplicate organization names case insensitive",
+			orgIdentifier: "duplicate name",
+			shouldErr:     true,
+			errorContains: "multiple organizations found",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			resolvedID, err := client.ResolveNetworkID("org123", tt.networkIdentifier)
+			orgID, err := client.ResolveOrganizationID(tt.orgIdentifier)
+
+			if tt.shouldErr {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				} else if tt.errorContains != "" && !strings.Contains(err.Error(), tt.errorContains) {
+					t.Errorf("Expected error to contain '%s', got: %s", tt.errorContains, err.Error())
+				}
+			} else {
+				if err != nil {
+					t.Errorf("Expected no error, got: %s", err.Error())
+				}
+				if orgID != tt.expectedID {
+					t.Errorf("Expected organization ID '%s', got '%s'", tt.expectedID, orgID)
+				}
+
+				org, err := client.ResolveOrganization(tt.orgIdentifier)
+				if err != nil {
+					t.Fatalf("Expected no error, got: %s", err.Error())
+				}
+				if org.ID != tt.expectedID || org.Name != tt.expectedName {
+					t.Errorf("Expected organization %s (%s), got %s (%s)", tt.expectedName, tt.expectedID, org.Name, org.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_GetDeviceStatusSummary(t *testing.T) {
+	statusCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/organizations/org123/networks":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "net1", "name": "Branch"}, {"id": "net2", "name": "HQ"}]`))
+		case "/organizations/org123/devices/statuses":
+			statusCalls++
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{"serial": "A", "status": "online", "networkId": "net1", "productType": "appliance"},
+				{"serial": "B", "status": "offline", "networkId": "net1", "productType": "wireless"},
+				{"serial": "C", "status": "alerting", "networkId": "net2", "productType": "wireless"},
+				{"serial": "D", "status": "dormant", "networkId": "net2", "productType": "switch"},
+				{"serial": "E", "status": "online", "networkId": "net2", "productType": "switch"}
+			]`))
+		default:
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-api-key",
+	}
+
+	summaries, err := client.GetDeviceStatusSummary("org123", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if statusCalls != 1 {
+		t.Errorf("Expected 1 statuses call, got %d", statusCalls)
+	}
+
+	// 2 networks + 3 product types
+	if len(summaries) != 5 {
+		t.Fatalf("Expected 5 summary records, got %d", len(summaries))
+	}
+
+	branch := summaries[0]
+	if branch.Scope != "network" || branch.NetworkName != "Branch" || branch.Online != 1 || branch.Offline != 1 || branch.Total != 2 {
+		t.Errorf("Unexpected Branch summary: %+v", branch)
+	}
+
+	hq := summaries[1]
+	if hq.NetworkName != "HQ" || hq.Alerting != 1 || hq.Dormant != 1 || hq.Online != 1 || hq.Total != 3 {
+		t.Errorf("Unexpected HQ summary: %+v", hq)
+	}
+
+	wireless := summaries[4]
+	if wireless.Scope != "productType" || wireless.ProductType != "wireless" || wireless.Total != 2 {
+		t.Errorf("Unexpected wireless summary: %+v", wireless)
+	}
+
+	total := TotalDeviceStatusSummary(summaries)
+	if total.Scope != "total" || total.Total != 5 || total.Online != 2 || total.Offline != 1 || total.Alerting != 1 || total.Dormant != 1 {
+		t.Errorf("Unexpected total summary: %+v", total)
+	}
+
+	// Restricting to a single network only counts its devices
+	summaries, err = client.GetDeviceStatusSummary("org123", "HQ")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	total = TotalDeviceStatusSummary(summaries)
+	if total.Total != 3 {
+		t.Errorf("Expected 3 devices for HQ, got %d", total.Total)
+	}
+
+	// The organization total needs only the statuses call
+	statusCalls = 0
+	orgTotal, err := client.GetOrganizationDeviceStatusTotal("org123")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if statusCalls != 1 || orgTotal.Total != 5 || orgTotal.Online != 2 || orgTotal.Alerting != 1 || orgTotal.Offline != 1 {
+		t.Errorf("Unexpected organization total after %d statuses calls: %+v", statusCalls, orgTotal)
+	}
+}
+
+func TestClient_GetSwitchStacks(t *testing.T) {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		switch r.URL.Path {
+		case "/organizations/org123/networks":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{"id": "net1", "name": "HQ", "productTypes": ["appliance", "switch"]},
+				{"id": "net2", "name": "Branch", "productTypes": ["wireless"]},
+				{"id": "net3", "name": "Campus", "productTypes": ["switch"]}
+			]`))
+		case "/networks/net1/switch/stacks":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "stack1", "name": "Core", "serials": ["Q2SW-0001", "Q2SW-0002"]}]`))
+		case "/networks/net3/switch/stacks":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{"id": "stack2", "name": "Access A", "serials": ["Q2SW-0003"]},
+				{"id": "stack3", "name": "Access B", "serials": ["Q2SW-0004", "Q2SW-0005", "Q2SW-0006"]}
+			]`))
+		default:
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-api-key",
+	}
+
+	t.Run("all switch networks", func(t *testing.T) {
+		stacks, err := client.GetSwitchStacks("org123", "")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(stacks) != 3 {
+			t.Fatalf("Expected 3 stacks, got %d", len(stacks))
+		}
+		if stacks[0].ID != "stack1" || stacks[0].NetworkName != "HQ" {
+			t.Errorf("Expected stack1 in HQ, got %s in %s", stacks[0].ID, stacks[0].NetworkName)
+		}
+		if len(stacks[0].Serials) != 2 || stacks[0].Serials[1] != "Q2SW-0002" {
+			t.Errorf("Expected member serials [Q2SW-0001 Q2SW-0002], got %v", stacks[0].Serials)
+		}
+		if len(stacks[2].Serials) != 3 || stacks[2].NetworkID != "net3" {
+			t.Errorf("Expected 3 members in net3 for stack3, got %v in %s", stacks[2].Serials, stacks[2].NetworkID)
+		}
+		for _, path := range requested {
+			if path == "/networks/net2/switch/stacks" {
+				t.Error("Expected network without switches to be skipped")
+			}
+		}
+	})
+
+	t.Run("single network by name", func(t *testing.T) {
+		stacks, err := client.GetSwitchStacks("org123", "Campus")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(stacks) != 2 {
+			t.Fatalf("Expected 2 stacks, got %d", len(stacks))
+		}
+		if stacks[0].Name != "Access A" || stacks[1].Name != "Access B" {
+			t.Errorf("Expected Access A and Access B, got %s and %s", stacks[0].Name, stacks[1].Name)
+		}
+	})
+}
+
+func TestClient_GetDHCPSubnets(t *testing.T) {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		switch r.URL.Path {
+		case "/organizations/org123/networks":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{"id": "net1", "name": "HQ", "productTypes": ["appliance", "switch"]},
+				{"id": "net2", "name": "Branch", "productTypes": ["wireless"]}
+			]`))
+		case "/networks/net1/appliance/vlans":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{
+					"id": 10, "name": "Users", "subnet": "10.0.10.0/24", "applianceIp": "10.0.10.1",
+					"dhcpHandling": "Run a DHCP server", "dhcpLeaseTime": "1 day",
+					"dnsNameservers": "10.0.0.53\n10.0.0.54",
+					"reservedIpRanges": [{"start": "10.0.10.1", "end": "10.0.10.20", "comment": "Printers"}],
+					"dhcpOptions": [{"code": "42", "type": "ip", "value": "10.0.0.123"}]
+				},
+				{
+					"id": 20, "name": "Voice", "subnet": "10.0.20.0/24", "applianceIp": "10.0.20.1",
+					"dhcpHandling": "Relay DHCP to another server", "dhcpRelayServerIps": ["10.0.0.67"]
+				}
+			]`))
+		case "/networks/net1/switch/stacks":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "stack1", "name": "Core", "serials": ["Q2SW-0001"]}]`))
+		case "/networks/net1/switch/stacks/stack1/routing/interfaces":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{"interfaceId": "if1", "name": "Servers", "subnet": "10.1.0.0/24", "interfaceIp": "10.1.0.1", "vlan": 100},
+				{"interfaceId": "if2", "name": "Cameras", "subnet": "10.2.0.0/24", "interfaceIp": "10.2.0.1", "vlan": 200}
+			]`))
+		case "/networks/net1/switch/stacks/stack1/routing/interfaces/if1/dhcp":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"dhcpMode": "dhcpRelay", "dhcpRelayServerIps": ["10.0.0.67", "10.0.0.68"]}`))
+		case "/networks/net1/switch/stacks/stack1/routing/interfaces/if2/dhcp":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{
+				"dhcpMode": "dhcpServer", "dhcpLeaseTime": "4 hours",
+				"dnsNameserversOption": "custom", "dnsCustomNameservers": ["8.8.8.8"]
+			}`))
+		default:
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-api-key",
+	}
+
+	subnets, err := client.GetDHCPSubnets("org123", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(subnets) != 4 {
+		t.Fatalf("Expected 4 DHCP subnets, got %d", len(subnets))
+	}
+
+	users := subnets[0]
+	if users.Source != "appliance" || users.VLAN != 10 || users.Mode != DHCPModeServer || users.NetworkName != "HQ" {
+		t.Errorf("Unexpected appliance server VLAN: %+v", users)
+	}
+	if users.LeaseTime != "1 day" || len(users.DNSNameservers) != 2 || users.DNSNameservers[1] != "10.0.0.54" {
+		t.Errorf("Expected lease time and two DNS nameservers, got %q and %v", users.LeaseTime, users.DNSNameservers)
+	}
+	if len(users.ReservedRanges) != 1 || users.ReservedRanges[0].Comment != "Printers" {
+		t.Errorf("Expected the Printers reserved range, got %v", users.ReservedRanges)
+	}
+	if len(users.Options) != 1 || users.Options[0].Code != "42" {
+		t.Errorf("Expected DHCP option 42, got %v", users.Options)
+	}
+
+	if voice := subnets[1]; voice.Mode != DHCPModeRelay || len(voice.RelayServerIPs) != 1 || voice.RelayServerIPs[0] != "10.0.0.67" {
+		t.Errorf("Expected appliance relay VLAN to 10.0.0.67, got %+v", voice)
+	}
+
+	servers := subnets[2]
+	if servers.Source != "switch-stack" || servers.StackName != "Core" || servers.InterfaceID != "if1" || servers.Subnet != "10.1.0.0/24" {
+		t.Errorf("Unexpected switch stack interface: %+v", servers)
+	}
+	if servers.Mode != DHCPModeRelay || len(servers.RelayServerIPs) != 2 {
+		t.Errorf("Expected relay to two servers, got %s with %v", servers.Mode, servers.RelayServerIPs)
+	}
+
+	if cameras := subnets[3]; cameras.Mode != DHCPModeServer || len(cameras.DNSNameservers) != 1 || cameras.DNSNameservers[0] != "8.8.8.8" {
+		t.Errorf("Expected DHCP server with custom DNS 8.8.8.8, got %+v", cameras)
+	}
+
+	for _, path := range requested {
+		if strings.HasPrefix(path, "/networks/net2/") {
+			t.Errorf("Expected network without appliances or switches to be skipped, got request for %s", path)
+		}
+	}
+}
+
+func TestClient_getNetworkSwitchStackRoutes_NoDHCP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/networks/net1/switch/stacks":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "stack1", "name": "Core"}]`))
+		case "/networks/net1/switch/stacks/stack1/routing/interfaces":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"interfaceId": "if1", "name": "Servers", "subnet": "10.1.0.0/24", "interfaceIp": "10.1.0.1"}]`))
+		case "/networks/net1/switch/stacks/stack1/routing/staticRoutes":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[]`))
+		default:
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-api-key",
+	}
+
+	routes, err := client.getNetworkSwitchStackRoutes("net1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(routes) != 1 || routes[0].Subnet != "10.1.0.0/24" {
+		t.Errorf("Expected only the stack interface route, got %+v", routes)
+	}
+}
+
+func TestFilterNetworksByPattern(t *testing.T) {
+	networks := []Network{
+		{ID: "N_1", Name: "Store-001"},
+		{ID: "N_2", Name: "Store-002"},
+		{ID: "N_3", Name: "store-EAST"},
+		{ID: "N_4", Name: "Warehouse"},
+		{ID: "N_5", Name: "Storefront Lab"},
+	}
+
+	tests := []struct {
+		name        string
+		pattern     string
+		expectedIDs []string
+		shouldError bool
+	}{
+		{name: "star suffix", pattern: "Store-*", expectedIDs: []string{"N_1", "N_2", "N_3"}},
+		{name: "single character", pattern: "Store-00?", expectedIDs: []string{"N_1", "N_2"}},
+		{name: "character class", pattern: "Store-00[2-9]", expectedIDs: []string{"N_2"}},
+		{name: "case insensitive", pattern: "WARE*", expectedIDs: []string{"N_4"}},
+		{name: "no matches", pattern: "Office-*", expectedIDs: []string{}},
+		{name: "malformed pattern", pattern: "Store-[", shouldError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matched, err := FilterNetworksByPattern(networks, tt.pattern)
+			if tt.shouldError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(matched) != len(tt.expectedIDs) {
+				t.Fatalf("Expected %d matches, got %d", len(tt.expectedIDs), len(matched))
+			}
+			for i, network := range matched {
+				if network.ID != tt.expectedIDs[i] {
+					t.Errorf("Expected match %d to be %s, got %s", i, tt.expectedIDs[i], network.ID)
+				}
+			}
+		})
+	}
+
+	if !IsNetworkPattern("Store-*") || IsNetworkPattern("Store-001") {
+		t.Error("IsNetworkPattern did not detect wildcards correctly")
+	}
+}
+
+func TestClient_GetDownDevices_DownLongerThan(t *testing.T) {
+	fixedNow := time.Date(2025, 7, 17, 12, 0, 0, 0, time.UTC)
+	originalNow := now
+	now = func() time.Time { return fixedNow }
+	defer func() { now = originalNow }()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/organizations/org123/networks":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "net1", "name": "HQ"}]`))
+		case "/networks/net1/devices":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{"serial": "RECENT", "status": "offline", "lastReportedAt": "2025-07-17T11:50:00Z"},
+				{"serial": "OLD", "status": "offline", "lastReportedAt": "2025-07-16T09:55:00Z"},
+				{"serial": "UNKNOWN", "status": "offline"},
+				{"serial": "UP", "status": "online", "lastReportedAt": "2025-07-16T09:55:00Z"}
+			]`))
+		default:
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-api-key",
+	}
+
+	t.Run("no threshold reports all down devices with durations", func(t *testing.T) {
+		client.SetDownLongerThan(0)
+		devices, err := client.GetDownDevices("org123", "net1", nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(devices) != 3 {
+			t.Fatalf("Expected 3 down devices, got %d", len(devices))
+		}
+		// Longest down first, unknown durations last
+		if devices[0].Serial != "OLD" || devices[0].DownDuration != "26h5m" {
+			t.Errorf("Expected OLD down for '26h5m' first, got %s down for '%s'", devices[0].Serial, devices[0].DownDuration)
+		}
+		if devices[1].Serial != "RECENT" || devices[1].DownDuration != "10m" {
+			t.Errorf("Expected RECENT down for '10m' second, got %s down for '%s'", devices[1].Serial, devices[1].DownDuration)
+		}
+		if devices[2].Serial != "UNKNOWN" || devices[2].DownDuration != UnknownDownDuration {
+			t.Errorf("Expected UNKNOWN with down duration 'unknown' last, got %s down for '%s'", devices[2].Serial, devices[2].DownDuration)
+		}
+	})
+
+	t.Run("threshold excludes recently reporting devices", func(t *testing.T) {
+		client.SetDownLongerThan(time.Hour)
+		devices, err := client.GetDownDevices("org123", "net1", nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(devices) != 2 {
+			t.Fatalf("Expected 2 down devices, got %d", len(devices))
+		}
+		if devices[0].Serial != "OLD" || devices[1].Serial != "UNKNOWN" {
+			t.Errorf("Expected OLD and UNKNOWN, got %s and %s", devices[0].Serial, devices[1].Serial)
+		}
+	})
+}
+
+func TestClient_GetDownDevices_DownStatuses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/organizations/org123/networks":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "net1", "name": "HQ"}]`))
+		case "/networks/net1/devices":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{"serial": "OFFLINE", "status": "offline"},
+				{"serial": "DORMANT", "status": "dormant"},
+				{"serial": "MAINT", "status": "Maintenance"},
+				{"serial": "UP", "status": "online"}
+			]`))
+		default:
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-api-key",
+	}
+
+	tests := []struct {
+		name         string
+		downStatuses []string
+		expected     []string
+	}{
+		{"nil uses default list", nil, []string{"OFFLINE", "DORMANT"}},
+		{"default list", DefaultDownStatuses, []string{"OFFLINE", "DORMANT"}},
+		{"custom list replaces default", []string{"offline", "maintenance"}, []string{"OFFLINE", "MAINT"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			devices, err := client.GetDownDevices("org123", "net1", tt.downStatuses)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			var serials []string
+			for _, device := range devices {
+				serials = append(serials, device.Serial)
+			}
+			if strings.Join(serials, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected %v, got %v", tt.expected, serials)
+			}
+		})
+	}
+}
+
+func TestClient_GetDownDevices_WarmSpare(t *testing.T) {
+	var warmSpareCalls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/organizations/org123/networks":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{"id": "net1", "name": "HQ", "productTypes": ["appliance", "switch"]},
+				{"id": "net2", "name": "Branch", "productTypes": ["wireless"]}
+			]`))
+		case "/networks/net1/devices":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{"serial": "MX-PRIMARY", "networkId": "net1", "productType": "appliance", "status": "online"},
+				{"serial": "MX-SPARE", "networkId": "net1", "productType": "appliance", "status": "offline"},
+				{"serial": "MS-1", "networkId": "net1", "productType": "switch", "status": "offline"}
+			]`))
+		case "/networks/net2/devices":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"serial": "MR-1", "networkId": "net2", "productType": "wireless", "status": "offline"}]`))
+		case "/networks/net1/appliance/warmSpare":
+			warmSpareCalls = append(warmSpareCalls, "net1")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"enabled": true, "primarySerial": "MX-PRIMARY", "spareSerial": "MX-SPARE"}`))
+		default:
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-api-key",
+	}
+
+	t.Run("annotates spare and skips networks without appliances", func(t *testing.T) {
+		warmSpareCalls = nil
+		devices, err := client.GetDownDevices("org123", "", nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(devices) != 3 {
+			t.Fatalf("Expected 3 down devices, got %d", len(devices))
+		}
+		if devices[0].Serial != "MX-SPARE" || devices[0].WarmSpareRole != "spare" {
+			t.Errorf("Expected MX-SPARE annotated as spare, got %s with role '%s'", devices[0].Serial, devices[0].WarmSpareRole)
+		}
+		if devices[1].WarmSpareRole != "" {
+			t.Errorf("Expected no warm spare role for switch, got '%s'", devices[1].WarmSpareRole)
+		}
+		if len(warmSpareCalls) != 1 {
+			t.Errorf("Expected warm spare lookup for net1 only, got %v", warmSpareCalls)
+		}
+	})
+
+	t.Run("ignore-warm-spare drops spares with an online primary", func(t *testing.T) {
+		client.SetIgnoreWarmSpare(true)
+		defer client.SetIgnoreWarmSpare(false)
+
+		devices, err := client.GetDownDevices("org123", "", nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(devices) != 2 {
+			t.Fatalf("Expected 2 down devices, got %d", len(devices))
+		}
+		for _, device := range devices {
+			if device.Serial == "MX-SPARE" {
+				t.Error("Expected MX-SPARE to be suppressed")
+			}
+		}
+	})
+}
+
+func TestDeviceFilter_Matches(t *testing.T) {
+	devices := []Device{
+		{Serial: "A", Model: "MR46", ProductType: "wireless", Tags: []string{"critical", "lobby"}},
+		{Serial: "B", Model: "MV12W", ProductType: "camera", Tags: []string{"Critical"}},
+		{Serial: "C", Model: "MS225-48LP", ProductType: "switch"},
+		{Serial: "D", Model: "MR36", ProductType: "wireless"},
+	}
+
+	tests := []struct {
+		name     string
+		filter   DeviceFilter
+		expected []string
+	}{
+		{name: "no filters", filter: DeviceFilter{}, expected: []string{"A", "B", "C", "D"}},
+		{name: "model glob", filter: DeviceFilter{Models: []string{"mr*"}}, expected: []string{"A", "D"}},
+		{name: "model exact", filter: DeviceFilter{Models: []string{"MR46"}}, expected: []string{"A"}},
+		{name: "model prefix", filter: DeviceFilter{Models: []string{"MS"}}, expected: []string{"C"}},
+		{name: "model prefix case insensitive", filter: DeviceFilter{Models: []string{"mv"}}, expected: []string{"B"}},
+		{name: "model list matches any", filter: DeviceFilter{Models: []string{"MR36", "MV"}}, expected: []string{"B", "D"}},
+		{name: "model is not a substring match", filter: DeviceFilter{Models: []string{"225"}}, expected: []string{}},
+		{name: "empty model list", filter: DeviceFilter{Models: []string{}}, expected: []string{"A", "B", "C", "D"}},
+		{name: "product type case insensitive", filter: DeviceFilter{ProductTypes: []string{"CAMERA"}}, expected: []string{"B"}},
+		{name: "product type list", filter: DeviceFilter{ProductTypes: []string{"switch", "camera"}}, expected: []string{"B", "C"}},
+		{name: "product type list with overlap", filter: DeviceFilter{ProductTypes: []string{"wireless", "Wireless", "switch"}}, expected: []string{"A", "C", "D"}},
+		{name: "empty product type list", filter: DeviceFilter{ProductTypes: []string{}}, expected: []string{"A", "B", "C", "D"}},
+		{name: "tag case insensitive", filter: DeviceFilter{Tag: "critical"}, expected: []string{"A", "B"}},
+		{name: "AND semantics", filter: DeviceFilter{ProductTypes: []string{"wireless"}, Tag: "critical"}, expected: []string{"A"}},
+		{name: "no match", filter: DeviceFilter{Models: []string{"MX*"}, Tag: "critical"}, expected: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered := tt.filter.Apply(devices)
+			if len(filtered) != len(tt.expected) {
+				t.Fatalf("Expected %d devices, got %d", len(tt.expected), len(filtered))
+			}
+			for i, device := range filtered {
+				if device.Serial != tt.expected[i] {
+					t.Errorf("Expected device %d to be %s, got %s", i, tt.expected[i], device.Serial)
+				}
+			}
+		})
+	}
+}
+
+func TestTagFilter_Matches(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   TagFilter
+		tags     []string
+		expected bool
+	}{
+		{name: "empty filter", filter: TagFilter{}, tags: nil, expected: true},
+		{name: "empty filter any", filter: TagFilter{MatchAny: true}, tags: []string{"retail"}, expected: true},
+		{name: "all matched", filter: TagFilter{Tags: []string{"retail", "east"}}, tags: []string{"East", "retail", "pci"}, expected: true},
+		{name: "all missing one", filter: TagFilter{Tags: []string{"retail", "east"}}, tags: []string{"retail"}, expected: false},
+		{name: "any matched", filter: TagFilter{Tags: []string{"retail", "east"}, MatchAny: true}, tags: []string{"east"}, expected: true},
+		{name: "any none matched", filter: TagFilter{Tags: []string{"retail", "east"}, MatchAny: true}, tags: []string{"west"}, expected: false},
+		{name: "untagged", filter: TagFilter{Tags: []string{"retail"}}, tags: nil, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(tt.tags); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestClient_GetDownDevices_TagFilter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/organizations/org123/networks":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{"id": "net1", "name": "Store 1", "tags": ["retail"]},
+				{"id": "net2", "name": "Office", "tags": ["corp"]}
+			]`))
+		case "/networks/net1/devices":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"serial": "STORE-AP", "networkId": "net1", "status": "offline"}]`))
+		case "/networks/net2/devices":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{"serial": "OFFICE-AP", "networkId": "net2", "status": "offline"},
+				{"serial": "OFFICE-KIOSK", "networkId": "net2", "status": "offline", "tags": ["retail", "kiosk"]}
+			]`))
+		default:
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-api-key",
+	}
+
+	serials := func(devices []Device) string {
+		var s []string
+		for _, device := range devices {
+			s = append(s, device.Serial)
+		}
+		return strings.Join(s, ",")
+	}
+
+	client.SetTagFilter(TagFilter{Tags: []string{"retail"}})
+	devices, err := client.GetDownDevices("org123", "", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := serials(devices); got != "STORE-AP,OFFICE-KIOSK" {
+		t.Errorf("Expected devices in retail networks or tagged retail, got %s", got)
+	}
+
+	client.SetTagFilter(TagFilter{Tags: []string{"retail", "kiosk"}})
+	devices, err = client.GetDownDevices("org123", "", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := serials(devices); got != "OFFICE-KIOSK" {
+		t.Errorf("Expected only the device with both tags, got %s", got)
+	}
+
+	client.SetTagFilter(TagFilter{Tags: []string{"corp", "kiosk"}, MatchAny: true})
+	devices, err = client.GetDownDevices("org123", "", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := serials(devices); got != "OFFICE-AP,OFFICE-KIOSK" {
+		t.Errorf("Expected devices matching any tag, got %s", got)
+	}
+}
+
+func TestClient_SetProxy(t *testing.T) {
+	// A stub forward proxy that records the absolute request URLs it receives
+	var proxiedHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.URL.Host
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[]`))
+	}))
+	defer proxy.Close()
+
+	client, err := NewClient("test-api-key")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client.baseURL = "http://api.meraki.test/api/v1"
+
+	if err := client.SetProxy(proxy.URL); err != nil {
+		t.Fatalf("Failed to set proxy: %v", err)
+	}
+
+	if _, err := client.GetOrganizations(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if proxiedHost != "api.meraki.test" {
+		t.Errorf("Expected request for api.meraki.test through proxy, got host '%s'", proxiedHost)
+	}
+
+	if proxyURL, err := client.ProxyURL(); err != nil || proxyURL == nil || proxyURL.String() != proxy.URL {
+		t.Errorf("Expected ProxyURL %s, got %v (err %v)", proxy.URL, proxyURL, err)
+	}
+
+	if err := client.SetProxy("not a url"); err == nil {
+		t.Error("Expected error for invalid proxy URL")
+	}
+	if err := client.SetProxy("ftp://proxy.example.com:21"); err == nil {
+		t.Error("Expected error for unsupported proxy scheme")
+	}
+}
+
+func TestClient_DisableProxy(t *testing.T) {
+	t.Setenv("HTTP_PROXY", "http://127.0.0.1:1")
+
+	client, err := NewClient("test-api-key")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if err := client.DisableProxy(); err != nil {
+		t.Fatalf("Failed to disable proxy: %v", err)
+	}
+
+	transport := client.httpClient.Transport.(*http.Transport)
+	if transport.Proxy != nil {
+		t.Error("Expected no proxy function after DisableProxy")
+	}
+	if proxyURL, err := client.ProxyURL(); err != nil || proxyURL != nil {
+		t.Errorf("Expected direct connection, got %v (err %v)", proxyURL, err)
+	}
+}
+
+func TestClient_ConnectRetries(t *testing.T) {
+	originalDelay := connectRetryDelay
+	connectRetryDelay = time.Millisecond
+	defer func() { connectRetryDelay = originalDelay }()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	// newClient returns a client whose first dial fails, as on a cold network
+	newClient := func(retries int) (*Client, *int) {
+		dials := 0
+		dialer := &net.Dialer{}
+		client := &Client{
+			httpClient: &http.Client{Transport: &http.Transport{
+				DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+					dials++
+					if dials == 1 {
+						return nil, &net.OpError{Op: "dial", Net: network, Err: errors.New("network is unreachable")}
+					}
+					return dialer.DialContext(ctx, network, addr)
+				},
+			}},
+			baseURL: server.URL,
+			apiKey:  "test-api-key",
+		}
+		client.SetConnectRetries(retries)
+		return client, &dials
+	}
+
+	t.Run("first dial fails then succeeds", func(t *testing.T) {
+		client, dials := newClient(2)
+		if _, err := client.GetOrganizations(); err != nil {
+			t.Fatalf("Expected request to succeed after connect retry, got: %v", err)
+		}
+		if *dials != 2 {
+			t.Errorf("Expected 2 dial attempts, got %d", *dials)
+		}
+	})
+
+	t.Run("no connect retries by default", func(t *testing.T) {
+		client, dials := newClient(0)
+		if _, err := client.GetOrganizations(); err == nil {
+			t.Fatal("Expected error without connect retries")
+		}
+		if *dials != 1 {
+			t.Errorf("Expected 1 dial attempt, got %d", *dials)
+		}
+	})
+}
+
+func TestClient_GetNetworkEvents_Pagination(t *testing.T) {
+	originalPerPage := eventsPerPage
+	eventsPerPage = 2
+	defer func() { eventsPerPage = originalPerPage }()
+
+	// Three pages walking backwards from the newest events
+	pages := map[string]string{
+		"": `{"pageStartAt": "2025-07-17T04:00:00Z", "pageEndAt": "2025-07-17T06:00:00Z", "events": [
+			{"occurredAt": "2025-07-17T04:00:00Z", "type": "association", "description": "c"},
+			{"occurredAt": "2025-07-17T05:00:00Z", "type": "association", "description": "d"}]}`,
+		"2025-07-17T04:00:00Z": `{"pageStartAt": "2025-07-17T02:00:00Z", "pageEndAt": "2025-07-17T04:00:00Z", "events": [
+			{"occurredAt": "2025-07-17T02:00:00Z", "type": "association", "description": "a"},
+			{"occurredAt": "2025-07-17T03:00:00Z", "type": "disassociation", "description": "b", "deviceSerial": "Q2MR-0001"}]}`,
+		"2025-07-17T02:00:00Z": `{"pageStartAt": "2025-07-17T00:00:00Z", "pageEndAt": "2025-07-17T02:00:00Z", "events": [
+			{"occurredAt": "2025-07-17T00:00:00Z", "type": "association", "description": "y"},
+			{"occurredAt": "2025-07-17T01:00:00Z", "type": "association", "description": "z"}]}`,
+		"2025-07-17T00:00:00Z": `{"pageStartAt": "", "pageEndAt": "", "events": []}`,
+	}
+
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/organizations/org123/networks":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "net1", "name": "HQ"}]`))
+		case "/networks/net1/events":
+			requests = append(requests, r.URL.RawQuery)
+			if r.URL.Query().Get("perPage") != "2" || r.URL.Query().Get("productType") != "wireless" {
+				t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+			}
+			page, ok := pages[r.URL.Query().Get("endingBefore")]
+			if !ok {
+				t.Errorf("Unexpected endingBefore: %s", r.URL.Query().Get("endingBefore"))
+				page = `{"events": []}`
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(page))
+		default:
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-api-key",
+	}
+
+	descriptions := func(events []EventWithNetwork) string {
+		var parts []string
+		for _, event := range events {
+			parts = append(parts, event.Description)
+		}
+		return strings.Join(parts, ",")
+	}
+
+	tests := []struct {
+		name     string
+		query    EventQuery
+		expected string
+		requests int
+	}{
+		{"follows pages until the API runs out", EventQuery{}, "y,z,a,b,c,d", 4},
+		{"stops once the window start is covered", EventQuery{Since: time.Date(2025, 7, 17, 2, 30, 0, 0, time.UTC)}, "b,c,d", 2},
+		{"stops at the limit keeping the newest events", EventQuery{Limit: 3}, "b,c,d", 2},
+		{"until excludes newer events", EventQuery{Until: time.Date(2025, 7, 17, 4, 0, 0, 0, time.UTC)}, "y,z,a,b", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests = nil
+			tt.query.ProductType = "wireless"
+			events, err := client.GetNetworkEvents("org123", "HQ", tt.query)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := descriptions(events); got != tt.expected {
+				t.Errorf("Expected events %s, got %s", tt.expected, got)
+			}
+			if len(requests) != tt.requests {
+				t.Errorf("Expected %d event requests, got %d: %v", tt.requests, len(requests), requests)
+			}
+			if len(events) > 0 && (events[0].NetworkID != "net1" || events[0].NetworkName != "HQ") {
+				t.Errorf("Expected network context on events, got %+v", events[0])
+			}
+		})
+	}
+
+	t.Run("event types are passed to the API", func(t *testing.T) {
+		requests = nil
+		_, err := client.GetNetworkEvents("org123", "net1", EventQuery{ProductType: "wireless", EventTypes: []string{"association", "disassociation"}, Limit: 1})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(requests) != 1 || strings.Count(requests[0], "includedEventTypes%5B%5D=") != 2 {
+			t.Errorf("Expected both event types in the query, got %v", requests)
+		}
+	})
+}
+
+func TestFilterRoutesBySupernet(t *testing.T) {
+	routes := []Route{
+		{Name: "exact", Subnet: "10.0.0.0/8"},
+		{Name: "contained", Subnet: "10.20.30.0/24"},
+		{Name: "host", Subnet: "10.1.2.3"},
+		{Name: "wider", Subnet: "0.0.0.0/0"},
+		{Name: "outside", Subnet: "192.168.1.0/24"},
+		{Name: "ipv6", Subnet: "fd00::/64"},
+		{Name: "unparseable", Subnet: ""},
+	}
+
+	tests := []struct {
+		name     string
+		supernet string
+		expected string
+	}{
+		{"exact match and contained subnets", "10.0.0.0/8", "exact,contained,host"},
+		{"narrower supernet excludes wider routes", "10.20.0.0/16", "contained"},
+		{"subnet outside supernet", "172.16.0.0/12", ""},
+		{"ipv6 supernet", "fd00::/8", "ipv6"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered, err := FilterRoutesBySupernet(routes, tt.supernet)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			var names []string
+			for _, route := range filtered {
+				names = append(names, route.Name)
+			}
+			if strings.Join(names, ",") != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, strings.Join(names, ","))
+			}
+		})
+	}
+
+	t.Run("invalid CIDR", func(t *testing.T) {
+		if _, err := FilterRoutesBySupernet(routes, "10.0.0.0/33"); err == nil {
+			t.Error("Expected error for invalid CIDR")
+		}
+	})
+}
+
+func TestFilterRoutesByEnabled(t *testing.T) {
+	routes := []RouteWithNetwork{
+		{Route: Route{Name: "active", Enabled: true}, NetworkName: "HQ"},
+		{Route: Route{Name: "legacy", Enabled: false}, NetworkName: "HQ"},
+		{Route: Route{Name: "branch", Enabled: true}, NetworkName: "Branch"},
+	}
+
+	tests := []struct {
+		name         string
+		enabledOnly  bool
+		disabledOnly bool
+		expected     string
+	}{
+		{"no filter", false, false, "active,legacy,branch"},
+		{"enabled only", true, false, "active,branch"},
+		{"disabled only", false, true, "legacy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for _, route := range FilterRoutesByEnabled(routes, tt.enabledOnly, tt.disabledOnly) {
+				names = append(names, route.Name)
+			}
+			if strings.Join(names, ",") != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, strings.Join(names, ","))
+			}
+		})
+	}
+}
+
+func TestFilterRoutesByGateway(t *testing.T) {
+	routes := []RouteWithNetwork{
+		{Route: Route{Name: "hq", GatewayIP: "10.0.0.1"}},
+		{Route: Route{Name: "hq-backup", GatewayIP: "10.0.0.129"}},
+		{Route: Route{Name: "branch", GatewayIP: "192.168.1.1"}},
+		{Route: Route{Name: "v6", GatewayIP: "2001:db8::1"}},
+		{Route: Route{Name: "no-gateway"}},
+	}
+
+	tests := []struct {
+		name     string
+		filter   string
+		expected string
+	}{
+		{"no filter", "", "hq,hq-backup,branch,v6,no-gateway"},
+		{"exact IP", "10.0.0.1", "hq"},
+		{"exact IP is not a prefix match", "10.0.0.12", ""},
+		{"CIDR", "10.0.0.0/24", "hq,hq-backup"},
+		{"narrow CIDR", "10.0.0.128/25", "hq-backup"},
+		{"IPv6 CIDR", "2001:db8::/32", "v6"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered, err := FilterRoutesByGateway(routes, tt.filter)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			var names []string
+			for _, route := range filtered {
+				names = append(names, route.Name)
+			}
+			if strings.Join(names, ",") != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, strings.Join(names, ","))
+			}
+		})
+	}
+
+	for _, invalid := range []string{"10.0.0", "10.0.0.0/33", "gateway"} {
+		if _, err := FilterRoutesByGateway(routes, invalid); err == nil {
+			t.Errorf("Expected an error for invalid gateway filter %q", invalid)
+		}
+	}
+}
+
+func TestFindRouteOverlaps(t *testing.T) {
+	routes := []RouteWithNetwork{
+		{Route: Route{ID: "lab", Subnet: "10.1.0.0/24"}, NetworkName: "Branch"},
+		{Route: Route{ID: "corp", Subnet: "10.0.0.0/8"}, NetworkName: "HQ"},
+		{Route: Route{ID: "lab-copy", Subnet: "10.1.0.0/24"}, NetworkName: "Lab"},
+		{Route: Route{ID: "guest", Subnet: "192.168.0.0/24"}, NetworkName: "HQ"},
+		{Route: Route{ID: "host", Subnet: "192.168.0.10"}, NetworkName: "Branch"},
+		{Route: Route{ID: "v6", Subnet: "::/0"}, NetworkName: "HQ"},
+		{Route: Route{ID: "bad", Subnet: "not-a-subnet"}, NetworkName: "HQ"},
+	}
+
+	var got []string
+	for _, overlap := range FindRouteOverlaps(routes) {
+		got = append(got, overlap.Route.ID+" "+overlap.Relation+" "+overlap.OverlapsWith.ID)
+	}
+	expected := "corp contains lab,lab equal lab-copy,corp contains lab-copy,guest contains host"
+	if strings.Join(got, ",") != expected {
+		t.Errorf("Expected %q, got %q", expected, strings.Join(got, ","))
+	}
+
+	if overlaps := FindRouteOverlaps(routes[3:4]); overlaps == nil || len(overlaps) != 0 {
+		t.Errorf("Expected an empty list for a single route, got %v", overlaps)
+	}
+}
+
+func TestFilterLicensesByExpiry(t *testing.T) {
+	in := func(d time.Duration) string {
+		return time.Now().Add(d).UTC().Format(time.RFC3339)
+	}
+	licenses := []License{
+		{ID: "expired", ExpirationDate: in(-10 * 24 * time.Hour)},
+		{ID: "today", ExpirationDate: in(2 * time.Hour)},
+		{ID: "month", ExpirationDate: in(30*24*time.Hour + time.Hour)},
+		{ID: "year", ExpirationDate: in(365 * 24 * time.Hour)},
+		{ID: "queued", ExpirationDate: in(24 * time.Hour), PermanentlyQueued: true},
+		{ID: "no-expiry"},
+	}
+
+	ids := func(licenses []License) string {
+		var result []string
+		for _, license := range licenses {
+			result = append(result, license.ID)
+		}
+		return strings.Join(result, ",")
+	}
+
+	tests := []struct {
+		name     string
+		days     int
+		expected string
+	}{
+		{name: "expiring today", days: 0, expected: "expired,today"},
+		{name: "within 90 days", days: 90, expected: "expired,today,month"},
+		{name: "boundary day included", days: 30, expected: "expired,today,month"},
+		{name: "day before boundary", days: 29, expected: "expired,today"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered, err := FilterLicensesByExpiry(licenses, tt.days)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := ids(filtered); got != tt.expected {
+				t.Errorf("Expected licenses %q, got %q", tt.expected, got)
+			}
+		})
+	}
 
-			if tt.shouldError {
-				if err == nil {
-					t.Errorf("Expected error but got none")
-				}
-				if tt.errorContains != "" && !strings.Contains(err.Error(), tt.errorContains) {
-					t.Errorf("Expected error to contain '%s', got '%s'", tt.errorContains, err.Error())
-				}
-			} else {
-				if err != nil {
-					t.Errorf("Expected no error but got: %v", err)
-				}
-				if resolvedID != tt.expectedID {
-					t.Errorf("Expected resolved ID '%s', got '%s'", tt.expectedID, resolvedID)
-				}
+	t.Run("invalid expiration date", func(t *testing.T) {
+		if _, err := FilterLicensesByExpiry([]License{{ID: "bad", ExpirationDate: "Mar 1, 2025"}}, 30); err == nil {
+			t.Error("Expected error for non-RFC3339 expiration date")
+		}
+	})
+
+	t.Run("negative days", func(t *testing.T) {
+		if _, err := FilterLicensesByExpiry(licenses, -1); err == nil {
+			t.Error("Expected error for negative days")
+		}
+	})
+}
+
+func TestFilterLicensesByExpirationRange(t *testing.T) {
+	licenses := []License{
+		{ID: "december", ExpirationDate: "2025-12-31T23:59:59Z"},
+		{ID: "new-year", ExpirationDate: "2026-01-01T00:00:00Z"},
+		{ID: "june", ExpirationDate: "2026-06-15T00:00:00Z"},
+		{ID: "queued", ExpirationDate: "2026-03-01T00:00:00Z", PermanentlyQueued: true},
+		{ID: "no-expiry"},
+	}
+	date := func(value string) time.Time {
+		t, _ := time.Parse(time.DateOnly, value)
+		return t
+	}
+	ids := func(licenses []License) string {
+		var result []string
+		for _, license := range licenses {
+			result = append(result, license.ID)
+		}
+		return strings.Join(result, ",")
+	}
+
+	tests := []struct {
+		name     string
+		after    time.Time
+		before   time.Time
+		expected string
+	}{
+		{name: "after is inclusive", after: date("2026-01-01"), expected: "new-year,june"},
+		{name: "before is exclusive", before: date("2026-01-01"), expected: "december"},
+		{name: "range", after: date("2026-01-01"), before: date("2026-06-01"), expected: "new-year"},
+		{name: "open range", expected: "december,new-year,june"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered, err := FilterLicensesByExpirationRange(licenses, tt.after, tt.before)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := ids(filtered); got != tt.expected {
+				t.Errorf("Expected licenses %q, got %q", tt.expected, got)
 			}
 		})
 	}
+
+	t.Run("invalid expiration date", func(t *testing.T) {
+		if _, err := FilterLicensesByExpirationRange([]License{{ID: "bad", ExpirationDate: "Mar 1, 2025"}}, date("2025-01-01"), time.Time{}); err == nil {
+			t.Error("Expected error for non-RFC3339 expiration date")
+		}
+	})
 }
 
-func TestClient_GetAllNetworkRoutes(t *testing.T) {
-	// Create a test server
+func TestFilterRoutesByRegex(t *testing.T) {
+	routes := []Route{{Name: "BRANCH-Berlin-Data"}, {Name: "BRANCH-Munich-Voice"}, {Name: "HQ-Berlin-Data"}, {}}
+	names := func(routes []Route) string {
+		var result []string
+		for _, route := range routes {
+			result = append(result, route.Name)
+		}
+		return strings.Join(result, ",")
+	}
+
+	tests := []struct {
+		name        string
+		pattern     string
+		networkName string
+		expected    string
+	}{
+		{name: "anchored pattern", pattern: `^BRANCH-[A-Za-z]+-Data$`, expected: "BRANCH-Berlin-Data"},
+		{name: "partial substring match", pattern: `Berlin`, expected: "BRANCH-Berlin-Data,HQ-Berlin-Data"},
+		{name: "no match", pattern: `^Hamburg`, expected: ""},
+		{name: "network name matches every route", pattern: `^Store-`, networkName: "Store-12", expected: "BRANCH-Berlin-Data,BRANCH-Munich-Voice,HQ-Berlin-Data,"},
+		{name: "empty names never match", pattern: `^$`, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := names(FilterRoutesByRegex(routes, regexp.MustCompile(tt.pattern), tt.networkName)); got != tt.expected {
+				t.Errorf("Expected routes %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	if got := FilterRoutesByRegex(routes, nil, ""); len(got) != len(routes) {
+		t.Errorf("Expected routes unchanged without a regex, got %d", len(got))
+	}
+}
+
+func TestFilterDevicesByRegex(t *testing.T) {
+	devices := []Device{{Serial: "Q2AA-0001", Name: "BRANCH-Berlin-AP"}, {Serial: "Q2AA-0002", Name: "HQ-Berlin-SW"}}
+
+	if got := FilterDevicesByRegex(devices, regexp.MustCompile(`^BRANCH-`), ""); len(got) != 1 || got[0].Serial != "Q2AA-0001" {
+		t.Errorf("Expected the anchored match only, got %+v", got)
+	}
+	if got := FilterDevicesByRegex(devices, regexp.MustCompile(`-SW`), ""); len(got) != 1 || got[0].Serial != "Q2AA-0002" {
+		t.Errorf("Expected the substring match only, got %+v", got)
+	}
+	if got := FilterDevicesByRegex(devices, regexp.MustCompile(`^Store`), "Store-12"); len(got) != 2 {
+		t.Errorf("Expected every device of a matching network, got %+v", got)
+	}
+}
+
+func TestRoute_IsIPv6(t *testing.T) {
+	tests := []struct {
+		subnet   string
+		expected bool
+	}{
+		{subnet: "10.0.0.0/24", expected: false},
+		{subnet: "10.0.0.1", expected: false},
+		{subnet: "2001:db8::/48", expected: true},
+		{subnet: " 2001:db8::1 ", expected: true},
+		{subnet: "not-a-subnet", expected: false},
+		{subnet: "", expected: false},
+	}
+
+	for _, tt := range tests {
+		if got := (Route{Subnet: tt.subnet}).IsIPv6(); got != tt.expected {
+			t.Errorf("IsIPv6(%q) = %t, expected %t", tt.subnet, got, tt.expected)
+		}
+	}
+}
+
+func TestFilterRoutesByIPVersion(t *testing.T) {
+	routes := []Route{
+		{ID: "v4", Subnet: "10.0.0.0/24"},
+		{ID: "v6", Subnet: "2001:db8::/48"},
+		{ID: "v4-host", Subnet: "192.168.1.1"},
+		{ID: "bad", Subnet: "10.0.0.0/33"},
+	}
+	ids := func(routes []Route) string {
+		var result []string
+		for _, route := range routes {
+			result = append(result, route.ID)
+		}
+		return strings.Join(result, ",")
+	}
+
+	if got := ids(FilterRoutesByIPVersion(routes, 4)); got != "v4,v4-host" {
+		t.Errorf("Expected IPv4 routes, got %q", got)
+	}
+	if got := ids(FilterRoutesByIPVersion(routes, 6)); got != "v6" {
+		t.Errorf("Expected IPv6 routes, got %q", got)
+	}
+	if got := ids(FilterRoutesByIPVersion(routes, 0)); got != "v4,v6,v4-host,bad" {
+		t.Errorf("Expected routes unchanged without a version, got %q", got)
+	}
+}
+
+func TestFilterDefaultRoutes(t *testing.T) {
+	routes := []Route{
+		{ID: "v4-default", Subnet: "0.0.0.0/0"},
+		{ID: "v4", Subnet: "10.0.0.0/24"},
+		{ID: "v6-default", Subnet: "::/0"},
+		{ID: "v4-host", Subnet: "0.0.0.0"},
+		{ID: "bad", Subnet: "0.0.0.0/33"},
+	}
+	ids := func(routes []Route) string {
+		var result []string
+		for _, route := range routes {
+			result = append(result, route.ID)
+		}
+		return strings.Join(result, ",")
+	}
+
+	if got := ids(FilterDefaultRoutes(routes, true)); got != "v4-default,v6-default" {
+		t.Errorf("Expected only default routes, got %q", got)
+	}
+	if got := ids(FilterDefaultRoutes(routes, false)); got != "v4-default,v4,v6-default,v4-host,bad" {
+		t.Errorf("Expected routes unchanged when not filtering, got %q", got)
+	}
+}
+
+func TestExcludeNetworks(t *testing.T) {
+	networks := []Network{{ID: "N_1", Name: "Store 1"}, {ID: "N_2", Name: "Test Lab"}, {ID: "N_3", Name: "Store 3"}}
+	ids := func(networks []Network) string {
+		var result []string
+		for _, network := range networks {
+			result = append(result, network.ID)
+		}
+		return strings.Join(result, ",")
+	}
+
+	if got := ids(ExcludeNetworks("org1", networks, []string{"test lab", "N_3"})); got != "N_1" {
+		t.Errorf("Expected networks excluded by name and ID, got %q", got)
+	}
+	if got := ids(ExcludeNetworks("org1", networks, []string{"n_1", "Missing"})); got != "N_1,N_2,N_3" {
+		t.Errorf("Expected IDs to match exactly and unmatched exclusions to be ignored, got %q", got)
+	}
+	if got := ids(ExcludeNetworks("org1", networks, nil)); got != "N_1,N_2,N_3" {
+		t.Errorf("Expected networks unchanged without exclusions, got %q", got)
+	}
+}
+
+func TestFilterDevicesBySerial(t *testing.T) {
+	devices := []Device{{Serial: "Q2AA-0001"}, {Serial: "Q2BB-0002"}}
+
+	if got := FilterDevicesBySerial(devices, "Q2BB-0002"); len(got) != 1 || got[0].Serial != "Q2BB-0002" {
+		t.Errorf("Expected the matching device, got %+v", got)
+	}
+	if got := FilterDevicesBySerial(devices, "q2aa-0001"); len(got) != 1 || got[0].Serial != "Q2AA-0001" {
+		t.Errorf("Expected a case-insensitive match, got %+v", got)
+	}
+	if got := FilterDevicesBySerial(devices, "Q2CC-0003"); len(got) != 0 {
+		t.Errorf("Expected no devices for an unknown serial, got %+v", got)
+	}
+	if got := FilterDevicesBySerial(devices, ""); len(got) != 2 {
+		t.Errorf("Expected devices unchanged without a serial, got %+v", got)
+	}
+}
+
+func TestFilterLicensesByState(t *testing.T) {
+	licenses := []License{
+		{ID: "1", State: "active"},
+		{ID: "2", State: "expired"},
+		{ID: "3", State: "recentlyQueued"},
+		{ID: "4", State: "active"},
+	}
+
+	if got := FilterLicensesByState(licenses, nil); len(got) != 4 {
+		t.Errorf("Expected all licenses without states, got %d", len(got))
+	}
+
+	got := FilterLicensesByState(licenses, []string{"expired", "RECENTLYQUEUED"})
+	if len(got) != 2 || got[0].ID != "2" || got[1].ID != "3" {
+		t.Errorf("Expected licenses 2 and 3, got %+v", got)
+	}
+
+	if got := FilterLicensesByState(licenses, []string{"inactive"}); len(got) != 0 {
+		t.Errorf("Expected no inactive licenses, got %+v", got)
+	}
+}
+
+func TestClient_ResolveLicenseNetworks(t *testing.T) {
+	statusCalls := 0
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case "/organizations/org123/networks":
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`[
-				{
-					"id": "net1",
-					"name": "Network 1",
-					"productTypes": ["appliance"]
-				},
-				{
-					"id": "net2", 
-					"name": "Network 2",
-					"productTypes": ["appliance"]
-				}
-			]`))
-		case "/networks/net1/appliance/staticRoutes":
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`[
-				{
-					"id": "route1",
-					"name": "Test Route 1",
-					"subnet": "192.168.1.0/24",
-					"gatewayIp": "192.168.1.1",
-					"enabled": true
-				}
-			]`))
-		case "/networks/net1/appliance/vpn/siteToSiteVpn":
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{
-				"subnets": [
-					{
-						"localSubnet": "10.1.0.0/16",
-						"useVpn": true
-					}
-				]
-			}`))
-		case "/networks/net2/appliance/staticRoutes":
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`[
-				{
-					"id": "route2",
-					"name": "Test Route 2",
-					"subnet": "192.168.2.0/24",
-					"gatewayIp": "192.168.2.1",
-					"enabled": true
-				}
-			]`))
-		case "/networks/net2/appliance/vpn/siteToSiteVpn":
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{
-				"subnets": [
-					{
-						"localSubnet": "10.2.0.0/16",
-						"useVpn": true
-					}
-				]
-			}`))
-		case "/networks/net1/appliance/vlans":
+			w.Write([]byte(`[{"id": "net1", "name": "HQ"}, {"id": "net2", "name": "Branch"}]`))
+		case "/organizations/org123/devices/statuses":
+			statusCalls++
 			w.WriteHeader(http.StatusOK)
 			w.Write([]byte(`[
-				{
-					"id": 1,
-					"name": "Default-1",
-					"applianceIp": "172.16.1.1",
-					"subnet": "172.16.1.0/24"
-				}
+				{"serial": "Q2SW-0001", "status": "online", "networkId": "net2"},
+				{"serial": "Q2MR-0001", "status": "online", "networkId": "net1"}
 			]`))
-		case "/networks/net2/appliance/vlans":
+		case "/organizations/org123/licenses":
 			w.WriteHeader(http.StatusOK)
 			w.Write([]byte(`[
-				{
-					"id": 1,
-					"name": "Default-2",
-					"applianceIp": "172.16.2.1",
-					"subnet": "172.16.2.0/24"
-				}
+				{"id": "L1", "deviceSerial": "Q2SW-0001", "state": "active"},
+				{"id": "L2", "networkId": "net1", "state": "active"},
+				{"id": "L3", "state": "unused"}
 			]`))
-		// Switch routing endpoints for net1
-		case "/networks/net1/switch/routing/interfaces":
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`[]`))
-		case "/networks/net1/switch/routing/staticRoutes":
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`[]`))
-		case "/networks/net1/switch/stacks":
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`[]`))
-		// Switch routing endpoints for net2
-		case "/networks/net2/switch/routing/interfaces":
+		default:
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-api-key",
+	}
+
+	licenses := []License{
+		{ID: "L1", DeviceSerial: "Q2SW-0001"},
+		{ID: "L2", NetworkID: "net1"},
+		{ID: "L3", DeviceSerial: "Q2XX-UNKNOWN"},
+	}
+
+	resolved, err := client.ResolveLicenseNetworks("org123", licenses)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resolved[0].NetworkID != "net2" || resolved[0].NetworkName != "Branch" {
+		t.Errorf("Expected L1 to resolve to Branch through its device, got %s (%s)", resolved[0].NetworkName, resolved[0].NetworkID)
+	}
+	if resolved[1].NetworkName != "HQ" {
+		t.Errorf("Expected L2 to keep net1 and be named HQ, got %q", resolved[1].NetworkName)
+	}
+	if resolved[2].NetworkID != "" || resolved[2].NetworkName != "" {
+		t.Errorf("Expected L3 with an unknown device to stay unresolved, got %+v", resolved[2])
+	}
+	if licenses[0].NetworkID != "" {
+		t.Error("Expected the input licenses to be left unchanged")
+	}
+
+	t.Run("skips device lookup when every license has a network", func(t *testing.T) {
+		statusCalls = 0
+		if _, err := client.ResolveLicenseNetworks("org123", []License{{ID: "L2", NetworkID: "net1"}}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if statusCalls != 0 {
+			t.Errorf("Expected no device statuses call, got %d", statusCalls)
+		}
+	})
+
+	t.Run("GetAllNetworkLicenses attributes device licenses to their network", func(t *testing.T) {
+		networkLicenses, err := client.GetAllNetworkLicenses("org123")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		ids := make(map[string][]string)
+		for _, entry := range networkLicenses {
+			for _, license := range entry.Licenses {
+				ids[entry.Network.Name] = append(ids[entry.Network.Name], license.ID)
+			}
+		}
+		// L3 has neither a network nor a device, so it stays organization-wide
+		if strings.Join(ids["HQ"], ",") != "L2,L3" || strings.Join(ids["Branch"], ",") != "L1,L3" {
+			t.Errorf("Unexpected license attribution: %v", ids)
+		}
+	})
+}
+
+func TestClient_GetNetworkApplianceFirewallL3Rules(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/networks/N_1/appliance/firewall/l3FirewallRules":
+			w.Write([]byte(`{"rules": [
+				{"comment": "Block guests", "policy": "deny", "protocol": "tcp", "srcPort": "Any", "srcCidr": "10.2.0.0/16",
+					"destPort": "443", "destCidr": "10.1.0.0/16", "syslogEnabled": true},
+				{"comment": "Default rule", "policy": "allow", "protocol": "Any", "srcPort": "Any", "srcCidr": "Any",
+					"destPort": "Any", "destCidr": "Any", "syslogEnabled": false}]}`))
+		case "/networks/N_empty/appliance/firewall/l3FirewallRules":
+			w.Write([]byte(`{"rules": []}`))
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-api-key",
+	}
+
+	rules, err := client.GetNetworkApplianceFirewallL3Rules("N_1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("Expected the 2 rules inside the envelope, got %d", len(rules))
+	}
+	expected := FirewallRule{
+		Layer: "l3", Number: 1, Policy: "deny", Protocol: "tcp", SrcCIDR: "10.2.0.0/16", SrcPort: "Any",
+		DestCIDR: "10.1.0.0/16", DestPort: "443", Comment: "Block guests", SyslogEnabled: true,
+	}
+	if rules[0] != expected {
+		t.Errorf("Expected %+v, got %+v", expected, rules[0])
+	}
+	if rules[1].Number != 2 || rules[1].Comment != "Default rule" || rules[1].Policy != "allow" {
+		t.Errorf("Expected the default rule last, got %+v", rules[1])
+	}
+
+	rules, err = client.GetNetworkApplianceFirewallL3Rules("N_empty")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rules == nil || len(rules) != 0 {
+		t.Errorf("Expected an empty, non-nil slice for an empty rules array, got %#v", rules)
+	}
+}
+
+func TestClient_GetFirewallRules(t *testing.T) {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		switch r.URL.Path {
+		case "/organizations/org123/networks":
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`[]`))
-		case "/networks/net2/switch/routing/staticRoutes":
+			w.Write([]byte(`[
+				{"id": "net1", "name": "HQ", "productTypes": ["appliance", "switch"]},
+				{"id": "net2", "name": "Branch", "productTypes": ["wireless"]}
+			]`))
+		case "/networks/net1/appliance/firewall/l3FirewallRules":
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`[]`))
-		case "/networks/net2/switch/stacks":
+			w.Write([]byte(`{"rules": [
+				{
+					"comment": "Block guest to servers", "policy": "deny", "protocol": "tcp",
+					"srcPort": "Any", "srcCidr": "192.168.10.0/24", "destPort": "443,8443", "destCidr": "10.0.0.0/8",
+					"syslogEnabled": true
+				},
+				{
+					"comment": "Default rule", "policy": "allow", "protocol": "Any",
+					"srcPort": "Any", "srcCidr": "Any", "destPort": "Any", "destCidr": "Any",
+					"syslogEnabled": false
+				}
+			]}`))
+		case "/networks/net1/appliance/firewall/l7FirewallRules":
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`[]`))
+			w.Write([]byte(`{"rules": [
+				{"policy": "deny", "type": "host", "value": "games.example.com"},
+				{"policy": "deny", "type": "applicationCategory", "value": {"id": "meraki:layer7/category/2", "name": "Peer-to-peer (P2P)"}}
+			]}`))
 		default:
 			t.Errorf("Unexpected path: %s", r.URL.Path)
 			w.WriteHeader(http.StatusNotFound)
@@ -491,132 +3130,389 @@ func TestClient_GetAllNetworkRoutes(t *testing.T) {
 		apiKey:     "test-api-key",
 	}
 
-	allNetworkRoutes, err := client.GetAllNetworkRoutes("org123")
+	t.Run("l3 rules of appliance networks", func(t *testing.T) {
+		requested = nil
+		rules, err := client.GetFirewallRules("org123", "", false)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(rules) != 2 {
+			t.Fatalf("Expected 2 rules, got %d", len(rules))
+		}
+		first := rules[0]
+		if first.Layer != "l3" || first.Number != 1 || first.Policy != "deny" || first.Protocol != "tcp" ||
+			first.SrcCIDR != "192.168.10.0/24" || first.DestCIDR != "10.0.0.0/8" || first.DestPort != "443,8443" ||
+			first.Comment != "Block guest to servers" || !first.SyslogEnabled || first.NetworkName != "HQ" {
+			t.Errorf("Unexpected first rule: %+v", first)
+		}
+		defaultRule := rules[1]
+		if defaultRule.Number != 2 || defaultRule.Comment != "Default rule" || defaultRule.Policy != "allow" || defaultRule.SrcCIDR != "Any" {
+			t.Errorf("Expected the default rule last, got %+v", defaultRule)
+		}
+		for _, path := range requested {
+			if strings.HasPrefix(path, "/networks/net2/") || strings.HasSuffix(path, "l7FirewallRules") {
+				t.Errorf("Expected no request for %s", path)
+			}
+		}
+	})
+
+	t.Run("l7 rules on request", func(t *testing.T) {
+		rules, err := client.GetFirewallRules("org123", "HQ", true)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(rules) != 4 {
+			t.Fatalf("Expected 2 L3 and 2 L7 rules, got %d", len(rules))
+		}
+		if rules[2].Layer != "l7" || rules[2].Number != 1 || rules[2].Type != "host" || rules[2].Value != "games.example.com" {
+			t.Errorf("Unexpected host rule: %+v", rules[2])
+		}
+		if rules[3].Type != "applicationCategory" || rules[3].Value != "Peer-to-peer (P2P)" {
+			t.Errorf("Expected the category name as value, got %+v", rules[3])
+		}
+	})
+}
+
+func TestClient_GetConfigurationChanges(t *testing.T) {
+	pages := map[string]string{
+		"": `[{"ts": "2026-10-15T09:00:00Z", "adminName": "Ann", "adminEmail": "ann@example.com", "adminId": "a1",
+			"networkId": "N_1", "page": "Addressing & VLANs", "label": "VLAN", "oldValue": "{\"id\":10,\"name\":\"a, b\"}", "newValue": "{\"id\":20}"}]`,
+		"2": `[{"ts": "2026-10-15T08:00:00Z", "adminName": "Bob", "adminEmail": "bob@example.com", "adminId": "a2",
+			"networkId": "N_2", "networkName": "Branch", "page": "SSIDs", "label": "Name", "oldValue": "old", "newValue": "new"}]`,
+	}
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/organizations/org1/networks":
+			w.Write([]byte(`[{"id": "N_1", "name": "HQ"}, {"id": "N_2", "name": "Branch"}]`))
+		case "/organizations/org1/configurationChanges":
+			query := r.URL.Query()
+			if query.Get("t0") != "2026-10-01T00:00:00Z" || query.Get("perPage") != "5000" {
+				t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+			}
+			startingAfter := query.Get("startingAfter")
+			if startingAfter == "" {
+				w.Header().Set("Link", fmt.Sprintf(`<%s/organizations/org1/configurationChanges?perPage=5000&startingAfter=2>; rel=next`, server.URL))
+			}
+			w.Write([]byte(pages[startingAfter]))
+		default:
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-api-key",
+	}
+
+	query := ConfigurationChangeQuery{Since: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)}
+	changes, err := client.GetConfigurationChanges("org1", query)
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
+	if len(changes) != 2 || changes[0].AdminName != "Ann" || changes[1].AdminName != "Bob" {
+		t.Fatalf("Expected the changes of both pages, got %+v", changes)
+	}
+	if changes[0].NetworkName != "HQ" || changes[0].OldValue != `{"id":10,"name":"a, b"}` {
+		t.Errorf("Expected the network name to be resolved and values kept, got %+v", changes[0])
+	}
 
-	if len(allNetworkRoutes) != 2 {
-		t.Errorf("Expected 2 network route sets, got %d", len(allNetworkRoutes))
+	query.Admin = "BOB@example.com"
+	changes, err = client.GetConfigurationChanges("org1", query)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(changes) != 1 || changes[0].AdminName != "Bob" {
+		t.Errorf("Expected only the changes by the admin, got %+v", changes)
 	}
+}
 
-	// Check first network
-	if allNetworkRoutes[0].Network.ID != "net1" {
-		t.Errorf("Expected network ID 'net1', got '%s'", allNetworkRoutes[0].Network.ID)
+func TestClient_GetOrganizationEventLog(t *testing.T) {
+	requests := 0
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/organizations/org1/events" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		query := r.URL.Query()
+		if query.Get("productType") != "appliance" || query.Get("perPage") != "2" || query.Get("endingBefore") != "2026-10-16T00:00:00Z" {
+			t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+		}
+		if included := query["includedEventTypes[]"]; len(included) != 2 || included[0] != "vpn_connectivity_change" || included[1] != "dhcp_problem" {
+			t.Errorf("Expected both included event types, got %v", included)
+		}
+		if excluded := query["excludedEventTypes[]"]; len(excluded) != 1 || excluded[0] != "client_vpn_connect" {
+			t.Errorf("Expected the excluded event type, got %v", excluded)
+		}
+
+		switch query.Get("startingAfter") {
+		case "2026-10-15T00:00:00Z":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/organizations/org1/events?perPage=2&startingAfter=e2>; rel=next`, server.URL))
+			w.Write([]byte(`[{"occurredAt": "2026-10-15T01:00:00Z", "networkId": "N_1", "type": "vpn_connectivity_change", "description": "VPN down", "category": "Site-to-site VPN", "deviceSerial": "Q2MX-0001", "deviceName": "HQ MX"},
+				{"occurredAt": "2026-10-15T02:00:00Z", "networkId": "N_2", "type": "dhcp_problem", "description": "No leases", "category": "DHCP", "clientId": "k1", "clientMac": "00:11:22:33:44:55", "ssidNumber": 3}]`))
+		case "e2":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/organizations/org1/events?perPage=2&startingAfter=e4>; rel=next`, server.URL))
+			w.Write([]byte(`[{"occurredAt": "2026-10-15T03:00:00Z", "networkId": "N_1", "type": "vpn_connectivity_change", "description": "VPN up"},
+				{"occurredAt": "2026-10-15T04:00:00Z", "networkId": "N_1", "type": "dhcp_problem", "description": "No leases"}]`))
+		default:
+			t.Errorf("Unexpected page %s", r.URL.RawQuery)
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-api-key",
 	}
-	if len(allNetworkRoutes[0].Routes) != 3 {
-		t.Errorf("Expected 3 routes for net1, got %d", len(allNetworkRoutes[0].Routes))
+
+	params := EventLogParams{
+		ProductType:        "appliance",
+		IncludedEventTypes: []string{"vpn_connectivity_change", "dhcp_problem"},
+		ExcludedEventTypes: []string{"client_vpn_connect"},
+		StartingAfter:      time.Date(2026, 10, 15, 2, 0, 0, 0, time.FixedZone("CEST", 2*60*60)),
+		EndingBefore:       time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC),
+		PerPage:            2,
+		MaxResults:         3,
 	}
-	if allNetworkRoutes[0].Routes[0].ID != "route1" {
-		t.Errorf("Expected route ID 'route1', got '%s'", allNetworkRoutes[0].Routes[0].ID)
+	entries, err := client.GetOrganizationEventLog("org1", params)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
 	}
+	if requests != 2 {
+		t.Errorf("Expected paging to stop after the page reaching MaxResults, got %d requests", requests)
+	}
+	if len(entries) != 3 || entries[0].Description != "VPN down" || entries[2].Description != "VPN up" {
+		t.Fatalf("Expected the first three entries across both pages, got %+v", entries)
+	}
+	if entries[0].DeviceSerial != "Q2MX-0001" || entries[0].DeviceName != "HQ MX" || entries[0].Category != "Site-to-site VPN" {
+		t.Errorf("Unexpected device entry: %+v", entries[0])
+	}
+	if entries[1].ClientID != "k1" || entries[1].ClientMAC != "00:11:22:33:44:55" || entries[1].SSIDNumber != 3 || entries[1].NetworkID != "N_2" {
+		t.Errorf("Unexpected client entry: %+v", entries[1])
+	}
+}
 
-	// Check second network
-	if allNetworkRoutes[1].Network.ID != "net2" {
-		t.Errorf("Expected network ID 'net2', got '%s'", allNetworkRoutes[1].Network.ID)
+func TestClient_GetNetworkHealthAlerts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/networks/N_1/health/alerts" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`[
+			{"id": "a1", "category": "configuration", "scope": "device", "severity": "warning", "type": "Unreachable DNS",
+				"source": {"serial": "Q2AA-0001", "name": "Lobby AP", "model": "MR46"}},
+			{"id": "a2", "category": "topology", "severity": "error", "type": "Switch loop",
+				"scope": {"devices": [{"serial": "Q2SW-0001", "name": "Core", "productType": "switch"}], "applications": []}},
+			{"id": "a3", "category": "security", "severity": "informational", "type": "Rogue SSID", "scope": {"devices": []}}
+		]`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-api-key",
 	}
-	if len(allNetworkRoutes[1].Routes) != 3 {
-		t.Errorf("Expected 3 routes for net2, got %d", len(allNetworkRoutes[1].Routes))
+
+	alerts, err := client.GetNetworkHealthAlerts("N_1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
 	}
-	if allNetworkRoutes[1].Routes[0].ID != "route2" {
-		t.Errorf("Expected route ID 'route2', got '%s'", allNetworkRoutes[1].Routes[0].ID)
+	if len(alerts) != 3 {
+		t.Fatalf("Expected 3 alerts, got %+v", alerts)
+	}
+	if alerts[0].Scope != "device" || alerts[0].Source.Model != "MR46" || alerts[0].Category != "configuration" {
+		t.Errorf("Unexpected device alert: %+v", alerts[0])
+	}
+	if alerts[1].Scope != "device" || alerts[1].Source.Serial != "Q2SW-0001" || alerts[1].Severity != "error" {
+		t.Errorf("Expected the device of the scope object as source, got %+v", alerts[1])
+	}
+	if alerts[2].Scope != "network" || alerts[2].Source.Serial != "" {
+		t.Errorf("Expected a network alert without a source, got %+v", alerts[2])
 	}
 }
 
-func TestResolveOrganizationID(t *testing.T) {
-	// Mock server setup
+func TestMergeHealthAlerts(t *testing.T) {
+	devices := []Device{{Serial: "Q2AA-0001", Status: "alerting"}}
+	alerts := []HealthAlert{
+		{Severity: HealthAlertSeverityInformational, Type: "Low signal", Source: HealthAlertSource{Serial: "Q2AA-0001"}},
+		{Severity: HealthAlertSeverityError, Type: "Unreachable gateway", Source: HealthAlertSource{Serial: "Q2AA-0001"}},
+		{Severity: HealthAlertSeverityWarning, Type: "Bad cable", Source: HealthAlertSource{Serial: "Q2AA-0001"}},
+		{Severity: HealthAlertSeverityWarning, Type: "Port flapping", Source: HealthAlertSource{Serial: "Q2SW-0001", Name: "Core", Model: "MS250"}},
+		{Severity: HealthAlertSeverityError, Type: "Loop detected", Source: HealthAlertSource{Serial: "Q2SW-0001"}},
+		{Severity: HealthAlertSeverityError, Scope: "network", Type: "VPN down"},
+	}
+
+	merged := MergeHealthAlerts(devices, alerts, "N_1")
+	if len(merged) != 2 {
+		t.Fatalf("Expected one entry per device, got %+v", merged)
+	}
+	if merged[0].Serial != "Q2AA-0001" || merged[0].HealthAlert != "error: Unreachable gateway" {
+		t.Errorf("Expected the alerting device annotated with its most severe alert, got %+v", merged[0])
+	}
+	switchDevice := merged[1]
+	if switchDevice.Serial != "Q2SW-0001" || switchDevice.HealthAlert != "error: Loop detected" || switchDevice.Status != "alerting" {
+		t.Errorf("Expected the switch added with its most severe alert, got %+v", switchDevice)
+	}
+	if switchDevice.Name != "Core" || switchDevice.Model != "MS250" || switchDevice.NetworkID != "N_1" {
+		t.Errorf("Expected the switch named after the alert giving a name and model, got %+v", switchDevice)
+	}
+}
+
+func TestClient_GetOrganizationApplianceUplinkStatuses(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/organizations/org1/appliance/uplink/statuses" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		switch r.URL.Query().Get("startingAfter") {
+		case "":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/organizations/org1/appliance/uplink/statuses?perPage=1000&startingAfter=Q2MX-0001>; rel=next`, server.URL))
+			w.Write([]byte(`[{"networkId": "N_1", "serial": "Q2MX-0001", "model": "MX68", "lastReportedAt": "2026-10-16T08:00:00Z",
+				"highAvailability": {"enabled": true, "role": "primary"},
+				"uplinks": [{"interface": "wan1", "status": "active", "ip": "192.0.2.10", "gateway": "192.0.2.1", "publicIp": "198.51.100.10"},
+					{"interface": "cellular", "status": "ready", "signalStat": {"rsrp": "-95", "rsrq": "-11"}}]}]`))
+		case "Q2MX-0001":
+			w.Write([]byte(`[{"networkId": "N_2", "serial": "Q2MX-0002", "model": "Z4", "highAvailability": {"enabled": false},
+				"uplinks": [{"interface": "wan1", "status": "failed"}]}]`))
+		default:
+			t.Errorf("Unexpected page %s", r.URL.RawQuery)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-api-key",
+	}
+
+	statuses, err := client.GetOrganizationApplianceUplinkStatuses("org1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(statuses) != 2 || statuses[1].Serial != "Q2MX-0002" || statuses[1].Uplinks[0].Status != "failed" {
+		t.Fatalf("Expected the appliances of both pages, got %+v", statuses)
+	}
+	primary := statuses[0]
+	if !primary.HighAvailability.Enabled || primary.HighAvailability.Role != "primary" || len(primary.Uplinks) != 2 {
+		t.Errorf("Expected a primary appliance with two uplinks, got %+v", primary)
+	}
+	if wan1 := primary.Uplinks[0]; wan1.IP != "192.0.2.10" || wan1.Gateway != "192.0.2.1" || wan1.PublicIP != "198.51.100.10" {
+		t.Errorf("Expected the wan1 addresses, got %+v", wan1)
+	}
+	if cellular := primary.Uplinks[1]; cellular.SignalStat.Rsrp != "-95" || cellular.SignalStat.Rsrq != "-11" {
+		t.Errorf("Expected the cellular signal, got %+v", cellular)
+	}
+}
+
+func TestClient_GetDeviceLocations(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path == "/organizations" {
-			// Mock organizations response
-			response := `[
-				{"id": "org1", "name": "Test Org 1"},
-				{"id": "org2", "name": "Test Org 2"},
-				{"id": "org3", "name": "Duplicate Name"},
-				{"id": "org4", "name": "Duplicate Name"}
-			]`
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(response))
+		switch r.URL.Path {
+		case "/organizations/org1/networks":
+			w.Write([]byte(`[{"id": "N_2", "name": "Sydney"}, {"id": "N_1", "name": "HQ"}]`))
+		case "/networks/N_1/devices":
+			w.Write([]byte(`[{"serial": "Q2AA-0001", "model": "MR46", "lat": 52.37, "lng": 4.89, "address": "Dam 1"},
+				{"serial": "Q2AA-0002", "model": "MS120-8", "lat": 0, "lng": 0}]`))
+		case "/networks/N_2/devices":
+			w.Write([]byte(`[{"serial": "Q2AA-0003", "model": "MX68", "lat": -33.87, "lng": 151.21}]`))
+		default:
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
 		}
 	}))
 	defer server.Close()
 
-	// Create client with mock server
 	client := &Client{
 		httpClient: &http.Client{},
 		baseURL:    server.URL,
-		apiKey:     "test-key",
+		apiKey:     "test-api-key",
 	}
 
-	tests := []struct {
-		name          string
-		orgIdentifier string
-		expectedID    string
-		shouldErr     bool
-		errorContains string
-	}{
-		{
-			name:          "resolve by ID",
-			orgIdentifier: "org1",
-			expectedID:    "org1",
-			shouldErr:     false,
-		},
-		{
-			name:          "resolve by name",
-			orgIdentifier: "Test Org 2",
-			expectedID:    "org2",
-			shouldErr:     false,
-		},
-		{
-			name:          "resolve by name case insensitive",
-			orgIdentifier: "TEST ORG 2",
-			expectedID:    "org2",
-			shouldErr:     false,
-		},
-		{
-			name:          "empty identifier",
-			orgIdentifier: "",
-			expectedID:    "",
-			shouldErr:     false,
-		},
-		{
-			name:          "non-existent organization",
-			orgIdentifier: "Non-existent Org",
-			shouldErr:     true,
-			errorContains: "not found",
-		},
-		{
-			name:          "duplicate organization names",
-			orgIdentifier: "Duplicate Name",
-			shouldErr:     true,
-			errorContains: "multiple organizations found",
-		},
-		{
-			name:          "duplicate organization names case insensitive",
-			orgIdentifier: "duplicate name",
-			shouldErr:     true,
-			errorContains: "multiple organizations found",
-		},
+	locations, err := client.GetDeviceLocations("org1", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(locations) != 2 || locations[0].NetworkName != "HQ" || locations[1].NetworkName != "Sydney" {
+		t.Fatalf("Expected the placed devices grouped by network name, got %+v", locations)
+	}
+	expected := DeviceLocation{Serial: "Q2AA-0001", Model: "MR46", Address: "Dam 1", Lat: 52.37, Lng: 4.89,
+		NetworkID: "N_1", NetworkName: "HQ", OrganizationID: "org1"}
+	if locations[0] != expected {
+		t.Errorf("Expected %+v, got %+v", expected, locations[0])
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			orgID, err := client.ResolveOrganizationID(tt.orgIdentifier)
+	locations, err = client.GetDeviceLocations("org1", "Sydney")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(locations) != 1 || locations[0].Serial != "Q2AA-0003" {
+		t.Errorf("Expected the devices of the selected network only, got %+v", locations)
+	}
+}
 
-			if tt.shouldErr {
-				if err == nil {
-					t.Errorf("Expected error but got none")
-				} else if tt.errorContains != "" && !strings.Contains(err.Error(), tt.errorContains) {
-					t.Errorf("Expected error to contain '%s', got: %s", tt.errorContains, err.Error())
-				}
-			} else {
-				if err != nil {
-					t.Errorf("Expected no error, got: %s", err.Error())
-				}
-				if orgID != tt.expectedID {
-					t.Errorf("Expected organization ID '%s', got '%s'", tt.expectedID, orgID)
-				}
+func TestClient_GetAppliancePerformance(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/organizations/org1/networks":
+			w.Write([]byte(`[{"id": "N_1", "name": "HQ"}, {"id": "N_2", "name": "Branch"}]`))
+		case "/organizations/org1/devices/statuses":
+			w.Write([]byte(`[{"serial": "Q2MX-0001", "name": "HQ MX", "model": "MX68", "networkId": "N_1", "productType": "appliance", "status": "online"},
+				{"serial": "Q2MX-0002", "model": "MX67", "networkId": "N_2", "productType": "appliance", "status": "offline"},
+				{"serial": "Q2MR-0001", "model": "MR46", "networkId": "N_1", "productType": "wireless", "status": "online"}]`))
+		case "/organizations/org1/devices/uplinksLossAndLatency":
+			w.Write([]byte(`[{"networkId": "N_1", "serial": "Q2MX-0001", "uplink": "wan1", "ip": "8.8.8.8",
+					"timeSeries": [{"ts": "2026-10-16T08:00:00Z", "lossPercent": 0, "latencyMs": 20.5},
+						{"ts": "2026-10-16T08:01:00Z", "lossPercent": 5, "latencyMs": null}]},
+				{"networkId": "N_1", "serial": "Q2MX-0001", "uplink": "wan2", "ip": "8.8.8.8",
+					"timeSeries": [{"ts": "2026-10-16T08:00:00Z", "lossPercent": 10, "latencyMs": 45}]}]`))
+		case "/devices/Q2MX-0001/appliance/performance":
+			if r.URL.Query().Get("timespan") != "3600" {
+				t.Errorf("Expected the timespan in seconds, got %s", r.URL.RawQuery)
 			}
-		})
+			w.Write([]byte(`{"perfScore": 72.5}`))
+		case "/devices/Q2MX-0002/appliance/performance":
+			w.WriteHeader(http.StatusBadRequest)
+		default:
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		apiKey:     "test-api-key",
+	}
+
+	appliances, err := client.GetAppliancePerformance("org1", "", time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(appliances) != 2 || appliances[0].Serial != "Q2MX-0002" || appliances[1].Serial != "Q2MX-0001" {
+		t.Fatalf("Expected both appliances ordered by network name, got %+v", appliances)
+	}
+	if appliances[0].PerfScore != 0 || len(appliances[0].Uplinks) != 0 {
+		t.Errorf("Expected the offline appliance without a score or uplinks, got %+v", appliances[0])
+	}
+	hq := appliances[1]
+	if hq.PerfScore != 72.5 || hq.NetworkName != "HQ" || hq.LossPercent != 10 || hq.LatencyMs != 45 {
+		t.Errorf("Expected the score and the worst uplink loss and latency, got %+v", hq)
+	}
+	expected := []UplinkPerformance{{Interface: "wan1", IP: "8.8.8.8", LossPercent: 2.5, LatencyMs: 20.5}, {Interface: "wan2", IP: "8.8.8.8", LossPercent: 10, LatencyMs: 45}}
+	if !reflect.DeepEqual(hq.Uplinks, expected) {
+		t.Errorf("Expected uplink averages %+v, got %+v", expected, hq.Uplinks)
+	}
+
+	hot := FilterAppliancePerformance(appliances, 50)
+	if len(hot) != 1 || hot[0].Serial != "Q2MX-0001" {
+		t.Errorf("Expected only the appliance above the threshold, got %+v", hot)
 	}
 }
@@ -0,0 +1,102 @@
+package meraki
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestClient_ETagCache(t *testing.T) {
+	var conditional []string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conditional = append(conditional, r.Header.Get("If-None-Match"))
+		switch r.URL.Path {
+		case "/organizations":
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("Link", fmt.Sprintf(`<%s/organizations?startingAfter=o1>; rel=next`, server.URL))
+			w.Write([]byte(`[{"id": "o1", "name": "Org One"}]`))
+		case "/organizations/o1/networks":
+			// No ETag support: the body must never be reused
+			w.Write([]byte(`[{"id": "N_1", "name": "HQ"}]`))
+		}
+	}))
+	defer server.Close()
+
+	cacheFile := filepath.Join(t.TempDir(), "etags.json")
+	newClient := func() *Client {
+		client := &Client{
+			httpClient:  &http.Client{},
+			baseURL:     server.URL,
+			apiKey:      "test-api-key",
+			retryConfig: DefaultRetryConfig(),
+		}
+		if err := client.SetETagCache(cacheFile); err != nil {
+			t.Fatalf("SetETagCache failed: %v", err)
+		}
+		return client
+	}
+
+	// First run: the body is downloaded and cached with its ETag, and written once the run saves it
+	firstClient := newClient()
+	first, err := firstClient.makeRequest("GET", "/organizations")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	first.Body.Close()
+	if _, err := os.Stat(cacheFile); !os.IsNotExist(err) {
+		t.Fatalf("Expected the cache file to be written only when saved, got %v", err)
+	}
+	if err := firstClient.SaveETagCache(); err != nil {
+		t.Fatalf("SaveETagCache failed: %v", err)
+	}
+	if info, err := os.Stat(cacheFile); err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("Expected the cache file to be written with mode 0600, got %v (%v)", info, err)
+	}
+	if leftovers, _ := filepath.Glob(cacheFile + ".*.tmp"); len(leftovers) != 0 {
+		t.Errorf("Expected no temporary files to be left behind, got %v", leftovers)
+	}
+
+	// Second run: a 304 answer is served from the cache, Link header included
+	client := newClient()
+	resp, err := client.makeRequest("GET", "/organizations")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var orgs []Organization
+	err = json.NewDecoder(resp.Body).Decode(&orgs)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatalf("Failed to decode cached body: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || len(orgs) != 1 || orgs[0].Name != "Org One" {
+		t.Errorf("Expected the cached organizations with status 200, got %d %+v", resp.StatusCode, orgs)
+	}
+	if next := nextPageStartingAfter(resp.Header.Get("Link")); next != "o1" {
+		t.Errorf("Expected the cached Link header, got %q", resp.Header.Get("Link"))
+	}
+	if len(conditional) != 2 || conditional[0] != "" || conditional[1] != `"v1"` {
+		t.Errorf("Expected only the second request to be conditional, got %q", conditional)
+	}
+
+	// Endpoints without ETags are requested unconditionally every time
+	conditional = nil
+	for i := 0; i < 2; i++ {
+		resp, err := client.makeRequest("GET", "/organizations/o1/networks")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		resp.Body.Close()
+	}
+	if len(conditional) != 2 || conditional[0] != "" || conditional[1] != "" {
+		t.Errorf("Expected no If-None-Match without an ETag, got %q", conditional)
+	}
+}
@@ -0,0 +1,91 @@
+package meraki
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestRateLimit_ConcurrentRequests fires requests from several goroutines through one client. Run it
+// with -race to also check the shared limiter and request counter for data races.
+func TestRateLimit_ConcurrentRequests(t *testing.T) {
+	var served atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served.Add(1)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient:  &http.Client{},
+		baseURL:     server.URL,
+		apiKey:      "test-api-key",
+		retryConfig: DefaultRetryConfig(),
+	}
+
+	const (
+		rate     = 100.0 // requests per second
+		workers  = 8
+		requests = 5 // per worker
+	)
+	client.SetRateLimit(rate, 1)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < requests; j++ {
+				resp, err := client.makeRequest("GET", "/organizations")
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+					return
+				}
+				resp.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	total := workers * requests
+	if got := client.RequestCount(); got != total || served.Load() != int64(total) {
+		t.Fatalf("Expected %d requests, client counted %d and server saw %d", total, got, served.Load())
+	}
+
+	// With a burst of 1, every request after the first waits for its own token
+	minimum := time.Duration(float64(total-1) / rate * float64(time.Second))
+	if elapsed < minimum*95/100 {
+		t.Errorf("Expected %d requests at %.0f/s to take at least %v, took %v", total, rate, minimum, elapsed)
+	}
+}
+
+func TestRateLimit_Disabled(t *testing.T) {
+	client := &Client{}
+	client.SetRateLimit(10, 1)
+	if client.limiter == nil {
+		t.Fatal("Expected a limiter to be set")
+	}
+	client.SetRateLimit(0, 1)
+	if client.limiter != nil {
+		t.Error("Expected a rate of 0 to remove the limiter")
+	}
+}
+
+func TestRateLimiter_Burst(t *testing.T) {
+	limiter := newRateLimiter(1, 3)
+
+	// The first burst requests go through without waiting
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		limiter.wait()
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("Expected a burst of 3 without waiting, took %v", elapsed)
+	}
+}
@@ -1,6 +1,7 @@
 package meraki
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -132,8 +133,8 @@ func TestRetryLogic(t *testing.T) {
 		client.baseURL = server.URL
 
 		resp, err := client.makeRequest("GET", "/test")
-		if err == nil {
-			t.Error("Expected error for 404, got none")
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound for 404, got: %v", err)
 		}
 		if resp != nil {
 			t.Error("Expected no response for 404, got one")
@@ -143,6 +144,29 @@ func TestRetryLogic(t *testing.T) {
 		}
 	})
 
+	t.Run("403 forbidden is reported as access denied without retry", func(t *testing.T) {
+		attemptCount := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attemptCount++
+			w.WriteHeader(403) // Forbidden, as for an organization the API key cannot access
+		}))
+		defer server.Close()
+
+		client, err := NewClient("test-api-key")
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		client.baseURL = server.URL
+
+		_, err = client.makeRequest("GET", "/test")
+		if !errors.Is(err, ErrForbidden) {
+			t.Fatalf("Expected ErrForbidden, got: %v", err)
+		}
+		if attemptCount != 1 {
+			t.Errorf("Expected 1 attempt (no retry), got %d", attemptCount)
+		}
+	})
+
 	t.Run("exhaust all retries", func(t *testing.T) {
 		attemptCount := 0
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -176,6 +200,41 @@ func TestRetryLogic(t *testing.T) {
 			t.Errorf("Expected 3 attempts, got %d", attemptCount)
 		}
 	})
+
+	t.Run("persistent 503 reports the API as unavailable", func(t *testing.T) {
+		attemptCount := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attemptCount++
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(503) // Always unavailable, as during maintenance
+		}))
+		defer server.Close()
+
+		client, err := NewClient("test-api-key")
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		client.baseURL = server.URL
+
+		// Set short retry intervals for testing
+		client.SetRetryConfig(RetryConfig{
+			MaxRetries:      2,
+			InitialInterval: 10 * time.Millisecond,
+			MaxInterval:     100 * time.Millisecond,
+			Multiplier:      2.0,
+		})
+
+		_, err = client.makeRequest("GET", "/test")
+		if !errors.Is(err, ErrServiceUnavailable) {
+			t.Fatalf("Expected ErrServiceUnavailable, got: %v", err)
+		}
+		if expected := "Meraki API appears to be unavailable (503); try again later (Retry-After: 120)"; err.Error() != expected {
+			t.Errorf("Expected %q, got %q", expected, err.Error())
+		}
+		if attemptCount != 3 {
+			t.Errorf("Expected 503 to be retried before giving up, got %d attempts", attemptCount)
+		}
+	})
 }
 
 func TestRetryConfig(t *testing.T) {
@@ -231,25 +290,31 @@ func TestRetryConfig(t *testing.T) {
 	})
 }
 
-func TestCalculateBackoff(t *testing.T) {
+func TestRetryConfig_Backoff(t *testing.T) {
 	client, err := NewClient("test-api-key")
 	if err != nil {
 		t.Fatalf("Failed to create client: %v", err)
 	}
 
-	// Test exponential backoff
-	backoff0 := client.calculateBackoff(0)
-	backoff1 := client.calculateBackoff(1)
-	backoff2 := client.calculateBackoff(2)
-
-	if backoff0 != 1*time.Second {
-		t.Errorf("Expected backoff0=1s, got %v", backoff0)
-	}
-	if backoff1 != 1*time.Second {
-		t.Errorf("Expected backoff1=1s, got %v", backoff1)
+	// Test exponential backoff; jitter keeps each wait between 0 and the interval
+	tests := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{attempt: 0, max: 1 * time.Second},
+		{attempt: 1, max: 1 * time.Second},
+		{attempt: 2, max: 2 * time.Second},
+		{attempt: 3, max: 4 * time.Second},
 	}
-	if backoff2 != 2*time.Second {
-		t.Errorf("Expected backoff2=2s, got %v", backoff2)
+	for _, tt := range tests {
+		if interval := client.retryConfig.Interval(tt.attempt); interval != tt.max {
+			t.Errorf("Expected interval %v for attempt %d, got %v", tt.max, tt.attempt, interval)
+		}
+		for i := 0; i < 100; i++ {
+			if backoff := client.retryConfig.Backoff(tt.attempt); backoff < 0 || backoff > tt.max {
+				t.Fatalf("Expected backoff for attempt %d within [0, %v], got %v", tt.attempt, tt.max, backoff)
+			}
+		}
 	}
 
 	// Test maximum backoff cap
@@ -260,9 +325,75 @@ func TestCalculateBackoff(t *testing.T) {
 		Multiplier:      2.0,
 	})
 
-	backoff10 := client.calculateBackoff(10)
-	if backoff10 != 5*time.Second {
-		t.Errorf("Expected backoff capped at 5s, got %v", backoff10)
+	if interval := client.retryConfig.Interval(10); interval != 5*time.Second {
+		t.Errorf("Expected interval capped at 5s, got %v", interval)
+	}
+	for i := 0; i < 100; i++ {
+		if backoff := client.retryConfig.Backoff(10); backoff > 5*time.Second {
+			t.Fatalf("Expected backoff capped at 5s, got %v", backoff)
+		}
+	}
+
+	// A maximum below the initial interval also caps the first retry
+	client.SetRetryConfig(RetryConfig{InitialInterval: 1 * time.Second, MaxInterval: 200 * time.Millisecond, Multiplier: 2.0})
+	if interval := client.retryConfig.Interval(0); interval != 200*time.Millisecond {
+		t.Errorf("Expected first interval capped at 200ms, got %v", interval)
+	}
+}
+
+func TestRetryConfig_BackoffJitter(t *testing.T) {
+	client, err := NewClient("test-api-key")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	// Full jitter spreads waits across the interval instead of repeating the same value
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 50; i++ {
+		seen[client.retryConfig.Backoff(3)] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("Expected jittered backoffs to vary, got %v", seen)
+	}
+}
+
+func TestRetryConfig_RetryDelay(t *testing.T) {
+	config := RetryConfig{InitialInterval: time.Second, MaxInterval: 30 * time.Second, Multiplier: 2.0}
+	response := func(status int, retryAfter string) *http.Response {
+		resp := &http.Response{StatusCode: status, Header: http.Header{}}
+		if retryAfter != "" {
+			resp.Header.Set("Retry-After", retryAfter)
+		}
+		return resp
+	}
+
+	tests := []struct {
+		name string
+		resp *http.Response
+		want time.Duration
+	}{
+		{"429 with Retry-After seconds", response(429, "2"), 2 * time.Second},
+		{"503 with Retry-After seconds", response(503, "0"), 0},
+		{"Retry-After capped at the maximum interval", response(429, "120"), 30 * time.Second},
+		{"Retry-After in the past", response(429, time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat)), 0},
+	}
+	for _, tt := range tests {
+		if delay := config.RetryDelay(0, tt.resp); delay != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, delay)
+		}
+	}
+
+	// An HTTP date is converted to the time remaining
+	date := time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat)
+	if delay := config.RetryDelay(0, response(429, date)); delay <= 8*time.Second || delay > 10*time.Second {
+		t.Errorf("Expected about 10s for an HTTP date, got %v", delay)
+	}
+
+	// Without a usable Retry-After, and for other statuses and network errors, the jittered backoff is used
+	for _, resp := range []*http.Response{response(429, ""), response(429, "soon"), response(500, "5"), nil} {
+		if delay := config.RetryDelay(2, resp); delay < 0 || delay > 2*time.Second {
+			t.Errorf("Expected a backoff within [0, 2s], got %v", delay)
+		}
 	}
 }
 
@@ -0,0 +1,172 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"meraki-info/internal/meraki"
+)
+
+// AccessXML represents the access information in XML format
+type AccessXML struct {
+	XMLName       xml.Name                `xml:"access"`
+	Organizations []OrganizationAccessXML `xml:"organization"`
+}
+
+// OrganizationAccessXML represents a single accessible organization in XML format
+type OrganizationAccessXML struct {
+	ID             string                  `xml:"id"`
+	Name           string                  `xml:"name"`
+	URL            string                  `xml:"url,omitempty"`
+	APIEnabled     bool                    `xml:"apiEnabled"`
+	LicensingModel string                  `xml:"licensingModel"`
+	Region         string                  `xml:"region"`
+	RegionHost     string                  `xml:"regionHost"`
+	Devices        *DeviceStatusSummaryXML `xml:"devices,omitempty"`
+	Networks       []NetworkXML            `xml:"networks>network"`
+	Error          string                  `xml:"error,omitempty"`
+}
+
+// NetworkXML represents a single network in XML format
+type NetworkXML struct {
+	ID           string   `xml:"id"`
+	Name         string   `xml:"name"`
+	ProductTypes []string `xml:"productTypes>productType,omitempty"`
+	TimeZone     string   `xml:"timeZone,omitempty"`
+	Tags         []string `xml:"tags>tag,omitempty"`
+}
+
+// writeAccessInfo writes the access information to an io.Writer as a plain text listing
+func (w *TextWriter) writeAccessInfo(access meraki.AccessInfo, writer io.Writer) error {
+	fmt.Fprintf(writer, "Access Information\n")
+	fmt.Fprintf(writer, "==================\n\n")
+
+	for _, org := range access.Organizations {
+		fmt.Fprintf(writer, "Organization: %s (%s)\n", org.Name, org.ID)
+		fmt.Fprintf(writer, "  API: %s\n", accessAPIStatus(org.APIEnabled))
+		fmt.Fprintf(writer, "  Licensing: %s\n", org.LicensingModel)
+		fmt.Fprintf(writer, "  Region: %s (%s)\n", org.Region, org.RegionHost)
+		if org.Devices != nil {
+			fmt.Fprintf(writer, "  Devices: %d online, %d alerting, %d offline, %d dormant\n",
+				org.Devices.Online, org.Devices.Alerting, org.Devices.Offline, org.Devices.Dormant)
+		}
+		if org.Error != "" {
+			fmt.Fprintf(writer, "  Error: %s\n", org.Error)
+		}
+		for _, network := range org.Networks {
+			fmt.Fprintf(writer, "  Network: %s (%s)\n", network.Name, network.ID)
+		}
+		if _, err := fmt.Fprintf(writer, "\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeAccessInfoXML writes the access information to an io.Writer in XML format
+func (w *XMLWriter) writeAccessInfoXML(access meraki.AccessInfo, writer io.Writer) error {
+	// Convert organizations to XML-compatible format
+	accessXML := AccessXML{Organizations: make([]OrganizationAccessXML, len(access.Organizations))}
+	for i, org := range access.Organizations {
+		orgXML := OrganizationAccessXML{
+			ID:             org.ID,
+			Name:           org.Name,
+			URL:            org.URL,
+			APIEnabled:     org.APIEnabled,
+			LicensingModel: org.LicensingModel,
+			Region:         org.Region,
+			RegionHost:     org.RegionHost,
+			Networks:       make([]NetworkXML, len(org.Networks)),
+			Error:          org.Error,
+		}
+		if org.Devices != nil {
+			orgXML.Devices = &DeviceStatusSummaryXML{
+				Scope:    org.Devices.Scope,
+				Online:   org.Devices.Online,
+				Offline:  org.Devices.Offline,
+				Alerting: org.Devices.Alerting,
+				Dormant:  org.Devices.Dormant,
+				Total:    org.Devices.Total,
+			}
+		}
+		for j, network := range org.Networks {
+			orgXML.Networks[j] = NetworkXML{
+				ID:           network.ID,
+				Name:         network.Name,
+				ProductTypes: network.ProductTypes,
+				TimeZone:     network.TimeZone,
+				Tags:         network.Tags,
+			}
+		}
+		accessXML.Organizations[i] = orgXML
+	}
+
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", w.indent())
+
+	// Write XML header
+	fmt.Fprint(writer, xml.Header)
+
+	if err := encoder.Encode(accessXML); err != nil {
+		return fmt.Errorf("failed to encode XML: %w", err)
+	}
+
+	return nil
+}
+
+// writeAccessInfoCSV writes the access information to an io.Writer in CSV format, one row per
+// network. Organizations without networks get a single row with empty network columns.
+func (w *CSVWriter) writeAccessInfoCSV(access meraki.AccessInfo, writer io.Writer) error {
+	csvWriter := w.newCSVWriter(writer)
+	defer csvWriter.Flush()
+
+	// Write header
+	header := []string{"Organization ID", "Organization Name", "API Enabled", "Licensing Model", "Region",
+		"Online", "Alerting", "Offline", "Dormant", "Network ID", "Network Name", "Product Types", "Time Zone", "Error"}
+	if err := csvWriter.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, org := range access.Organizations {
+		orgColumns := []string{org.ID, org.Name, fmt.Sprintf("%t", org.APIEnabled), org.LicensingModel, org.Region}
+		if org.Devices != nil {
+			orgColumns = append(orgColumns,
+				fmt.Sprintf("%d", org.Devices.Online),
+				fmt.Sprintf("%d", org.Devices.Alerting),
+				fmt.Sprintf("%d", org.Devices.Offline),
+				fmt.Sprintf("%d", org.Devices.Dormant))
+		} else {
+			orgColumns = append(orgColumns, "", "", "", "")
+		}
+
+		networks := org.Networks
+		if len(networks) == 0 {
+			networks = []meraki.Network{{}}
+		}
+		for _, network := range networks {
+			record := append(append([]string{}, orgColumns...),
+				network.ID,
+				network.Name,
+				strings.Join(network.ProductTypes, ";"),
+				network.TimeZone,
+				org.Error,
+			)
+			if err := csvWriter.Write(record); err != nil {
+				return fmt.Errorf("failed to write CSV record: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// accessAPIStatus formats whether API access is enabled for an organization
+func accessAPIStatus(enabled bool) string {
+	if enabled {
+		return "Enabled"
+	}
+	return "Disabled"
+}
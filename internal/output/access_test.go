@@ -0,0 +1,94 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"meraki-info/internal/meraki"
+)
+
+func TestWriters_AccessInfo(t *testing.T) {
+	access := meraki.AccessInfo{Organizations: []meraki.OrganizationAccess{
+		{
+			ID:             "1",
+			Name:           "Org A",
+			APIEnabled:     true,
+			LicensingModel: "co-term",
+			Region:         "North America",
+			RegionHost:     "United States",
+			Devices:        &meraki.DeviceStatusSummary{Scope: "total", Online: 10, Alerting: 1, Offline: 2, Total: 13},
+			Networks: []meraki.Network{
+				{ID: "N_1", Name: "Branch 1", ProductTypes: []string{"appliance", "switch"}, TimeZone: "America/Chicago"},
+				{ID: "N_2", Name: "Branch 2"},
+			},
+		},
+		{ID: "2", Name: "Org B", Networks: []meraki.Network{}, Error: "403 Forbidden"},
+	}}
+
+	t.Run("text", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := (&TextWriter{}).WriteTo(access, &buf); err != nil {
+			t.Fatalf("WriteTo failed: %v", err)
+		}
+		output := buf.String()
+		for _, expected := range []string{"Organization: Org A (1)", "Devices: 10 online, 1 alerting, 2 offline, 0 dormant", "Network: Branch 2 (N_2)", "Error: 403 Forbidden"} {
+			if !strings.Contains(output, expected) {
+				t.Errorf("Expected text output to contain %q, got:\n%s", expected, output)
+			}
+		}
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := (&CSVWriter{}).WriteTo(access, &buf); err != nil {
+			t.Fatalf("WriteTo failed: %v", err)
+		}
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 4 {
+			t.Fatalf("Expected header, 2 network rows and 1 row for the organization without networks, got %d lines", len(lines))
+		}
+		if lines[1] != "1,Org A,true,co-term,North America,10,1,2,0,N_1,Branch 1,appliance;switch,America/Chicago," {
+			t.Errorf("Unexpected network row: %s", lines[1])
+		}
+		if lines[3] != "2,Org B,false,,,,,,,,,,,403 Forbidden" {
+			t.Errorf("Unexpected row for organization without networks: %s", lines[3])
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := (&JSONWriter{}).WriteTo(access, &buf); err != nil {
+			t.Fatalf("WriteTo failed: %v", err)
+		}
+		var decoded meraki.AccessInfo
+		if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("Failed to parse JSON: %v", err)
+		}
+		if len(decoded.Organizations) != 2 || len(decoded.Organizations[0].Networks) != 2 || decoded.Organizations[0].Devices.Online != 10 {
+			t.Errorf("Unexpected decoded access info: %+v", decoded)
+		}
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := (&XMLWriter{}).WriteTo(access, &buf); err != nil {
+			t.Fatalf("WriteTo failed: %v", err)
+		}
+		var decoded AccessXML
+		if err := xml.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("Failed to parse XML: %v", err)
+		}
+		if len(decoded.Organizations) != 2 || len(decoded.Organizations[0].Networks) != 2 {
+			t.Fatalf("Unexpected decoded access info: %+v", decoded)
+		}
+		if got := decoded.Organizations[0].Networks[0].ProductTypes; len(got) != 2 || got[1] != "switch" {
+			t.Errorf("Unexpected product types: %v", got)
+		}
+		if decoded.Organizations[1].Error != "403 Forbidden" || decoded.Organizations[1].Devices != nil {
+			t.Errorf("Unexpected organization without networks: %+v", decoded.Organizations[1])
+		}
+	})
+}
@@ -0,0 +1,197 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"meraki-info/internal/meraki"
+)
+
+// APIUsageReportXML represents the API usage of several organizations in XML format
+type APIUsageReportXML struct {
+	XMLName       xml.Name      `xml:"apiUsage"`
+	Organizations []APIUsageXML `xml:"organization"`
+}
+
+// APIUsageXML represents the API usage of a single organization in XML format
+type APIUsageXML struct {
+	OrganizationID  string          `xml:"organizationId"`
+	Organization    string          `xml:"organization"`
+	TimespanSeconds int             `xml:"timespanSeconds"`
+	Since           string          `xml:"since"`
+	Until           string          `xml:"until"`
+	TotalRequests   int             `xml:"totalRequests"`
+	RateLimited     int             `xml:"rateLimited"`
+	ResponseCodes   []UsageCountXML `xml:"responseCodes>responseCode"`
+	TopAdmins       []UsageCountXML `xml:"topAdmins>admin,omitempty"`
+	TopUserAgents   []UsageCountXML `xml:"topUserAgents>userAgent,omitempty"`
+	Requests        []APIRequestXML `xml:"requests>request,omitempty"`
+}
+
+// UsageCountXML represents a request count for one response code, admin or user agent in XML format
+type UsageCountXML struct {
+	Key   string `xml:"key,attr"`
+	Count int    `xml:",chardata"`
+}
+
+// APIRequestXML represents a single API request log entry in XML format
+type APIRequestXML struct {
+	Ts           string `xml:"ts"`
+	AdminID      string `xml:"adminId"`
+	Method       string `xml:"method"`
+	Path         string `xml:"path"`
+	QueryString  string `xml:"queryString,omitempty"`
+	ResponseCode int    `xml:"responseCode"`
+	UserAgent    string `xml:"userAgent,omitempty"`
+	SourceIP     string `xml:"sourceIp,omitempty"`
+}
+
+// writeAPIUsage writes the API usage of each organization to an io.Writer in text format
+func (w *TextWriter) writeAPIUsage(usages []meraki.APIUsage, writer io.Writer) error {
+	fmt.Fprintf(writer, "Meraki API Usage\n")
+	fmt.Fprintf(writer, "================\n\n")
+
+	for _, usage := range usages {
+		fmt.Fprintf(writer, "Organization: %s (%s)\n", usage.Organization, usage.OrganizationID)
+		fmt.Fprintf(writer, "  Timespan: %s (%s to %s)\n", time.Duration(usage.TimespanSeconds)*time.Second, usage.Since, usage.Until)
+		fmt.Fprintf(writer, "  Total Requests: %d\n", usage.TotalRequests)
+		fmt.Fprintf(writer, "  Rate Limited (429): %d\n", usage.RateLimited)
+		writeUsageCounts(writer, "Response Codes", usage.ResponseCodes)
+		writeUsageCounts(writer, "Top Admins", usage.TopAdmins)
+		writeUsageCounts(writer, "Top User Agents", usage.TopUserAgents)
+		if len(usage.Requests) > 0 {
+			fmt.Fprintf(writer, "  Requests:\n")
+			for _, request := range usage.Requests {
+				fmt.Fprintf(writer, "    %s %s %s -> %d (admin %s, %s)\n",
+					request.Ts, request.Method, request.Path, request.ResponseCode, request.AdminID, request.UserAgent)
+			}
+		}
+		if _, err := fmt.Fprintf(writer, "\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeUsageCounts writes a titled list of request counts, or nothing when there are none
+func writeUsageCounts(writer io.Writer, title string, counts []meraki.UsageCount) {
+	if len(counts) == 0 {
+		return
+	}
+	fmt.Fprintf(writer, "  %s:\n", title)
+	for _, count := range counts {
+		fmt.Fprintf(writer, "    %s: %d\n", count.Key, count.Count)
+	}
+}
+
+// writeAPIUsageXML writes the API usage of each organization to an io.Writer in XML format
+func (w *XMLWriter) writeAPIUsageXML(usages []meraki.APIUsage, writer io.Writer) error {
+	// Convert usage reports to XML-compatible format
+	report := APIUsageReportXML{Organizations: make([]APIUsageXML, len(usages))}
+	for i, usage := range usages {
+		usageXML := APIUsageXML{
+			OrganizationID:  usage.OrganizationID,
+			Organization:    usage.Organization,
+			TimespanSeconds: usage.TimespanSeconds,
+			Since:           usage.Since,
+			Until:           usage.Until,
+			TotalRequests:   usage.TotalRequests,
+			RateLimited:     usage.RateLimited,
+			ResponseCodes:   usageCountsXML(usage.ResponseCodes),
+			TopAdmins:       usageCountsXML(usage.TopAdmins),
+			TopUserAgents:   usageCountsXML(usage.TopUserAgents),
+		}
+		for _, request := range usage.Requests {
+			usageXML.Requests = append(usageXML.Requests, APIRequestXML{
+				Ts:           request.Ts,
+				AdminID:      request.AdminID,
+				Method:       request.Method,
+				Path:         request.Path,
+				QueryString:  request.QueryString,
+				ResponseCode: request.ResponseCode,
+				UserAgent:    request.UserAgent,
+				SourceIP:     request.SourceIP,
+			})
+		}
+		report.Organizations[i] = usageXML
+	}
+
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", w.indent())
+
+	// Write XML header
+	fmt.Fprint(writer, xml.Header)
+
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("failed to encode XML: %w", err)
+	}
+
+	return nil
+}
+
+// usageCountsXML converts request counts to XML-compatible format
+func usageCountsXML(counts []meraki.UsageCount) []UsageCountXML {
+	var countsXML []UsageCountXML
+	for _, count := range counts {
+		countsXML = append(countsXML, UsageCountXML{Key: count.Key, Count: count.Count})
+	}
+	return countsXML
+}
+
+// writeAPIUsageCSV writes the API usage of each organization to an io.Writer in CSV format. Every
+// row carries the organization and timespan; the Record column tells the rows apart: total,
+// responseCode, admin and userAgent rows fill Key and Count, request rows the request columns.
+func (w *CSVWriter) writeAPIUsageCSV(usages []meraki.APIUsage, writer io.Writer) error {
+	csvWriter := w.newCSVWriter(writer)
+	defer csvWriter.Flush()
+
+	// Write header
+	header := []string{"Organization ID", "Organization Name", "Timespan Seconds", "Since", "Until", "Record", "Key", "Count",
+		"Timestamp", "Admin ID", "Method", "Path", "Response Code", "User Agent", "Source IP"}
+	if err := csvWriter.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, usage := range usages {
+		orgColumns := []string{usage.OrganizationID, usage.Organization, fmt.Sprintf("%d", usage.TimespanSeconds), usage.Since, usage.Until}
+		row := func(columns ...string) error {
+			record := append(append([]string{}, orgColumns...), columns...)
+			for len(record) < len(header) {
+				record = append(record, "")
+			}
+			if err := csvWriter.Write(record); err != nil {
+				return fmt.Errorf("failed to write CSV record: %w", err)
+			}
+			return nil
+		}
+
+		if err := row("total", "", fmt.Sprintf("%d", usage.TotalRequests)); err != nil {
+			return err
+		}
+		for _, group := range []struct {
+			record string
+			counts []meraki.UsageCount
+		}{
+			{"responseCode", usage.ResponseCodes},
+			{"admin", usage.TopAdmins},
+			{"userAgent", usage.TopUserAgents},
+		} {
+			for _, count := range group.counts {
+				if err := row(group.record, count.Key, fmt.Sprintf("%d", count.Count)); err != nil {
+					return err
+				}
+			}
+		}
+		for _, request := range usage.Requests {
+			if err := row("request", "", "", request.Ts, request.AdminID, request.Method, request.Path,
+				fmt.Sprintf("%d", request.ResponseCode), request.UserAgent, request.SourceIP); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
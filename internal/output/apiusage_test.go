@@ -0,0 +1,112 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"meraki-info/internal/meraki"
+)
+
+func TestWriters_APIUsage(t *testing.T) {
+	usages := []meraki.APIUsage{
+		{
+			Organization:    "Org A",
+			OrganizationID:  "1",
+			TimespanSeconds: 86400,
+			Since:           "2025-07-16T12:00:00Z",
+			Until:           "2025-07-17T12:00:00Z",
+			TotalRequests:   120,
+			RateLimited:     20,
+			ResponseCodes:   []meraki.UsageCount{{Key: "200", Count: 100}, {Key: "429", Count: 20}},
+			TopAdmins:       []meraki.UsageCount{{Key: "admin1", Count: 2}},
+			TopUserAgents:   []meraki.UsageCount{{Key: "python-requests", Count: 2}},
+			Requests: []meraki.APIRequest{
+				{Ts: "2025-07-17T11:00:00Z", AdminID: "admin1", Method: "GET", Path: "/api/v1/organizations", ResponseCode: 429, UserAgent: "python-requests"},
+				{Ts: "2025-07-17T11:00:01Z", AdminID: "admin1", Method: "GET", Path: "/api/v1/organizations", ResponseCode: 200, UserAgent: "python-requests"},
+			},
+		},
+		{Organization: "Org B", OrganizationID: "2", TimespanSeconds: 86400, ResponseCodes: []meraki.UsageCount{}},
+	}
+
+	t.Run("text", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := (&TextWriter{}).WriteTo(usages, &buf); err != nil {
+			t.Fatalf("WriteTo failed: %v", err)
+		}
+		output := buf.String()
+		for _, expected := range []string{
+			"Organization: Org A (1)",
+			"Timespan: 24h0m0s (2025-07-16T12:00:00Z to 2025-07-17T12:00:00Z)",
+			"Rate Limited (429): 20",
+			"    429: 20",
+			"Top User Agents:\n    python-requests: 2",
+			"2025-07-17T11:00:00Z GET /api/v1/organizations -> 429 (admin admin1, python-requests)",
+			"Organization: Org B (2)",
+		} {
+			if !strings.Contains(output, expected) {
+				t.Errorf("Expected text output to contain %q, got:\n%s", expected, output)
+			}
+		}
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := (&CSVWriter{}).WriteTo(usages, &buf); err != nil {
+			t.Fatalf("WriteTo failed: %v", err)
+		}
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		// Org A: total, 2 response codes, 1 admin, 1 user agent, 2 requests; Org B: total
+		if len(lines) != 9 {
+			t.Fatalf("Expected header and 8 rows, got %d lines:\n%s", len(lines), buf.String())
+		}
+		if lines[3] != "1,Org A,86400,2025-07-16T12:00:00Z,2025-07-17T12:00:00Z,responseCode,429,20,,,,,,," {
+			t.Errorf("Unexpected response code row: %s", lines[3])
+		}
+		if lines[6] != "1,Org A,86400,2025-07-16T12:00:00Z,2025-07-17T12:00:00Z,request,,,2025-07-17T11:00:00Z,admin1,GET,/api/v1/organizations,429,python-requests," {
+			t.Errorf("Unexpected request row: %s", lines[6])
+		}
+		if lines[8] != "2,Org B,86400,,,total,,0,,,,,,," {
+			t.Errorf("Unexpected total row: %s", lines[9])
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := (&JSONWriter{}).WriteTo(usages, &buf); err != nil {
+			t.Fatalf("WriteTo failed: %v", err)
+		}
+		var decoded []meraki.APIUsage
+		if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("Failed to parse JSON: %v", err)
+		}
+		if len(decoded) != 2 || decoded[0].TimespanSeconds != 86400 || decoded[0].RateLimited != 20 || len(decoded[0].Requests) != 2 {
+			t.Errorf("Unexpected decoded API usage: %+v", decoded)
+		}
+		if strings.Contains(buf.String(), `"requests": null`) || decoded[1].Requests != nil {
+			t.Errorf("Expected no requests for a summary-only report, got:\n%s", buf.String())
+		}
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := (&XMLWriter{}).WriteTo(usages, &buf); err != nil {
+			t.Fatalf("WriteTo failed: %v", err)
+		}
+		var decoded APIUsageReportXML
+		if err := xml.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("Failed to parse XML: %v", err)
+		}
+		if len(decoded.Organizations) != 2 || decoded.Organizations[0].TimespanSeconds != 86400 {
+			t.Fatalf("Unexpected decoded API usage: %+v", decoded)
+		}
+		if got := decoded.Organizations[0].ResponseCodes; len(got) != 2 || got[1].Key != "429" || got[1].Count != 20 {
+			t.Errorf("Unexpected response codes: %+v", got)
+		}
+		if len(decoded.Organizations[0].Requests) != 2 || len(decoded.Organizations[1].Requests) != 0 {
+			t.Errorf("Unexpected requests: %+v", decoded.Organizations)
+		}
+	})
+}
@@ -0,0 +1,141 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"meraki-info/internal/meraki"
+)
+
+// ConfigurationChangesXML represents a collection of configuration changes in XML format
+type ConfigurationChangesXML struct {
+	XMLName xml.Name                 `xml:"configurationChanges"`
+	Changes []ConfigurationChangeXML `xml:"change"`
+}
+
+// ConfigurationChangeXML represents a single configuration change in XML format
+type ConfigurationChangeXML struct {
+	TS             string `xml:"ts"`
+	AdminName      string `xml:"adminName"`
+	AdminEmail     string `xml:"adminEmail"`
+	AdminID        string `xml:"adminId,omitempty"`
+	Page           string `xml:"page"`
+	Label          string `xml:"label"`
+	OldValue       string `xml:"oldValue"`
+	NewValue       string `xml:"newValue"`
+	SSIDName       string `xml:"ssidName,omitempty"`
+	NetworkID      string `xml:"networkId,omitempty"`
+	NetworkName    string `xml:"networkName,omitempty"`
+	Organization   string `xml:"organization,omitempty"`
+	OrganizationID string `xml:"organizationId,omitempty"`
+}
+
+// writeConfigurationChanges writes configuration changes to an io.Writer in text format
+func (w *TextWriter) writeConfigurationChanges(changes []meraki.ConfigurationChangeWithOrganization, writer io.Writer) error {
+	// Write header
+	fmt.Fprintf(writer, "Meraki Configuration Changes\n")
+	fmt.Fprintf(writer, "============================\n\n")
+	fmt.Fprintf(writer, "Total Changes: %d\n\n", len(changes))
+
+	// Write changes
+	for i, change := range changes {
+		fmt.Fprintf(writer, "Change %d:\n", i+1)
+		fmt.Fprintf(writer, "  Time: %s\n", change.TS)
+		fmt.Fprintf(writer, "  Admin: %s (%s)\n", change.AdminName, change.AdminEmail)
+		fmt.Fprintf(writer, "  Page: %s\n", change.Page)
+		fmt.Fprintf(writer, "  Label: %s\n", change.Label)
+		fmt.Fprintf(writer, "  Old Value: %s\n", change.OldValue)
+		fmt.Fprintf(writer, "  New Value: %s\n", change.NewValue)
+		if change.SSIDName != "" {
+			fmt.Fprintf(writer, "  SSID: %s\n", change.SSIDName)
+		}
+		if change.Organization != "" {
+			fmt.Fprintf(writer, "  Organization: %s\n", change.Organization)
+		}
+		if change.NetworkID != "" {
+			fmt.Fprintf(writer, "  Network Name: %s\n", labelOrID(change.NetworkName, change.NetworkID))
+			fmt.Fprintf(writer, "  Network ID: %s\n", change.NetworkID)
+		}
+		if _, err := fmt.Fprintf(writer, "\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeConfigurationChangesXML writes configuration changes to an io.Writer in XML format
+func (w *XMLWriter) writeConfigurationChangesXML(changes []meraki.ConfigurationChangeWithOrganization, writer io.Writer) error {
+	// Convert changes to XML-compatible format
+	xmlChanges := make([]ConfigurationChangeXML, len(changes))
+	for i, change := range changes {
+		xmlChanges[i] = ConfigurationChangeXML{
+			TS:             change.TS,
+			AdminName:      change.AdminName,
+			AdminEmail:     change.AdminEmail,
+			AdminID:        change.AdminID,
+			Page:           change.Page,
+			Label:          change.Label,
+			OldValue:       change.OldValue,
+			NewValue:       change.NewValue,
+			SSIDName:       change.SSIDName,
+			NetworkID:      change.NetworkID,
+			NetworkName:    change.NetworkName,
+			Organization:   change.Organization,
+			OrganizationID: change.OrganizationID,
+		}
+	}
+
+	changesXML := ConfigurationChangesXML{Changes: xmlChanges}
+
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", w.indent())
+
+	// Write XML header
+	fmt.Fprint(writer, xml.Header)
+
+	if err := encoder.Encode(changesXML); err != nil {
+		return fmt.Errorf("failed to encode XML: %w", err)
+	}
+
+	return nil
+}
+
+// writeConfigurationChangesCSV writes configuration changes to an io.Writer in CSV format. Old and
+// new values are often JSON with commas, quotes and newlines; the CSV encoder quotes them so each
+// change stays one record.
+func (w *CSVWriter) writeConfigurationChangesCSV(changes []meraki.ConfigurationChangeWithOrganization, writer io.Writer) error {
+	csvWriter := w.newCSVWriter(writer)
+	defer csvWriter.Flush()
+
+	// Write header
+	header := []string{"Organization", "Organization ID", "Network ID", "Network Name", "Time", "Admin Name", "Admin Email", "Admin ID", "Page", "Label", "SSID", "Old Value", "New Value"}
+	if err := csvWriter.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	// Write changes
+	for _, change := range changes {
+		record := []string{
+			change.Organization,
+			change.OrganizationID,
+			change.NetworkID,
+			change.NetworkName,
+			change.TS,
+			change.AdminName,
+			change.AdminEmail,
+			change.AdminID,
+			change.Page,
+			change.Label,
+			change.SSIDName,
+			change.OldValue,
+			change.NewValue,
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"meraki-info/internal/meraki"
+)
+
+// DevicesDetailsXML represents a collection of device details in XML format
+type DevicesDetailsXML struct {
+	XMLName xml.Name           `xml:"devices"`
+	Devices []DeviceDetailsXML `xml:"device"`
+}
+
+// DeviceDetailsXML represents the details of a single device in XML format
+type DeviceDetailsXML struct {
+	Serial         string   `xml:"serial"`
+	Name           string   `xml:"name,omitempty"`
+	Model          string   `xml:"model"`
+	MAC            string   `xml:"mac,omitempty"`
+	ProductType    string   `xml:"productType,omitempty"`
+	Firmware       string   `xml:"firmware,omitempty"`
+	LanIP          string   `xml:"lanIp,omitempty"`
+	WAN1IP         string   `xml:"wan1Ip,omitempty"`
+	WAN2IP         string   `xml:"wan2Ip,omitempty"`
+	PublicIP       string   `xml:"publicIp,omitempty"`
+	Tags           []string `xml:"tags>tag,omitempty"`
+	Address        string   `xml:"address,omitempty"`
+	Lat            float64  `xml:"lat,omitempty"`
+	Lng            float64  `xml:"lng,omitempty"`
+	Notes          string   `xml:"notes,omitempty"`
+	Status         string   `xml:"status,omitempty"`
+	LastReportedAt string   `xml:"lastReportedAt,omitempty"`
+	NetworkID      string   `xml:"networkId,omitempty"`
+	NetworkName    string   `xml:"networkName,omitempty"`
+	OrganizationID string   `xml:"organizationId,omitempty"`
+}
+
+// writeDeviceDetails writes device details to an io.Writer in text format
+func (w *TextWriter) writeDeviceDetails(devices []meraki.DeviceDetails, writer io.Writer) error {
+	// Write header
+	fmt.Fprintf(writer, "Meraki Device Details\n")
+	fmt.Fprintf(writer, "=====================\n\n")
+
+	// Write devices
+	for _, device := range devices {
+		fmt.Fprintf(writer, "Serial: %s\n", device.Serial)
+		if device.Name != "" {
+			fmt.Fprintf(writer, "  Name: %s\n", device.Name)
+		}
+		fmt.Fprintf(writer, "  Model: %s\n", device.Model)
+		if device.MAC != "" {
+			fmt.Fprintf(writer, "  MAC: %s\n", device.MAC)
+		}
+		if device.Firmware != "" {
+			fmt.Fprintf(writer, "  Firmware: %s\n", device.Firmware)
+		}
+		if device.LanIP != "" {
+			fmt.Fprintf(writer, "  LAN IP: %s\n", device.LanIP)
+		}
+		if device.WAN1IP != "" {
+			fmt.Fprintf(writer, "  WAN 1 IP: %s\n", device.WAN1IP)
+		}
+		if device.WAN2IP != "" {
+			fmt.Fprintf(writer, "  WAN 2 IP: %s\n", device.WAN2IP)
+		}
+		if device.PublicIP != "" {
+			fmt.Fprintf(writer, "  Public IP: %s\n", device.PublicIP)
+		}
+		if len(device.Tags) > 0 {
+			fmt.Fprintf(writer, "  Tags: %s\n", strings.Join(device.Tags, ", "))
+		}
+		if device.Address != "" {
+			fmt.Fprintf(writer, "  Address: %s\n", device.Address)
+		}
+		if device.Lat != 0 || device.Lng != 0 {
+			fmt.Fprintf(writer, "  Location: %g, %g\n", device.Lat, device.Lng)
+		}
+		if device.Notes != "" {
+			fmt.Fprintf(writer, "  Notes: %s\n", device.Notes)
+		}
+		if device.NetworkID != "" {
+			fmt.Fprintf(writer, "  Network: %s\n", labelOrID(device.NetworkName, device.NetworkID))
+			fmt.Fprintf(writer, "  Network ID: %s\n", device.NetworkID)
+		} else {
+			fmt.Fprintf(writer, "  Network: (unassigned)\n")
+		}
+		if device.Status != "" {
+			fmt.Fprintf(writer, "  Status: %s\n", device.Status)
+		}
+		if device.LastReportedAt != "" {
+			fmt.Fprintf(writer, "  Last Reported: %s\n", device.LastReportedAt)
+		}
+		if _, err := fmt.Fprintf(writer, "\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeDeviceDetailsXML writes device details to an io.Writer in XML format
+func (w *XMLWriter) writeDeviceDetailsXML(devices []meraki.DeviceDetails, writer io.Writer) error {
+	// Convert devices to XML-compatible format
+	xmlDevices := make([]DeviceDetailsXML, len(devices))
+	for i, device := range devices {
+		xmlDevices[i] = DeviceDetailsXML{
+			Serial:         device.Serial,
+			Name:           device.Name,
+			Model:          device.Model,
+			MAC:            device.MAC,
+			ProductType:    device.ProductType,
+			Firmware:       device.Firmware,
+			LanIP:          device.LanIP,
+			WAN1IP:         device.WAN1IP,
+			WAN2IP:         device.WAN2IP,
+			PublicIP:       device.PublicIP,
+			Tags:           device.Tags,
+			Address:        device.Address,
+			Lat:            device.Lat,
+			Lng:            device.Lng,
+			Notes:          device.Notes,
+			Status:         device.Status,
+			LastReportedAt: device.LastReportedAt,
+			NetworkID:      device.NetworkID,
+			NetworkName:    device.NetworkName,
+			OrganizationID: device.OrganizationID,
+		}
+	}
+
+	devicesXML := DevicesDetailsXML{Devices: xmlDevices}
+
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", w.indent())
+
+	// Write XML header
+	fmt.Fprint(writer, xml.Header)
+
+	if err := encoder.Encode(devicesXML); err != nil {
+		return fmt.Errorf("failed to encode XML: %w", err)
+	}
+
+	return nil
+}
+
+// writeDeviceDetailsCSV writes device details to an io.Writer in CSV format, one row per device.
+// Tags are joined with "; " within their column.
+func (w *CSVWriter) writeDeviceDetailsCSV(devices []meraki.DeviceDetails, writer io.Writer) error {
+	csvWriter := w.newCSVWriter(writer)
+	defer csvWriter.Flush()
+
+	// Write header
+	header := []string{"Serial", "Name", "Model", "MAC", "Product Type", "Firmware", "LAN IP", "WAN 1 IP", "WAN 2 IP",
+		"Public IP", "Tags", "Address", "Lat", "Lng", "Notes", "Status", "Last Reported At", "Network ID", "Network Name",
+		"Organization ID"}
+	if err := csvWriter.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	// Write devices
+	for _, device := range devices {
+		record := []string{
+			device.Serial,
+			device.Name,
+			device.Model,
+			device.MAC,
+			device.ProductType,
+			device.Firmware,
+			device.LanIP,
+			device.WAN1IP,
+			device.WAN2IP,
+			device.PublicIP,
+			strings.Join(device.Tags, "; "),
+			device.Address,
+			fmt.Sprintf("%g", device.Lat),
+			fmt.Sprintf("%g", device.Lng),
+			device.Notes,
+			device.Status,
+			device.LastReportedAt,
+			device.NetworkID,
+			device.NetworkName,
+			device.OrganizationID,
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+
+	return nil
+}
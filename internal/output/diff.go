@@ -0,0 +1,305 @@
+package output
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// RecordChange describes how a single record differs from a previous run. Changed records
+// list only the fields that differ; added and removed records list all of their set fields.
+type RecordChange struct {
+	Key    string        `json:"key" xml:"key"`
+	Change string        `json:"change" xml:"change"` // added, removed, or changed
+	Fields []FieldChange `json:"fields,omitempty" xml:"field,omitempty"`
+}
+
+// FieldChange is the old and new value of one field, formatted as text
+type FieldChange struct {
+	Field string `json:"field" xml:"name,attr"`
+	Old   string `json:"old,omitempty" xml:"old,omitempty"`
+	New   string `json:"new,omitempty" xml:"new,omitempty"`
+}
+
+// recordChangesXML wraps record changes for XML output
+type recordChangesXML struct {
+	XMLName xml.Name       `xml:"changes"`
+	Changes []RecordChange `xml:"record"`
+}
+
+// diffIgnoredFields are computed on every run and would mark every record as changed
+var diffIgnoredFields = map[string]bool{
+	"downDuration": true,
+}
+
+// DiffWriter replaces records with their changes against a previous JSON output file
+type DiffWriter struct {
+	inner        Writer
+	previousFile string
+}
+
+// NewDiffWriter creates a writer that compares data against the records in previousFile,
+// a JSON output of the same command, and passes the resulting []RecordChange to inner
+func NewDiffWriter(inner Writer, previousFile string) Writer {
+	return &DiffWriter{inner: inner, previousFile: previousFile}
+}
+
+// WriteToFile writes the changes to a file
+func (w *DiffWriter) WriteToFile(data interface{}, filename string) error {
+	changes, err := w.diff(data)
+	if err != nil {
+		return err
+	}
+	return w.inner.WriteToFile(changes, filename)
+}
+
+// WriteTo writes the changes to an io.Writer
+func (w *DiffWriter) WriteTo(data interface{}, writer io.Writer) error {
+	changes, err := w.diff(data)
+	if err != nil {
+		return err
+	}
+	return w.inner.WriteTo(changes, writer)
+}
+
+// diff loads the previous records as the same type as data and compares them
+func (w *DiffWriter) diff(data interface{}) ([]RecordChange, error) {
+	content, err := os.ReadFile(w.previousFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read -diff-against file: %w", err)
+	}
+
+	previous := reflect.New(reflect.TypeOf(data))
+	if err := json.Unmarshal(unwrapJSONEnvelope(content), previous.Interface()); err != nil {
+		return nil, fmt.Errorf("failed to parse -diff-against file %s as JSON output of this command: %w", w.previousFile, err)
+	}
+
+	return DiffRecords(previous.Elem().Interface(), data)
+}
+
+// DiffRecords compares two slices of the same record type. Records are matched by serial for
+// devices, by network and subnet for routes, and by ID otherwise.
+func DiffRecords(previous, current interface{}) ([]RecordChange, error) {
+	previousValue := reflect.ValueOf(previous)
+	currentValue := reflect.ValueOf(current)
+	if currentValue.Kind() != reflect.Slice || currentValue.Type().Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot diff data of type %T", current)
+	}
+	if previousValue.Type() != currentValue.Type() {
+		return nil, fmt.Errorf("cannot diff %T against %T", current, previous)
+	}
+
+	previousRecords := indexRecords(previousValue)
+	currentRecords := indexRecords(currentValue)
+
+	changes := make([]RecordChange, 0)
+	for _, record := range currentRecords {
+		old, found := findRecord(previousRecords, record.key)
+		if !found {
+			changes = append(changes, RecordChange{Key: record.key, Change: "added", Fields: setFields(record, false)})
+			continue
+		}
+
+		var fields []FieldChange
+		for _, name := range record.names {
+			if diffIgnoredFields[name] || reflect.DeepEqual(old.fields[name], record.fields[name]) {
+				continue
+			}
+			fields = append(fields, FieldChange{
+				Field: name,
+				Old:   formatFieldValue(old.fields[name]),
+				New:   formatFieldValue(record.fields[name]),
+			})
+		}
+		if len(fields) > 0 {
+			changes = append(changes, RecordChange{Key: record.key, Change: "changed", Fields: fields})
+		}
+	}
+
+	for _, record := range previousRecords {
+		if _, found := findRecord(currentRecords, record.key); !found {
+			changes = append(changes, RecordChange{Key: record.key, Change: "removed", Fields: setFields(record, true)})
+		}
+	}
+
+	return changes, nil
+}
+
+// diffRecord is a record's key and its fields flattened by JSON name, in declaration order
+type diffRecord struct {
+	key    string
+	names  []string
+	fields map[string]interface{}
+}
+
+// indexRecords flattens each element of a slice, giving duplicate keys a #n suffix
+func indexRecords(slice reflect.Value) []diffRecord {
+	records := make([]diffRecord, 0, slice.Len())
+	seen := make(map[string]int)
+	for i := 0; i < slice.Len(); i++ {
+		record := diffRecord{fields: make(map[string]interface{})}
+		flattenFields(slice.Index(i), &record)
+		record.key = recordKey(record.fields)
+		seen[record.key]++
+		if seen[record.key] > 1 {
+			record.key = fmt.Sprintf("%s#%d", record.key, seen[record.key])
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// findRecord returns the record with the given key
+func findRecord(records []diffRecord, key string) (diffRecord, bool) {
+	for _, record := range records {
+		if record.key == key {
+			return record, true
+		}
+	}
+	return diffRecord{}, false
+}
+
+// flattenFields collects exported fields by JSON name, descending into embedded structs
+func flattenFields(value reflect.Value, record *diffRecord) {
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			flattenFields(value.Field(i), record)
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if _, exists := record.fields[name]; !exists {
+			record.names = append(record.names, name)
+		}
+		record.fields[name] = value.Field(i).Interface()
+	}
+}
+
+// recordKey identifies a record: serial for devices, network and subnet for routes, otherwise ID
+func recordKey(fields map[string]interface{}) string {
+	if serial, _ := fields["serial"].(string); serial != "" {
+		return serial
+	}
+	if subnet, ok := fields["subnet"].(string); ok {
+		if networkID, _ := fields["network_id"].(string); networkID != "" {
+			return networkID + "/" + subnet
+		}
+		return subnet
+	}
+	id, _ := fields["id"].(string)
+	return id
+}
+
+// setFields lists the non-zero fields of an added (new values) or removed (old values) record
+func setFields(record diffRecord, removed bool) []FieldChange {
+	var changes []FieldChange
+	for _, name := range record.names {
+		value := reflect.ValueOf(record.fields[name])
+		if !value.IsValid() || value.IsZero() {
+			continue
+		}
+		change := FieldChange{Field: name}
+		if removed {
+			change.Old = formatFieldValue(record.fields[name])
+		} else {
+			change.New = formatFieldValue(record.fields[name])
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// formatFieldValue renders a field value as text, using JSON for non-string values
+func formatFieldValue(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
+
+// writeRecordChanges writes record changes to an io.Writer in text format
+func (w *TextWriter) writeRecordChanges(changes []RecordChange, writer io.Writer) error {
+	counts := make(map[string]int)
+	for _, change := range changes {
+		counts[change.Change]++
+	}
+
+	fmt.Fprintf(writer, "Meraki Changes\n")
+	fmt.Fprintf(writer, "==============\n\n")
+	fmt.Fprintf(writer, "Added: %d, Removed: %d, Changed: %d\n\n", counts["added"], counts["removed"], counts["changed"])
+
+	symbols := map[string]string{"added": "+", "removed": "-", "changed": "~"}
+	for _, change := range changes {
+		fmt.Fprintf(writer, "%s %s\n", symbols[change.Change], change.Key)
+		if change.Change != "changed" {
+			continue
+		}
+		for _, field := range change.Fields {
+			fmt.Fprintf(writer, "    %s: %s -> %s\n", field.Field, field.Old, field.New)
+		}
+	}
+
+	return nil
+}
+
+// writeRecordChangesXML writes record changes to an io.Writer in XML format
+func (w *XMLWriter) writeRecordChangesXML(changes []RecordChange, writer io.Writer) error {
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", w.indent())
+
+	// Write XML header
+	fmt.Fprint(writer, xml.Header)
+
+	if err := encoder.Encode(recordChangesXML{Changes: changes}); err != nil {
+		return fmt.Errorf("failed to encode XML: %w", err)
+	}
+
+	return nil
+}
+
+// writeRecordChangesCSV writes record changes to an io.Writer in CSV format, one row per changed field
+func (w *CSVWriter) writeRecordChangesCSV(changes []RecordChange, writer io.Writer) error {
+	csvWriter := w.newCSVWriter(writer)
+	defer csvWriter.Flush()
+
+	// Write header
+	header := []string{"Key", "Change", "Field", "Old", "New"}
+	if err := csvWriter.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, change := range changes {
+		fields := change.Fields
+		if change.Change != "changed" {
+			// Added and removed records get a single row rather than one per field
+			fields = []FieldChange{{}}
+		}
+		for _, field := range fields {
+			record := []string{change.Key, change.Change, field.Field, field.Old, field.New}
+			if err := csvWriter.Write(record); err != nil {
+				return fmt.Errorf("failed to write CSV record: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
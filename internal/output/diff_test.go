@@ -0,0 +1,109 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"meraki-info/internal/meraki"
+)
+
+func TestDiffRecords_Routes(t *testing.T) {
+	previous := []meraki.RouteWithNetwork{
+		{Route: meraki.Route{Subnet: "10.0.0.0/24", GatewayIP: "10.0.0.1", Enabled: true}, NetworkID: "net1", NetworkName: "HQ"},
+		{Route: meraki.Route{Subnet: "10.0.1.0/24", GatewayIP: "10.0.1.1", Enabled: true}, NetworkID: "net1", NetworkName: "HQ"},
+	}
+	current := []meraki.RouteWithNetwork{
+		{Route: meraki.Route{Subnet: "10.0.0.0/24", GatewayIP: "10.0.0.254", Enabled: true}, NetworkID: "net1", NetworkName: "HQ"},
+		{Route: meraki.Route{Subnet: "10.0.2.0/24", GatewayIP: "10.0.2.1", Enabled: true}, NetworkID: "net1", NetworkName: "HQ"},
+	}
+
+	changes, err := DiffRecords(previous, current)
+	if err != nil {
+		t.Fatalf("DiffRecords failed: %v", err)
+	}
+	if len(changes) != 3 {
+		t.Fatalf("Expected 3 changes, got %d: %+v", len(changes), changes)
+	}
+
+	changed := changes[0]
+	if changed.Key != "net1/10.0.0.0/24" || changed.Change != "changed" {
+		t.Errorf("Expected changed net1/10.0.0.0/24, got %s %s", changed.Change, changed.Key)
+	}
+	if len(changed.Fields) != 1 {
+		t.Fatalf("Expected only the gateway field to be reported, got %+v", changed.Fields)
+	}
+	if changed.Fields[0] != (FieldChange{Field: "gatewayIp", Old: "10.0.0.1", New: "10.0.0.254"}) {
+		t.Errorf("Unexpected field change: %+v", changed.Fields[0])
+	}
+
+	if changes[1].Key != "net1/10.0.2.0/24" || changes[1].Change != "added" {
+		t.Errorf("Expected added net1/10.0.2.0/24, got %s %s", changes[1].Change, changes[1].Key)
+	}
+	if changes[2].Key != "net1/10.0.1.0/24" || changes[2].Change != "removed" {
+		t.Errorf("Expected removed net1/10.0.1.0/24, got %s %s", changes[2].Change, changes[2].Key)
+	}
+}
+
+func TestDiffRecords_DevicesAndLicenses(t *testing.T) {
+	previousDevices := []meraki.Device{{Serial: "Q2XX-1", Status: "offline", DownDuration: "1h"}}
+	currentDevices := []meraki.Device{{Serial: "Q2XX-1", Status: "offline", DownDuration: "2h"}}
+	changes, err := DiffRecords(previousDevices, currentDevices)
+	if err != nil {
+		t.Fatalf("DiffRecords failed: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("Expected computed down duration to be ignored, got %+v", changes)
+	}
+
+	previousLicenses := []meraki.License{{ID: "L1", State: "active"}}
+	currentLicenses := []meraki.License{{ID: "L1", State: "expired"}}
+	changes, err = DiffRecords(previousLicenses, currentLicenses)
+	if err != nil {
+		t.Fatalf("DiffRecords failed: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Key != "L1" || len(changes[0].Fields) != 1 || changes[0].Fields[0].Field != "state" {
+		t.Errorf("Expected only the state of L1 to change, got %+v", changes)
+	}
+
+	if _, err := DiffRecords(previousLicenses, currentDevices); err == nil {
+		t.Error("Expected error diffing different record types")
+	}
+}
+
+func TestDiffWriter(t *testing.T) {
+	previous := []meraki.Device{
+		{Serial: "Q2XX-1", Name: "Lobby AP", Status: "online"},
+		{Serial: "Q2XX-2", Name: "Core", Status: "online"},
+	}
+	current := []meraki.Device{
+		{Serial: "Q2XX-1", Name: "Lobby AP", Status: "offline"},
+		{Serial: "Q2XX-2", Name: "Core", Status: "online"},
+	}
+
+	content, err := json.Marshal(previous)
+	if err != nil {
+		t.Fatalf("Failed to encode previous output: %v", err)
+	}
+	previousFile := filepath.Join(t.TempDir(), "previous.json")
+	if err := os.WriteFile(previousFile, content, 0644); err != nil {
+		t.Fatalf("Failed to write previous output: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := NewDiffWriter(&TextWriter{}, previousFile).WriteTo(current, &buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	output := buf.String()
+	for _, expected := range []string{"Added: 0, Removed: 0, Changed: 1", "~ Q2XX-1", "status: online -> offline"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, "Q2XX-2") {
+		t.Errorf("Expected unchanged device to be omitted, got:\n%s", output)
+	}
+}
@@ -0,0 +1,75 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+)
+
+// JSONEnvelope is the top-level object JSON output is wrapped in with -json-envelope, so consumers
+// get a stable object with the generation time and record count instead of a bare array
+type JSONEnvelope struct {
+	GeneratedAt time.Time       `json:"generatedAt"`
+	Count       int             `json:"count"`
+	Items       json.RawMessage `json:"items"`
+}
+
+// envelopeNow returns the generation time of enveloped output; overridden in tests
+var envelopeNow = time.Now
+
+// SetJSONEnvelope makes writer, or the writer a DestinationWriter wraps, wrap JSON output in a
+// JSONEnvelope. Other writers are left unchanged.
+func SetJSONEnvelope(writer Writer, envelope bool) {
+	switch w := writer.(type) {
+	case *JSONWriter:
+		w.Envelope = envelope
+	case *DestinationWriter:
+		SetJSONEnvelope(w.writer, envelope)
+	}
+}
+
+// writeJSONEnvelope writes data as the items of a JSONEnvelope. Data that is not a list, such as
+// a summary, becomes a single item.
+func (w *JSONWriter) writeJSONEnvelope(data interface{}, writer io.Writer) error {
+	inner := *w
+	inner.Envelope = false
+	var buf bytes.Buffer
+	if err := inner.WriteTo(data, &buf); err != nil {
+		return err
+	}
+
+	items := bytes.TrimSpace(buf.Bytes())
+	count := 1
+	if value := reflect.ValueOf(data); value.Kind() == reflect.Slice {
+		count = value.Len()
+	} else {
+		items = append(append([]byte("["), items...), ']')
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", w.indent())
+
+	envelope := JSONEnvelope{GeneratedAt: envelopeNow().UTC(), Count: count, Items: items}
+	if err := encoder.Encode(envelope); err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+
+	return nil
+}
+
+// unwrapJSONEnvelope returns the items of JSON output written with -json-envelope, and content
+// unchanged when it is a bare array or not an envelope
+func unwrapJSONEnvelope(content []byte) []byte {
+	if trimmed := bytes.TrimSpace(content); len(trimmed) == 0 || trimmed[0] != '{' {
+		return content
+	}
+
+	var envelope JSONEnvelope
+	if err := json.Unmarshal(content, &envelope); err != nil || envelope.Items == nil {
+		return content
+	}
+	return envelope.Items
+}
@@ -0,0 +1,82 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"meraki-info/internal/meraki"
+)
+
+func TestJSONWriter_Envelope(t *testing.T) {
+	generatedAt := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+	envelopeNow = func() time.Time { return generatedAt }
+	t.Cleanup(func() { envelopeNow = time.Now })
+
+	routes := []meraki.Route{
+		{Subnet: "10.0.0.0/24", GatewayIP: "10.0.0.1", Enabled: true},
+		{Subnet: "10.1.0.0/24", GatewayIP: "10.1.0.1"},
+	}
+
+	var buf bytes.Buffer
+	writer := &JSONWriter{Envelope: true, Fields: []string{"subnet"}}
+	if err := writer.WriteTo(routes, &buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+
+	var envelope struct {
+		GeneratedAt time.Time                `json:"generatedAt"`
+		Count       int                      `json:"count"`
+		Items       []map[string]interface{} `json:"items"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &envelope); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if !envelope.GeneratedAt.Equal(generatedAt) || envelope.Count != 2 || len(envelope.Items) != 2 {
+		t.Fatalf("Expected both routes with their count and generation time, got %s", buf.String())
+	}
+	if len(envelope.Items[0]) != 1 || envelope.Items[0]["subnet"] != "10.0.0.0/24" {
+		t.Errorf("Expected -fields to apply to the items, got %+v", envelope.Items[0])
+	}
+
+	// A summary is not a list, so it becomes the single item
+	buf.Reset()
+	writer = &JSONWriter{Envelope: true}
+	if err := writer.WriteTo(Summary{Title: "Route Tables", Total: 2}, &buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	var single struct {
+		Count int       `json:"count"`
+		Items []Summary `json:"items"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &single); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if single.Count != 1 || len(single.Items) != 1 || single.Items[0].Total != 2 {
+		t.Errorf("Expected the summary as a single item, got %s", buf.String())
+	}
+}
+
+func TestDiffWriter_EnvelopedPrevious(t *testing.T) {
+	previous := []meraki.Route{{ID: "r1", Subnet: "10.0.0.0/24", GatewayIP: "10.0.0.1"}}
+	var buf bytes.Buffer
+	if err := (&JSONWriter{Envelope: true}).WriteTo(previous, &buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	previousFile := filepath.Join(t.TempDir(), "previous.json")
+	if err := os.WriteFile(previousFile, buf.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	current := []meraki.Route{{ID: "r1", Subnet: "10.0.0.0/24", GatewayIP: "10.0.0.254"}}
+	changes, err := NewDiffWriter(&JSONWriter{}, previousFile).(*DiffWriter).diff(current)
+	if err != nil {
+		t.Fatalf("Expected an enveloped previous file to be accepted, got: %v", err)
+	}
+	if len(changes) != 1 {
+		t.Errorf("Expected the changed gateway to be reported, got %+v", changes)
+	}
+}
@@ -0,0 +1,239 @@
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"meraki-info/internal/meraki"
+)
+
+// textFieldLine matches a top-level "  Key: Value" line of text output, capturing the key
+var textFieldLine = regexp.MustCompile(`^  (\S[^:]*):(?: |$)`)
+
+// SetFields limits the output of writer, or of the writer a DestinationWriter wraps, to the named
+// fields. Writers without field selection are left unchanged.
+func SetFields(writer Writer, fields []string) {
+	switch w := writer.(type) {
+	case *TextWriter:
+		w.Fields = fields
+	case *JSONWriter:
+		w.Fields = fields
+	case *CSVWriter:
+		w.Fields = fields
+	case *DestinationWriter:
+		SetFields(w.writer, fields)
+	}
+}
+
+// fieldSelector matches output keys, columns and text labels against a list of field names.
+// Names match case-insensitively and ignoring spaces, underscores and hyphens, so GatewayIP
+// selects the "Gateway IP" CSV column, the gatewayIp JSON key and the "  Gateway IP:" text line.
+type fieldSelector struct {
+	fields    []string
+	requested map[string]bool // normalized requested names
+	seen      map[string]bool // normalized names present in the output
+}
+
+func newFieldSelector(fields []string) *fieldSelector {
+	s := &fieldSelector{fields: fields, requested: make(map[string]bool), seen: make(map[string]bool)}
+	for _, field := range fields {
+		s.requested[normalizeFieldName(field)] = true
+	}
+	return s
+}
+
+// selected reports whether a key of the output is one of the requested fields
+func (s *fieldSelector) selected(key string) bool {
+	name := normalizeFieldName(key)
+	s.seen[name] = true
+	return s.requested[name]
+}
+
+// warnUnknown logs the requested fields that matched nothing. Output without any keys, such as
+// an empty result, says nothing about which fields exist, so it is not warned about.
+func (s *fieldSelector) warnUnknown() {
+	if len(s.seen) == 0 {
+		return
+	}
+	for _, field := range s.fields {
+		if !s.seen[normalizeFieldName(field)] {
+			slog.Warn("Unknown field in -fields, ignoring it", "field", field)
+		}
+	}
+}
+
+func normalizeFieldName(name string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "_", "", "-", "").Replace(name))
+}
+
+// selectTextFields copies text output to writer, keeping only the "  Key: Value" lines of the
+// selected fields. Deeper indented lines follow the key line above them; headings, record titles
+// and blank lines are always kept.
+func selectTextFields(fields []string, output []byte, writer io.Writer) error {
+	selector := newFieldSelector(fields)
+	keep := true
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if match := textFieldLine.FindStringSubmatch(line); match != nil {
+			keep = selector.selected(match[1])
+		} else if !strings.HasPrefix(line, "   ") {
+			keep = true
+		}
+		if !keep {
+			continue
+		}
+		if _, err := fmt.Fprintln(writer, line); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	selector.warnUnknown()
+	return nil
+}
+
+// selectJSONFields re-encodes JSON output with indent, keeping only the selected keys of each
+// record. Output that is neither an object nor an array of objects is written unchanged.
+func selectJSONFields(fields []string, indent string, output []byte, writer io.Writer) error {
+	selector := newFieldSelector(fields)
+	selectKeys := func(record map[string]json.RawMessage) map[string]json.RawMessage {
+		selected := make(map[string]json.RawMessage)
+		for key, value := range record {
+			if selector.selected(key) {
+				selected[key] = value
+			}
+		}
+		return selected
+	}
+
+	var data interface{}
+	var records []map[string]json.RawMessage
+	var record map[string]json.RawMessage
+	if err := json.Unmarshal(output, &records); err == nil {
+		for i := range records {
+			records[i] = selectKeys(records[i])
+		}
+		data = records
+	} else if err := json.Unmarshal(output, &record); err == nil {
+		data = selectKeys(record)
+	} else {
+		_, err := writer.Write(output)
+		return err
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", indent)
+	if err := encoder.Encode(data); err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+
+	selector.warnUnknown()
+	return nil
+}
+
+// csvWritesJSON reports whether the CSV writer writes data as JSON, as it does for consolidated
+// licenses and devices, which then gets no byte order mark and has JSON keys selected by -fields
+func csvWritesJSON(data interface{}) bool {
+	switch data.(type) {
+	case []meraki.LicenseWithNetwork, []meraki.DeviceWithNetwork:
+		return true
+	default:
+		return false
+	}
+}
+
+// selectCSVFields rewrites CSV output written by the writer keeping only the columns whose header is
+// a selected field
+func (w *CSVWriter) selectCSVFields(fields []string, output []byte, writer io.Writer) error {
+	reader := csv.NewReader(bytes.NewReader(output))
+	reader.FieldsPerRecord = -1
+	if w.Delimiter != 0 {
+		reader.Comma = w.Delimiter
+	}
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return fmt.Errorf("failed to read CSV output: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	selector := newFieldSelector(fields)
+	var columns []int
+	for i, header := range rows[0] {
+		if selector.selected(header) {
+			columns = append(columns, i)
+		}
+	}
+
+	csvWriter := w.newCSVWriter(writer)
+	for _, row := range rows {
+		record := make([]string, 0, len(columns))
+		for _, column := range columns {
+			if column < len(row) {
+				record = append(record, row[column])
+			} else {
+				record = append(record, "")
+			}
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+	csvWriter.Flush()
+	if err := csvWriter.Error(); err != nil {
+		return err
+	}
+
+	selector.warnUnknown()
+	return nil
+}
+
+// writeTextFields writes data in text format limited to the writer's fields
+func (w *TextWriter) writeTextFields(data interface{}, writer io.Writer) error {
+	all := *w
+	all.Fields = nil
+	var buf bytes.Buffer
+	if err := all.WriteTo(data, &buf); err != nil {
+		return err
+	}
+	return selectTextFields(w.Fields, buf.Bytes(), writer)
+}
+
+// writeJSONFields writes data in JSON format limited to the writer's fields
+func (w *JSONWriter) writeJSONFields(data interface{}, writer io.Writer) error {
+	all := *w
+	all.Fields = nil
+	var buf bytes.Buffer
+	if err := all.WriteTo(data, &buf); err != nil {
+		return err
+	}
+	return selectJSONFields(w.Fields, w.indent(), buf.Bytes(), writer)
+}
+
+// writeCSVFields writes data in CSV format limited to the writer's fields. Consolidated licenses
+// and devices are written as JSON by the CSV writer, so their keys are selected instead.
+func (w *CSVWriter) writeCSVFields(data interface{}, writer io.Writer) error {
+	all := *w
+	all.Fields = nil
+	var buf bytes.Buffer
+	if err := all.WriteTo(data, &buf); err != nil {
+		return err
+	}
+
+	if csvWritesJSON(data) {
+		return selectJSONFields(w.Fields, defaultIndent, buf.Bytes(), writer)
+	}
+	return w.selectCSVFields(w.Fields, buf.Bytes(), writer)
+}
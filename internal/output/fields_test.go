@@ -0,0 +1,98 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"meraki-info/internal/meraki"
+)
+
+func TestWriters_Fields(t *testing.T) {
+	routes := []meraki.Route{
+		{ID: "r1", Name: "Office", Subnet: "10.0.0.0/24", GatewayIP: "10.0.0.1", GatewayVlan: 10, Enabled: true},
+		{ID: "r2", Name: "Lab", Subnet: "10.1.0.0/24", GatewayIP: "10.1.0.1", GatewayVlan: 20},
+	}
+	fields := []string{"Subnet", "GatewayIP"}
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := (&CSVWriter{Fields: fields}).WriteTo(routes, &buf); err != nil {
+			t.Fatalf("WriteTo failed: %v", err)
+		}
+		expected := "Subnet,Gateway IP\n10.0.0.0/24,10.0.0.1\n10.1.0.0/24,10.1.0.1\n"
+		if buf.String() != expected {
+			t.Errorf("Expected only the selected columns:\n%s\ngot:\n%s", expected, buf.String())
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := (&JSONWriter{Fields: fields}).WriteTo(routes, &buf); err != nil {
+			t.Fatalf("WriteTo failed: %v", err)
+		}
+		var records []map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &records); err != nil {
+			t.Fatalf("Failed to parse JSON: %v\n%s", err, buf.String())
+		}
+		if len(records) != 2 {
+			t.Fatalf("Expected 2 records, got %d", len(records))
+		}
+		if len(records[0]) != 2 || records[0]["subnet"] != "10.0.0.0/24" || records[0]["gatewayIp"] != "10.0.0.1" {
+			t.Errorf("Expected only subnet and gatewayIp keys, got %v", records[0])
+		}
+	})
+
+	t.Run("text", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := (&TextWriter{Fields: fields}).WriteTo(routes, &buf); err != nil {
+			t.Fatalf("WriteTo failed: %v", err)
+		}
+		output := buf.String()
+		for _, expected := range []string{"Total Routes: 2", "Route 1:\n  Subnet: 10.0.0.0/24\n  Gateway IP: 10.0.0.1\n\nRoute 2:"} {
+			if !strings.Contains(output, expected) {
+				t.Errorf("Expected text output to contain %q, got:\n%s", expected, output)
+			}
+		}
+		for _, unexpected := range []string{"Name:", "Gateway VLAN:", "Enabled:"} {
+			if strings.Contains(output, unexpected) {
+				t.Errorf("Expected no %q lines, got:\n%s", unexpected, output)
+			}
+		}
+	})
+
+	t.Run("unknown fields are warned about", func(t *testing.T) {
+		var logs bytes.Buffer
+		previous := slog.Default()
+		slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+		defer slog.SetDefault(previous)
+
+		var buf bytes.Buffer
+		if err := (&CSVWriter{Fields: []string{"Subnet", "Color"}}).WriteTo(routes, &buf); err != nil {
+			t.Fatalf("Expected unknown fields not to fail, got %v", err)
+		}
+		if !strings.HasPrefix(buf.String(), "Subnet\n") {
+			t.Errorf("Expected only the Subnet column, got:\n%s", buf.String())
+		}
+		if !strings.Contains(logs.String(), "field=Color") || strings.Contains(logs.String(), "field=Subnet") {
+			t.Errorf("Expected a warning for Color only, got %q", logs.String())
+		}
+	})
+
+	t.Run("consolidated devices in csv select JSON keys", func(t *testing.T) {
+		devices := []meraki.DeviceWithNetwork{{Device: meraki.Device{Serial: "Q2XX-1", Model: "MX64"}, NetworkName: "HQ"}}
+		var buf bytes.Buffer
+		if err := (&CSVWriter{Fields: []string{"serial", "NetworkName"}}).WriteTo(devices, &buf); err != nil {
+			t.Fatalf("WriteTo failed: %v", err)
+		}
+		var records []map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &records); err != nil {
+			t.Fatalf("Failed to parse JSON: %v\n%s", err, buf.String())
+		}
+		if len(records) != 1 || len(records[0]) != 2 || records[0]["serial"] != "Q2XX-1" || records[0]["network_name"] != "HQ" {
+			t.Errorf("Expected only serial and network_name, got %v", records)
+		}
+	})
+}
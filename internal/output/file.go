@@ -0,0 +1,170 @@
+package output
+
+import (
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultFileMode is the permission of files created by WriteToFile unless a writer sets FileMode
+const DefaultFileMode os.FileMode = 0644
+
+// FileOptions controls how a writer creates files in WriteToFile. Every file-writing format embeds
+// it, so permissions and compression are handled once here rather than in each writer.
+type FileOptions struct {
+	// FileMode is the permission of created files; DefaultFileMode when zero
+	FileMode os.FileMode
+	// Compress gzips the output as it is written, appending .gz to the filename
+	Compress bool
+}
+
+// fileOptions gives SetFileMode and SetCompress access to the options of any writer embedding FileOptions
+func (o *FileOptions) fileOptions() *FileOptions {
+	return o
+}
+
+// fileWriter is implemented by writers that embed FileOptions
+type fileWriter interface {
+	fileOptions() *FileOptions
+}
+
+// SetFileMode sets the permission of files created by writer, or by the writer a
+// DestinationWriter wraps. Writers that do not create files are left unchanged.
+func SetFileMode(writer Writer, mode os.FileMode) {
+	switch w := writer.(type) {
+	case *DestinationWriter:
+		SetFileMode(w.writer, mode)
+	case fileWriter:
+		w.fileOptions().FileMode = mode
+	}
+}
+
+// SetCompress makes writer, or the writer a DestinationWriter wraps, gzip the files it creates.
+// Writers that do not create files are left unchanged.
+func SetCompress(writer Writer, compress bool) {
+	switch w := writer.(type) {
+	case *DestinationWriter:
+		SetCompress(w.writer, compress)
+	case fileWriter:
+		w.fileOptions().Compress = compress
+	}
+}
+
+// CompressedFilename returns the name of the gzipped file written for filename
+func CompressedFilename(filename string) string {
+	if strings.HasSuffix(filename, ".gz") {
+		return filename
+	}
+	return filename + ".gz"
+}
+
+// writeFile writes the output of fn to filename atomically with the configured permission. When
+// compressing, fn writes through a gzip stream to filename.gz, so the output is never held in memory.
+func (o *FileOptions) writeFile(filename string, fn func(io.Writer) error) error {
+	if !o.Compress {
+		return atomicWriteToFile(filename, o.FileMode, fn)
+	}
+
+	return atomicWriteToFile(CompressedFilename(filename), o.FileMode, func(writer io.Writer) error {
+		gz := gzip.NewWriter(writer)
+		if err := fn(gz); err != nil {
+			return err
+		}
+		if err := gz.Close(); err != nil {
+			return fmt.Errorf("failed to compress output: %w", err)
+		}
+		return nil
+	})
+}
+
+// atomicWriteToFile writes to a temporary file in the same directory and renames it over filename
+// once fn succeeds, so an interrupted or failed write never leaves a truncated output file behind.
+// The file is created with mode, or DefaultFileMode when mode is zero.
+func atomicWriteToFile(filename string, mode os.FileMode, fn func(io.Writer) error) error {
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return fmt.Errorf("failed to generate temporary file name: %w", err)
+	}
+	tmpName := filename + "." + hex.EncodeToString(suffix) + ".tmp"
+
+	perm := mode
+	if perm == 0 {
+		perm = DefaultFileMode
+	}
+	file, err := os.OpenFile(tmpName, os.O_RDWR|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+
+	// Remove the temporary file unless it was successfully renamed into place
+	renamed := false
+	defer func() {
+		if !renamed {
+			file.Close()
+			os.Remove(tmpName)
+		}
+	}()
+
+	// An explicitly requested mode is applied as given rather than narrowed by the umask
+	if mode != 0 {
+		if err := file.Chmod(mode); err != nil {
+			return fmt.Errorf("failed to set file mode: %w", err)
+		}
+	}
+
+	if err := fn(file); err != nil {
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+
+	if err := os.Rename(tmpName, filename); err != nil {
+		return fmt.Errorf("failed to rename temporary file: %w", err)
+	}
+	renamed = true
+
+	return nil
+}
+
+// FilenameTokens are the values substituted for the {date}, {time}, {org}, {network} and
+// {command} tokens of an output filename
+type FilenameTokens struct {
+	Time         time.Time
+	Organization string
+	Network      string
+	Command      string
+}
+
+// unsafeFilenameChars matches the characters replaced in token values so that an organization
+// or network name cannot introduce directories or awkward characters into a filename
+var unsafeFilenameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// ExpandFilename replaces the tokens of filename: {date} as YYYY-MM-DD, {time} as HHMMSS, and
+// {org}, {network} and {command} with their values, or "all" when the output covers every one.
+// Filenames without tokens are returned unchanged.
+func ExpandFilename(filename string, tokens FilenameTokens) string {
+	if !strings.Contains(filename, "{") {
+		return filename
+	}
+	value := func(v string) string {
+		if v == "" {
+			return "all"
+		}
+		return unsafeFilenameChars.ReplaceAllString(v, "_")
+	}
+	return strings.NewReplacer(
+		"{date}", tokens.Time.Format("2006-01-02"),
+		"{time}", tokens.Time.Format("150405"),
+		"{org}", value(tokens.Organization),
+		"{network}", value(tokens.Network),
+		"{command}", value(tokens.Command),
+	).Replace(filename)
+}
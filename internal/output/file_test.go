@@ -0,0 +1,101 @@
+package output
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"meraki-info/internal/meraki"
+)
+
+func TestWriteToFile_Compress(t *testing.T) {
+	dir := t.TempDir()
+	routes := []meraki.Route{{Subnet: "10.0.0.0/24", GatewayIP: "10.0.0.1"}}
+	// Devices are written by every format, including prometheus
+	devices := []meraki.DeviceWithNetwork{{Device: meraki.Device{Serial: "Q2XX-1", Status: "offline"}, NetworkName: "HQ"}}
+
+	for _, format := range FormatNames() {
+		t.Run(format, func(t *testing.T) {
+			writer := NewWriter(format)
+			SetCompress(writer, true)
+			SetFileMode(writer, 0600)
+			filename := filepath.Join(dir, "devices."+format)
+			if err := writer.WriteToFile(devices, filename); err != nil {
+				t.Fatalf("WriteToFile failed: %v", err)
+			}
+
+			if _, err := os.Stat(filename); !os.IsNotExist(err) {
+				t.Errorf("Expected no uncompressed file, got %v", err)
+			}
+			file, err := os.Open(filename + ".gz")
+			if err != nil {
+				t.Fatalf("Expected a .gz file: %v", err)
+			}
+			defer file.Close()
+			if info, _ := file.Stat(); info.Mode().Perm() != 0600 {
+				t.Errorf("Expected file mode 600, got %o", info.Mode().Perm())
+			}
+			gz, err := gzip.NewReader(file)
+			if err != nil {
+				t.Fatalf("Expected gzip content: %v", err)
+			}
+			content, err := io.ReadAll(gz)
+			if err != nil || len(content) == 0 {
+				t.Errorf("Expected decompressed output, got %q, %v", content, err)
+			}
+		})
+	}
+
+	t.Run("existing .gz suffix is kept", func(t *testing.T) {
+		writer := &JSONWriter{FileOptions: FileOptions{Compress: true}}
+		filename := filepath.Join(dir, "routes.json.gz")
+		if err := writer.WriteToFile(routes, filename); err != nil {
+			t.Fatalf("WriteToFile failed: %v", err)
+		}
+		file, err := os.Open(filename)
+		if err != nil {
+			t.Fatalf("Expected %s: %v", filename, err)
+		}
+		defer file.Close()
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			t.Fatalf("Expected gzip content: %v", err)
+		}
+		var decoded []meraki.Route
+		if err := json.NewDecoder(gz).Decode(&decoded); err != nil || len(decoded) != 1 {
+			t.Errorf("Expected the routes back, got %v, %v", decoded, err)
+		}
+	})
+}
+
+func TestExpandFilename(t *testing.T) {
+	tokens := FilenameTokens{
+		Time:         time.Date(2025, 7, 17, 6, 30, 5, 0, time.UTC),
+		Organization: "Acme Corp",
+		Network:      "Store/12",
+		Command:      "route-tables",
+	}
+
+	tests := []struct {
+		filename string
+		expected string
+	}{
+		{filename: "routes-{date}.json", expected: "routes-2025-07-17.json"},
+		{filename: "out/{command}-{org}-{network}-{date}T{time}.csv", expected: "out/route-tables-Acme_Corp-Store_12-2025-07-17T063005.csv"},
+		{filename: "routes.json", expected: "routes.json"},
+		{filename: "{unknown}.json", expected: "{unknown}.json"},
+	}
+	for _, tt := range tests {
+		if got := ExpandFilename(tt.filename, tokens); got != tt.expected {
+			t.Errorf("ExpandFilename(%q) = %q, expected %q", tt.filename, got, tt.expected)
+		}
+	}
+
+	if got := ExpandFilename("{org}-{network}.json", FilenameTokens{}); got != "all-all.json" {
+		t.Errorf("Expected unset tokens to expand to all, got %q", got)
+	}
+}
@@ -0,0 +1,46 @@
+package output
+
+import (
+	"io"
+	"reflect"
+)
+
+// FilterWriter passes only the records satisfying a predicate on to an inner writer
+type FilterWriter struct {
+	inner     Writer
+	predicate func(interface{}) bool
+}
+
+// NewFilterWriter creates a writer that drops slice elements for which predicate returns false
+// before delegating to inner. Non-slice data is passed through unchanged.
+func NewFilterWriter(inner Writer, predicate func(interface{}) bool) Writer {
+	return &FilterWriter{inner: inner, predicate: predicate}
+}
+
+// WriteToFile writes the filtered data to a file
+func (w *FilterWriter) WriteToFile(data interface{}, filename string) error {
+	return w.inner.WriteToFile(w.filter(data), filename)
+}
+
+// WriteTo writes the filtered data to an io.Writer
+func (w *FilterWriter) WriteTo(data interface{}, writer io.Writer) error {
+	return w.inner.WriteTo(w.filter(data), writer)
+}
+
+// filter returns a new slice of the same type holding only the records that satisfy the predicate
+func (w *FilterWriter) filter(data interface{}) interface{} {
+	value := reflect.ValueOf(data)
+	if value.Kind() != reflect.Slice {
+		return data
+	}
+
+	filtered := reflect.MakeSlice(value.Type(), 0, value.Len())
+	for i := 0; i < value.Len(); i++ {
+		record := value.Index(i)
+		if w.predicate(record.Interface()) {
+			filtered = reflect.Append(filtered, record)
+		}
+	}
+
+	return filtered.Interface()
+}
@@ -0,0 +1,71 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"meraki-info/internal/meraki"
+)
+
+func TestFilterWriter_ExcludesRecords(t *testing.T) {
+	devices := []meraki.Device{
+		{Serial: "A", Status: "online"},
+		{Serial: "B", Status: "alerting"},
+		{Serial: "C", Status: "offline"},
+	}
+
+	isAlerting := func(v interface{}) bool {
+		d, ok := v.(meraki.Device)
+		return ok && meraki.IsDeviceAlerting(d.Status)
+	}
+
+	recorder := &recordingWriter{}
+	if err := NewFilterWriter(recorder, isAlerting).WriteTo(devices, &bytes.Buffer{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	filtered, ok := recorder.received[0].([]meraki.Device)
+	if !ok {
+		t.Fatalf("Expected []meraki.Device, got %T", recorder.received[0])
+	}
+	if len(filtered) != 1 || filtered[0].Serial != "B" {
+		t.Errorf("Expected only device B, got %+v", filtered)
+	}
+
+	// The original slice must be left untouched
+	if len(devices) != 3 {
+		t.Errorf("Expected original slice to keep 3 devices, got %d", len(devices))
+	}
+}
+
+func TestFilterWriter_Chaining(t *testing.T) {
+	devices := []meraki.Device{
+		{Serial: "A", Status: "offline", Model: "MR46"},
+		{Serial: "B", Status: "alerting", Model: "MS225"},
+		{Serial: "C", Status: "online", Model: "MR46"},
+	}
+
+	isDown := func(v interface{}) bool {
+		d, ok := v.(meraki.Device)
+		return ok && meraki.IsDeviceDown(d.Status)
+	}
+	isAccessPoint := func(v interface{}) bool {
+		d, ok := v.(meraki.Device)
+		return ok && d.Model == "MR46"
+	}
+
+	var buf bytes.Buffer
+	writer := NewFilterWriter(NewFilterWriter(NewWriter("json"), isAccessPoint), isDown)
+	if err := writer.WriteTo(devices, &buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var parsed []meraki.Device
+	if err := json.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if len(parsed) != 1 || parsed[0].Serial != "A" {
+		t.Errorf("Expected only device A, got %+v", parsed)
+	}
+}
@@ -0,0 +1,160 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"meraki-info/internal/meraki"
+)
+
+// FirewallRulesXML represents a collection of firewall rules in XML format
+type FirewallRulesXML struct {
+	XMLName xml.Name          `xml:"firewallRules"`
+	Rules   []FirewallRuleXML `xml:"rule"`
+}
+
+// FirewallRuleXML represents a single firewall rule in XML format
+type FirewallRuleXML struct {
+	Organization   string `xml:"organization,omitempty"`
+	OrganizationID string `xml:"organizationId,omitempty"`
+	NetworkID      string `xml:"networkId"`
+	NetworkName    string `xml:"networkName"`
+	Layer          string `xml:"layer"`
+	Number         int    `xml:"number"`
+	Policy         string `xml:"policy"`
+	Protocol       string `xml:"protocol,omitempty"`
+	SrcCIDR        string `xml:"srcCidr,omitempty"`
+	SrcPort        string `xml:"srcPort,omitempty"`
+	DestCIDR       string `xml:"destCidr,omitempty"`
+	DestPort       string `xml:"destPort,omitempty"`
+	Type           string `xml:"type,omitempty"`
+	Value          string `xml:"value,omitempty"`
+	Comment        string `xml:"comment,omitempty"`
+	SyslogEnabled  bool   `xml:"syslogEnabled"`
+}
+
+// writeFirewallRules writes firewall rules to an io.Writer in text format
+func (w *TextWriter) writeFirewallRules(rules []meraki.FirewallRuleWithNetwork, writer io.Writer) error {
+	// Write header
+	fmt.Fprintf(writer, "Meraki Firewall Rules\n")
+	fmt.Fprintf(writer, "=====================\n\n")
+	fmt.Fprintf(writer, "Total Rules: %d\n\n", len(rules))
+
+	// Write rules
+	for _, rule := range rules {
+		fmt.Fprintf(writer, "%s Rule %d:\n", strings.ToUpper(rule.Layer), rule.Number)
+		if rule.Organization != "" {
+			fmt.Fprintf(writer, "  Organization: %s\n", rule.Organization)
+		}
+		fmt.Fprintf(writer, "  Network Name: %s\n", rule.NetworkName)
+		fmt.Fprintf(writer, "  Network ID: %s\n", rule.NetworkID)
+		fmt.Fprintf(writer, "  Policy: %s\n", rule.Policy)
+		if rule.Layer == "l7" {
+			fmt.Fprintf(writer, "  Type: %s\n", rule.Type)
+			fmt.Fprintf(writer, "  Value: %s\n", rule.Value)
+		} else {
+			fmt.Fprintf(writer, "  Protocol: %s\n", rule.Protocol)
+			fmt.Fprintf(writer, "  Source: %s\n", firewallEndpoint(rule.SrcCIDR, rule.SrcPort))
+			fmt.Fprintf(writer, "  Destination: %s\n", firewallEndpoint(rule.DestCIDR, rule.DestPort))
+			fmt.Fprintf(writer, "  Syslog: %t\n", rule.SyslogEnabled)
+		}
+		if rule.Comment != "" {
+			fmt.Fprintf(writer, "  Comment: %s\n", rule.Comment)
+		}
+		if _, err := fmt.Fprintf(writer, "\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// firewallEndpoint formats the CIDRs and ports of a rule's source or destination, e.g. "10.0.0.0/8 port 443"
+func firewallEndpoint(cidr, port string) string {
+	if port == "" || strings.EqualFold(port, "any") {
+		return cidr
+	}
+	return fmt.Sprintf("%s port %s", cidr, port)
+}
+
+// writeFirewallRulesXML writes firewall rules to an io.Writer in XML format
+func (w *XMLWriter) writeFirewallRulesXML(rules []meraki.FirewallRuleWithNetwork, writer io.Writer) error {
+	// Convert rules to XML-compatible format
+	xmlRules := make([]FirewallRuleXML, len(rules))
+	for i, rule := range rules {
+		xmlRules[i] = FirewallRuleXML{
+			Organization:   rule.Organization,
+			OrganizationID: rule.OrganizationID,
+			NetworkID:      rule.NetworkID,
+			NetworkName:    rule.NetworkName,
+			Layer:          rule.Layer,
+			Number:         rule.Number,
+			Policy:         rule.Policy,
+			Protocol:       rule.Protocol,
+			SrcCIDR:        rule.SrcCIDR,
+			SrcPort:        rule.SrcPort,
+			DestCIDR:       rule.DestCIDR,
+			DestPort:       rule.DestPort,
+			Type:           rule.Type,
+			Value:          rule.Value,
+			Comment:        rule.Comment,
+			SyslogEnabled:  rule.SyslogEnabled,
+		}
+	}
+
+	rulesXML := FirewallRulesXML{Rules: xmlRules}
+
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", w.indent())
+
+	// Write XML header
+	fmt.Fprint(writer, xml.Header)
+
+	if err := encoder.Encode(rulesXML); err != nil {
+		return fmt.Errorf("failed to encode XML: %w", err)
+	}
+
+	return nil
+}
+
+// writeFirewallRulesCSV writes firewall rules to an io.Writer in CSV format, one row per rule
+func (w *CSVWriter) writeFirewallRulesCSV(rules []meraki.FirewallRuleWithNetwork, writer io.Writer) error {
+	csvWriter := w.newCSVWriter(writer)
+	defer csvWriter.Flush()
+
+	// Write header
+	header := []string{"Organization", "Organization ID", "Network ID", "Network Name", "Layer", "Number", "Policy",
+		"Protocol", "Source CIDR", "Source Port", "Destination CIDR", "Destination Port", "Type", "Value", "Comment", "Syslog Enabled"}
+	if err := csvWriter.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	// Write rules
+	for _, rule := range rules {
+		record := []string{
+			rule.Organization,
+			rule.OrganizationID,
+			rule.NetworkID,
+			rule.NetworkName,
+			rule.Layer,
+			fmt.Sprintf("%d", rule.Number),
+			rule.Policy,
+			rule.Protocol,
+			rule.SrcCIDR,
+			rule.SrcPort,
+			rule.DestCIDR,
+			rule.DestPort,
+			rule.Type,
+			rule.Value,
+			rule.Comment,
+			fmt.Sprintf("%t", rule.SyslogEnabled),
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+
+	return nil
+}
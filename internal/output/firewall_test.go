@@ -0,0 +1,76 @@
+package output
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"meraki-info/internal/meraki"
+)
+
+func TestWriters_FirewallRules(t *testing.T) {
+	rules := []meraki.FirewallRuleWithNetwork{
+		{
+			FirewallRule: meraki.FirewallRule{Layer: "l3", Number: 1, Policy: "deny", Protocol: "tcp", SrcCIDR: "192.168.10.0/24", SrcPort: "Any",
+				DestCIDR: "10.0.0.0/8", DestPort: "443", Comment: "Block guest to servers", SyslogEnabled: true},
+			NetworkID: "N_1", NetworkName: "HQ", Organization: "Org A", OrganizationID: "1",
+		},
+		{
+			FirewallRule: meraki.FirewallRule{Layer: "l3", Number: 2, Policy: "allow", Protocol: "Any", SrcCIDR: "Any", SrcPort: "Any",
+				DestCIDR: "Any", DestPort: "Any", Comment: "Default rule"},
+			NetworkID: "N_1", NetworkName: "HQ", Organization: "Org A", OrganizationID: "1",
+		},
+		{
+			FirewallRule: meraki.FirewallRule{Layer: "l7", Number: 1, Policy: "deny", Type: "host", Value: "games.example.com"},
+			NetworkID:    "N_1", NetworkName: "HQ", Organization: "Org A", OrganizationID: "1",
+		},
+	}
+
+	t.Run("text", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := (&TextWriter{}).WriteTo(rules, &buf); err != nil {
+			t.Fatalf("WriteTo failed: %v", err)
+		}
+		output := buf.String()
+		for _, expected := range []string{
+			"Total Rules: 3",
+			"L3 Rule 1:",
+			"  Source: 192.168.10.0/24\n  Destination: 10.0.0.0/8 port 443\n",
+			"  Comment: Default rule",
+			"L7 Rule 1:\n  Organization: Org A\n  Network Name: HQ\n  Network ID: N_1\n  Policy: deny\n  Type: host\n  Value: games.example.com\n",
+		} {
+			if !strings.Contains(output, expected) {
+				t.Errorf("Expected text output to contain %q, got:\n%s", expected, output)
+			}
+		}
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := (&CSVWriter{}).WriteTo(rules, &buf); err != nil {
+			t.Fatalf("WriteTo failed: %v", err)
+		}
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 4 {
+			t.Fatalf("Expected header and 3 rows, got %d:\n%s", len(lines), buf.String())
+		}
+		if lines[2] != "Org A,1,N_1,HQ,l3,2,allow,Any,Any,Any,Any,Any,,,Default rule,false" {
+			t.Errorf("Unexpected default rule row: %s", lines[2])
+		}
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := (&XMLWriter{}).WriteTo(rules, &buf); err != nil {
+			t.Fatalf("WriteTo failed: %v", err)
+		}
+		var decoded FirewallRulesXML
+		if err := xml.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("Failed to parse XML: %v", err)
+		}
+		if len(decoded.Rules) != 3 || decoded.Rules[0].DestPort != "443" || decoded.Rules[2].Value != "games.example.com" {
+			t.Errorf("Unexpected XML rules: %+v", decoded.Rules)
+		}
+	})
+}
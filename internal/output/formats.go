@@ -0,0 +1,105 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format describes an output format selectable with -format
+type Format struct {
+	Name        string
+	Description string
+	ContentType string // MIME type used when POSTing to an -output URL
+	newWriter   func() Writer
+}
+
+// formats is the registry of output formats, in the order they are listed. The first is the default.
+var formats = []Format{
+	{
+		Name:        "text",
+		Description: "Human-readable report (default)",
+		ContentType: "text/plain; charset=utf-8",
+		newWriter:   func() Writer { return &TextWriter{} },
+	},
+	{
+		Name:        "json",
+		Description: "Indented JSON array of records",
+		ContentType: "application/json",
+		newWriter:   func() Writer { return &JSONWriter{} },
+	},
+	{
+		Name:        "ndjson",
+		Description: "Newline-delimited JSON, one record per line; POSTed to an -output URL in batches",
+		ContentType: "application/x-ndjson",
+		newWriter:   func() Writer { return &NDJSONWriter{} },
+	},
+	{
+		Name:        "xml",
+		Description: "XML document with one element per record",
+		ContentType: "application/xml",
+		newWriter:   func() Writer { return &XMLWriter{} },
+	},
+	{
+		Name:        "csv",
+		Description: "Comma-separated values with a header row",
+		ContentType: "text/csv",
+		newWriter:   func() Writer { return &CSVWriter{} },
+	},
+	{
+		Name:        "prometheus",
+		Description: "Prometheus text exposition metrics for down, alerting and licenses",
+		ContentType: "text/plain; version=0.0.4",
+		newWriter:   func() Writer { return &PrometheusWriter{} },
+	},
+	{
+		Name:        "geojson",
+		Description: "GeoJSON FeatureCollection of device locations for locations, down and alerting",
+		ContentType: "application/geo+json",
+		newWriter:   func() Writer { return &GeoJSONWriter{} },
+	},
+	{
+		Name:        "template",
+		Description: "Custom text laid out by the Go text/template given with -template",
+		ContentType: "text/plain; charset=utf-8",
+		newWriter:   func() Writer { return &TemplateWriter{} },
+	},
+}
+
+// Formats returns the registered output formats
+func Formats() []Format {
+	return append([]Format(nil), formats...)
+}
+
+// FormatNames returns the names of the registered output formats
+func FormatNames() []string {
+	names := make([]string, len(formats))
+	for i, format := range formats {
+		names[i] = format.Name
+	}
+	return names
+}
+
+// LookupFormat finds a registered output format by name (case-insensitive)
+func LookupFormat(name string) (Format, bool) {
+	for _, format := range formats {
+		if strings.EqualFold(format.Name, name) {
+			return format, true
+		}
+	}
+	return Format{}, false
+}
+
+// ListFormats writes each registered format name with its description, one per line
+func ListFormats(writer io.Writer) error {
+	width := 0
+	for _, format := range formats {
+		width = max(width, len(format.Name))
+	}
+	for _, format := range formats {
+		if _, err := fmt.Fprintf(writer, "%-*s  %s\n", width, format.Name, format.Description); err != nil {
+			return err
+		}
+	}
+	return nil
+}
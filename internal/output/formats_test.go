@@ -0,0 +1,46 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestListFormats(t *testing.T) {
+	var buf bytes.Buffer
+	if err := ListFormats(&buf); err != nil {
+		t.Fatalf("ListFormats failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != len(Formats()) {
+		t.Fatalf("Expected %d lines, got %d:\n%s", len(Formats()), len(lines), buf.String())
+	}
+	for i, format := range Formats() {
+		if !strings.HasPrefix(lines[i], format.Name+" ") || !strings.HasSuffix(lines[i], format.Description) {
+			t.Errorf("Expected line for format %q with its description, got %q", format.Name, lines[i])
+		}
+	}
+}
+
+func TestLookupFormat(t *testing.T) {
+	for _, name := range FormatNames() {
+		format, ok := LookupFormat(strings.ToUpper(name))
+		if !ok || format.Name != name {
+			t.Errorf("Expected to look up format %q case-insensitively", name)
+		}
+		if format.ContentType != ContentType(name) {
+			t.Errorf("Expected content type %q for %q, got %q", format.ContentType, name, ContentType(name))
+		}
+	}
+
+	if _, ok := NewWriter("prometheus").(*PrometheusWriter); !ok {
+		t.Error("Expected -format prometheus to create the Prometheus writer")
+	}
+	if _, ok := LookupFormat("yaml"); ok {
+		t.Error("Expected unregistered format not to be found")
+	}
+	if _, ok := NewWriter("yaml").(*TextWriter); !ok {
+		t.Error("Expected unknown format to fall back to the text writer")
+	}
+}
@@ -0,0 +1,104 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"meraki-info/internal/meraki"
+)
+
+// GeoJSONWriter writes device locations, or down and alerting devices, as a GeoJSON
+// FeatureCollection (RFC 7946), one Point feature per device, for loading into mapping tools
+type GeoJSONWriter struct {
+	FileOptions // Permission and compression of files created by WriteToFile
+}
+
+// geoJSONFeatureCollection is the top-level GeoJSON object
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+// geoJSONFeature is a device as a GeoJSON Point feature
+type geoJSONFeature struct {
+	Type       string                `json:"type"`
+	Geometry   geoJSONPoint          `json:"geometry"`
+	Properties meraki.DeviceLocation `json:"properties"`
+}
+
+// geoJSONPoint is a GeoJSON Point; coordinates are longitude first
+type geoJSONPoint struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+// WriteToFile writes data to a file in GeoJSON format
+func (w *GeoJSONWriter) WriteToFile(data interface{}, filename string) error {
+	return w.writeFile(filename, func(writer io.Writer) error {
+		return w.WriteTo(data, writer)
+	})
+}
+
+// WriteTo writes data to an io.Writer in GeoJSON format
+func (w *GeoJSONWriter) WriteTo(data interface{}, writer io.Writer) error {
+	switch v := data.(type) {
+	case []meraki.DeviceLocation:
+		return w.writeDeviceLocationsGeoJSON(v, writer)
+	case []meraki.Device:
+		locations := make([]meraki.DeviceLocation, len(v))
+		for i, device := range v {
+			locations[i] = deviceLocation(meraki.DeviceWithNetwork{Device: device, NetworkID: device.NetworkID})
+		}
+		return w.writeDeviceLocationsGeoJSON(locations, writer)
+	case []meraki.DeviceWithNetwork:
+		locations := make([]meraki.DeviceLocation, len(v))
+		for i, device := range v {
+			locations[i] = deviceLocation(device)
+		}
+		return w.writeDeviceLocationsGeoJSON(locations, writer)
+	default:
+		return fmt.Errorf("unsupported data type for geojson format: %T", data)
+	}
+}
+
+// deviceLocation converts a down or alerting device to its location record
+func deviceLocation(device meraki.DeviceWithNetwork) meraki.DeviceLocation {
+	return meraki.DeviceLocation{
+		Serial:         device.Serial,
+		Name:           device.Name,
+		Model:          device.Model,
+		ProductType:    device.ProductType,
+		Address:        device.Address,
+		Lat:            device.Lat,
+		Lng:            device.Lng,
+		NetworkID:      device.NetworkID,
+		NetworkName:    device.NetworkName,
+		Organization:   device.Organization,
+		OrganizationID: device.OrganizationID,
+	}
+}
+
+// writeDeviceLocationsGeoJSON writes one feature per device with coordinates; devices that have
+// not been placed on the map have no meaningful point and are skipped
+func (w *GeoJSONWriter) writeDeviceLocationsGeoJSON(locations []meraki.DeviceLocation, writer io.Writer) error {
+	collection := geoJSONFeatureCollection{Type: "FeatureCollection", Features: make([]geoJSONFeature, 0, len(locations))}
+	for _, location := range locations {
+		if location.Lat == 0 && location.Lng == 0 {
+			continue
+		}
+		collection.Features = append(collection.Features, geoJSONFeature{
+			Type:       "Feature",
+			Geometry:   geoJSONPoint{Type: "Point", Coordinates: [2]float64{location.Lng, location.Lat}},
+			Properties: location,
+		})
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(collection); err != nil {
+		return fmt.Errorf("failed to encode GeoJSON: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"meraki-info/internal/meraki"
+)
+
+func TestGeoJSONWriter_DeviceLocations(t *testing.T) {
+	locations := []meraki.DeviceLocation{
+		{Serial: "Q2AA-0001", Name: "HQ AP", Model: "MR46", Lat: 52.370216, Lng: 4.895168, NetworkID: "N_1", NetworkName: "HQ"},
+		{Serial: "Q2AA-0002", Model: "MS120-8", NetworkID: "N_1", NetworkName: "HQ"},
+		{Serial: "Q2AA-0003", Model: "MX68", Lat: -33.8688, Lng: 151.2093, NetworkID: "N_2", NetworkName: "Sydney"},
+	}
+
+	var buf bytes.Buffer
+	if err := (&GeoJSONWriter{}).WriteTo(locations, &buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+
+	var collection struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Type     string `json:"type"`
+			Geometry struct {
+				Type        string    `json:"type"`
+				Coordinates []float64 `json:"coordinates"`
+			} `json:"geometry"`
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &collection); err != nil {
+		t.Fatalf("Failed to parse GeoJSON: %v", err)
+	}
+	if collection.Type != "FeatureCollection" || len(collection.Features) != 2 {
+		t.Fatalf("Expected a collection of the two devices with coordinates, got %s", buf.String())
+	}
+
+	feature := collection.Features[0]
+	if feature.Type != "Feature" || feature.Geometry.Type != "Point" {
+		t.Errorf("Expected a Point feature, got %+v", feature)
+	}
+	if coordinates := feature.Geometry.Coordinates; len(coordinates) != 2 || coordinates[0] != 4.895168 || coordinates[1] != 52.370216 {
+		t.Errorf("Expected longitude, latitude coordinates, got %v", coordinates)
+	}
+	if feature.Properties["serial"] != "Q2AA-0001" || feature.Properties["network_name"] != "HQ" {
+		t.Errorf("Expected the device and network as properties, got %v", feature.Properties)
+	}
+	if collection.Features[1].Properties["serial"] != "Q2AA-0003" {
+		t.Errorf("Expected the device without coordinates to be skipped, got %v", collection.Features[1].Properties)
+	}
+
+	if err := (&GeoJSONWriter{}).WriteTo([]meraki.Route{}, &buf); err == nil {
+		t.Error("Expected an error for data other than device locations")
+	}
+}
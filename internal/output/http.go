@@ -0,0 +1,148 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"meraki-info/internal/meraki"
+)
+
+// HTTPWriter posts serialized output to a URL instead of writing a file. WriteToFile
+// treats its filename as the destination URL; WriteTo passes through to the inner writer.
+type HTTPWriter struct {
+	writer      Writer
+	contentType string
+	headers     http.Header
+	httpClient  *http.Client
+	retryConfig meraki.RetryConfig
+}
+
+// NewHTTPWriter creates a writer that POSTs outputType-formatted data with the given extra headers
+func NewHTTPWriter(writer Writer, outputType string, headers http.Header) *HTTPWriter {
+	return &HTTPWriter{
+		writer:      writer,
+		contentType: ContentType(outputType),
+		headers:     headers,
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		retryConfig: meraki.DefaultRetryConfig(),
+	}
+}
+
+// IsURL reports whether an -output destination is an HTTP(S) URL
+func IsURL(destination string) bool {
+	lower := strings.ToLower(destination)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+// ContentType returns the MIME type for an output format
+func ContentType(outputType string) string {
+	if format, ok := LookupFormat(outputType); ok {
+		return format.ContentType
+	}
+	return formats[0].ContentType
+}
+
+// ParseHeader splits an "Name: value" -output-header flag value
+func ParseHeader(spec string) (name, value string, err error) {
+	name, value, found := strings.Cut(spec, ":")
+	name = strings.TrimSpace(name)
+	if !found || name == "" || strings.ContainsAny(name, " \t") {
+		return "", "", fmt.Errorf("invalid -output-header '%s'. Expected 'Name: value'", spec)
+	}
+	return name, strings.TrimSpace(value), nil
+}
+
+// SetRetryConfig sets the retry behavior for 5xx and 429 responses and connection errors
+func (w *HTTPWriter) SetRetryConfig(config meraki.RetryConfig) {
+	w.retryConfig = config
+}
+
+// ndjsonBatchSize is the number of records POSTed per request in NDJSON format
+var ndjsonBatchSize = 500
+
+// WriteToFile posts data to the URL given as filename. NDJSON records are posted in batches.
+func (w *HTTPWriter) WriteToFile(data interface{}, url string) error {
+	if _, ok := w.writer.(*NDJSONWriter); ok && reflect.ValueOf(data).Kind() == reflect.Slice {
+		return w.postBatches(reflect.ValueOf(data), url)
+	}
+
+	var body bytes.Buffer
+	if err := w.writer.WriteTo(data, &body); err != nil {
+		return err
+	}
+	return w.post(url, body.Bytes())
+}
+
+// WriteTo writes data to an io.Writer using the inner writer
+func (w *HTTPWriter) WriteTo(data interface{}, writer io.Writer) error {
+	return w.writer.WriteTo(data, writer)
+}
+
+// postBatches posts the records of a slice ndjsonBatchSize at a time, each request retried on its
+// own, so an ingestion endpoint receives records incrementally and a failed batch does not resend
+// the batches already delivered
+func (w *HTTPWriter) postBatches(records reflect.Value, url string) error {
+	for start := 0; start < records.Len(); start += ndjsonBatchSize {
+		end := min(start+ndjsonBatchSize, records.Len())
+
+		var body bytes.Buffer
+		if err := w.writer.WriteTo(records.Slice(start, end).Interface(), &body); err != nil {
+			return err
+		}
+		if err := w.post(url, body.Bytes()); err != nil {
+			return fmt.Errorf("%w (%d of %d records were delivered)", err, start, records.Len())
+		}
+	}
+	return nil
+}
+
+// post sends body to url, retrying retryable failures with exponential backoff
+func (w *HTTPWriter) post(url string, body []byte) error {
+	var lastErr error
+	var lastResp *http.Response
+	for attempt := 0; attempt <= w.retryConfig.MaxRetries; attempt++ {
+		if attempt > 0 {
+			backoff := w.retryConfig.RetryDelay(attempt-1, lastResp)
+			slog.Info("Output delivery failed, retrying", "url", url, "error", lastErr, "attempt", attempt, "backoff", backoff)
+			time.Sleep(backoff)
+		}
+
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("failed to create output request: %w", err)
+		}
+		for name, values := range w.headers {
+			for _, value := range values {
+				req.Header.Add(name, value)
+			}
+		}
+		req.Header.Set("Content-Type", w.contentType)
+
+		resp, err := w.httpClient.Do(req)
+		lastResp = resp
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			slog.Info("Output delivered", "url", url, "status", resp.StatusCode, "bytes", len(body))
+			return nil
+		}
+
+		lastErr = fmt.Errorf("output endpoint returned status %d", resp.StatusCode)
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+			break
+		}
+	}
+
+	return fmt.Errorf("failed to deliver output to %s: %w", url, lastErr)
+}
@@ -0,0 +1,173 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"meraki-info/internal/meraki"
+)
+
+func TestHTTPWriter_WriteToFile(t *testing.T) {
+	devices := []meraki.Device{{Serial: "Q2XX-1111-2222", Status: "offline"}}
+	retryConfig := meraki.RetryConfig{MaxRetries: 2, InitialInterval: time.Millisecond, MaxInterval: time.Millisecond, Multiplier: 2}
+
+	t.Run("posts body with headers and content type", func(t *testing.T) {
+		var body []byte
+		var header http.Header
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				t.Errorf("Expected POST, got %s", r.Method)
+			}
+			body, _ = io.ReadAll(r.Body)
+			header = r.Header
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer server.Close()
+
+		headers := http.Header{}
+		headers.Add("Authorization", "Bearer x")
+		writer := NewHTTPWriter(&JSONWriter{}, "json", headers)
+		if err := writer.WriteToFile(devices, server.URL+"/hook"); err != nil {
+			t.Fatalf("WriteToFile failed: %v", err)
+		}
+
+		if header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected Content-Type application/json, got '%s'", header.Get("Content-Type"))
+		}
+		if header.Get("Authorization") != "Bearer x" {
+			t.Errorf("Expected Authorization header, got '%s'", header.Get("Authorization"))
+		}
+		var posted []meraki.Device
+		if err := json.Unmarshal(body, &posted); err != nil {
+			t.Fatalf("Failed to parse posted body: %v", err)
+		}
+		if len(posted) != 1 || posted[0].Serial != "Q2XX-1111-2222" {
+			t.Errorf("Unexpected posted devices: %+v", posted)
+		}
+	})
+
+	t.Run("retries server errors", func(t *testing.T) {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if attempts < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		writer := NewHTTPWriter(&CSVWriter{}, "csv", nil)
+		writer.SetRetryConfig(retryConfig)
+		if err := writer.WriteToFile(devices, server.URL); err != nil {
+			t.Fatalf("Expected delivery after retries, got: %v", err)
+		}
+		if attempts != 3 {
+			t.Errorf("Expected 3 attempts, got %d", attempts)
+		}
+	})
+
+	t.Run("fails after retries are exhausted", func(t *testing.T) {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		writer := NewHTTPWriter(&JSONWriter{}, "json", nil)
+		writer.SetRetryConfig(retryConfig)
+		if err := writer.WriteToFile(devices, server.URL); err == nil {
+			t.Fatal("Expected delivery error")
+		}
+		if attempts != 3 {
+			t.Errorf("Expected 3 attempts, got %d", attempts)
+		}
+	})
+
+	t.Run("posts NDJSON in batches retried on their own", func(t *testing.T) {
+		originalBatchSize := ndjsonBatchSize
+		ndjsonBatchSize = 2
+		defer func() { ndjsonBatchSize = originalBatchSize }()
+
+		var batches []string
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if attempts == 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			if r.Header.Get("Content-Type") != "application/x-ndjson" {
+				t.Errorf("Expected Content-Type application/x-ndjson, got '%s'", r.Header.Get("Content-Type"))
+			}
+			body, _ := io.ReadAll(r.Body)
+			batches = append(batches, string(body))
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		records := []meraki.Device{{Serial: "Q2XX-1"}, {Serial: "Q2XX-2"}, {Serial: "Q2XX-3"}, {Serial: "Q2XX-4"}, {Serial: "Q2XX-5"}}
+		writer := NewHTTPWriter(&NDJSONWriter{}, "ndjson", nil)
+		writer.SetRetryConfig(retryConfig)
+		if err := writer.WriteToFile(records, server.URL); err != nil {
+			t.Fatalf("WriteToFile failed: %v", err)
+		}
+
+		if len(batches) != 3 || attempts != 4 {
+			t.Fatalf("Expected 3 batches with the second retried once, got %d batches in %d attempts: %q", len(batches), attempts, batches)
+		}
+		var serials []string
+		for _, batch := range batches {
+			if !strings.HasSuffix(batch, "\n") {
+				t.Errorf("Expected each batch to end with a newline, got %q", batch)
+			}
+			for _, line := range strings.Split(strings.TrimSuffix(batch, "\n"), "\n") {
+				var device meraki.Device
+				if err := json.Unmarshal([]byte(line), &device); err != nil {
+					t.Fatalf("Expected one JSON record per line, got %q: %v", line, err)
+				}
+				serials = append(serials, device.Serial)
+			}
+		}
+		if strings.Join(serials, ",") != "Q2XX-1,Q2XX-2,Q2XX-3,Q2XX-4,Q2XX-5" {
+			t.Errorf("Expected every record once and in order, got %v", serials)
+		}
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		writer := NewHTTPWriter(&JSONWriter{}, "json", nil)
+		writer.SetRetryConfig(retryConfig)
+		if err := writer.WriteToFile(devices, server.URL); err == nil {
+			t.Fatal("Expected delivery error")
+		}
+		if attempts != 1 {
+			t.Errorf("Expected 1 attempt, got %d", attempts)
+		}
+	})
+}
+
+func TestParseHeader(t *testing.T) {
+	name, value, err := ParseHeader("Authorization: Bearer x")
+	if err != nil || name != "Authorization" || value != "Bearer x" {
+		t.Errorf("Unexpected result: %q %q %v", name, value, err)
+	}
+	for _, spec := range []string{"no-colon", ": value", "Bad Name: value"} {
+		if _, _, err := ParseHeader(spec); err == nil {
+			t.Errorf("Expected error for %q", spec)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"meraki-info/internal/meraki"
+)
+
+// OrganizationLicenseSummariesXML represents a collection of per-organization license summaries in XML format
+type OrganizationLicenseSummariesXML struct {
+	XMLName       xml.Name                            `xml:"licenseSummaries"`
+	Organizations []meraki.OrganizationLicenseSummary `xml:"organization"`
+}
+
+// writeOrganizationLicenseSummaries writes per-organization license summaries to an io.Writer in text format
+func (w *TextWriter) writeOrganizationLicenseSummaries(summaries []meraki.OrganizationLicenseSummary, writer io.Writer) error {
+	// Write header
+	fmt.Fprintf(writer, "Meraki License Summary\n")
+	fmt.Fprintf(writer, "======================\n\n")
+	fmt.Fprintf(writer, "Total Organizations: %d\n\n", len(summaries))
+
+	// Write summaries
+	for _, summary := range summaries {
+		fmt.Fprintf(writer, "Organization: %s\n", labelOrID(summary.Organization, summary.OrganizationID))
+		fmt.Fprintf(writer, "  Organization ID: %s\n", summary.OrganizationID)
+		fmt.Fprintf(writer, "  Total Licenses: %d\n", summary.Total)
+		for _, state := range summary.States {
+			fmt.Fprintf(writer, "  %s: %d\n", labelOrID(state.State, "unknown"), state.Count)
+		}
+		if summary.SoonestExpiration != "" {
+			fmt.Fprintf(writer, "  Soonest Expiration: %s\n", summary.SoonestExpiration)
+			fmt.Fprintf(writer, "  Latest Expiration: %s\n", summary.LatestExpiration)
+		}
+		fmt.Fprintf(writer, "  Total Duration (Days): %d\n", summary.TotalDurationDays)
+		if _, err := fmt.Fprintf(writer, "\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeOrganizationLicenseSummariesXML writes per-organization license summaries to an io.Writer in XML format
+func (w *XMLWriter) writeOrganizationLicenseSummariesXML(summaries []meraki.OrganizationLicenseSummary, writer io.Writer) error {
+	summariesXML := OrganizationLicenseSummariesXML{Organizations: summaries}
+
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", w.indent())
+
+	// Write XML header
+	fmt.Fprint(writer, xml.Header)
+
+	if err := encoder.Encode(summariesXML); err != nil {
+		return fmt.Errorf("failed to encode XML: %w", err)
+	}
+
+	return nil
+}
+
+// writeOrganizationLicenseSummariesCSV writes per-organization license summaries to an io.Writer in
+// CSV format, one row per organization. States are joined as "state=count" with "; ".
+func (w *CSVWriter) writeOrganizationLicenseSummariesCSV(summaries []meraki.OrganizationLicenseSummary, writer io.Writer) error {
+	csvWriter := w.newCSVWriter(writer)
+	defer csvWriter.Flush()
+
+	// Write header
+	header := []string{"Organization", "Organization ID", "Total Licenses", "States", "Soonest Expiration", "Latest Expiration", "Total Duration Days"}
+	if err := csvWriter.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	// Write summaries
+	for _, summary := range summaries {
+		states := make([]string, len(summary.States))
+		for i, state := range summary.States {
+			states[i] = fmt.Sprintf("%s=%d", state.State, state.Count)
+		}
+		record := []string{
+			summary.Organization,
+			summary.OrganizationID,
+			strconv.Itoa(summary.Total),
+			strings.Join(states, "; "),
+			summary.SoonestExpiration,
+			summary.LatestExpiration,
+			strconv.Itoa(summary.TotalDurationDays),
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+
+	return nil
+}
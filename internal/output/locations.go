@@ -0,0 +1,117 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+
+	"meraki-info/internal/meraki"
+)
+
+// DeviceLocationsXML represents a collection of device locations in XML format
+type DeviceLocationsXML struct {
+	XMLName   xml.Name            `xml:"locations"`
+	Locations []DeviceLocationXML `xml:"device"`
+}
+
+// DeviceLocationXML represents the location of a single device in XML format
+type DeviceLocationXML struct {
+	Serial         string  `xml:"serial"`
+	Name           string  `xml:"name,omitempty"`
+	Model          string  `xml:"model"`
+	ProductType    string  `xml:"productType,omitempty"`
+	Address        string  `xml:"address,omitempty"`
+	Lat            float64 `xml:"lat"`
+	Lng            float64 `xml:"lng"`
+	NetworkID      string  `xml:"networkId"`
+	NetworkName    string  `xml:"networkName"`
+	Organization   string  `xml:"organization,omitempty"`
+	OrganizationID string  `xml:"organizationId,omitempty"`
+}
+
+// writeDeviceLocations writes device locations to an io.Writer in text format, one section per network
+func (w *TextWriter) writeDeviceLocations(locations []meraki.DeviceLocation, writer io.Writer) error {
+	// Write header
+	fmt.Fprintf(writer, "Meraki Device Locations\n")
+	fmt.Fprintf(writer, "=======================\n\n")
+	fmt.Fprintf(writer, "Total Devices: %d\n\n", len(locations))
+
+	// Write devices, starting a section whenever the network changes
+	for i, location := range locations {
+		if i == 0 || location.NetworkID != locations[i-1].NetworkID || location.OrganizationID != locations[i-1].OrganizationID {
+			fmt.Fprintf(writer, "Network: %s (%s)\n", labelOrID(location.NetworkName, location.NetworkID), location.NetworkID)
+			if location.Organization != "" {
+				fmt.Fprintf(writer, "Organization: %s\n", location.Organization)
+			}
+		}
+		fmt.Fprintf(writer, "  %s %s (%s): %.6f, %.6f\n", location.Serial, location.Name, location.Model, location.Lat, location.Lng)
+		if location.Address != "" {
+			fmt.Fprintf(writer, "    Address: %s\n", location.Address)
+		}
+		if i == len(locations)-1 || location.NetworkID != locations[i+1].NetworkID || location.OrganizationID != locations[i+1].OrganizationID {
+			if _, err := fmt.Fprintf(writer, "\n"); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeDeviceLocationsXML writes device locations to an io.Writer in XML format
+func (w *XMLWriter) writeDeviceLocationsXML(locations []meraki.DeviceLocation, writer io.Writer) error {
+	// Convert locations to XML-compatible format
+	xmlLocations := make([]DeviceLocationXML, len(locations))
+	for i, location := range locations {
+		xmlLocations[i] = DeviceLocationXML(location)
+	}
+
+	locationsXML := DeviceLocationsXML{Locations: xmlLocations}
+
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", w.indent())
+
+	// Write XML header
+	fmt.Fprint(writer, xml.Header)
+
+	if err := encoder.Encode(locationsXML); err != nil {
+		return fmt.Errorf("failed to encode XML: %w", err)
+	}
+
+	return nil
+}
+
+// writeDeviceLocationsCSV writes device locations to an io.Writer in CSV format
+func (w *CSVWriter) writeDeviceLocationsCSV(locations []meraki.DeviceLocation, writer io.Writer) error {
+	csvWriter := w.newCSVWriter(writer)
+	defer csvWriter.Flush()
+
+	// Write header
+	header := []string{"Organization", "Organization ID", "Network ID", "Network Name", "Serial", "Name", "Model", "Product Type", "Address", "Lat", "Lng"}
+	if err := csvWriter.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	// Write locations
+	for _, location := range locations {
+		record := []string{
+			location.Organization,
+			location.OrganizationID,
+			location.NetworkID,
+			location.NetworkName,
+			location.Serial,
+			location.Name,
+			location.Model,
+			location.ProductType,
+			location.Address,
+			strconv.FormatFloat(location.Lat, 'f', -1, 64),
+			strconv.FormatFloat(location.Lng, 'f', -1, 64),
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+
+	return nil
+}
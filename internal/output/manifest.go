@@ -0,0 +1,68 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// ManifestEntry describes one output file written by a run
+type ManifestEntry struct {
+	File           string `json:"file"`
+	OrganizationID string `json:"organizationId,omitempty"` // Empty when the file covers every organization
+	NetworkID      string `json:"networkId,omitempty"`      // Empty when the file covers every network
+	Records        int    `json:"records"`
+	Size           int64  `json:"size"`
+}
+
+// Manifest lists the output files written by a run. The manifest file is rewritten after each
+// added file, so it is complete up to the point where a run stops.
+type Manifest struct {
+	path  string
+	mode  os.FileMode
+	mu    sync.Mutex
+	files []ManifestEntry
+}
+
+// NewManifest creates a manifest that is written to path with mode, or DefaultFileMode when mode is zero
+func NewManifest(path string, mode os.FileMode) *Manifest {
+	return &Manifest{path: path, mode: mode}
+}
+
+// Add records entry, taking its size from the written file, and rewrites the manifest. A file
+// written again, as when several networks share an output file without tokens, replaces its
+// earlier entry.
+func (m *Manifest) Add(entry ManifestEntry) error {
+	info, err := os.Stat(entry.File)
+	if err != nil {
+		return fmt.Errorf("failed to add %s to manifest: %w", entry.File, err)
+	}
+	entry.Size = info.Size()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	replaced := false
+	for i := range m.files {
+		if m.files[i].File == entry.File {
+			m.files[i] = entry
+			replaced = true
+		}
+	}
+	if !replaced {
+		m.files = append(m.files, entry)
+	}
+
+	return atomicWriteToFile(m.path, m.mode, func(writer io.Writer) error {
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(struct {
+			Files []ManifestEntry `json:"files"`
+		}{m.files}); err != nil {
+			return fmt.Errorf("failed to encode manifest: %w", err)
+		}
+		return nil
+	})
+}
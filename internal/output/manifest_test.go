@@ -0,0 +1,45 @@
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestManifest_Add(t *testing.T) {
+	dir := t.TempDir()
+	outputFile := filepath.Join(dir, "routes.json")
+	manifestFile := filepath.Join(dir, "manifest.json")
+	manifest := NewManifest(manifestFile, 0)
+
+	if err := manifest.Add(ManifestEntry{File: filepath.Join(dir, "missing.json")}); err == nil {
+		t.Error("Expected an error for a file that was not written")
+	}
+
+	// A shared output file written for two networks keeps only the last entry
+	for _, entry := range []ManifestEntry{{NetworkID: "N_1", Records: 3}, {NetworkID: "N_2", Records: 1}} {
+		if err := os.WriteFile(outputFile, []byte("[{}]"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		entry.File = outputFile
+		if err := manifest.Add(entry); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+
+	content, err := os.ReadFile(manifestFile)
+	if err != nil {
+		t.Fatalf("Expected the manifest to be written: %v", err)
+	}
+	var written struct {
+		Files []ManifestEntry `json:"files"`
+	}
+	if err := json.Unmarshal(content, &written); err != nil {
+		t.Fatalf("Failed to parse manifest: %v", err)
+	}
+	expected := ManifestEntry{File: outputFile, NetworkID: "N_2", Records: 1, Size: 4}
+	if len(written.Files) != 1 || written.Files[0] != expected {
+		t.Errorf("Expected %+v, got %+v", expected, written.Files)
+	}
+}
@@ -0,0 +1,73 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// MultiWriter fans out the same data to several writers
+type MultiWriter struct {
+	writers []Writer
+}
+
+// NewMultiWriter creates a writer that calls each of the given writers in order
+func NewMultiWriter(writers ...Writer) Writer {
+	return &MultiWriter{writers: writers}
+}
+
+// WriteToFile writes data with every writer, collecting all errors
+func (w *MultiWriter) WriteToFile(data interface{}, filename string) error {
+	var errs []error
+	for _, writer := range w.writers {
+		if err := writer.WriteToFile(data, filename); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WriteTo writes data with every writer, collecting all errors
+func (w *MultiWriter) WriteTo(data interface{}, writer io.Writer) error {
+	var errs []error
+	for _, wr := range w.writers {
+		if err := wr.WriteTo(data, writer); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// DestinationWriter writes to its own fixed destination regardless of the
+// file or io.Writer it is given, so it can ride along in a MultiWriter
+type DestinationWriter struct {
+	writer Writer
+	path   string
+}
+
+// NewDestinationWriter creates a writer bound to path, "-" meaning stdout, with opts applied as by NewWriter
+func NewDestinationWriter(outputType, path string, opts ...Option) Writer {
+	return &DestinationWriter{writer: NewWriter(outputType, opts...), path: path}
+}
+
+// WriteToFile writes data to the writer's own destination, ignoring filename
+func (w *DestinationWriter) WriteToFile(data interface{}, filename string) error {
+	return w.write(data)
+}
+
+// WriteTo writes data to the writer's own destination, ignoring writer
+func (w *DestinationWriter) WriteTo(data interface{}, writer io.Writer) error {
+	return w.write(data)
+}
+
+// write sends data to stdout or the bound file path
+func (w *DestinationWriter) write(data interface{}) error {
+	if w.path == "" || w.path == "-" {
+		return w.writer.WriteTo(data, os.Stdout)
+	}
+	if err := w.writer.WriteToFile(data, w.path); err != nil {
+		return fmt.Errorf("failed to write secondary output %s: %w", w.path, err)
+	}
+	return nil
+}
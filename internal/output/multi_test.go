@@ -0,0 +1,89 @@
+package output
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"meraki-info/internal/meraki"
+)
+
+// recordingWriter captures the data it is asked to write
+type recordingWriter struct {
+	received []interface{}
+	err      error
+}
+
+func (w *recordingWriter) WriteToFile(data interface{}, filename string) error {
+	w.received = append(w.received, data)
+	return w.err
+}
+
+func (w *recordingWriter) WriteTo(data interface{}, writer io.Writer) error {
+	w.received = append(w.received, data)
+	return w.err
+}
+
+func TestMultiWriter_WriteTo(t *testing.T) {
+	routes := []meraki.Route{{ID: "route1", Subnet: "10.0.0.0/8"}}
+
+	first := &recordingWriter{}
+	second := &recordingWriter{}
+	writer := NewMultiWriter(first, second)
+
+	if err := writer.WriteTo(routes, io.Discard); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(first.received) != 1 || len(second.received) != 1 {
+		t.Fatalf("Expected each writer to be called once, got %d and %d", len(first.received), len(second.received))
+	}
+
+	firstRoutes := first.received[0].([]meraki.Route)
+	secondRoutes := second.received[0].([]meraki.Route)
+	if firstRoutes[0].ID != secondRoutes[0].ID || firstRoutes[0].Subnet != secondRoutes[0].Subnet {
+		t.Errorf("Writers received different data: %+v vs %+v", firstRoutes, secondRoutes)
+	}
+}
+
+func TestMultiWriter_JoinsErrors(t *testing.T) {
+	errFirst := errors.New("first failed")
+	errSecond := errors.New("second failed")
+	first := &recordingWriter{err: errFirst}
+	second := &recordingWriter{err: errSecond}
+
+	err := NewMultiWriter(first, second).WriteTo([]meraki.Route{}, io.Discard)
+	if !errors.Is(err, errFirst) || !errors.Is(err, errSecond) {
+		t.Errorf("Expected joined error, got: %v", err)
+	}
+	if len(second.received) != 1 {
+		t.Error("Expected second writer to be called even after first failed")
+	}
+}
+
+func TestMultiWriter_DestinationWriter(t *testing.T) {
+	routes := []meraki.Route{{ID: "route1", Name: "Test Route 1", Subnet: "10.0.0.0/8"}}
+	archive := filepath.Join(t.TempDir(), "routes.json")
+
+	var stdout bytes.Buffer
+	writer := NewMultiWriter(NewWriter("text"), NewDestinationWriter("json", archive))
+	if err := writer.WriteTo(routes, &stdout); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !strings.Contains(stdout.String(), "Test Route 1") {
+		t.Error("Expected primary text output to contain route name")
+	}
+
+	content, err := os.ReadFile(archive)
+	if err != nil {
+		t.Fatalf("Failed to read secondary output: %v", err)
+	}
+	if !strings.Contains(string(content), `"name": "Test Route 1"`) {
+		t.Errorf("Expected secondary JSON output to contain route name, got: %s", string(content))
+	}
+}
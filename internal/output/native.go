@@ -0,0 +1,82 @@
+package output
+
+import (
+	"meraki-info/internal/meraki"
+)
+
+// NativeMeta carries the organization and network context that the consolidated
+// types otherwise flatten into snake_case fields
+type NativeMeta struct {
+	OrganizationID   string `json:"organizationId,omitempty"`
+	OrganizationName string `json:"organizationName,omitempty"`
+	NetworkID        string `json:"networkId,omitempty"`
+	NetworkName      string `json:"networkName,omitempty"`
+}
+
+// nativeRoute is a Meraki route with its context nested under meta
+type nativeRoute struct {
+	meraki.Route
+	Meta NativeMeta `json:"meta"`
+}
+
+// nativeDevice is a Meraki device with its context nested under meta
+type nativeDevice struct {
+	meraki.Device
+	Meta NativeMeta `json:"meta"`
+}
+
+// nativeLicense is a Meraki license with its context nested under meta
+type nativeLicense struct {
+	meraki.License
+	Meta NativeMeta `json:"meta"`
+}
+
+// toNativeJSON converts consolidated types into records that keep the Meraki JSON field
+// names verbatim. Other data is returned unchanged since it has no injected fields.
+func toNativeJSON(data interface{}) interface{} {
+	switch v := data.(type) {
+	case []meraki.RouteWithNetwork:
+		records := make([]nativeRoute, len(v))
+		for i, route := range v {
+			records[i] = nativeRoute{
+				Route: route.Route,
+				Meta: NativeMeta{
+					OrganizationID:   route.OrganizationID,
+					OrganizationName: route.Organization,
+					NetworkID:        route.NetworkID,
+					NetworkName:      route.NetworkName,
+				},
+			}
+		}
+		return records
+	case []meraki.DeviceWithNetwork:
+		records := make([]nativeDevice, len(v))
+		for i, device := range v {
+			records[i] = nativeDevice{
+				Device: device.Device,
+				Meta: NativeMeta{
+					OrganizationID:   device.OrganizationID,
+					OrganizationName: device.Organization,
+					NetworkID:        device.NetworkID,
+					NetworkName:      device.NetworkName,
+				},
+			}
+		}
+		return records
+	case []meraki.LicenseWithNetwork:
+		records := make([]nativeLicense, len(v))
+		for i, license := range v {
+			records[i] = nativeLicense{
+				License: license.License,
+				Meta: NativeMeta{
+					OrganizationID:   license.OrganizationID,
+					OrganizationName: license.Organization,
+					NetworkID:        license.NetworkID,
+				},
+			}
+		}
+		return records
+	default:
+		return data
+	}
+}
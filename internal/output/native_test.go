@@ -0,0 +1,96 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"meraki-info/internal/meraki"
+)
+
+func TestJSONWriter_Native(t *testing.T) {
+	devices := []meraki.DeviceWithNetwork{
+		{
+			Device: meraki.Device{
+				Serial:         "Q2XX-1111-2222",
+				Model:          "MX64",
+				NetworkID:      "N_1",
+				Status:         "offline",
+				LastReportedAt: "2026-01-01T00:00:00Z",
+			},
+			NetworkName:    "Store 1",
+			NetworkID:      "N_1",
+			Organization:   "Test Org",
+			OrganizationID: "123",
+		},
+	}
+
+	var buf bytes.Buffer
+	writer := &JSONWriter{Native: true}
+	if err := writer.WriteTo(devices, &buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+
+	var records []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &records); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+	record := records[0]
+
+	for _, field := range []string{"serial", "model", "networkId", "status", "lastReportedAt", "meta"} {
+		if _, ok := record[field]; !ok {
+			t.Errorf("Expected native field %q in output", field)
+		}
+	}
+	for _, field := range []string{"network_id", "network_name", "organization", "organization_id"} {
+		if _, ok := record[field]; ok {
+			t.Errorf("Did not expect flattened field %q in output", field)
+		}
+	}
+
+	meta, ok := record["meta"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected meta object, got %T", record["meta"])
+	}
+	expected := map[string]string{
+		"organizationId":   "123",
+		"organizationName": "Test Org",
+		"networkId":        "N_1",
+		"networkName":      "Store 1",
+	}
+	for key, value := range expected {
+		if meta[key] != value {
+			t.Errorf("Expected meta.%s %q, got %v", key, value, meta[key])
+		}
+	}
+}
+
+func TestJSONWriter_NativeRoutes(t *testing.T) {
+	routes := []meraki.RouteWithNetwork{
+		{
+			Route:        meraki.Route{Subnet: "10.0.0.0/24", GatewayIP: "10.0.0.1", GatewayVlan: 10},
+			NetworkID:    "N_1",
+			NetworkName:  "Store 1",
+			Organization: "Test Org",
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := (&JSONWriter{Native: true}).WriteTo(routes, &buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+
+	var records []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &records); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if _, ok := records[0]["gatewayVlanId"]; !ok {
+		t.Error("Expected native field gatewayVlanId in output")
+	}
+	if _, ok := records[0]["network_name"]; ok {
+		t.Error("Did not expect flattened field network_name in output")
+	}
+}
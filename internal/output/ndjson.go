@@ -0,0 +1,42 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// NDJSONWriter writes newline-delimited JSON, one compact record per line, as expected by log
+// ingestion endpoints
+type NDJSONWriter struct {
+	FileOptions // Permission and compression of files created by WriteToFile
+}
+
+// WriteToFile writes data to a file in NDJSON format
+func (w *NDJSONWriter) WriteToFile(data interface{}, filename string) error {
+	return w.writeFile(filename, func(writer io.Writer) error {
+		return w.WriteTo(data, writer)
+	})
+}
+
+// WriteTo writes each record of a slice to an io.Writer as one line of JSON. Data that is not a
+// slice, such as a summary, is written as a single line.
+func (w *NDJSONWriter) WriteTo(data interface{}, writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+
+	value := reflect.ValueOf(data)
+	if value.Kind() != reflect.Slice {
+		if err := encoder.Encode(data); err != nil {
+			return fmt.Errorf("failed to encode NDJSON: %w", err)
+		}
+		return nil
+	}
+
+	for i := 0; i < value.Len(); i++ {
+		if err := encoder.Encode(value.Index(i).Interface()); err != nil {
+			return fmt.Errorf("failed to encode NDJSON record %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
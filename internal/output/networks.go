@@ -0,0 +1,131 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"meraki-info/internal/meraki"
+)
+
+// NetworksWithOrganizationXML represents a collection of networks in XML format
+type NetworksWithOrganizationXML struct {
+	XMLName  xml.Name                     `xml:"networks"`
+	Networks []NetworkWithOrganizationXML `xml:"network"`
+}
+
+// NetworkWithOrganizationXML represents a single network in XML format
+type NetworkWithOrganizationXML struct {
+	Organization     string   `xml:"organization,omitempty"`
+	OrganizationID   string   `xml:"organizationId,omitempty"`
+	ID               string   `xml:"id"`
+	Name             string   `xml:"name"`
+	ProductTypes     []string `xml:"productTypes>productType,omitempty"`
+	TimeZone         string   `xml:"timeZone,omitempty"`
+	Tags             []string `xml:"tags>tag,omitempty"`
+	EnrollmentString string   `xml:"enrollmentString,omitempty"`
+	Notes            string   `xml:"notes,omitempty"`
+	URL              string   `xml:"url,omitempty"`
+}
+
+// writeNetworks writes networks to an io.Writer in text format
+func (w *TextWriter) writeNetworks(networks []meraki.NetworkWithOrganization, writer io.Writer) error {
+	// Write header
+	fmt.Fprintf(writer, "Meraki Networks\n")
+	fmt.Fprintf(writer, "===============\n\n")
+	fmt.Fprintf(writer, "Total Networks: %d\n\n", len(networks))
+
+	// Write networks
+	for i, network := range networks {
+		fmt.Fprintf(writer, "Network %d:\n", i+1)
+		fmt.Fprintf(writer, "  Organization: %s\n", labelOrID(network.Organization, network.OrganizationID))
+		fmt.Fprintf(writer, "  ID: %s\n", network.ID)
+		fmt.Fprintf(writer, "  Name: %s\n", network.Name)
+		fmt.Fprintf(writer, "  Product Types: %s\n", strings.Join(network.ProductTypes, ", "))
+		fmt.Fprintf(writer, "  Time Zone: %s\n", network.TimeZone)
+		if len(network.Tags) > 0 {
+			fmt.Fprintf(writer, "  Tags: %s\n", strings.Join(network.Tags, ", "))
+		}
+		if network.EnrollmentString != "" {
+			fmt.Fprintf(writer, "  Enrollment String: %s\n", network.EnrollmentString)
+		}
+		if network.Notes != "" {
+			fmt.Fprintf(writer, "  Notes: %s\n", network.Notes)
+		}
+		if _, err := fmt.Fprintf(writer, "\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeNetworksXML writes networks to an io.Writer in XML format
+func (w *XMLWriter) writeNetworksXML(networks []meraki.NetworkWithOrganization, writer io.Writer) error {
+	// Convert networks to XML-compatible format
+	xmlNetworks := make([]NetworkWithOrganizationXML, len(networks))
+	for i, network := range networks {
+		xmlNetworks[i] = NetworkWithOrganizationXML{
+			Organization:     network.Organization,
+			OrganizationID:   network.OrganizationID,
+			ID:               network.ID,
+			Name:             network.Name,
+			ProductTypes:     network.ProductTypes,
+			TimeZone:         network.TimeZone,
+			Tags:             network.Tags,
+			EnrollmentString: network.EnrollmentString,
+			Notes:            network.Notes,
+			URL:              network.URL,
+		}
+	}
+
+	networksXML := NetworksWithOrganizationXML{Networks: xmlNetworks}
+
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", w.indent())
+
+	// Write XML header
+	fmt.Fprint(writer, xml.Header)
+
+	if err := encoder.Encode(networksXML); err != nil {
+		return fmt.Errorf("failed to encode XML: %w", err)
+	}
+
+	return nil
+}
+
+// writeNetworksCSV writes networks to an io.Writer in CSV format, one row per network. Product
+// types and tags are space-separated within their columns.
+func (w *CSVWriter) writeNetworksCSV(networks []meraki.NetworkWithOrganization, writer io.Writer) error {
+	csvWriter := w.newCSVWriter(writer)
+	defer csvWriter.Flush()
+
+	// Write header
+	header := []string{"Organization", "Organization ID", "Network ID", "Network Name", "Product Types", "Time Zone",
+		"Tags", "Enrollment String", "Notes", "URL"}
+	if err := csvWriter.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	// Write networks
+	for _, network := range networks {
+		record := []string{
+			network.Organization,
+			network.OrganizationID,
+			network.ID,
+			network.Name,
+			strings.Join(network.ProductTypes, " "),
+			network.TimeZone,
+			strings.Join(network.Tags, " "),
+			network.EnrollmentString,
+			network.Notes,
+			network.URL,
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package output
+
+import (
+	"encoding/csv"
+	"io"
+	"os"
+)
+
+// defaultIndent is the indentation of JSON and XML output unless WithIndent sets another
+const defaultIndent = "  "
+
+// Option configures a writer created by NewWriter or NewDestinationWriter. Options that do not
+// apply to the writer's format, such as a CSV delimiter for JSON, are ignored.
+type Option func(Writer)
+
+// WithIndent sets the indentation of JSON and XML output. An empty indent keeps the default of
+// two spaces; use the ndjson format for compact JSON.
+func WithIndent(indent string) Option {
+	return func(writer Writer) {
+		SetIndent(writer, indent)
+	}
+}
+
+// WithCSVDelimiter sets the field delimiter of CSV output, e.g. ';' for spreadsheets in locales
+// that use a decimal comma
+func WithCSVDelimiter(delimiter rune) Option {
+	return func(writer Writer) {
+		SetCSVDelimiter(writer, delimiter)
+	}
+}
+
+// WithCSVBOM starts CSV output with a UTF-8 byte order mark, so that Excel reads non-ASCII names
+// correctly
+func WithCSVBOM(bom bool) Option {
+	return func(writer Writer) {
+		SetCSVBOM(writer, bom)
+	}
+}
+
+// WithJSONEnvelope wraps JSON output in a JSONEnvelope
+func WithJSONEnvelope(envelope bool) Option {
+	return func(writer Writer) {
+		SetJSONEnvelope(writer, envelope)
+	}
+}
+
+// WithFileMode sets the permission of files created by WriteToFile
+func WithFileMode(mode os.FileMode) Option {
+	return func(writer Writer) {
+		SetFileMode(writer, mode)
+	}
+}
+
+// WithFields limits each record to these fields
+func WithFields(fields []string) Option {
+	return func(writer Writer) {
+		SetFields(writer, fields)
+	}
+}
+
+// WithCompress gzips files as they are written
+func WithCompress(compress bool) Option {
+	return func(writer Writer) {
+		SetCompress(writer, compress)
+	}
+}
+
+// WithTemplate sets the template file of template output and the run metadata passed to it
+func WithTemplate(filename string, meta TemplateMeta) Option {
+	return func(writer Writer) {
+		SetTemplate(writer, filename, meta)
+	}
+}
+
+// SetIndent sets the indentation of a JSON or XML writer, or of the writer a DestinationWriter
+// wraps. Other writers are left unchanged.
+func SetIndent(writer Writer, indent string) {
+	switch w := writer.(type) {
+	case *JSONWriter:
+		w.Indent = indent
+	case *XMLWriter:
+		w.Indent = indent
+	case *DestinationWriter:
+		SetIndent(w.writer, indent)
+	}
+}
+
+// SetCSVDelimiter sets the field delimiter of a CSV writer, or of the writer a DestinationWriter
+// wraps. Other writers are left unchanged.
+func SetCSVDelimiter(writer Writer, delimiter rune) {
+	switch w := writer.(type) {
+	case *CSVWriter:
+		w.Delimiter = delimiter
+	case *DestinationWriter:
+		SetCSVDelimiter(w.writer, delimiter)
+	}
+}
+
+// SetCSVBOM sets whether a CSV writer, or the writer a DestinationWriter wraps, starts its output
+// with a UTF-8 byte order mark. Other writers are left unchanged.
+func SetCSVBOM(writer Writer, bom bool) {
+	switch w := writer.(type) {
+	case *CSVWriter:
+		w.BOM = bom
+	case *DestinationWriter:
+		SetCSVBOM(w.writer, bom)
+	}
+}
+
+// SetTemplate sets the template file and metadata of a template writer, or of the writer a
+// DestinationWriter wraps. Other writers are left unchanged.
+func SetTemplate(writer Writer, filename string, meta TemplateMeta) {
+	switch w := writer.(type) {
+	case *TemplateWriter:
+		w.TemplateFile = filename
+		w.Meta = meta
+	case *DestinationWriter:
+		SetTemplate(w.writer, filename, meta)
+	}
+}
+
+// indent returns the indentation of JSON output
+func (w *JSONWriter) indent() string {
+	if w.Indent == "" {
+		return defaultIndent
+	}
+	return w.Indent
+}
+
+// indent returns the indentation of XML output
+func (w *XMLWriter) indent() string {
+	if w.Indent == "" {
+		return defaultIndent
+	}
+	return w.Indent
+}
+
+// utf8BOM is the UTF-8 encoding of the byte order mark U+FEFF
+const utf8BOM = "\uFEFF"
+
+// newCSVWriter creates a csv.Writer using the writer's delimiter
+func (w *CSVWriter) newCSVWriter(writer io.Writer) *csv.Writer {
+	csvWriter := csv.NewWriter(writer)
+	if w.Delimiter != 0 {
+		csvWriter.Comma = w.Delimiter
+	}
+	return csvWriter
+}
@@ -0,0 +1,133 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"meraki-info/internal/meraki"
+)
+
+func TestNewWriter_Options(t *testing.T) {
+	routes := []meraki.Route{{Subnet: "10.0.0.0/24", GatewayIP: "10.0.0.1", Name: "LAN, main"}}
+
+	t.Run("defaults", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := NewWriter("json").WriteTo(routes, &buf); err != nil {
+			t.Fatalf("WriteTo failed: %v", err)
+		}
+		if !strings.Contains(buf.String(), "\n  {\n    \"") {
+			t.Errorf("Expected two-space indentation by default, got %q", buf.String())
+		}
+	})
+
+	t.Run("indent", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := NewWriter("json", WithIndent("\t"), WithFields([]string{"subnet"})).WriteTo(routes, &buf); err != nil {
+			t.Fatalf("WriteTo failed: %v", err)
+		}
+		if buf.String() != "[\n\t{\n\t\t\"subnet\": \"10.0.0.0/24\"\n\t}\n]\n" {
+			t.Errorf("Expected tab-indented JSON limited to the field, got %q", buf.String())
+		}
+
+		buf.Reset()
+		if err := NewWriter("xml", WithIndent("    ")).WriteTo(routes, &buf); err != nil {
+			t.Fatalf("WriteTo failed: %v", err)
+		}
+		if !strings.Contains(buf.String(), "\n    <route>") {
+			t.Errorf("Expected four-space indented XML, got %q", buf.String())
+		}
+	})
+
+	t.Run("csv delimiter", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := NewWriter("csv", WithCSVDelimiter(';')).WriteTo(routes, &buf); err != nil {
+			t.Fatalf("WriteTo failed: %v", err)
+		}
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 2 || !strings.Contains(lines[0], ";") || strings.Contains(lines[0], ",") {
+			t.Fatalf("Expected semicolon-delimited CSV, got %q", buf.String())
+		}
+		if !strings.Contains(lines[1], ";LAN, main;") {
+			t.Errorf("Expected the comma in a value to need no quoting, got %q", lines[1])
+		}
+
+		buf.Reset()
+		if err := NewWriter("csv", WithCSVDelimiter(';'), WithFields([]string{"subnet", "gateway ip"})).WriteTo(routes, &buf); err != nil {
+			t.Fatalf("WriteTo failed: %v", err)
+		}
+		if !strings.HasPrefix(buf.String(), "Subnet;Gateway IP") || !strings.Contains(buf.String(), "10.0.0.0/24;10.0.0.1") {
+			t.Errorf("Expected -fields to keep the delimiter, got %q", buf.String())
+		}
+	})
+
+	t.Run("json envelope", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := NewWriter("json", WithJSONEnvelope(true)).WriteTo(routes, &buf); err != nil {
+			t.Fatalf("WriteTo failed: %v", err)
+		}
+		var envelope JSONEnvelope
+		if err := json.Unmarshal(buf.Bytes(), &envelope); err != nil || envelope.Count != 1 {
+			t.Errorf("Expected an envelope with one item, got %q (%v)", buf.String(), err)
+		}
+	})
+
+	t.Run("other formats ignore options", func(t *testing.T) {
+		writer := NewWriter("text", WithIndent("\t"), WithCSVDelimiter(';'), WithJSONEnvelope(true))
+		if _, ok := writer.(*TextWriter); !ok {
+			t.Fatalf("Expected a text writer, got %T", writer)
+		}
+		destination := NewDestinationWriter("csv", filepath.Join(t.TempDir(), "routes.csv"), WithCSVDelimiter('\t'))
+		if delimiter := destination.(*DestinationWriter).writer.(*CSVWriter).Delimiter; delimiter != '\t' {
+			t.Errorf("Expected the destination's CSV writer to get the delimiter, got %q", delimiter)
+		}
+	})
+}
+
+func TestCSVWriter_BOM(t *testing.T) {
+	devices := []meraki.Device{{Serial: "Q2XX-1", Name: "Büro Zürich", Status: "offline"}}
+
+	var buf bytes.Buffer
+	if err := NewWriter("csv").WriteTo(devices, &buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "Serial,") {
+		t.Errorf("Expected no byte order mark by default, got %q", buf.String()[:10])
+	}
+
+	buf.Reset()
+	writer := NewWriter("csv", WithCSVBOM(true), WithFields([]string{"Serial", "Name"}))
+	if err := writer.WriteTo(devices, &buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	if got := buf.Bytes()[:3]; !bytes.Equal(got, []byte{0xEF, 0xBB, 0xBF}) {
+		t.Errorf("Expected the UTF-8 byte order mark, got % x", got)
+	}
+	if expected := "\uFEFFSerial,Name\nQ2XX-1,Büro Zürich\n"; buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+
+	filename := filepath.Join(t.TempDir(), "devices.csv")
+	if err := writer.WriteToFile(devices, filename); err != nil {
+		t.Fatalf("WriteToFile failed: %v", err)
+	}
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	if !bytes.HasPrefix(content, []byte{0xEF, 0xBB, 0xBF, 'S'}) {
+		t.Errorf("Expected the file to start with the byte order mark, got % x", content[:4])
+	}
+
+	buf.Reset()
+	consolidated := []meraki.DeviceWithNetwork{{Device: devices[0]}}
+	if err := NewWriter("csv", WithCSVBOM(true)).WriteTo(consolidated, &buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "[") {
+		t.Errorf("Expected consolidated devices, written as JSON, without a byte order mark, got %q", buf.String()[:4])
+	}
+}
@@ -56,6 +56,7 @@ func TestLicenseWithNetworkOrganizationID(t *testing.T) {
 			ID:             "L_123456789",
 			DeviceSerial:   "Q2XX-XXXX-XXXX",
 			NetworkID:      "N_123456789",
+			NetworkName:    "HQ",
 			State:          "active",
 			Edition:        "enterprise",
 			Mode:           "addons",
@@ -85,4 +86,7 @@ func TestLicenseWithNetworkOrganizationID(t *testing.T) {
 	if !strings.Contains(output, "Organization ID: 123456") {
 		t.Error("Expected organization ID in output")
 	}
+	if !strings.Contains(output, "Network Name: HQ") {
+		t.Error("Expected resolved network name in output")
+	}
 }
@@ -0,0 +1,132 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"meraki-info/internal/meraki"
+)
+
+// RouteOverlapsXML represents a collection of overlapping route pairs in XML format
+type RouteOverlapsXML struct {
+	XMLName  xml.Name          `xml:"routeOverlaps"`
+	Overlaps []RouteOverlapXML `xml:"overlap"`
+}
+
+// RouteOverlapXML represents a single pair of overlapping routes in XML format
+type RouteOverlapXML struct {
+	Relation     string              `xml:"relation,attr"`
+	Route        RouteWithNetworkXML `xml:"route"`
+	OverlapsWith RouteWithNetworkXML `xml:"overlapsWith"`
+}
+
+// overlapRouteXML converts one route of an overlapping pair to its XML form
+func overlapRouteXML(route meraki.RouteWithNetwork) RouteWithNetworkXML {
+	return RouteWithNetworkXML{
+		ID:             route.ID,
+		Name:           route.Name,
+		Subnet:         route.Subnet,
+		GatewayIP:      route.GatewayIP,
+		GatewayVlan:    route.GatewayVlan,
+		Enabled:        route.Enabled,
+		VPNMode:        route.VPNMode,
+		NetworkID:      route.NetworkID,
+		NetworkName:    route.NetworkName,
+		Organization:   route.Organization,
+		OrganizationID: route.OrganizationID,
+	}
+}
+
+// writeRouteOverlaps writes overlapping route pairs to an io.Writer in text format
+func (w *TextWriter) writeRouteOverlaps(overlaps []meraki.RouteOverlap, writer io.Writer) error {
+	// Write header
+	fmt.Fprintf(writer, "Meraki Overlapping Subnets\n")
+	fmt.Fprintf(writer, "==========================\n\n")
+	fmt.Fprintf(writer, "Total Overlaps: %d\n\n", len(overlaps))
+
+	// Write overlaps
+	for i, overlap := range overlaps {
+		fmt.Fprintf(writer, "Overlap %d: %s %s %s\n", i+1, overlap.Route.Subnet, overlap.Relation, overlap.OverlapsWith.Subnet)
+		fmt.Fprintf(writer, "  Route: %s\n", overlapRouteLabel(overlap.Route))
+		fmt.Fprintf(writer, "  Overlaps With: %s\n", overlapRouteLabel(overlap.OverlapsWith))
+		if _, err := fmt.Fprintf(writer, "\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// overlapRouteLabel describes one route of an overlapping pair, e.g. "10.0.0.0/8 (Office) in HQ via 10.0.0.1"
+func overlapRouteLabel(route meraki.RouteWithNetwork) string {
+	label := route.Subnet
+	if route.Name != "" {
+		label += fmt.Sprintf(" (%s)", route.Name)
+	}
+	if network := labelOrID(route.NetworkName, route.NetworkID); network != "" {
+		label += " in " + network
+	}
+	if route.Organization != "" {
+		label += fmt.Sprintf(" [%s]", route.Organization)
+	}
+	if route.GatewayIP != "" {
+		label += " via " + route.GatewayIP
+	}
+	return label
+}
+
+// writeRouteOverlapsXML writes overlapping route pairs to an io.Writer in XML format
+func (w *XMLWriter) writeRouteOverlapsXML(overlaps []meraki.RouteOverlap, writer io.Writer) error {
+	// Convert overlaps to XML-compatible format
+	xmlOverlaps := make([]RouteOverlapXML, len(overlaps))
+	for i, overlap := range overlaps {
+		xmlOverlaps[i] = RouteOverlapXML{
+			Relation:     overlap.Relation,
+			Route:        overlapRouteXML(overlap.Route),
+			OverlapsWith: overlapRouteXML(overlap.OverlapsWith),
+		}
+	}
+
+	overlapsXML := RouteOverlapsXML{Overlaps: xmlOverlaps}
+
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", w.indent())
+
+	// Write XML header
+	fmt.Fprint(writer, xml.Header)
+
+	if err := encoder.Encode(overlapsXML); err != nil {
+		return fmt.Errorf("failed to encode XML: %w", err)
+	}
+
+	return nil
+}
+
+// writeRouteOverlapsCSV writes overlapping route pairs to an io.Writer in CSV format, one row per pair
+func (w *CSVWriter) writeRouteOverlapsCSV(overlaps []meraki.RouteOverlap, writer io.Writer) error {
+	csvWriter := w.newCSVWriter(writer)
+	defer csvWriter.Flush()
+
+	// Write header
+	header := []string{"Relation",
+		"Organization", "Network ID", "Network Name", "Route ID", "Route Name", "Subnet", "Gateway IP",
+		"Overlapping Organization", "Overlapping Network ID", "Overlapping Network Name", "Overlapping Route ID",
+		"Overlapping Route Name", "Overlapping Subnet", "Overlapping Gateway IP"}
+	if err := csvWriter.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	// Write overlaps
+	for _, overlap := range overlaps {
+		record := []string{overlap.Relation}
+		for _, route := range []meraki.RouteWithNetwork{overlap.Route, overlap.OverlapsWith} {
+			record = append(record, route.Organization, route.NetworkID, route.NetworkName, route.ID, route.Name, route.Subnet, route.GatewayIP)
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+
+	return nil
+}
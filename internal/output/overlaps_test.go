@@ -0,0 +1,69 @@
+package output
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"meraki-info/internal/meraki"
+)
+
+func TestWriters_RouteOverlaps(t *testing.T) {
+	overlaps := []meraki.RouteOverlap{
+		{
+			Route: meraki.RouteWithNetwork{Route: meraki.Route{ID: "r1", Name: "Corporate", Subnet: "10.0.0.0/8", GatewayIP: "10.0.0.1"},
+				NetworkID: "N_1", NetworkName: "HQ", Organization: "Org A", OrganizationID: "1"},
+			OverlapsWith: meraki.RouteWithNetwork{Route: meraki.Route{ID: "r2", Name: "Lab", Subnet: "10.1.0.0/24", GatewayIP: "10.1.0.1"},
+				NetworkID: "N_2", NetworkName: "Branch", Organization: "Org A", OrganizationID: "1"},
+			Relation: meraki.OverlapContains,
+		},
+	}
+
+	t.Run("text", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := (&TextWriter{}).WriteTo(overlaps, &buf); err != nil {
+			t.Fatalf("WriteTo failed: %v", err)
+		}
+		output := buf.String()
+		for _, expected := range []string{
+			"Total Overlaps: 1",
+			"Overlap 1: 10.0.0.0/8 contains 10.1.0.0/24\n",
+			"  Route: 10.0.0.0/8 (Corporate) in HQ [Org A] via 10.0.0.1\n",
+			"  Overlaps With: 10.1.0.0/24 (Lab) in Branch [Org A] via 10.1.0.1\n",
+		} {
+			if !strings.Contains(output, expected) {
+				t.Errorf("Expected text output to contain %q, got:\n%s", expected, output)
+			}
+		}
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := (&CSVWriter{}).WriteTo(overlaps, &buf); err != nil {
+			t.Fatalf("WriteTo failed: %v", err)
+		}
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("Expected header and 1 row, got %d:\n%s", len(lines), buf.String())
+		}
+		if lines[1] != "contains,Org A,N_1,HQ,r1,Corporate,10.0.0.0/8,10.0.0.1,Org A,N_2,Branch,r2,Lab,10.1.0.0/24,10.1.0.1" {
+			t.Errorf("Unexpected overlap row: %s", lines[1])
+		}
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := (&XMLWriter{}).WriteTo(overlaps, &buf); err != nil {
+			t.Fatalf("WriteTo failed: %v", err)
+		}
+		var decoded RouteOverlapsXML
+		if err := xml.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("Failed to parse XML: %v", err)
+		}
+		if len(decoded.Overlaps) != 1 || decoded.Overlaps[0].Relation != "contains" ||
+			decoded.Overlaps[0].Route.NetworkName != "HQ" || decoded.Overlaps[0].OverlapsWith.Subnet != "10.1.0.0/24" {
+			t.Errorf("Unexpected XML overlaps: %+v", decoded.Overlaps)
+		}
+	})
+}
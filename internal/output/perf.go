@@ -0,0 +1,125 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"meraki-info/internal/meraki"
+)
+
+// AppliancePerformancesXML represents a collection of appliance performance records in XML format
+type AppliancePerformancesXML struct {
+	XMLName    xml.Name                  `xml:"appliances"`
+	Appliances []AppliancePerformanceXML `xml:"appliance"`
+}
+
+// AppliancePerformanceXML represents the performance of a single appliance in XML format
+type AppliancePerformanceXML struct {
+	Serial         string                     `xml:"serial"`
+	Name           string                     `xml:"name,omitempty"`
+	Model          string                     `xml:"model"`
+	PerfScore      float64                    `xml:"perfScore"`
+	LossPercent    float64                    `xml:"lossPercent"`
+	LatencyMs      float64                    `xml:"latencyMs"`
+	Uplinks        []meraki.UplinkPerformance `xml:"uplinks>uplink"`
+	NetworkID      string                     `xml:"networkId"`
+	NetworkName    string                     `xml:"networkName"`
+	Organization   string                     `xml:"organization,omitempty"`
+	OrganizationID string                     `xml:"organizationId,omitempty"`
+}
+
+// writeAppliancePerformance writes appliance performance to an io.Writer in text format
+func (w *TextWriter) writeAppliancePerformance(appliances []meraki.AppliancePerformance, writer io.Writer) error {
+	// Write header
+	fmt.Fprintf(writer, "Meraki Appliance Performance\n")
+	fmt.Fprintf(writer, "============================\n\n")
+	fmt.Fprintf(writer, "Total Appliances: %d\n\n", len(appliances))
+
+	// Write appliances
+	for _, appliance := range appliances {
+		fmt.Fprintf(writer, "Serial: %s\n", appliance.Serial)
+		if appliance.Name != "" {
+			fmt.Fprintf(writer, "  Name: %s\n", appliance.Name)
+		}
+		fmt.Fprintf(writer, "  Model: %s\n", appliance.Model)
+		fmt.Fprintf(writer, "  Performance Score: %g\n", appliance.PerfScore)
+		for _, uplink := range appliance.Uplinks {
+			fmt.Fprintf(writer, "  Uplink %s: %g%% loss, %g ms latency to %s\n", uplink.Interface, uplink.LossPercent, uplink.LatencyMs, uplink.IP)
+		}
+		if appliance.Organization != "" {
+			fmt.Fprintf(writer, "  Organization: %s\n", appliance.Organization)
+		}
+		fmt.Fprintf(writer, "  Network Name: %s\n", labelOrID(appliance.NetworkName, appliance.NetworkID))
+		fmt.Fprintf(writer, "  Network ID: %s\n", appliance.NetworkID)
+		if _, err := fmt.Fprintf(writer, "\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeAppliancePerformanceXML writes appliance performance to an io.Writer in XML format
+func (w *XMLWriter) writeAppliancePerformanceXML(appliances []meraki.AppliancePerformance, writer io.Writer) error {
+	// Convert appliances to XML-compatible format
+	xmlAppliances := make([]AppliancePerformanceXML, len(appliances))
+	for i, appliance := range appliances {
+		xmlAppliances[i] = AppliancePerformanceXML(appliance)
+	}
+
+	appliancesXML := AppliancePerformancesXML{Appliances: xmlAppliances}
+
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", w.indent())
+
+	// Write XML header
+	fmt.Fprint(writer, xml.Header)
+
+	if err := encoder.Encode(appliancesXML); err != nil {
+		return fmt.Errorf("failed to encode XML: %w", err)
+	}
+
+	return nil
+}
+
+// writeAppliancePerformanceCSV writes appliance performance to an io.Writer in CSV format, one row
+// per appliance. Uplinks are joined with "; " within their column.
+func (w *CSVWriter) writeAppliancePerformanceCSV(appliances []meraki.AppliancePerformance, writer io.Writer) error {
+	csvWriter := w.newCSVWriter(writer)
+	defer csvWriter.Flush()
+
+	// Write header
+	header := []string{"Organization", "Organization ID", "Network ID", "Network Name", "Serial", "Name", "Model", "Perf Score", "Loss Percent", "Latency Ms", "Uplinks"}
+	if err := csvWriter.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	// Write appliances
+	for _, appliance := range appliances {
+		uplinks := make([]string, len(appliance.Uplinks))
+		for i, uplink := range appliance.Uplinks {
+			uplinks[i] = fmt.Sprintf("%s: %g%% %gms", uplink.Interface, uplink.LossPercent, uplink.LatencyMs)
+		}
+		record := []string{
+			appliance.Organization,
+			appliance.OrganizationID,
+			appliance.NetworkID,
+			appliance.NetworkName,
+			appliance.Serial,
+			appliance.Name,
+			appliance.Model,
+			strconv.FormatFloat(appliance.PerfScore, 'f', -1, 64),
+			strconv.FormatFloat(appliance.LossPercent, 'f', -1, 64),
+			strconv.FormatFloat(appliance.LatencyMs, 'f', -1, 64),
+			strings.Join(uplinks, "; "),
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,209 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// RunSummary holds per-organization statistics for a consolidated run, so a short
+// result can be told apart from one where networks failed
+type RunSummary struct {
+	XMLName       xml.Name               `json:"-" xml:"runSummary"`
+	Command       string                 `json:"command" xml:"command"`
+	Organizations []OrganizationRunStats `json:"organizations" xml:"organization"`
+	Total         OrganizationRunStats   `json:"total" xml:"total"`
+}
+
+// OrganizationRunStats counts what a run did within a single organization
+type OrganizationRunStats struct {
+	Organization    string `json:"organization,omitempty" xml:"name,omitempty"`
+	OrganizationID  string `json:"organization_id,omitempty" xml:"id,omitempty"`
+	NetworksScanned int    `json:"networks_scanned" xml:"networksScanned"`
+	NetworksFailed  int    `json:"networks_failed" xml:"networksFailed"`
+	Items           int    `json:"items" xml:"items"`
+	APICalls        int    `json:"api_calls" xml:"apiCalls"`
+	Error           string `json:"error,omitempty" xml:"error,omitempty"` // Set when the organization could not be scanned at all
+	// AccessDenied is set when the API key has no access to the organization
+	AccessDenied bool `json:"access_denied,omitempty" xml:"accessDenied,omitempty"`
+	// FailedNetworks lists the networks counted in NetworksFailed
+	FailedNetworks []NetworkFailure `json:"failed_networks,omitempty" xml:"failedNetwork,omitempty"`
+}
+
+// NetworkFailure records a network whose data could not be fetched, so its records are missing
+// from the output
+type NetworkFailure struct {
+	Network   string `json:"network,omitempty" xml:"name,omitempty"`
+	NetworkID string `json:"network_id" xml:"id"`
+	Error     string `json:"error" xml:"error"`
+}
+
+// AddFailedNetwork counts a network whose data could not be fetched and records why
+func (s *OrganizationRunStats) AddFailedNetwork(network, networkID, reason string) {
+	s.NetworksFailed++
+	s.FailedNetworks = append(s.FailedNetworks, NetworkFailure{Network: network, NetworkID: networkID, Error: reason})
+}
+
+// AddOrganization appends stats for one organization and adds them to the total
+func (s *RunSummary) AddOrganization(stats OrganizationRunStats) {
+	s.Organizations = append(s.Organizations, stats)
+	s.Total.NetworksScanned += stats.NetworksScanned
+	s.Total.NetworksFailed += stats.NetworksFailed
+	s.Total.Items += stats.Items
+	s.Total.APICalls += stats.APICalls
+}
+
+// Incomplete returns the organizations that could not be scanned at all, typically because
+// the API key can see the organization but its networks could not be listed (403/404)
+func (s RunSummary) Incomplete() []OrganizationRunStats {
+	var incomplete []OrganizationRunStats
+	for _, stats := range s.Organizations {
+		if stats.Error != "" {
+			incomplete = append(incomplete, stats)
+		}
+	}
+	return incomplete
+}
+
+// WriteAccessNote writes which organizations of a run were accessible and which denied the API
+// key access, or nothing when every organization was accessible
+func WriteAccessNote(writer io.Writer, summary RunSummary) error {
+	var accessible, denied []string
+	for _, stats := range summary.Organizations {
+		if stats.AccessDenied {
+			denied = append(denied, fmt.Sprintf("%s (%s)", labelOrID(stats.Organization, stats.OrganizationID), stats.OrganizationID))
+		} else {
+			accessible = append(accessible, labelOrID(stats.Organization, stats.OrganizationID))
+		}
+	}
+	if len(denied) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(writer, "Note: the API key could access %d of %d organization(s):\n", len(accessible), len(summary.Organizations))
+	if len(accessible) > 0 {
+		fmt.Fprintf(writer, "  Accessible: %s\n", strings.Join(accessible, ", "))
+	}
+	_, err := fmt.Fprintf(writer, "  Denied: %s\n", strings.Join(denied, ", "))
+	return err
+}
+
+// WriteIncompleteNote writes a note listing the incomplete organizations of a run, or nothing when
+// every organization was scanned. Organizations that denied access are left to WriteAccessNote.
+func WriteIncompleteNote(writer io.Writer, summary RunSummary) error {
+	var incomplete []OrganizationRunStats
+	for _, stats := range summary.Incomplete() {
+		if !stats.AccessDenied {
+			incomplete = append(incomplete, stats)
+		}
+	}
+	if len(incomplete) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(writer, "Note: %d organization(s) are incomplete; their networks could not be listed:\n", len(incomplete))
+	for _, stats := range incomplete {
+		if _, err := fmt.Fprintf(writer, "  - %s (%s): %s\n", labelOrID(stats.Organization, stats.OrganizationID), stats.OrganizationID, stats.Error); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteFailedNetworksNote writes a note listing the networks of a run whose data could not be
+// fetched, or nothing when every network succeeded
+func WriteFailedNetworksNote(writer io.Writer, summary RunSummary) error {
+	if summary.Total.NetworksFailed == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(writer, "Note: %d network(s) failed and are missing from the output:\n", summary.Total.NetworksFailed)
+	for _, stats := range summary.Organizations {
+		for _, failure := range stats.FailedNetworks {
+			if _, err := fmt.Fprintf(writer, "  - %s / %s (%s): %s\n", labelOrID(stats.Organization, stats.OrganizationID),
+				labelOrID(failure.Network, failure.NetworkID), failure.NetworkID, failure.Error); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeRunSummary writes a run summary to an io.Writer in text format
+func (w *TextWriter) writeRunSummary(summary RunSummary, writer io.Writer) error {
+	fmt.Fprintf(writer, "Run Summary\n")
+	fmt.Fprintf(writer, "===========\n\n")
+
+	tw := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "ORGANIZATION\tNETWORKS\tFAILED\tITEMS\tAPI CALLS\tERROR\n")
+	for _, stats := range summary.Organizations {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%s\n",
+			labelOrID(stats.Organization, stats.OrganizationID),
+			stats.NetworksScanned, stats.NetworksFailed, stats.Items, stats.APICalls, stats.Error)
+	}
+	fmt.Fprintf(tw, "Total\t%d\t%d\t%d\t%d\t\n",
+		summary.Total.NetworksScanned, summary.Total.NetworksFailed, summary.Total.Items, summary.Total.APICalls)
+
+	return tw.Flush()
+}
+
+// writeRunSummaryXML writes a run summary to an io.Writer in XML format
+func (w *XMLWriter) writeRunSummaryXML(summary RunSummary, writer io.Writer) error {
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", w.indent())
+
+	// Write XML header
+	fmt.Fprint(writer, xml.Header)
+
+	if err := encoder.Encode(summary); err != nil {
+		return fmt.Errorf("failed to encode XML: %w", err)
+	}
+
+	return nil
+}
+
+// writeRunSummaryCSV writes a run summary to an io.Writer in CSV format, one row per organization plus a total row
+func (w *CSVWriter) writeRunSummaryCSV(summary RunSummary, writer io.Writer) error {
+	csvWriter := w.newCSVWriter(writer)
+	defer csvWriter.Flush()
+
+	// Write header
+	header := []string{"Organization", "Organization ID", "Networks Scanned", "Networks Failed", "Items", "API Calls", "Error"}
+	if err := csvWriter.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	rows := append(append([]OrganizationRunStats{}, summary.Organizations...), OrganizationRunStats{
+		Organization:    "Total",
+		NetworksScanned: summary.Total.NetworksScanned,
+		NetworksFailed:  summary.Total.NetworksFailed,
+		Items:           summary.Total.Items,
+		APICalls:        summary.Total.APICalls,
+	})
+	for _, stats := range rows {
+		record := []string{
+			stats.Organization,
+			stats.OrganizationID,
+			fmt.Sprintf("%d", stats.NetworksScanned),
+			fmt.Sprintf("%d", stats.NetworksFailed),
+			fmt.Sprintf("%d", stats.Items),
+			fmt.Sprintf("%d", stats.APICalls),
+			stats.Error,
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// labelOrID returns label if set, otherwise id
+func labelOrID(label, id string) string {
+	if label != "" {
+		return label
+	}
+	return id
+}
@@ -0,0 +1,113 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRunSummary(t *testing.T) {
+	var run RunSummary
+	run.Command = "down"
+	run.AddOrganization(OrganizationRunStats{Organization: "Org A", OrganizationID: "1", NetworksScanned: 3, NetworksFailed: 1, Items: 5, APICalls: 7})
+	run.AddOrganization(OrganizationRunStats{OrganizationID: "2", Error: "403 Forbidden", APICalls: 1})
+
+	if run.Total.NetworksScanned != 3 || run.Total.NetworksFailed != 1 || run.Total.Items != 5 || run.Total.APICalls != 8 {
+		t.Errorf("Unexpected totals: %+v", run.Total)
+	}
+
+	t.Run("text", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := (&TextWriter{}).WriteTo(run, &buf); err != nil {
+			t.Fatalf("WriteTo failed: %v", err)
+		}
+		output := buf.String()
+		for _, expected := range []string{"Run Summary", "Org A", "403 Forbidden", "Total"} {
+			if !strings.Contains(output, expected) {
+				t.Errorf("Expected text output to contain %q, got:\n%s", expected, output)
+			}
+		}
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := (&CSVWriter{}).WriteTo(run, &buf); err != nil {
+			t.Fatalf("WriteTo failed: %v", err)
+		}
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 4 {
+			t.Fatalf("Expected header, 2 organization rows and a total row, got %d lines", len(lines))
+		}
+		if lines[3] != "Total,,3,1,5,8," {
+			t.Errorf("Unexpected total row: %s", lines[3])
+		}
+		if len(run.Organizations) != 2 {
+			t.Errorf("Expected CSV writer to leave organizations unchanged, got %d", len(run.Organizations))
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := (&JSONWriter{}).WriteTo(run, &buf); err != nil {
+			t.Fatalf("WriteTo failed: %v", err)
+		}
+		var decoded map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("Failed to parse JSON: %v", err)
+		}
+		if decoded["command"] != "down" {
+			t.Errorf("Expected command 'down', got %v", decoded["command"])
+		}
+	})
+}
+
+func TestRunSummary_Incomplete(t *testing.T) {
+	var run RunSummary
+	run.AddOrganization(OrganizationRunStats{Organization: "Org A", OrganizationID: "1", NetworksScanned: 3})
+	run.AddOrganization(OrganizationRunStats{OrganizationID: "2", Error: "403 Forbidden"})
+
+	incomplete := run.Incomplete()
+	if len(incomplete) != 1 || incomplete[0].OrganizationID != "2" {
+		t.Fatalf("Expected only organization 2 to be incomplete, got: %+v", incomplete)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteIncompleteNote(&buf, run); err != nil {
+		t.Fatalf("WriteIncompleteNote failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "- 2 (2): 403 Forbidden") {
+		t.Errorf("Unexpected note: %s", buf.String())
+	}
+}
+
+func TestWriteAccessNote(t *testing.T) {
+	var run RunSummary
+	run.AddOrganization(OrganizationRunStats{Organization: "Org A", OrganizationID: "1", NetworksScanned: 3})
+	run.AddOrganization(OrganizationRunStats{Organization: "Org B", OrganizationID: "2", Error: "forbidden", AccessDenied: true})
+	run.AddOrganization(OrganizationRunStats{Organization: "Org C", OrganizationID: "3", Error: "404 Not Found"})
+
+	var buf bytes.Buffer
+	if err := WriteAccessNote(&buf, run); err != nil {
+		t.Fatalf("WriteAccessNote failed: %v", err)
+	}
+	expected := "Note: the API key could access 2 of 3 organization(s):\n  Accessible: Org A, Org C\n  Denied: Org B (2)\n"
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteIncompleteNote(&buf, run); err != nil {
+		t.Fatalf("WriteIncompleteNote failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "Org B") || !strings.Contains(buf.String(), "Org C (3): 404 Not Found") {
+		t.Errorf("Expected the incomplete note to leave denied organizations to the access note, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	var accessible RunSummary
+	accessible.AddOrganization(OrganizationRunStats{Organization: "Org A", OrganizationID: "1"})
+	if err := WriteAccessNote(&buf, accessible); err != nil || buf.Len() != 0 {
+		t.Errorf("Expected no note when every organization is accessible, got %q (%v)", buf.String(), err)
+	}
+}
@@ -0,0 +1,124 @@
+package output
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// sortFields lists the fields that can be used with SortSlice, keyed by lowercase name
+var sortFields = map[string]string{
+	"subnet":         "Subnet",
+	"networkname":    "NetworkName",
+	"organization":   "Organization",
+	"gatewayip":      "GatewayIP",
+	"serial":         "Serial",
+	"name":           "Name",
+	"model":          "Model",
+	"status":         "Status",
+	"lastreportedat": "LastReportedAt",
+	"expirationdate": "ExpirationDate",
+	"state":          "State",
+}
+
+// ParseSort splits a FIELD[:asc|desc] sort specification, returning the canonical field name
+func ParseSort(spec string) (field string, ascending bool, err error) {
+	name, direction, _ := strings.Cut(spec, ":")
+
+	field, ok := sortFields[strings.ToLower(name)]
+	if !ok {
+		valid := make([]string, 0, len(sortFields))
+		for _, f := range sortFields {
+			valid = append(valid, f)
+		}
+		sort.Strings(valid)
+		return "", false, fmt.Errorf("invalid sort field '%s'. Must be one of: %s", name, strings.Join(valid, ", "))
+	}
+
+	switch strings.ToLower(direction) {
+	case "", "asc":
+		return field, true, nil
+	case "desc":
+		return field, false, nil
+	default:
+		return "", false, fmt.Errorf("invalid sort direction '%s'. Must be asc or desc", direction)
+	}
+}
+
+// SortSlice stably sorts a slice of records in place by the named string field.
+// It handles the consolidated route, device, and license types as well as their plain counterparts.
+func SortSlice(data interface{}, field string, ascending bool) error {
+	value := reflect.ValueOf(data)
+	if value.Kind() != reflect.Slice || value.Type().Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot sort data of type %T", data)
+	}
+
+	canonical, ok := sortFields[strings.ToLower(field)]
+	if !ok {
+		return fmt.Errorf("invalid sort field '%s'", field)
+	}
+
+	structField, ok := sortKeyField(value.Type().Elem(), canonical)
+	if !ok {
+		return fmt.Errorf("cannot sort %T by %s", data, canonical)
+	}
+
+	swap := reflect.Swapper(data)
+	keys := make([]string, value.Len())
+	for i := range keys {
+		keys[i] = value.Index(i).FieldByIndex(structField.Index).String()
+	}
+
+	sort.Stable(&keyedSorter{keys: keys, swap: swap, ascending: ascending})
+	return nil
+}
+
+// SortFields returns the canonical sort fields that records, a slice of records such as a
+// command writes, can be sorted by. It returns nil for data that is not a slice of records.
+func SortFields(records interface{}) []string {
+	recordsType := reflect.TypeOf(records)
+	if recordsType == nil || recordsType.Kind() != reflect.Slice || recordsType.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []string
+	for _, canonical := range sortFields {
+		if _, ok := sortKeyField(recordsType.Elem(), canonical); ok {
+			fields = append(fields, canonical)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// sortKeyField looks up the string field records of type recordType are sorted by, including
+// fields promoted from embedded records such as the Route of a RouteWithNetwork
+func sortKeyField(recordType reflect.Type, canonical string) (reflect.StructField, bool) {
+	structField, ok := recordType.FieldByName(canonical)
+	if !ok || structField.Type.Kind() != reflect.String {
+		return reflect.StructField{}, false
+	}
+	return structField, true
+}
+
+// keyedSorter sorts a slice via its swapper while keeping a parallel slice of sort keys in step
+type keyedSorter struct {
+	keys      []string
+	swap      func(i, j int)
+	ascending bool
+}
+
+func (s *keyedSorter) Len() int { return len(s.keys) }
+
+func (s *keyedSorter) Less(i, j int) bool {
+	if s.ascending {
+		return s.keys[i] < s.keys[j]
+	}
+	return s.keys[i] > s.keys[j]
+}
+
+func (s *keyedSorter) Swap(i, j int) {
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+	s.swap(i, j)
+}
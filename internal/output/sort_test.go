@@ -0,0 +1,145 @@
+package output
+
+import (
+	"reflect"
+	"testing"
+
+	"meraki-info/internal/meraki"
+)
+
+func TestSortSlice_Routes(t *testing.T) {
+	newRoutes := func() []meraki.RouteWithNetwork {
+		return []meraki.RouteWithNetwork{
+			{Route: meraki.Route{Subnet: "10.2.0.0/16", GatewayIP: "10.2.0.1"}, NetworkName: "Branch", Organization: "Org B"},
+			{Route: meraki.Route{Subnet: "10.1.0.0/16", GatewayIP: "10.1.0.1"}, NetworkName: "HQ", Organization: "Org A"},
+			{Route: meraki.Route{Subnet: "10.3.0.0/16", GatewayIP: "10.0.0.1"}, NetworkName: "Annex", Organization: "Org C"},
+		}
+	}
+
+	tests := []struct {
+		field     string
+		ascending bool
+		expected  []string // expected subnets in order
+	}{
+		{"Subnet", true, []string{"10.1.0.0/16", "10.2.0.0/16", "10.3.0.0/16"}},
+		{"Subnet", false, []string{"10.3.0.0/16", "10.2.0.0/16", "10.1.0.0/16"}},
+		{"NetworkName", true, []string{"10.3.0.0/16", "10.2.0.0/16", "10.1.0.0/16"}},
+		{"Organization", true, []string{"10.1.0.0/16", "10.2.0.0/16", "10.3.0.0/16"}},
+		{"GatewayIP", true, []string{"10.3.0.0/16", "10.1.0.0/16", "10.2.0.0/16"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			routes := newRoutes()
+			if err := SortSlice(routes, tt.field, tt.ascending); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			for i, route := range routes {
+				if route.Subnet != tt.expected[i] {
+					t.Errorf("Position %d: expected %s, got %s", i, tt.expected[i], route.Subnet)
+				}
+			}
+		})
+	}
+}
+
+func TestSortSlice_Devices(t *testing.T) {
+	newDevices := func() []meraki.DeviceWithNetwork {
+		return []meraki.DeviceWithNetwork{
+			{Device: meraki.Device{Serial: "Q2-B", Name: "ap-2", Model: "MR46", Status: "offline", LastReportedAt: "2025-07-02T00:00:00Z"}},
+			{Device: meraki.Device{Serial: "Q2-C", Name: "ap-1", Model: "MS225", Status: "alerting", LastReportedAt: "2025-07-03T00:00:00Z"}},
+			{Device: meraki.Device{Serial: "Q2-A", Name: "ap-3", Model: "MV12", Status: "dormant", LastReportedAt: "2025-07-01T00:00:00Z"}},
+		}
+	}
+
+	tests := []struct {
+		field    string
+		expected []string // expected serials in order
+	}{
+		{"Serial", []string{"Q2-A", "Q2-B", "Q2-C"}},
+		{"Name", []string{"Q2-C", "Q2-B", "Q2-A"}},
+		{"Model", []string{"Q2-B", "Q2-C", "Q2-A"}},
+		{"Status", []string{"Q2-C", "Q2-A", "Q2-B"}},
+		{"LastReportedAt", []string{"Q2-A", "Q2-B", "Q2-C"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			devices := newDevices()
+			if err := SortSlice(devices, tt.field, true); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			for i, device := range devices {
+				if device.Serial != tt.expected[i] {
+					t.Errorf("Position %d: expected %s, got %s", i, tt.expected[i], device.Serial)
+				}
+			}
+		})
+	}
+}
+
+func TestSortSlice_Licenses(t *testing.T) {
+	licenses := []meraki.LicenseWithNetwork{
+		{License: meraki.License{ID: "2", State: "expired", ExpirationDate: "2024-01-01"}},
+		{License: meraki.License{ID: "1", State: "active", ExpirationDate: "2026-01-01"}},
+		{License: meraki.License{ID: "3", State: "unused", ExpirationDate: "2025-01-01"}},
+	}
+
+	if err := SortSlice(licenses, "ExpirationDate", true); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if licenses[0].ID != "2" || licenses[1].ID != "3" || licenses[2].ID != "1" {
+		t.Errorf("Unexpected expiration order: %s, %s, %s", licenses[0].ID, licenses[1].ID, licenses[2].ID)
+	}
+
+	if err := SortSlice(licenses, "State", false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if licenses[0].State != "unused" || licenses[2].State != "active" {
+		t.Errorf("Unexpected state order: %s, %s, %s", licenses[0].State, licenses[1].State, licenses[2].State)
+	}
+}
+
+func TestSortSlice_Errors(t *testing.T) {
+	routes := []meraki.RouteWithNetwork{{}}
+	if err := SortSlice(routes, "Serial", true); err == nil {
+		t.Error("Expected error sorting routes by a device field")
+	}
+	if err := SortSlice(routes, "Bogus", true); err == nil {
+		t.Error("Expected error for unknown field")
+	}
+	if err := SortSlice(Summary{}, "State", true); err == nil {
+		t.Error("Expected error for non-slice data")
+	}
+}
+
+func TestSortFields(t *testing.T) {
+	if fields := SortFields([]meraki.Route{}); !reflect.DeepEqual(fields, []string{"GatewayIP", "Name", "Subnet"}) {
+		t.Errorf("Expected the plain route fields, got %v", fields)
+	}
+	if fields := SortFields([]meraki.RouteWithNetwork{}); !reflect.DeepEqual(fields, []string{"GatewayIP", "Name", "NetworkName", "Organization", "Subnet"}) {
+		t.Errorf("Expected the network and organization to be added for consolidated routes, got %v", fields)
+	}
+	if fields := SortFields(Summary{}); fields != nil {
+		t.Errorf("Expected no fields for non-slice data, got %v", fields)
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	field, ascending, err := ParseSort("networkname:desc")
+	if err != nil || field != "NetworkName" || ascending {
+		t.Errorf("Expected NetworkName descending, got %s %t (err: %v)", field, ascending, err)
+	}
+
+	field, ascending, err = ParseSort("Serial")
+	if err != nil || field != "Serial" || !ascending {
+		t.Errorf("Expected Serial ascending, got %s %t (err: %v)", field, ascending, err)
+	}
+
+	if _, _, err := ParseSort("Serial:sideways"); err == nil {
+		t.Error("Expected error for invalid direction")
+	}
+	if _, _, err := ParseSort("Color"); err == nil {
+		t.Error("Expected error for invalid field")
+	}
+}
@@ -0,0 +1,83 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Summary holds aggregate counts for a command's results instead of the full item list
+type Summary struct {
+	XMLName xml.Name       `json:"-" xml:"summary"`
+	Title   string         `json:"title" xml:"title"`
+	GroupBy string         `json:"group_by" xml:"groupBy"`
+	Total   int            `json:"total" xml:"total"`
+	Counts  []SummaryCount `json:"counts" xml:"count"`
+}
+
+// SummaryCount is the number of items sharing a single group key
+type SummaryCount struct {
+	Key   string `json:"key" xml:"key"`
+	Count int    `json:"count" xml:"value"`
+}
+
+// writeSummary writes a summary to an io.Writer in text format
+func (w *TextWriter) writeSummary(summary Summary, writer io.Writer) error {
+	title := fmt.Sprintf("Meraki %s Summary", summary.Title)
+	fmt.Fprintf(writer, "%s\n", title)
+	fmt.Fprintf(writer, "%s\n\n", strings.Repeat("=", len(title)))
+	fmt.Fprintf(writer, "Total: %d\n\n", summary.Total)
+
+	if len(summary.Counts) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(writer, "By %s:\n", summary.GroupBy)
+	for _, count := range summary.Counts {
+		fmt.Fprintf(writer, "  %s: %d\n", count.Key, count.Count)
+	}
+
+	return nil
+}
+
+// writeSummaryXML writes a summary to an io.Writer in XML format
+func (w *XMLWriter) writeSummaryXML(summary Summary, writer io.Writer) error {
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", w.indent())
+
+	// Write XML header
+	fmt.Fprint(writer, xml.Header)
+
+	if err := encoder.Encode(summary); err != nil {
+		return fmt.Errorf("failed to encode XML: %w", err)
+	}
+
+	return nil
+}
+
+// writeSummaryCSV writes a summary to an io.Writer in CSV format, one row per group plus a total row
+func (w *CSVWriter) writeSummaryCSV(summary Summary, writer io.Writer) error {
+	csvWriter := w.newCSVWriter(writer)
+	defer csvWriter.Flush()
+
+	// Write header
+	header := []string{summary.GroupBy, "Count"}
+	if err := csvWriter.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	// Write counts
+	for _, count := range summary.Counts {
+		record := []string{count.Key, fmt.Sprintf("%d", count.Count)}
+		if err := csvWriter.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+
+	if err := csvWriter.Write([]string{"Total", fmt.Sprintf("%d", summary.Total)}); err != nil {
+		return fmt.Errorf("failed to write CSV record: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"meraki-info/internal/meraki"
+)
+
+// SwitchPortsXML represents a collection of switch port statuses in XML format
+type SwitchPortsXML struct {
+	XMLName xml.Name        `xml:"switchPorts"`
+	Ports   []SwitchPortXML `xml:"port"`
+}
+
+// SwitchPortXML represents a single switch port status in XML format
+type SwitchPortXML struct {
+	Organization          string   `xml:"organization,omitempty"`
+	OrganizationID        string   `xml:"organizationId,omitempty"`
+	NetworkID             string   `xml:"networkId"`
+	NetworkName           string   `xml:"networkName"`
+	Serial                string   `xml:"serial"`
+	SwitchName            string   `xml:"switchName,omitempty"`
+	PortID                string   `xml:"portId"`
+	Enabled               bool     `xml:"enabled"`
+	Status                string   `xml:"status"`
+	IsUplink              bool     `xml:"isUplink"`
+	Speed                 string   `xml:"speed,omitempty"`
+	Duplex                string   `xml:"duplex,omitempty"`
+	Errors                []string `xml:"errors>error,omitempty"`
+	Warnings              []string `xml:"warnings>warning,omitempty"`
+	CRCAlignErrors        int      `xml:"crcAlignErrors"`
+	Collisions            int      `xml:"collisions"`
+	RecentErrorsPerSecond float64  `xml:"recentErrorsPerSecond"`
+	ClientCount           int      `xml:"clientCount"`
+	PowerUsageInWh        float64  `xml:"powerUsageInWh"`
+	VLAN                  int      `xml:"vlan,omitempty"`
+	VoiceVLAN             int      `xml:"voiceVlan,omitempty"`
+	UsageTotalKb          int64    `xml:"usageInKb>total"`
+	UsageSentKb           int64    `xml:"usageInKb>sent"`
+	UsageRecvKb           int64    `xml:"usageInKb>recv"`
+}
+
+// writeSwitchPorts writes switch port statuses to an io.Writer in text format
+func (w *TextWriter) writeSwitchPorts(ports []meraki.SwitchPortStatusWithNetwork, writer io.Writer) error {
+	// Write header
+	fmt.Fprintf(writer, "Meraki Switch Ports\n")
+	fmt.Fprintf(writer, "===================\n\n")
+	fmt.Fprintf(writer, "Total Ports: %d\n\n", len(ports))
+
+	// Write ports
+	for i, port := range ports {
+		fmt.Fprintf(writer, "Port %d:\n", i+1)
+		if port.Organization != "" {
+			fmt.Fprintf(writer, "  Organization: %s\n", port.Organization)
+		}
+		fmt.Fprintf(writer, "  Network Name: %s\n", port.NetworkName)
+		fmt.Fprintf(writer, "  Switch: %s\n", labelOrID(port.SwitchName, port.Serial))
+		fmt.Fprintf(writer, "  Serial: %s\n", port.Serial)
+		fmt.Fprintf(writer, "  Port: %s\n", port.PortID)
+		fmt.Fprintf(writer, "  Enabled: %t\n", port.Enabled)
+		fmt.Fprintf(writer, "  Status: %s\n", port.Status)
+		if port.IsUplink {
+			fmt.Fprintf(writer, "  Uplink: true\n")
+		}
+		if port.Speed != "" {
+			fmt.Fprintf(writer, "  Speed: %s %s\n", port.Speed, port.Duplex)
+		}
+		if len(port.Errors) > 0 {
+			fmt.Fprintf(writer, "  Errors: %s\n", strings.Join(port.Errors, ", "))
+		}
+		if len(port.Warnings) > 0 {
+			fmt.Fprintf(writer, "  Warnings: %s\n", strings.Join(port.Warnings, ", "))
+		}
+		fmt.Fprintf(writer, "  CRC Align Errors: %d\n", port.CRCAlignErrors)
+		fmt.Fprintf(writer, "  Collisions: %d\n", port.Collisions)
+		fmt.Fprintf(writer, "  Recent Errors/s: %g\n", port.RecentErrorsPerSecond)
+		if port.VLAN != 0 {
+			fmt.Fprintf(writer, "  VLAN: %d\n", port.VLAN)
+		}
+		if port.VoiceVLAN != 0 {
+			fmt.Fprintf(writer, "  Voice VLAN: %d\n", port.VoiceVLAN)
+		}
+		fmt.Fprintf(writer, "  Clients: %d\n", port.ClientCount)
+		if port.PowerUsageInWh > 0 {
+			fmt.Fprintf(writer, "  PoE Usage: %g Wh\n", port.PowerUsageInWh)
+		}
+		fmt.Fprintf(writer, "  Usage: %d KB (sent %d KB, received %d KB)\n", port.UsageInKb.Total, port.UsageInKb.Sent, port.UsageInKb.Recv)
+		if _, err := fmt.Fprintf(writer, "\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeSwitchPortsXML writes switch port statuses to an io.Writer in XML format
+func (w *XMLWriter) writeSwitchPortsXML(ports []meraki.SwitchPortStatusWithNetwork, writer io.Writer) error {
+	// Convert ports to XML-compatible format
+	xmlPorts := make([]SwitchPortXML, len(ports))
+	for i, port := range ports {
+		xmlPorts[i] = SwitchPortXML{
+			Organization:          port.Organization,
+			OrganizationID:        port.OrganizationID,
+			NetworkID:             port.NetworkID,
+			NetworkName:           port.NetworkName,
+			Serial:                port.Serial,
+			SwitchName:            port.SwitchName,
+			PortID:                port.PortID,
+			Enabled:               port.Enabled,
+			Status:                port.Status,
+			IsUplink:              port.IsUplink,
+			Speed:                 port.Speed,
+			Duplex:                port.Duplex,
+			Errors:                port.Errors,
+			Warnings:              port.Warnings,
+			CRCAlignErrors:        port.CRCAlignErrors,
+			Collisions:            port.Collisions,
+			RecentErrorsPerSecond: port.RecentErrorsPerSecond,
+			ClientCount:           port.ClientCount,
+			PowerUsageInWh:        port.PowerUsageInWh,
+			VLAN:                  port.VLAN,
+			VoiceVLAN:             port.VoiceVLAN,
+			UsageTotalKb:          port.UsageInKb.Total,
+			UsageSentKb:           port.UsageInKb.Sent,
+			UsageRecvKb:           port.UsageInKb.Recv,
+		}
+	}
+
+	portsXML := SwitchPortsXML{Ports: xmlPorts}
+
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", w.indent())
+
+	// Write XML header
+	fmt.Fprint(writer, xml.Header)
+
+	if err := encoder.Encode(portsXML); err != nil {
+		return fmt.Errorf("failed to encode XML: %w", err)
+	}
+
+	return nil
+}
+
+// writeSwitchPortsCSV writes switch port statuses to an io.Writer in CSV format, one row per port.
+// Errors and warnings are joined with "; " within their columns.
+func (w *CSVWriter) writeSwitchPortsCSV(ports []meraki.SwitchPortStatusWithNetwork, writer io.Writer) error {
+	csvWriter := w.newCSVWriter(writer)
+	defer csvWriter.Flush()
+
+	// Write header
+	header := []string{"Organization", "Network ID", "Network Name", "Serial", "Switch Name", "Port", "Enabled", "Status",
+		"Uplink", "Speed", "Duplex", "Errors", "Warnings", "CRC Align Errors", "Collisions", "Recent Errors Per Second",
+		"VLAN", "Voice VLAN", "Client Count", "Power Usage In Wh", "Usage Total Kb", "Usage Sent Kb", "Usage Recv Kb"}
+	if err := csvWriter.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	// Write ports
+	for _, port := range ports {
+		record := []string{
+			port.Organization,
+			port.NetworkID,
+			port.NetworkName,
+			port.Serial,
+			port.SwitchName,
+			port.PortID,
+			fmt.Sprintf("%t", port.Enabled),
+			port.Status,
+			fmt.Sprintf("%t", port.IsUplink),
+			port.Speed,
+			port.Duplex,
+			strings.Join(port.Errors, "; "),
+			strings.Join(port.Warnings, "; "),
+			fmt.Sprintf("%d", port.CRCAlignErrors),
+			fmt.Sprintf("%d", port.Collisions),
+			fmt.Sprintf("%g", port.RecentErrorsPerSecond),
+			fmt.Sprintf("%d", port.VLAN),
+			fmt.Sprintf("%d", port.VoiceVLAN),
+			fmt.Sprintf("%d", port.ClientCount),
+			fmt.Sprintf("%g", port.PowerUsageInWh),
+			fmt.Sprintf("%d", port.UsageInKb.Total),
+			fmt.Sprintf("%d", port.UsageInKb.Sent),
+			fmt.Sprintf("%d", port.UsageInKb.Recv),
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+)
+
+// defaultTemplate is used when no template file is set: one record per line in Go's %+v notation
+const defaultTemplate = "{{range .Items}}{{printf \"%+v\" .}}\n{{end}}"
+
+// TemplateWriter writes data by executing a Go text/template, so teams can lay out text output
+// without a dedicated writer. The template is executed with a TemplateData value.
+type TemplateWriter struct {
+	FileOptions // Permission and compression of files created by WriteToFile
+
+	// TemplateFile is the template to execute; records are written with defaultTemplate when empty
+	TemplateFile string
+	// Meta describes the run to the template. GeneratedAt is set at write time when zero.
+	Meta TemplateMeta
+}
+
+// TemplateMeta describes the run that produced the data passed to a template
+type TemplateMeta struct {
+	Command      string
+	Organization string
+	GeneratedAt  time.Time
+}
+
+// TemplateData is the value templates are executed with: the command's result, usually a slice
+// of records, as Items, and the run's metadata as Meta
+type TemplateData struct {
+	Items interface{}
+	Meta  TemplateMeta
+}
+
+// templateFuncs are the functions available to templates in addition to text/template's builtins
+var templateFuncs = template.FuncMap{
+	"join":       templateJoin,
+	"formatTime": templateFormatTime,
+	"pad":        templatePad,
+	"padLeft":    templatePadLeft,
+}
+
+// ParseTemplate reads and parses a template file with the template functions. Syntax errors name
+// the file and line, e.g. "template: devices.tmpl:3: function "nmae" not defined".
+func ParseTemplate(filename string) (*template.Template, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	return template.New(filepath.Base(filename)).Funcs(templateFuncs).Parse(string(content))
+}
+
+// WriteToFile writes data to a file using the template
+func (w *TemplateWriter) WriteToFile(data interface{}, filename string) error {
+	return w.writeFile(filename, func(writer io.Writer) error {
+		return w.WriteTo(data, writer)
+	})
+}
+
+// WriteTo executes the template with data to an io.Writer
+func (w *TemplateWriter) WriteTo(data interface{}, writer io.Writer) error {
+	var tmpl *template.Template
+	var err error
+	if w.TemplateFile == "" {
+		tmpl, err = template.New("default").Funcs(templateFuncs).Parse(defaultTemplate)
+	} else {
+		tmpl, err = ParseTemplate(w.TemplateFile)
+	}
+	if err != nil {
+		return err
+	}
+
+	meta := w.Meta
+	if meta.GeneratedAt.IsZero() {
+		meta.GeneratedAt = time.Now().UTC()
+	}
+
+	if err := tmpl.Execute(writer, TemplateData{Items: data, Meta: meta}); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+	return nil
+}
+
+// templateJoin joins items with sep, as in {{join ", " .Tags}} or {{.Tags | join ", "}}
+func templateJoin(sep string, items []string) string {
+	return strings.Join(items, sep)
+}
+
+// templateFormatTime formats a time.Time, or an RFC 3339 timestamp as returned by the API, with a
+// Go layout such as "2006-01-02 15:04". Empty and unparsable strings are returned unchanged.
+func templateFormatTime(layout string, value interface{}) (string, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(layout), nil
+	case string:
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return v, nil
+		}
+		return t.Format(layout), nil
+	default:
+		return "", fmt.Errorf("formatTime: unsupported value of type %T", value)
+	}
+}
+
+// templatePad left-aligns value in a column of width characters, as in {{.Name | pad 20}}
+func templatePad(width int, value interface{}) string {
+	text := fmt.Sprint(value)
+	return text + strings.Repeat(" ", max(0, width-utf8.RuneCountInString(text)))
+}
+
+// templatePadLeft right-aligns value in a column of width characters, e.g. for numbers
+func templatePadLeft(width int, value interface{}) string {
+	text := fmt.Sprint(value)
+	return strings.Repeat(" ", max(0, width-utf8.RuneCountInString(text))) + text
+}
@@ -0,0 +1,94 @@
+package output
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"meraki-info/internal/meraki"
+)
+
+// writeTemplate writes a template file to a temporary directory and returns its path
+func writeTemplate(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+	return path
+}
+
+func TestTemplateWriter_Devices(t *testing.T) {
+	path := writeTemplate(t, "devices.tmpl", `{{.Meta.Command}} report for {{.Meta.Organization}} at {{formatTime "2006-01-02" .Meta.GeneratedAt}}
+{{range .Items}}{{.Serial | pad 10}}|{{.Status | padLeft 8}}|{{formatTime "02.01.2006 15:04" .LastReportedAt}}|{{join ", " .Tags}}
+{{end}}`)
+
+	devices := []meraki.DeviceWithNetwork{
+		{Device: meraki.Device{Serial: "Q2XX-1", Status: "offline", LastReportedAt: "2026-10-15T08:30:00Z", Tags: []string{"store", "critical"}}},
+		{Device: meraki.Device{Serial: "Q2XX-22", Status: "dormant"}},
+	}
+	writer := NewWriter("template", WithTemplate(path, TemplateMeta{
+		Command:      "down",
+		Organization: "Org One",
+		GeneratedAt:  time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC),
+	}))
+
+	var buf bytes.Buffer
+	if err := writer.WriteTo(devices, &buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+
+	expected := "down report for Org One at 2026-10-16\n" +
+		"Q2XX-1    | offline|15.10.2026 08:30|store, critical\n" +
+		"Q2XX-22   | dormant||\n"
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestTemplateWriter_Routes(t *testing.T) {
+	path := writeTemplate(t, "routes.tmpl", `{{len .Items}} routes
+{{range .Items}}{{if .Enabled}}{{.Subnet | pad 16}} via {{.GatewayIP}}
+{{end}}{{end}}`)
+
+	routes := []meraki.Route{
+		{Subnet: "10.0.0.0/24", GatewayIP: "10.0.0.1", Enabled: true},
+		{Subnet: "10.1.0.0/16", GatewayIP: "10.0.0.2"},
+		{Subnet: "0.0.0.0/0", GatewayIP: "192.0.2.1", Enabled: true},
+	}
+	writer := NewWriter("template", WithTemplate(path, TemplateMeta{Command: "route-tables"}))
+
+	filename := filepath.Join(t.TempDir(), "routes.txt")
+	if err := writer.WriteToFile(routes, filename); err != nil {
+		t.Fatalf("WriteToFile failed: %v", err)
+	}
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+
+	expected := "3 routes\n10.0.0.0/24      via 10.0.0.1\n0.0.0.0/0        via 192.0.2.1\n"
+	if string(content) != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, content)
+	}
+}
+
+func TestTemplateWriter_Errors(t *testing.T) {
+	path := writeTemplate(t, "broken.tmpl", "header\n{{range .Items}}\n{{.Serial | nmae}}\n{{end}}\n")
+	_, err := ParseTemplate(path)
+	if err == nil || !strings.Contains(err.Error(), "broken.tmpl:3") {
+		t.Errorf("Expected a parse error naming line 3, got: %v", err)
+	}
+	if err := NewWriter("template", WithTemplate(path, TemplateMeta{})).WriteTo([]meraki.Device{}, &bytes.Buffer{}); err == nil {
+		t.Error("Expected WriteTo to fail for a broken template")
+	}
+
+	path = writeTemplate(t, "fields.tmpl", "{{range .Items}}\n{{.Nmae}}\n{{end}}")
+	err = NewWriter("template", WithTemplate(path, TemplateMeta{})).WriteTo([]meraki.Device{{Serial: "Q2XX-1"}}, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "fields.tmpl:2") {
+		t.Errorf("Expected an execution error naming line 2, got: %v", err)
+	}
+}
@@ -2,13 +2,14 @@
 package output
 
 import (
-	"encoding/csv"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"io"
-	"os"
+	"sort"
 	"strings"
+	"text/tabwriter"
+	"time"
 
 	"meraki-info/internal/meraki"
 )
@@ -20,16 +21,48 @@ type Writer interface {
 }
 
 // TextWriter writes routes in plain text format
-type TextWriter struct{}
+type TextWriter struct {
+	// Subtotals inserts a per-organization record count after each organization's group in consolidated output
+	Subtotals bool
+	// GroupByNetwork prints consolidated routes under a header per network instead of one numbered list
+	GroupByNetwork bool
+	// FileOptions sets the permission and compression of files created by WriteToFile
+	FileOptions
+	// Fields limits each record to the "  Key: Value" lines of these fields; all fields when empty
+	Fields []string
+	// NoLicenseFooter omits the statistics footer after license lists
+	NoLicenseFooter bool
+}
 
 // JSONWriter writes routes in JSON format
-type JSONWriter struct{}
+type JSONWriter struct {
+	FileOptions          // Permission and compression of files created by WriteToFile
+	Native      bool     // Keep Meraki field names verbatim, nesting organization and network under meta
+	Fields      []string // Keys to keep in each record; all keys when empty
+	Envelope    bool     // Wrap the records in an object with generatedAt, count and items
+	Indent      string   // Indentation of nested values; two spaces when empty
+}
 
 // XMLWriter writes routes in XML format
-type XMLWriter struct{}
+type XMLWriter struct {
+	FileOptions        // Permission and compression of files created by WriteToFile
+	Indent      string // Indentation of nested elements; two spaces when empty
+}
 
 // CSVWriter writes routes in CSV format
-type CSVWriter struct{}
+type CSVWriter struct {
+	FileOptions          // Permission and compression of files created by WriteToFile
+	Fields      []string // Columns to keep, matched against the header; all columns when empty
+	Delimiter   rune     // Field delimiter; a comma when zero
+	BOM         bool     // Start the output with a UTF-8 byte order mark, for Excel
+}
+
+// PrometheusWriter writes devices and licenses as metrics in the Prometheus text exposition
+// format, for the node_exporter textfile collector
+type PrometheusWriter struct {
+	FileOptions         // Permission and compression of files created by WriteToFile
+	DeviceMetric string // Metric name for device records; meraki_device_down when empty
+}
 
 // RoutesXML represents routes in XML format
 type RoutesXML struct {
@@ -52,20 +85,23 @@ type RouteXML struct {
 	GatewayVlan int    `xml:"gatewayVlanId,omitempty"`
 	Enabled     bool   `xml:"enabled"`
 	FixedIP     string `xml:"fixedIpAssignments,omitempty"`
+	VPNMode     string `xml:"vpnMode,omitempty"`
 }
 
 // RouteWithNetworkXML represents a single route with network information in XML format
 type RouteWithNetworkXML struct {
-	ID           string `xml:"id,omitempty"`
-	Name         string `xml:"name,omitempty"`
-	Subnet       string `xml:"subnet"`
-	GatewayIP    string `xml:"gatewayIp"`
-	GatewayVlan  int    `xml:"gatewayVlanId,omitempty"`
-	Enabled      bool   `xml:"enabled"`
-	FixedIP      string `xml:"fixedIpAssignments,omitempty"`
-	NetworkID    string `xml:"networkId"`
-	NetworkName  string `xml:"networkName"`
-	Organization string `xml:"organization"`
+	ID             string `xml:"id,omitempty"`
+	Name           string `xml:"name,omitempty"`
+	Subnet         string `xml:"subnet"`
+	GatewayIP      string `xml:"gatewayIp"`
+	GatewayVlan    int    `xml:"gatewayVlanId,omitempty"`
+	Enabled        bool   `xml:"enabled"`
+	FixedIP        string `xml:"fixedIpAssignments,omitempty"`
+	VPNMode        string `xml:"vpnMode,omitempty"`
+	NetworkID      string `xml:"networkId"`
+	NetworkName    string `xml:"networkName"`
+	Organization   string `xml:"organization"`
+	OrganizationID string `xml:"organizationId,omitempty"`
 }
 
 // LicensesXML represents licenses in XML format
@@ -80,6 +116,7 @@ type LicenseXML struct {
 	OrganizationID    string `xml:"organizationId,omitempty"`
 	DeviceSerial      string `xml:"deviceSerial,omitempty"`
 	NetworkID         string `xml:"networkId,omitempty"`
+	NetworkName       string `xml:"networkName,omitempty"`
 	State             string `xml:"state,omitempty"`
 	Edition           string `xml:"edition,omitempty"`
 	Mode              string `xml:"mode,omitempty"`
@@ -112,35 +149,127 @@ type DeviceXML struct {
 	Lat            float64  `xml:"lat,omitempty"`
 	Lng            float64  `xml:"lng,omitempty"`
 	Notes          string   `xml:"notes,omitempty"`
+	DownDuration   string   `xml:"downDuration,omitempty"`
+	WarmSpareRole  string   `xml:"warmSpareRole,omitempty"`
+	HealthAlert    string   `xml:"healthAlert,omitempty"`
 }
 
-// NewWriter creates a new writer based on the output type
-func NewWriter(outputType string) Writer {
-	switch strings.ToLower(outputType) {
-	case "json":
-		return &JSONWriter{}
-	case "xml":
-		return &XMLWriter{}
-	case "csv":
-		return &CSVWriter{}
-	default:
-		return &TextWriter{}
+// DeviceStatusSummariesXML represents device status summaries in XML format
+type DeviceStatusSummariesXML struct {
+	XMLName   xml.Name                 `xml:"deviceStatusSummary"`
+	Summaries []DeviceStatusSummaryXML `xml:"summary"`
+}
+
+// DeviceStatusSummaryXML represents a single device status summary record in XML format
+type DeviceStatusSummaryXML struct {
+	Scope          string `xml:"scope"`
+	Organization   string `xml:"organization,omitempty"`
+	OrganizationID string `xml:"organizationId,omitempty"`
+	NetworkID      string `xml:"networkId,omitempty"`
+	NetworkName    string `xml:"networkName,omitempty"`
+	ProductType    string `xml:"productType,omitempty"`
+	Online         int    `xml:"online"`
+	Offline        int    `xml:"offline"`
+	Alerting       int    `xml:"alerting"`
+	Dormant        int    `xml:"dormant"`
+	Total          int    `xml:"total"`
+}
+
+// SwitchStacksXML represents a collection of switch stacks in XML format
+type SwitchStacksXML struct {
+	XMLName xml.Name         `xml:"switchStacks"`
+	Stacks  []SwitchStackXML `xml:"stack"`
+}
+
+// SwitchStackXML represents a single switch stack in XML format
+type SwitchStackXML struct {
+	ID             string   `xml:"id"`
+	Name           string   `xml:"name"`
+	Serials        []string `xml:"serials>serial"`
+	Organization   string   `xml:"organization,omitempty"`
+	OrganizationID string   `xml:"organizationId,omitempty"`
+	NetworkID      string   `xml:"networkId"`
+	NetworkName    string   `xml:"networkName,omitempty"`
+}
+
+// DHCPSubnetsXML represents a collection of DHCP subnets in XML format
+type DHCPSubnetsXML struct {
+	XMLName xml.Name        `xml:"dhcpSubnets"`
+	Subnets []DHCPSubnetXML `xml:"subnet"`
+}
+
+// DHCPSubnetXML represents a single DHCP subnet in XML format
+type DHCPSubnetXML struct {
+	Source         string                     `xml:"source"`
+	StackID        string                     `xml:"stackId,omitempty"`
+	StackName      string                     `xml:"stackName,omitempty"`
+	InterfaceID    string                     `xml:"interfaceId"`
+	Name           string                     `xml:"name"`
+	VLAN           int                        `xml:"vlan,omitempty"`
+	Subnet         string                     `xml:"subnet"`
+	GatewayIP      string                     `xml:"gatewayIp"`
+	Mode           string                     `xml:"mode"`
+	RelayServerIPs []string                   `xml:"relayServers>ip,omitempty"`
+	LeaseTime      string                     `xml:"leaseTime,omitempty"`
+	DNSNameservers []string                   `xml:"dnsNameservers>nameserver,omitempty"`
+	ReservedRanges []meraki.DHCPReservedRange `xml:"reservedRanges>range,omitempty"`
+	Options        []meraki.DHCPOption        `xml:"options>option,omitempty"`
+	Organization   string                     `xml:"organization,omitempty"`
+	OrganizationID string                     `xml:"organizationId,omitempty"`
+	NetworkID      string                     `xml:"networkId"`
+	NetworkName    string                     `xml:"networkName,omitempty"`
+}
+
+// EventsXML represents a collection of network events in XML format
+type EventsXML struct {
+	XMLName xml.Name   `xml:"events"`
+	Events  []EventXML `xml:"event"`
+}
+
+// EventXML represents a single network event in XML format
+type EventXML struct {
+	OccurredAt        string `xml:"occurredAt"`
+	Type              string `xml:"type"`
+	Description       string `xml:"description"`
+	Category          string `xml:"category,omitempty"`
+	DeviceSerial      string `xml:"deviceSerial,omitempty"`
+	DeviceName        string `xml:"deviceName,omitempty"`
+	ClientID          string `xml:"clientId,omitempty"`
+	ClientDescription string `xml:"clientDescription,omitempty"`
+	ClientMac         string `xml:"clientMac,omitempty"`
+	Organization      string `xml:"organization,omitempty"`
+	OrganizationID    string `xml:"organizationId,omitempty"`
+	NetworkID         string `xml:"networkId"`
+	NetworkName       string `xml:"networkName,omitempty"`
+}
+
+// NewWriter creates a new writer based on the output type, falling back to text for unknown types,
+// and applies opts to it. Without options the writer keeps the format's defaults.
+func NewWriter(outputType string, opts ...Option) Writer {
+	format, ok := LookupFormat(outputType)
+	if !ok {
+		format = formats[0]
+	}
+	writer := format.newWriter()
+	for _, opt := range opts {
+		opt(writer)
 	}
+	return writer
 }
 
 // WriteToFile writes data to a file in text format
 func (w *TextWriter) WriteToFile(data interface{}, filename string) error {
-	file, err := os.Create(filename)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
-	}
-	defer file.Close()
-
-	return w.WriteTo(data, file)
+	return w.writeFile(filename, func(writer io.Writer) error {
+		return w.WriteTo(data, writer)
+	})
 }
 
 // WriteTo writes data to an io.Writer in text format
 func (w *TextWriter) WriteTo(data interface{}, writer io.Writer) error {
+	if len(w.Fields) > 0 {
+		return w.writeTextFields(data, writer)
+	}
+
 	switch v := data.(type) {
 	case []meraki.Route:
 		return w.writeRoutes(v, writer)
@@ -154,6 +283,42 @@ func (w *TextWriter) WriteTo(data interface{}, writer io.Writer) error {
 		return w.writeDevices(v, writer)
 	case []meraki.DeviceWithNetwork:
 		return w.writeDevicesWithNetwork(v, writer)
+	case []meraki.DeviceStatusSummary:
+		return w.writeDeviceStatusSummary(v, writer)
+	case []meraki.SwitchStackWithNetwork:
+		return w.writeSwitchStacks(v, writer)
+	case []meraki.DHCPSubnetWithNetwork:
+		return w.writeDHCPSubnets(v, writer)
+	case []meraki.FirewallRuleWithNetwork:
+		return w.writeFirewallRules(v, writer)
+	case []meraki.NetworkWithOrganization:
+		return w.writeNetworks(v, writer)
+	case []meraki.SwitchPortStatusWithNetwork:
+		return w.writeSwitchPorts(v, writer)
+	case []meraki.DeviceDetails:
+		return w.writeDeviceDetails(v, writer)
+	case []meraki.RouteOverlap:
+		return w.writeRouteOverlaps(v, writer)
+	case []meraki.EventWithNetwork:
+		return w.writeEvents(v, writer)
+	case []meraki.ConfigurationChangeWithOrganization:
+		return w.writeConfigurationChanges(v, writer)
+	case []meraki.DeviceLocation:
+		return w.writeDeviceLocations(v, writer)
+	case []meraki.AppliancePerformance:
+		return w.writeAppliancePerformance(v, writer)
+	case []meraki.OrganizationLicenseSummary:
+		return w.writeOrganizationLicenseSummaries(v, writer)
+	case meraki.AccessInfo:
+		return w.writeAccessInfo(v, writer)
+	case []meraki.APIUsage:
+		return w.writeAPIUsage(v, writer)
+	case Summary:
+		return w.writeSummary(v, writer)
+	case RunSummary:
+		return w.writeRunSummary(v, writer)
+	case []RecordChange:
+		return w.writeRecordChanges(v, writer)
 	default:
 		return fmt.Errorf("unsupported data type: %T", data)
 	}
@@ -176,6 +341,9 @@ func (w *TextWriter) writeRoutes(routes []meraki.Route, writer io.Writer) error
 		fmt.Fprintf(writer, "  Gateway VLAN: %d\n", route.GatewayVlan)
 		fmt.Fprintf(writer, "  Enabled: %t\n", route.Enabled)
 		fmt.Fprintf(writer, "  Fixed IP: %v\n", route.FixedIP)
+		if route.VPNMode != "" {
+			fmt.Fprintf(writer, "  VPN Mode: %s\n", route.VPNMode)
+		}
 		fmt.Fprintf(writer, "\n")
 	}
 
@@ -189,10 +357,18 @@ func (w *TextWriter) writeRoutesWithNetwork(routes []meraki.RouteWithNetwork, wr
 	fmt.Fprintf(writer, "=======================================\n\n")
 	fmt.Fprintf(writer, "Total Routes: %d\n\n", len(routes))
 
+	if w.GroupByNetwork {
+		return w.writeRoutesGroupedByNetwork(routes, writer)
+	}
+
 	// Write routes
+	groupCount := 0
 	for i, route := range routes {
 		fmt.Fprintf(writer, "Route %d:\n", i+1)
 		fmt.Fprintf(writer, "  Organization: %s\n", route.Organization)
+		if route.OrganizationID != "" {
+			fmt.Fprintf(writer, "  Organization ID: %s\n", route.OrganizationID)
+		}
 		fmt.Fprintf(writer, "  Network ID: %s\n", route.NetworkID)
 		fmt.Fprintf(writer, "  Network Name: %s\n", route.NetworkName)
 		fmt.Fprintf(writer, "  ID: %s\n", route.ID)
@@ -202,7 +378,66 @@ func (w *TextWriter) writeRoutesWithNetwork(routes []meraki.RouteWithNetwork, wr
 		fmt.Fprintf(writer, "  Gateway VLAN: %d\n", route.GatewayVlan)
 		fmt.Fprintf(writer, "  Enabled: %t\n", route.Enabled)
 		fmt.Fprintf(writer, "  Fixed IP: %v\n", route.FixedIP)
+		if route.VPNMode != "" {
+			fmt.Fprintf(writer, "  VPN Mode: %s\n", route.VPNMode)
+		}
 		fmt.Fprintf(writer, "\n")
+
+		groupCount++
+		if w.Subtotals && (i == len(routes)-1 || routes[i+1].Organization != route.Organization) {
+			writeSubtotal(writer, route.Organization, groupCount, "routes")
+			groupCount = 0
+		}
+	}
+
+	return nil
+}
+
+// writeRoutesGroupedByNetwork writes consolidated routes under one header per network, keeping
+// networks in the order they first appear so a sorted list stays in order
+func (w *TextWriter) writeRoutesGroupedByNetwork(routes []meraki.RouteWithNetwork, writer io.Writer) error {
+	type networkKey struct{ organizationID, networkID string }
+	var order []networkKey
+	groups := make(map[networkKey][]meraki.RouteWithNetwork)
+	for _, route := range routes {
+		key := networkKey{route.OrganizationID, route.NetworkID}
+		if _, seen := groups[key]; !seen {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], route)
+	}
+
+	orgCount := 0
+	for i, key := range order {
+		networkRoutes := groups[key]
+		first := networkRoutes[0]
+		header := fmt.Sprintf("Network: %s (%s)", first.NetworkName, first.NetworkID)
+		fmt.Fprintf(writer, "%s\n%s\n", header, strings.Repeat("-", len(header)))
+		if first.Organization != "" || first.OrganizationID != "" {
+			fmt.Fprintf(writer, "Organization: %s (%s)\n", first.Organization, first.OrganizationID)
+		}
+		fmt.Fprintf(writer, "Routes: %d\n\n", len(networkRoutes))
+
+		for j, route := range networkRoutes {
+			fmt.Fprintf(writer, "  Route %d:\n", j+1)
+			fmt.Fprintf(writer, "    ID: %s\n", route.ID)
+			fmt.Fprintf(writer, "    Name: %s\n", route.Name)
+			fmt.Fprintf(writer, "    Subnet: %s\n", route.Subnet)
+			fmt.Fprintf(writer, "    Gateway IP: %s\n", route.GatewayIP)
+			fmt.Fprintf(writer, "    Gateway VLAN: %d\n", route.GatewayVlan)
+			fmt.Fprintf(writer, "    Enabled: %t\n", route.Enabled)
+			fmt.Fprintf(writer, "    Fixed IP: %v\n", route.FixedIP)
+			if route.VPNMode != "" {
+				fmt.Fprintf(writer, "    VPN Mode: %s\n", route.VPNMode)
+			}
+			fmt.Fprintf(writer, "\n")
+		}
+
+		orgCount += len(networkRoutes)
+		if w.Subtotals && (i == len(order)-1 || order[i+1].organizationID != key.organizationID) {
+			writeSubtotal(writer, first.Organization, orgCount, "routes")
+			orgCount = 0
+		}
 	}
 
 	return nil
@@ -222,6 +457,9 @@ func (w *TextWriter) writeLicenses(licenses []meraki.License, writer io.Writer)
 		fmt.Fprintf(writer, "  Organization ID: %s\n", license.OrganizationID)
 		fmt.Fprintf(writer, "  Device Serial: %s\n", license.DeviceSerial)
 		fmt.Fprintf(writer, "  Network ID: %s\n", license.NetworkID)
+		if license.NetworkName != "" {
+			fmt.Fprintf(writer, "  Network Name: %s\n", license.NetworkName)
+		}
 		fmt.Fprintf(writer, "  State: %s\n", license.State)
 		fmt.Fprintf(writer, "  Edition: %s\n", license.Edition)
 		fmt.Fprintf(writer, "  Mode: %s\n", license.Mode)
@@ -234,7 +472,7 @@ func (w *TextWriter) writeLicenses(licenses []meraki.License, writer io.Writer)
 		fmt.Fprintf(writer, "\n")
 	}
 
-	return nil
+	return w.writeLicenseFooter(licenses, writer)
 }
 
 // writeDevices writes devices to an io.Writer in text format
@@ -259,6 +497,15 @@ func (w *TextWriter) writeDevices(devices []meraki.Device, writer io.Writer) err
 		fmt.Fprintf(writer, "  MAC: %s\n", device.MAC)
 		fmt.Fprintf(writer, "  Status: %s\n", device.Status)
 		fmt.Fprintf(writer, "  Last Reported: %s\n", device.LastReportedAt)
+		if device.DownDuration != "" {
+			fmt.Fprintf(writer, "  Down For: %s\n", device.DownDuration)
+		}
+		if device.WarmSpareRole != "" {
+			fmt.Fprintf(writer, "  Warm Spare Role: %s\n", device.WarmSpareRole)
+		}
+		if device.HealthAlert != "" {
+			fmt.Fprintf(writer, "  Health Alert: %s\n", device.HealthAlert)
+		}
 		fmt.Fprintf(writer, "  Product Type: %s\n", device.ProductType)
 		if len(device.Tags) > 0 {
 			fmt.Fprintf(writer, "  Tags: %v\n", device.Tags)
@@ -286,6 +533,7 @@ func (w *TextWriter) writeLicensesWithNetwork(licenses []meraki.LicenseWithNetwo
 	fmt.Fprintf(writer, "Total Licenses: %d\n\n", len(licenses))
 
 	// Write licenses
+	groupCount := 0
 	for i, licenseWithNetwork := range licenses {
 		license := licenseWithNetwork.License
 		fmt.Fprintf(writer, "License %d:\n", i+1)
@@ -294,6 +542,9 @@ func (w *TextWriter) writeLicensesWithNetwork(licenses []meraki.LicenseWithNetwo
 		fmt.Fprintf(writer, "  Organization ID: %s\n", licenseWithNetwork.OrganizationID)
 		fmt.Fprintf(writer, "  Device Serial: %s\n", license.DeviceSerial)
 		fmt.Fprintf(writer, "  Network ID: %s\n", license.NetworkID)
+		if license.NetworkName != "" {
+			fmt.Fprintf(writer, "  Network Name: %s\n", license.NetworkName)
+		}
 		fmt.Fprintf(writer, "  State: %s\n", license.State)
 		fmt.Fprintf(writer, "  Edition: %s\n", license.Edition)
 		fmt.Fprintf(writer, "  Mode: %s\n", license.Mode)
@@ -304,9 +555,52 @@ func (w *TextWriter) writeLicensesWithNetwork(licenses []meraki.LicenseWithNetwo
 		fmt.Fprintf(writer, "  Expiration Date: %s\n", license.ExpirationDate)
 		fmt.Fprintf(writer, "  Permanently Queued: %t\n", license.PermanentlyQueued)
 		fmt.Fprintf(writer, "\n")
+
+		groupCount++
+		if w.Subtotals && (i == len(licenses)-1 || licenses[i+1].Organization != licenseWithNetwork.Organization) {
+			writeSubtotal(writer, licenseWithNetwork.Organization, groupCount, "licenses")
+			groupCount = 0
+		}
 	}
 
-	return nil
+	plain := make([]meraki.License, len(licenses))
+	for i, license := range licenses {
+		plain[i] = license.License
+	}
+	return w.writeLicenseFooter(plain, writer)
+}
+
+// writeLicenseFooter writes license counts by state, the soonest and latest expiration dates and
+// the total duration after a license list, unless NoLicenseFooter is set
+func (w *TextWriter) writeLicenseFooter(licenses []meraki.License, writer io.Writer) error {
+	if w.NoLicenseFooter || len(licenses) == 0 {
+		return nil
+	}
+
+	stats, err := meraki.SummarizeLicenses(licenses)
+	if err != nil {
+		return err
+	}
+
+	states := make([]string, 0, len(stats.ByState))
+	for state := range stats.ByState {
+		states = append(states, state)
+	}
+	sort.Strings(states)
+
+	fmt.Fprintf(writer, "License Statistics\n")
+	fmt.Fprintf(writer, "------------------\n")
+	fmt.Fprintf(writer, "Total Licenses: %d\n", stats.Total)
+	fmt.Fprintf(writer, "By State:\n")
+	for _, state := range states {
+		fmt.Fprintf(writer, "  %s: %d\n", labelOrID(state, "(none)"), stats.ByState[state])
+	}
+	if !stats.SoonestExpiration.IsZero() {
+		fmt.Fprintf(writer, "Soonest Expiration: %s\n", stats.SoonestExpiration.Format(time.RFC3339))
+		fmt.Fprintf(writer, "Latest Expiration: %s\n", stats.LatestExpiration.Format(time.RFC3339))
+	}
+	_, err = fmt.Fprintf(writer, "Total Duration (Days): %d\n", stats.TotalDurationDays)
+	return err
 }
 
 // writeDevicesWithNetwork writes devices with network info to an io.Writer in text format
@@ -322,6 +616,7 @@ func (w *TextWriter) writeDevicesWithNetwork(devices []meraki.DeviceWithNetwork,
 	}
 
 	// Write devices
+	groupCount := 0
 	for i, deviceWithNetwork := range devices {
 		device := deviceWithNetwork.Device
 		fmt.Fprintf(writer, "Device %d:\n", i+1)
@@ -335,6 +630,15 @@ func (w *TextWriter) writeDevicesWithNetwork(devices []meraki.DeviceWithNetwork,
 		fmt.Fprintf(writer, "  MAC: %s\n", device.MAC)
 		fmt.Fprintf(writer, "  Status: %s\n", device.Status)
 		fmt.Fprintf(writer, "  Last Reported: %s\n", device.LastReportedAt)
+		if device.DownDuration != "" {
+			fmt.Fprintf(writer, "  Down For: %s\n", device.DownDuration)
+		}
+		if device.WarmSpareRole != "" {
+			fmt.Fprintf(writer, "  Warm Spare Role: %s\n", device.WarmSpareRole)
+		}
+		if device.HealthAlert != "" {
+			fmt.Fprintf(writer, "  Health Alert: %s\n", device.HealthAlert)
+		}
 		fmt.Fprintf(writer, "  Product Type: %s\n", device.ProductType)
 		if len(device.Tags) > 0 {
 			fmt.Fprintf(writer, "  Tags: %v\n", device.Tags)
@@ -349,26 +653,193 @@ func (w *TextWriter) writeDevicesWithNetwork(devices []meraki.DeviceWithNetwork,
 			fmt.Fprintf(writer, "  Notes: %s\n", device.Notes)
 		}
 		fmt.Fprintf(writer, "\n")
+
+		groupCount++
+		if w.Subtotals && (i == len(devices)-1 || devices[i+1].Organization != deviceWithNetwork.Organization) {
+			writeSubtotal(writer, deviceWithNetwork.Organization, groupCount, "devices")
+			groupCount = 0
+		}
+	}
+
+	return nil
+}
+
+// writeSubtotal writes a per-organization record count line for -subtotals output
+func writeSubtotal(writer io.Writer, organization string, count int, noun string) {
+	fmt.Fprintf(writer, "--- Subtotal for %s: %d %s ---\n\n", organization, count, noun)
+}
+
+// writeDeviceStatusSummary writes device status counts to an io.Writer as a compact text table
+func (w *TextWriter) writeDeviceStatusSummary(summaries []meraki.DeviceStatusSummary, writer io.Writer) error {
+	// Write header
+	fmt.Fprintf(writer, "Meraki Device Status Summary\n")
+	fmt.Fprintf(writer, "============================\n\n")
+
+	tw := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "SCOPE\tORGANIZATION\tNETWORK / PRODUCT\tONLINE\tOFFLINE\tALERTING\tDORMANT\tTOTAL\n")
+	for _, summary := range summaries {
+		label := summary.NetworkName
+		switch summary.Scope {
+		case "productType":
+			label = summary.ProductType
+		case "network":
+			if label == "" {
+				label = summary.NetworkID
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\t%d\t%d\t%d\n",
+			summary.Scope, summary.Organization, label,
+			summary.Online, summary.Offline, summary.Alerting, summary.Dormant, summary.Total)
+	}
+
+	return tw.Flush()
+}
+
+// writeSwitchStacks writes switch stacks to an io.Writer in text format
+func (w *TextWriter) writeSwitchStacks(stacks []meraki.SwitchStackWithNetwork, writer io.Writer) error {
+	// Write header
+	fmt.Fprintf(writer, "Meraki Switch Stacks\n")
+	fmt.Fprintf(writer, "====================\n\n")
+	fmt.Fprintf(writer, "Total Stacks: %d\n\n", len(stacks))
+
+	// Write stacks
+	for i, stack := range stacks {
+		fmt.Fprintf(writer, "Stack %d:\n", i+1)
+		fmt.Fprintf(writer, "  ID: %s\n", stack.ID)
+		fmt.Fprintf(writer, "  Name: %s\n", stack.Name)
+		if stack.Organization != "" {
+			fmt.Fprintf(writer, "  Organization: %s\n", stack.Organization)
+		}
+		fmt.Fprintf(writer, "  Network Name: %s\n", stack.NetworkName)
+		fmt.Fprintf(writer, "  Network ID: %s\n", stack.NetworkID)
+		fmt.Fprintf(writer, "  Members: %d\n", len(stack.Serials))
+		for _, serial := range stack.Serials {
+			fmt.Fprintf(writer, "    - %s\n", serial)
+		}
+		fmt.Fprintf(writer, "\n")
 	}
 
 	return nil
 }
 
-// WriteToFile writes data to a file in JSON format
-func (w *JSONWriter) WriteToFile(data interface{}, filename string) error {
-	file, err := os.Create(filename)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
+// writeDHCPSubnets writes DHCP subnets to an io.Writer in text format
+func (w *TextWriter) writeDHCPSubnets(subnets []meraki.DHCPSubnetWithNetwork, writer io.Writer) error {
+	// Write header
+	fmt.Fprintf(writer, "Meraki DHCP Subnets\n")
+	fmt.Fprintf(writer, "===================\n\n")
+	fmt.Fprintf(writer, "Total Subnets: %d\n\n", len(subnets))
+
+	// Write subnets
+	for i, subnet := range subnets {
+		fmt.Fprintf(writer, "Subnet %d:\n", i+1)
+		fmt.Fprintf(writer, "  Name: %s\n", subnet.Name)
+		if subnet.Organization != "" {
+			fmt.Fprintf(writer, "  Organization: %s\n", subnet.Organization)
+		}
+		fmt.Fprintf(writer, "  Network Name: %s\n", subnet.NetworkName)
+		fmt.Fprintf(writer, "  Network ID: %s\n", subnet.NetworkID)
+		fmt.Fprintf(writer, "  Source: %s\n", subnet.Source)
+		if subnet.StackID != "" {
+			fmt.Fprintf(writer, "  Stack: %s (%s)\n", subnet.StackName, subnet.StackID)
+		}
+		fmt.Fprintf(writer, "  Interface ID: %s\n", subnet.InterfaceID)
+		if subnet.VLAN != 0 {
+			fmt.Fprintf(writer, "  VLAN: %d\n", subnet.VLAN)
+		}
+		fmt.Fprintf(writer, "  Subnet: %s\n", subnet.Subnet)
+		fmt.Fprintf(writer, "  Gateway IP: %s\n", subnet.GatewayIP)
+		fmt.Fprintf(writer, "  Mode: %s\n", subnet.Mode)
+		if len(subnet.RelayServerIPs) > 0 {
+			fmt.Fprintf(writer, "  Relay Servers: %s\n", strings.Join(subnet.RelayServerIPs, ", "))
+		}
+		if subnet.LeaseTime != "" {
+			fmt.Fprintf(writer, "  Lease Time: %s\n", subnet.LeaseTime)
+		}
+		if len(subnet.DNSNameservers) > 0 {
+			fmt.Fprintf(writer, "  DNS Nameservers: %s\n", strings.Join(subnet.DNSNameservers, ", "))
+		}
+		if len(subnet.ReservedRanges) > 0 {
+			fmt.Fprintf(writer, "  Reserved Ranges:\n")
+			for _, reserved := range subnet.ReservedRanges {
+				fmt.Fprintf(writer, "    - %s\n", formatReservedRange(reserved))
+			}
+		}
+		if len(subnet.Options) > 0 {
+			fmt.Fprintf(writer, "  Options:\n")
+			for _, option := range subnet.Options {
+				fmt.Fprintf(writer, "    - %s\n", formatDHCPOption(option))
+			}
+		}
+		fmt.Fprintf(writer, "\n")
+	}
+
+	return nil
+}
+
+// formatReservedRange formats a reserved range as START-END, followed by its comment if any
+func formatReservedRange(reserved meraki.DHCPReservedRange) string {
+	if reserved.Comment == "" {
+		return reserved.Start + "-" + reserved.End
+	}
+	return fmt.Sprintf("%s-%s (%s)", reserved.Start, reserved.End, reserved.Comment)
+}
+
+// formatDHCPOption formats a DHCP option as CODE=VALUE with its type
+func formatDHCPOption(option meraki.DHCPOption) string {
+	return fmt.Sprintf("%s=%s (%s)", option.Code, option.Value, option.Type)
+}
+
+// writeEvents writes network events to an io.Writer in text format
+func (w *TextWriter) writeEvents(events []meraki.EventWithNetwork, writer io.Writer) error {
+	// Write header
+	fmt.Fprintf(writer, "Meraki Network Events\n")
+	fmt.Fprintf(writer, "=====================\n\n")
+	fmt.Fprintf(writer, "Total Events: %d\n\n", len(events))
+
+	// Write events
+	for i, event := range events {
+		fmt.Fprintf(writer, "Event %d:\n", i+1)
+		fmt.Fprintf(writer, "  Occurred At: %s\n", event.OccurredAt)
+		fmt.Fprintf(writer, "  Type: %s\n", event.Type)
+		fmt.Fprintf(writer, "  Description: %s\n", event.Description)
+		if event.DeviceSerial != "" || event.DeviceName != "" {
+			fmt.Fprintf(writer, "  Device: %s (%s)\n", event.DeviceName, event.DeviceSerial)
+		}
+		if event.ClientID != "" || event.ClientDescription != "" || event.ClientMac != "" {
+			fmt.Fprintf(writer, "  Client: %s (%s)\n", event.ClientDescription, event.ClientMac)
+		}
+		if event.Organization != "" {
+			fmt.Fprintf(writer, "  Organization: %s\n", event.Organization)
+		}
+		fmt.Fprintf(writer, "  Network Name: %s\n", event.NetworkName)
+		fmt.Fprintf(writer, "  Network ID: %s\n", event.NetworkID)
+		fmt.Fprintf(writer, "\n")
 	}
-	defer file.Close()
 
-	return w.WriteTo(data, file)
+	return nil
+}
+
+// WriteToFile writes data to a file in JSON format
+func (w *JSONWriter) WriteToFile(data interface{}, filename string) error {
+	return w.writeFile(filename, func(writer io.Writer) error {
+		return w.WriteTo(data, writer)
+	})
 }
 
 // WriteTo writes data to an io.Writer in JSON format
 func (w *JSONWriter) WriteTo(data interface{}, writer io.Writer) error {
+	if w.Envelope {
+		return w.writeJSONEnvelope(data, writer)
+	}
+	if len(w.Fields) > 0 {
+		return w.writeJSONFields(data, writer)
+	}
+	if w.Native {
+		data = toNativeJSON(data)
+	}
+
 	encoder := json.NewEncoder(writer)
-	encoder.SetIndent("", "  ")
+	encoder.SetIndent("", w.indent())
 
 	if err := encoder.Encode(data); err != nil {
 		return fmt.Errorf("failed to encode JSON: %w", err)
@@ -379,13 +850,9 @@ func (w *JSONWriter) WriteTo(data interface{}, writer io.Writer) error {
 
 // WriteToFile writes data to a file in XML format
 func (w *XMLWriter) WriteToFile(data interface{}, filename string) error {
-	file, err := os.Create(filename)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
-	}
-	defer file.Close()
-
-	return w.WriteTo(data, file)
+	return w.writeFile(filename, func(writer io.Writer) error {
+		return w.WriteTo(data, writer)
+	})
 }
 
 // WriteTo writes data to an io.Writer in XML format
@@ -400,15 +867,51 @@ func (w *XMLWriter) WriteTo(data interface{}, writer io.Writer) error {
 	case []meraki.LicenseWithNetwork:
 		// Use JSON encoding for consolidated structures
 		encoder := json.NewEncoder(writer)
-		encoder.SetIndent("", "  ")
+		encoder.SetIndent("", w.indent())
 		return encoder.Encode(v)
 	case []meraki.Device:
 		return w.writeDevicesXML(v, writer)
 	case []meraki.DeviceWithNetwork:
 		// Use JSON encoding for consolidated structures
 		encoder := json.NewEncoder(writer)
-		encoder.SetIndent("", "  ")
+		encoder.SetIndent("", w.indent())
 		return encoder.Encode(v)
+	case []meraki.DeviceStatusSummary:
+		return w.writeDeviceStatusSummaryXML(v, writer)
+	case []meraki.SwitchStackWithNetwork:
+		return w.writeSwitchStacksXML(v, writer)
+	case []meraki.DHCPSubnetWithNetwork:
+		return w.writeDHCPSubnetsXML(v, writer)
+	case []meraki.FirewallRuleWithNetwork:
+		return w.writeFirewallRulesXML(v, writer)
+	case []meraki.NetworkWithOrganization:
+		return w.writeNetworksXML(v, writer)
+	case []meraki.SwitchPortStatusWithNetwork:
+		return w.writeSwitchPortsXML(v, writer)
+	case []meraki.DeviceDetails:
+		return w.writeDeviceDetailsXML(v, writer)
+	case []meraki.RouteOverlap:
+		return w.writeRouteOverlapsXML(v, writer)
+	case []meraki.EventWithNetwork:
+		return w.writeEventsXML(v, writer)
+	case []meraki.ConfigurationChangeWithOrganization:
+		return w.writeConfigurationChangesXML(v, writer)
+	case []meraki.DeviceLocation:
+		return w.writeDeviceLocationsXML(v, writer)
+	case []meraki.AppliancePerformance:
+		return w.writeAppliancePerformanceXML(v, writer)
+	case []meraki.OrganizationLicenseSummary:
+		return w.writeOrganizationLicenseSummariesXML(v, writer)
+	case meraki.AccessInfo:
+		return w.writeAccessInfoXML(v, writer)
+	case []meraki.APIUsage:
+		return w.writeAPIUsageXML(v, writer)
+	case Summary:
+		return w.writeSummaryXML(v, writer)
+	case RunSummary:
+		return w.writeRunSummaryXML(v, writer)
+	case []RecordChange:
+		return w.writeRecordChangesXML(v, writer)
 	default:
 		return fmt.Errorf("unsupported data type: %T", data)
 	}
@@ -436,13 +939,14 @@ func (w *XMLWriter) writeRoutesXML(routes []meraki.Route, writer io.Writer) erro
 			GatewayVlan: route.GatewayVlan,
 			Enabled:     route.Enabled,
 			FixedIP:     fixedIPStr,
+			VPNMode:     route.VPNMode,
 		}
 	}
 
 	routesXML := RoutesXML{Routes: xmlRoutes}
 
 	encoder := xml.NewEncoder(writer)
-	encoder.Indent("", "  ")
+	encoder.Indent("", w.indent())
 
 	// Write XML header
 	fmt.Fprint(writer, xml.Header)
@@ -469,23 +973,25 @@ func (w *XMLWriter) writeRoutesWithNetworkXML(routes []meraki.RouteWithNetwork,
 		}
 
 		xmlRoutes[i] = RouteWithNetworkXML{
-			ID:           route.ID,
-			Name:         route.Name,
-			Subnet:       route.Subnet,
-			GatewayIP:    route.GatewayIP,
-			GatewayVlan:  route.GatewayVlan,
-			Enabled:      route.Enabled,
-			FixedIP:      fixedIPStr,
-			NetworkID:    route.NetworkID,
-			NetworkName:  route.NetworkName,
-			Organization: route.Organization,
+			ID:             route.ID,
+			Name:           route.Name,
+			Subnet:         route.Subnet,
+			GatewayIP:      route.GatewayIP,
+			GatewayVlan:    route.GatewayVlan,
+			Enabled:        route.Enabled,
+			FixedIP:        fixedIPStr,
+			VPNMode:        route.VPNMode,
+			NetworkID:      route.NetworkID,
+			NetworkName:    route.NetworkName,
+			Organization:   route.Organization,
+			OrganizationID: route.OrganizationID,
 		}
 	}
 
 	routesXML := RoutesWithNetworkXML{Routes: xmlRoutes}
 
 	encoder := xml.NewEncoder(writer)
-	encoder.Indent("", "  ")
+	encoder.Indent("", w.indent())
 
 	// Write XML header
 	fmt.Fprint(writer, xml.Header)
@@ -507,6 +1013,7 @@ func (w *XMLWriter) writeLicensesXML(licenses []meraki.License, writer io.Writer
 			OrganizationID:    license.OrganizationID,
 			DeviceSerial:      license.DeviceSerial,
 			NetworkID:         license.NetworkID,
+			NetworkName:       license.NetworkName,
 			State:             license.State,
 			Edition:           license.Edition,
 			Mode:              license.Mode,
@@ -522,7 +1029,7 @@ func (w *XMLWriter) writeLicensesXML(licenses []meraki.License, writer io.Writer
 	licensesXML := LicensesXML{Licenses: xmlLicenses}
 
 	encoder := xml.NewEncoder(writer)
-	encoder.Indent("", "  ")
+	encoder.Indent("", w.indent())
 
 	// Write XML header
 	fmt.Fprint(writer, xml.Header)
@@ -553,13 +1060,16 @@ func (w *XMLWriter) writeDevicesXML(devices []meraki.Device, writer io.Writer) e
 			Lat:            device.Lat,
 			Lng:            device.Lng,
 			Notes:          device.Notes,
+			DownDuration:   device.DownDuration,
+			WarmSpareRole:  device.WarmSpareRole,
+			HealthAlert:    device.HealthAlert,
 		}
 	}
 
 	devicesXML := DevicesXML{Devices: xmlDevices}
 
 	encoder := xml.NewEncoder(writer)
-	encoder.Indent("", "  ")
+	encoder.Indent("", w.indent())
 
 	// Write XML header
 	fmt.Fprint(writer, xml.Header)
@@ -571,19 +1081,172 @@ func (w *XMLWriter) writeDevicesXML(devices []meraki.Device, writer io.Writer) e
 	return nil
 }
 
-// WriteToFile writes data to a file in CSV format
-func (w *CSVWriter) WriteToFile(data interface{}, filename string) error {
-	file, err := os.Create(filename)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
+// writeDeviceStatusSummaryXML writes device status counts to an io.Writer in XML format
+func (w *XMLWriter) writeDeviceStatusSummaryXML(summaries []meraki.DeviceStatusSummary, writer io.Writer) error {
+	// Convert summaries to XML-compatible format
+	xmlSummaries := make([]DeviceStatusSummaryXML, len(summaries))
+	for i, summary := range summaries {
+		xmlSummaries[i] = DeviceStatusSummaryXML{
+			Scope:          summary.Scope,
+			Organization:   summary.Organization,
+			OrganizationID: summary.OrganizationID,
+			NetworkID:      summary.NetworkID,
+			NetworkName:    summary.NetworkName,
+			ProductType:    summary.ProductType,
+			Online:         summary.Online,
+			Offline:        summary.Offline,
+			Alerting:       summary.Alerting,
+			Dormant:        summary.Dormant,
+			Total:          summary.Total,
+		}
+	}
+
+	summariesXML := DeviceStatusSummariesXML{Summaries: xmlSummaries}
+
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", w.indent())
+
+	// Write XML header
+	fmt.Fprint(writer, xml.Header)
+
+	if err := encoder.Encode(summariesXML); err != nil {
+		return fmt.Errorf("failed to encode XML: %w", err)
 	}
-	defer file.Close()
 
-	return w.WriteTo(data, file)
+	return nil
+}
+
+// writeSwitchStacksXML writes switch stacks to an io.Writer in XML format
+func (w *XMLWriter) writeSwitchStacksXML(stacks []meraki.SwitchStackWithNetwork, writer io.Writer) error {
+	// Convert stacks to XML-compatible format
+	xmlStacks := make([]SwitchStackXML, len(stacks))
+	for i, stack := range stacks {
+		xmlStacks[i] = SwitchStackXML{
+			ID:             stack.ID,
+			Name:           stack.Name,
+			Serials:        stack.Serials,
+			Organization:   stack.Organization,
+			OrganizationID: stack.OrganizationID,
+			NetworkID:      stack.NetworkID,
+			NetworkName:    stack.NetworkName,
+		}
+	}
+
+	stacksXML := SwitchStacksXML{Stacks: xmlStacks}
+
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", w.indent())
+
+	// Write XML header
+	fmt.Fprint(writer, xml.Header)
+
+	if err := encoder.Encode(stacksXML); err != nil {
+		return fmt.Errorf("failed to encode XML: %w", err)
+	}
+
+	return nil
+}
+
+// writeDHCPSubnetsXML writes DHCP subnets to an io.Writer in XML format
+func (w *XMLWriter) writeDHCPSubnetsXML(subnets []meraki.DHCPSubnetWithNetwork, writer io.Writer) error {
+	// Convert subnets to XML-compatible format
+	xmlSubnets := make([]DHCPSubnetXML, len(subnets))
+	for i, subnet := range subnets {
+		xmlSubnets[i] = DHCPSubnetXML{
+			Source:         subnet.Source,
+			StackID:        subnet.StackID,
+			StackName:      subnet.StackName,
+			InterfaceID:    subnet.InterfaceID,
+			Name:           subnet.Name,
+			VLAN:           subnet.VLAN,
+			Subnet:         subnet.Subnet,
+			GatewayIP:      subnet.GatewayIP,
+			Mode:           subnet.Mode,
+			RelayServerIPs: subnet.RelayServerIPs,
+			LeaseTime:      subnet.LeaseTime,
+			DNSNameservers: subnet.DNSNameservers,
+			ReservedRanges: subnet.ReservedRanges,
+			Options:        subnet.Options,
+			Organization:   subnet.Organization,
+			OrganizationID: subnet.OrganizationID,
+			NetworkID:      subnet.NetworkID,
+			NetworkName:    subnet.NetworkName,
+		}
+	}
+
+	subnetsXML := DHCPSubnetsXML{Subnets: xmlSubnets}
+
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", w.indent())
+
+	// Write XML header
+	fmt.Fprint(writer, xml.Header)
+
+	if err := encoder.Encode(subnetsXML); err != nil {
+		return fmt.Errorf("failed to encode XML: %w", err)
+	}
+
+	return nil
+}
+
+// writeEventsXML writes network events to an io.Writer in XML format
+func (w *XMLWriter) writeEventsXML(events []meraki.EventWithNetwork, writer io.Writer) error {
+	// Convert events to XML-compatible format
+	xmlEvents := make([]EventXML, len(events))
+	for i, event := range events {
+		xmlEvents[i] = EventXML{
+			OccurredAt:        event.OccurredAt,
+			Type:              event.Type,
+			Description:       event.Description,
+			Category:          event.Category,
+			DeviceSerial:      event.DeviceSerial,
+			DeviceName:        event.DeviceName,
+			ClientID:          event.ClientID,
+			ClientDescription: event.ClientDescription,
+			ClientMac:         event.ClientMac,
+			Organization:      event.Organization,
+			OrganizationID:    event.OrganizationID,
+			NetworkID:         event.NetworkID,
+			NetworkName:       event.NetworkName,
+		}
+	}
+
+	eventsXML := EventsXML{Events: xmlEvents}
+
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", w.indent())
+
+	// Write XML header
+	fmt.Fprint(writer, xml.Header)
+
+	if err := encoder.Encode(eventsXML); err != nil {
+		return fmt.Errorf("failed to encode XML: %w", err)
+	}
+
+	return nil
+}
+
+// WriteToFile writes data to a file in CSV format
+func (w *CSVWriter) WriteToFile(data interface{}, filename string) error {
+	return w.writeFile(filename, func(writer io.Writer) error {
+		return w.WriteTo(data, writer)
+	})
 }
 
 // WriteTo writes data to an io.Writer in CSV format
 func (w *CSVWriter) WriteTo(data interface{}, writer io.Writer) error {
+	if w.BOM && !csvWritesJSON(data) {
+		if _, err := io.WriteString(writer, utf8BOM); err != nil {
+			return fmt.Errorf("failed to write byte order mark: %w", err)
+		}
+		plain := *w
+		plain.BOM = false
+		return plain.WriteTo(data, writer)
+	}
+	if len(w.Fields) > 0 {
+		return w.writeCSVFields(data, writer)
+	}
+
 	switch v := data.(type) {
 	case []meraki.Route:
 		return w.writeRoutesCSV(v, writer)
@@ -603,6 +1266,42 @@ func (w *CSVWriter) WriteTo(data interface{}, writer io.Writer) error {
 		encoder := json.NewEncoder(writer)
 		encoder.SetIndent("", "  ")
 		return encoder.Encode(v)
+	case []meraki.DeviceStatusSummary:
+		return w.writeDeviceStatusSummaryCSV(v, writer)
+	case []meraki.SwitchStackWithNetwork:
+		return w.writeSwitchStacksCSV(v, writer)
+	case []meraki.DHCPSubnetWithNetwork:
+		return w.writeDHCPSubnetsCSV(v, writer)
+	case []meraki.FirewallRuleWithNetwork:
+		return w.writeFirewallRulesCSV(v, writer)
+	case []meraki.NetworkWithOrganization:
+		return w.writeNetworksCSV(v, writer)
+	case []meraki.SwitchPortStatusWithNetwork:
+		return w.writeSwitchPortsCSV(v, writer)
+	case []meraki.DeviceDetails:
+		return w.writeDeviceDetailsCSV(v, writer)
+	case []meraki.RouteOverlap:
+		return w.writeRouteOverlapsCSV(v, writer)
+	case []meraki.EventWithNetwork:
+		return w.writeEventsCSV(v, writer)
+	case []meraki.ConfigurationChangeWithOrganization:
+		return w.writeConfigurationChangesCSV(v, writer)
+	case []meraki.DeviceLocation:
+		return w.writeDeviceLocationsCSV(v, writer)
+	case []meraki.AppliancePerformance:
+		return w.writeAppliancePerformanceCSV(v, writer)
+	case []meraki.OrganizationLicenseSummary:
+		return w.writeOrganizationLicenseSummariesCSV(v, writer)
+	case meraki.AccessInfo:
+		return w.writeAccessInfoCSV(v, writer)
+	case []meraki.APIUsage:
+		return w.writeAPIUsageCSV(v, writer)
+	case Summary:
+		return w.writeSummaryCSV(v, writer)
+	case RunSummary:
+		return w.writeRunSummaryCSV(v, writer)
+	case []RecordChange:
+		return w.writeRecordChangesCSV(v, writer)
 	default:
 		return fmt.Errorf("unsupported data type: %T", data)
 	}
@@ -610,11 +1309,11 @@ func (w *CSVWriter) WriteTo(data interface{}, writer io.Writer) error {
 
 // writeRoutesCSV writes routes to an io.Writer in CSV format
 func (w *CSVWriter) writeRoutesCSV(routes []meraki.Route, writer io.Writer) error {
-	csvWriter := csv.NewWriter(writer)
+	csvWriter := w.newCSVWriter(writer)
 	defer csvWriter.Flush()
 
 	// Write header
-	header := []string{"ID", "Name", "Subnet", "Gateway IP", "Gateway VLAN", "Enabled", "Fixed IP"}
+	header := []string{"ID", "Name", "Subnet", "Gateway IP", "Gateway VLAN", "Enabled", "Fixed IP", "VPN Mode"}
 	if err := csvWriter.Write(header); err != nil {
 		return fmt.Errorf("failed to write CSV header: %w", err)
 	}
@@ -629,6 +1328,7 @@ func (w *CSVWriter) writeRoutesCSV(routes []meraki.Route, writer io.Writer) erro
 			fmt.Sprintf("%d", route.GatewayVlan),
 			fmt.Sprintf("%t", route.Enabled),
 			fmt.Sprintf("%v", route.FixedIP),
+			route.VPNMode,
 		}
 		if err := csvWriter.Write(record); err != nil {
 			return fmt.Errorf("failed to write CSV record: %w", err)
@@ -640,11 +1340,11 @@ func (w *CSVWriter) writeRoutesCSV(routes []meraki.Route, writer io.Writer) erro
 
 // writeRoutesWithNetworkCSV writes routes with network information to an io.Writer in CSV format
 func (w *CSVWriter) writeRoutesWithNetworkCSV(routes []meraki.RouteWithNetwork, writer io.Writer) error {
-	csvWriter := csv.NewWriter(writer)
+	csvWriter := w.newCSVWriter(writer)
 	defer csvWriter.Flush()
 
 	// Write header
-	header := []string{"Organization", "Network ID", "Network Name", "ID", "Name", "Subnet", "Gateway IP", "Gateway VLAN", "Enabled", "Fixed IP"}
+	header := []string{"Organization", "Organization ID", "Network ID", "Network Name", "ID", "Name", "Subnet", "Gateway IP", "Gateway VLAN", "Enabled", "Fixed IP", "VPN Mode"}
 	if err := csvWriter.Write(header); err != nil {
 		return fmt.Errorf("failed to write CSV header: %w", err)
 	}
@@ -653,6 +1353,7 @@ func (w *CSVWriter) writeRoutesWithNetworkCSV(routes []meraki.RouteWithNetwork,
 	for _, route := range routes {
 		record := []string{
 			route.Organization,
+			route.OrganizationID,
 			route.NetworkID,
 			route.NetworkName,
 			route.ID,
@@ -662,6 +1363,7 @@ func (w *CSVWriter) writeRoutesWithNetworkCSV(routes []meraki.RouteWithNetwork,
 			fmt.Sprintf("%d", route.GatewayVlan),
 			fmt.Sprintf("%t", route.Enabled),
 			fmt.Sprintf("%v", route.FixedIP),
+			route.VPNMode,
 		}
 		if err := csvWriter.Write(record); err != nil {
 			return fmt.Errorf("failed to write CSV record: %w", err)
@@ -673,11 +1375,11 @@ func (w *CSVWriter) writeRoutesWithNetworkCSV(routes []meraki.RouteWithNetwork,
 
 // writeLicensesCSV writes licenses to an io.Writer in CSV format
 func (w *CSVWriter) writeLicensesCSV(licenses []meraki.License, writer io.Writer) error {
-	csvWriter := csv.NewWriter(writer)
+	csvWriter := w.newCSVWriter(writer)
 	defer csvWriter.Flush()
 
 	// Write header
-	header := []string{"ID", "Organization ID", "Device Serial", "Network ID", "State", "Edition", "Mode", "License Type", "License Key", "Order Number", "Duration (Days)", "Expiration Date", "Permanently Queued"}
+	header := []string{"ID", "Organization ID", "Device Serial", "Network ID", "Network Name", "State", "Edition", "Mode", "License Type", "License Key", "Order Number", "Duration (Days)", "Expiration Date", "Permanently Queued"}
 	if err := csvWriter.Write(header); err != nil {
 		return fmt.Errorf("failed to write CSV header: %w", err)
 	}
@@ -689,6 +1391,7 @@ func (w *CSVWriter) writeLicensesCSV(licenses []meraki.License, writer io.Writer
 			license.OrganizationID,
 			license.DeviceSerial,
 			license.NetworkID,
+			license.NetworkName,
 			license.State,
 			license.Edition,
 			license.Mode,
@@ -709,11 +1412,11 @@ func (w *CSVWriter) writeLicensesCSV(licenses []meraki.License, writer io.Writer
 
 // writeDevicesCSV writes devices to an io.Writer in CSV format
 func (w *CSVWriter) writeDevicesCSV(devices []meraki.Device, writer io.Writer) error {
-	csvWriter := csv.NewWriter(writer)
+	csvWriter := w.newCSVWriter(writer)
 	defer csvWriter.Flush()
 
 	// Write header
-	header := []string{"Serial", "Name", "Model", "Network ID", "MAC", "Status", "Last Reported At", "Product Type", "Tags", "Address", "Latitude", "Longitude", "Notes"}
+	header := []string{"Serial", "Name", "Model", "Network ID", "MAC", "Status", "Last Reported At", "Product Type", "Tags", "Address", "Latitude", "Longitude", "Notes", "Down Duration", "Warm Spare Role", "Health Alert"}
 	if err := csvWriter.Write(header); err != nil {
 		return fmt.Errorf("failed to write CSV header: %w", err)
 	}
@@ -740,6 +1443,43 @@ func (w *CSVWriter) writeDevicesCSV(devices []meraki.Device, writer io.Writer) e
 			fmt.Sprintf("%.6f", device.Lat),
 			fmt.Sprintf("%.6f", device.Lng),
 			device.Notes,
+			device.DownDuration,
+			device.WarmSpareRole,
+			device.HealthAlert,
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// writeDeviceStatusSummaryCSV writes device status counts to an io.Writer in CSV format
+func (w *CSVWriter) writeDeviceStatusSummaryCSV(summaries []meraki.DeviceStatusSummary, writer io.Writer) error {
+	csvWriter := w.newCSVWriter(writer)
+	defer csvWriter.Flush()
+
+	// Write header
+	header := []string{"Scope", "Organization", "Organization ID", "Network ID", "Network Name", "Product Type", "Online", "Offline", "Alerting", "Dormant", "Total"}
+	if err := csvWriter.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	// Write summaries
+	for _, summary := range summaries {
+		record := []string{
+			summary.Scope,
+			summary.Organization,
+			summary.OrganizationID,
+			summary.NetworkID,
+			summary.NetworkName,
+			summary.ProductType,
+			fmt.Sprintf("%d", summary.Online),
+			fmt.Sprintf("%d", summary.Offline),
+			fmt.Sprintf("%d", summary.Alerting),
+			fmt.Sprintf("%d", summary.Dormant),
+			fmt.Sprintf("%d", summary.Total),
 		}
 		if err := csvWriter.Write(record); err != nil {
 			return fmt.Errorf("failed to write CSV record: %w", err)
@@ -748,3 +1488,236 @@ func (w *CSVWriter) writeDevicesCSV(devices []meraki.Device, writer io.Writer) e
 
 	return nil
 }
+
+// writeSwitchStacksCSV writes switch stacks to an io.Writer in CSV format
+func (w *CSVWriter) writeSwitchStacksCSV(stacks []meraki.SwitchStackWithNetwork, writer io.Writer) error {
+	csvWriter := w.newCSVWriter(writer)
+	defer csvWriter.Flush()
+
+	// Write header
+	header := []string{"Organization", "Organization ID", "Network ID", "Network Name", "Stack ID", "Stack Name", "Member Count", "Member Serials"}
+	if err := csvWriter.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	// Write stacks
+	for _, stack := range stacks {
+		record := []string{
+			stack.Organization,
+			stack.OrganizationID,
+			stack.NetworkID,
+			stack.NetworkName,
+			stack.ID,
+			stack.Name,
+			fmt.Sprintf("%d", len(stack.Serials)),
+			strings.Join(stack.Serials, ";"),
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// writeDHCPSubnetsCSV writes DHCP subnets to an io.Writer in CSV format
+func (w *CSVWriter) writeDHCPSubnetsCSV(subnets []meraki.DHCPSubnetWithNetwork, writer io.Writer) error {
+	csvWriter := w.newCSVWriter(writer)
+	defer csvWriter.Flush()
+
+	// Write header
+	header := []string{"Organization", "Organization ID", "Network ID", "Network Name", "Source", "Stack ID", "Stack Name",
+		"Interface ID", "Name", "VLAN", "Subnet", "Gateway IP", "Mode", "Relay Servers", "Lease Time", "DNS Nameservers",
+		"Reserved Ranges", "Options"}
+	if err := csvWriter.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	// Write subnets
+	for _, subnet := range subnets {
+		reserved := make([]string, len(subnet.ReservedRanges))
+		for i, reservedRange := range subnet.ReservedRanges {
+			reserved[i] = formatReservedRange(reservedRange)
+		}
+		options := make([]string, len(subnet.Options))
+		for i, option := range subnet.Options {
+			options[i] = formatDHCPOption(option)
+		}
+		vlan := ""
+		if subnet.VLAN != 0 {
+			vlan = fmt.Sprintf("%d", subnet.VLAN)
+		}
+
+		record := []string{
+			subnet.Organization,
+			subnet.OrganizationID,
+			subnet.NetworkID,
+			subnet.NetworkName,
+			subnet.Source,
+			subnet.StackID,
+			subnet.StackName,
+			subnet.InterfaceID,
+			subnet.Name,
+			vlan,
+			subnet.Subnet,
+			subnet.GatewayIP,
+			subnet.Mode,
+			strings.Join(subnet.RelayServerIPs, ";"),
+			subnet.LeaseTime,
+			strings.Join(subnet.DNSNameservers, ";"),
+			strings.Join(reserved, ";"),
+			strings.Join(options, ";"),
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// writeEventsCSV writes network events to an io.Writer in CSV format
+func (w *CSVWriter) writeEventsCSV(events []meraki.EventWithNetwork, writer io.Writer) error {
+	csvWriter := w.newCSVWriter(writer)
+	defer csvWriter.Flush()
+
+	// Write header
+	header := []string{"Organization", "Organization ID", "Network ID", "Network Name", "Occurred At", "Type", "Description", "Category", "Device Serial", "Device Name", "Client ID", "Client Description", "Client MAC"}
+	if err := csvWriter.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	// Write events
+	for _, event := range events {
+		record := []string{
+			event.Organization,
+			event.OrganizationID,
+			event.NetworkID,
+			event.NetworkName,
+			event.OccurredAt,
+			event.Type,
+			event.Description,
+			event.Category,
+			event.DeviceSerial,
+			event.DeviceName,
+			event.ClientID,
+			event.ClientDescription,
+			event.ClientMac,
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// WriteToFile writes data to a file in Prometheus text format. The file is replaced atomically,
+// so the textfile collector never reads a partial file.
+func (w *PrometheusWriter) WriteToFile(data interface{}, filename string) error {
+	return w.writeFile(filename, func(writer io.Writer) error {
+		return w.WriteTo(data, writer)
+	})
+}
+
+// WriteTo writes data to an io.Writer in Prometheus text format
+func (w *PrometheusWriter) WriteTo(data interface{}, writer io.Writer) error {
+	switch v := data.(type) {
+	case []meraki.Device:
+		devices := make([]meraki.DeviceWithNetwork, len(v))
+		for i, device := range v {
+			devices[i] = meraki.DeviceWithNetwork{Device: device, NetworkID: device.NetworkID}
+		}
+		return w.writeDevicesPrometheus(devices, writer)
+	case []meraki.DeviceWithNetwork:
+		return w.writeDevicesPrometheus(v, writer)
+	case []meraki.License:
+		licenses := make([]meraki.LicenseWithNetwork, len(v))
+		for i, license := range v {
+			licenses[i] = meraki.LicenseWithNetwork{License: license, OrganizationID: license.OrganizationID}
+		}
+		return w.writeLicensesPrometheus(licenses, writer)
+	case []meraki.LicenseWithNetwork:
+		return w.writeLicensesPrometheus(v, writer)
+	default:
+		return fmt.Errorf("unsupported data type for prometheus format: %T", data)
+	}
+}
+
+// writeDevicesPrometheus writes one sample with value 1 per device
+func (w *PrometheusWriter) writeDevicesPrometheus(devices []meraki.DeviceWithNetwork, writer io.Writer) error {
+	metric := w.DeviceMetric
+	if metric == "" {
+		metric = "meraki_device_down"
+	}
+
+	fmt.Fprintf(writer, "# HELP %s Meraki device reported by the %s command.\n", metric, strings.TrimPrefix(metric, "meraki_device_"))
+	fmt.Fprintf(writer, "# TYPE %s gauge\n", metric)
+	for _, device := range devices {
+		labels := prometheusLabels(
+			"org", device.Organization,
+			"org_id", device.OrganizationID,
+			"network", device.NetworkName,
+			"network_id", device.NetworkID,
+			"serial", device.Serial,
+			"name", device.Name,
+			"model", device.Model,
+			"product_type", device.ProductType,
+			"status", device.Status,
+		)
+		if _, err := fmt.Fprintf(writer, "%s{%s} 1\n", metric, labels); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeLicensesPrometheus writes the whole days remaining until each license expires. Licenses
+// without an expiration date are skipped.
+func (w *PrometheusWriter) writeLicensesPrometheus(licenses []meraki.LicenseWithNetwork, writer io.Writer) error {
+	fmt.Fprintf(writer, "# HELP meraki_license_days_remaining Whole days until the Meraki license expires, negative once expired.\n")
+	fmt.Fprintf(writer, "# TYPE meraki_license_days_remaining gauge\n")
+	for _, license := range licenses {
+		days, ok, err := license.DaysRemaining()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		labels := prometheusLabels(
+			"org", license.Organization,
+			"org_id", license.OrganizationID,
+			"network", license.NetworkName,
+			"network_id", license.NetworkID,
+			"license_id", license.ID,
+			"license_type", license.LicenseType,
+			"state", license.State,
+			"device_serial", license.DeviceSerial,
+		)
+		if _, err := fmt.Fprintf(writer, "meraki_license_days_remaining{%s} %d\n", labels, days); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// prometheusLabels formats name/value pairs as a label set, escaping the values
+func prometheusLabels(pairs ...string) string {
+	labels := make([]string, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		labels = append(labels, fmt.Sprintf("%s=\"%s\"", pairs[i], escapeLabelValue(pairs[i+1])))
+	}
+	return strings.Join(labels, ",")
+}
+
+// labelValueEscaper escapes backslashes, double quotes and line feeds, as the Prometheus text
+// exposition format requires for label values
+var labelValueEscaper = strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n")
+
+// escapeLabelValue escapes a label value for the Prometheus text exposition format
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
@@ -1,11 +1,17 @@
 package output
 
 import (
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"meraki-info/internal/meraki"
 )
@@ -238,3 +244,561 @@ func TestCSVWriter_WriteToFile(t *testing.T) {
 		t.Error("Expected CSV data not found")
 	}
 }
+
+func TestCSVWriter_DeviceStatusSummary(t *testing.T) {
+	summaries := []meraki.DeviceStatusSummary{
+		{Scope: "network", Organization: "Org", OrganizationID: "1", NetworkID: "N_1", NetworkName: "HQ", Online: 3, Offline: 1, Total: 4},
+		{Scope: "productType", Organization: "Org", OrganizationID: "1", ProductType: "switch", Online: 3, Offline: 1, Total: 4},
+		{Scope: "total", Online: 3, Offline: 1, Total: 4},
+	}
+
+	var buf bytes.Buffer
+	if err := NewWriter("csv").WriteTo(summaries, &buf); err != nil {
+		t.Fatalf("Failed to write summaries: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected 4 lines (header + 3 records), got %d", len(lines))
+	}
+	if !strings.HasPrefix(lines[0], "Scope,Organization,Organization ID,Network ID,Network Name") {
+		t.Errorf("Unexpected CSV header: %s", lines[0])
+	}
+	if lines[1] != "network,Org,1,N_1,HQ,,3,1,0,0,4" {
+		t.Errorf("Unexpected network row: %s", lines[1])
+	}
+	if lines[3] != "total,,,,,,3,1,0,0,4" {
+		t.Errorf("Unexpected total row: %s", lines[3])
+	}
+}
+
+func TestAtomicWriteToFile(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "routes.json")
+
+	t.Run("successful write replaces file", func(t *testing.T) {
+		if err := os.WriteFile(filename, []byte("old"), 0644); err != nil {
+			t.Fatalf("Failed to seed file: %v", err)
+		}
+
+		err := atomicWriteToFile(filename, 0, func(w io.Writer) error {
+			_, err := w.Write([]byte("new"))
+			return err
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			t.Fatalf("Failed to read file: %v", err)
+		}
+		if string(content) != "new" {
+			t.Errorf("Expected 'new', got '%s'", string(content))
+		}
+	})
+
+	t.Run("failed write keeps original and removes temp file", func(t *testing.T) {
+		err := atomicWriteToFile(filename, 0, func(w io.Writer) error {
+			w.Write([]byte("partial"))
+			return errors.New("interrupted")
+		})
+		if err == nil {
+			t.Fatal("Expected error but got none")
+		}
+
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			t.Fatalf("Failed to read file: %v", err)
+		}
+		if string(content) != "new" {
+			t.Errorf("Expected original content to be preserved, got '%s'", string(content))
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatalf("Failed to read dir: %v", err)
+		}
+		for _, entry := range entries {
+			if strings.HasSuffix(entry.Name(), ".tmp") {
+				t.Errorf("Temporary file was not removed: %s", entry.Name())
+			}
+		}
+	})
+}
+
+func TestWriteToFile_FileMode(t *testing.T) {
+	dir := t.TempDir()
+	routes := []meraki.Route{{Subnet: "10.0.0.0/24", GatewayIP: "10.0.0.1"}}
+
+	tests := []struct {
+		name     string
+		mode     os.FileMode
+		expected os.FileMode
+	}{
+		{name: "requested mode", mode: 0600, expected: 0600},
+		{name: "mode wider than the umask", mode: 0666, expected: 0666},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(dir, tt.name+".json")
+			// Replacing an existing file must not keep its old permission
+			if err := os.WriteFile(filename, []byte("old"), 0644); err != nil {
+				t.Fatalf("Failed to seed file: %v", err)
+			}
+
+			writer := NewWriter("json")
+			SetFileMode(writer, tt.mode)
+			if err := writer.WriteToFile(routes, filename); err != nil {
+				t.Fatalf("WriteToFile failed: %v", err)
+			}
+
+			info, err := os.Stat(filename)
+			if err != nil {
+				t.Fatalf("Failed to stat file: %v", err)
+			}
+			if got := info.Mode().Perm(); got != tt.expected {
+				t.Errorf("Expected file mode %o, got %o", tt.expected, got)
+			}
+		})
+	}
+
+	t.Run("secondary output", func(t *testing.T) {
+		filename := filepath.Join(dir, "secondary.csv")
+		writer := NewDestinationWriter("csv", filename)
+		SetFileMode(writer, 0600)
+		if err := writer.WriteTo(routes, io.Discard); err != nil {
+			t.Fatalf("WriteTo failed: %v", err)
+		}
+
+		info, err := os.Stat(filename)
+		if err != nil {
+			t.Fatalf("Failed to stat file: %v", err)
+		}
+		if got := info.Mode().Perm(); got != 0600 {
+			t.Errorf("Expected file mode 600, got %o", got)
+		}
+	})
+}
+
+func TestWriters_Summary(t *testing.T) {
+	summary := Summary{
+		Title:   "Licenses",
+		GroupBy: "State",
+		Total:   3,
+		Counts: []SummaryCount{
+			{Key: "active", Count: 2},
+			{Key: "expired", Count: 1},
+		},
+	}
+
+	var textBuf bytes.Buffer
+	if err := NewWriter("text").WriteTo(summary, &textBuf); err != nil {
+		t.Fatalf("Failed to write text summary: %v", err)
+	}
+	if !strings.Contains(textBuf.String(), "Total: 3") || !strings.Contains(textBuf.String(), "  active: 2") {
+		t.Errorf("Unexpected text summary:\n%s", textBuf.String())
+	}
+
+	var csvBuf bytes.Buffer
+	if err := NewWriter("csv").WriteTo(summary, &csvBuf); err != nil {
+		t.Fatalf("Failed to write CSV summary: %v", err)
+	}
+	expectedCSV := "State,Count\nactive,2\nexpired,1\nTotal,3\n"
+	if csvBuf.String() != expectedCSV {
+		t.Errorf("Expected CSV %q, got %q", expectedCSV, csvBuf.String())
+	}
+
+	var jsonBuf bytes.Buffer
+	if err := NewWriter("json").WriteTo(summary, &jsonBuf); err != nil {
+		t.Fatalf("Failed to write JSON summary: %v", err)
+	}
+	var parsed Summary
+	if err := json.Unmarshal(jsonBuf.Bytes(), &parsed); err != nil {
+		t.Fatalf("Failed to parse JSON summary: %v", err)
+	}
+	if parsed.Total != 3 || len(parsed.Counts) != 2 {
+		t.Errorf("Unexpected JSON summary: %+v", parsed)
+	}
+}
+
+func TestWriters_LicenseNetworkName(t *testing.T) {
+	licenses := []meraki.License{{ID: "L1", DeviceSerial: "Q2SW-0001", NetworkID: "net2", NetworkName: "Branch", State: "active"}}
+
+	var csvOut bytes.Buffer
+	if err := (&CSVWriter{}).WriteTo(licenses, &csvOut); err != nil {
+		t.Fatalf("CSV WriteTo failed: %v", err)
+	}
+	if !strings.Contains(csvOut.String(), "Network ID,Network Name,State") || !strings.Contains(csvOut.String(), "Q2SW-0001,net2,Branch,active") {
+		t.Errorf("Expected CSV network name column, got:\n%s", csvOut.String())
+	}
+
+	var xmlOut bytes.Buffer
+	if err := (&XMLWriter{}).WriteTo(licenses, &xmlOut); err != nil {
+		t.Fatalf("XML WriteTo failed: %v", err)
+	}
+	if !strings.Contains(xmlOut.String(), "<networkName>Branch</networkName>") {
+		t.Errorf("Expected XML network name, got:\n%s", xmlOut.String())
+	}
+}
+
+func TestTextWriter_LicenseFooter(t *testing.T) {
+	licenses := []meraki.License{
+		{ID: "L1", State: "active", DurationInDays: 365, ExpirationDate: "2027-03-01T00:00:00Z"},
+		{ID: "L2", State: "active", DurationInDays: 1095, ExpirationDate: "2029-06-15T00:00:00Z"},
+		{ID: "L3", State: "expired", DurationInDays: 365, ExpirationDate: "2025-01-10T00:00:00Z"},
+		{ID: "L4", State: "recentlyQueued", DurationInDays: 30, PermanentlyQueued: true},
+	}
+
+	var buf bytes.Buffer
+	if err := (&TextWriter{}).WriteTo(licenses, &buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	expected := "License Statistics\n" +
+		"------------------\n" +
+		"Total Licenses: 4\n" +
+		"By State:\n" +
+		"  active: 2\n" +
+		"  expired: 1\n" +
+		"  recentlyQueued: 1\n" +
+		"Soonest Expiration: 2025-01-10T00:00:00Z\n" +
+		"Latest Expiration: 2029-06-15T00:00:00Z\n" +
+		"Total Duration (Days): 1855\n"
+	if !strings.HasSuffix(buf.String(), expected) {
+		t.Errorf("Expected footer:\n%s\ngot:\n%s", expected, buf.String())
+	}
+
+	buf.Reset()
+	if err := (&TextWriter{NoLicenseFooter: true}).WriteTo(licenses, &buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "License Statistics") {
+		t.Errorf("Expected no footer with NoLicenseFooter, got:\n%s", buf.String())
+	}
+}
+
+func TestWriters_SwitchStacks(t *testing.T) {
+	stacks := []meraki.SwitchStackWithNetwork{
+		{
+			SwitchStack:  meraki.SwitchStack{ID: "stack1", Name: "Core", Serials: []string{"Q2SW-0001", "Q2SW-0002"}},
+			NetworkID:    "net1",
+			NetworkName:  "HQ",
+			Organization: "Test Org",
+		},
+	}
+
+	var text bytes.Buffer
+	if err := (&TextWriter{}).WriteTo(stacks, &text); err != nil {
+		t.Fatalf("Text WriteTo failed: %v", err)
+	}
+	for _, expected := range []string{"Total Stacks: 1", "Name: Core", "Members: 2", "- Q2SW-0002"} {
+		if !strings.Contains(text.String(), expected) {
+			t.Errorf("Expected text output to contain %q", expected)
+		}
+	}
+
+	var csvOut bytes.Buffer
+	if err := (&CSVWriter{}).WriteTo(stacks, &csvOut); err != nil {
+		t.Fatalf("CSV WriteTo failed: %v", err)
+	}
+	if !strings.Contains(csvOut.String(), "stack1,Core,2,Q2SW-0001;Q2SW-0002") {
+		t.Errorf("Expected CSV record with member serials, got:\n%s", csvOut.String())
+	}
+
+	var xmlOut bytes.Buffer
+	if err := (&XMLWriter{}).WriteTo(stacks, &xmlOut); err != nil {
+		t.Fatalf("XML WriteTo failed: %v", err)
+	}
+	if !strings.Contains(xmlOut.String(), "<serial>Q2SW-0001</serial>") {
+		t.Errorf("Expected XML member serials, got:\n%s", xmlOut.String())
+	}
+}
+
+func TestWriters_DHCPSubnets(t *testing.T) {
+	subnets := []meraki.DHCPSubnetWithNetwork{
+		{
+			DHCPSubnet: meraki.DHCPSubnet{
+				Source:         "switch-stack",
+				StackID:        "stack1",
+				StackName:      "Core",
+				InterfaceID:    "if1",
+				Name:           "Servers",
+				VLAN:           100,
+				Subnet:         "10.1.0.0/24",
+				GatewayIP:      "10.1.0.1",
+				Mode:           meraki.DHCPModeServer,
+				LeaseTime:      "1 day",
+				DNSNameservers: []string{"10.0.0.53", "10.0.0.54"},
+				ReservedRanges: []meraki.DHCPReservedRange{{Start: "10.1.0.1", End: "10.1.0.20", Comment: "Static"}},
+				Options:        []meraki.DHCPOption{{Code: "42", Type: "ip", Value: "10.0.0.123"}},
+			},
+			NetworkID:    "net1",
+			NetworkName:  "HQ",
+			Organization: "Test Org",
+		},
+	}
+
+	var text bytes.Buffer
+	if err := (&TextWriter{}).WriteTo(subnets, &text); err != nil {
+		t.Fatalf("Text WriteTo failed: %v", err)
+	}
+	for _, expected := range []string{"Total Subnets: 1", "Stack: Core (stack1)", "Mode: server", "DNS Nameservers: 10.0.0.53, 10.0.0.54", "- 10.1.0.1-10.1.0.20 (Static)", "- 42=10.0.0.123 (ip)"} {
+		if !strings.Contains(text.String(), expected) {
+			t.Errorf("Expected text output to contain %q", expected)
+		}
+	}
+
+	var csvOut bytes.Buffer
+	if err := (&CSVWriter{}).WriteTo(subnets, &csvOut); err != nil {
+		t.Fatalf("CSV WriteTo failed: %v", err)
+	}
+	if !strings.Contains(csvOut.String(), "if1,Servers,100,10.1.0.0/24,10.1.0.1,server,,1 day,10.0.0.53;10.0.0.54,10.1.0.1-10.1.0.20 (Static),42=10.0.0.123 (ip)") {
+		t.Errorf("Expected CSV record with DHCP settings, got:\n%s", csvOut.String())
+	}
+
+	var xmlOut bytes.Buffer
+	if err := (&XMLWriter{}).WriteTo(subnets, &xmlOut); err != nil {
+		t.Fatalf("XML WriteTo failed: %v", err)
+	}
+	for _, expected := range []string{"<nameserver>10.0.0.54</nameserver>", "<start>10.1.0.1</start>", "<code>42</code>"} {
+		if !strings.Contains(xmlOut.String(), expected) {
+			t.Errorf("Expected XML output to contain %q, got:\n%s", expected, xmlOut.String())
+		}
+	}
+
+	var jsonOut bytes.Buffer
+	if err := (&JSONWriter{}).WriteTo(subnets, &jsonOut); err != nil {
+		t.Fatalf("JSON WriteTo failed: %v", err)
+	}
+	if !strings.Contains(jsonOut.String(), `"mode": "server"`) {
+		t.Errorf("Expected JSON mode field, got:\n%s", jsonOut.String())
+	}
+}
+
+func TestWriters_Events(t *testing.T) {
+	events := []meraki.EventWithNetwork{
+		{
+			Event: meraki.Event{
+				OccurredAt:        "2025-07-17T02:14:00Z",
+				Type:              "association",
+				Description:       "802.11 association",
+				DeviceSerial:      "Q2MR-0001",
+				DeviceName:        "Lobby AP",
+				ClientDescription: "laptop-42",
+				ClientMac:         "aa:bb:cc:dd:ee:ff",
+			},
+			NetworkID:    "net1",
+			NetworkName:  "HQ",
+			Organization: "Test Org",
+		},
+	}
+
+	var text bytes.Buffer
+	if err := (&TextWriter{}).WriteTo(events, &text); err != nil {
+		t.Fatalf("Text WriteTo failed: %v", err)
+	}
+	for _, expected := range []string{"Total Events: 1", "Occurred At: 2025-07-17T02:14:00Z", "Type: association", "Device: Lobby AP (Q2MR-0001)", "Client: laptop-42 (aa:bb:cc:dd:ee:ff)"} {
+		if !strings.Contains(text.String(), expected) {
+			t.Errorf("Expected text output to contain %q", expected)
+		}
+	}
+
+	var csvOut bytes.Buffer
+	if err := (&CSVWriter{}).WriteTo(events, &csvOut); err != nil {
+		t.Fatalf("CSV WriteTo failed: %v", err)
+	}
+	if !strings.Contains(csvOut.String(), "2025-07-17T02:14:00Z,association,802.11 association,,Q2MR-0001,Lobby AP,,laptop-42,aa:bb:cc:dd:ee:ff") {
+		t.Errorf("Expected CSV event record, got:\n%s", csvOut.String())
+	}
+
+	var xmlOut bytes.Buffer
+	if err := (&XMLWriter{}).WriteTo(events, &xmlOut); err != nil {
+		t.Fatalf("XML WriteTo failed: %v", err)
+	}
+	if !strings.Contains(xmlOut.String(), "<deviceSerial>Q2MR-0001</deviceSerial>") {
+		t.Errorf("Expected XML device serial, got:\n%s", xmlOut.String())
+	}
+}
+
+func TestWriters_RoutesWithNetworkOrganization(t *testing.T) {
+	routes := []meraki.RouteWithNetwork{
+		{
+			Route:          meraki.Route{Subnet: "10.0.1.0/24", GatewayIP: "10.0.1.1", Enabled: true},
+			NetworkID:      "net1",
+			NetworkName:    "HQ",
+			Organization:   "Test Org",
+			OrganizationID: "123456",
+		},
+	}
+
+	var text bytes.Buffer
+	if err := (&TextWriter{}).WriteTo(routes, &text); err != nil {
+		t.Fatalf("Text WriteTo failed: %v", err)
+	}
+	for _, expected := range []string{"Organization: Test Org", "Organization ID: 123456"} {
+		if !strings.Contains(text.String(), expected) {
+			t.Errorf("Expected text output to contain %q", expected)
+		}
+	}
+
+	var csvOut bytes.Buffer
+	if err := (&CSVWriter{}).WriteTo(routes, &csvOut); err != nil {
+		t.Fatalf("CSV WriteTo failed: %v", err)
+	}
+	if !strings.HasPrefix(csvOut.String(), "Organization,Organization ID,Network ID") || !strings.Contains(csvOut.String(), "Test Org,123456,net1,HQ") {
+		t.Errorf("Expected CSV organization name and ID columns, got:\n%s", csvOut.String())
+	}
+
+	var xmlOut bytes.Buffer
+	if err := (&XMLWriter{}).WriteTo(routes, &xmlOut); err != nil {
+		t.Fatalf("XML WriteTo failed: %v", err)
+	}
+	if !strings.Contains(xmlOut.String(), "<organization>Test Org</organization>") || !strings.Contains(xmlOut.String(), "<organizationId>123456</organizationId>") {
+		t.Errorf("Expected XML organization name and ID, got:\n%s", xmlOut.String())
+	}
+}
+
+func TestPrometheusWriter(t *testing.T) {
+	devices := []meraki.DeviceWithNetwork{
+		{
+			Device:         meraki.Device{Serial: "Q2AA-0001", Name: `Lobby "AP"`, Model: "MR46", Status: "offline", ProductType: "wireless"},
+			NetworkName:    `Store\1`,
+			NetworkID:      "N_1",
+			Organization:   "Org\nA",
+			OrganizationID: "1",
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := (&PrometheusWriter{}).WriteTo(devices, &buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	expected := `meraki_device_down{org="Org\nA",org_id="1",network="Store\\1",network_id="N_1",serial="Q2AA-0001",name="Lobby \"AP\"",model="MR46",product_type="wireless",status="offline"} 1`
+	if !strings.Contains(buf.String(), "# TYPE meraki_device_down gauge\n") || !strings.Contains(buf.String(), expected+"\n") {
+		t.Errorf("Expected escaped device sample, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := (&PrometheusWriter{DeviceMetric: "meraki_device_alerting"}).WriteTo(devices, &buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "meraki_device_alerting{") || strings.Contains(buf.String(), "meraki_device_down") {
+		t.Errorf("Expected the alerting metric name, got:\n%s", buf.String())
+	}
+
+	licenses := []meraki.License{
+		{ID: "L1", LicenseType: "ENT", State: "active", ExpirationDate: time.Now().Add(10*24*time.Hour + time.Hour).Format(time.RFC3339)},
+		{ID: "L2", State: "recentlyQueued"},
+	}
+	buf.Reset()
+	if err := (&PrometheusWriter{}).WriteTo(licenses, &buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), `license_id="L1",license_type="ENT",state="active",device_serial=""} 10`) {
+		t.Errorf("Expected 10 days remaining for L1, got:\n%s", buf.String())
+	}
+	if strings.Contains(buf.String(), `"L2"`) {
+		t.Errorf("Expected license without expiration date to be skipped, got:\n%s", buf.String())
+	}
+
+	if err := (&PrometheusWriter{}).WriteTo([]meraki.Route{}, &buf); err == nil {
+		t.Error("Expected an error for routes, which have no metrics")
+	}
+}
+
+func TestTextWriter_RoutesGroupedByNetwork(t *testing.T) {
+	routes := []meraki.RouteWithNetwork{
+		{Route: meraki.Route{Subnet: "10.0.1.0/24"}, NetworkID: "net1", NetworkName: "HQ", Organization: "Org A", OrganizationID: "1"},
+		{Route: meraki.Route{Subnet: "10.0.2.0/24"}, NetworkID: "net2", NetworkName: "Branch", Organization: "Org A", OrganizationID: "1"},
+		{Route: meraki.Route{Subnet: "10.0.3.0/24"}, NetworkID: "net1", NetworkName: "HQ", Organization: "Org A", OrganizationID: "1"},
+		{Route: meraki.Route{Subnet: "10.9.0.0/24"}, NetworkID: "net9", NetworkName: "Lab", Organization: "Org B", OrganizationID: "2"},
+	}
+
+	var buf bytes.Buffer
+	if err := (&TextWriter{GroupByNetwork: true, Subtotals: true}).WriteTo(routes, &buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	output := buf.String()
+
+	hq := strings.Index(output, "Network: HQ (net1)\n------------------\nOrganization: Org A (1)\nRoutes: 2\n")
+	branch := strings.Index(output, "Network: Branch (net2)\n")
+	lab := strings.Index(output, "Network: Lab (net9)\n")
+	if hq < 0 || branch < 0 || lab < 0 || !(hq < branch && branch < lab) {
+		t.Fatalf("Expected per-network headers in first-appearance order, got:\n%s", output)
+	}
+	if section := output[hq:branch]; !strings.Contains(section, "10.0.1.0/24") || !strings.Contains(section, "  Route 2:\n    ID: \n    Name: \n    Subnet: 10.0.3.0/24") {
+		t.Errorf("Expected both HQ routes under the HQ header, got:\n%s", section)
+	}
+	if strings.Contains(output, "Route 3:") {
+		t.Errorf("Expected route numbering to restart for each network, got:\n%s", output)
+	}
+	if !strings.Contains(output, "--- Subtotal for Org A: 3 routes ---") || !strings.Contains(output, "--- Subtotal for Org B: 1 routes ---") {
+		t.Errorf("Expected per-organization subtotals after the grouped networks, got:\n%s", output)
+	}
+}
+
+func TestTextWriter_Subtotals(t *testing.T) {
+	devices := []meraki.DeviceWithNetwork{
+		{Device: meraki.Device{Serial: "A"}, Organization: "Org One", OrganizationID: "1"},
+		{Device: meraki.Device{Serial: "B"}, Organization: "Org One", OrganizationID: "1"},
+		{Device: meraki.Device{Serial: "C"}, Organization: "Org Two", OrganizationID: "2"},
+	}
+
+	var buf bytes.Buffer
+	writer := &TextWriter{Subtotals: true}
+	if err := writer.WriteTo(devices, &buf); err != nil {
+		t.Fatalf("Failed to write devices: %v", err)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, "--- Subtotal for Org One: 2 devices ---") {
+		t.Errorf("Expected Org One subtotal of 2, got:\n%s", output)
+	}
+	if !strings.Contains(output, "--- Subtotal for Org Two: 1 devices ---") {
+		t.Errorf("Expected Org Two subtotal of 1, got:\n%s", output)
+	}
+
+	// The Org One subtotal must come before the first Org Two record
+	if strings.Index(output, "Subtotal for Org One") > strings.Index(output, "Serial: C") {
+		t.Error("Expected Org One subtotal between organization groups")
+	}
+
+	// Subtotals are off by default
+	buf.Reset()
+	if err := NewWriter("text").WriteTo(devices, &buf); err != nil {
+		t.Fatalf("Failed to write devices: %v", err)
+	}
+	if strings.Contains(buf.String(), "Subtotal") {
+		t.Error("Expected no subtotal lines without -subtotals")
+	}
+}
+
+func TestCSVWriter_ConfigurationChanges(t *testing.T) {
+	changes := []meraki.ConfigurationChangeWithOrganization{{
+		ConfigurationChange: meraki.ConfigurationChange{
+			TS:       "2026-10-15T09:00:00Z",
+			Label:    "VLAN",
+			OldValue: `{"id":10,"name":"a, b"}`,
+			NewValue: "line one\nline two",
+		},
+		OrganizationID: "org1",
+	}}
+
+	var buf bytes.Buffer
+	if err := (&CSVWriter{}).WriteTo(changes, &buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected a header and one record, got %d records", len(records))
+	}
+	record := records[1]
+	if record[len(record)-2] != changes[0].OldValue || record[len(record)-1] != changes[0].NewValue {
+		t.Errorf("Expected old and new values to survive CSV quoting, got %q", record)
+	}
+}
@@ -0,0 +1,23 @@
+// Package version holds build information injected at link time
+package version
+
+import "fmt"
+
+// These values are overridden at build time with -ldflags, for example:
+//
+//	go build -ldflags "-X meraki-info/internal/version.Version=1.2.0 -X meraki-info/internal/version.Commit=$(git rev-parse --short HEAD) -X meraki-info/internal/version.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	Version   = "1.0.0"
+	Commit    = "unknown"
+	BuildDate = "unknown"
+)
+
+// String returns a human-readable version line including commit and build date
+func String() string {
+	return fmt.Sprintf("meraki-info %s (commit %s, built %s)", Version, Commit, BuildDate)
+}
+
+// UserAgent returns the User-Agent header value sent with API requests
+func UserAgent() string {
+	return "meraki-info/" + Version
+}
@@ -1,864 +1,366 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 
+	"meraki-info/internal/commands"
 	"meraki-info/internal/config"
 	"meraki-info/internal/logger"
 	"meraki-info/internal/meraki"
 	"meraki-info/internal/output"
+	"meraki-info/internal/version"
 )
 
 func main() {
 	// Parse command line flags and environment variables
 	cfg := config.ParseConfig()
 
+	if cfg.Command == "version" {
+		fmt.Println(version.String())
+		return
+	}
+	if cfg.Command == "list-formats" {
+		if err := output.ListFormats(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Initialize logger
 	logger.InitLogger(cfg.LogLevel)
 
-	slog.Info("Starting Meraki Info", "version", "1.0.0")
+	slog.Info("Starting Meraki Info", "version", version.Version, "commit", version.Commit, "build_date", version.BuildDate)
 
 	// Create Meraki client
-	client, err := meraki.NewClient(cfg.APIKey)
+	client, err := meraki.NewClientWithBaseURL(cfg.APIKey, cfg.BaseURL)
 	if err != nil {
 		slog.Error("Failed to create Meraki client", "error", err)
 		os.Exit(1)
 	}
-
-	// Resolve organization name to ID if needed
-	if cfg.Organization != "" {
-		resolvedOrgID, err := client.ResolveOrganizationID(cfg.Organization)
+	if cfg.Proxy != "" {
+		if err := client.SetProxy(cfg.Proxy); err != nil {
+			slog.Error("Failed to configure proxy", "error", err)
+			os.Exit(1)
+		}
+	} else if cfg.NoProxy {
+		if err := client.DisableProxy(); err != nil {
+			slog.Error("Failed to disable proxy", "error", err)
+			os.Exit(1)
+		}
+	}
+	if proxyURL, err := client.ProxyURL(); err != nil {
+		slog.Debug("Unable to determine proxy", "error", err)
+	} else if proxyURL != nil {
+		slog.Debug("Using proxy for API requests", "proxy", proxyURL.Redacted())
+	} else {
+		slog.Debug("Connecting to API directly without a proxy")
+	}
+	if err := client.SetETagCache(cfg.ETagCache); err != nil {
+		slog.Error("Failed to load ETag cache", "file", cfg.ETagCache, "error", err)
+		os.Exit(1)
+	}
+	// Write the ETag cache once, however the run ends
+	defer saveETagCache(client)
+	exit = func(code int) {
+		saveETagCache(client)
+		os.Exit(code)
+	}
+	client.SetVPNModeFilter(cfg.VPNMode)
+	client.SetKeepDuplicateRoutes(cfg.NoDedup)
+	client.SetKeepEmptyRouteNames(cfg.NoSyntheticNames)
+	client.SetNormalizeSubnets(cfg.NormalizeSubnets)
+	client.SetDownLongerThan(cfg.DownLongerThan)
+	client.SetIgnoreWarmSpare(cfg.IgnoreWarmSpare)
+	client.SetConnectRetries(cfg.ConnectRetries)
+	retryConfig := client.GetRetryConfig()
+	retryConfig.MaxRetries = cfg.MaxRetries
+	retryConfig.MaxInterval = cfg.RetryMaxInterval
+	client.SetRetryConfig(retryConfig)
+	client.SetRateLimit(cfg.RateLimit, 1)
+	client.SetTagFilter(commands.TagFilter(cfg))
+	client.SetNetworkTags(cfg.NetworkTags, cfg.NetworkTagMatch == "all")
+	client.SetExcludedNetworks(cfg.ExcludeNetworks)
+	client.SetDeviceFilter(meraki.DeviceFilter{
+		Models:       cfg.ModelFilter,
+		ProductTypes: cfg.ProductTypeFilter,
+		Tag:          cfg.DeviceTag,
+	})
+
+	// Resolve organization names to IDs if needed. With several -org values, or a comma-separated
+	// value that is not itself an organization name, every one must resolve before any data is
+	// collected; values naming the same organization select it once.
+	if len(cfg.Organizations) > 1 || strings.Contains(cfg.Organization, ",") {
+		resolvedOrgs, err := client.ResolveOrganizations(cfg.Organizations)
+		if err != nil {
+			slog.Error("Failed to resolve organizations", "orgs", cfg.Organizations, "error", err)
+			exit(1)
+		}
+		if len(resolvedOrgs) == 1 {
+			cfg.Organization = resolvedOrgs[0].ID
+			cfg.OrganizationName = resolvedOrgs[0].Name
+		} else {
+			if err := config.ValidateSeveralOrganizations(cfg); err != nil {
+				slog.Error("Invalid options for several organizations", "orgs", cfg.Organizations, "error", err)
+				exit(1)
+			}
+			cfg.Organization = ""
+			cfg.SelectedOrganizations = resolvedOrgs
+		}
+	} else if cfg.Organization != "" {
+		resolvedOrg, err := client.ResolveOrganization(cfg.Organization)
 		if err != nil {
 			slog.Error("Failed to resolve organization", "org", cfg.Organization, "error", err)
-			os.Exit(1)
+			exit(1)
+		}
+		cfg.Organization = resolvedOrg.ID
+		cfg.OrganizationName = resolvedOrg.Name
+	}
+
+	// -networks-file selects the listed networks of the organization
+	if len(cfg.NetworkList) > 0 {
+		count, err := commands.ListedNetworks(client, cfg)
+		if err != nil {
+			slog.Error("Failed to collect info for the networks in -networks-file", "file", cfg.NetworksFile, "error", err)
+			exit(exitStatus(err))
 		}
-		cfg.Organization = resolvedOrgID
+		exitOnResults(cfg, count)
+		return
+	}
+
+	// A wildcard -network selects every matching network in the organization; the commands
+	// that support patterns are checked when the config is parsed
+	if !cfg.InfoAll && meraki.IsNetworkPattern(cfg.Network) {
+		count, err := commands.MatchedNetworks(client, cfg)
+		if err != nil {
+			slog.Error("Failed to collect info for matched networks", "pattern", cfg.Network, "error", err)
+			exit(1)
+		}
+		exitOnResults(cfg, count)
+		return
 	}
 
 	// Handle commands based on the Command field
 	switch cfg.Command {
 	case "access":
-		showAccessInformation(client, cfg.Organization)
+		if err := commands.AccessInformation(client, cfg); err != nil {
+			exit(1)
+		}
 		return
 
 	case "route-tables":
 		if cfg.InfoAll {
-			err := infoAllNetworkRoutes(client, cfg)
+			err := commands.AllNetworkRoutes(client, cfg)
 			if err != nil {
 				slog.Error("Failed to get info for all network route tables", "error", err)
-				os.Exit(1)
+				exit(exitStatus(err))
 			}
 		} else {
-			err := infoSingleNetworkRoutes(client, cfg)
+			err := commands.SingleNetworkRoutes(client, cfg)
 			if err != nil {
 				slog.Error("Failed to get info for route tables", "error", err)
-				os.Exit(1)
+				exit(1)
 			}
 		}
 		return
 
 	case "licenses":
 		if cfg.InfoAll {
-			err := infoAllNetworkLicenses(client, cfg)
+			err := commands.AllNetworkLicenses(client, cfg)
 			if err != nil {
 				slog.Error("Failed to get info for all network licenses", "error", err)
-				os.Exit(1)
+				exit(exitStatus(err))
 			}
 		} else {
-			err := infoSingleNetworkLicenses(client, cfg)
+			err := commands.SingleNetworkLicenses(client, cfg)
 			if err != nil {
 				slog.Error("Failed to collect license info", "error", err)
-				os.Exit(1)
+				exit(1)
 			}
 		}
 		return
 
 	case "down":
+		var count int
 		if cfg.InfoAll {
-			err := infoAllNetworkDownDevices(client, cfg)
+			count, err = commands.AllNetworkDownDevices(client, cfg)
 			if err != nil {
 				slog.Error("Failed to get info for all network down devices", "error", err)
-				os.Exit(1)
+				exit(exitStatus(err))
 			}
 		} else {
-			err := infoSingleNetworkDownDevices(client, cfg)
+			count, err = commands.SingleNetworkDownDevices(client, cfg)
 			if err != nil {
 				slog.Error("Failed to collect down device info", "error", err)
-				os.Exit(1)
+				exit(1)
 			}
 		}
+		exitOnResults(cfg, count)
 		return
 
 	case "alerting":
+		var count int
 		if cfg.InfoAll {
-			if err := infoAllNetworkAlertingDevices(client, cfg); err != nil {
+			if count, err = commands.AllNetworkAlertingDevices(client, cfg); err != nil {
 				slog.Error("Failed to get info for all network alerting devices", "error", err)
-				os.Exit(1)
+				exit(exitStatus(err))
 			}
 		} else {
-			if err := infoSingleNetworkAlertingDevices(client, cfg); err != nil {
+			if count, err = commands.SingleNetworkAlertingDevices(client, cfg); err != nil {
 				slog.Error("Failed to collect alerting device info", "error", err)
-				os.Exit(1)
+				exit(1)
 			}
 		}
+		exitOnResults(cfg, count)
 		return
 
-	default:
-		fmt.Fprintf(os.Stderr, "Error: Unknown command '%s'. Use access, route-tables, licenses, down, or alerting.\n", cfg.Command)
-		os.Exit(1)
-	}
-}
-
-// infoSingleNetworkRoutes collects routes for a single network
-func infoSingleNetworkRoutes(client *meraki.Client, cfg *config.Config) error {
-	// Fetch routes for single network
-	routes, err := client.GetRoutes(cfg.Organization, cfg.Network)
-	if err != nil {
-		return fmt.Errorf("failed to fetch routes: %w", err)
-	}
-
-	slog.Info("Retrieved routes", "count", len(routes))
-
-	// Determine output filename
-	outputFile := cfg.OutputFile
-	if outputFile == "" || outputFile == "-" {
-		// Send to stdout when not provided or explicitly set to "-"
-		outputWriter := output.NewWriter(cfg.OutputType)
-		if err := outputWriter.WriteTo(routes, os.Stdout); err != nil {
-			return fmt.Errorf("failed to write output to stdout: %w", err)
-		}
-		slog.Info("Route tables sent to stdout", "route_count", len(routes))
-		return nil
-	}
-
-	// Output to file
-	outputWriter := output.NewWriter(cfg.OutputType)
-	if err := outputWriter.WriteToFile(routes, outputFile); err != nil {
-		return fmt.Errorf("failed to write output: %w", err)
-	}
-	slog.Info("Route tables info collection completed successfully", "output_file", outputFile)
-
-	return nil
-}
-
-// infoSingleNetworkLicenses collects info for licenses for a single network/organization
-func infoSingleNetworkLicenses(client *meraki.Client, cfg *config.Config) error {
-	// Fetch licenses for the organization
-	licenses, err := client.GetLicenses(cfg.Organization)
-	if err != nil {
-		return fmt.Errorf("failed to fetch licenses: %w", err)
-	}
-
-	slog.Info("Retrieved licenses", "count", len(licenses))
-
-	// Determine output filename
-	outputFile := cfg.OutputFile
-	if outputFile == "" || outputFile == "-" {
-		// Send to stdout when not provided or explicitly set to "-"
-		outputWriter := output.NewWriter(cfg.OutputType)
-		if err := outputWriter.WriteTo(licenses, os.Stdout); err != nil {
-			return fmt.Errorf("failed to write output to stdout: %w", err)
-		}
-		slog.Info("Licenses sent to stdout", "license_count", len(licenses))
-		return nil
-	}
-
-	// Output to file
-	outputWriter := output.NewWriter(cfg.OutputType)
-	if err := outputWriter.WriteToFile(licenses, outputFile); err != nil {
-		return fmt.Errorf("failed to write output: %w", err)
-	}
-	slog.Info("Licenses info collection completed successfully", "output_file", outputFile)
-
-	return nil
-}
-
-// infoSingleNetworkDownDevices collects info for down devices for a single network
-func infoSingleNetworkDownDevices(client *meraki.Client, cfg *config.Config) error {
-	// Fetch down devices for single network
-	downDevices, err := client.GetDownDevices(cfg.Organization, cfg.Network)
-	if err != nil {
-		return fmt.Errorf("failed to fetch down devices: %w", err)
-	}
-
-	slog.Info("Retrieved down devices", "count", len(downDevices))
-
-	// Determine output filename
-	outputFile := cfg.OutputFile
-	if outputFile == "" || outputFile == "-" {
-		// Send to stdout when not provided or explicitly set to "-"
-		outputWriter := output.NewWriter(cfg.OutputType)
-		if err := outputWriter.WriteTo(downDevices, os.Stdout); err != nil {
-			return fmt.Errorf("failed to write output to stdout: %w", err)
-		}
-		slog.Info("Down devices sent to stdout", "device_count", len(downDevices))
-		return nil
-	}
-
-	// Output to file
-	outputWriter := output.NewWriter(cfg.OutputType)
-	if err := outputWriter.WriteToFile(downDevices, outputFile); err != nil {
-		return fmt.Errorf("failed to write output: %w", err)
-	}
-	slog.Info("Down devices info collection completed successfully", "output_file", outputFile)
-
-	return nil
-}
-
-// infoSingleNetworkAlertingDevices retrieves and outputs alerting device information for a single network
-func infoSingleNetworkAlertingDevices(client *meraki.Client, cfg *config.Config) error {
-	// Fetch alerting devices for single network
-	alertingDevices, err := client.GetAlertingDevices(cfg.Organization, cfg.Network)
-	if err != nil {
-		return fmt.Errorf("failed to fetch alerting devices: %w", err)
-	}
-
-	slog.Info("Retrieved alerting devices", "count", len(alertingDevices))
-
-	// Determine output filename
-	outputFile := cfg.OutputFile
-	if outputFile == "" || outputFile == "-" {
-		// Send to stdout when not provided or explicitly set to "-"
-		outputWriter := output.NewWriter(cfg.OutputType)
-		if err := outputWriter.WriteTo(alertingDevices, os.Stdout); err != nil {
-			return fmt.Errorf("failed to write output to stdout: %w", err)
-		}
-		slog.Info("Alerting devices sent to stdout", "device_count", len(alertingDevices))
-		return nil
-	}
-
-	// Output to file
-	outputWriter := output.NewWriter(cfg.OutputType)
-	if err := outputWriter.WriteToFile(alertingDevices, outputFile); err != nil {
-		return fmt.Errorf("failed to write output: %w", err)
-	}
-	slog.Info("Alerting devices info collection completed successfully", "output_file", outputFile)
-
-	return nil
-}
-
-// infoAllNetworkAlertingDevices collects info for alerting devices for all networks in the organization(s) to separate files
-func infoAllNetworkAlertingDevices(client *meraki.Client, cfg *config.Config) error {
-	// Check if output should go to stdout (consolidated format)
-	if cfg.OutputFile == "" || cfg.OutputFile == "-" {
-		return infoAllNetworkAlertingDevicesConsolidated(client, cfg)
-	}
-
-	// Otherwise use separate files for each network
-	if cfg.Organization != "" {
-		// Get info for all networks in a specific organization
-		return infoOrganizationNetworkAlertingDevices(cfg, client, cfg.Organization)
-	} else {
-		// Get info for all networks in all organizations
-		return infoAllOrganizationAlertingDevices(cfg, client)
-	}
-}
-
-// infoAllNetworkAlertingDevicesConsolidated collects alerting device info for all networks and outputs in a consolidated format to stdout
-func infoAllNetworkAlertingDevicesConsolidated(client *meraki.Client, cfg *config.Config) error {
-	// Get all organizations
-	orgs, err := client.GetOrganizations()
-	if err != nil {
-		return fmt.Errorf("failed to get organizations: %w", err)
-	}
-
-	var allAlertingDevices []meraki.DeviceWithNetwork
-
-	for _, org := range orgs {
-		// Get all networks in the organization
-		networks, err := client.GetOrganizationNetworks(org.ID)
-		if err != nil {
-			slog.Error("Failed to get networks for organization", "orgID", org.ID, "orgName", org.Name, "error", err)
-			continue
-		}
-
-		for _, network := range networks {
-			// Get alerting devices for this network
-			alertingDevices, err := client.GetAlertingDevices(org.ID, network.ID)
-			if err != nil {
-				slog.Error("Failed to get alerting devices for network", "networkID", network.ID, "networkName", network.Name, "error", err)
-				continue
-			}
-
-			// Add network and organization information to each device
-			for _, device := range alertingDevices {
-				deviceWithNetwork := meraki.DeviceWithNetwork{
-					Device:         device,
-					NetworkName:    network.Name,
-					NetworkID:      network.ID,
-					Organization:   org.Name,
-					OrganizationID: org.ID,
-				}
-				allAlertingDevices = append(allAlertingDevices, deviceWithNetwork)
-			}
-		}
-	}
-
-	slog.Info("Collected all alerting devices", "totalDevices", len(allAlertingDevices))
-
-	// Output to stdout or file
-	writer := output.NewWriter(cfg.OutputType)
-	if cfg.OutputFile == "" || cfg.OutputFile == "-" {
-		if err := writer.WriteTo(allAlertingDevices, os.Stdout); err != nil {
-			return fmt.Errorf("failed to write output to stdout: %w", err)
-		}
-		slog.Info("Alerting devices info sent to stdout", "total_devices", len(allAlertingDevices))
-	} else {
-		if err := writer.WriteToFile(allAlertingDevices, cfg.OutputFile); err != nil {
-			return fmt.Errorf("failed to write output to file: %w", err)
-		}
-		slog.Info("Alerting devices info written to file", "total_devices", len(allAlertingDevices), "file", cfg.OutputFile)
-	}
-
-	return nil
-}
-
-// infoAllNetworkLicensesConsolidated collects license info for all networks and outputs in a consolidated format to stdout
-func infoAllNetworkLicensesConsolidated(client *meraki.Client, cfg *config.Config) error {
-	// Get all organizations
-	orgs, err := client.GetOrganizations()
-	if err != nil {
-		return fmt.Errorf("failed to get organizations: %w", err)
-	}
-
-	var allLicenses []meraki.LicenseWithNetwork
-
-	for _, org := range orgs {
-		// Get licenses for this organization
-		licenses, err := client.GetLicenses(org.ID)
-		if err != nil {
-			slog.Error("Failed to get licenses for organization", "orgID", org.ID, "orgName", org.Name, "error", err)
-			continue
-		}
-
-		// Add organization information to each license
-		for _, license := range licenses {
-			licenseWithNetwork := meraki.LicenseWithNetwork{
-				License:        license,
-				Organization:   org.Name,
-				OrganizationID: org.ID,
-			}
-			allLicenses = append(allLicenses, licenseWithNetwork)
-		}
-	}
-
-	slog.Info("Collected all licenses", "totalLicenses", len(allLicenses))
-
-	// Output to stdout or file
-	writer := output.NewWriter(cfg.OutputType)
-	if cfg.OutputFile == "" || cfg.OutputFile == "-" {
-		if err := writer.WriteTo(allLicenses, os.Stdout); err != nil {
-			return fmt.Errorf("failed to write output to stdout: %w", err)
-		}
-		slog.Info("License info sent to stdout", "total_licenses", len(allLicenses))
-	} else {
-		if err := writer.WriteToFile(allLicenses, cfg.OutputFile); err != nil {
-			return fmt.Errorf("failed to write output to file: %w", err)
-		}
-		slog.Info("License info written to file", "total_licenses", len(allLicenses), "file", cfg.OutputFile)
-	}
-
-	return nil
-}
-
-// infoAllNetworkDownDevicesConsolidated collects down device info for all networks and outputs in a consolidated format to stdout
-func infoAllNetworkDownDevicesConsolidated(client *meraki.Client, cfg *config.Config) error {
-	// Get all organizations
-	orgs, err := client.GetOrganizations()
-	if err != nil {
-		return fmt.Errorf("failed to get organizations: %w", err)
-	}
-
-	var allDownDevices []meraki.DeviceWithNetwork
-
-	for _, org := range orgs {
-		// Get all networks in the organization
-		networks, err := client.GetOrganizationNetworks(org.ID)
-		if err != nil {
-			slog.Error("Failed to get networks for organization", "orgID", org.ID, "orgName", org.Name, "error", err)
-			continue
-		}
-
-		for _, network := range networks {
-			// Get down devices for this network
-			downDevices, err := client.GetDownDevices(org.ID, network.ID)
-			if err != nil {
-				slog.Error("Failed to get down devices for network", "networkID", network.ID, "networkName", network.Name, "error", err)
-				continue
-			}
-
-			// Add network and organization information to each device
-			for _, device := range downDevices {
-				deviceWithNetwork := meraki.DeviceWithNetwork{
-					Device:         device,
-					NetworkName:    network.Name,
-					NetworkID:      network.ID,
-					Organization:   org.Name,
-					OrganizationID: org.ID,
-				}
-				allDownDevices = append(allDownDevices, deviceWithNetwork)
-			}
-		}
-	}
-
-	slog.Info("Collected all down devices", "totalDevices", len(allDownDevices))
-
-	// Output to stdout or file
-	writer := output.NewWriter(cfg.OutputType)
-	if cfg.OutputFile == "" || cfg.OutputFile == "-" {
-		if err := writer.WriteTo(allDownDevices, os.Stdout); err != nil {
-			return fmt.Errorf("failed to write output to stdout: %w", err)
-		}
-		slog.Info("Down devices info sent to stdout", "total_devices", len(allDownDevices))
-	} else {
-		if err := writer.WriteToFile(allDownDevices, cfg.OutputFile); err != nil {
-			return fmt.Errorf("failed to write output to file: %w", err)
-		}
-		slog.Info("Down devices info written to file", "total_devices", len(allDownDevices), "file", cfg.OutputFile)
-	}
-
-	return nil
-}
-
-// infoAllNetworkDownDevices collects info for down devices for all networks in the organization(s)
-func infoAllNetworkDownDevices(client *meraki.Client, cfg *config.Config) error {
-	// Check if output should go to stdout (consolidated format)
-	if cfg.OutputFile == "" || cfg.OutputFile == "-" {
-		return infoAllNetworkDownDevicesConsolidated(client, cfg)
-	}
-
-	// Otherwise use separate files for each network
-	if cfg.Organization != "" {
-		// Get info for all networks in a specific organization
-		return infoOrganizationNetworkDownDevices(cfg, client, cfg.Organization)
-	} else {
-		// Get info for all networks in all organizations
-		return infoAllOrganizationDownDevices(cfg, client)
-	}
-}
-
-// infoAllNetworkRoutes collects info for routes for all networks in the organization(s)
-func infoAllNetworkRoutes(client *meraki.Client, cfg *config.Config) error {
-	// Check if output should go to stdout (consolidated format)
-	if cfg.OutputFile == "" || cfg.OutputFile == "-" {
-		return infoAllNetworkRoutesConsolidated(client, cfg)
-	}
-
-	// Otherwise use separate files for each network
-	if cfg.Organization != "" {
-		// Get info for all networks in a specific organization
-		return infoOrganizationNetworkRoutes(cfg, client, cfg.Organization)
-	} else {
-		// Get info for all networks in all organizations
-		return infoAllOrganizationRoutes(cfg, client)
-	}
-}
-
-// infoAllNetworkRoutesConsolidated collects info for routes for all networks and outputs to stdout in consolidated format
-func infoAllNetworkRoutesConsolidated(client *meraki.Client, cfg *config.Config) error {
-	if cfg.Organization != "" {
-		// Get routes for all networks in a specific organization
-		networkRoutes, err := client.GetAllNetworkRoutes(cfg.Organization)
-		if err != nil {
-			return fmt.Errorf("failed to fetch network routes: %w", err)
-		}
-
-		// Create consolidated output with network information
-		allRoutes := make([]meraki.RouteWithNetwork, 0)
-		for _, nr := range networkRoutes {
-			orgName := cfg.Organization // Could be resolved to name if needed
-			for _, route := range nr.Routes {
-				allRoutes = append(allRoutes, meraki.RouteWithNetwork{
-					Route:        route,
-					NetworkID:    nr.Network.ID,
-					NetworkName:  nr.Network.Name,
-					Organization: orgName,
-				})
-			}
+	case "api-usage":
+		if err := commands.APIUsage(client, cfg); err != nil {
+			slog.Error("Failed to collect API usage", "error", err)
+			exit(1)
 		}
+		return
 
-		// Output to stdout or file
-		outputWriter := output.NewWriter(cfg.OutputType)
-		if cfg.OutputFile == "" || cfg.OutputFile == "-" {
-			if err := outputWriter.WriteTo(allRoutes, os.Stdout); err != nil {
-				return fmt.Errorf("failed to write output to stdout: %w", err)
-			}
-			slog.Info("Route tables info sent to stdout", "total_routes", len(allRoutes))
-		} else {
-			if err := outputWriter.WriteToFile(allRoutes, cfg.OutputFile); err != nil {
-				return fmt.Errorf("failed to write output to file: %w", err)
-			}
-			slog.Info("Route tables info written to file", "total_routes", len(allRoutes), "file", cfg.OutputFile)
+	case "change-log":
+		if err := commands.ChangeLog(client, cfg); err != nil {
+			slog.Error("Failed to collect configuration changes", "error", err)
+			exit(exitStatus(err))
 		}
-		return nil
-	} else {
-		// Get routes for all networks in all organizations
-		organizations, err := client.GetOrganizations()
-		if err != nil {
-			return fmt.Errorf("error getting organizations: %w", err)
-		}
-
-		allRoutes := make([]meraki.RouteWithNetwork, 0)
-		for _, org := range organizations {
-			networkRoutes, err := client.GetAllNetworkRoutes(org.ID)
-			if err != nil {
-				slog.Error("Failed to get routes for organization", "org", org.Name, "error", err)
-				continue
-			}
+		return
 
-			for _, nr := range networkRoutes {
-				for _, route := range nr.Routes {
-					allRoutes = append(allRoutes, meraki.RouteWithNetwork{
-						Route:        route,
-						NetworkID:    nr.Network.ID,
-						NetworkName:  nr.Network.Name,
-						Organization: org.Name,
-					})
-				}
-			}
+	case "status-summary":
+		if err := commands.DeviceStatusSummary(client, cfg); err != nil {
+			slog.Error("Failed to collect device status summary", "error", err)
+			exit(exitStatus(err))
 		}
+		return
 
-		// Output to stdout or file
-		outputWriter := output.NewWriter(cfg.OutputType)
-		if cfg.OutputFile == "" || cfg.OutputFile == "-" {
-			if err := outputWriter.WriteTo(allRoutes, os.Stdout); err != nil {
-				return fmt.Errorf("failed to write output to stdout: %w", err)
-			}
-			slog.Info("Route tables info sent to stdout", "total_routes", len(allRoutes))
-		} else {
-			if err := outputWriter.WriteToFile(allRoutes, cfg.OutputFile); err != nil {
-				return fmt.Errorf("failed to write output to file: %w", err)
-			}
-			slog.Info("Route tables info written to file", "total_routes", len(allRoutes), "file", cfg.OutputFile)
+	case "stacks":
+		if err := commands.SwitchStacks(client, cfg); err != nil {
+			slog.Error("Failed to collect switch stacks", "error", err)
+			exit(exitStatus(err))
 		}
-		return nil
-	}
-}
-
-// infoAllNetworkLicenses collects info for licenses for all networks in the organization(s)
-func infoAllNetworkLicenses(client *meraki.Client, cfg *config.Config) error {
-	// Check if output should go to stdout (consolidated format)
-	if cfg.OutputFile == "" || cfg.OutputFile == "-" {
-		return infoAllNetworkLicensesConsolidated(client, cfg)
-	}
-
-	// Otherwise use separate files for each network
-	if cfg.Organization != "" {
-		// Get info for all networks in a specific organization
-		return infoOrganizationNetworkLicenses(cfg, client, cfg.Organization)
-	} else {
-		// Get info for all networks in all organizations
-		return infoAllOrganizationLicenses(cfg, client)
-	}
-}
-
-// showAccessInformation displays available organizations and networks for the API key
-func showAccessInformation(client *meraki.Client, orgFilter string) {
-	fmt.Println("╔════════════════════════════════════════════════════════════════════════════════╗")
-	fmt.Println("║                          Meraki API Access Information                        ║")
-	fmt.Println("╚════════════════════════════════════════════════════════════════════════════════╝")
-	fmt.Println()
-
-	// Get organizations
-	orgs, err := client.GetOrganizations()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "❌ Error fetching organizations: %v\n", err)
-		os.Exit(1)
-	}
-
-	if len(orgs) == 0 {
-		fmt.Fprintln(os.Stderr, "⚠️  No organizations found. Please check your API key permissions.")
 		return
-	}
 
-	// Filter organizations if orgFilter is provided
-	var filteredOrgs []meraki.Organization
-	if orgFilter != "" {
-		for _, org := range orgs {
-			if org.ID == orgFilter || org.Name == orgFilter {
-				filteredOrgs = append(filteredOrgs, org)
-			}
-		}
-		if len(filteredOrgs) == 0 {
-			fmt.Fprintf(os.Stderr, "⚠️  No organization found matching '%s'\n", orgFilter)
-			fmt.Println("\n📋 Available organizations:")
-			for i, org := range orgs {
-				fmt.Printf("   %d. %s (ID: %s)\n", i+1, org.Name, org.ID)
-			}
-			return
+	case "switchports":
+		if err := commands.SwitchPorts(client, cfg); err != nil {
+			slog.Error("Failed to collect switch port statuses", "error", err)
+			exit(exitStatus(err))
 		}
-		orgs = filteredOrgs
-		fmt.Printf("🔍 Filtering by organization: %s\n\n", orgFilter)
-	}
+		return
 
-	fmt.Printf("✅ Found %d organization(s) accessible with your API key:\n\n", len(orgs))
-
-	for i, org := range orgs {
-		fmt.Printf("┌─ Organization %d ─────────────────────────────────────────────────────────\n", i+1)
-		fmt.Printf("│ ID:         %s\n", org.ID)
-		fmt.Printf("│ Name:       %s\n", org.Name)
-		fmt.Printf("│ API:        %s\n", formatBool(org.API.Enabled, "Enabled", "Disabled"))
-		fmt.Printf("│ Licensing:  %s\n", org.Licensing.Model)
-		fmt.Printf("│ Region:     %s (%s)\n", org.Cloud.Region.Name, org.Cloud.Region.Host.Name)
-		if org.URL != "" {
-			fmt.Printf("│ Dashboard:  %s\n", org.URL)
+	case "device":
+		if err := commands.Device(client, cfg); err != nil {
+			slog.Error("Failed to get device details", "error", err)
+			exit(exitStatus(err))
 		}
-		fmt.Printf("└───────────────────────────────────────────────────────────────────────────\n")
+		return
 
-		// Get networks for this organization
-		networks, err := client.GetOrganizationNetworks(org.ID)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "  ⚠️  Error fetching networks for %s: %v\n", org.Name, err)
-			continue
+	case "dhcp":
+		if err := commands.DHCPSubnets(client, cfg); err != nil {
+			slog.Error("Failed to collect DHCP subnets", "error", err)
+			exit(exitStatus(err))
 		}
+		return
 
-		if len(networks) == 0 {
-			fmt.Println("  📭 No networks found in this organization")
-		} else {
-			fmt.Printf("  📶 Networks (%d):\n", len(networks))
-			for j, network := range networks {
-				fmt.Printf("    %d. %s (ID: %s)", j+1, network.Name, network.ID)
-				if len(network.ProductTypes) > 0 {
-					fmt.Printf(" - Products: %v", network.ProductTypes)
-				}
-				if network.TimeZone != "" {
-					fmt.Printf(" - TZ: %s", network.TimeZone)
-				}
-				fmt.Println()
-			}
+	case "firewall":
+		if err := commands.FirewallRules(client, cfg); err != nil {
+			slog.Error("Failed to collect firewall rules", "error", err)
+			exit(exitStatus(err))
 		}
-		fmt.Println()
-	}
+		return
 
-	fmt.Println("💡 Usage Examples:")
-	if len(orgs) > 0 {
-		if orgFilter != "" {
-			// When filtering by org, show specific examples for that org
-			fmt.Printf("   # Get route info from organization '%s':\n", orgs[0].Name)
-			fmt.Printf("   ./meraki-routes-backup --apikey \"your-key\" --org \"%s\"\n", orgs[0].ID)
-
-			networks, err := client.GetOrganizationNetworks(orgs[0].ID)
-			if err == nil && len(networks) > 0 {
-				fmt.Printf("   # Get route info from specific network in '%s':\n", orgs[0].Name)
-				fmt.Printf("   ./meraki-routes-backup --apikey \"your-key\" --org \"%s\" --network \"%s\"\n", orgs[0].ID, networks[0].ID)
-				fmt.Printf("   # Get info for all networks in '%s' to separate files:\n", orgs[0].Name)
-				fmt.Printf("   ./meraki-routes-backup --apikey \"your-key\" --org \"%s\" --all\n", orgs[0].ID)
-				fmt.Printf("   # View access info for this organization only:\n")
-				fmt.Printf("   ./meraki-routes-backup --access --apikey \"your-key\" --org \"%s\"\n", orgs[0].ID)
-			}
-		} else {
-			// General examples when showing all orgs
-			fmt.Printf("   # Get route info from a specific organization:\n")
-			fmt.Printf("   ./meraki-routes-backup --apikey \"your-key\" --org \"%s\"\n", orgs[0].ID)
-
-			networks, err := client.GetOrganizationNetworks(orgs[0].ID)
-			if err == nil && len(networks) > 0 {
-				fmt.Printf("   # Get route info from a specific network:\n")
-				fmt.Printf("   ./meraki-routes-backup --apikey \"your-key\" --org \"%s\" --network \"%s\"\n", orgs[0].ID, networks[0].ID)
-			}
-			fmt.Printf("   # Get info for all networks to separate files:\n")
-			fmt.Printf("   ./meraki-routes-backup --apikey \"your-key\" --org \"%s\" --all\n", orgs[0].ID)
-			fmt.Printf("   # View access info for specific organization:\n")
-			fmt.Printf("   ./meraki-routes-backup --access --apikey \"your-key\" --org \"%s\"\n", orgs[0].ID)
+	case "locations":
+		if err := commands.Locations(client, cfg); err != nil {
+			slog.Error("Failed to collect device locations", "error", err)
+			exit(exitStatus(err))
 		}
-	}
-	fmt.Println()
-}
-
-// formatBool formats a boolean value with custom true/false strings
-func formatBool(value bool, trueStr, falseStr string) string {
-	if value {
-		return trueStr
-	}
-	return falseStr
-}
-
-// infoOrganizationNetworkRoutes collects info for routes for all networks in an organization
-func infoOrganizationNetworkRoutes(cfg *config.Config, client *meraki.Client, organizationID string) error {
-	networks, err := client.GetOrganizationNetworks(organizationID)
-	if err != nil {
-		return fmt.Errorf("error getting organization networks: %w", err)
-	}
-
-	for _, network := range networks {
-		// Create a copy of config for this network
-		networkCfg := *cfg
-		networkCfg.Organization = organizationID
-		networkCfg.Network = network.ID
+		return
 
-		// Only proceed if a specific output file is provided
-		if networkCfg.OutputFile == "" {
-			return fmt.Errorf("no output file specified for separate file generation")
+	case "perf":
+		if err := commands.AppliancePerformance(client, cfg); err != nil {
+			slog.Error("Failed to collect appliance performance", "error", err)
+			exit(exitStatus(err))
 		}
+		return
 
-		err := infoSingleNetworkRoutes(client, &networkCfg)
-		if err != nil {
-			slog.Error("Failed to collect route info for network", "network", network.Name, "error", err)
-			continue
+	case "networks":
+		if err := commands.Networks(client, cfg); err != nil {
+			slog.Error("Failed to list networks", "error", err)
+			exit(exitStatus(err))
 		}
-	}
-
-	return nil
-}
-
-// infoOrganizationNetworkLicenses collects info for licenses for all networks in an organization
-func infoOrganizationNetworkLicenses(cfg *config.Config, client *meraki.Client, organizationID string) error {
-	networks, err := client.GetOrganizationNetworks(organizationID)
-	if err != nil {
-		return fmt.Errorf("error getting organization networks: %w", err)
-	}
-
-	for _, network := range networks {
-		// Create a copy of config for this network
-		networkCfg := *cfg
-		networkCfg.Organization = organizationID
-		networkCfg.Network = network.ID
+		return
 
-		// Only proceed if a specific output file is provided
-		if networkCfg.OutputFile == "" {
-			return fmt.Errorf("no output file specified for separate file generation")
+	case "events":
+		if err := commands.NetworkEvents(client, cfg); err != nil {
+			slog.Error("Failed to collect network events", "error", err)
+			exit(1)
 		}
+		return
 
-		err := infoSingleNetworkLicenses(client, &networkCfg)
-		if err != nil {
-			slog.Error("Failed to collect license info for network", "network", network.Name, "error", err)
-			continue
-		}
+	default:
+		fmt.Fprintf(os.Stderr, "Error: Unknown command '%s'. Use access, api-usage, change-log, route-tables, licenses, down, alerting, device, dhcp, events, firewall, firewall-rules, locations, networks, perf, stacks, status-summary, or switchports.\n", cfg.Command)
+		exit(1)
 	}
-
-	return nil
 }
 
-// infoAllOrganizationRoutes collects info for routes for all organizations
-func infoAllOrganizationRoutes(cfg *config.Config, client *meraki.Client) error {
-	organizations, err := client.GetOrganizations()
-	if err != nil {
-		return fmt.Errorf("error getting organizations: %w", err)
-	}
+// exit ends the process with a status code. Once the ETag cache is loaded it also writes the cache,
+// since os.Exit skips deferred calls.
+var exit = os.Exit
 
-	for _, org := range organizations {
-		slog.Info("Processing organization for route tables", "org", org.Name, "id", org.ID)
-		err := infoOrganizationNetworkRoutes(cfg, client, org.ID)
-		if err != nil {
-			slog.Error("Failed to collect route info for organization", "org", org.Name, "error", err)
-			continue
-		}
+// saveETagCache writes the responses cached during the run to the -etag-cache file
+func saveETagCache(client *meraki.Client) {
+	if err := client.SaveETagCache(); err != nil {
+		slog.Warn("Failed to save ETag cache", "error", err)
 	}
-
-	return nil
 }
 
-// infoAllOrganizationLicenses collects info for licenses for all organizations
-func infoAllOrganizationLicenses(cfg *config.Config, client *meraki.Client) error {
-	organizations, err := client.GetOrganizations()
-	if err != nil {
-		return fmt.Errorf("error getting organizations: %w", err)
-	}
+// exitResultsFound is the exit status used by -fail-on-results when devices were found
+const exitResultsFound = 2
 
-	for _, org := range organizations {
-		slog.Info("Processing organization for licenses", "org", org.Name, "id", org.ID)
-		err := infoOrganizationNetworkLicenses(cfg, client, org.ID)
-		if err != nil {
-			slog.Error("Failed to collect license info for organization", "org", org.Name, "error", err)
-			continue
-		}
-	}
+// exitPartialResults is the exit status used when organizations or networks could not be scanned
+const exitPartialResults = 3
 
-	return nil
-}
+// exitSerialNotFound is the exit status used when the device selected with -serial was not found,
+// by the down and alerting commands or by the device command
+const exitSerialNotFound = 4
 
-// infoOrganizationNetworkDownDevices collects info for down devices for all networks in an organization
-func infoOrganizationNetworkDownDevices(cfg *config.Config, client *meraki.Client, organizationID string) error {
-	networks, err := client.GetOrganizationNetworks(organizationID)
-	if err != nil {
-		return fmt.Errorf("error getting organization networks: %w", err)
+// exitStatus returns the exit status for a failed run
+func exitStatus(err error) int {
+	if errors.Is(err, commands.ErrIncompleteRun) {
+		return exitPartialResults
 	}
-
-	for _, network := range networks {
-		// Create a copy of config for this network
-		networkCfg := *cfg
-		networkCfg.Organization = organizationID
-		networkCfg.Network = network.ID
-
-		// Only proceed if a specific output file is provided
-		if networkCfg.OutputFile == "" {
-			return fmt.Errorf("no output file specified for separate file generation")
-		}
-
-		err := infoSingleNetworkDownDevices(client, &networkCfg)
-		if err != nil {
-			slog.Error("Failed to collect down device info for network", "network", network.Name, "error", err)
-			continue
-		}
+	if errors.Is(err, commands.ErrDeviceNotFound) {
+		return exitSerialNotFound
 	}
-
-	return nil
+	return 1
 }
 
-// infoAllOrganizationDownDevices collects info for down devices for all organizations
-func infoAllOrganizationDownDevices(cfg *config.Config, client *meraki.Client) error {
-	organizations, err := client.GetOrganizations()
-	if err != nil {
-		return fmt.Errorf("error getting organizations: %w", err)
+// exitOnResults exits with status 2 when -fail-on-results is set and the down or alerting
+// command found devices, so the tool can act as a health gate in scripts, and with status 4
+// when the device selected with -serial was not found
+func exitOnResults(cfg *config.Config, count int) {
+	if code := resultsExitCode(cfg, count); code != 0 {
+		slog.Info("Exiting with failure status", "command", cfg.Command, "count", count, "exit_code", code)
+		exit(code)
 	}
-
-	for _, org := range organizations {
-		slog.Info("Processing organization for down devices", "org", org.Name, "id", org.ID)
-		err := infoOrganizationNetworkDownDevices(cfg, client, org.ID)
-		if err != nil {
-			slog.Error("Failed to collect down device info for organization", "org", org.Name, "error", err)
-			continue
-		}
-	}
-
-	return nil
 }
 
-// infoOrganizationNetworkAlertingDevices collects info for alerting devices for all networks in an organization
-func infoOrganizationNetworkAlertingDevices(cfg *config.Config, client *meraki.Client, organizationID string) error {
-	networks, err := client.GetOrganizationNetworks(organizationID)
-	if err != nil {
-		return fmt.Errorf("error getting organization networks: %w", err)
+// resultsExitCode returns the exit status for a successful run that found count records
+func resultsExitCode(cfg *config.Config, count int) int {
+	if cfg.SerialFilter != "" && count == 0 {
+		return exitSerialNotFound
 	}
-
-	for _, network := range networks {
-		// Create a copy of config for this network
-		networkCfg := *cfg
-		networkCfg.Organization = organizationID
-		networkCfg.Network = network.ID
-
-		// Only proceed if a specific output file is provided
-		if networkCfg.OutputFile == "" {
-			return fmt.Errorf("no output file specified for separate file generation")
-		}
-
-		err := infoSingleNetworkAlertingDevices(client, &networkCfg)
-		if err != nil {
-			slog.Error("Failed to collect alerting device info for network", "network", network.Name, "error", err)
-			continue
-		}
+	if !cfg.FailOnResults || count == 0 {
+		return 0
 	}
-
-	return nil
-}
-
-// infoAllOrganizationAlertingDevices collects info for alerting devices for all organizations
-func infoAllOrganizationAlertingDevices(cfg *config.Config, client *meraki.Client) error {
-	organizations, err := client.GetOrganizations()
-	if err != nil {
-		return fmt.Errorf("error getting organizations: %w", err)
-	}
-
-	for _, org := range organizations {
-		slog.Info("Processing organization for alerting devices", "org", org.Name, "id", org.ID)
-		err := infoOrganizationNetworkAlertingDevices(cfg, client, org.ID)
-		if err != nil {
-			slog.Error("Failed to collect alerting device info for organization", "org", org.Name, "error", err)
-			continue
-		}
+	switch cfg.Command {
+	case "down", "alerting":
+		return exitResultsFound
 	}
-
-	return nil
+	return 0
 }
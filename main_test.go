@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"meraki-info/internal/commands"
+	"meraki-info/internal/config"
+)
+
+func TestResultsExitCode(t *testing.T) {
+	tests := []struct {
+		name          string
+		command       string
+		failOnResults bool
+		serial        string
+		count         int
+		expected      int
+	}{
+		{name: "flag unset", command: "down", failOnResults: false, count: 3, expected: 0},
+		{name: "down with devices", command: "down", failOnResults: true, count: 3, expected: 2},
+		{name: "down without devices", command: "down", failOnResults: true, count: 0, expected: 0},
+		{name: "alerting with devices", command: "alerting", failOnResults: true, count: 1, expected: 2},
+		{name: "other commands never fail", command: "route-tables", failOnResults: true, count: 10, expected: 0},
+		{name: "serial found", command: "down", serial: "Q2AA-0001", count: 1, expected: 0},
+		{name: "serial not found", command: "alerting", serial: "Q2AA-0001", count: 0, expected: exitSerialNotFound},
+		{name: "serial found with fail-on-results", command: "down", serial: "Q2AA-0001", failOnResults: true, count: 1, expected: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Command: tt.command, FailOnResults: tt.failOnResults, SerialFilter: tt.serial}
+			if got := resultsExitCode(cfg, tt.count); got != tt.expected {
+				t.Errorf("Expected exit code %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestExitStatus(t *testing.T) {
+	err := fmt.Errorf("%w: 1 organization(s) could not be fully scanned", commands.ErrIncompleteRun)
+	if code := exitStatus(err); code != exitPartialResults {
+		t.Errorf("Expected exit code %d, got %d", exitPartialResults, code)
+	}
+	err = fmt.Errorf("%w: no device with serial Q2AA-0001", commands.ErrDeviceNotFound)
+	if code := exitStatus(err); code != exitSerialNotFound {
+		t.Errorf("Expected exit code %d for an unknown device, got %d", exitSerialNotFound, code)
+	}
+	if code := exitStatus(errors.New("boom")); code != 1 {
+		t.Errorf("Expected exit code 1 for other errors, got %d", code)
+	}
+}
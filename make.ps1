@@ -64,6 +64,20 @@ function Show-MakeHelp {
     Write-MakeOutput ""
 }
 
+# Build information injected into internal/version, as in the Makefile; VERSION, COMMIT and
+# BUILD_DATE may be set in the environment to override them
+function Get-VersionLdFlags {
+    $version = $env:VERSION
+    if (-not $version) { $version = git describe --tags --always --dirty 2>$null }
+    if (-not $version) { $version = "1.0.0" }
+    $commit = $env:COMMIT
+    if (-not $commit) { $commit = git rev-parse --short HEAD 2>$null }
+    if (-not $commit) { $commit = "unknown" }
+    $buildDate = $env:BUILD_DATE
+    if (-not $buildDate) { $buildDate = (Get-Date).ToUniversalTime().ToString("yyyy-MM-ddTHH:mm:ssZ") }
+    return "-X meraki-info/internal/version.Version=$version -X meraki-info/internal/version.Commit=$commit -X meraki-info/internal/version.BuildDate=$buildDate"
+}
+
 function Invoke-BuildTarget {
     param([string]$Platform = "windows")
     
@@ -80,7 +94,7 @@ function Invoke-BuildTarget {
             Write-MakeOutput "🔨 Building for Linux ARM64..." -Color Info
             $env:GOOS = "linux"
             $env:GOARCH = "arm64"
-            go build -o meraki-info-linux-arm .
+            go build -ldflags (Get-VersionLdFlags) -o meraki-info-linux-arm .
             if ($LASTEXITCODE -eq 0) {
                 Write-MakeOutput "✅ Linux ARM64 build completed: meraki-info-linux-arm" -Color Success
             }
@@ -94,7 +108,7 @@ function Invoke-BuildTarget {
             Write-MakeOutput "🔨 Building for macOS ARM64 (Apple Silicon)..." -Color Info
             $env:GOOS = "darwin"
             $env:GOARCH = "arm64"
-            go build -o meraki-info-mac-arm .
+            go build -ldflags (Get-VersionLdFlags) -o meraki-info-mac-arm .
             if ($LASTEXITCODE -eq 0) {
                 Write-MakeOutput "✅ macOS ARM64 build completed: meraki-info-mac-arm" -Color Success
             }